@@ -0,0 +1,64 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"strings"
+)
+
+const (
+	apiKeyType           = "ak"
+	apiKeyPrefixBytes    = 6
+	apiKeySecretBytes    = 32
+	apiKeyPartsSeparator = "_"
+)
+
+// apiKeySecret is a new API key. The key is "ak_{prefix}_{secret}", and only its prefix and hash are stored
+type apiKeySecret struct {
+	Key    string
+	Prefix string
+	Hash   string
+}
+
+// newAPIKeySecret generates a random API key
+func newAPIKeySecret() (apiKeySecret, error) {
+	prefix := make([]byte, apiKeyPrefixBytes)
+	if _, err := rand.Read(prefix); err != nil {
+		return apiKeySecret{}, err
+	}
+	secret := make([]byte, apiKeySecretBytes)
+	if _, err := rand.Read(secret); err != nil {
+		return apiKeySecret{}, err
+	}
+
+	key := strings.Join([]string{apiKeyType, hex.EncodeToString(prefix), hex.EncodeToString(secret)}, apiKeyPartsSeparator)
+	return apiKeySecret{
+		Key:    key,
+		Prefix: hex.EncodeToString(prefix),
+		Hash:   hashAPIKey(key),
+	}, nil
+}
+
+// parseAPIKeyPrefix returns the prefix of an API key, and false when the key is malformed
+func parseAPIKeyPrefix(key string) (string, bool) {
+	parts := strings.Split(key, apiKeyPartsSeparator)
+	if len(parts) != 3 || parts[0] != apiKeyType ||
+		len(parts[1]) != hex.EncodedLen(apiKeyPrefixBytes) || len(parts[2]) != hex.EncodedLen(apiKeySecretBytes) {
+		return "", false
+	}
+
+	return parts[1], true
+}
+
+// hashAPIKey hashes an API key. The keys are random and long, so a fast hash is enough and they can be checked on every request
+func hashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// compareAPIKey returns true when the key matches the hash, in constant time
+func compareAPIKey(hash string, key string) bool {
+	return subtle.ConstantTimeCompare([]byte(hash), []byte(hashAPIKey(key))) == 1
+}
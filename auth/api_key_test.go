@@ -0,0 +1,43 @@
+package auth
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIKey_GivenANewKey_WhenParseAndCompare_ThenMatchItsPrefixAndHash(t *testing.T) {
+	t.Log("Should generate an API key that can be found by its prefix and checked with its hash")
+
+	secret, err := newAPIKeySecret()
+
+	assert.Nil(t, err)
+	assert.True(t, strings.HasPrefix(secret.Key, "ak_"+secret.Prefix+"_"))
+
+	prefix, ok := parseAPIKeyPrefix(secret.Key)
+
+	assert.True(t, ok)
+	assert.Equal(t, secret.Prefix, prefix)
+	assert.True(t, compareAPIKey(secret.Hash, secret.Key))
+	assert.NotContains(t, secret.Hash, secret.Key)
+}
+
+func TestAPIKey_GivenTwoKeys_WhenCompare_ThenOnlyMatchItsOwnHash(t *testing.T) {
+	t.Log("Should not match the hash of another API key")
+
+	first, _ := newAPIKeySecret()
+	second, _ := newAPIKeySecret()
+
+	assert.NotEqual(t, first.Key, second.Key)
+	assert.False(t, compareAPIKey(first.Hash, second.Key))
+}
+
+func TestAPIKey_GivenMalformedKeys_WhenParse_ThenReturnFalse(t *testing.T) {
+	t.Log("Should reject malformed API keys")
+
+	for _, key := range []string{"", "ak", "ak_0123456789ab", "xx_0123456789ab_" + strings.Repeat("0", 64), "ak_0123_" + strings.Repeat("0", 64)} {
+		_, ok := parseAPIKeyPrefix(key)
+		assert.False(t, ok, key)
+	}
+}
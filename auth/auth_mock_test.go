@@ -0,0 +1,130 @@
+package auth
+
+import (
+	"errors"
+	"time"
+
+	"github.com/desarrollogj/golang-api-example/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+type credentialRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *credentialRepositoryMock) FindByUserReference(userReference string) (domain.Credential, error) {
+	args := m.Called(userReference)
+
+	credential, ok := args.Get(0).(domain.Credential)
+	if !ok {
+		return domain.Credential{}, errors.New("mock error")
+	}
+
+	return credential, args.Error(1)
+}
+
+func (m *credentialRepositoryMock) Save(credential domain.Credential) (domain.Credential, error) {
+	args := m.Called(credential)
+
+	credential, ok := args.Get(0).(domain.Credential)
+	if !ok {
+		return domain.Credential{}, errors.New("mock error")
+	}
+
+	return credential, args.Error(1)
+}
+
+func (m *credentialRepositoryMock) DeleteByUserReference(userReference string) error {
+	args := m.Called(userReference)
+	return args.Error(0)
+}
+
+type tokenIssuerMock struct {
+	mock.Mock
+}
+
+func (m *tokenIssuerMock) Issue(user domain.User) (domain.AccessToken, error) {
+	args := m.Called(user)
+
+	token, ok := args.Get(0).(domain.AccessToken)
+	if !ok {
+		return domain.AccessToken{}, errors.New("mock error")
+	}
+
+	return token, args.Error(1)
+}
+
+func (m *tokenIssuerMock) Parse(token string) (AccessTokenClaims, error) {
+	args := m.Called(token)
+
+	claims, ok := args.Get(0).(AccessTokenClaims)
+	if !ok {
+		return AccessTokenClaims{}, errors.New("mock error")
+	}
+
+	return claims, args.Error(1)
+}
+
+type apiKeyRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *apiKeyRepositoryMock) FindAll() ([]domain.APIKey, error) {
+	args := m.Called()
+
+	keys, ok := args.Get(0).([]domain.APIKey)
+	if !ok {
+		return []domain.APIKey{}, errors.New("mock error")
+	}
+
+	return keys, args.Error(1)
+}
+
+func (m *apiKeyRepositoryMock) FindByReference(reference string) (domain.APIKey, error) {
+	args := m.Called(reference)
+
+	key, ok := args.Get(0).(domain.APIKey)
+	if !ok {
+		return domain.APIKey{}, errors.New("mock error")
+	}
+
+	return key, args.Error(1)
+}
+
+func (m *apiKeyRepositoryMock) FindByPrefix(prefix string) (domain.APIKey, error) {
+	args := m.Called(prefix)
+
+	key, ok := args.Get(0).(domain.APIKey)
+	if !ok {
+		return domain.APIKey{}, errors.New("mock error")
+	}
+
+	return key, args.Error(1)
+}
+
+func (m *apiKeyRepositoryMock) Create(key domain.APIKey) (domain.APIKey, error) {
+	args := m.Called(key)
+
+	key, ok := args.Get(0).(domain.APIKey)
+	if !ok {
+		return domain.APIKey{}, errors.New("mock error")
+	}
+
+	return key, args.Error(1)
+}
+
+func (m *apiKeyRepositoryMock) Update(key domain.APIKey) (domain.APIKey, error) {
+	args := m.Called(key)
+
+	key, ok := args.Get(0).(domain.APIKey)
+	if !ok {
+		return domain.APIKey{}, errors.New("mock error")
+	}
+
+	return key, args.Error(1)
+}
+
+func (m *apiKeyRepositoryMock) UpdateLastUsed(reference string, date time.Time) error {
+	args := m.Called(reference, date)
+	return args.Error(0)
+}
@@ -0,0 +1,66 @@
+package auth
+
+import (
+	"time"
+
+	"github.com/desarrollogj/golang-api-example/domain"
+	"github.com/desarrollogj/golang-api-example/infrastructure"
+	"github.com/desarrollogj/golang-api-example/libs/errors"
+	"github.com/desarrollogj/golang-api-example/libs/logger"
+)
+
+const (
+	invalidAPIKeyMessage = "API key is not valid, expired or revoked"
+	// apiKeyLastUsedResolution is how often the last used date is updated, so not every request writes it
+	apiKeyLastUsedResolution = time.Minute
+)
+
+// AuthenticateAPIKey represents the method to be implemented to authenticate requests with API keys
+type AuthenticateAPIKey interface {
+	Execute(key string) (domain.APIKey, error)
+}
+
+// defaultAuthenticateAPIKey is the default implementation of AuthenticateAPIKey interface
+type defaultAuthenticateAPIKey struct {
+	repository infrastructure.APIKeyRepository
+}
+
+// NewDefaultAuthenticateAPIKey creates a defaultAuthenticateAPIKey instance
+func NewDefaultAuthenticateAPIKey(repository infrastructure.APIKeyRepository) defaultAuthenticateAPIKey {
+	return defaultAuthenticateAPIKey{
+		repository: repository,
+	}
+}
+
+// Execute finds the API key by its prefix and checks its secret, expiration and revocation, and registers its use.
+// Unknown, wrong, expired and revoked keys return the same error
+func (s defaultAuthenticateAPIKey) Execute(key string) (domain.APIKey, error) {
+	prefix, ok := parseAPIKeyPrefix(key)
+	if !ok {
+		return domain.APIKey{}, errors.NewBusinessUnauthorizedError(invalidAPIKeyMessage).WithKey("api_key_not_valid")
+	}
+
+	apiKey, err := s.repository.FindByPrefix(prefix)
+	if err != nil {
+		errMsg := "unexpected error when try to get API key by prefix"
+		logger.AppLog.Error().Err(err).Msg(errMsg)
+		return domain.APIKey{}, errors.NewFatalError(errMsg)
+	}
+
+	now := time.Now().UTC()
+	if len(apiKey.Reference) == 0 || !compareAPIKey(apiKey.SecretHash, key) ||
+		apiKey.RevokedDate != nil || (apiKey.ExpiresDate != nil && !apiKey.ExpiresDate.After(now)) {
+		return domain.APIKey{}, errors.NewBusinessUnauthorizedError(invalidAPIKeyMessage).WithKey("api_key_not_valid")
+	}
+
+	if apiKey.LastUsedDate == nil || now.Sub(*apiKey.LastUsedDate) >= apiKeyLastUsedResolution {
+		// The request is authenticated even if the use can not be registered
+		if err := s.repository.UpdateLastUsed(apiKey.Reference, now); err != nil {
+			logger.AppLog.Warn().Err(err).Str("apiKey", apiKey.Reference).Msg("unable to update the API key last used date")
+		} else {
+			apiKey.LastUsedDate = &now
+		}
+	}
+
+	return apiKey, nil
+}
@@ -0,0 +1,128 @@
+package auth
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/desarrollogj/golang-api-example/domain"
+	appErrors "github.com/desarrollogj/golang-api-example/libs/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newAPIKeyMock(t *testing.T) (domain.APIKey, string) {
+	secret, err := newAPIKeySecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return domain.APIKey{
+		Reference:  "KEY1",
+		Prefix:     secret.Prefix,
+		SecretHash: secret.Hash,
+		Scopes:     []string{domain.APIKeyScopeRead},
+	}, secret.Key
+}
+
+func TestAuthenticateAPIKey_GivenAValidKey_WhenExecute_ThenReturnItAndRegisterItsUse(t *testing.T) {
+	t.Log("Successfully authenticate an API key")
+
+	apiKey, key := newAPIKeyMock(t)
+	repositoryMock := new(apiKeyRepositoryMock)
+	repositoryMock.On("FindByPrefix", apiKey.Prefix).Return(apiKey, nil)
+	repositoryMock.On("UpdateLastUsed", "KEY1", mock.AnythingOfType("time.Time")).Return(nil)
+
+	useCase := NewDefaultAuthenticateAPIKey(repositoryMock)
+
+	result, err := useCase.Execute(key)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "KEY1", result.Reference)
+	assert.NotNil(t, result.LastUsedDate)
+
+	repositoryMock.AssertExpectations(t)
+}
+
+func TestAuthenticateAPIKey_GivenARecentlyUsedKey_WhenExecute_ThenDoNotRegisterItsUseAgain(t *testing.T) {
+	t.Log("Successfully authenticate an API key without updating a recent last used date")
+
+	apiKey, key := newAPIKeyMock(t)
+	used := time.Now().UTC().Add(-10 * time.Second)
+	apiKey.LastUsedDate = &used
+	repositoryMock := new(apiKeyRepositoryMock)
+	repositoryMock.On("FindByPrefix", apiKey.Prefix).Return(apiKey, nil)
+
+	useCase := NewDefaultAuthenticateAPIKey(repositoryMock)
+
+	_, err := useCase.Execute(key)
+
+	assert.Nil(t, err)
+
+	repositoryMock.AssertNotCalled(t, "UpdateLastUsed", mock.Anything, mock.Anything)
+}
+
+func TestAuthenticateAPIKey_GivenNotValidKeys_WhenExecute_ThenReturnAnUnauthorizedError(t *testing.T) {
+	t.Log("Failure to authenticate wrong, expired or revoked API keys")
+
+	apiKey, key := newAPIKeyMock(t)
+	other, otherKey := newAPIKeyMock(t)
+	past := time.Now().UTC().Add(-time.Hour)
+	expired := apiKey
+	expired.ExpiresDate = &past
+	revoked := apiKey
+	revoked.RevokedDate = &past
+
+	cases := []struct {
+		name   string
+		stored domain.APIKey
+		key    string
+	}{
+		{"unknown", domain.APIKey{}, key},
+		{"wrong secret", other, otherKey[:len(otherKey)-1] + "x"},
+		{"expired", expired, key},
+		{"revoked", revoked, key},
+	}
+	for _, c := range cases {
+		prefix, _ := parseAPIKeyPrefix(c.key)
+		repositoryMock := new(apiKeyRepositoryMock)
+		repositoryMock.On("FindByPrefix", prefix).Return(c.stored, nil)
+
+		useCase := NewDefaultAuthenticateAPIKey(repositoryMock)
+
+		_, err := useCase.Execute(c.key)
+
+		assert.NotNil(t, err, c.name)
+		assert.Equal(t, "API key is not valid, expired or revoked", err.Error(), c.name)
+		assert.Equal(t, appErrors.UnauthorizedErrorCode, err.(*appErrors.BusinessError).Err, c.name)
+	}
+}
+
+func TestAuthenticateAPIKey_GivenAMalformedKey_WhenExecute_ThenReturnAnUnauthorizedError(t *testing.T) {
+	t.Log("Failure to authenticate a malformed API key")
+
+	repositoryMock := new(apiKeyRepositoryMock)
+
+	useCase := NewDefaultAuthenticateAPIKey(repositoryMock)
+
+	_, err := useCase.Execute("not-a-key")
+
+	assert.NotNil(t, err)
+	assert.Equal(t, "API key is not valid, expired or revoked", err.Error())
+
+	repositoryMock.AssertNotCalled(t, "FindByPrefix", mock.Anything)
+}
+
+func TestAuthenticateAPIKey_GivenAValidKey_WhenExecuteAndUpdateLastUsedFailed_ThenReturnIt(t *testing.T) {
+	t.Log("Successfully authenticate an API key even if its use can not be registered")
+
+	apiKey, key := newAPIKeyMock(t)
+	repositoryMock := new(apiKeyRepositoryMock)
+	repositoryMock.On("FindByPrefix", apiKey.Prefix).Return(apiKey, nil)
+	repositoryMock.On("UpdateLastUsed", "KEY1", mock.AnythingOfType("time.Time")).Return(errors.New("repository error"))
+
+	useCase := NewDefaultAuthenticateAPIKey(repositoryMock)
+
+	_, err := useCase.Execute(key)
+
+	assert.Nil(t, err)
+}
@@ -0,0 +1,91 @@
+package auth
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/desarrollogj/golang-api-example/domain"
+	"github.com/desarrollogj/golang-api-example/infrastructure"
+	"github.com/desarrollogj/golang-api-example/libs/errors"
+	"github.com/desarrollogj/golang-api-example/libs/logger"
+	"github.com/google/uuid"
+)
+
+// CreateAPIKey represents the method to be implemented to create API keys
+type CreateAPIKey interface {
+	Execute(input domain.APIKeyCreateInput) (domain.APIKeySecret, error)
+}
+
+// defaultCreateAPIKey is the default implementation of CreateAPIKey interface
+type defaultCreateAPIKey struct {
+	repository infrastructure.APIKeyRepository
+}
+
+// NewDefaultCreateAPIKey creates a defaultCreateAPIKey instance
+func NewDefaultCreateAPIKey(repository infrastructure.APIKeyRepository) defaultCreateAPIKey {
+	return defaultCreateAPIKey{
+		repository: repository,
+	}
+}
+
+// Execute creates an API key with a random secret. The returned key is the only time the secret is available
+func (s defaultCreateAPIKey) Execute(input domain.APIKeyCreateInput) (domain.APIKeySecret, error) {
+	if err := validateAPIKeyScopes(input.Scopes); err != nil {
+		return domain.APIKeySecret{}, err
+	}
+	now := time.Now().UTC()
+	if input.ExpiresDate != nil && !input.ExpiresDate.After(now) {
+		return domain.APIKeySecret{}, errors.NewValidationError("API key expiration date must be in the future").WithKey("api_key_expiration_not_valid")
+	}
+
+	secret, err := newAPIKeySecret()
+	if err != nil {
+		errMsg := "unexpected error when generate the API key"
+		logger.AppLog.Error().Err(err).Msg(errMsg)
+		return domain.APIKeySecret{}, errors.NewFatalError(errMsg)
+	}
+
+	created, err := s.repository.Create(domain.APIKey{
+		Reference:   uuid.NewString(),
+		Name:        input.Name,
+		Prefix:      secret.Prefix,
+		SecretHash:  secret.Hash,
+		Scopes:      input.Scopes,
+		ExpiresDate: input.ExpiresDate,
+		CreatedDate: now,
+		UpdatedDate: now,
+	})
+	if err != nil {
+		errMsg := "unexpected error when create the API key"
+		logger.AppLog.Error().Err(err).Msg(errMsg)
+		return domain.APIKeySecret{}, errors.NewFatalError(errMsg)
+	}
+
+	return domain.APIKeySecret{APIKey: created, Key: secret.Key}, nil
+}
+
+func validateAPIKeyScopes(scopes []string) error {
+	if len(scopes) == 0 {
+		return errors.NewValidationError("API key requires at least one scope").WithKey("api_key_scopes_required")
+	}
+	for _, scope := range scopes {
+		if !isAPIKeyScope(scope) {
+			return errors.NewValidationError(fmt.Sprintf("API key scope %s is not valid, valid scopes are %s",
+				scope, strings.Join(domain.APIKeyScopes, ", "))).
+				WithKey("api_key_scope_not_valid", scope, strings.Join(domain.APIKeyScopes, ", "))
+		}
+	}
+
+	return nil
+}
+
+func isAPIKeyScope(scope string) bool {
+	for _, s := range domain.APIKeyScopes {
+		if s == scope {
+			return true
+		}
+	}
+
+	return false
+}
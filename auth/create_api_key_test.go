@@ -0,0 +1,74 @@
+package auth
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/desarrollogj/golang-api-example/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCreateAPIKey_GivenAValidInput_WhenExecute_ThenReturnTheKeyWithItsSecret(t *testing.T) {
+	t.Log("Successfully create an API key")
+
+	repositoryMock := new(apiKeyRepositoryMock)
+	repositoryMock.On("Create", mock.MatchedBy(func(key domain.APIKey) bool {
+		return key.Name == "batch" && len(key.Reference) > 0 && len(key.Prefix) > 0 && len(key.SecretHash) > 0
+	})).Return(domain.APIKey{Reference: "KEY1", Name: "batch"}, nil)
+
+	useCase := NewDefaultCreateAPIKey(repositoryMock)
+
+	created, err := useCase.Execute(domain.APIKeyCreateInput{Name: "batch", Scopes: []string{domain.APIKeyScopeRead}})
+
+	assert.Nil(t, err)
+	assert.Equal(t, "KEY1", created.Reference)
+	_, ok := parseAPIKeyPrefix(created.Key)
+	assert.True(t, ok)
+
+	repositoryMock.AssertExpectations(t)
+}
+
+func TestCreateAPIKey_GivenANotValidScope_WhenExecute_ThenReturnAValidationError(t *testing.T) {
+	t.Log("Failure to create an API key because a scope is not valid")
+
+	repositoryMock := new(apiKeyRepositoryMock)
+
+	useCase := NewDefaultCreateAPIKey(repositoryMock)
+
+	_, err := useCase.Execute(domain.APIKeyCreateInput{Name: "batch", Scopes: []string{"delete"}})
+
+	assert.NotNil(t, err)
+	assert.Equal(t, "API key scope delete is not valid, valid scopes are read, write, admin", err.Error())
+
+	repositoryMock.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+func TestCreateAPIKey_GivenAPastExpirationDate_WhenExecute_ThenReturnAValidationError(t *testing.T) {
+	t.Log("Failure to create an API key because it is already expired")
+
+	expires := time.Now().UTC().Add(-time.Hour)
+	repositoryMock := new(apiKeyRepositoryMock)
+
+	useCase := NewDefaultCreateAPIKey(repositoryMock)
+
+	_, err := useCase.Execute(domain.APIKeyCreateInput{Name: "batch", Scopes: []string{domain.APIKeyScopeRead}, ExpiresDate: &expires})
+
+	assert.NotNil(t, err)
+	assert.Equal(t, "API key expiration date must be in the future", err.Error())
+}
+
+func TestCreateAPIKey_GivenAValidInput_WhenExecuteAndCreateReturnedAnError_ThenReturnAFatalError(t *testing.T) {
+	t.Log("Failure to create an API key because the repository returned an error")
+
+	repositoryMock := new(apiKeyRepositoryMock)
+	repositoryMock.On("Create", mock.AnythingOfType("APIKey")).Return(domain.APIKey{}, errors.New("repository error"))
+
+	useCase := NewDefaultCreateAPIKey(repositoryMock)
+
+	_, err := useCase.Execute(domain.APIKeyCreateInput{Name: "batch", Scopes: []string{domain.APIKeyScopeRead}})
+
+	assert.NotNil(t, err)
+	assert.Equal(t, "unexpected error when create the API key", err.Error())
+}
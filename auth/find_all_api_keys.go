@@ -0,0 +1,37 @@
+package auth
+
+import (
+	"github.com/desarrollogj/golang-api-example/domain"
+	"github.com/desarrollogj/golang-api-example/infrastructure"
+	"github.com/desarrollogj/golang-api-example/libs/errors"
+	"github.com/desarrollogj/golang-api-example/libs/logger"
+)
+
+// FindAllAPIKeys represents the method to be implemented to list the API keys
+type FindAllAPIKeys interface {
+	Execute() ([]domain.APIKey, error)
+}
+
+// defaultFindAllAPIKeys is the default implementation of FindAllAPIKeys interface
+type defaultFindAllAPIKeys struct {
+	repository infrastructure.APIKeyRepository
+}
+
+// NewDefaultFindAllAPIKeys creates a defaultFindAllAPIKeys instance
+func NewDefaultFindAllAPIKeys(repository infrastructure.APIKeyRepository) defaultFindAllAPIKeys {
+	return defaultFindAllAPIKeys{
+		repository: repository,
+	}
+}
+
+// Execute returns all the API keys, including the expired and revoked ones
+func (s defaultFindAllAPIKeys) Execute() ([]domain.APIKey, error) {
+	keys, err := s.repository.FindAll()
+	if err != nil {
+		errMsg := "unexpected error when find all API keys"
+		logger.AppLog.Error().Err(err).Msg(errMsg)
+		return []domain.APIKey{}, errors.NewFatalError(errMsg)
+	}
+
+	return keys, nil
+}
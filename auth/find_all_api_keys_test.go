@@ -0,0 +1,38 @@
+package auth
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/desarrollogj/golang-api-example/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindAllAPIKeys_GivenStoredKeys_WhenExecute_ThenReturnThem(t *testing.T) {
+	t.Log("Successfully list the API keys")
+
+	keys := []domain.APIKey{{Reference: "KEY1"}, {Reference: "KEY2"}}
+	repositoryMock := new(apiKeyRepositoryMock)
+	repositoryMock.On("FindAll").Return(keys, nil)
+
+	useCase := NewDefaultFindAllAPIKeys(repositoryMock)
+
+	result, err := useCase.Execute()
+
+	assert.Nil(t, err)
+	assert.Equal(t, keys, result)
+}
+
+func TestFindAllAPIKeys_GivenStoredKeys_WhenExecuteAndFindReturnedAnError_ThenReturnAFatalError(t *testing.T) {
+	t.Log("Failure to list the API keys because the repository returned an error")
+
+	repositoryMock := new(apiKeyRepositoryMock)
+	repositoryMock.On("FindAll").Return([]domain.APIKey{}, errors.New("repository error"))
+
+	useCase := NewDefaultFindAllAPIKeys(repositoryMock)
+
+	_, err := useCase.Execute()
+
+	assert.NotNil(t, err)
+	assert.Equal(t, "unexpected error when find all API keys", err.Error())
+}
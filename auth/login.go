@@ -0,0 +1,81 @@
+package auth
+
+import (
+	"github.com/desarrollogj/golang-api-example/domain"
+	"github.com/desarrollogj/golang-api-example/infrastructure"
+	"github.com/desarrollogj/golang-api-example/libs/errors"
+	"github.com/desarrollogj/golang-api-example/libs/logger"
+	"github.com/desarrollogj/golang-api-example/libs/password"
+)
+
+const invalidCredentialsMessage = "email or password is not valid"
+
+// Login represents the method to be implemented to authenticate an user with its password
+type Login interface {
+	Execute(input domain.LoginInput) (domain.AccessToken, error)
+}
+
+// defaultLogin is the default implementation of Login interface
+type defaultLogin struct {
+	repository           infrastructure.UserRepository
+	credentialRepository infrastructure.CredentialRepository
+	issuer               TokenIssuer
+}
+
+// NewDefaultLogin creates a defaultLogin instance
+func NewDefaultLogin(repository infrastructure.UserRepository,
+	credentialRepository infrastructure.CredentialRepository,
+	issuer TokenIssuer) defaultLogin {
+	return defaultLogin{
+		repository:           repository,
+		credentialRepository: credentialRepository,
+		issuer:               issuer,
+	}
+}
+
+// Execute checks the user password and issues an access token. Only active users can login.
+// Unknown emails, users without password and wrong passwords return the same error.
+// Without the personal data encryption several active users can share the email, so the password of every one of them is checked,
+// and the login is refused when it is not valid for exactly one user
+func (s defaultLogin) Execute(input domain.LoginInput) (domain.AccessToken, error) {
+	users, err := s.repository.FindAllActiveByEmail(input.Email)
+	if err != nil {
+		errMsg := "unexpected error when try to get user by email"
+		logger.AppLog.Error().Err(err).Msg(errMsg)
+		return domain.AccessToken{}, errors.NewFatalError(errMsg)
+	}
+
+	// The password is always compared, so a missing user or credential takes as long as a wrong password
+	if len(users) == 0 {
+		password.Compare("", input.Password)
+		return domain.AccessToken{}, errors.NewBusinessUnauthorizedError(invalidCredentialsMessage).WithKey("credentials_not_valid")
+	}
+
+	matches := []domain.User{}
+	for _, user := range users {
+		credential, err := s.credentialRepository.FindByUserReference(user.Reference)
+		if err != nil {
+			errMsg := "unexpected error when try to get user credentials"
+			logger.AppLog.Error().Err(err).Msg(errMsg)
+			return domain.AccessToken{}, errors.NewFatalError(errMsg)
+		}
+		if password.Compare(credential.PasswordHash, input.Password) {
+			matches = append(matches, user)
+		}
+	}
+	if len(matches) > 1 {
+		logger.AppLog.Warn().Int("users", len(matches)).Msg("login refused because the password is valid for several users with the same email")
+	}
+	if len(matches) != 1 {
+		return domain.AccessToken{}, errors.NewBusinessUnauthorizedError(invalidCredentialsMessage).WithKey("credentials_not_valid")
+	}
+
+	token, err := s.issuer.Issue(matches[0])
+	if err != nil {
+		errMsg := "unexpected error when issue the access token"
+		logger.AppLog.Error().Err(err).Msg(errMsg)
+		return domain.AccessToken{}, errors.NewFatalError(errMsg)
+	}
+
+	return token, nil
+}
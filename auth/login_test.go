@@ -0,0 +1,161 @@
+package auth
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/desarrollogj/golang-api-example/domain"
+	"github.com/desarrollogj/golang-api-example/infrastructure"
+	"github.com/desarrollogj/golang-api-example/infrastructure/usertest"
+	appErrors "github.com/desarrollogj/golang-api-example/libs/errors"
+	"github.com/desarrollogj/golang-api-example/libs/password"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newUserRepositoryMock(users ...domain.User) infrastructure.UserRepository {
+	repository := usertest.NewInMemoryUserRepository()
+	for _, user := range users {
+		repository.Create(user)
+	}
+	return repository
+}
+
+func newCredentialMock(t *testing.T, reference string, value string) domain.Credential {
+	hash, err := password.Hash(value)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return domain.Credential{UserReference: reference, PasswordHash: hash}
+}
+
+func TestLogin_GivenValidCredentials_WhenExecute_ThenReturnAnAccessToken(t *testing.T) {
+	t.Log("Successfully login an User")
+
+	user := domain.User{GenericEntity: domain.GenericEntity{Reference: "USER1", IsActive: true}, Email: "foobar@email.com"}
+	accessToken := domain.AccessToken{Token: "TOKEN", TokenType: "Bearer", ExpiresIn: 900}
+	credentialRepositoryMock := new(credentialRepositoryMock)
+	credentialRepositoryMock.On("FindByUserReference", "USER1").Return(newCredentialMock(t, "USER1", "Secret-Password1"), nil)
+	issuerMock := new(tokenIssuerMock)
+	issuerMock.On("Issue", user).Return(accessToken, nil)
+
+	useCase := NewDefaultLogin(newUserRepositoryMock(user), credentialRepositoryMock, issuerMock)
+
+	token, err := useCase.Execute(domain.LoginInput{Email: "FOOBAR@email.com", Password: "Secret-Password1"})
+
+	assert.Nil(t, err)
+	assert.Equal(t, accessToken, token)
+
+	credentialRepositoryMock.AssertExpectations(t)
+	issuerMock.AssertExpectations(t)
+}
+
+func TestLogin_GivenAWrongPassword_WhenExecute_ThenReturnAnUnauthorizedError(t *testing.T) {
+	t.Log("Failure to login an User because the password is wrong")
+
+	user := domain.User{GenericEntity: domain.GenericEntity{Reference: "USER1", IsActive: true}, Email: "foobar@email.com"}
+	credentialRepositoryMock := new(credentialRepositoryMock)
+	credentialRepositoryMock.On("FindByUserReference", "USER1").Return(newCredentialMock(t, "USER1", "Secret-Password1"), nil)
+	issuerMock := new(tokenIssuerMock)
+
+	useCase := NewDefaultLogin(newUserRepositoryMock(user), credentialRepositoryMock, issuerMock)
+
+	_, err := useCase.Execute(domain.LoginInput{Email: "foobar@email.com", Password: "Wrong-Password1"})
+
+	assert.NotNil(t, err)
+	assert.Equal(t, "email or password is not valid", err.Error())
+	assert.Equal(t, appErrors.UnauthorizedErrorCode, err.(*appErrors.BusinessError).Err)
+
+	issuerMock.AssertNotCalled(t, "Issue", mock.Anything)
+}
+
+func TestLogin_GivenAnUnknownOrInactiveUser_WhenExecute_ThenReturnAnUnauthorizedError(t *testing.T) {
+	t.Log("Failure to login an User because it does not exist or is not active")
+
+	user := domain.User{GenericEntity: domain.GenericEntity{Reference: "USER1", IsActive: false}, Email: "foobar@email.com", Status: domain.UserStatusSuspended}
+	credentialRepositoryMock := new(credentialRepositoryMock)
+	issuerMock := new(tokenIssuerMock)
+
+	useCase := NewDefaultLogin(newUserRepositoryMock(user), credentialRepositoryMock, issuerMock)
+
+	_, err := useCase.Execute(domain.LoginInput{Email: "foobar@email.com", Password: "Secret-Password1"})
+	assert.Equal(t, "email or password is not valid", err.Error())
+
+	_, err = useCase.Execute(domain.LoginInput{Email: "unknown@email.com", Password: "Secret-Password1"})
+	assert.Equal(t, "email or password is not valid", err.Error())
+
+	credentialRepositoryMock.AssertNotCalled(t, "FindByUserReference", mock.Anything)
+}
+
+func TestLogin_GivenAnUserWithoutPassword_WhenExecute_ThenReturnAnUnauthorizedError(t *testing.T) {
+	t.Log("Failure to login an User because it has no password")
+
+	user := domain.User{GenericEntity: domain.GenericEntity{Reference: "USER1", IsActive: true}, Email: "foobar@email.com"}
+	credentialRepositoryMock := new(credentialRepositoryMock)
+	credentialRepositoryMock.On("FindByUserReference", "USER1").Return(domain.Credential{}, nil)
+
+	useCase := NewDefaultLogin(newUserRepositoryMock(user), credentialRepositoryMock, new(tokenIssuerMock))
+
+	_, err := useCase.Execute(domain.LoginInput{Email: "foobar@email.com", Password: ""})
+
+	assert.NotNil(t, err)
+	assert.Equal(t, "email or password is not valid", err.Error())
+}
+
+func TestLogin_GivenValidCredentials_WhenExecuteAndCredentialRepositoryFailed_ThenReturnAFatalError(t *testing.T) {
+	t.Log("Failure to login an User because the credential repository returned an error")
+
+	user := domain.User{GenericEntity: domain.GenericEntity{Reference: "USER1", IsActive: true}, Email: "foobar@email.com"}
+	credentialRepositoryMock := new(credentialRepositoryMock)
+	credentialRepositoryMock.On("FindByUserReference", "USER1").Return(domain.Credential{}, errors.New("repository error"))
+
+	useCase := NewDefaultLogin(newUserRepositoryMock(user), credentialRepositoryMock, new(tokenIssuerMock))
+
+	_, err := useCase.Execute(domain.LoginInput{Email: "foobar@email.com", Password: "Secret-Password1"})
+
+	assert.NotNil(t, err)
+	assert.Equal(t, "unexpected error when try to get user credentials", err.Error())
+}
+
+func TestLogin_GivenActiveUsersWithTheSameEmail_WhenExecute_ThenLoginTheUserWithThePassword(t *testing.T) {
+	t.Log("Successfully login the only User whose password is valid among the active users with the same email")
+
+	first := domain.User{GenericEntity: domain.GenericEntity{Reference: "USER1", IsActive: true}, Email: "foobar@email.com"}
+	second := domain.User{GenericEntity: domain.GenericEntity{Reference: "USER2", IsActive: true}, Email: "foobar@email.com"}
+	accessToken := domain.AccessToken{Token: "TOKEN", TokenType: "Bearer", ExpiresIn: 900}
+	credentialRepositoryMock := new(credentialRepositoryMock)
+	credentialRepositoryMock.On("FindByUserReference", "USER1").Return(newCredentialMock(t, "USER1", "Secret-Password1"), nil)
+	credentialRepositoryMock.On("FindByUserReference", "USER2").Return(newCredentialMock(t, "USER2", "Secret-Password2"), nil)
+	issuerMock := new(tokenIssuerMock)
+	issuerMock.On("Issue", second).Return(accessToken, nil)
+
+	useCase := NewDefaultLogin(newUserRepositoryMock(first, second), credentialRepositoryMock, issuerMock)
+
+	token, err := useCase.Execute(domain.LoginInput{Email: "foobar@email.com", Password: "Secret-Password2"})
+
+	assert.Nil(t, err)
+	assert.Equal(t, accessToken, token)
+
+	credentialRepositoryMock.AssertExpectations(t)
+	issuerMock.AssertExpectations(t)
+}
+
+func TestLogin_GivenActiveUsersWithTheSameEmailAndPassword_WhenExecute_ThenReturnAnUnauthorizedError(t *testing.T) {
+	t.Log("Failure to login an User because the password is valid for several active users with the same email")
+
+	first := domain.User{GenericEntity: domain.GenericEntity{Reference: "USER1", IsActive: true}, Email: "foobar@email.com"}
+	second := domain.User{GenericEntity: domain.GenericEntity{Reference: "USER2", IsActive: true}, Email: "foobar@email.com"}
+	credentialRepositoryMock := new(credentialRepositoryMock)
+	credentialRepositoryMock.On("FindByUserReference", "USER1").Return(newCredentialMock(t, "USER1", "Secret-Password1"), nil)
+	credentialRepositoryMock.On("FindByUserReference", "USER2").Return(newCredentialMock(t, "USER2", "Secret-Password1"), nil)
+	issuerMock := new(tokenIssuerMock)
+
+	useCase := NewDefaultLogin(newUserRepositoryMock(first, second), credentialRepositoryMock, issuerMock)
+
+	_, err := useCase.Execute(domain.LoginInput{Email: "foobar@email.com", Password: "Secret-Password1"})
+
+	assert.NotNil(t, err)
+	assert.Equal(t, "email or password is not valid", err.Error())
+
+	issuerMock.AssertNotCalled(t, "Issue", mock.Anything)
+}
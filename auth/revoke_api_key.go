@@ -0,0 +1,51 @@
+package auth
+
+import (
+	"time"
+
+	"github.com/desarrollogj/golang-api-example/domain"
+	"github.com/desarrollogj/golang-api-example/infrastructure"
+	"github.com/desarrollogj/golang-api-example/libs/errors"
+	"github.com/desarrollogj/golang-api-example/libs/logger"
+)
+
+// RevokeAPIKey represents the method to be implemented to revoke API keys
+type RevokeAPIKey interface {
+	Execute(reference string) (domain.APIKey, error)
+}
+
+// defaultRevokeAPIKey is the default implementation of RevokeAPIKey interface
+type defaultRevokeAPIKey struct {
+	repository infrastructure.APIKeyRepository
+}
+
+// NewDefaultRevokeAPIKey creates a defaultRevokeAPIKey instance
+func NewDefaultRevokeAPIKey(repository infrastructure.APIKeyRepository) defaultRevokeAPIKey {
+	return defaultRevokeAPIKey{
+		repository: repository,
+	}
+}
+
+// Execute revokes the API key. Revoked keys are kept to be listed, but they can not be used or rotated.
+// Revoking an already revoked key returns it without changes
+func (s defaultRevokeAPIKey) Execute(reference string) (domain.APIKey, error) {
+	current, err := findAPIKey(s.repository, reference)
+	if err != nil {
+		return domain.APIKey{}, err
+	}
+	if current.RevokedDate != nil {
+		return current, nil
+	}
+
+	now := time.Now().UTC()
+	current.RevokedDate = &now
+	current.UpdatedDate = now
+	updated, err := s.repository.Update(current)
+	if err != nil {
+		errMsg := "unexpected error when revoke the API key"
+		logger.AppLog.Error().Err(err).Msg(errMsg)
+		return domain.APIKey{}, errors.NewFatalError(errMsg)
+	}
+
+	return updated, nil
+}
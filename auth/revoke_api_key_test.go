@@ -0,0 +1,63 @@
+package auth
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/desarrollogj/golang-api-example/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestRevokeAPIKey_GivenAnActiveKey_WhenExecute_ThenRevokeIt(t *testing.T) {
+	t.Log("Successfully revoke an API key")
+
+	current := domain.APIKey{Reference: "KEY1"}
+	repositoryMock := new(apiKeyRepositoryMock)
+	repositoryMock.On("FindByReference", "KEY1").Return(current, nil)
+	repositoryMock.On("Update", mock.MatchedBy(func(key domain.APIKey) bool {
+		return key.RevokedDate != nil && key.UpdatedDate.Equal(*key.RevokedDate)
+	})).Return(current, nil)
+
+	useCase := NewDefaultRevokeAPIKey(repositoryMock)
+
+	_, err := useCase.Execute("KEY1")
+
+	assert.Nil(t, err)
+
+	repositoryMock.AssertExpectations(t)
+}
+
+func TestRevokeAPIKey_GivenARevokedKey_WhenExecute_ThenReturnItWithoutChanges(t *testing.T) {
+	t.Log("Revoke an already revoked API key has no effect")
+
+	revoked := time.Now().UTC()
+	current := domain.APIKey{Reference: "KEY1", RevokedDate: &revoked}
+	repositoryMock := new(apiKeyRepositoryMock)
+	repositoryMock.On("FindByReference", "KEY1").Return(current, nil)
+
+	useCase := NewDefaultRevokeAPIKey(repositoryMock)
+
+	key, err := useCase.Execute("KEY1")
+
+	assert.Nil(t, err)
+	assert.Equal(t, current, key)
+
+	repositoryMock.AssertNotCalled(t, "Update", mock.Anything)
+}
+
+func TestRevokeAPIKey_GivenAnActiveKey_WhenExecuteAndUpdateReturnedAnError_ThenReturnAFatalError(t *testing.T) {
+	t.Log("Failure to revoke an API key because the repository returned an error")
+
+	repositoryMock := new(apiKeyRepositoryMock)
+	repositoryMock.On("FindByReference", "KEY1").Return(domain.APIKey{Reference: "KEY1"}, nil)
+	repositoryMock.On("Update", mock.AnythingOfType("APIKey")).Return(domain.APIKey{}, errors.New("repository error"))
+
+	useCase := NewDefaultRevokeAPIKey(repositoryMock)
+
+	_, err := useCase.Execute("KEY1")
+
+	assert.NotNil(t, err)
+	assert.Equal(t, "unexpected error when revoke the API key", err.Error())
+}
@@ -0,0 +1,72 @@
+package auth
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/desarrollogj/golang-api-example/domain"
+	"github.com/desarrollogj/golang-api-example/infrastructure"
+	"github.com/desarrollogj/golang-api-example/libs/errors"
+	"github.com/desarrollogj/golang-api-example/libs/logger"
+)
+
+// RotateAPIKey represents the method to be implemented to replace the secret of an API key
+type RotateAPIKey interface {
+	Execute(reference string) (domain.APIKeySecret, error)
+}
+
+// defaultRotateAPIKey is the default implementation of RotateAPIKey interface
+type defaultRotateAPIKey struct {
+	repository infrastructure.APIKeyRepository
+}
+
+// NewDefaultRotateAPIKey creates a defaultRotateAPIKey instance
+func NewDefaultRotateAPIKey(repository infrastructure.APIKeyRepository) defaultRotateAPIKey {
+	return defaultRotateAPIKey{
+		repository: repository,
+	}
+}
+
+// Execute replaces the API key secret, keeping its name, scopes and expiration. The previous secret stops working immediately
+func (s defaultRotateAPIKey) Execute(reference string) (domain.APIKeySecret, error) {
+	current, err := findAPIKey(s.repository, reference)
+	if err != nil {
+		return domain.APIKeySecret{}, err
+	}
+	if current.RevokedDate != nil {
+		return domain.APIKeySecret{}, errors.NewValidationError("API key was revoked and can not be rotated").WithKey("api_key_revoked")
+	}
+
+	secret, err := newAPIKeySecret()
+	if err != nil {
+		errMsg := "unexpected error when generate the API key"
+		logger.AppLog.Error().Err(err).Msg(errMsg)
+		return domain.APIKeySecret{}, errors.NewFatalError(errMsg)
+	}
+
+	current.Prefix = secret.Prefix
+	current.SecretHash = secret.Hash
+	current.UpdatedDate = time.Now().UTC()
+	updated, err := s.repository.Update(current)
+	if err != nil {
+		errMsg := "unexpected error when rotate the API key"
+		logger.AppLog.Error().Err(err).Msg(errMsg)
+		return domain.APIKeySecret{}, errors.NewFatalError(errMsg)
+	}
+
+	return domain.APIKeySecret{APIKey: updated, Key: secret.Key}, nil
+}
+
+func findAPIKey(repository infrastructure.APIKeyRepository, reference string) (domain.APIKey, error) {
+	key, err := repository.FindByReference(reference)
+	if err != nil {
+		errMsg := fmt.Sprintf("unexpected error when try to get API key with reference %s", reference)
+		logger.AppLog.Error().Err(err).Msg(errMsg)
+		return domain.APIKey{}, errors.NewFatalError(errMsg)
+	}
+	if len(key.Reference) == 0 {
+		return domain.APIKey{}, errors.NewNotFoundError("API key not found").WithKey("api_key_not_found")
+	}
+
+	return key, nil
+}
@@ -0,0 +1,61 @@
+package auth
+
+import (
+	"testing"
+	"time"
+
+	"github.com/desarrollogj/golang-api-example/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestRotateAPIKey_GivenAnActiveKey_WhenExecute_ThenReplaceItsSecret(t *testing.T) {
+	t.Log("Successfully rotate an API key")
+
+	current := domain.APIKey{Reference: "KEY1", Name: "batch", Prefix: "0123456789ab", SecretHash: "hash"}
+	repositoryMock := new(apiKeyRepositoryMock)
+	repositoryMock.On("FindByReference", "KEY1").Return(current, nil)
+	repositoryMock.On("Update", mock.MatchedBy(func(key domain.APIKey) bool {
+		return key.Reference == "KEY1" && key.Prefix != current.Prefix && key.SecretHash != current.SecretHash
+	})).Return(current, nil)
+
+	useCase := NewDefaultRotateAPIKey(repositoryMock)
+
+	rotated, err := useCase.Execute("KEY1")
+
+	assert.Nil(t, err)
+	assert.NotEmpty(t, rotated.Key)
+
+	repositoryMock.AssertExpectations(t)
+}
+
+func TestRotateAPIKey_GivenARevokedKey_WhenExecute_ThenReturnAValidationError(t *testing.T) {
+	t.Log("Failure to rotate an API key because it was revoked")
+
+	revoked := time.Now().UTC()
+	repositoryMock := new(apiKeyRepositoryMock)
+	repositoryMock.On("FindByReference", "KEY1").Return(domain.APIKey{Reference: "KEY1", RevokedDate: &revoked}, nil)
+
+	useCase := NewDefaultRotateAPIKey(repositoryMock)
+
+	_, err := useCase.Execute("KEY1")
+
+	assert.NotNil(t, err)
+	assert.Equal(t, "API key was revoked and can not be rotated", err.Error())
+
+	repositoryMock.AssertNotCalled(t, "Update", mock.Anything)
+}
+
+func TestRotateAPIKey_GivenAReference_WhenExecuteAndKeyNotFound_ThenReturnAnError(t *testing.T) {
+	t.Log("Failure to rotate an API key because it was not found")
+
+	repositoryMock := new(apiKeyRepositoryMock)
+	repositoryMock.On("FindByReference", "KEY1").Return(domain.APIKey{}, nil)
+
+	useCase := NewDefaultRotateAPIKey(repositoryMock)
+
+	_, err := useCase.Execute("KEY1")
+
+	assert.NotNil(t, err)
+	assert.Equal(t, "API key not found", err.Error())
+}
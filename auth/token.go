@@ -0,0 +1,213 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/desarrollogj/golang-api-example/domain"
+	"github.com/desarrollogj/golang-api-example/libs/jwks"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+// TokenVerifier represents the method to be implemented to parse and validate access tokens
+type TokenVerifier interface {
+	Parse(token string) (AccessTokenClaims, error)
+}
+
+// TokenIssuer represents the methods to be implemented to issue and parse access tokens
+type TokenIssuer interface {
+	TokenVerifier
+	Issue(user domain.User) (domain.AccessToken, error)
+}
+
+// AccessTokenClaims are the claims of an access token. The subject is the user reference
+type AccessTokenClaims struct {
+	Email string   `json:"email"`
+	Roles []string `json:"roles,omitempty"`
+	jwt.RegisteredClaims
+}
+
+// defaultTokenIssuer is the default implementation of TokenIssuer interface. Tokens are signed JWT
+type defaultTokenIssuer struct {
+	config     domain.AuthConfiguration
+	method     jwt.SigningMethod
+	signingKey interface{}
+	keyFunc    jwt.Keyfunc
+}
+
+// NewDefaultTokenIssuer creates a defaultTokenIssuer instance.
+// HS256 tokens are signed and verified with the configured secret. RS256 and ES256 tokens are signed with the private key file,
+// and verified with the keys of the JWKS file (by the token kid) if it is set, or else with the public key file or the private key.
+// Without a private key file, RS256 and ES256 tokens can only be verified
+func NewDefaultTokenIssuer(config domain.AuthConfiguration) (defaultTokenIssuer, error) {
+	issuer := defaultTokenIssuer{config: config}
+	switch config.Algorithm {
+	case domain.SigningAlgorithmHS256:
+		if len(config.Secret) == 0 {
+			return defaultTokenIssuer{}, errors.New("auth secret is required for HS256")
+		}
+		if len(config.Secret) < domain.MinSigningSecretLength {
+			return defaultTokenIssuer{}, fmt.Errorf("auth secret must have at least %d characters for HS256", domain.MinSigningSecretLength)
+		}
+		issuer.method = jwt.SigningMethodHS256
+		issuer.signingKey = []byte(config.Secret)
+		issuer.keyFunc = func(t *jwt.Token) (interface{}, error) {
+			return []byte(config.Secret), nil
+		}
+		return issuer, nil
+	case domain.SigningAlgorithmRS256:
+		issuer.method = jwt.SigningMethodRS256
+	case domain.SigningAlgorithmES256:
+		issuer.method = jwt.SigningMethodES256
+	default:
+		return defaultTokenIssuer{}, fmt.Errorf("auth algorithm %s is not supported", config.Algorithm)
+	}
+
+	var publicKey crypto.PublicKey
+	if len(config.PrivateKeyFile) > 0 {
+		privateKey, err := loadPrivateKey(config.Algorithm, config.PrivateKeyFile)
+		if err != nil {
+			return defaultTokenIssuer{}, err
+		}
+		issuer.signingKey = privateKey
+		publicKey = privateKey.Public()
+	}
+
+	switch {
+	case len(config.JWKSFile) > 0:
+		keySet, err := jwks.Load(config.JWKSFile)
+		if err != nil {
+			return defaultTokenIssuer{}, err
+		}
+		issuer.keyFunc = func(t *jwt.Token) (interface{}, error) {
+			kid, _ := t.Header["kid"].(string)
+			if len(kid) == 0 {
+				return nil, errors.New("token kid is required")
+			}
+			return keySet.Key(kid)
+		}
+	case len(config.PublicKeyFile) > 0:
+		key, err := loadPublicKey(config.Algorithm, config.PublicKeyFile)
+		if err != nil {
+			return defaultTokenIssuer{}, err
+		}
+		publicKey = key
+		fallthrough
+	default:
+		if publicKey == nil {
+			return defaultTokenIssuer{}, fmt.Errorf("auth keys are required for %s", config.Algorithm)
+		}
+		issuer.keyFunc = func(t *jwt.Token) (interface{}, error) {
+			return publicKey, nil
+		}
+	}
+
+	return issuer, nil
+}
+
+// Issue creates a new signed access token for the user
+func (i defaultTokenIssuer) Issue(user domain.User) (domain.AccessToken, error) {
+	if i.signingKey == nil {
+		return domain.AccessToken{}, errors.New("access tokens can not be issued without a private key")
+	}
+
+	now := time.Now().UTC()
+	expires := now.Add(time.Duration(i.config.AccessTokenTTLSeconds) * time.Second)
+	claims := AccessTokenClaims{
+		Email: user.Email,
+		Roles: user.Roles,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewString(),
+			Issuer:    i.config.Issuer,
+			Subject:   user.Reference,
+			Audience:  jwt.ClaimStrings{i.config.Audience},
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(expires),
+		},
+	}
+
+	token := jwt.NewWithClaims(i.method, claims)
+	if len(i.config.KeyID) > 0 {
+		token.Header["kid"] = i.config.KeyID
+	}
+	signed, err := token.SignedString(i.signingKey)
+	if err != nil {
+		return domain.AccessToken{}, err
+	}
+
+	return domain.AccessToken{
+		Token:       signed,
+		TokenType:   domain.AccessTokenTypeBearer,
+		ExpiresIn:   i.config.AccessTokenTTLSeconds,
+		ExpiresDate: expires,
+	}, nil
+}
+
+// Parse checks the token signature, algorithm, issuer, audience and expiration, and returns its claims.
+// The dates are checked with the configured clock skew
+func (i defaultTokenIssuer) Parse(token string) (AccessTokenClaims, error) {
+	claims := AccessTokenClaims{}
+	_, err := jwt.ParseWithClaims(token, &claims, i.keyFunc,
+		jwt.WithValidMethods([]string{i.method.Alg()}),
+		jwt.WithIssuer(i.config.Issuer),
+		jwt.WithAudience(i.config.Audience),
+		jwt.WithLeeway(time.Duration(i.config.ClockSkewSeconds)*time.Second),
+		jwt.WithExpirationRequired())
+	if err != nil {
+		return AccessTokenClaims{}, err
+	}
+
+	return claims, nil
+}
+
+type privateKey interface {
+	Public() crypto.PublicKey
+}
+
+func loadPrivateKey(algorithm string, file string) (privateKey, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read auth private key: %w", err)
+	}
+
+	var key privateKey
+	if algorithm == domain.SigningAlgorithmES256 {
+		var ecKey *ecdsa.PrivateKey
+		ecKey, err = jwt.ParseECPrivateKeyFromPEM(data)
+		key = ecKey
+	} else {
+		var rsaKey *rsa.PrivateKey
+		rsaKey, err = jwt.ParseRSAPrivateKeyFromPEM(data)
+		key = rsaKey
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse auth private key: %w", err)
+	}
+
+	return key, nil
+}
+
+func loadPublicKey(algorithm string, file string) (crypto.PublicKey, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read auth public key: %w", err)
+	}
+
+	var key crypto.PublicKey
+	if algorithm == domain.SigningAlgorithmES256 {
+		key, err = jwt.ParseECPublicKeyFromPEM(data)
+	} else {
+		key, err = jwt.ParseRSAPublicKeyFromPEM(data)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse auth public key: %w", err)
+	}
+
+	return key, nil
+}
@@ -0,0 +1,241 @@
+package auth
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/desarrollogj/golang-api-example/domain"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func newAuthConfigurationMock() domain.AuthConfiguration {
+	return domain.AuthConfiguration{
+		Algorithm:             domain.SigningAlgorithmHS256,
+		Secret:                "secret-with-at-least-32-characters",
+		Issuer:                "issuer",
+		Audience:              "audience",
+		AccessTokenTTLSeconds: 900,
+	}
+}
+
+func writeRSAKeyMock(t *testing.T) string {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	file := filepath.Join(t.TempDir(), "private.pem")
+	data := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	if err := os.WriteFile(file, data, 0600); err != nil {
+		t.Fatal(err)
+	}
+	return file
+}
+
+func writeECKeyMock(t *testing.T) (string, *ecdsa.PrivateKey) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	file := filepath.Join(t.TempDir(), "private.pem")
+	if err := os.WriteFile(file, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der}), 0600); err != nil {
+		t.Fatal(err)
+	}
+	return file, key
+}
+
+func writeJWKSMock(t *testing.T, kid string, key *ecdsa.PrivateKey) string {
+	data, _ := json.Marshal(map[string]interface{}{"keys": []map[string]string{{
+		"kty": "EC",
+		"kid": kid,
+		"crv": "P-256",
+		"x":   base64.RawURLEncoding.EncodeToString(key.X.Bytes()),
+		"y":   base64.RawURLEncoding.EncodeToString(key.Y.Bytes()),
+	}}})
+	file := filepath.Join(t.TempDir(), "jwks.json")
+	if err := os.WriteFile(file, data, 0600); err != nil {
+		t.Fatal(err)
+	}
+	return file
+}
+
+func TestTokenIssuer_GivenHS256_WhenIssue_ThenTheTokenCanBeParsed(t *testing.T) {
+	t.Log("Should issue an HS256 access token that can be parsed")
+
+	issuer, err := NewDefaultTokenIssuer(newAuthConfigurationMock())
+	assert.Nil(t, err)
+
+	token, err := issuer.Issue(domain.User{
+		GenericEntity: domain.GenericEntity{Reference: "USER1"},
+		Email:         "foobar@email.com",
+		Roles:         []string{"admin"},
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, "Bearer", token.TokenType)
+	assert.Equal(t, 900, token.ExpiresIn)
+
+	claims, err := issuer.Parse(token.Token)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "USER1", claims.Subject)
+	assert.Equal(t, "foobar@email.com", claims.Email)
+	assert.Equal(t, []string{"admin"}, claims.Roles)
+	assert.Equal(t, "issuer", claims.Issuer)
+}
+
+func TestTokenIssuer_GivenRS256_WhenIssue_ThenTheTokenCanBeParsed(t *testing.T) {
+	t.Log("Should issue an RS256 access token that can be parsed")
+
+	config := newAuthConfigurationMock()
+	config.Algorithm = domain.SigningAlgorithmRS256
+	config.PrivateKeyFile = writeRSAKeyMock(t)
+
+	issuer, err := NewDefaultTokenIssuer(config)
+	assert.Nil(t, err)
+
+	token, err := issuer.Issue(domain.User{GenericEntity: domain.GenericEntity{Reference: "USER1"}})
+	assert.Nil(t, err)
+
+	claims, err := issuer.Parse(token.Token)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "USER1", claims.Subject)
+}
+
+func TestTokenIssuer_GivenATokenFromOtherIssuer_WhenParse_ThenReturnAnError(t *testing.T) {
+	t.Log("Should not parse a token signed with other secret or for other audience")
+
+	issuer, _ := NewDefaultTokenIssuer(newAuthConfigurationMock())
+
+	config := newAuthConfigurationMock()
+	config.Secret = "other-secret-with-at-least-32-characters"
+	other, _ := NewDefaultTokenIssuer(config)
+	token, _ := other.Issue(domain.User{GenericEntity: domain.GenericEntity{Reference: "USER1"}})
+	_, err := issuer.Parse(token.Token)
+	assert.NotNil(t, err)
+
+	config = newAuthConfigurationMock()
+	config.Audience = "other"
+	other, _ = NewDefaultTokenIssuer(config)
+	token, _ = other.Issue(domain.User{GenericEntity: domain.GenericEntity{Reference: "USER1"}})
+	_, err = issuer.Parse(token.Token)
+	assert.NotNil(t, err)
+}
+
+func TestTokenIssuer_GivenAnExpiredToken_WhenParse_ThenReturnAnError(t *testing.T) {
+	t.Log("Should not parse an expired token")
+
+	config := newAuthConfigurationMock()
+	config.AccessTokenTTLSeconds = -60
+	issuer, _ := NewDefaultTokenIssuer(config)
+	token, _ := issuer.Issue(domain.User{GenericEntity: domain.GenericEntity{Reference: "USER1"}})
+
+	_, err := issuer.Parse(token.Token)
+
+	assert.NotNil(t, err)
+}
+
+func TestTokenIssuer_GivenANotValidConfiguration_WhenCreate_ThenReturnAnError(t *testing.T) {
+	t.Log("Should not create an issuer without keys, with a short secret or with an unsupported algorithm")
+
+	config := newAuthConfigurationMock()
+	config.Secret = ""
+	_, err := NewDefaultTokenIssuer(config)
+	assert.NotNil(t, err)
+
+	config = newAuthConfigurationMock()
+	config.Secret = "local-auth-secret"
+	_, err = NewDefaultTokenIssuer(config)
+	assert.EqualError(t, err, "auth secret must have at least 32 characters for HS256")
+
+	config = newAuthConfigurationMock()
+	config.Algorithm = domain.SigningAlgorithmRS256
+	config.PrivateKeyFile = filepath.Join(t.TempDir(), "missing.pem")
+	_, err = NewDefaultTokenIssuer(config)
+	assert.NotNil(t, err)
+
+	config = newAuthConfigurationMock()
+	config.Algorithm = "none"
+	_, err = NewDefaultTokenIssuer(config)
+	assert.NotNil(t, err)
+}
+
+func TestTokenIssuer_GivenES256WithJWKS_WhenIssue_ThenTheTokenIsVerifiedByItsKid(t *testing.T) {
+	t.Log("Should issue an ES256 access token with a kid that is verified with the JWKS key")
+
+	privateKeyFile, key := writeECKeyMock(t)
+	config := newAuthConfigurationMock()
+	config.Algorithm = domain.SigningAlgorithmES256
+	config.PrivateKeyFile = privateKeyFile
+	config.KeyID = "key-1"
+	config.JWKSFile = writeJWKSMock(t, "key-1", key)
+
+	issuer, err := NewDefaultTokenIssuer(config)
+	assert.Nil(t, err)
+
+	token, err := issuer.Issue(domain.User{GenericEntity: domain.GenericEntity{Reference: "USER1"}})
+	assert.Nil(t, err)
+
+	claims, err := issuer.Parse(token.Token)
+	assert.Nil(t, err)
+	assert.Equal(t, "USER1", claims.Subject)
+
+	config.KeyID = "key-2"
+	other, _ := NewDefaultTokenIssuer(config)
+	token, _ = other.Issue(domain.User{GenericEntity: domain.GenericEntity{Reference: "USER1"}})
+	_, err = issuer.Parse(token.Token)
+	assert.NotNil(t, err)
+}
+
+func TestTokenIssuer_GivenAJWKSWithoutPrivateKey_WhenIssue_ThenReturnAnError(t *testing.T) {
+	t.Log("Should only verify tokens when there is no private key")
+
+	_, key := writeECKeyMock(t)
+	config := newAuthConfigurationMock()
+	config.Algorithm = domain.SigningAlgorithmES256
+	config.JWKSFile = writeJWKSMock(t, "key-1", key)
+
+	issuer, err := NewDefaultTokenIssuer(config)
+	assert.Nil(t, err)
+
+	_, err = issuer.Issue(domain.User{GenericEntity: domain.GenericEntity{Reference: "USER1"}})
+	assert.NotNil(t, err)
+}
+
+func TestTokenIssuer_GivenARecentlyExpiredToken_WhenParse_ThenAcceptItWithinTheClockSkew(t *testing.T) {
+	t.Log("Should accept a token expired less than the clock skew ago")
+
+	config := newAuthConfigurationMock()
+	config.ClockSkewSeconds = 60
+	issuer, _ := NewDefaultTokenIssuer(config)
+	claims := AccessTokenClaims{RegisteredClaims: jwt.RegisteredClaims{
+		Subject:   "USER1",
+		Issuer:    "issuer",
+		Audience:  jwt.ClaimStrings{"audience"},
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(-30 * time.Second)),
+	}}
+	token, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte("secret-with-at-least-32-characters"))
+
+	_, err := issuer.Parse(token)
+	assert.Nil(t, err)
+
+	config.ClockSkewSeconds = 0
+	issuer, _ = NewDefaultTokenIssuer(config)
+	_, err = issuer.Parse(token)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,50 @@
+package domain
+
+import "time"
+
+const (
+	APIKeyScopeRead  = "read"
+	APIKeyScopeWrite = "write"
+	APIKeyScopeAdmin = "admin"
+)
+
+// APIKeyScopes are the valid API key scopes. Read allows the safe methods, write the other ones, and admin the API keys management besides both of them
+var APIKeyScopes = []string{APIKeyScopeRead, APIKeyScopeWrite, APIKeyScopeAdmin}
+
+// APIKey is a credential for services that can not use the access tokens. The secret is only known when the key is created
+// or rotated, only its hash is stored. The prefix identifies the key
+type APIKey struct {
+	Reference    string
+	Name         string
+	Prefix       string
+	SecretHash   string
+	Scopes       []string
+	ExpiresDate  *time.Time
+	LastUsedDate *time.Time
+	RevokedDate  *time.Time
+	CreatedDate  time.Time
+	UpdatedDate  time.Time
+}
+
+// HasScope returns true when the API key was granted the scope. The admin scope includes the read and write ones
+func (k APIKey) HasScope(scope string) bool {
+	for _, s := range k.Scopes {
+		if s == scope || s == APIKeyScopeAdmin {
+			return true
+		}
+	}
+
+	return false
+}
+
+type APIKeyCreateInput struct {
+	Name        string
+	Scopes      []string
+	ExpiresDate *time.Time
+}
+
+// APIKeySecret is an API key with its plain secret, returned once after the key is created or rotated
+type APIKeySecret struct {
+	APIKey
+	Key string
+}
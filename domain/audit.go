@@ -0,0 +1,20 @@
+package domain
+
+import "time"
+
+const (
+	AuditEntityUser       = "user"
+	AuditActionUserErase  = "user_erased"
+	AuditActionUserStatus = "user_status_changed"
+	AuditActionUserRoles  = "user_roles_changed"
+	AuditActionUserMerge  = "user_merged"
+)
+
+type AuditEntry struct {
+	Reference       string
+	Action          string
+	EntityType      string
+	EntityReference string
+	Details         map[string]string
+	CreatedDate     time.Time
+}
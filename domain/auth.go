@@ -0,0 +1,40 @@
+package domain
+
+import "time"
+
+const (
+	AccessTokenTypeBearer = "Bearer"
+	SigningAlgorithmHS256 = "HS256"
+	SigningAlgorithmRS256 = "RS256"
+	SigningAlgorithmES256 = "ES256"
+	// MinSigningSecretLength is the minimum length of the HMAC secrets, so the tokens can not be forged by guessing them
+	MinSigningSecretLength = 32
+)
+
+// Credential holds the password credentials of an user. It is stored apart from the user, so it is never loaded with it
+type Credential struct {
+	UserReference string
+	PasswordHash  string
+	CreatedDate   time.Time
+	UpdatedDate   time.Time
+}
+
+type UserPasswordInput struct {
+	Reference string
+	Password  string
+	// CurrentPassword is verified when RequireCurrentPassword is true, like when the users change their own password
+	CurrentPassword        string
+	RequireCurrentPassword bool
+}
+
+type LoginInput struct {
+	Email    string
+	Password string
+}
+
+type AccessToken struct {
+	Token       string
+	TokenType   string
+	ExpiresIn   int
+	ExpiresDate time.Time
+}
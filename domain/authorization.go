@@ -0,0 +1,46 @@
+package domain
+
+const (
+	PermissionUsersRead    = "users:read"
+	PermissionUsersWrite   = "users:write"
+	PermissionUsersAdmin   = "users:admin"
+	PermissionAPIKeysAdmin = "api-keys:admin"
+	IdentitySourceToken    = "token"
+	IdentitySourceHeader   = "header"
+)
+
+// Permissions are all the permissions that can be granted to a role
+var Permissions = []string{PermissionUsersRead, PermissionUsersWrite, PermissionUsersAdmin, PermissionAPIKeysAdmin}
+
+// APIKeyScopePermissions are the permissions granted to the API keys by their scopes. The admin scope grants every
+// permission, as the admin role
+var APIKeyScopePermissions = map[string][]string{
+	APIKeyScopeRead:  {PermissionUsersRead},
+	APIKeyScopeWrite: {PermissionUsersWrite},
+	APIKeyScopeAdmin: {PermissionUsersRead, PermissionUsersWrite, PermissionUsersAdmin, PermissionAPIKeysAdmin},
+}
+
+// Identity is the authenticated caller of a request, with the permissions granted by its roles
+type Identity struct {
+	Subject     string
+	Roles       []string
+	Permissions []string
+}
+
+// HasPermission returns true when the identity was granted the permission
+func (i Identity) HasPermission(permission string) bool {
+	for _, p := range i.Permissions {
+		if p == permission {
+			return true
+		}
+	}
+
+	return false
+}
+
+type UserRolesInput struct {
+	Reference string
+	Roles     []string
+	// ChangedBy is the subject of the caller that changes the roles
+	ChangedBy string
+}
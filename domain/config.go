@@ -1,11 +1,137 @@
 package domain
 
+type ServerConfiguration struct {
+	ReadTimeoutSeconds       int `mapstructure:"readTimeoutSeconds"`
+	ReadHeaderTimeoutSeconds int `mapstructure:"readHeaderTimeoutSeconds"`
+	WriteTimeoutSeconds      int `mapstructure:"writeTimeoutSeconds"`
+	IdleTimeoutSeconds       int `mapstructure:"idleTimeoutSeconds"`
+	DrainSeconds             int `mapstructure:"drainSeconds"`
+	ShutdownTimeoutSeconds   int `mapstructure:"shutdownTimeoutSeconds"`
+}
+
 type ApplicationConfiguration struct {
 	PagingDefaultPage int `mapstructure:"pagingDefaultPage"`
 	PagingDefaultSize int `mapstructure:"pagingDefaultSize"`
 }
 
+type ReferenceConfiguration struct {
+	Generator string `mapstructure:"generator"`
+	Prefix    string `mapstructure:"prefix"`
+}
+
+type LocalizationConfiguration struct {
+	DefaultLanguage string `mapstructure:"defaultLanguage"`
+}
+
+type ErrorsConfiguration struct {
+	Format             string `mapstructure:"format"`
+	ProblemTypeBaseURI string `mapstructure:"problemTypeBaseUri"`
+}
+
+type IdempotencyConfiguration struct {
+	TTLSeconds         int `mapstructure:"ttlSeconds"`
+	LockTimeoutSeconds int `mapstructure:"lockTimeoutSeconds"`
+	WaitTimeoutSeconds int `mapstructure:"waitTimeoutSeconds"`
+}
+
 type MongoRepositoryConfiguration struct {
-	Database        string `mapstructure:"database"`
-	UsersCollection string `mapstructure:"usersCollection"`
+	Database              string `mapstructure:"database"`
+	UsersCollection       string `mapstructure:"usersCollection"`
+	AuditCollection       string `mapstructure:"auditCollection"`
+	CredentialsCollection string `mapstructure:"credentialsCollection"`
+	APIKeysCollection     string `mapstructure:"apiKeysCollection"`
+	GroupsCollection      string `mapstructure:"groupsCollection"`
+	IdempotencyCollection string `mapstructure:"idempotencyCollection"`
+}
+
+type EncryptionConfiguration struct {
+	Enabled                     bool   `mapstructure:"enabled"`
+	KeyringFile                 string `mapstructure:"keyringFile"`
+	ReencryptionIntervalSeconds int    `mapstructure:"reencryptionIntervalSeconds"`
+	ReencryptionBatchSize       int    `mapstructure:"reencryptionBatchSize"`
+}
+
+type SchemaValidationConfiguration struct {
+	Enabled     bool   `mapstructure:"enabled"`
+	Level       string `mapstructure:"level"`
+	Action      string `mapstructure:"action"`
+	ReportLimit int    `mapstructure:"reportLimit"`
+}
+
+type EmailVerificationConfiguration struct {
+	Secret                string `mapstructure:"secret"`
+	From                  string `mapstructure:"from"`
+	LinkFormat            string `mapstructure:"linkFormat"`
+	TokenTTLSeconds       int    `mapstructure:"tokenTtlSeconds"`
+	ResendIntervalSeconds int    `mapstructure:"resendIntervalSeconds"`
+}
+
+type MailConfiguration struct {
+	Driver    string                `mapstructure:"driver"`
+	Directory string                `mapstructure:"directory"`
+	SMTP      MailSMTPConfiguration `mapstructure:"smtp"`
+}
+
+type MailSMTPConfiguration struct {
+	Host     string `mapstructure:"host"`
+	Port     int    `mapstructure:"port"`
+	Username string `mapstructure:"username"`
+	Password string `mapstructure:"password"`
+}
+
+type AuthConfiguration struct {
+	Enabled               bool                        `mapstructure:"enabled"`
+	Algorithm             string                      `mapstructure:"algorithm"`
+	Secret                string                      `mapstructure:"secret"`
+	PrivateKeyFile        string                      `mapstructure:"privateKeyFile"`
+	PublicKeyFile         string                      `mapstructure:"publicKeyFile"`
+	JWKSFile              string                      `mapstructure:"jwksFile"`
+	KeyID                 string                      `mapstructure:"keyId"`
+	Issuer                string                      `mapstructure:"issuer"`
+	Audience              string                      `mapstructure:"audience"`
+	AccessTokenTTLSeconds int                         `mapstructure:"accessTokenTtlSeconds"`
+	ClockSkewSeconds      int                         `mapstructure:"clockSkewSeconds"`
+	PasswordPolicy        PasswordPolicyConfiguration `mapstructure:"passwordPolicy"`
+}
+
+type PasswordPolicyConfiguration struct {
+	MinLength     int  `mapstructure:"minLength"`
+	RequireUpper  bool `mapstructure:"requireUpper"`
+	RequireLower  bool `mapstructure:"requireLower"`
+	RequireDigit  bool `mapstructure:"requireDigit"`
+	RequireSymbol bool `mapstructure:"requireSymbol"`
+}
+
+type AvatarConfiguration struct {
+	Directory          string `mapstructure:"directory"`
+	MaxSizeBytes       int    `mapstructure:"maxSizeBytes"`
+	MaxDimension       int    `mapstructure:"maxDimension"`
+	Sizes              []int  `mapstructure:"sizes"`
+	CacheMaxAgeSeconds int    `mapstructure:"cacheMaxAgeSeconds"`
+}
+
+type DuplicateDetectionConfiguration struct {
+	// IntervalSeconds is how often the report is refreshed in background, at least every 300 seconds
+	IntervalSeconds int `mapstructure:"intervalSeconds"`
+	// NameSimilarityThreshold is the minimum similarity, from 0 to 1, of duplicate names. Without threshold, names are not compared
+	NameSimilarityThreshold float64 `mapstructure:"nameSimilarityThreshold"`
+}
+
+type UserAttributesConfiguration struct {
+	Definitions map[string]UserAttributeDefinition `mapstructure:"definitions"`
+}
+
+type UserAttributeDefinition struct {
+	Type     string `mapstructure:"type"`
+	Required bool   `mapstructure:"required"`
+	// Values are the allowed values of enum attributes
+	Values []string `mapstructure:"values"`
+}
+
+type AuthorizationConfiguration struct {
+	Enabled        bool                `mapstructure:"enabled"`
+	IdentitySource string              `mapstructure:"identitySource"`
+	SubjectHeader  string              `mapstructure:"subjectHeader"`
+	RolesHeader    string              `mapstructure:"rolesHeader"`
+	Roles          map[string][]string `mapstructure:"roles"`
 }
@@ -0,0 +1,42 @@
+package domain
+
+import "time"
+
+// Group organizes users, for example in teams. Deleted groups are kept inactive
+type Group struct {
+	GenericEntity
+	Name        string
+	Description string
+	Members     []GroupMember
+}
+
+// GroupMember is an user that belongs to a group
+type GroupMember struct {
+	UserReference string
+	AddedDate     time.Time
+}
+
+// HasMember returns true when the user belongs to the group
+func (g Group) HasMember(userReference string) bool {
+	for _, member := range g.Members {
+		if member.UserReference == userReference {
+			return true
+		}
+	}
+	return false
+}
+
+type GroupCreateInput struct {
+	Name        string
+	Description string
+}
+
+type GroupUpdateInput struct {
+	GroupCreateInput
+	Reference string
+}
+
+type GroupMemberInput struct {
+	GroupReference string
+	UserReference  string
+}
@@ -0,0 +1,26 @@
+package domain
+
+import "time"
+
+const (
+	IdempotencyStatusProcessing = "processing"
+	IdempotencyStatusCompleted  = "completed"
+)
+
+// IdempotencyRecord keeps the first response to a request with an idempotency key, to replay it for the request retries.
+// While the request is processed the record locks the key, until it is completed or the lock expires
+type IdempotencyRecord struct {
+	// Key is the idempotency key, scoped to the caller
+	Key string
+	// Fingerprint identifies the request, so the key can not be reused with another request
+	Fingerprint     string
+	Status          string
+	ResponseStatus  int
+	ResponseHeaders map[string][]string
+	ResponseBody    []byte
+	// ResourceReference is the user or group the response is about, so the response is removed when its user is erased
+	ResourceReference string
+	LockedUntil       time.Time
+	CreatedDate       time.Time
+	ExpiresDate       time.Time
+}
@@ -1,16 +1,100 @@
 package domain
 
+import "time"
+
+type UserStatus string
+
+const (
+	UserStatusPending   UserStatus = "pending"
+	UserStatusActive    UserStatus = "active"
+	UserStatusSuspended UserStatus = "suspended"
+	UserStatusDeleted   UserStatus = "deleted"
+)
+
+const (
+	UserAttributeTypeString = "string"
+	UserAttributeTypeNumber = "number"
+	UserAttributeTypeBool   = "bool"
+	UserAttributeTypeDate   = "date"
+	UserAttributeTypeEnum   = "enum"
+)
+
+// ResolveUserStatus returns the user status. Users stored without status are active or deleted, depending on their active mark
+func ResolveUserStatus(status UserStatus, isActive bool) UserStatus {
+	if len(status) > 0 {
+		return status
+	}
+	if isActive {
+		return UserStatusActive
+	}
+
+	return UserStatusDeleted
+}
+
 type User struct {
 	GenericEntity
+	UserProfile
 	FirstName string
 	LastName  string
 	Email     string
+	// EmailVerifiedDate is set when the user confirms its email, and cleared when the email changes
+	EmailVerifiedDate         *time.Time
+	EmailVerificationSentDate *time.Time
+	// Roles grant the user permissions through the authorization configuration
+	Roles []string
+	// Attributes are the custom fields declared in the attributes configuration
+	Attributes map[string]interface{}
+	// Tags are normalized labels, sorted and without duplicates
+	Tags []string
+	// ExternalIDs are the user ids in other systems. Each pair belongs to a single user
+	ExternalIDs []UserExternalID
+	// Avatar is set when the user uploads an avatar image
+	Avatar       *UserAvatar
+	Status       UserStatus
+	StatusReason string
+	StatusDate   time.Time
+	ErasedDate   *time.Time
+	// MergedInto is the reference of the user that a merged duplicate was folded into
+	MergedInto string
+	// Undecryptable is set when the stored personal data could not be decrypted. Its personal data is blank,
+	// so the user can not be stored again without losing it
+	Undecryptable bool
+}
+
+// UserProfile holds the optional user profile data
+type UserProfile struct {
+	Phone     string
+	BirthDate *time.Time
+	Locale    string
+	Timezone  string
+	Address   *Address
+}
+
+// UserAvatar describes the stored avatar thumbnails. The version changes when a different image is uploaded
+type UserAvatar struct {
+	Version     string
+	ContentType string
+	Sizes       []int
+	UpdatedDate time.Time
+}
+
+type Address struct {
+	Line1      string
+	Line2      string
+	City       string
+	Region     string
+	PostalCode string
+	Country    string
 }
 
 type UserCreateInput struct {
-	FirstName string
-	LastName  string
-	Email     string
+	UserProfile
+	FirstName   string
+	LastName    string
+	Email       string
+	Attributes  map[string]interface{}
+	Tags        []string
+	ExternalIDs []UserExternalID
 }
 
 type UserUpdateInput struct {
@@ -18,14 +102,103 @@ type UserUpdateInput struct {
 	Reference string
 }
 
+// UserPatchFunc applies a patch to the current user data and returns the patched data
+type UserPatchFunc func(current UserCreateInput) (UserCreateInput, error)
+
+type UserPatchInput struct {
+	Reference string
+	Apply     UserPatchFunc
+}
+
+// UserExternalID is the id of an user in another system, the source
+type UserExternalID struct {
+	Source string
+	ID     string
+}
+
+type UserAvatarInput struct {
+	Reference string
+	Data      []byte
+}
+
+// UserAvatarImage is an avatar thumbnail
+type UserAvatarImage struct {
+	Data        []byte
+	ContentType string
+	Version     string
+	Size        int
+	UpdatedDate time.Time
+}
+
+type UserTagInput struct {
+	Reference string
+	Tag       string
+}
+
+// TagCount is the number of active users with a tag
+type TagCount struct {
+	Tag   string
+	Count int64
+}
+
+type UserMergeInput struct {
+	// Reference is the surviving user
+	Reference          string
+	DuplicateReference string
+	MergedBy           string
+}
+
+const (
+	UserDuplicateReasonEmail = "email"
+	UserDuplicateReasonName  = "name"
+)
+
+// UserDuplicateGroup is a group of users that may be the same person, oldest first
+type UserDuplicateGroup struct {
+	Reasons []string
+	Users   []User
+}
+
+type UserDuplicateReport struct {
+	GeneratedDate time.Time
+	Groups        []UserDuplicateGroup
+}
+
+type UserStatusChangeInput struct {
+	Reference string
+	Status    UserStatus
+	Reason    string
+}
+
 type UserSearchInput struct {
 	SearchInput
 	FirstName string
 	LastName  string
 	Email     string
+	Country   string
+	Locale    string
+	// Attributes filters the users by custom attribute values. Text values are parsed to the attribute type
+	Attributes map[string]interface{}
+	// Tags filters the users that have all the tags
+	Tags []string
+	// AnyTags filters the users that have at least one of the tags
+	AnyTags []string
+	// IncludeInactive also returns the inactive users. Only administrators can request it
+	IncludeInactive bool
 }
 
 type UserSearchOutput struct {
 	SearchOutput
 	Users []User
 }
+
+type UserDataExport struct {
+	Reference     string
+	GeneratedDate time.Time
+	Sections      []UserDataExportSection
+}
+
+type UserDataExportSection struct {
+	Name    string
+	Records []map[string]interface{}
+}
@@ -0,0 +1,66 @@
+package group
+
+import (
+	"time"
+
+	"github.com/desarrollogj/golang-api-example/domain"
+	"github.com/desarrollogj/golang-api-example/infrastructure"
+	"github.com/desarrollogj/golang-api-example/libs/errors"
+	"github.com/desarrollogj/golang-api-example/libs/logger"
+)
+
+// AddMember represents the method to be implemented to add an user to a group
+type AddMember interface {
+	Execute(input domain.GroupMemberInput) (domain.Group, error)
+}
+
+// defaultAddMember is the default implementation of AddMember interface
+type defaultAddMember struct {
+	repository     infrastructure.GroupRepository
+	userRepository infrastructure.UserRepository
+}
+
+// NewDefaultAddMember creates a defaultAddMember instance
+func NewDefaultAddMember(repository infrastructure.GroupRepository, userRepository infrastructure.UserRepository) defaultAddMember {
+	return defaultAddMember{
+		repository:     repository,
+		userRepository: userRepository,
+	}
+}
+
+// Execute add an active User to a Group. Adding a current member returns the group without changes. The member is added
+// atomically, so concurrent membership changes are kept
+func (s defaultAddMember) Execute(input domain.GroupMemberInput) (domain.Group, error) {
+	group, err := findActiveGroup(s.repository, input.GroupReference)
+	if err != nil {
+		return domain.Group{}, err
+	}
+	if group.HasMember(input.UserReference) {
+		return group, nil
+	}
+
+	if _, err := findActiveUser(s.userRepository, input.UserReference); err != nil {
+		return domain.Group{}, err
+	}
+
+	added := time.Now().UTC()
+	member := domain.GroupMember{
+		UserReference: input.UserReference,
+		AddedDate:     added,
+	}
+	ok, err := s.repository.AddMember(group.Reference, member)
+	if err != nil {
+		errMsg := "unexpected error when add the group member"
+		logger.AppLog.Error().Err(err).Msg(errMsg)
+		return domain.Group{}, errors.NewFatalError(errMsg)
+	}
+	// The user was added or the group was deleted meanwhile
+	if !ok {
+		return findActiveGroup(s.repository, input.GroupReference)
+	}
+
+	group.Members = append(group.Members, member)
+	group.UpdatedDate = added
+
+	return group, nil
+}
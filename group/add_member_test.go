@@ -0,0 +1,115 @@
+package group
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/desarrollogj/golang-api-example/domain"
+	"github.com/desarrollogj/golang-api-example/infrastructure"
+	"github.com/desarrollogj/golang-api-example/infrastructure/usertest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newUserRepositoryWithUser(reference string, isActive bool) infrastructure.UserRepository {
+	repository := usertest.NewInMemoryUserRepository()
+	repository.Create(domain.User{GenericEntity: domain.GenericEntity{Reference: reference, IsActive: isActive}})
+	return repository
+}
+
+func TestAddMember_GivenAnActiveUser_WhenExecute_ThenAddItToTheGroup(t *testing.T) {
+	t.Log("Successfully add a member to a Group")
+
+	current := domain.Group{GenericEntity: domain.GenericEntity{Reference: "GROUP1", IsActive: true}}
+	repositoryMock := new(repositoryMock)
+	repositoryMock.On("FindActiveByReference", "GROUP1").Return(current, nil)
+	repositoryMock.On("AddMember", "GROUP1", mock.MatchedBy(func(member domain.GroupMember) bool {
+		return member.UserReference == "USER1" && !member.AddedDate.IsZero()
+	})).Return(true, nil)
+
+	useCase := NewDefaultAddMember(repositoryMock, newUserRepositoryWithUser("USER1", true))
+
+	group, err := useCase.Execute(domain.GroupMemberInput{GroupReference: "GROUP1", UserReference: "USER1"})
+
+	assert.Nil(t, err)
+	assert.True(t, group.HasMember("USER1"))
+	repositoryMock.AssertNotCalled(t, "Update", mock.Anything)
+
+	repositoryMock.AssertExpectations(t)
+}
+
+func TestAddMember_GivenACurrentMember_WhenExecute_ThenReturnTheGroupWithoutChanges(t *testing.T) {
+	t.Log("Add a current member to a Group has no effect")
+
+	current := domain.Group{
+		GenericEntity: domain.GenericEntity{Reference: "GROUP1", IsActive: true},
+		Members:       []domain.GroupMember{{UserReference: "USER1"}},
+	}
+	repositoryMock := new(repositoryMock)
+	repositoryMock.On("FindActiveByReference", "GROUP1").Return(current, nil)
+
+	useCase := NewDefaultAddMember(repositoryMock, newUserRepositoryWithUser("USER1", true))
+
+	group, err := useCase.Execute(domain.GroupMemberInput{GroupReference: "GROUP1", UserReference: "USER1"})
+
+	assert.Nil(t, err)
+	assert.Equal(t, current, group)
+
+	repositoryMock.AssertNotCalled(t, "AddMember", mock.Anything, mock.Anything)
+}
+
+func TestAddMember_GivenAnInactiveUser_WhenExecute_ThenReturnANotFoundError(t *testing.T) {
+	t.Log("Failure to add a member to a Group because the user was not found")
+
+	repositoryMock := new(repositoryMock)
+	repositoryMock.On("FindActiveByReference", "GROUP1").
+		Return(domain.Group{GenericEntity: domain.GenericEntity{Reference: "GROUP1", IsActive: true}}, nil)
+
+	useCase := NewDefaultAddMember(repositoryMock, newUserRepositoryWithUser("USER1", false))
+
+	_, err := useCase.Execute(domain.GroupMemberInput{GroupReference: "GROUP1", UserReference: "USER1"})
+
+	assert.NotNil(t, err)
+	assert.Equal(t, "user not found", err.Error())
+
+	repositoryMock.AssertNotCalled(t, "AddMember", mock.Anything, mock.Anything)
+}
+
+func TestAddMember_GivenAnActiveUser_WhenExecuteAndAddReturnedAnError_ThenReturnAFatalError(t *testing.T) {
+	t.Log("Failure to add a member to a Group because the repository returned an unexpected error")
+
+	repositoryMock := new(repositoryMock)
+	repositoryMock.On("FindActiveByReference", "GROUP1").
+		Return(domain.Group{GenericEntity: domain.GenericEntity{Reference: "GROUP1", IsActive: true}}, nil)
+	repositoryMock.On("AddMember", "GROUP1", mock.AnythingOfType("GroupMember")).Return(false, errors.New("repository error"))
+
+	useCase := NewDefaultAddMember(repositoryMock, newUserRepositoryWithUser("USER1", true))
+
+	_, err := useCase.Execute(domain.GroupMemberInput{GroupReference: "GROUP1", UserReference: "USER1"})
+
+	assert.NotNil(t, err)
+	assert.Equal(t, "unexpected error when add the group member", err.Error())
+}
+
+func TestAddMember_GivenAnUserAddedConcurrently_WhenExecute_ThenReturnTheCurrentGroup(t *testing.T) {
+	t.Log("Add a member that was added meanwhile to a Group returns the stored group")
+
+	current := domain.Group{GenericEntity: domain.GenericEntity{Reference: "GROUP1", IsActive: true}}
+	stored := domain.Group{
+		GenericEntity: domain.GenericEntity{Reference: "GROUP1", IsActive: true},
+		Members:       []domain.GroupMember{{UserReference: "USER2"}, {UserReference: "USER1"}},
+	}
+	repositoryMock := new(repositoryMock)
+	repositoryMock.On("FindActiveByReference", "GROUP1").Return(current, nil).Once()
+	repositoryMock.On("FindActiveByReference", "GROUP1").Return(stored, nil).Once()
+	repositoryMock.On("AddMember", "GROUP1", mock.AnythingOfType("GroupMember")).Return(false, nil)
+
+	useCase := NewDefaultAddMember(repositoryMock, newUserRepositoryWithUser("USER1", true))
+
+	group, err := useCase.Execute(domain.GroupMemberInput{GroupReference: "GROUP1", UserReference: "USER1"})
+
+	assert.Nil(t, err)
+	assert.Equal(t, stored, group)
+
+	repositoryMock.AssertExpectations(t)
+}
@@ -0,0 +1,52 @@
+package group
+
+import (
+	"time"
+
+	"github.com/desarrollogj/golang-api-example/domain"
+	"github.com/desarrollogj/golang-api-example/infrastructure"
+	"github.com/desarrollogj/golang-api-example/libs/errors"
+	"github.com/desarrollogj/golang-api-example/libs/logger"
+	"github.com/google/uuid"
+)
+
+// Create represents the method to be implemented to create a group
+type Create interface {
+	Execute(input domain.GroupCreateInput) (domain.Group, error)
+}
+
+// defaultCreate is the default implementation of Create interface
+type defaultCreate struct {
+	repository infrastructure.GroupRepository
+}
+
+// NewDefaultCreate creates a defaultCreate instance
+func NewDefaultCreate(repository infrastructure.GroupRepository) defaultCreate {
+	return defaultCreate{
+		repository: repository,
+	}
+}
+
+// Execute create a Group without members
+func (s defaultCreate) Execute(input domain.GroupCreateInput) (domain.Group, error) {
+	created := time.Now().UTC()
+	group := domain.Group{
+		GenericEntity: domain.GenericEntity{
+			Reference:   uuid.NewString(),
+			IsActive:    true,
+			CreatedDate: created,
+			UpdatedDate: created,
+		},
+		Name:        input.Name,
+		Description: input.Description,
+	}
+
+	group, err := s.repository.Create(group)
+	if err != nil {
+		errMsg := "unexpected error when create the group"
+		logger.AppLog.Error().Err(err).Msg(errMsg)
+		return domain.Group{}, errors.NewFatalError(errMsg)
+	}
+
+	return group, nil
+}
@@ -0,0 +1,43 @@
+package group
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/desarrollogj/golang-api-example/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCreate_GivenAnInput_WhenExecute_ThenCreateAGroup(t *testing.T) {
+	t.Log("Successfully create a Group")
+
+	repositoryMock := new(repositoryMock)
+	repositoryMock.On("Create", mock.MatchedBy(func(group domain.Group) bool {
+		return len(group.Reference) > 0 && group.IsActive && group.Name == "Backend" &&
+			group.Description == "Backend team" && len(group.Members) == 0
+	})).Return(domain.Group{Name: "Backend"}, nil)
+
+	useCase := NewDefaultCreate(repositoryMock)
+
+	created, err := useCase.Execute(domain.GroupCreateInput{Name: "Backend", Description: "Backend team"})
+
+	assert.Nil(t, err)
+	assert.Equal(t, "Backend", created.Name)
+
+	repositoryMock.AssertExpectations(t)
+}
+
+func TestCreate_GivenAnInput_WhenExecuteAndCreateReturnedAnError_ThenReturnAFatalError(t *testing.T) {
+	t.Log("Failure to create a Group because create returned an unexpected error")
+
+	repositoryMock := new(repositoryMock)
+	repositoryMock.On("Create", mock.AnythingOfType("Group")).Return(domain.Group{}, errors.New("repository error"))
+
+	useCase := NewDefaultCreate(repositoryMock)
+
+	_, err := useCase.Execute(domain.GroupCreateInput{Name: "Backend"})
+
+	assert.NotNil(t, err)
+	assert.Equal(t, "unexpected error when create the group", err.Error())
+}
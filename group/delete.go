@@ -0,0 +1,47 @@
+package group
+
+import (
+	"time"
+
+	"github.com/desarrollogj/golang-api-example/domain"
+	"github.com/desarrollogj/golang-api-example/infrastructure"
+	"github.com/desarrollogj/golang-api-example/libs/errors"
+	"github.com/desarrollogj/golang-api-example/libs/logger"
+)
+
+// Delete represents the method to be implemented to delete (inactive) a group
+type Delete interface {
+	Execute(reference string) (domain.Group, error)
+}
+
+// defaultDelete is the default implementation of Delete interface
+type defaultDelete struct {
+	repository infrastructure.GroupRepository
+}
+
+// NewDefaultDelete creates a defaultDelete instance
+func NewDefaultDelete(repository infrastructure.GroupRepository) defaultDelete {
+	return defaultDelete{
+		repository: repository,
+	}
+}
+
+// Execute delete a Group
+func (s defaultDelete) Execute(reference string) (domain.Group, error) {
+	group, err := findActiveGroup(s.repository, reference)
+	if err != nil {
+		return domain.Group{}, err
+	}
+
+	group.IsActive = false
+	group.UpdatedDate = time.Now().UTC()
+
+	deleted, err := s.repository.Update(group)
+	if err != nil {
+		errMsg := "unexpected error when delete the group"
+		logger.AppLog.Error().Err(err).Msg(errMsg)
+		return domain.Group{}, errors.NewFatalError(errMsg)
+	}
+
+	return deleted, nil
+}
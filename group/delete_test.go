@@ -0,0 +1,60 @@
+package group
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/desarrollogj/golang-api-example/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestDelete_GivenAReference_WhenExecute_ThenInactiveTheGroup(t *testing.T) {
+	t.Log("Successfully delete a Group")
+
+	current := domain.Group{GenericEntity: domain.GenericEntity{Reference: "GROUP1", IsActive: true}}
+	repositoryMock := new(repositoryMock)
+	repositoryMock.On("FindActiveByReference", "GROUP1").Return(current, nil)
+	repositoryMock.On("Update", mock.MatchedBy(func(group domain.Group) bool {
+		return !group.IsActive && !group.UpdatedDate.IsZero()
+	})).Return(domain.Group{GenericEntity: domain.GenericEntity{Reference: "GROUP1"}}, nil)
+
+	useCase := NewDefaultDelete(repositoryMock)
+
+	deleted, err := useCase.Execute("GROUP1")
+
+	assert.Nil(t, err)
+	assert.False(t, deleted.IsActive)
+
+	repositoryMock.AssertExpectations(t)
+}
+
+func TestDelete_GivenAReference_WhenExecuteAndGroupNotFound_ThenReturnANotFoundError(t *testing.T) {
+	t.Log("Failure to delete a Group because it was not found")
+
+	repositoryMock := new(repositoryMock)
+	repositoryMock.On("FindActiveByReference", "GROUP1").Return(domain.Group{}, nil)
+
+	useCase := NewDefaultDelete(repositoryMock)
+
+	_, err := useCase.Execute("GROUP1")
+
+	assert.NotNil(t, err)
+	assert.Equal(t, "group not found", err.Error())
+}
+
+func TestDelete_GivenAReference_WhenExecuteAndUpdateReturnedAnError_ThenReturnAFatalError(t *testing.T) {
+	t.Log("Failure to delete a Group because update returned an unexpected error")
+
+	repositoryMock := new(repositoryMock)
+	repositoryMock.On("FindActiveByReference", "GROUP1").
+		Return(domain.Group{GenericEntity: domain.GenericEntity{Reference: "GROUP1", IsActive: true}}, nil)
+	repositoryMock.On("Update", mock.AnythingOfType("Group")).Return(domain.Group{}, errors.New("repository error"))
+
+	useCase := NewDefaultDelete(repositoryMock)
+
+	_, err := useCase.Execute("GROUP1")
+
+	assert.NotNil(t, err)
+	assert.Equal(t, "unexpected error when delete the group", err.Error())
+}
@@ -0,0 +1,36 @@
+package group
+
+import (
+	"github.com/desarrollogj/golang-api-example/domain"
+	"github.com/desarrollogj/golang-api-example/infrastructure"
+	"github.com/desarrollogj/golang-api-example/libs/errors"
+	"github.com/desarrollogj/golang-api-example/libs/logger"
+)
+
+// FindAll represents the method to be implemented to get all groups
+type FindAll interface {
+	Execute() ([]domain.Group, error)
+}
+
+// defaultFindAll is the default implementation of FindAll interface
+type defaultFindAll struct {
+	repository infrastructure.GroupRepository
+}
+
+// NewDefaultFindAll creates a defaultFindAll instance
+func NewDefaultFindAll(repository infrastructure.GroupRepository) defaultFindAll {
+	return defaultFindAll{
+		repository: repository,
+	}
+}
+
+// Execute get all active groups
+func (s defaultFindAll) Execute() ([]domain.Group, error) {
+	groups, err := s.repository.FindAllActive()
+	if err != nil {
+		errMsg := "unexpected error when find all groups"
+		logger.AppLog.Error().Err(err).Msg(errMsg)
+		return []domain.Group{}, errors.NewFatalError(errMsg)
+	}
+	return groups, nil
+}
@@ -0,0 +1,38 @@
+package group
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/desarrollogj/golang-api-example/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindAll_WhenExecute_ThenReturnAllGroups(t *testing.T) {
+	t.Log("Successfully find all Groups")
+
+	groups := []domain.Group{{Name: "Backend"}, {Name: "Frontend"}}
+	repositoryMock := new(repositoryMock)
+	repositoryMock.On("FindAllActive").Return(groups, nil)
+
+	useCase := NewDefaultFindAll(repositoryMock)
+
+	result, err := useCase.Execute()
+
+	assert.Nil(t, err)
+	assert.Equal(t, groups, result)
+}
+
+func TestFindAll_WhenExecuteAndFindReturnedAnError_ThenReturnAFatalError(t *testing.T) {
+	t.Log("Failure to find all Groups because find returned an unexpected error")
+
+	repositoryMock := new(repositoryMock)
+	repositoryMock.On("FindAllActive").Return([]domain.Group{}, errors.New("repository error"))
+
+	useCase := NewDefaultFindAll(repositoryMock)
+
+	_, err := useCase.Execute()
+
+	assert.NotNil(t, err)
+	assert.Equal(t, "unexpected error when find all groups", err.Error())
+}
@@ -0,0 +1,43 @@
+package group
+
+import (
+	"github.com/desarrollogj/golang-api-example/domain"
+	"github.com/desarrollogj/golang-api-example/infrastructure"
+	"github.com/desarrollogj/golang-api-example/libs/errors"
+	"github.com/desarrollogj/golang-api-example/libs/logger"
+)
+
+// FindByMember represents the method to be implemented to get the groups of an user
+type FindByMember interface {
+	Execute(userReference string) ([]domain.Group, error)
+}
+
+// defaultFindByMember is the default implementation of FindByMember interface
+type defaultFindByMember struct {
+	repository     infrastructure.GroupRepository
+	userRepository infrastructure.UserRepository
+}
+
+// NewDefaultFindByMember creates a defaultFindByMember instance
+func NewDefaultFindByMember(repository infrastructure.GroupRepository, userRepository infrastructure.UserRepository) defaultFindByMember {
+	return defaultFindByMember{
+		repository:     repository,
+		userRepository: userRepository,
+	}
+}
+
+// Execute get the active groups of an active User
+func (s defaultFindByMember) Execute(userReference string) ([]domain.Group, error) {
+	if _, err := findActiveUser(s.userRepository, userReference); err != nil {
+		return []domain.Group{}, err
+	}
+
+	groups, err := s.repository.FindActiveByMember(userReference)
+	if err != nil {
+		errMsg := "unexpected error when find the user groups"
+		logger.AppLog.Error().Err(err).Msg(errMsg)
+		return []domain.Group{}, errors.NewFatalError(errMsg)
+	}
+
+	return groups, nil
+}
@@ -0,0 +1,54 @@
+package group
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/desarrollogj/golang-api-example/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestFindByMember_GivenAnActiveUser_WhenExecute_ThenReturnItsGroups(t *testing.T) {
+	t.Log("Successfully find the Groups of an user")
+
+	groups := []domain.Group{{Name: "Backend"}}
+	repositoryMock := new(repositoryMock)
+	repositoryMock.On("FindActiveByMember", "USER1").Return(groups, nil)
+
+	useCase := NewDefaultFindByMember(repositoryMock, newUserRepositoryWithUser("USER1", true))
+
+	result, err := useCase.Execute("USER1")
+
+	assert.Nil(t, err)
+	assert.Equal(t, groups, result)
+}
+
+func TestFindByMember_GivenAnUnknownUser_WhenExecute_ThenReturnANotFoundError(t *testing.T) {
+	t.Log("Failure to find the Groups of an user because the user was not found")
+
+	repositoryMock := new(repositoryMock)
+
+	useCase := NewDefaultFindByMember(repositoryMock, newUserRepositoryWithUser("USER1", true))
+
+	_, err := useCase.Execute("USER2")
+
+	assert.NotNil(t, err)
+	assert.Equal(t, "user not found", err.Error())
+
+	repositoryMock.AssertNotCalled(t, "FindActiveByMember", mock.Anything)
+}
+
+func TestFindByMember_GivenAnActiveUser_WhenExecuteAndFindReturnedAnError_ThenReturnAFatalError(t *testing.T) {
+	t.Log("Failure to find the Groups of an user because find returned an unexpected error")
+
+	repositoryMock := new(repositoryMock)
+	repositoryMock.On("FindActiveByMember", "USER1").Return([]domain.Group{}, errors.New("repository error"))
+
+	useCase := NewDefaultFindByMember(repositoryMock, newUserRepositoryWithUser("USER1", true))
+
+	_, err := useCase.Execute("USER1")
+
+	assert.NotNil(t, err)
+	assert.Equal(t, "unexpected error when find the user groups", err.Error())
+}
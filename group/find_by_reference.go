@@ -0,0 +1,62 @@
+package group
+
+import (
+	"fmt"
+
+	"github.com/desarrollogj/golang-api-example/domain"
+	"github.com/desarrollogj/golang-api-example/infrastructure"
+	"github.com/desarrollogj/golang-api-example/libs/errors"
+	"github.com/desarrollogj/golang-api-example/libs/logger"
+)
+
+// FindByReference represents the method to be implemented to get a group by its reference
+type FindByReference interface {
+	Execute(reference string) (domain.Group, error)
+}
+
+// defaultFindByReference is the default implementation of FindByReference interface
+type defaultFindByReference struct {
+	repository infrastructure.GroupRepository
+}
+
+// NewDefaultFindByReference creates a defaultFindByReference instance
+func NewDefaultFindByReference(repository infrastructure.GroupRepository) defaultFindByReference {
+	return defaultFindByReference{
+		repository: repository,
+	}
+}
+
+// Execute get an active group by its reference
+func (s defaultFindByReference) Execute(reference string) (domain.Group, error) {
+	return findActiveGroup(s.repository, reference)
+}
+
+// findActiveGroup returns the active group, or a not found error when it does not exist
+func findActiveGroup(repository infrastructure.GroupRepository, reference string) (domain.Group, error) {
+	group, err := repository.FindActiveByReference(reference)
+	if err != nil {
+		errMsg := fmt.Sprintf("unexpected error when try to get group with reference %s", reference)
+		logger.AppLog.Error().Err(err).Msg(errMsg)
+		return domain.Group{}, errors.NewFatalError(errMsg)
+	}
+	if len(group.Reference) == 0 {
+		return domain.Group{}, errors.NewNotFoundError("group not found").WithKey("group_not_found")
+	}
+
+	return group, nil
+}
+
+// findActiveUser returns the active user, or a not found error when it does not exist
+func findActiveUser(repository infrastructure.UserRepository, reference string) (domain.User, error) {
+	user, err := repository.FindActiveByReference(reference)
+	if err != nil {
+		errMsg := fmt.Sprintf("unexpected error when try to get user with reference %s", reference)
+		logger.AppLog.Error().Err(err).Msg(errMsg)
+		return domain.User{}, errors.NewFatalError(errMsg)
+	}
+	if len(user.Reference) == 0 {
+		return domain.User{}, errors.NewNotFoundError("user not found").WithKey("user_not_found")
+	}
+
+	return user, nil
+}
@@ -0,0 +1,52 @@
+package group
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/desarrollogj/golang-api-example/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindByReference_GivenAReference_WhenExecute_ThenReturnTheGroup(t *testing.T) {
+	t.Log("Successfully find a Group by its reference")
+
+	group := domain.Group{GenericEntity: domain.GenericEntity{Reference: "GROUP1", IsActive: true}, Name: "Backend"}
+	repositoryMock := new(repositoryMock)
+	repositoryMock.On("FindActiveByReference", "GROUP1").Return(group, nil)
+
+	useCase := NewDefaultFindByReference(repositoryMock)
+
+	result, err := useCase.Execute("GROUP1")
+
+	assert.Nil(t, err)
+	assert.Equal(t, group, result)
+}
+
+func TestFindByReference_GivenAReference_WhenExecuteAndGroupNotFound_ThenReturnANotFoundError(t *testing.T) {
+	t.Log("Failure to find a Group by its reference because it was not found")
+
+	repositoryMock := new(repositoryMock)
+	repositoryMock.On("FindActiveByReference", "GROUP1").Return(domain.Group{}, nil)
+
+	useCase := NewDefaultFindByReference(repositoryMock)
+
+	_, err := useCase.Execute("GROUP1")
+
+	assert.NotNil(t, err)
+	assert.Equal(t, "group not found", err.Error())
+}
+
+func TestFindByReference_GivenAReference_WhenExecuteAndFindReturnedAnError_ThenReturnAFatalError(t *testing.T) {
+	t.Log("Failure to find a Group by its reference because find returned an unexpected error")
+
+	repositoryMock := new(repositoryMock)
+	repositoryMock.On("FindActiveByReference", "GROUP1").Return(domain.Group{}, errors.New("repository error"))
+
+	useCase := NewDefaultFindByReference(repositoryMock)
+
+	_, err := useCase.Execute("GROUP1")
+
+	assert.NotNil(t, err)
+	assert.Equal(t, "unexpected error when try to get group with reference GROUP1", err.Error())
+}
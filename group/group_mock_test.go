@@ -0,0 +1,111 @@
+package group
+
+import (
+	"errors"
+	"time"
+
+	"github.com/desarrollogj/golang-api-example/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+type repositoryMock struct {
+	mock.Mock
+}
+
+func (m *repositoryMock) FindAllActive() ([]domain.Group, error) {
+	args := m.Called()
+
+	groups, ok := args.Get(0).([]domain.Group)
+	if !ok {
+		return []domain.Group{}, errors.New("mock error")
+	}
+
+	return groups, args.Error(1)
+}
+
+func (m *repositoryMock) FindActiveByReference(reference string) (domain.Group, error) {
+	args := m.Called(reference)
+
+	group, ok := args.Get(0).(domain.Group)
+	if !ok {
+		return domain.Group{}, errors.New("mock error")
+	}
+
+	return group, args.Error(1)
+}
+
+func (m *repositoryMock) FindActiveByMember(userReference string) ([]domain.Group, error) {
+	args := m.Called(userReference)
+
+	groups, ok := args.Get(0).([]domain.Group)
+	if !ok {
+		return []domain.Group{}, errors.New("mock error")
+	}
+
+	return groups, args.Error(1)
+}
+
+func (m *repositoryMock) FindByMember(userReference string) ([]domain.Group, error) {
+	args := m.Called(userReference)
+
+	groups, ok := args.Get(0).([]domain.Group)
+	if !ok {
+		return []domain.Group{}, errors.New("mock error")
+	}
+
+	return groups, args.Error(1)
+}
+
+func (m *repositoryMock) Create(group domain.Group) (domain.Group, error) {
+	args := m.Called(group)
+
+	group, ok := args.Get(0).(domain.Group)
+	if !ok {
+		return domain.Group{}, errors.New("mock error")
+	}
+
+	return group, args.Error(1)
+}
+
+func (m *repositoryMock) Update(group domain.Group) (domain.Group, error) {
+	args := m.Called(group)
+
+	group, ok := args.Get(0).(domain.Group)
+	if !ok {
+		return domain.Group{}, errors.New("mock error")
+	}
+
+	return group, args.Error(1)
+}
+
+func (m *repositoryMock) AddMember(groupReference string, member domain.GroupMember) (bool, error) {
+	args := m.Called(groupReference, member)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *repositoryMock) RemoveMember(groupReference string, userReference string, removedDate time.Time) (bool, error) {
+	args := m.Called(groupReference, userReference, removedDate)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *repositoryMock) RemoveMemberFromAll(userReference string) (int64, error) {
+	args := m.Called(userReference)
+
+	removed, ok := args.Get(0).(int64)
+	if !ok {
+		return 0, errors.New("mock error")
+	}
+
+	return removed, args.Error(1)
+}
+
+func (m *repositoryMock) MoveMember(userReference string, toUserReference string, movedDate time.Time) (int64, error) {
+	args := m.Called(userReference, toUserReference, movedDate)
+
+	moved, ok := args.Get(0).(int64)
+	if !ok {
+		return 0, errors.New("mock error")
+	}
+
+	return moved, args.Error(1)
+}
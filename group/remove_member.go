@@ -0,0 +1,61 @@
+package group
+
+import (
+	"time"
+
+	"github.com/desarrollogj/golang-api-example/domain"
+	"github.com/desarrollogj/golang-api-example/infrastructure"
+	"github.com/desarrollogj/golang-api-example/libs/errors"
+	"github.com/desarrollogj/golang-api-example/libs/logger"
+)
+
+// RemoveMember represents the method to be implemented to remove an user from a group
+type RemoveMember interface {
+	Execute(input domain.GroupMemberInput) (domain.Group, error)
+}
+
+// defaultRemoveMember is the default implementation of RemoveMember interface
+type defaultRemoveMember struct {
+	repository infrastructure.GroupRepository
+}
+
+// NewDefaultRemoveMember creates a defaultRemoveMember instance
+func NewDefaultRemoveMember(repository infrastructure.GroupRepository) defaultRemoveMember {
+	return defaultRemoveMember{
+		repository: repository,
+	}
+}
+
+// Execute remove an User from a Group. The member is removed atomically, so concurrent membership changes are kept
+func (s defaultRemoveMember) Execute(input domain.GroupMemberInput) (domain.Group, error) {
+	group, err := findActiveGroup(s.repository, input.GroupReference)
+	if err != nil {
+		return domain.Group{}, err
+	}
+	if !group.HasMember(input.UserReference) {
+		return domain.Group{}, errors.NewNotFoundError("group member not found").WithKey("group_member_not_found")
+	}
+
+	removed := time.Now().UTC()
+	ok, err := s.repository.RemoveMember(group.Reference, input.UserReference, removed)
+	if err != nil {
+		errMsg := "unexpected error when remove the group member"
+		logger.AppLog.Error().Err(err).Msg(errMsg)
+		return domain.Group{}, errors.NewFatalError(errMsg)
+	}
+	// The user was removed or the group was deleted meanwhile
+	if !ok {
+		return domain.Group{}, errors.NewNotFoundError("group member not found").WithKey("group_member_not_found")
+	}
+
+	members := []domain.GroupMember{}
+	for _, member := range group.Members {
+		if member.UserReference != input.UserReference {
+			members = append(members, member)
+		}
+	}
+	group.Members = members
+	group.UpdatedDate = removed
+
+	return group, nil
+}
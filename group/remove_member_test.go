@@ -0,0 +1,69 @@
+package group
+
+import (
+	"testing"
+
+	"github.com/desarrollogj/golang-api-example/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestRemoveMember_GivenAMember_WhenExecute_ThenRemoveItFromTheGroup(t *testing.T) {
+	t.Log("Successfully remove a member from a Group")
+
+	current := domain.Group{
+		GenericEntity: domain.GenericEntity{Reference: "GROUP1", IsActive: true},
+		Members:       []domain.GroupMember{{UserReference: "USER1"}, {UserReference: "USER2"}},
+	}
+	repositoryMock := new(repositoryMock)
+	repositoryMock.On("FindActiveByReference", "GROUP1").Return(current, nil)
+	repositoryMock.On("RemoveMember", "GROUP1", "USER1", mock.AnythingOfType("time.Time")).Return(true, nil)
+
+	useCase := NewDefaultRemoveMember(repositoryMock)
+
+	group, err := useCase.Execute(domain.GroupMemberInput{GroupReference: "GROUP1", UserReference: "USER1"})
+
+	assert.Nil(t, err)
+	assert.Len(t, group.Members, 1)
+	assert.True(t, group.HasMember("USER2"))
+	repositoryMock.AssertNotCalled(t, "Update", mock.Anything)
+
+	repositoryMock.AssertExpectations(t)
+}
+
+func TestRemoveMember_GivenAnUserThatIsNotAMember_WhenExecute_ThenReturnANotFoundError(t *testing.T) {
+	t.Log("Failure to remove a member from a Group because the user is not a member")
+
+	repositoryMock := new(repositoryMock)
+	repositoryMock.On("FindActiveByReference", "GROUP1").
+		Return(domain.Group{GenericEntity: domain.GenericEntity{Reference: "GROUP1", IsActive: true}}, nil)
+
+	useCase := NewDefaultRemoveMember(repositoryMock)
+
+	_, err := useCase.Execute(domain.GroupMemberInput{GroupReference: "GROUP1", UserReference: "USER1"})
+
+	assert.NotNil(t, err)
+	assert.Equal(t, "group member not found", err.Error())
+
+	repositoryMock.AssertNotCalled(t, "RemoveMember", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestRemoveMember_GivenAMemberRemovedConcurrently_WhenExecute_ThenReturnANotFoundError(t *testing.T) {
+	t.Log("Failure to remove a member from a Group because it was removed meanwhile")
+
+	repositoryMock := new(repositoryMock)
+	repositoryMock.On("FindActiveByReference", "GROUP1").Return(domain.Group{
+		GenericEntity: domain.GenericEntity{Reference: "GROUP1", IsActive: true},
+		Members:       []domain.GroupMember{{UserReference: "USER1"}},
+	}, nil)
+	repositoryMock.On("RemoveMember", "GROUP1", "USER1", mock.AnythingOfType("time.Time")).Return(false, nil)
+
+	useCase := NewDefaultRemoveMember(repositoryMock)
+
+	_, err := useCase.Execute(domain.GroupMemberInput{GroupReference: "GROUP1", UserReference: "USER1"})
+
+	assert.NotNil(t, err)
+	assert.Equal(t, "group member not found", err.Error())
+
+	repositoryMock.AssertExpectations(t)
+}
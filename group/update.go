@@ -0,0 +1,48 @@
+package group
+
+import (
+	"time"
+
+	"github.com/desarrollogj/golang-api-example/domain"
+	"github.com/desarrollogj/golang-api-example/infrastructure"
+	"github.com/desarrollogj/golang-api-example/libs/errors"
+	"github.com/desarrollogj/golang-api-example/libs/logger"
+)
+
+// Update represents the method to be implemented to update a group
+type Update interface {
+	Execute(input domain.GroupUpdateInput) (domain.Group, error)
+}
+
+// defaultUpdate is the default implementation of Update interface
+type defaultUpdate struct {
+	repository infrastructure.GroupRepository
+}
+
+// NewDefaultUpdate creates a defaultUpdate instance
+func NewDefaultUpdate(repository infrastructure.GroupRepository) defaultUpdate {
+	return defaultUpdate{
+		repository: repository,
+	}
+}
+
+// Execute update a Group name and description. The members are kept
+func (s defaultUpdate) Execute(input domain.GroupUpdateInput) (domain.Group, error) {
+	group, err := findActiveGroup(s.repository, input.Reference)
+	if err != nil {
+		return domain.Group{}, err
+	}
+
+	group.Name = input.Name
+	group.Description = input.Description
+	group.UpdatedDate = time.Now().UTC()
+
+	updated, err := s.repository.Update(group)
+	if err != nil {
+		errMsg := "unexpected error when update the group"
+		logger.AppLog.Error().Err(err).Msg(errMsg)
+		return domain.Group{}, errors.NewFatalError(errMsg)
+	}
+
+	return updated, nil
+}
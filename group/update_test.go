@@ -0,0 +1,69 @@
+package group
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/desarrollogj/golang-api-example/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestUpdate_GivenAnInput_WhenExecute_ThenUpdateTheGroupKeepingItsMembers(t *testing.T) {
+	t.Log("Successfully update a Group")
+
+	current := domain.Group{
+		GenericEntity: domain.GenericEntity{Reference: "GROUP1", IsActive: true},
+		Name:          "Backend",
+		Members:       []domain.GroupMember{{UserReference: "USER1"}},
+	}
+	repositoryMock := new(repositoryMock)
+	repositoryMock.On("FindActiveByReference", "GROUP1").Return(current, nil)
+	repositoryMock.On("Update", mock.MatchedBy(func(group domain.Group) bool {
+		return group.Name == "Platform" && group.Description == "Platform team" &&
+			group.HasMember("USER1") && !group.UpdatedDate.IsZero()
+	})).Return(current, nil)
+
+	useCase := NewDefaultUpdate(repositoryMock)
+
+	_, err := useCase.Execute(domain.GroupUpdateInput{
+		GroupCreateInput: domain.GroupCreateInput{Name: "Platform", Description: "Platform team"},
+		Reference:        "GROUP1",
+	})
+
+	assert.Nil(t, err)
+
+	repositoryMock.AssertExpectations(t)
+}
+
+func TestUpdate_GivenAnInput_WhenExecuteAndGroupNotFound_ThenReturnANotFoundError(t *testing.T) {
+	t.Log("Failure to update a Group because it was not found")
+
+	repositoryMock := new(repositoryMock)
+	repositoryMock.On("FindActiveByReference", "GROUP1").Return(domain.Group{}, nil)
+
+	useCase := NewDefaultUpdate(repositoryMock)
+
+	_, err := useCase.Execute(domain.GroupUpdateInput{Reference: "GROUP1"})
+
+	assert.NotNil(t, err)
+	assert.Equal(t, "group not found", err.Error())
+
+	repositoryMock.AssertNotCalled(t, "Update", mock.Anything)
+}
+
+func TestUpdate_GivenAnInput_WhenExecuteAndUpdateReturnedAnError_ThenReturnAFatalError(t *testing.T) {
+	t.Log("Failure to update a Group because update returned an unexpected error")
+
+	repositoryMock := new(repositoryMock)
+	repositoryMock.On("FindActiveByReference", "GROUP1").
+		Return(domain.Group{GenericEntity: domain.GenericEntity{Reference: "GROUP1", IsActive: true}}, nil)
+	repositoryMock.On("Update", mock.AnythingOfType("Group")).Return(domain.Group{}, errors.New("repository error"))
+
+	useCase := NewDefaultUpdate(repositoryMock)
+
+	_, err := useCase.Execute(domain.GroupUpdateInput{Reference: "GROUP1"})
+
+	assert.NotNil(t, err)
+	assert.Equal(t, "unexpected error when update the group", err.Error())
+}
@@ -0,0 +1,172 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/desarrollogj/golang-api-example/auth"
+	appErrors "github.com/desarrollogj/golang-api-example/libs/errors"
+	appGin "github.com/desarrollogj/golang-api-example/libs/gin"
+	"github.com/gin-gonic/gin"
+)
+
+// APIKey represents the method for API keys management endpoints handlers
+type APIKey interface {
+	FindAll(c *gin.Context)
+	Create(c *gin.Context)
+	Rotate(c *gin.Context)
+	Revoke(c *gin.Context)
+}
+
+// defaultAPIKey is the default implementation for APIKey interface
+type defaultAPIKey struct {
+	mapper  APIKeyMapper
+	findAll auth.FindAllAPIKeys
+	create  auth.CreateAPIKey
+	rotate  auth.RotateAPIKey
+	revoke  auth.RevokeAPIKey
+}
+
+// NewDefaultAPIKey creates a defaultAPIKey handler
+func NewDefaultAPIKey(mapper APIKeyMapper,
+	findAll auth.FindAllAPIKeys,
+	create auth.CreateAPIKey,
+	rotate auth.RotateAPIKey,
+	revoke auth.RevokeAPIKey) defaultAPIKey {
+	validate = newValidator()
+	return defaultAPIKey{
+		mapper:  mapper,
+		findAll: findAll,
+		create:  create,
+		rotate:  rotate,
+		revoke:  revoke,
+	}
+}
+
+// FindAll get all the API keys
+// @Tags apiKey
+// @Summary Get all API keys
+// @Description Get all the API keys, including the expired and revoked ones. The secrets are never returned
+// @Produce json
+// @Success 200 {array} handler.APIKeyResponse
+// @Failure 401	{object} appErrors.APIError
+// @Failure 403	{object} appErrors.APIError
+// @Failure 500	{object} appErrors.APIError
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api-keys [get]
+func (h defaultAPIKey) FindAll(c *gin.Context) {
+	appGin.ErrorWrapper(h.executeFindAll, c)
+}
+
+func (h defaultAPIKey) executeFindAll(c *gin.Context) *appErrors.APIError {
+	keys, err := h.findAll.Execute()
+	if err != nil {
+		return appErrors.HandleBusinessError(err)
+	}
+
+	c.JSON(http.StatusOK, h.mapper.MapDomainListToResponseList(keys))
+	return nil
+}
+
+// Create create an API key
+// @Tags apiKey
+// @Summary Create an API key
+// @Description Create an API key with a random secret. The key is only returned in this response, store it safely
+// @Param request body handler.APIKeyCreateRequest true "API key data"
+// @Produce json
+// @Success 201 {object} handler.APIKeySecretResponse
+// @Failure 400	{object} appErrors.APIError
+// @Failure 401	{object} appErrors.APIError
+// @Failure 403	{object} appErrors.APIError
+// @Failure 500	{object} appErrors.APIError
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api-keys [post]
+func (h defaultAPIKey) Create(c *gin.Context) {
+	appGin.ErrorWrapper(h.executeCreate, c)
+}
+
+func (h defaultAPIKey) executeCreate(c *gin.Context) *appErrors.APIError {
+	var req APIKeyCreateRequest
+	if apiErr := bindJSON(c, &req); apiErr != nil {
+		return apiErr
+	}
+
+	created, err := h.create.Execute(h.mapper.MapCreateRequestToInput(req))
+	if err != nil {
+		return appErrors.HandleBusinessError(err)
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusCreated, h.mapper.MapDomainSecretToResponse(created))
+	return nil
+}
+
+// Rotate replace an API key secret
+// @Tags apiKey
+// @Summary Rotate an API key
+// @Description Replace the API key secret. The previous key stops working immediately, and the new one is only returned in this response
+// @Param id path string true "API key id"
+// @Produce json
+// @Success 200 {object} handler.APIKeySecretResponse
+// @Failure 400	{object} appErrors.APIError
+// @Failure 401	{object} appErrors.APIError
+// @Failure 403	{object} appErrors.APIError
+// @Failure 404	{object} appErrors.APIError
+// @Failure 500	{object} appErrors.APIError
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api-keys/{id}/rotate [post]
+func (h defaultAPIKey) Rotate(c *gin.Context) {
+	appGin.ErrorWrapper(h.executeRotate, c)
+}
+
+func (h defaultAPIKey) executeRotate(c *gin.Context) *appErrors.APIError {
+	reference := c.Param("id")
+	if len(reference) == 0 {
+		return appErrors.NewBadRequest("API key id is required").WithKey("api_key_id_required")
+	}
+
+	rotated, err := h.rotate.Execute(reference)
+	if err != nil {
+		return appErrors.HandleBusinessError(err)
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, h.mapper.MapDomainSecretToResponse(rotated))
+	return nil
+}
+
+// Revoke revoke an API key
+// @Tags apiKey
+// @Summary Revoke an API key
+// @Description Revoke an API key, it stops working immediately. Revoking a revoked key has no effect
+// @Param id path string true "API key id"
+// @Produce json
+// @Success 200 {object} handler.APIKeyResponse
+// @Failure 400	{object} appErrors.APIError
+// @Failure 401	{object} appErrors.APIError
+// @Failure 403	{object} appErrors.APIError
+// @Failure 404	{object} appErrors.APIError
+// @Failure 500	{object} appErrors.APIError
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /api-keys/{id}/revoke [post]
+func (h defaultAPIKey) Revoke(c *gin.Context) {
+	appGin.ErrorWrapper(h.executeRevoke, c)
+}
+
+func (h defaultAPIKey) executeRevoke(c *gin.Context) *appErrors.APIError {
+	reference := c.Param("id")
+	if len(reference) == 0 {
+		return appErrors.NewBadRequest("API key id is required").WithKey("api_key_id_required")
+	}
+
+	revoked, err := h.revoke.Execute(reference)
+	if err != nil {
+		return appErrors.HandleBusinessError(err)
+	}
+
+	c.JSON(http.StatusOK, h.mapper.MapDomainToResponse(revoked))
+	return nil
+}
@@ -0,0 +1,109 @@
+package handler
+
+import (
+	"strings"
+	"time"
+
+	"github.com/desarrollogj/golang-api-example/domain"
+)
+
+type APIKeyResponse struct {
+	Id           string   `json:"id"`
+	Name         string   `json:"name"`
+	Prefix       string   `json:"prefix"`
+	Scopes       []string `json:"scopes"`
+	ExpiresDate  string   `json:"expiresDate,omitempty"`
+	LastUsedDate string   `json:"lastUsedDate,omitempty"`
+	RevokedDate  string   `json:"revokedDate,omitempty"`
+	IsActive     bool     `json:"isActive"`
+	CreatedDate  string   `json:"createdDate"`
+	UpdatedDate  string   `json:"updatedDate"`
+}
+
+// APIKeySecretResponse is an API key with its secret. It is only returned when the key is created or rotated
+type APIKeySecretResponse struct {
+	APIKeyResponse
+	Key string `json:"key"`
+}
+
+type APIKeyCreateRequest struct {
+	Name        string   `json:"name" validate:"required,max=100"`
+	Scopes      []string `json:"scopes" validate:"required,min=1,dive,required"`
+	ExpiresDate string   `json:"expiresDate" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+}
+
+// APIKeyMapper represents the method for API key mappers
+type APIKeyMapper interface {
+	MapDomainToResponse(key domain.APIKey) APIKeyResponse
+	MapDomainListToResponseList(keys []domain.APIKey) []APIKeyResponse
+	MapDomainSecretToResponse(secret domain.APIKeySecret) APIKeySecretResponse
+	MapCreateRequestToInput(request APIKeyCreateRequest) domain.APIKeyCreateInput
+}
+
+// defaultAPIKeyMapper is the default implementation for APIKeyMapper interface
+type defaultAPIKeyMapper struct {
+}
+
+// NewDefaultAPIKeyMapper creates a defaultAPIKeyMapper
+func NewDefaultAPIKeyMapper() defaultAPIKeyMapper {
+	return defaultAPIKeyMapper{}
+}
+
+// MapDomainToResponse map a domain API key to a response. The secret hash is never returned
+func (m defaultAPIKeyMapper) MapDomainToResponse(key domain.APIKey) APIKeyResponse {
+	return APIKeyResponse{
+		Id:           key.Reference,
+		Name:         key.Name,
+		Prefix:       key.Prefix,
+		Scopes:       key.Scopes,
+		ExpiresDate:  m.mapOptionalDateToResponse(key.ExpiresDate),
+		LastUsedDate: m.mapOptionalDateToResponse(key.LastUsedDate),
+		RevokedDate:  m.mapOptionalDateToResponse(key.RevokedDate),
+		IsActive:     key.RevokedDate == nil && (key.ExpiresDate == nil || key.ExpiresDate.After(time.Now())),
+		CreatedDate:  key.CreatedDate.UTC().Format(time.RFC3339),
+		UpdatedDate:  key.UpdatedDate.UTC().Format(time.RFC3339),
+	}
+}
+
+// MapDomainListToResponseList map a list of domain API keys to a response list
+func (m defaultAPIKeyMapper) MapDomainListToResponseList(keys []domain.APIKey) []APIKeyResponse {
+	keysResponse := []APIKeyResponse{}
+
+	for _, key := range keys {
+		keysResponse = append(keysResponse, m.MapDomainToResponse(key))
+	}
+
+	return keysResponse
+}
+
+// MapDomainSecretToResponse map a domain API key with its secret to a response
+func (m defaultAPIKeyMapper) MapDomainSecretToResponse(secret domain.APIKeySecret) APIKeySecretResponse {
+	return APIKeySecretResponse{
+		APIKeyResponse: m.MapDomainToResponse(secret.APIKey),
+		Key:            secret.Key,
+	}
+}
+
+// MapCreateRequestToInput map create request to an input struct
+func (m defaultAPIKeyMapper) MapCreateRequestToInput(request APIKeyCreateRequest) domain.APIKeyCreateInput {
+	input := domain.APIKeyCreateInput{
+		Name: strings.TrimSpace(request.Name),
+	}
+	for _, scope := range request.Scopes {
+		input.Scopes = append(input.Scopes, strings.ToLower(strings.TrimSpace(scope)))
+	}
+	if expires, err := time.Parse(time.RFC3339, strings.TrimSpace(request.ExpiresDate)); err == nil {
+		expires = expires.UTC()
+		input.ExpiresDate = &expires
+	}
+
+	return input
+}
+
+func (m defaultAPIKeyMapper) mapOptionalDateToResponse(date *time.Time) string {
+	if date == nil {
+		return ""
+	}
+
+	return date.UTC().Format(time.RFC3339)
+}
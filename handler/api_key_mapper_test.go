@@ -0,0 +1,58 @@
+package handler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/desarrollogj/golang-api-example/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIKeyMapper_GivenADomainKey_WhenMapDomainToResponse_ThenReturnResponseWithoutSecretHash(t *testing.T) {
+	t.Log("Should map a domain API key to a response")
+
+	created := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	revoked := created.Add(time.Hour)
+	key := domain.APIKey{
+		Reference:   "KEY1",
+		Name:        "batch",
+		Prefix:      "0123456789ab",
+		SecretHash:  "hash",
+		Scopes:      []string{domain.APIKeyScopeRead},
+		RevokedDate: &revoked,
+		CreatedDate: created,
+		UpdatedDate: revoked,
+	}
+
+	mapper := NewDefaultAPIKeyMapper()
+	response := mapper.MapDomainToResponse(key)
+
+	assert.Equal(t, APIKeyResponse{
+		Id:          "KEY1",
+		Name:        "batch",
+		Prefix:      "0123456789ab",
+		Scopes:      []string{domain.APIKeyScopeRead},
+		RevokedDate: "2024-01-01T11:00:00Z",
+		IsActive:    false,
+		CreatedDate: "2024-01-01T10:00:00Z",
+		UpdatedDate: "2024-01-01T11:00:00Z",
+	}, response)
+}
+
+func TestAPIKeyMapper_GivenACreateRequest_WhenMapCreateRequestToInput_ThenReturnInput(t *testing.T) {
+	t.Log("Should map an API key create request to an input")
+
+	mapper := NewDefaultAPIKeyMapper()
+	input := mapper.MapCreateRequestToInput(APIKeyCreateRequest{
+		Name:        " batch ",
+		Scopes:      []string{" Read", "write"},
+		ExpiresDate: "2030-01-01T00:00:00-03:00",
+	})
+
+	expires := time.Date(2030, 1, 1, 3, 0, 0, 0, time.UTC)
+	assert.Equal(t, domain.APIKeyCreateInput{
+		Name:        "batch",
+		Scopes:      []string{domain.APIKeyScopeRead, domain.APIKeyScopeWrite},
+		ExpiresDate: &expires,
+	}, input)
+}
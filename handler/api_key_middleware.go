@@ -0,0 +1,77 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/desarrollogj/golang-api-example/auth"
+	"github.com/desarrollogj/golang-api-example/domain"
+	appErrors "github.com/desarrollogj/golang-api-example/libs/errors"
+	appGin "github.com/desarrollogj/golang-api-example/libs/gin"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// APIKeyHeader is the header with the API key of the requests authenticated with it
+	APIKeyHeader = "X-API-Key"
+	// AuthAPIKeyKey is the context key of the API key of the requests authenticated with it
+	AuthAPIKeyKey = "auth.apiKey"
+)
+
+// NewAPIKeyMiddleware creates a middleware that authenticates the requests with an API key header, and puts the key in the context.
+// Requests without the header are left to the next middleware. Safe methods require the read scope, and the other ones the write scope
+func NewAPIKeyMiddleware(authenticate auth.AuthenticateAPIKey) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(APIKeyHeader)
+		if len(key) == 0 {
+			c.Next()
+			return
+		}
+
+		apiKey, err := authenticate.Execute(key)
+		if err != nil {
+			apiErr := appErrors.HandleBusinessError(err)
+			appGin.AbortWithError(c, apiErr)
+			return
+		}
+
+		scope := domain.APIKeyScopeWrite
+		switch c.Request.Method {
+		case http.MethodGet, http.MethodHead, http.MethodOptions:
+			scope = domain.APIKeyScopeRead
+		}
+		if !apiKey.HasScope(scope) {
+			abortForbidden(c, "API key requires the "+scope+" scope", "api_key_scope_required", scope)
+			return
+		}
+
+		c.Set(AuthAPIKeyKey, apiKey)
+		c.Next()
+	}
+}
+
+// RequireAPIKeyScope creates a middleware that requires a scope to the requests authenticated with an API key
+func RequireAPIKeyScope(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if apiKey, ok := GetAuthAPIKey(c); ok && !apiKey.HasScope(scope) {
+			abortForbidden(c, "API key requires the "+scope+" scope", "api_key_scope_required", scope)
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// GetAuthAPIKey returns the API key of a request authenticated with it
+func GetAuthAPIKey(c *gin.Context) (domain.APIKey, bool) {
+	value, ok := c.Get(AuthAPIKeyKey)
+	if !ok {
+		return domain.APIKey{}, false
+	}
+	apiKey, ok := value.(domain.APIKey)
+	return apiKey, ok
+}
+
+func abortForbidden(c *gin.Context, message string, key string, args ...interface{}) {
+	err := appErrors.NewForbidden(message).WithKey(key, args...)
+	appGin.AbortWithError(c, err)
+}
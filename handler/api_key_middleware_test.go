@@ -0,0 +1,161 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/desarrollogj/golang-api-example/auth"
+	"github.com/desarrollogj/golang-api-example/domain"
+	libErrors "github.com/desarrollogj/golang-api-example/libs/errors"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func apiKeyMiddlewareTestRouter(authenticate auth.AuthenticateAPIKey, verifier auth.TokenVerifier) *gin.Engine {
+	r := testRouter()
+	api := r.Group("/api/v1", NewAPIKeyMiddleware(authenticate), NewAuthMiddleware(verifier))
+	api.GET("/users", func(c *gin.Context) {
+		apiKey, _ := GetAuthAPIKey(c)
+		c.String(http.StatusOK, apiKey.Reference)
+	})
+	api.POST("/users", func(c *gin.Context) {
+		c.Status(http.StatusCreated)
+	})
+	api.GET("/api-keys", RequireAPIKeyScope(domain.APIKeyScopeAdmin), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	return r
+}
+
+func TestAPIKeyMiddleware_GivenAValidKey_WhenRequest_ThenPutKeyInContextAndSkipBearer(t *testing.T) {
+	t.Log("Successfully authenticate a request with an API key")
+
+	authenticateMock := new(authenticateAPIKeyServiceMock)
+	authenticateMock.On("Execute", "KEY").Return(domain.APIKey{Reference: "KEY1", Scopes: []string{domain.APIKeyScopeRead}}, nil)
+	verifierMock := new(tokenVerifierMock)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/users", nil)
+	req.Header.Set("X-API-Key", "KEY")
+
+	apiKeyMiddlewareTestRouter(authenticateMock, verifierMock).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "KEY1", w.Body.String())
+
+	verifierMock.AssertNotCalled(t, "Parse", mock.Anything)
+}
+
+func TestAPIKeyMiddleware_GivenANotValidKey_WhenRequest_ThenReturnUnauthorizedResponse(t *testing.T) {
+	t.Log("Failure to authenticate a request because the API key is not valid")
+
+	authenticateMock := new(authenticateAPIKeyServiceMock)
+	authenticateMock.On("Execute", "KEY").
+		Return(domain.APIKey{}, libErrors.NewBusinessUnauthorizedError("API key is not valid, expired or revoked"))
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/users", nil)
+	req.Header.Set("X-API-Key", "KEY")
+
+	apiKeyMiddlewareTestRouter(authenticateMock, new(tokenVerifierMock)).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	var err libErrors.APIError
+	json.NewDecoder(w.Body).Decode(&err)
+
+	assert.Equal(t, "API key is not valid, expired or revoked", err.Message)
+}
+
+func TestAPIKeyMiddleware_GivenAReadOnlyKey_WhenWriteRequest_ThenReturnForbiddenResponse(t *testing.T) {
+	t.Log("Failure to write with an API key without the write scope")
+
+	authenticateMock := new(authenticateAPIKeyServiceMock)
+	authenticateMock.On("Execute", "KEY").Return(domain.APIKey{Reference: "KEY1", Scopes: []string{domain.APIKeyScopeRead}}, nil)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/users", nil)
+	req.Header.Set("X-API-Key", "KEY")
+
+	apiKeyMiddlewareTestRouter(authenticateMock, new(tokenVerifierMock)).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	var err libErrors.APIError
+	json.NewDecoder(w.Body).Decode(&err)
+
+	assert.Equal(t, "API key requires the write scope", err.Message)
+}
+
+func TestAPIKeyMiddleware_GivenAKeyWithoutAdminScope_WhenRequestAnAdminRoute_ThenReturnForbiddenResponse(t *testing.T) {
+	t.Log("Failure to manage API keys with an API key without the admin scope")
+
+	authenticateMock := new(authenticateAPIKeyServiceMock)
+	authenticateMock.On("Execute", "KEY").Return(domain.APIKey{Reference: "KEY1", Scopes: []string{domain.APIKeyScopeRead}}, nil)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/api-keys", nil)
+	req.Header.Set("X-API-Key", "KEY")
+
+	apiKeyMiddlewareTestRouter(authenticateMock, new(tokenVerifierMock)).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestAPIKeyMiddleware_GivenARequestWithoutKey_WhenRequest_ThenRequireABearerToken(t *testing.T) {
+	t.Log("Requests without API key are authenticated with the bearer access token")
+
+	authenticateMock := new(authenticateAPIKeyServiceMock)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/users", nil)
+
+	apiKeyMiddlewareTestRouter(authenticateMock, new(tokenVerifierMock)).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	authenticateMock.AssertNotCalled(t, "Execute", mock.Anything)
+}
+
+func TestAPIKeyMiddleware_GivenAnAdminKey_WhenRequest_ThenAllowTheReadWriteAndAdminRoutes(t *testing.T) {
+	t.Log("Successfully read, write and manage API keys with an API key with only the admin scope")
+
+	authenticateMock := new(authenticateAPIKeyServiceMock)
+	authenticateMock.On("Execute", "KEY").Return(domain.APIKey{Reference: "KEY1", Scopes: []string{domain.APIKeyScopeAdmin}}, nil)
+	identity, err := NewIdentityMiddleware(newAuthorizationConfigurationMock(domain.IdentitySourceToken))
+	assert.Nil(t, err)
+
+	r := testRouter()
+	api := r.Group("/api/v1", NewAPIKeyMiddleware(authenticateMock), NewAuthMiddleware(new(tokenVerifierMock)), identity)
+	api.GET("/users", Authorize(domain.PermissionUsersRead), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	api.POST("/users", Authorize(domain.PermissionUsersWrite), func(c *gin.Context) {
+		c.Status(http.StatusCreated)
+	})
+	api.POST("/api-keys", RequireAPIKeyScope(domain.APIKeyScopeAdmin), Authorize(domain.PermissionAPIKeysAdmin), func(c *gin.Context) {
+		c.Status(http.StatusCreated)
+	})
+
+	requests := []struct {
+		method string
+		path   string
+		status int
+	}{
+		{http.MethodGet, "/api/v1/users", http.StatusOK},
+		{http.MethodPost, "/api/v1/users", http.StatusCreated},
+		{http.MethodPost, "/api/v1/api-keys", http.StatusCreated},
+	}
+	for _, request := range requests {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(request.method, request.path, nil)
+		req.Header.Set("X-API-Key", "KEY")
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, request.status, w.Code, request.method+" "+request.path)
+	}
+}
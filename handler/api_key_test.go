@@ -0,0 +1,174 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/desarrollogj/golang-api-example/domain"
+	libErrors "github.com/desarrollogj/golang-api-example/libs/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type apiKeyHandlerMocks struct {
+	mapper  *apiKeyMapperMock
+	findAll *findAllAPIKeysServiceMock
+	create  *createAPIKeyServiceMock
+	rotate  *rotateAPIKeyServiceMock
+	revoke  *revokeAPIKeyServiceMock
+}
+
+func newAPIKeyHandlerMocks() apiKeyHandlerMocks {
+	return apiKeyHandlerMocks{
+		mapper:  new(apiKeyMapperMock),
+		findAll: new(findAllAPIKeysServiceMock),
+		create:  new(createAPIKeyServiceMock),
+		rotate:  new(rotateAPIKeyServiceMock),
+		revoke:  new(revokeAPIKeyServiceMock),
+	}
+}
+
+func (m apiKeyHandlerMocks) handler() defaultAPIKey {
+	return NewDefaultAPIKey(m.mapper, m.findAll, m.create, m.rotate, m.revoke)
+}
+
+func TestAPIKey_GivenStoredKeys_WhenFindAll_ThenReturnKeysResponse(t *testing.T) {
+	t.Log("Successfully get all API keys")
+
+	keys := []domain.APIKey{{Reference: "KEY1"}}
+	responseKeys := []APIKeyResponse{{Id: "KEY1"}}
+	mocks := newAPIKeyHandlerMocks()
+	mocks.findAll.On("Execute").Return(keys, nil)
+	mocks.mapper.On("MapDomainListToResponseList", keys).Return(responseKeys)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/api-keys", nil)
+
+	r := testRouter()
+	r.GET("/api/v1/api-keys", mocks.handler().FindAll)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var result []APIKeyResponse
+	json.NewDecoder(w.Body).Decode(&result)
+
+	assert.Equal(t, responseKeys, result)
+}
+
+func TestAPIKey_GivenAValidRequest_WhenCreate_ThenReturnCreatedKeyWithItsSecret(t *testing.T) {
+	t.Log("Successfully create an API key")
+
+	request := APIKeyCreateRequest{Name: "batch", Scopes: []string{"read"}}
+	input := domain.APIKeyCreateInput{Name: "batch", Scopes: []string{"read"}}
+	created := domain.APIKeySecret{APIKey: domain.APIKey{Reference: "KEY1"}, Key: "SECRET"}
+	response := APIKeySecretResponse{APIKeyResponse: APIKeyResponse{Id: "KEY1"}, Key: "SECRET"}
+	mocks := newAPIKeyHandlerMocks()
+	mocks.mapper.On("MapCreateRequestToInput", request).Return(input)
+	mocks.create.On("Execute", input).Return(created, nil)
+	mocks.mapper.On("MapDomainSecretToResponse", created).Return(response)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/api-keys", bytes.NewBufferString(`{"name":"batch","scopes":["read"]}`))
+
+	r := testRouter()
+	r.POST("/api/v1/api-keys", mocks.handler().Create)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, "no-store", w.Header().Get("Cache-Control"))
+
+	var result APIKeySecretResponse
+	json.NewDecoder(w.Body).Decode(&result)
+
+	assert.Equal(t, response, result)
+}
+
+func TestAPIKey_GivenARequestWithoutScopes_WhenCreate_ThenReturnBadRequestResponse(t *testing.T) {
+	t.Log("Failure to create an API key because the scopes are missing")
+
+	mocks := newAPIKeyHandlerMocks()
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/api-keys", bytes.NewBufferString(`{"name":"batch","scopes":[]}`))
+
+	r := testRouter()
+	r.POST("/api/v1/api-keys", mocks.handler().Create)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	mocks.create.AssertNotCalled(t, "Execute", mock.Anything)
+}
+
+func TestAPIKey_GivenAReference_WhenRotate_ThenReturnKeyWithItsNewSecret(t *testing.T) {
+	t.Log("Successfully rotate an API key")
+
+	rotated := domain.APIKeySecret{APIKey: domain.APIKey{Reference: "KEY1"}, Key: "SECRET"}
+	response := APIKeySecretResponse{APIKeyResponse: APIKeyResponse{Id: "KEY1"}, Key: "SECRET"}
+	mocks := newAPIKeyHandlerMocks()
+	mocks.rotate.On("Execute", "KEY1").Return(rotated, nil)
+	mocks.mapper.On("MapDomainSecretToResponse", rotated).Return(response)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/api-keys/KEY1/rotate", nil)
+
+	r := testRouter()
+	r.POST("/api/v1/api-keys/:id/rotate", mocks.handler().Rotate)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var result APIKeySecretResponse
+	json.NewDecoder(w.Body).Decode(&result)
+
+	assert.Equal(t, response, result)
+}
+
+func TestAPIKey_GivenAnUnknownReference_WhenRevoke_ThenReturnNotFoundResponse(t *testing.T) {
+	t.Log("Failure to revoke an API key because it was not found")
+
+	mocks := newAPIKeyHandlerMocks()
+	mocks.revoke.On("Execute", "KEY1").Return(domain.APIKey{}, libErrors.NewNotFoundError("API key not found"))
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/api-keys/KEY1/revoke", nil)
+
+	r := testRouter()
+	r.POST("/api/v1/api-keys/:id/revoke", mocks.handler().Revoke)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	var err libErrors.APIError
+	json.NewDecoder(w.Body).Decode(&err)
+
+	assert.Equal(t, "API key not found", err.Message)
+}
+
+func TestAPIKey_GivenAReference_WhenRevoke_ThenReturnRevokedKeyResponse(t *testing.T) {
+	t.Log("Successfully revoke an API key")
+
+	revoked := domain.APIKey{Reference: "KEY1"}
+	response := APIKeyResponse{Id: "KEY1", RevokedDate: "2024-01-01T00:00:00Z"}
+	mocks := newAPIKeyHandlerMocks()
+	mocks.revoke.On("Execute", "KEY1").Return(revoked, nil)
+	mocks.mapper.On("MapDomainToResponse", revoked).Return(response)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/api-keys/KEY1/revoke", nil)
+
+	r := testRouter()
+	r.POST("/api/v1/api-keys/:id/revoke", mocks.handler().Revoke)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var result APIKeyResponse
+	json.NewDecoder(w.Body).Decode(&result)
+
+	assert.Equal(t, response, result)
+}
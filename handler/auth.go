@@ -0,0 +1,75 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/desarrollogj/golang-api-example/auth"
+	"github.com/desarrollogj/golang-api-example/domain"
+	appErrors "github.com/desarrollogj/golang-api-example/libs/errors"
+	appGin "github.com/desarrollogj/golang-api-example/libs/gin"
+	"github.com/gin-gonic/gin"
+)
+
+type LoginRequest struct {
+	Email    string `json:"email" validate:"required,email"`
+	Password string `json:"password" validate:"required"`
+}
+
+type AccessTokenResponse struct {
+	AccessToken string `json:"accessToken"`
+	TokenType   string `json:"tokenType"`
+	ExpiresIn   int    `json:"expiresIn"`
+}
+
+// Auth represents the method for authentication endpoints handlers
+type Auth interface {
+	Login(c *gin.Context)
+}
+
+// defaultAuth is the default implementation for Auth interface
+type defaultAuth struct {
+	login auth.Login
+}
+
+// NewDefaultAuth creates a defaultAuth handler
+func NewDefaultAuth(login auth.Login) defaultAuth {
+	validate = newValidator()
+	return defaultAuth{
+		login: login,
+	}
+}
+
+// Login authenticate an user
+// @Tags auth
+// @Summary Login
+// @Description Authenticate an active user with its email and password, and return a signed access token
+// @Param request body handler.LoginRequest true "user credentials"
+// @Produce json
+// @Success 200 {object} handler.AccessTokenResponse
+// @Failure 400	{object} appErrors.APIError
+// @Failure 401	{object} appErrors.APIError
+// @Failure 500	{object} appErrors.APIError
+// @Router /auth/login [post]
+func (h defaultAuth) Login(c *gin.Context) {
+	appGin.ErrorWrapper(h.executeLogin, c)
+}
+
+func (h defaultAuth) executeLogin(c *gin.Context) *appErrors.APIError {
+	var req LoginRequest
+	if apiErr := bindJSON(c, &req); apiErr != nil {
+		return apiErr
+	}
+
+	token, err := h.login.Execute(domain.LoginInput{Email: req.Email, Password: req.Password})
+	if err != nil {
+		return appErrors.HandleBusinessError(err)
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.JSON(http.StatusOK, AccessTokenResponse{
+		AccessToken: token.Token,
+		TokenType:   token.TokenType,
+		ExpiresIn:   token.ExpiresIn,
+	})
+	return nil
+}
@@ -0,0 +1,69 @@
+package handler
+
+import (
+	"strings"
+
+	"github.com/desarrollogj/golang-api-example/auth"
+	appErrors "github.com/desarrollogj/golang-api-example/libs/errors"
+	appGin "github.com/desarrollogj/golang-api-example/libs/gin"
+	"github.com/desarrollogj/golang-api-example/libs/logger"
+	"github.com/gin-gonic/gin"
+)
+
+// AuthClaimsKey is the context key of the access token claims of the authenticated requests
+const AuthClaimsKey = "auth.claims"
+
+// NewAuthMiddleware creates a middleware that requires a valid bearer access token, and puts its claims in the context.
+// Public routes do not require it. They are registered paths ("/health"), optionally with a method ("POST /api/v1/auth/login").
+// Requests already authenticated with an API key do not require it either
+func NewAuthMiddleware(verifier auth.TokenVerifier, publicRoutes ...string) gin.HandlerFunc {
+	public := map[string]bool{}
+	for _, route := range publicRoutes {
+		public[route] = true
+	}
+
+	return func(c *gin.Context) {
+		path := c.FullPath()
+		if len(path) > 0 && (public[path] || public[c.Request.Method+" "+path]) {
+			c.Next()
+			return
+		}
+		if _, ok := GetAuthAPIKey(c); ok {
+			c.Next()
+			return
+		}
+
+		scheme, token, found := strings.Cut(c.GetHeader("Authorization"), " ")
+		token = strings.TrimSpace(token)
+		if !found || !strings.EqualFold(scheme, "Bearer") || len(token) == 0 {
+			abortUnauthorized(c, "bearer access token is required", "access_token_required")
+			return
+		}
+
+		claims, err := verifier.Parse(token)
+		if err != nil {
+			logger.AppLog.Debug().Err(err).Msg("access token rejected")
+			abortUnauthorized(c, "access token is not valid or expired", "access_token_not_valid")
+			return
+		}
+
+		c.Set(AuthClaimsKey, claims)
+		c.Next()
+	}
+}
+
+// GetAuthClaims returns the access token claims of an authenticated request
+func GetAuthClaims(c *gin.Context) (auth.AccessTokenClaims, bool) {
+	value, ok := c.Get(AuthClaimsKey)
+	if !ok {
+		return auth.AccessTokenClaims{}, false
+	}
+	claims, ok := value.(auth.AccessTokenClaims)
+	return claims, ok
+}
+
+func abortUnauthorized(c *gin.Context, message string, key string) {
+	err := appErrors.NewUnauthorizedError(message).WithKey(key)
+	c.Header("WWW-Authenticate", `Bearer error="invalid_token"`)
+	appGin.AbortWithError(c, err)
+}
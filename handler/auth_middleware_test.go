@@ -0,0 +1,106 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/desarrollogj/golang-api-example/auth"
+	libErrors "github.com/desarrollogj/golang-api-example/libs/errors"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func authMiddlewareTestRouter(verifier auth.TokenVerifier) *gin.Engine {
+	r := testRouter()
+	api := r.Group("/api/v1", NewAuthMiddleware(verifier, "POST /api/v1/auth/login"))
+	api.GET("/users", func(c *gin.Context) {
+		claims, _ := GetAuthClaims(c)
+		c.String(http.StatusOK, claims.Subject)
+	})
+	api.POST("/auth/login", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	return r
+}
+
+func TestAuthMiddleware_GivenAValidToken_WhenRequest_ThenPutClaimsInContext(t *testing.T) {
+	t.Log("Successfully authenticate a request with a bearer access token")
+
+	verifierMock := new(tokenVerifierMock)
+	verifierMock.On("Parse", "TOKEN").
+		Return(auth.AccessTokenClaims{RegisteredClaims: jwt.RegisteredClaims{Subject: "USER1"}}, nil)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/users", nil)
+	req.Header.Set("Authorization", "Bearer TOKEN")
+
+	authMiddlewareTestRouter(verifierMock).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "USER1", w.Body.String())
+
+	verifierMock.AssertExpectations(t)
+}
+
+func TestAuthMiddleware_GivenARequestWithoutToken_WhenRequest_ThenReturnUnauthorizedResponse(t *testing.T) {
+	t.Log("Failure to authenticate a request because the bearer access token is missing")
+
+	verifierMock := new(tokenVerifierMock)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/users", nil)
+	req.Header.Set("Authorization", "Basic Zm9vOmJhcg==")
+
+	authMiddlewareTestRouter(verifierMock).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	var err libErrors.APIError
+	json.NewDecoder(w.Body).Decode(&err)
+
+	assert.Equal(t, "bearer access token is required", err.Message)
+	assert.NotEmpty(t, w.Header().Get("WWW-Authenticate"))
+
+	verifierMock.AssertNotCalled(t, "Parse", mock.Anything)
+}
+
+func TestAuthMiddleware_GivenANotValidToken_WhenRequest_ThenReturnUnauthorizedResponse(t *testing.T) {
+	t.Log("Failure to authenticate a request because the bearer access token is not valid")
+
+	verifierMock := new(tokenVerifierMock)
+	verifierMock.On("Parse", "TOKEN").Return(auth.AccessTokenClaims{}, errors.New("token is expired"))
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/users", nil)
+	req.Header.Set("Authorization", "Bearer TOKEN")
+
+	authMiddlewareTestRouter(verifierMock).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	var err libErrors.APIError
+	json.NewDecoder(w.Body).Decode(&err)
+
+	assert.Equal(t, "access token is not valid or expired", err.Message)
+	assert.Equal(t, "unauthorized", err.Err)
+}
+
+func TestAuthMiddleware_GivenAPublicRoute_WhenRequestWithoutToken_ThenSkipAuthentication(t *testing.T) {
+	t.Log("Successfully skip the authentication of a public route")
+
+	verifierMock := new(tokenVerifierMock)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/login", nil)
+
+	authMiddlewareTestRouter(verifierMock).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	verifierMock.AssertNotCalled(t, "Parse", mock.Anything)
+}
@@ -0,0 +1,162 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/desarrollogj/golang-api-example/auth"
+	"github.com/desarrollogj/golang-api-example/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+type authLoginServiceMock struct {
+	mock.Mock
+}
+
+func (s *authLoginServiceMock) Execute(input domain.LoginInput) (domain.AccessToken, error) {
+	args := s.Called(input)
+
+	t, ok := args.Get(0).(domain.AccessToken)
+	if !ok {
+		return domain.AccessToken{}, errors.New("mock_error")
+	}
+
+	return t, args.Error(1)
+}
+
+type tokenVerifierMock struct {
+	mock.Mock
+}
+
+func (v *tokenVerifierMock) Parse(token string) (auth.AccessTokenClaims, error) {
+	args := v.Called(token)
+
+	c, ok := args.Get(0).(auth.AccessTokenClaims)
+	if !ok {
+		return auth.AccessTokenClaims{}, errors.New("mock_error")
+	}
+
+	return c, args.Error(1)
+}
+
+type authenticateAPIKeyServiceMock struct {
+	mock.Mock
+}
+
+func (s *authenticateAPIKeyServiceMock) Execute(key string) (domain.APIKey, error) {
+	args := s.Called(key)
+
+	k, ok := args.Get(0).(domain.APIKey)
+	if !ok {
+		return domain.APIKey{}, errors.New("mock_error")
+	}
+
+	return k, args.Error(1)
+}
+
+type apiKeyMapperMock struct {
+	mock.Mock
+}
+
+func (m *apiKeyMapperMock) MapDomainToResponse(key domain.APIKey) APIKeyResponse {
+	args := m.Called(key)
+
+	t, ok := args.Get(0).(APIKeyResponse)
+	if !ok {
+		return APIKeyResponse{}
+	}
+
+	return t
+}
+
+func (m *apiKeyMapperMock) MapDomainListToResponseList(keys []domain.APIKey) []APIKeyResponse {
+	args := m.Called(keys)
+
+	t, ok := args.Get(0).([]APIKeyResponse)
+	if !ok {
+		return []APIKeyResponse{}
+	}
+
+	return t
+}
+
+func (m *apiKeyMapperMock) MapDomainSecretToResponse(secret domain.APIKeySecret) APIKeySecretResponse {
+	args := m.Called(secret)
+
+	t, ok := args.Get(0).(APIKeySecretResponse)
+	if !ok {
+		return APIKeySecretResponse{}
+	}
+
+	return t
+}
+
+func (m *apiKeyMapperMock) MapCreateRequestToInput(request APIKeyCreateRequest) domain.APIKeyCreateInput {
+	args := m.Called(request)
+
+	t, ok := args.Get(0).(domain.APIKeyCreateInput)
+	if !ok {
+		return domain.APIKeyCreateInput{}
+	}
+
+	return t
+}
+
+type findAllAPIKeysServiceMock struct {
+	mock.Mock
+}
+
+func (s *findAllAPIKeysServiceMock) Execute() ([]domain.APIKey, error) {
+	args := s.Called()
+
+	k, ok := args.Get(0).([]domain.APIKey)
+	if !ok {
+		return []domain.APIKey{}, errors.New("mock_error")
+	}
+
+	return k, args.Error(1)
+}
+
+type createAPIKeyServiceMock struct {
+	mock.Mock
+}
+
+func (s *createAPIKeyServiceMock) Execute(input domain.APIKeyCreateInput) (domain.APIKeySecret, error) {
+	args := s.Called(input)
+
+	k, ok := args.Get(0).(domain.APIKeySecret)
+	if !ok {
+		return domain.APIKeySecret{}, errors.New("mock_error")
+	}
+
+	return k, args.Error(1)
+}
+
+type rotateAPIKeyServiceMock struct {
+	mock.Mock
+}
+
+func (s *rotateAPIKeyServiceMock) Execute(reference string) (domain.APIKeySecret, error) {
+	args := s.Called(reference)
+
+	k, ok := args.Get(0).(domain.APIKeySecret)
+	if !ok {
+		return domain.APIKeySecret{}, errors.New("mock_error")
+	}
+
+	return k, args.Error(1)
+}
+
+type revokeAPIKeyServiceMock struct {
+	mock.Mock
+}
+
+func (s *revokeAPIKeyServiceMock) Execute(reference string) (domain.APIKey, error) {
+	args := s.Called(reference)
+
+	k, ok := args.Get(0).(domain.APIKey)
+	if !ok {
+		return domain.APIKey{}, errors.New("mock_error")
+	}
+
+	return k, args.Error(1)
+}
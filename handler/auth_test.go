@@ -0,0 +1,86 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/desarrollogj/golang-api-example/domain"
+	libErrors "github.com/desarrollogj/golang-api-example/libs/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestAuth_GivenValidCredentials_WhenLogin_ThenReturnAccessTokenResponse(t *testing.T) {
+	t.Log("Successfully login an user")
+
+	loginMock := new(authLoginServiceMock)
+	loginMock.On("Execute", domain.LoginInput{Email: "foobar@email.com", Password: "Secret-Password1"}).
+		Return(domain.AccessToken{Token: "TOKEN", TokenType: "Bearer", ExpiresIn: 900}, nil)
+
+	handler := NewDefaultAuth(loginMock)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/login", bytes.NewBufferString(`{"email":"foobar@email.com","password":"Secret-Password1"}`))
+
+	r := testRouter()
+	r.POST("/api/v1/auth/login", handler.Login)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "no-store", w.Header().Get("Cache-Control"))
+
+	var result AccessTokenResponse
+	json.NewDecoder(w.Body).Decode(&result)
+
+	assert.Equal(t, AccessTokenResponse{AccessToken: "TOKEN", TokenType: "Bearer", ExpiresIn: 900}, result)
+
+	loginMock.AssertExpectations(t)
+}
+
+func TestAuth_GivenNotValidCredentials_WhenLogin_ThenReturnUnauthorizedResponse(t *testing.T) {
+	t.Log("Failure to login an user because the credentials are not valid")
+
+	loginMock := new(authLoginServiceMock)
+	loginMock.On("Execute", mock.AnythingOfType("LoginInput")).
+		Return(domain.AccessToken{}, libErrors.NewBusinessUnauthorizedError("email or password is not valid"))
+
+	handler := NewDefaultAuth(loginMock)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/login", bytes.NewBufferString(`{"email":"foobar@email.com","password":"wrong"}`))
+
+	r := testRouter()
+	r.POST("/api/v1/auth/login", handler.Login)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	var err libErrors.APIError
+	json.NewDecoder(w.Body).Decode(&err)
+
+	assert.Equal(t, "email or password is not valid", err.Message)
+
+	loginMock.AssertExpectations(t)
+}
+
+func TestAuth_GivenARequestWithoutPassword_WhenLogin_ThenReturnBadRequestResponse(t *testing.T) {
+	t.Log("Failure to login an user because the password is missing")
+
+	loginMock := new(authLoginServiceMock)
+
+	handler := NewDefaultAuth(loginMock)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/login", bytes.NewBufferString(`{"email":"foobar@email.com"}`))
+
+	r := testRouter()
+	r.POST("/api/v1/auth/login", handler.Login)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	loginMock.AssertNotCalled(t, "Execute", mock.Anything)
+}
@@ -0,0 +1,144 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/desarrollogj/golang-api-example/domain"
+	"github.com/gin-gonic/gin"
+)
+
+// AuthIdentityKey is the context key of the caller identity
+const AuthIdentityKey = "auth.identity"
+
+// NewIdentityMiddleware creates a middleware that puts the caller identity in the context, with the permissions granted by its roles.
+// Requests authenticated with an API key get the permissions of its scopes. The other callers are taken from the verified access
+// token claims, or from the configured headers of a trusted gateway. When the authorization is disabled, every caller has all the permissions
+func NewIdentityMiddleware(config domain.AuthorizationConfiguration) (gin.HandlerFunc, error) {
+	if config.Enabled {
+		if err := validateAuthorizationConfiguration(config); err != nil {
+			return nil, err
+		}
+	}
+
+	return func(c *gin.Context) {
+		if !config.Enabled {
+			c.Set(AuthIdentityKey, domain.Identity{Permissions: domain.Permissions})
+			c.Next()
+			return
+		}
+
+		if identity, ok := callerIdentity(c, config); ok {
+			c.Set(AuthIdentityKey, identity)
+		}
+		c.Next()
+	}, nil
+}
+
+// Authorize creates a route middleware that requires the caller to have all the permissions
+func Authorize(permissions ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		identity, ok := GetIdentity(c)
+		if !ok {
+			abortUnauthorized(c, "caller identity is required", "caller_identity_required")
+			return
+		}
+		for _, permission := range permissions {
+			if !identity.HasPermission(permission) {
+				abortForbidden(c, fmt.Sprintf("permission %s is required", permission), "permission_required", permission)
+				return
+			}
+		}
+
+		c.Next()
+	}
+}
+
+// GetIdentity returns the caller identity
+func GetIdentity(c *gin.Context) (domain.Identity, bool) {
+	value, ok := c.Get(AuthIdentityKey)
+	if !ok {
+		return domain.Identity{}, false
+	}
+	identity, ok := value.(domain.Identity)
+	return identity, ok
+}
+
+// HasPermission returns true when the caller has the permission
+func HasPermission(c *gin.Context, permission string) bool {
+	identity, ok := GetIdentity(c)
+	return ok && identity.HasPermission(permission)
+}
+
+func callerIdentity(c *gin.Context, config domain.AuthorizationConfiguration) (domain.Identity, bool) {
+	if apiKey, ok := GetAuthAPIKey(c); ok {
+		identity := domain.Identity{Subject: "api-key:" + apiKey.Reference}
+		for _, scope := range apiKey.Scopes {
+			identity.Permissions = appendMissing(identity.Permissions, domain.APIKeyScopePermissions[scope]...)
+		}
+		return identity, true
+	}
+
+	identity := domain.Identity{}
+	if config.IdentitySource == domain.IdentitySourceHeader {
+		identity.Subject = strings.TrimSpace(c.GetHeader(config.SubjectHeader))
+		for _, role := range strings.Split(c.GetHeader(config.RolesHeader), ",") {
+			if role = strings.TrimSpace(role); len(role) > 0 {
+				identity.Roles = append(identity.Roles, role)
+			}
+		}
+	} else if claims, ok := GetAuthClaims(c); ok {
+		identity.Subject = claims.Subject
+		identity.Roles = claims.Roles
+	}
+	if len(identity.Subject) == 0 {
+		return domain.Identity{}, false
+	}
+
+	// Unknown roles grant no permissions
+	for _, role := range identity.Roles {
+		identity.Permissions = appendMissing(identity.Permissions, config.Roles[role]...)
+	}
+	return identity, true
+}
+
+func validateAuthorizationConfiguration(config domain.AuthorizationConfiguration) error {
+	switch config.IdentitySource {
+	case domain.IdentitySourceToken:
+	case domain.IdentitySourceHeader:
+		if len(config.SubjectHeader) == 0 || len(config.RolesHeader) == 0 {
+			return errors.New("authorization subject and roles headers are required")
+		}
+	default:
+		return fmt.Errorf("authorization identity source %s is not supported", config.IdentitySource)
+	}
+
+	for role, permissions := range config.Roles {
+		for _, permission := range permissions {
+			if !containsString(domain.Permissions, permission) {
+				return fmt.Errorf("permission %s of role %s is not valid", permission, role)
+			}
+		}
+	}
+
+	return nil
+}
+
+func appendMissing(values []string, items ...string) []string {
+	for _, item := range items {
+		if !containsString(values, item) {
+			values = append(values, item)
+		}
+	}
+	return values
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,183 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/desarrollogj/golang-api-example/auth"
+	"github.com/desarrollogj/golang-api-example/domain"
+	libErrors "github.com/desarrollogj/golang-api-example/libs/errors"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func newAuthorizationConfigurationMock(source string) domain.AuthorizationConfiguration {
+	return domain.AuthorizationConfiguration{
+		Enabled:        true,
+		IdentitySource: source,
+		SubjectHeader:  "X-Auth-Subject",
+		RolesHeader:    "X-Auth-Roles",
+		Roles: map[string][]string{
+			"viewer": {domain.PermissionUsersRead},
+			"admin":  {domain.PermissionUsersRead, domain.PermissionUsersWrite, domain.PermissionUsersAdmin},
+		},
+	}
+}
+
+// authorizationTestRouter creates a router whose caller is authenticated by the setup middleware
+func authorizationTestRouter(t *testing.T, config domain.AuthorizationConfiguration, setup gin.HandlerFunc) *gin.Engine {
+	identity, err := NewIdentityMiddleware(config)
+	assert.Nil(t, err)
+
+	r := testRouter()
+	api := r.Group("/api/v1", setup, identity)
+	api.GET("/users", Authorize(domain.PermissionUsersRead), func(c *gin.Context) {
+		identity, _ := GetIdentity(c)
+		c.String(http.StatusOK, identity.Subject)
+	})
+	api.DELETE("/users/:id", Authorize(domain.PermissionUsersAdmin), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	return r
+}
+
+func withClaims(subject string, roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(AuthClaimsKey, auth.AccessTokenClaims{
+			Roles:            roles,
+			RegisteredClaims: jwt.RegisteredClaims{Subject: subject},
+		})
+	}
+}
+
+func TestAuthorization_GivenATokenWithARole_WhenRequest_ThenAuthorizeItsPermissions(t *testing.T) {
+	t.Log("Successfully authorize a request with the permissions of the token roles")
+
+	r := authorizationTestRouter(t, newAuthorizationConfigurationMock(domain.IdentitySourceToken), withClaims("USER1", "viewer"))
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/users", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "USER1", w.Body.String())
+}
+
+func TestAuthorization_GivenATokenWithoutThePermission_WhenRequest_ThenReturnForbiddenResponse(t *testing.T) {
+	t.Log("Failure to authorize a request because the token roles do not grant the permission")
+
+	r := authorizationTestRouter(t, newAuthorizationConfigurationMock(domain.IdentitySourceToken), withClaims("USER1", "viewer", "unknown"))
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/api/v1/users/USER2", nil))
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	var err libErrors.APIError
+	json.NewDecoder(w.Body).Decode(&err)
+
+	assert.Equal(t, "permission users:admin is required", err.Message)
+}
+
+func TestAuthorization_GivenARequestWithoutIdentity_WhenRequest_ThenReturnUnauthorizedResponse(t *testing.T) {
+	t.Log("Failure to authorize a request because the caller identity is missing")
+
+	r := authorizationTestRouter(t, newAuthorizationConfigurationMock(domain.IdentitySourceToken), func(c *gin.Context) {})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/users", nil))
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	var err libErrors.APIError
+	json.NewDecoder(w.Body).Decode(&err)
+
+	assert.Equal(t, "caller identity is required", err.Message)
+}
+
+func TestAuthorization_GivenGatewayHeaders_WhenRequest_ThenAuthorizeTheHeaderRoles(t *testing.T) {
+	t.Log("Successfully authorize a request with the roles of the trusted gateway headers")
+
+	r := authorizationTestRouter(t, newAuthorizationConfigurationMock(domain.IdentitySourceHeader), withClaims("IGNORED", "viewer"))
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodDelete, "/api/v1/users/USER2", nil)
+	req.Header.Set("X-Auth-Subject", "USER1")
+	req.Header.Set("X-Auth-Roles", "viewer, admin")
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/users", nil))
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestAuthorization_GivenAnAPIKey_WhenRequest_ThenAuthorizeItsScopes(t *testing.T) {
+	t.Log("Successfully authorize a request with the permissions of the API key scopes")
+
+	r := authorizationTestRouter(t, newAuthorizationConfigurationMock(domain.IdentitySourceToken), func(c *gin.Context) {
+		c.Set(AuthAPIKeyKey, domain.APIKey{Reference: "KEY1", Scopes: []string{domain.APIKeyScopeRead}})
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/users", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "api-key:KEY1", w.Body.String())
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/api/v1/users/USER2", nil))
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestAuthorization_GivenAnAdminAPIKey_WhenRequest_ThenAuthorizeEveryPermission(t *testing.T) {
+	t.Log("Successfully authorize the read and admin requests of an API key with the admin scope")
+
+	r := authorizationTestRouter(t, newAuthorizationConfigurationMock(domain.IdentitySourceToken), func(c *gin.Context) {
+		c.Set(AuthAPIKeyKey, domain.APIKey{Reference: "KEY1", Scopes: []string{domain.APIKeyScopeAdmin}})
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/users", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/api/v1/users/USER2", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestAuthorization_GivenADisabledAuthorization_WhenRequest_ThenAuthorizeEveryPermission(t *testing.T) {
+	t.Log("Successfully authorize any request when the authorization is disabled")
+
+	r := authorizationTestRouter(t, domain.AuthorizationConfiguration{}, func(c *gin.Context) {})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/api/v1/users/USER2", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestAuthorization_GivenANotValidConfiguration_WhenCreate_ThenReturnAnError(t *testing.T) {
+	t.Log("Failure to create the identity middleware because the configuration is not valid")
+
+	config := newAuthorizationConfigurationMock("cookie")
+	_, err := NewIdentityMiddleware(config)
+	assert.Equal(t, "authorization identity source cookie is not supported", err.Error())
+
+	config = newAuthorizationConfigurationMock(domain.IdentitySourceHeader)
+	config.RolesHeader = ""
+	_, err = NewIdentityMiddleware(config)
+	assert.Equal(t, "authorization subject and roles headers are required", err.Error())
+
+	config = newAuthorizationConfigurationMock(domain.IdentitySourceToken)
+	config.Roles["support"] = []string{"users:delete"}
+	_, err = NewIdentityMiddleware(config)
+	assert.Equal(t, "permission users:delete of role support is not valid", err.Error())
+}
@@ -0,0 +1,48 @@
+package handler
+
+import (
+	"net/http"
+
+	appErrors "github.com/desarrollogj/golang-api-example/libs/errors"
+	appGin "github.com/desarrollogj/golang-api-example/libs/gin"
+	"github.com/desarrollogj/golang-api-example/libs/i18n"
+	"github.com/gin-gonic/gin"
+)
+
+// ErrorDefinitionResponse represents an error code returned by the API
+type ErrorDefinitionResponse struct {
+	Code        string `json:"code"`
+	Status      int    `json:"status"`
+	Message     string `json:"message"`
+	Description string `json:"description"`
+	Retryable   bool   `json:"retryable"`
+}
+
+// ErrorCatalog find the error codes
+// @Tags error
+// @Summary Find the error codes
+// @Description Find the error codes returned by the API, with their HTTP status, default message and if the request can be retried
+// @Produce json
+// @Success 200 {object} []handler.ErrorDefinitionResponse
+// @Router /errors [get]
+func ErrorCatalog(c *gin.Context) {
+	lang := appGin.GetLanguage(c)
+	definitions := appErrors.Definitions()
+
+	response := make([]ErrorDefinitionResponse, 0, len(definitions))
+	for _, definition := range definitions {
+		message, ok := i18n.Text(lang, definition.Code)
+		if !ok {
+			message = definition.Message
+		}
+		response = append(response, ErrorDefinitionResponse{
+			Code:        definition.Code,
+			Status:      definition.Status,
+			Message:     message,
+			Description: definition.Description,
+			Retryable:   definition.Retryable,
+		})
+	}
+
+	c.JSON(http.StatusOK, response)
+}
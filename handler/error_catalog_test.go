@@ -0,0 +1,61 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrorCatalog_GivenARequest_WhenErrorCatalog_ThenReturnTheErrorCodes(t *testing.T) {
+	t.Log("Successfully find the error codes")
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/errors", nil)
+
+	r := testRouter()
+	r.GET("/api/v1/errors", ErrorCatalog)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response []ErrorDefinitionResponse
+	json.NewDecoder(w.Body).Decode(&response)
+
+	assert.NotEmpty(t, response)
+	assert.Equal(t, ErrorDefinitionResponse{
+		Code:        "bad_request",
+		Status:      http.StatusBadRequest,
+		Message:     "invalid request parameters",
+		Description: "The request parameters or body are not valid. The causes have the invalid fields.",
+	}, response[0])
+	assert.Contains(t, response, ErrorDefinitionResponse{
+		Code:        "too_many_requests",
+		Status:      http.StatusTooManyRequests,
+		Message:     "too many requests",
+		Description: "The request was throttled. It can be sent again later.",
+		Retryable:   true,
+	})
+}
+
+func TestErrorCatalog_GivenARequestInSpanish_WhenErrorCatalog_ThenReturnTheMessagesInSpanish(t *testing.T) {
+	t.Log("Successfully find the error codes, with the messages in the accepted language")
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/errors", nil)
+	req.Header.Set("Accept-Language", "es")
+
+	r := testRouter()
+	r.GET("/api/v1/errors", ErrorCatalog)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response []ErrorDefinitionResponse
+	json.NewDecoder(w.Body).Decode(&response)
+
+	assert.Equal(t, "bad_request", response[0].Code)
+	assert.Equal(t, "parámetros de la solicitud no válidos", response[0].Message)
+}
@@ -0,0 +1,346 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/desarrollogj/golang-api-example/domain"
+	"github.com/desarrollogj/golang-api-example/group"
+	appErrors "github.com/desarrollogj/golang-api-example/libs/errors"
+	appGin "github.com/desarrollogj/golang-api-example/libs/gin"
+	"github.com/gin-gonic/gin"
+)
+
+// Group represents the method for group endpoints handlers
+type Group interface {
+	FindAll(c *gin.Context)
+	FindByReference(c *gin.Context)
+	Create(c *gin.Context)
+	Update(c *gin.Context)
+	Delete(c *gin.Context)
+	AddMember(c *gin.Context)
+	RemoveMember(c *gin.Context)
+	FindByMember(c *gin.Context)
+}
+
+// defaultGroup is the default implementation for Group interface
+type defaultGroup struct {
+	mapper          GroupMapper
+	findAll         group.FindAll
+	findByReference group.FindByReference
+	create          group.Create
+	update          group.Update
+	delete          group.Delete
+	addMember       group.AddMember
+	removeMember    group.RemoveMember
+	findByMember    group.FindByMember
+}
+
+// NewDefaultGroup creates a defaultGroup handler
+func NewDefaultGroup(mapper GroupMapper,
+	findAll group.FindAll,
+	findByReference group.FindByReference,
+	create group.Create,
+	update group.Update,
+	delete group.Delete,
+	addMember group.AddMember,
+	removeMember group.RemoveMember,
+	findByMember group.FindByMember) defaultGroup {
+	validate = newValidator()
+	return defaultGroup{
+		mapper:          mapper,
+		findAll:         findAll,
+		findByReference: findByReference,
+		create:          create,
+		update:          update,
+		delete:          delete,
+		addMember:       addMember,
+		removeMember:    removeMember,
+		findByMember:    findByMember}
+}
+
+// FindAll find all groups
+// @Tags group
+// @Summary Find all groups
+// @Description Find all active groups, sorted by name
+// @Produce json
+// @Success 200 {object} []handler.GroupResponse
+// @Failure 401	{object} appErrors.APIError
+// @Failure 403	{object} appErrors.APIError
+// @Failure 500	{object} appErrors.APIError
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /groups [get]
+func (h defaultGroup) FindAll(c *gin.Context) {
+	appGin.ErrorWrapper(h.executeFindAll, c)
+}
+
+func (h defaultGroup) executeFindAll(c *gin.Context) *appErrors.APIError {
+	groups, err := h.findAll.Execute()
+	if err != nil {
+		return appErrors.HandleBusinessError(err)
+	}
+
+	c.JSON(http.StatusOK, h.mapper.MapDomainListToResponseList(groups))
+	return nil
+}
+
+// FindByReference find a group by its id
+// @Tags group
+// @Summary Find a group by its id
+// @Description Find an active group by its id, with its members
+// @Param id path string true "Group id"
+// @Produce json
+// @Success 200 {object} handler.GroupResponse
+// @Failure 400	{object} appErrors.APIError
+// @Failure 401	{object} appErrors.APIError
+// @Failure 403	{object} appErrors.APIError
+// @Failure 404	{object} appErrors.APIError
+// @Failure 500	{object} appErrors.APIError
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /groups/{id} [get]
+func (h defaultGroup) FindByReference(c *gin.Context) {
+	appGin.ErrorWrapper(h.executeFindByReference, c)
+}
+
+func (h defaultGroup) executeFindByReference(c *gin.Context) *appErrors.APIError {
+	reference := c.Param("id")
+	if len(reference) == 0 {
+		return appErrors.NewBadRequest("group id is required").WithKey("group_id_required")
+	}
+
+	found, err := h.findByReference.Execute(reference)
+	if err != nil {
+		return appErrors.HandleBusinessError(err)
+	}
+
+	c.JSON(http.StatusOK, h.mapper.MapDomainToResponse(found))
+	return nil
+}
+
+// Create creates a group
+// @Tags group
+// @Summary Create a group
+// @Description Create a group without members
+// @Param request body handler.GroupCreateRequest true "group data"
+// @Param Idempotency-Key header string false "client key to process the request only once"
+// @Produce json
+// @Success 201 {object} handler.GroupResponse
+// @Failure 400	{object} appErrors.APIError
+// @Failure 401	{object} appErrors.APIError
+// @Failure 403	{object} appErrors.APIError
+// @Failure 422	{object} appErrors.APIError
+// @Failure 500	{object} appErrors.APIError
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /groups [post]
+func (h defaultGroup) Create(c *gin.Context) {
+	appGin.ErrorWrapper(h.executeCreate, c)
+}
+
+func (h defaultGroup) executeCreate(c *gin.Context) *appErrors.APIError {
+	var req GroupCreateRequest
+	if apiErr := bindJSON(c, &req); apiErr != nil {
+		return apiErr
+	}
+
+	created, err := h.create.Execute(h.mapper.MapCreateRequestToInput(req))
+	if err != nil {
+		return appErrors.HandleBusinessError(err)
+	}
+
+	c.JSON(http.StatusCreated, h.mapper.MapDomainToResponse(created))
+	return nil
+}
+
+// Update update a group
+// @Tags group
+// @Summary Update a group
+// @Description Update a group name and description. The members are kept
+// @Param id path string true "Group id"
+// @Param request body handler.GroupUpdateRequest true "group data"
+// @Produce json
+// @Success 200 {object} handler.GroupResponse
+// @Failure 400	{object} appErrors.APIError
+// @Failure 401	{object} appErrors.APIError
+// @Failure 403	{object} appErrors.APIError
+// @Failure 404	{object} appErrors.APIError
+// @Failure 500	{object} appErrors.APIError
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /groups/{id} [put]
+func (h defaultGroup) Update(c *gin.Context) {
+	appGin.ErrorWrapper(h.executeUpdate, c)
+}
+
+func (h defaultGroup) executeUpdate(c *gin.Context) *appErrors.APIError {
+	reference := c.Param("id")
+	if len(reference) == 0 {
+		return appErrors.NewBadRequest("group id is required").WithKey("group_id_required")
+	}
+	var req GroupUpdateRequest
+	if apiErr := bindJSON(c, &req); apiErr != nil {
+		return apiErr
+	}
+
+	updated, err := h.update.Execute(h.mapper.MapUpdateRequestToInput(reference, req))
+	if err != nil {
+		return appErrors.HandleBusinessError(err)
+	}
+
+	c.JSON(http.StatusOK, h.mapper.MapDomainToResponse(updated))
+	return nil
+}
+
+// Delete delete a group
+// @Tags group
+// @Summary Delete a group
+// @Description Delete (inactive) a group
+// @Param id path string true "Group id"
+// @Produce json
+// @Success 200 {object} handler.GroupResponse
+// @Failure 400	{object} appErrors.APIError
+// @Failure 401	{object} appErrors.APIError
+// @Failure 403	{object} appErrors.APIError
+// @Failure 404	{object} appErrors.APIError
+// @Failure 500	{object} appErrors.APIError
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /groups/{id} [delete]
+func (h defaultGroup) Delete(c *gin.Context) {
+	appGin.ErrorWrapper(h.executeDelete, c)
+}
+
+func (h defaultGroup) executeDelete(c *gin.Context) *appErrors.APIError {
+	reference := c.Param("id")
+	if len(reference) == 0 {
+		return appErrors.NewBadRequest("group id is required").WithKey("group_id_required")
+	}
+
+	deleted, err := h.delete.Execute(reference)
+	if err != nil {
+		return appErrors.HandleBusinessError(err)
+	}
+
+	c.JSON(http.StatusOK, h.mapper.MapDomainToResponse(deleted))
+	return nil
+}
+
+// AddMember add an user to a group
+// @Tags group
+// @Summary Add a group member
+// @Description Add an active user to a group. Adding a current member has no effect
+// @Param id path string true "Group id"
+// @Param userId path string true "User id"
+// @Produce json
+// @Success 200 {object} handler.GroupResponse
+// @Failure 400	{object} appErrors.APIError
+// @Failure 401	{object} appErrors.APIError
+// @Failure 403	{object} appErrors.APIError
+// @Failure 404	{object} appErrors.APIError
+// @Failure 500	{object} appErrors.APIError
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /groups/{id}/members/{userId} [put]
+func (h defaultGroup) AddMember(c *gin.Context) {
+	appGin.ErrorWrapper(h.executeAddMember, c)
+}
+
+func (h defaultGroup) executeAddMember(c *gin.Context) *appErrors.APIError {
+	input, apiErr := h.memberInput(c)
+	if apiErr != nil {
+		return apiErr
+	}
+
+	updated, err := h.addMember.Execute(input)
+	if err != nil {
+		return appErrors.HandleBusinessError(err)
+	}
+
+	c.JSON(http.StatusOK, h.mapper.MapDomainToResponse(updated))
+	return nil
+}
+
+// RemoveMember remove an user from a group
+// @Tags group
+// @Summary Remove a group member
+// @Description Remove an user from a group
+// @Param id path string true "Group id"
+// @Param userId path string true "User id"
+// @Produce json
+// @Success 200 {object} handler.GroupResponse
+// @Failure 400	{object} appErrors.APIError
+// @Failure 401	{object} appErrors.APIError
+// @Failure 403	{object} appErrors.APIError
+// @Failure 404	{object} appErrors.APIError
+// @Failure 500	{object} appErrors.APIError
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /groups/{id}/members/{userId} [delete]
+func (h defaultGroup) RemoveMember(c *gin.Context) {
+	appGin.ErrorWrapper(h.executeRemoveMember, c)
+}
+
+func (h defaultGroup) executeRemoveMember(c *gin.Context) *appErrors.APIError {
+	input, apiErr := h.memberInput(c)
+	if apiErr != nil {
+		return apiErr
+	}
+
+	updated, err := h.removeMember.Execute(input)
+	if err != nil {
+		return appErrors.HandleBusinessError(err)
+	}
+
+	c.JSON(http.StatusOK, h.mapper.MapDomainToResponse(updated))
+	return nil
+}
+
+// FindByMember find the groups of an user
+// @Tags group
+// @Summary Find the groups of an user
+// @Description Find the active groups an active user belongs to
+// @Param id path string true "User id"
+// @Produce json
+// @Success 200 {object} []handler.GroupResponse
+// @Failure 400	{object} appErrors.APIError
+// @Failure 401	{object} appErrors.APIError
+// @Failure 403	{object} appErrors.APIError
+// @Failure 404	{object} appErrors.APIError
+// @Failure 500	{object} appErrors.APIError
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /users/{id}/groups [get]
+func (h defaultGroup) FindByMember(c *gin.Context) {
+	appGin.ErrorWrapper(h.executeFindByMember, c)
+}
+
+func (h defaultGroup) executeFindByMember(c *gin.Context) *appErrors.APIError {
+	reference := c.Param("id")
+	if len(reference) == 0 {
+		return appErrors.NewBadRequest("user id is required").WithKey("user_id_required")
+	}
+
+	groups, err := h.findByMember.Execute(reference)
+	if err != nil {
+		return appErrors.HandleBusinessError(err)
+	}
+
+	c.JSON(http.StatusOK, h.mapper.MapDomainListToResponseList(groups))
+	return nil
+}
+
+func (h defaultGroup) memberInput(c *gin.Context) (domain.GroupMemberInput, *appErrors.APIError) {
+	input := domain.GroupMemberInput{
+		GroupReference: c.Param("id"),
+		UserReference:  c.Param("userId"),
+	}
+	if len(input.GroupReference) == 0 {
+		return domain.GroupMemberInput{}, appErrors.NewBadRequest("group id is required").WithKey("group_id_required")
+	}
+	if len(input.UserReference) == 0 {
+		return domain.GroupMemberInput{}, appErrors.NewBadRequest("user id is required").WithKey("user_id_required")
+	}
+
+	return input, nil
+}
@@ -0,0 +1,97 @@
+package handler
+
+import (
+	"strings"
+	"time"
+
+	"github.com/desarrollogj/golang-api-example/domain"
+)
+
+type GroupResponse struct {
+	Id          string                `json:"id"`
+	Name        string                `json:"name"`
+	Description string                `json:"description,omitempty"`
+	Members     []GroupMemberResponse `json:"members"`
+	IsActive    bool                  `json:"isActive"`
+	CreatedDate string                `json:"created"`
+	UpdatedDate string                `json:"updated"`
+}
+
+type GroupMemberResponse struct {
+	UserId    string `json:"userId"`
+	AddedDate string `json:"added"`
+}
+
+type GroupCreateRequest struct {
+	Name        string `json:"name" validate:"required,max=100"`
+	Description string `json:"description" validate:"max=500"`
+}
+
+type GroupUpdateRequest struct {
+	GroupCreateRequest
+}
+
+// GroupMapper represents the method for group mappers
+type GroupMapper interface {
+	MapDomainToResponse(group domain.Group) GroupResponse
+	MapDomainListToResponseList(groups []domain.Group) []GroupResponse
+	MapCreateRequestToInput(request GroupCreateRequest) domain.GroupCreateInput
+	MapUpdateRequestToInput(reference string, request GroupUpdateRequest) domain.GroupUpdateInput
+}
+
+// defaultGroupMapper is the default implementation for GroupMapper interface
+type defaultGroupMapper struct {
+}
+
+// NewDefaultGroupMapper creates a defaultGroupMapper handler
+func NewDefaultGroupMapper() defaultGroupMapper {
+	return defaultGroupMapper{}
+}
+
+// MapDomainToResponse map a domain group to a response
+func (m defaultGroupMapper) MapDomainToResponse(group domain.Group) GroupResponse {
+	members := []GroupMemberResponse{}
+	for _, member := range group.Members {
+		members = append(members, GroupMemberResponse{
+			UserId:    member.UserReference,
+			AddedDate: member.AddedDate.UTC().Format(time.RFC3339),
+		})
+	}
+
+	return GroupResponse{
+		Id:          group.Reference,
+		Name:        group.Name,
+		Description: group.Description,
+		Members:     members,
+		IsActive:    group.IsActive,
+		CreatedDate: group.CreatedDate.UTC().Format(time.RFC3339),
+		UpdatedDate: group.UpdatedDate.UTC().Format(time.RFC3339),
+	}
+}
+
+// MapDomainListToResponseList map a list of domain groups to a response list
+func (m defaultGroupMapper) MapDomainListToResponseList(groups []domain.Group) []GroupResponse {
+	groupsResponse := []GroupResponse{}
+
+	for _, group := range groups {
+		groupsResponse = append(groupsResponse, m.MapDomainToResponse(group))
+	}
+
+	return groupsResponse
+}
+
+// MapCreateRequestToInput map create request to an input struct
+func (m defaultGroupMapper) MapCreateRequestToInput(request GroupCreateRequest) domain.GroupCreateInput {
+	return domain.GroupCreateInput{
+		Name:        strings.TrimSpace(request.Name),
+		Description: strings.TrimSpace(request.Description),
+	}
+}
+
+// MapUpdateRequestToInput map update request to an input struct
+func (m defaultGroupMapper) MapUpdateRequestToInput(reference string, request GroupUpdateRequest) domain.GroupUpdateInput {
+	return domain.GroupUpdateInput{
+		GroupCreateInput: m.MapCreateRequestToInput(request.GroupCreateRequest),
+		Reference:        strings.TrimSpace(reference),
+	}
+}
@@ -0,0 +1,55 @@
+package handler
+
+import (
+	"testing"
+	"time"
+
+	"github.com/desarrollogj/golang-api-example/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGroupMapper_GivenADomainGroup_WhenMapToResponse_ThenReturnAGroupResponse(t *testing.T) {
+	t.Log("Should map a domain group to a response")
+
+	current := time.Date(2023, 2, 1, 23, 58, 18, 0, time.UTC)
+	group := domain.Group{
+		GenericEntity: domain.GenericEntity{
+			Reference:   "GROUP1",
+			IsActive:    true,
+			CreatedDate: current,
+			UpdatedDate: current,
+		},
+		Name:        "Backend",
+		Description: "Backend team",
+		Members:     []domain.GroupMember{{UserReference: "USER1", AddedDate: current}},
+	}
+	expected := GroupResponse{
+		Id:          "GROUP1",
+		Name:        "Backend",
+		Description: "Backend team",
+		Members:     []GroupMemberResponse{{UserId: "USER1", AddedDate: "2023-02-01T23:58:18Z"}},
+		IsActive:    true,
+		CreatedDate: "2023-02-01T23:58:18Z",
+		UpdatedDate: "2023-02-01T23:58:18Z",
+	}
+
+	mapper := NewDefaultGroupMapper()
+
+	assert.Equal(t, expected, mapper.MapDomainToResponse(group))
+	assert.Equal(t, []GroupResponse{expected}, mapper.MapDomainListToResponseList([]domain.Group{group}))
+	assert.Equal(t, []GroupMemberResponse{}, mapper.MapDomainToResponse(domain.Group{}).Members)
+}
+
+func TestGroupMapper_GivenAnUpdateRequest_WhenMapToInput_ThenReturnTrimmedInput(t *testing.T) {
+	t.Log("Should map a group update request to a trimmed input")
+
+	request := GroupUpdateRequest{GroupCreateRequest: GroupCreateRequest{Name: " Backend ", Description: " Backend team "}}
+	expected := domain.GroupUpdateInput{
+		GroupCreateInput: domain.GroupCreateInput{Name: "Backend", Description: "Backend team"},
+		Reference:        "GROUP1",
+	}
+
+	mapper := NewDefaultGroupMapper()
+
+	assert.Equal(t, expected, mapper.MapUpdateRequestToInput(" GROUP1 ", request))
+}
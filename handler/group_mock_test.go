@@ -0,0 +1,163 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/desarrollogj/golang-api-example/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+// Mapper
+type groupMapperMock struct {
+	mock.Mock
+}
+
+func (m *groupMapperMock) MapDomainToResponse(group domain.Group) GroupResponse {
+	args := m.Called(group)
+
+	t, ok := args.Get(0).(GroupResponse)
+	if !ok {
+		return GroupResponse{}
+	}
+
+	return t
+}
+
+func (m *groupMapperMock) MapDomainListToResponseList(groups []domain.Group) []GroupResponse {
+	args := m.Called(groups)
+
+	t, ok := args.Get(0).([]GroupResponse)
+	if !ok {
+		return []GroupResponse{}
+	}
+
+	return t
+}
+
+func (m *groupMapperMock) MapCreateRequestToInput(request GroupCreateRequest) domain.GroupCreateInput {
+	args := m.Called(request)
+
+	t, ok := args.Get(0).(domain.GroupCreateInput)
+	if !ok {
+		return domain.GroupCreateInput{}
+	}
+
+	return t
+}
+
+func (m *groupMapperMock) MapUpdateRequestToInput(reference string, request GroupUpdateRequest) domain.GroupUpdateInput {
+	args := m.Called(reference, request)
+
+	t, ok := args.Get(0).(domain.GroupUpdateInput)
+	if !ok {
+		return domain.GroupUpdateInput{}
+	}
+
+	return t
+}
+
+// Services
+type groupFindAllServiceMock struct {
+	mock.Mock
+}
+
+func (s *groupFindAllServiceMock) Execute() ([]domain.Group, error) {
+	args := s.Called()
+
+	t, ok := args.Get(0).([]domain.Group)
+	if !ok {
+		return []domain.Group{}, errors.New("mock_error")
+	}
+
+	return t, args.Error(1)
+}
+
+type groupFindByReferenceServiceMock struct {
+	mock.Mock
+}
+
+func (s *groupFindByReferenceServiceMock) Execute(reference string) (domain.Group, error) {
+	args := s.Called(reference)
+
+	t, ok := args.Get(0).(domain.Group)
+	if !ok {
+		return domain.Group{}, errors.New("mock_error")
+	}
+
+	return t, args.Error(1)
+}
+
+type groupCreateServiceMock struct {
+	mock.Mock
+}
+
+func (s *groupCreateServiceMock) Execute(input domain.GroupCreateInput) (domain.Group, error) {
+	args := s.Called(input)
+
+	t, ok := args.Get(0).(domain.Group)
+	if !ok {
+		return domain.Group{}, errors.New("mock_error")
+	}
+
+	return t, args.Error(1)
+}
+
+type groupUpdateServiceMock struct {
+	mock.Mock
+}
+
+func (s *groupUpdateServiceMock) Execute(input domain.GroupUpdateInput) (domain.Group, error) {
+	args := s.Called(input)
+
+	t, ok := args.Get(0).(domain.Group)
+	if !ok {
+		return domain.Group{}, errors.New("mock_error")
+	}
+
+	return t, args.Error(1)
+}
+
+type groupDeleteServiceMock struct {
+	mock.Mock
+}
+
+func (s *groupDeleteServiceMock) Execute(reference string) (domain.Group, error) {
+	args := s.Called(reference)
+
+	t, ok := args.Get(0).(domain.Group)
+	if !ok {
+		return domain.Group{}, errors.New("mock_error")
+	}
+
+	return t, args.Error(1)
+}
+
+type groupMemberServiceMock struct {
+	mock.Mock
+}
+
+func (s *groupMemberServiceMock) Execute(input domain.GroupMemberInput) (domain.Group, error) {
+	args := s.Called(input)
+
+	t, ok := args.Get(0).(domain.Group)
+	if !ok {
+		return domain.Group{}, errors.New("mock_error")
+	}
+
+	return t, args.Error(1)
+}
+
+type groupFindByMemberServiceMock struct {
+	mock.Mock
+}
+
+func (s *groupFindByMemberServiceMock) Execute(userReference string) ([]domain.Group, error) {
+	args := s.Called(userReference)
+
+	t, ok := args.Get(0).([]domain.Group)
+	if !ok {
+		return []domain.Group{}, errors.New("mock_error")
+	}
+
+	return t, args.Error(1)
+}
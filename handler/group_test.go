@@ -0,0 +1,251 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/desarrollogj/golang-api-example/domain"
+	libErrors "github.com/desarrollogj/golang-api-example/libs/errors"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type groupServicesMock struct {
+	mapper          *groupMapperMock
+	findAll         *groupFindAllServiceMock
+	findByReference *groupFindByReferenceServiceMock
+	create          *groupCreateServiceMock
+	update          *groupUpdateServiceMock
+	delete          *groupDeleteServiceMock
+	addMember       *groupMemberServiceMock
+	removeMember    *groupMemberServiceMock
+	findByMember    *groupFindByMemberServiceMock
+}
+
+func newGroupServicesMock() groupServicesMock {
+	return groupServicesMock{
+		mapper:          new(groupMapperMock),
+		findAll:         new(groupFindAllServiceMock),
+		findByReference: new(groupFindByReferenceServiceMock),
+		create:          new(groupCreateServiceMock),
+		update:          new(groupUpdateServiceMock),
+		delete:          new(groupDeleteServiceMock),
+		addMember:       new(groupMemberServiceMock),
+		removeMember:    new(groupMemberServiceMock),
+		findByMember:    new(groupFindByMemberServiceMock),
+	}
+}
+
+func (m groupServicesMock) handler() defaultGroup {
+	return NewDefaultGroup(m.mapper,
+		m.findAll,
+		m.findByReference,
+		m.create,
+		m.update,
+		m.delete,
+		m.addMember,
+		m.removeMember,
+		m.findByMember)
+}
+
+func serveGroupRequest(method string, route string, handler gin.HandlerFunc, req *http.Request) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	r := testRouter()
+	r.Handle(method, route, handler)
+	r.ServeHTTP(w, req)
+	return w
+}
+
+func TestGroup_WhenFindAll_ThenReturnGroupListResponse(t *testing.T) {
+	t.Log("Successfully find all groups")
+
+	groups := []domain.Group{{Name: "Backend"}}
+	response := []GroupResponse{{Name: "Backend", Members: []GroupMemberResponse{}}}
+
+	mocks := newGroupServicesMock()
+	mocks.findAll.On("Execute").Return(groups, nil)
+	mocks.mapper.On("MapDomainListToResponseList", groups).Return(response)
+
+	w := serveGroupRequest(http.MethodGet, "/api/v1/groups", mocks.handler().FindAll,
+		httptest.NewRequest(http.MethodGet, "/api/v1/groups", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var result []GroupResponse
+	json.NewDecoder(w.Body).Decode(&result)
+
+	assert.Equal(t, response, result)
+
+	mocks.findAll.AssertExpectations(t)
+}
+
+func TestGroup_GivenAnId_WhenFindByIdAndGroupNotFound_ThenReturnNotFoundResponse(t *testing.T) {
+	t.Log("Failure to find a group by its id because it was not found")
+
+	mocks := newGroupServicesMock()
+	mocks.findByReference.On("Execute", "GROUP1").Return(domain.Group{}, libErrors.NewNotFoundError("group not found"))
+
+	w := serveGroupRequest(http.MethodGet, "/api/v1/groups/:id", mocks.handler().FindByReference,
+		httptest.NewRequest(http.MethodGet, "/api/v1/groups/GROUP1", nil))
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	var err libErrors.APIError
+	json.NewDecoder(w.Body).Decode(&err)
+
+	assert.Equal(t, "group not found", err.Message)
+}
+
+func TestGroup_GivenACreateRequest_WhenCreate_ThenReturnCreatedGroupResponse(t *testing.T) {
+	t.Log("Successfully create a group")
+
+	request := GroupCreateRequest{Name: "Backend", Description: "Backend team"}
+	input := domain.GroupCreateInput{Name: "Backend", Description: "Backend team"}
+	created := domain.Group{GenericEntity: domain.GenericEntity{Reference: "GROUP1"}, Name: "Backend"}
+	response := GroupResponse{Id: "GROUP1", Name: "Backend", Members: []GroupMemberResponse{}}
+
+	mocks := newGroupServicesMock()
+	mocks.mapper.On("MapCreateRequestToInput", request).Return(input)
+	mocks.mapper.On("MapDomainToResponse", created).Return(response)
+	mocks.create.On("Execute", input).Return(created, nil)
+
+	w := serveGroupRequest(http.MethodPost, "/api/v1/groups", mocks.handler().Create,
+		httptest.NewRequest(http.MethodPost, "/api/v1/groups", bytes.NewBufferString(`{"name":"Backend","description":"Backend team"}`)))
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+
+	var result GroupResponse
+	json.NewDecoder(w.Body).Decode(&result)
+
+	assert.Equal(t, response, result)
+
+	mocks.mapper.AssertExpectations(t)
+	mocks.create.AssertExpectations(t)
+}
+
+func TestGroup_GivenACreateRequestWithoutName_WhenCreate_ThenReturnBadRequestResponse(t *testing.T) {
+	t.Log("Failure to create a group because the name is missing")
+
+	mocks := newGroupServicesMock()
+
+	w := serveGroupRequest(http.MethodPost, "/api/v1/groups", mocks.handler().Create,
+		httptest.NewRequest(http.MethodPost, "/api/v1/groups", bytes.NewBufferString(`{"description":"Backend team"}`)))
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	mocks.create.AssertNotCalled(t, "Execute", mock.Anything)
+}
+
+func TestGroup_GivenAnUpdateRequest_WhenUpdate_ThenReturnUpdatedGroupResponse(t *testing.T) {
+	t.Log("Successfully update a group")
+
+	request := GroupUpdateRequest{GroupCreateRequest: GroupCreateRequest{Name: "Platform"}}
+	input := domain.GroupUpdateInput{GroupCreateInput: domain.GroupCreateInput{Name: "Platform"}, Reference: "GROUP1"}
+	updated := domain.Group{GenericEntity: domain.GenericEntity{Reference: "GROUP1"}, Name: "Platform"}
+	response := GroupResponse{Id: "GROUP1", Name: "Platform", Members: []GroupMemberResponse{}}
+
+	mocks := newGroupServicesMock()
+	mocks.mapper.On("MapUpdateRequestToInput", "GROUP1", request).Return(input)
+	mocks.mapper.On("MapDomainToResponse", updated).Return(response)
+	mocks.update.On("Execute", input).Return(updated, nil)
+
+	w := serveGroupRequest(http.MethodPut, "/api/v1/groups/:id", mocks.handler().Update,
+		httptest.NewRequest(http.MethodPut, "/api/v1/groups/GROUP1", bytes.NewBufferString(`{"name":"Platform"}`)))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	mocks.update.AssertExpectations(t)
+}
+
+func TestGroup_GivenAnId_WhenDelete_ThenReturnDeletedGroupResponse(t *testing.T) {
+	t.Log("Successfully delete a group")
+
+	deleted := domain.Group{GenericEntity: domain.GenericEntity{Reference: "GROUP1"}}
+	response := GroupResponse{Id: "GROUP1", Members: []GroupMemberResponse{}}
+
+	mocks := newGroupServicesMock()
+	mocks.mapper.On("MapDomainToResponse", deleted).Return(response)
+	mocks.delete.On("Execute", "GROUP1").Return(deleted, nil)
+
+	w := serveGroupRequest(http.MethodDelete, "/api/v1/groups/:id", mocks.handler().Delete,
+		httptest.NewRequest(http.MethodDelete, "/api/v1/groups/GROUP1", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	mocks.delete.AssertExpectations(t)
+}
+
+func TestGroup_GivenAGroupAndAnUser_WhenAddMember_ThenReturnUpdatedGroupResponse(t *testing.T) {
+	t.Log("Successfully add a group member")
+
+	input := domain.GroupMemberInput{GroupReference: "GROUP1", UserReference: "USER1"}
+	updated := domain.Group{
+		GenericEntity: domain.GenericEntity{Reference: "GROUP1"},
+		Members:       []domain.GroupMember{{UserReference: "USER1"}},
+	}
+	response := GroupResponse{Id: "GROUP1", Members: []GroupMemberResponse{{UserId: "USER1"}}}
+
+	mocks := newGroupServicesMock()
+	mocks.mapper.On("MapDomainToResponse", updated).Return(response)
+	mocks.addMember.On("Execute", input).Return(updated, nil)
+
+	w := serveGroupRequest(http.MethodPut, "/api/v1/groups/:id/members/:userId", mocks.handler().AddMember,
+		httptest.NewRequest(http.MethodPut, "/api/v1/groups/GROUP1/members/USER1", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var result GroupResponse
+	json.NewDecoder(w.Body).Decode(&result)
+
+	assert.Equal(t, response, result)
+
+	mocks.addMember.AssertExpectations(t)
+}
+
+func TestGroup_GivenAnUserThatIsNotAMember_WhenRemoveMember_ThenReturnNotFoundResponse(t *testing.T) {
+	t.Log("Failure to remove a group member because the user is not a member")
+
+	input := domain.GroupMemberInput{GroupReference: "GROUP1", UserReference: "USER1"}
+
+	mocks := newGroupServicesMock()
+	mocks.removeMember.On("Execute", input).Return(domain.Group{}, libErrors.NewNotFoundError("group member not found"))
+
+	w := serveGroupRequest(http.MethodDelete, "/api/v1/groups/:id/members/:userId", mocks.handler().RemoveMember,
+		httptest.NewRequest(http.MethodDelete, "/api/v1/groups/GROUP1/members/USER1", nil))
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	var err libErrors.APIError
+	json.NewDecoder(w.Body).Decode(&err)
+
+	assert.Equal(t, "group member not found", err.Message)
+
+	mocks.removeMember.AssertExpectations(t)
+}
+
+func TestGroup_GivenAnUserId_WhenFindByMember_ThenReturnGroupListResponse(t *testing.T) {
+	t.Log("Successfully find the groups of an user")
+
+	groups := []domain.Group{{Name: "Backend"}}
+	response := []GroupResponse{{Name: "Backend", Members: []GroupMemberResponse{}}}
+
+	mocks := newGroupServicesMock()
+	mocks.findByMember.On("Execute", "USER1").Return(groups, nil)
+	mocks.mapper.On("MapDomainListToResponseList", groups).Return(response)
+
+	w := serveGroupRequest(http.MethodGet, "/api/v1/users/:id/groups", mocks.handler().FindByMember,
+		httptest.NewRequest(http.MethodGet, "/api/v1/users/USER1/groups", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var result []GroupResponse
+	json.NewDecoder(w.Body).Decode(&result)
+
+	assert.Equal(t, response, result)
+
+	mocks.findByMember.AssertExpectations(t)
+}
@@ -1,6 +1,9 @@
 package handler
 
-import "github.com/gin-gonic/gin"
+import (
+	"github.com/desarrollogj/golang-api-example/domain"
+	"github.com/gin-gonic/gin"
+)
 
 func testRouter() *gin.Engine {
 	gin.SetMode(gin.TestMode)
@@ -8,3 +11,10 @@ func testRouter() *gin.Engine {
 	router.HandleMethodNotAllowed = true
 	return router
 }
+
+// withIdentity creates a middleware that puts a caller identity with the permissions in the context
+func withIdentity(permissions ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(AuthIdentityKey, domain.Identity{Subject: "CALLER", Permissions: permissions})
+	}
+}
@@ -3,6 +3,7 @@ package handler
 import (
 	"net/http"
 
+	"github.com/desarrollogj/golang-api-example/libs/server"
 	"github.com/desarrollogj/golang-api-example/libs/system"
 	"github.com/gin-gonic/gin"
 )
@@ -20,3 +21,16 @@ func Health(ctx *gin.Context) {
 		system.GetEnv("APP_VERSION", "UNKNOWN"),
 	})
 }
+
+// Readiness returns if the api accepts requests. It is not ready while the server shuts down, so the load balancers
+// stop sending requests before the server stops
+func Readiness(ctx *gin.Context) {
+	status, code := "READY", http.StatusOK
+	if !server.IsReady() {
+		status, code = "NOT_READY", http.StatusServiceUnavailable
+	}
+
+	ctx.JSON(code, struct {
+		Status string `json:"status"`
+	}{status})
+}
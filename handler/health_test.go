@@ -25,3 +25,20 @@ func TestHealthSuccess(t *testing.T) {
 
 	assert.Equal(t, "{\"status\":\"OK\",\"environment\":\"LOCAL\",\"app\":\"UNKNOWN\",\"version\":\"UNKNOWN\"}", string(bodyBytes))
 }
+
+func TestReadinessNotReady(t *testing.T) {
+	t.Log("Not ready response for readiness check when the server is not started or is shutting down")
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/ready", nil)
+
+	r := testRouter()
+	r.GET("/ready", Readiness)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+
+	bodyBytes, _ := io.ReadAll(w.Body)
+
+	assert.Equal(t, "{\"status\":\"NOT_READY\"}", string(bodyBytes))
+}
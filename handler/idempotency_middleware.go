@@ -0,0 +1,150 @@
+package handler
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/desarrollogj/golang-api-example/domain"
+	"github.com/desarrollogj/golang-api-example/idempotency"
+	appErrors "github.com/desarrollogj/golang-api-example/libs/errors"
+	appGin "github.com/desarrollogj/golang-api-example/libs/gin"
+	"github.com/desarrollogj/golang-api-example/libs/logger"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// IdempotencyKeyHeader is the header with the client key of a request that must be processed only once
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotentReplayedHeader is the header added to a response replayed from a previous request with the same key
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+	// maxIdempotencyKeyLength is the maximum length of an idempotency key
+	maxIdempotencyKeyLength = 255
+)
+
+// idempotencySkippedHeaders are the response headers that are not stored to be replayed
+var idempotencySkippedHeaders = map[string]bool{
+	"Date":           true,
+	"Content-Length": true,
+	"Set-Cookie":     true,
+}
+
+// NewIdempotencyMiddleware creates a route middleware that processes the POST requests with an idempotency key only once.
+// The first response is stored and replayed for the retries with the same key, which is scoped by the caller.
+// Server errors are not stored, so the request can be retried with the same key. The stored response keeps the
+// reference of its resource, so it can be removed when its user is erased
+func NewIdempotencyMiddleware(keeper idempotency.Keeper) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyKeyHeader)
+		if c.Request.Method != http.MethodPost || len(key) == 0 {
+			c.Next()
+			return
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			err := appErrors.NewBadRequest("idempotency key is too long").WithKey("idempotency_key_too_long")
+			appGin.AbortWithError(c, err)
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			apiErr := appErrors.NewBadRequest("unable to read the request body").WithKey("request_body_unreadable")
+			appGin.AbortWithError(c, apiErr)
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		identity, _ := GetIdentity(c)
+		// The response format and language depend on the request headers, so a retry must send the same ones
+		fingerprint := hashValues(c.Request.Method, c.Request.RequestURI, c.GetHeader("Accept"), c.GetHeader("Accept-Language"), string(body))
+		record, err := keeper.Begin(hashValues(identity.Subject, key), fingerprint)
+		if err != nil {
+			apiErr := appErrors.HandleBusinessError(err)
+			appGin.AbortWithError(c, apiErr)
+			return
+		}
+		if record.Status == domain.IdempotencyStatusCompleted {
+			replayResponse(c, record)
+			return
+		}
+
+		writer := &bufferedResponseWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+		c.Next()
+
+		status := c.Writer.Status()
+		if status >= http.StatusInternalServerError || status == http.StatusTooManyRequests {
+			_ = keeper.Release(record)
+			return
+		}
+
+		record.ResponseStatus = status
+		record.ResponseHeaders = map[string][]string{}
+		for name, values := range c.Writer.Header() {
+			if !idempotencySkippedHeaders[http.CanonicalHeaderKey(name)] {
+				record.ResponseHeaders[name] = values
+			}
+		}
+		record.ResponseBody = writer.body.Bytes()
+		record.ResourceReference = responseResource(c, record.ResponseBody)
+		if err := keeper.Complete(record); err != nil {
+			logger.AppLog.Error().Err(err).Msg("unable to store the idempotent response")
+		}
+	}
+}
+
+// responseResource returns the reference of the resource a response is about: the resource of the route,
+// or the id of the created resource
+func responseResource(c *gin.Context, body []byte) string {
+	if reference := c.Param("id"); len(reference) > 0 {
+		return reference
+	}
+
+	var resource struct {
+		ID string `json:"id"`
+	}
+	_ = json.Unmarshal(body, &resource)
+	return resource.ID
+}
+
+// replayResponse writes the stored response of a completed request
+func replayResponse(c *gin.Context, record domain.IdempotencyRecord) {
+	for name, values := range record.ResponseHeaders {
+		for _, value := range values {
+			c.Writer.Header().Add(name, value)
+		}
+	}
+	c.Header(IdempotentReplayedHeader, "true")
+	c.Data(record.ResponseStatus, c.Writer.Header().Get("Content-Type"), record.ResponseBody)
+	c.Abort()
+}
+
+// hashValues returns the hex encoded SHA-256 of the values
+func hashValues(values ...string) string {
+	h := sha256.New()
+	for _, value := range values {
+		h.Write([]byte(value))
+		// Separate the values, so different splits of the same text have different hashes
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// bufferedResponseWriter writes the response and keeps a copy of its body
+type bufferedResponseWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *bufferedResponseWriter) Write(data []byte) (int, error) {
+	w.body.Write(data)
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *bufferedResponseWriter) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
@@ -0,0 +1,191 @@
+package handler
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/desarrollogj/golang-api-example/domain"
+	libErrors "github.com/desarrollogj/golang-api-example/libs/errors"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// idempotencyTestRouter creates a router whose POST handler echoes the request body with the status
+func idempotencyTestRouter(keeper *idempotencyKeeperMock, status int) *gin.Engine {
+	r := testRouter()
+	r.POST("/api/v1/users", withIdentity(domain.PermissionUsersWrite), NewIdempotencyMiddleware(keeper), func(c *gin.Context) {
+		body, _ := io.ReadAll(c.Request.Body)
+		c.Header("Location", "/api/v1/users/USER1")
+		c.Data(status, "application/json", body)
+	})
+	return r
+}
+
+func newIdempotentRequest(key string, body string) *http.Request {
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/users", strings.NewReader(body))
+	req.Header.Set(IdempotencyKeyHeader, key)
+	return req
+}
+
+func TestIdempotency_GivenANewKey_WhenRequest_ThenStoreTheResponse(t *testing.T) {
+	t.Log("Successfully process a request with a new idempotency key and store its response")
+
+	processing := domain.IdempotencyRecord{Key: hashValues("CALLER", "KEY1"), Status: domain.IdempotencyStatusProcessing}
+	keeperMock := new(idempotencyKeeperMock)
+	keeperMock.On("Begin", hashValues("CALLER", "KEY1"), hashValues(http.MethodPost, "/api/v1/users", "", "", `{"name":"John"}`)).
+		Return(processing, nil)
+	keeperMock.On("Complete", mock.MatchedBy(func(record domain.IdempotencyRecord) bool {
+		return record.ResponseStatus == http.StatusCreated &&
+			string(record.ResponseBody) == `{"name":"John"}` &&
+			record.ResponseHeaders["Location"][0] == "/api/v1/users/USER1" &&
+			record.ResponseHeaders["Content-Length"] == nil
+	})).Return(nil)
+
+	r := idempotencyTestRouter(keeperMock, http.StatusCreated)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, newIdempotentRequest("KEY1", `{"name":"John"}`))
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, `{"name":"John"}`, w.Body.String())
+	assert.Empty(t, w.Header().Get(IdempotentReplayedHeader))
+	keeperMock.AssertExpectations(t)
+}
+
+func TestIdempotency_GivenRequestsWithDifferentLanguages_WhenRequest_ThenUseDifferentFingerprints(t *testing.T) {
+	t.Log("Successfully fingerprint the response format and language headers, so a retry can not replay another language")
+
+	processing := domain.IdempotencyRecord{Key: hashValues("CALLER", "KEY1"), Status: domain.IdempotencyStatusProcessing}
+	keeperMock := new(idempotencyKeeperMock)
+	keeperMock.On("Begin", hashValues("CALLER", "KEY1"), hashValues(http.MethodPost, "/api/v1/users", "application/json", "es", `{"name":"John"}`)).
+		Return(processing, nil).Once()
+	keeperMock.On("Begin", hashValues("CALLER", "KEY1"), hashValues(http.MethodPost, "/api/v1/users", "application/json", "en", `{"name":"John"}`)).
+		Return(domain.IdempotencyRecord{}, libErrors.NewUnprocessableError("idempotency key was already used with a different request")).Once()
+	keeperMock.On("Complete", mock.AnythingOfType("IdempotencyRecord")).Return(nil)
+
+	r := idempotencyTestRouter(keeperMock, http.StatusCreated)
+
+	for _, lang := range []string{"es", "en"} {
+		req := newIdempotentRequest("KEY1", `{"name":"John"}`)
+		req.Header.Set("Accept", "application/json")
+		req.Header.Set("Accept-Language", lang)
+		r.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	keeperMock.AssertExpectations(t)
+}
+
+func TestIdempotency_GivenACreatedResource_WhenRequest_ThenStoreItsReference(t *testing.T) {
+	t.Log("Successfully store the reference of the created resource, so its response can be removed when its user is erased")
+
+	keeperMock := new(idempotencyKeeperMock)
+	keeperMock.On("Begin", mock.AnythingOfType("string"), mock.AnythingOfType("string")).
+		Return(domain.IdempotencyRecord{Status: domain.IdempotencyStatusProcessing}, nil)
+	keeperMock.On("Complete", mock.MatchedBy(func(record domain.IdempotencyRecord) bool {
+		return record.ResourceReference == "USER1"
+	})).Return(nil)
+
+	r := idempotencyTestRouter(keeperMock, http.StatusCreated)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, newIdempotentRequest("KEY1", `{"id":"USER1","email":"foo@email.com"}`))
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	keeperMock.AssertExpectations(t)
+}
+
+func TestIdempotency_GivenACompletedKey_WhenRequest_ThenReplayTheStoredResponse(t *testing.T) {
+	t.Log("Successfully replay the stored response of a request with a used idempotency key")
+
+	completed := domain.IdempotencyRecord{
+		Key:             hashValues("CALLER", "KEY1"),
+		Status:          domain.IdempotencyStatusCompleted,
+		ResponseStatus:  http.StatusCreated,
+		ResponseHeaders: map[string][]string{"Content-Type": {"application/json"}, "Location": {"/api/v1/users/USER1"}},
+		ResponseBody:    []byte(`{"reference":"USER1"}`),
+	}
+	keeperMock := new(idempotencyKeeperMock)
+	keeperMock.On("Begin", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(completed, nil)
+
+	r := idempotencyTestRouter(keeperMock, http.StatusCreated)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, newIdempotentRequest("KEY1", `{"name":"John"}`))
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, `{"reference":"USER1"}`, w.Body.String())
+	assert.Equal(t, "/api/v1/users/USER1", w.Header().Get("Location"))
+	assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	assert.Equal(t, "true", w.Header().Get(IdempotentReplayedHeader))
+	keeperMock.AssertNotCalled(t, "Complete", mock.Anything)
+}
+
+func TestIdempotency_GivenAKeyUsedByAnotherRequest_WhenRequest_ThenReturnUnprocessableEntityResponse(t *testing.T) {
+	t.Log("Failure to process a request because its idempotency key was used with a different request")
+
+	keeperMock := new(idempotencyKeeperMock)
+	keeperMock.On("Begin", mock.AnythingOfType("string"), mock.AnythingOfType("string")).
+		Return(domain.IdempotencyRecord{}, libErrors.NewUnprocessableError("idempotency key was already used with a different request"))
+
+	r := idempotencyTestRouter(keeperMock, http.StatusCreated)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, newIdempotentRequest("KEY1", `{"name":"Jane"}`))
+
+	assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+
+	var err libErrors.APIError
+	json.NewDecoder(w.Body).Decode(&err)
+	assert.Equal(t, "idempotency key was already used with a different request", err.Message)
+}
+
+func TestIdempotency_GivenANewKey_WhenRequestFailedWithAServerError_ThenReleaseTheKey(t *testing.T) {
+	t.Log("Successfully release the idempotency key of a request that failed with a server error")
+
+	processing := domain.IdempotencyRecord{Key: hashValues("CALLER", "KEY1"), Status: domain.IdempotencyStatusProcessing}
+	keeperMock := new(idempotencyKeeperMock)
+	keeperMock.On("Begin", mock.AnythingOfType("string"), mock.AnythingOfType("string")).Return(processing, nil)
+	keeperMock.On("Release", processing).Return(nil)
+
+	r := idempotencyTestRouter(keeperMock, http.StatusInternalServerError)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, newIdempotentRequest("KEY1", `{"name":"John"}`))
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	keeperMock.AssertExpectations(t)
+	keeperMock.AssertNotCalled(t, "Complete", mock.Anything)
+}
+
+func TestIdempotency_GivenNoKey_WhenRequest_ThenProcessItWithoutKeeper(t *testing.T) {
+	t.Log("Successfully process a request without an idempotency key")
+
+	keeperMock := new(idempotencyKeeperMock)
+
+	r := idempotencyTestRouter(keeperMock, http.StatusCreated)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/v1/users", strings.NewReader(`{"name":"John"}`)))
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	keeperMock.AssertNotCalled(t, "Begin", mock.Anything, mock.Anything)
+}
+
+func TestIdempotency_GivenATooLongKey_WhenRequest_ThenReturnBadRequestResponse(t *testing.T) {
+	t.Log("Failure to process a request because its idempotency key is too long")
+
+	keeperMock := new(idempotencyKeeperMock)
+
+	r := idempotencyTestRouter(keeperMock, http.StatusCreated)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, newIdempotentRequest(strings.Repeat("K", 256), `{"name":"John"}`))
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	keeperMock.AssertNotCalled(t, "Begin", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,33 @@
+package handler
+
+import (
+	"errors"
+
+	"github.com/desarrollogj/golang-api-example/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+type idempotencyKeeperMock struct {
+	mock.Mock
+}
+
+func (k *idempotencyKeeperMock) Begin(key string, fingerprint string) (domain.IdempotencyRecord, error) {
+	args := k.Called(key, fingerprint)
+
+	r, ok := args.Get(0).(domain.IdempotencyRecord)
+	if !ok {
+		return domain.IdempotencyRecord{}, errors.New("mock_error")
+	}
+
+	return r, args.Error(1)
+}
+
+func (k *idempotencyKeeperMock) Complete(record domain.IdempotencyRecord) error {
+	args := k.Called(record)
+	return args.Error(0)
+}
+
+func (k *idempotencyKeeperMock) Release(record domain.IdempotencyRecord) error {
+	args := k.Called(record)
+	return args.Error(0)
+}
@@ -0,0 +1,38 @@
+package handler
+
+import (
+	"errors"
+
+	appErrors "github.com/desarrollogj/golang-api-example/libs/errors"
+	jsonpatch "github.com/evanphx/json-patch/v5"
+)
+
+const (
+	MergePatchContentType = "application/merge-patch+json"
+	JSONPatchContentType  = "application/json-patch+json"
+)
+
+// applyPatch applies a JSON Merge Patch (RFC 7396) or a JSON Patch (RFC 6902) to a JSON document,
+// depending on the patch content type
+func applyPatch(contentType string, patch []byte, document []byte) ([]byte, error) {
+	if contentType == MergePatchContentType {
+		patched, err := jsonpatch.MergePatch(document, patch)
+		if err != nil {
+			return nil, appErrors.NewBusinessError("patch document is not valid", appErrors.BadRequestErrorCode).WithKey("patch_not_valid")
+		}
+		return patched, nil
+	}
+
+	operations, err := jsonpatch.DecodePatch(patch)
+	if err != nil {
+		return nil, appErrors.NewBusinessError("patch document is not valid", appErrors.BadRequestErrorCode).WithKey("patch_not_valid")
+	}
+	patched, err := operations.Apply(document)
+	if errors.Is(err, jsonpatch.ErrTestFailed) {
+		return nil, appErrors.NewConflictError("patch test operation failed").WithKey("patch_test_failed")
+	} else if err != nil {
+		return nil, appErrors.NewBusinessError("patch can not be applied", appErrors.BadRequestErrorCode).WithKey("patch_not_applicable")
+	}
+
+	return patched, nil
+}
@@ -0,0 +1,69 @@
+package handler
+
+import (
+	"testing"
+
+	appErrors "github.com/desarrollogj/golang-api-example/libs/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyPatch_GivenAMergePatch_WhenApply_ThenReturnPatchedDocument(t *testing.T) {
+	t.Log("Successfully apply a JSON Merge Patch")
+
+	document := []byte(`{"firstName":"Foo","lastName":"Bar","phone":"+5491112345678"}`)
+	patch := []byte(`{"firstName":"Another Foo","phone":null}`)
+
+	patched, err := applyPatch(MergePatchContentType, patch, document)
+
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"firstName":"Another Foo","lastName":"Bar"}`, string(patched))
+}
+
+func TestApplyPatch_GivenAJSONPatch_WhenApply_ThenReturnPatchedDocument(t *testing.T) {
+	t.Log("Successfully apply a JSON Patch")
+
+	document := []byte(`{"firstName":"Foo","lastName":"Bar"}`)
+	patch := []byte(`[
+		{"op":"test","path":"/firstName","value":"Foo"},
+		{"op":"replace","path":"/firstName","value":"Another Foo"},
+		{"op":"remove","path":"/lastName"}
+	]`)
+
+	patched, err := applyPatch(JSONPatchContentType, patch, document)
+
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"firstName":"Another Foo"}`, string(patched))
+}
+
+func TestApplyPatch_GivenAJSONPatchWithAFailedTest_WhenApply_ThenReturnAConflictError(t *testing.T) {
+	t.Log("Failure to apply a JSON Patch because a test operation failed")
+
+	document := []byte(`{"firstName":"Foo"}`)
+	patch := []byte(`[{"op":"test","path":"/firstName","value":"Bar"},{"op":"replace","path":"/firstName","value":"Another Foo"}]`)
+
+	_, err := applyPatch(JSONPatchContentType, patch, document)
+
+	assert.Equal(t, appErrors.NewConflictError("patch test operation failed").WithKey("patch_test_failed"), err)
+}
+
+func TestApplyPatch_GivenNotValidPatches_WhenApply_ThenReturnABadRequestError(t *testing.T) {
+	t.Log("Failure to apply not valid patches")
+
+	document := []byte(`{"firstName":"Foo"}`)
+	cases := map[string]struct {
+		contentType string
+		patch       string
+		message     string
+		key         string
+	}{
+		"merge patch not json": {MergePatchContentType, `{"firstName":`, "patch document is not valid", "patch_not_valid"},
+		"json patch not array": {JSONPatchContentType, `{"op":"remove","path":"/firstName"}`, "patch document is not valid", "patch_not_valid"},
+		"json patch bad path":  {JSONPatchContentType, `[{"op":"remove","path":"/lastName"}]`, "patch can not be applied", "patch_not_applicable"},
+	}
+
+	for name, c := range cases {
+		_, err := applyPatch(c.contentType, []byte(c.patch), document)
+
+		assert.Equal(t, appErrors.NewBusinessError(c.message, "bad_request").WithKey(c.key), err, name)
+	}
+}
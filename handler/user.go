@@ -1,17 +1,23 @@
 package handler
 
 import (
+	"bytes"
+	"encoding/json"
+	"fmt"
 	"net/http"
+	"net/url"
+	"path"
+	"strings"
 
 	"github.com/desarrollogj/golang-api-example/domain"
 	appErrors "github.com/desarrollogj/golang-api-example/libs/errors"
 	appGin "github.com/desarrollogj/golang-api-example/libs/gin"
 	"github.com/desarrollogj/golang-api-example/user"
 	"github.com/gin-gonic/gin"
-	"github.com/gin-gonic/gin/binding"
-	"github.com/go-playground/validator/v10"
 )
 
+const attributeFilterPrefix = "attr."
+
 // User represents the method for user endpoints handlers
 type User interface {
 	FindAll(c *gin.Context)
@@ -19,6 +25,7 @@ type User interface {
 	Search(c *gin.Context)
 	Create(c *gin.Context)
 	Update(c *gin.Context)
+	Patch(c *gin.Context)
 	Delete(c *gin.Context)
 }
 
@@ -30,6 +37,7 @@ type defaultUser struct {
 	findByReference user.FindByReference
 	create          user.Create
 	update          user.Update
+	patch           user.Patch
 	delete          user.Delete
 	search          user.Search
 }
@@ -41,9 +49,10 @@ func NewDefaultUser(config domain.ApplicationConfiguration,
 	findByReference user.FindByReference,
 	create user.Create,
 	update user.Update,
+	patch user.Patch,
 	delete user.Delete,
 	search user.Search) defaultUser {
-	validate = validator.New()
+	validate = newValidator()
 	return defaultUser{
 		config:          config,
 		mapper:          mapper,
@@ -51,6 +60,7 @@ func NewDefaultUser(config domain.ApplicationConfiguration,
 		findByReference: findByReference,
 		create:          create,
 		update:          update,
+		patch:           patch,
 		delete:          delete,
 		search:          search}
 }
@@ -62,9 +72,13 @@ func NewDefaultUser(config domain.ApplicationConfiguration,
 // @Produce json
 // @Success 200 {object} []handler.UserResponse
 // @Failure 400	{object} appErrors.APIError
+// @Failure 401	{object} appErrors.APIError
+// @Failure 403	{object} appErrors.APIError
 // @Failure 404	{object} appErrors.APIError
 // @Failure 500	{object} appErrors.APIError
-// @Router / [get]
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /users [get]
 func (h defaultUser) FindAll(c *gin.Context) {
 	appGin.ErrorWrapper(h.executeFindAll, c)
 }
@@ -82,14 +96,20 @@ func (h defaultUser) executeFindAll(c *gin.Context) *appErrors.APIError {
 // FindByReference find an user by its id
 // @Tags user
 // @Summary Find an user by its id
-// @Description Find an user by its id
+// @Description Find an user by its id. The id of an user merged into another user redirects to that user
 // @Param id path string true "User id"
+// @Param includeInactive query bool false "Include inactive users (administrators only)"
 // @Produce json
 // @Success 200 {object} handler.UserResponse
+// @Success 301
 // @Failure 400	{object} appErrors.APIError
+// @Failure 401	{object} appErrors.APIError
+// @Failure 403	{object} appErrors.APIError
 // @Failure 404	{object} appErrors.APIError
 // @Failure 500	{object} appErrors.APIError
-// @Router /{id} [get]
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /users/{id} [get]
 func (h defaultUser) FindByReference(c *gin.Context) {
 	appGin.ErrorWrapper(h.executeFindByReference, c)
 }
@@ -97,13 +117,24 @@ func (h defaultUser) FindByReference(c *gin.Context) {
 func (h defaultUser) executeFindByReference(c *gin.Context) *appErrors.APIError {
 	reference := c.Param("id")
 	if len(reference) == 0 {
-		return appErrors.NewBadRequest("user id is required")
+		return appErrors.NewBadRequest("user id is required").WithKey("user_id_required")
+	}
+
+	includeInactive := appGin.GetBoolQuery("includeInactive", c)
+	if includeInactive && !HasPermission(c, domain.PermissionUsersAdmin) {
+		return appErrors.NewForbidden("only administrators can include inactive users").WithKey("include_inactive_forbidden")
 	}
 
-	user, err := h.findByReference.Execute(reference)
+	user, err := h.findByReference.Execute(reference, includeInactive)
 	if err != nil {
 		return appErrors.HandleBusinessError(err)
 	}
+	if user.Reference != reference {
+		// The query is kept, so the redirected request has the same options
+		location := url.URL{Path: path.Join(path.Dir(c.Request.URL.Path), user.Reference), RawQuery: c.Request.URL.RawQuery}
+		c.Redirect(http.StatusMovedPermanently, location.String())
+		return nil
+	}
 
 	c.JSON(http.StatusOK, h.mapper.MapDomainToResponse(user))
 	return nil
@@ -113,17 +144,27 @@ func (h defaultUser) executeFindByReference(c *gin.Context) *appErrors.APIError
 // @Tags user
 // @Summary Search users
 // @Description Search users
-// @Param firstName query string false "User first name"
-// @Param lastName query string false "User last name"
+// @Param firstName query string false "User first name prefix. Not supported when the personal data is encrypted"
+// @Param lastName query string false "User last name prefix. Not supported when the personal data is encrypted"
 // @Param email query string false "User email"
+// @Param country query string false "User address country (ISO 3166-1 alpha-2)"
+// @Param locale query string false "User locale (BCP 47)"
 // @Param page query int false "Page number"
 // @Param size query int false "Page size"
+// @Param includeInactive query bool false "Include inactive users (administrators only)"
+// @Param filter query []string false "Attribute filter, as attr.<name>==<value>" collectionFormat(multi)
+// @Param tags query string false "Comma separated tags, the users must have all of them"
+// @Param anyTag query string false "Comma separated tags, the users must have at least one of them"
 // @Produce json
 // @Success 200 {object} handler.UserSearchResponse
 // @Failure 400	{object} appErrors.APIError
+// @Failure 401	{object} appErrors.APIError
+// @Failure 403	{object} appErrors.APIError
 // @Failure 404	{object} appErrors.APIError
 // @Failure 500	{object} appErrors.APIError
-// @Router /search [get]
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /users/search [get]
 func (h defaultUser) Search(c *gin.Context) {
 	appGin.ErrorWrapper(h.executeSearch, c)
 }
@@ -132,23 +173,39 @@ func (h defaultUser) executeSearch(c *gin.Context) *appErrors.APIError {
 	firstName := c.Query("firstName")
 	lastName := c.Query("lastName")
 	email := c.Query("email")
+	country := c.Query("country")
+	locale := c.Query("locale")
 	page := appGin.GetIntQuery("page", c)
 	size := appGin.GetIntQuery("size", c)
+	includeInactive := appGin.GetBoolQuery("includeInactive", c)
+	if includeInactive && !HasPermission(c, domain.PermissionUsersAdmin) {
+		return appErrors.NewForbidden("only administrators can include inactive users").WithKey("include_inactive_forbidden")
+	}
 	if page < 1 {
 		page = h.config.PagingDefaultPage
 	}
 	if size < 1 {
 		size = h.config.PagingDefaultSize
 	}
+	attributes, apiErr := parseAttributeFilters(c.QueryArray("filter"))
+	if apiErr != nil {
+		return apiErr
+	}
 
 	input := domain.UserSearchInput{
 		SearchInput: domain.SearchInput{
 			Page:     page,
 			PageSize: size,
 		},
-		FirstName: firstName,
-		LastName:  lastName,
-		Email:     email,
+		FirstName:       firstName,
+		LastName:        lastName,
+		Email:           email,
+		Country:         country,
+		Locale:          locale,
+		Attributes:      attributes,
+		Tags:            appGin.GetListQuery("tags", c),
+		AnyTags:         appGin.GetListQuery("anyTag", c),
+		IncludeInactive: includeInactive,
 	}
 	output, err := h.search.Execute(input)
 	if err != nil {
@@ -159,31 +216,48 @@ func (h defaultUser) executeSearch(c *gin.Context) *appErrors.APIError {
 	return nil
 }
 
+// parseAttributeFilters parses the attr.<name>==<value> filters. The values are parsed to the attribute type by the use case
+func parseAttributeFilters(filters []string) (map[string]interface{}, *appErrors.APIError) {
+	if len(filters) == 0 {
+		return nil, nil
+	}
+
+	attributes := map[string]interface{}{}
+	for _, filter := range filters {
+		name, value, ok := strings.Cut(filter, "==")
+		if !ok || !strings.HasPrefix(name, attributeFilterPrefix) || len(name) == len(attributeFilterPrefix) {
+			return nil, appErrors.NewBadRequest(fmt.Sprintf("filter %s is not valid, the format is attr.<name>==<value>", filter)).WithKey("attribute_filter_not_valid", filter)
+		}
+		attributes[strings.TrimPrefix(name, attributeFilterPrefix)] = value
+	}
+	return attributes, nil
+}
+
 // Create creates an user
 // @Tags user
 // @Summary Create an user
 // @Description Create an user
 // @Param request body handler.UserCreateRequest true "user data"
+// @Param Idempotency-Key header string false "client key to process the request only once"
 // @Produce json
 // @Success 201 {object} handler.UserResponse
 // @Failure 400	{object} appErrors.APIError
+// @Failure 401	{object} appErrors.APIError
+// @Failure 403	{object} appErrors.APIError
 // @Failure 404	{object} appErrors.APIError
+// @Failure 422	{object} appErrors.APIError
 // @Failure 500	{object} appErrors.APIError
-// @Router / [post]
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /users [post]
 func (h defaultUser) Create(c *gin.Context) {
 	appGin.ErrorWrapper(h.executeCreate, c)
 }
 
 func (h defaultUser) executeCreate(c *gin.Context) *appErrors.APIError {
 	var req UserCreateRequest
-	err := c.ShouldBindWith(&req, binding.JSON)
-	if err != nil {
-		return appErrors.NewBadRequest("request body is not valid")
-	}
-
-	err = validate.Struct(req)
-	if err != nil {
-		return appErrors.NewBadRequest("request body is not valid")
+	if apiErr := bindJSON(c, &req); apiErr != nil {
+		return apiErr
 	}
 
 	created, err := h.create.Execute(h.mapper.MapCreateRequestToInput(req))
@@ -204,9 +278,13 @@ func (h defaultUser) executeCreate(c *gin.Context) *appErrors.APIError {
 // @Produce json
 // @Success 200 {object} handler.UserResponse
 // @Failure 400	{object} appErrors.APIError
+// @Failure 401	{object} appErrors.APIError
+// @Failure 403	{object} appErrors.APIError
 // @Failure 404	{object} appErrors.APIError
 // @Failure 500	{object} appErrors.APIError
-// @Router /{id} [put]
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /users/{id} [put]
 func (h defaultUser) Update(c *gin.Context) {
 	appGin.ErrorWrapper(h.executeUpdate, c)
 }
@@ -214,25 +292,93 @@ func (h defaultUser) Update(c *gin.Context) {
 func (h defaultUser) executeUpdate(c *gin.Context) *appErrors.APIError {
 	reference := c.Param("id")
 	if len(reference) == 0 {
-		return appErrors.NewBadRequest("user id is required")
+		return appErrors.NewBadRequest("user id is required").WithKey("user_id_required")
 	}
 	var req UserUpdateRequest
-	err := c.ShouldBindWith(&req, binding.JSON)
-	if err != nil {
-		return appErrors.NewBadRequest("request body is not valid")
+	if apiErr := bindJSON(c, &req); apiErr != nil {
+		return apiErr
 	}
 
-	err = validate.Struct(req)
+	updated, err := h.update.Execute(h.mapper.MapUpdateRequestToInput(reference, req))
 	if err != nil {
-		return appErrors.NewBadRequest("request body is not valid")
+		return appErrors.HandleBusinessError(err)
 	}
 
-	updated, err := h.update.Execute(h.mapper.MapUpdateRequestToInput(reference, req))
+	c.JSON(http.StatusOK, h.mapper.MapDomainToResponse(updated))
+	return nil
+}
+
+// Patch partially update an user
+// @Tags user
+// @Summary Patch an user
+// @Description Partially update an user with a JSON Merge Patch (RFC 7396) or a JSON Patch (RFC 6902) document.
+// @Description The patch is applied to the user data with the same format as the update request. Patching a deleted user reactivates it
+// @Accept application/merge-patch+json
+// @Accept application/json-patch+json
+// @Param id path string true "User id"
+// @Param request body object true "patch document"
+// @Produce json
+// @Success 200 {object} handler.UserResponse
+// @Failure 400	{object} appErrors.APIError
+// @Failure 401	{object} appErrors.APIError
+// @Failure 403	{object} appErrors.APIError
+// @Failure 404	{object} appErrors.APIError
+// @Failure 409	{object} appErrors.APIError
+// @Failure 415	{object} appErrors.APIError
+// @Failure 500	{object} appErrors.APIError
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /users/{id} [patch]
+func (h defaultUser) Patch(c *gin.Context) {
+	appGin.ErrorWrapper(h.executePatch, c)
+}
+
+func (h defaultUser) executePatch(c *gin.Context) *appErrors.APIError {
+	reference := c.Param("id")
+	if len(reference) == 0 {
+		return appErrors.NewBadRequest("user id is required").WithKey("user_id_required")
+	}
+	contentType := c.ContentType()
+	if contentType != MergePatchContentType && contentType != JSONPatchContentType {
+		return appErrors.NewUnsupportedMediaType(fmt.Sprintf("content type must be %s or %s", MergePatchContentType, JSONPatchContentType)).
+			WithKey("patch_content_type_not_valid", MergePatchContentType, JSONPatchContentType)
+	}
+	patch, err := c.GetRawData()
+	if err != nil || len(patch) == 0 {
+		return appErrors.NewBadRequest(invalidBodyMessage).WithKey(invalidBodyKey)
+	}
+
+	input := domain.UserPatchInput{
+		Reference: reference,
+		Apply: func(current domain.UserCreateInput) (domain.UserCreateInput, error) {
+			document, err := json.Marshal(h.mapper.MapInputToUpdateRequest(current))
+			if err != nil {
+				return domain.UserCreateInput{}, err
+			}
+			patched, err := applyPatch(contentType, patch, document)
+			if err != nil {
+				return domain.UserCreateInput{}, err
+			}
+
+			var req UserUpdateRequest
+			decoder := json.NewDecoder(bytes.NewReader(patched))
+			decoder.DisallowUnknownFields()
+			if err := decoder.Decode(&req); err != nil {
+				return domain.UserCreateInput{}, appErrors.NewBusinessError(invalidBodyMessage, appErrors.BadRequestErrorCode).WithKey(invalidBodyKey).WithCauses(decodeErrorCauses(err)...)
+			}
+			if err := validate.Struct(req); err != nil {
+				return domain.UserCreateInput{}, appErrors.NewBusinessError(invalidBodyMessage, appErrors.BadRequestErrorCode).WithKey(invalidBodyKey).WithCauses(validationErrorCauses(req, err, appGin.GetLanguage(c))...)
+			}
+
+			return h.mapper.MapUpdateRequestToInput(reference, req).UserCreateInput, nil
+		},
+	}
+	patched, err := h.patch.Execute(input)
 	if err != nil {
 		return appErrors.HandleBusinessError(err)
 	}
 
-	c.JSON(http.StatusOK, h.mapper.MapDomainToResponse(updated))
+	c.JSON(http.StatusOK, h.mapper.MapDomainToResponse(patched))
 	return nil
 }
 
@@ -244,9 +390,13 @@ func (h defaultUser) executeUpdate(c *gin.Context) *appErrors.APIError {
 // @Produce json
 // @Success 200 {object} handler.UserResponse
 // @Failure 400	{object} appErrors.APIError
+// @Failure 401	{object} appErrors.APIError
+// @Failure 403	{object} appErrors.APIError
 // @Failure 404	{object} appErrors.APIError
 // @Failure 500	{object} appErrors.APIError
-// @Router /{id} [delete]
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /users/{id} [delete]
 func (h defaultUser) Delete(c *gin.Context) {
 	appGin.ErrorWrapper(h.executeDelete, c)
 }
@@ -255,7 +405,7 @@ func (h defaultUser) Delete(c *gin.Context) {
 func (h defaultUser) executeDelete(c *gin.Context) *appErrors.APIError {
 	reference := c.Param("id")
 	if len(reference) == 0 {
-		return appErrors.NewBadRequest("user id is required")
+		return appErrors.NewBadRequest("user id is required").WithKey("user_id_required")
 	}
 
 	deleted, err := h.delete.Execute(reference)
@@ -0,0 +1,178 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/desarrollogj/golang-api-example/domain"
+	appErrors "github.com/desarrollogj/golang-api-example/libs/errors"
+	appGin "github.com/desarrollogj/golang-api-example/libs/gin"
+	"github.com/desarrollogj/golang-api-example/user"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	AvatarFormField = "avatar"
+	// avatarMultipartOverhead is the room left for the multipart boundaries and headers
+	avatarMultipartOverhead = 64 * 1024
+)
+
+// UserAvatar represents the method for user avatar endpoints handlers
+type UserAvatar interface {
+	SetAvatar(c *gin.Context)
+	FindAvatar(c *gin.Context)
+}
+
+// defaultUserAvatar is the default implementation for UserAvatar interface
+type defaultUserAvatar struct {
+	config     domain.AvatarConfiguration
+	mapper     UserMapper
+	setAvatar  user.SetAvatar
+	findAvatar user.FindAvatar
+}
+
+// NewDefaultUserAvatar creates a defaultUserAvatar handler
+func NewDefaultUserAvatar(config domain.AvatarConfiguration, mapper UserMapper, setAvatar user.SetAvatar, findAvatar user.FindAvatar) defaultUserAvatar {
+	return defaultUserAvatar{
+		config:     config,
+		mapper:     mapper,
+		setAvatar:  setAvatar,
+		findAvatar: findAvatar,
+	}
+}
+
+// SetAvatar set an user avatar
+// @Tags user
+// @Summary Set an user avatar
+// @Description Upload a JPEG or PNG image, as a multipart form "avatar" file or as the raw request body. The image is resized to the configured sizes
+// @Param id path string true "User id"
+// @Accept multipart/form-data
+// @Accept image/jpeg
+// @Accept image/png
+// @Param avatar formData file false "avatar image"
+// @Produce json
+// @Success 200 {object} handler.UserResponse
+// @Failure 400	{object} appErrors.APIError
+// @Failure 401	{object} appErrors.APIError
+// @Failure 403	{object} appErrors.APIError
+// @Failure 404	{object} appErrors.APIError
+// @Failure 413	{object} appErrors.APIError
+// @Failure 415	{object} appErrors.APIError
+// @Failure 500	{object} appErrors.APIError
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /users/{id}/avatar [put]
+func (h defaultUserAvatar) SetAvatar(c *gin.Context) {
+	appGin.ErrorWrapper(h.executeSetAvatar, c)
+}
+
+func (h defaultUserAvatar) executeSetAvatar(c *gin.Context) *appErrors.APIError {
+	reference := c.Param("id")
+	if len(reference) == 0 {
+		return appErrors.NewBadRequest("user id is required").WithKey("user_id_required")
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, int64(h.config.MaxSizeBytes+avatarMultipartOverhead))
+	var data []byte
+	var err error
+	switch c.ContentType() {
+	case "multipart/form-data":
+		data, err = h.readAvatarFormFile(c)
+	case "image/jpeg", "image/png", "application/octet-stream":
+		data, err = io.ReadAll(c.Request.Body)
+	default:
+		return appErrors.NewUnsupportedMediaType("content type must be multipart/form-data, image/jpeg or image/png").WithKey("avatar_content_type_not_valid")
+	}
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) || len(data) > h.config.MaxSizeBytes {
+		return appErrors.NewPayloadTooLarge(fmt.Sprintf("avatar image is too large, the maximum size is %d bytes", h.config.MaxSizeBytes)).
+			WithKey("avatar_too_large", h.config.MaxSizeBytes)
+	}
+	if errors.Is(err, http.ErrMissingFile) {
+		return appErrors.NewBadRequest("avatar file is required").WithKey("avatar_file_required")
+	}
+	if err != nil {
+		return appErrors.NewBadRequest(invalidBodyMessage).WithKey(invalidBodyKey)
+	}
+
+	updated, err := h.setAvatar.Execute(domain.UserAvatarInput{
+		Reference: reference,
+		Data:      data,
+	})
+	if err != nil {
+		return appErrors.HandleBusinessError(err)
+	}
+
+	c.JSON(http.StatusOK, h.mapper.MapDomainToResponse(updated))
+	return nil
+}
+
+func (h defaultUserAvatar) readAvatarFormFile(c *gin.Context) ([]byte, error) {
+	fileHeader, err := c.FormFile(AvatarFormField)
+	if err != nil {
+		return nil, err
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return io.ReadAll(file)
+}
+
+// FindAvatar get an user avatar
+// @Tags user
+// @Summary Get an user avatar
+// @Description Get an user avatar thumbnail. Without a size, the largest configured size is returned
+// @Param id path string true "User id"
+// @Param size query int false "Thumbnail size in pixels"
+// @Produce image/jpeg
+// @Produce image/png
+// @Success 200 {file} binary
+// @Success 304
+// @Failure 400	{object} appErrors.APIError
+// @Failure 401	{object} appErrors.APIError
+// @Failure 403	{object} appErrors.APIError
+// @Failure 404	{object} appErrors.APIError
+// @Failure 500	{object} appErrors.APIError
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /users/{id}/avatar [get]
+func (h defaultUserAvatar) FindAvatar(c *gin.Context) {
+	appGin.ErrorWrapper(h.executeFindAvatar, c)
+}
+
+func (h defaultUserAvatar) executeFindAvatar(c *gin.Context) *appErrors.APIError {
+	reference := c.Param("id")
+	if len(reference) == 0 {
+		return appErrors.NewBadRequest("user id is required").WithKey("user_id_required")
+	}
+	size := 0
+	if value := c.Query("size"); len(value) > 0 {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed <= 0 {
+			return appErrors.NewBadRequest("size is not valid").WithKey("avatar_size_param_not_valid")
+		}
+		size = parsed
+	}
+
+	avatar, err := h.findAvatar.Execute(reference, size)
+	if err != nil {
+		return appErrors.HandleBusinessError(err)
+	}
+
+	etag := fmt.Sprintf(`"%s-%d"`, avatar.Version, avatar.Size)
+	c.Header("ETag", etag)
+	c.Header("Cache-Control", fmt.Sprintf("private, max-age=%d", h.config.CacheMaxAgeSeconds))
+	c.Header("Last-Modified", avatar.UpdatedDate.UTC().Format(http.TimeFormat))
+	if c.GetHeader("If-None-Match") == etag {
+		c.Status(http.StatusNotModified)
+		return nil
+	}
+
+	c.Data(http.StatusOK, avatar.ContentType, avatar.Data)
+	return nil
+}
@@ -0,0 +1,202 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/desarrollogj/golang-api-example/domain"
+	libErrors "github.com/desarrollogj/golang-api-example/libs/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var avatarConfig = domain.AvatarConfiguration{
+	MaxSizeBytes:       16,
+	CacheMaxAgeSeconds: 60,
+}
+
+func TestUserAvatar_GivenAMultipartRequest_WhenSetAvatar_ThenReturnUpdatedUserResponse(t *testing.T) {
+	t.Log("Successfully set an user avatar from a multipart form")
+
+	domainUser := domain.User{GenericEntity: domain.GenericEntity{Reference: "USER1", IsActive: true}}
+	responseUser := UserResponse{Id: "USER1", IsActive: true, AvatarUrl: "/api/v1/users/USER1/avatar?v=V1"}
+
+	mapperMock := new(userMapperMock)
+	mapperMock.On("MapDomainToResponse", domainUser).Return(responseUser)
+	setAvatarMock := new(userSetAvatarServiceMock)
+	setAvatarMock.On("Execute", domain.UserAvatarInput{Reference: "USER1", Data: []byte("image")}).Return(domainUser, nil)
+
+	handler := NewDefaultUserAvatar(avatarConfig, mapperMock, setAvatarMock, new(userFindAvatarServiceMock))
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	part, _ := writer.CreateFormFile(AvatarFormField, "avatar.png")
+	part.Write([]byte("image"))
+	writer.Close()
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPut, "/api/v1/users/USER1/avatar", &body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+
+	r := testRouter()
+	r.PUT("/api/v1/users/:id/avatar", handler.SetAvatar)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var result UserResponse
+	json.NewDecoder(w.Body).Decode(&result)
+
+	assert.Equal(t, responseUser, result)
+
+	setAvatarMock.AssertExpectations(t)
+}
+
+func TestUserAvatar_GivenARawRequest_WhenSetAvatar_ThenReturnUpdatedUserResponse(t *testing.T) {
+	t.Log("Successfully set an user avatar from the request body")
+
+	domainUser := domain.User{GenericEntity: domain.GenericEntity{Reference: "USER1", IsActive: true}}
+
+	mapperMock := new(userMapperMock)
+	mapperMock.On("MapDomainToResponse", domainUser).Return(UserResponse{Id: "USER1"})
+	setAvatarMock := new(userSetAvatarServiceMock)
+	setAvatarMock.On("Execute", domain.UserAvatarInput{Reference: "USER1", Data: []byte("image")}).Return(domainUser, nil)
+
+	handler := NewDefaultUserAvatar(avatarConfig, mapperMock, setAvatarMock, new(userFindAvatarServiceMock))
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPut, "/api/v1/users/USER1/avatar", bytes.NewBufferString("image"))
+	req.Header.Set("Content-Type", "image/png")
+
+	r := testRouter()
+	r.PUT("/api/v1/users/:id/avatar", handler.SetAvatar)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	setAvatarMock.AssertExpectations(t)
+}
+
+func TestUserAvatar_GivenANotValidRequest_WhenSetAvatar_ThenReturnAnErrorResponse(t *testing.T) {
+	t.Log("Failure to set an user avatar because the request is not valid")
+
+	var missingFile bytes.Buffer
+	writer := multipart.NewWriter(&missingFile)
+	writer.WriteField("name", "avatar")
+	writer.Close()
+
+	tests := []struct {
+		name        string
+		contentType string
+		body        []byte
+		status      int
+		message     string
+	}{
+		{"MissingFile", writer.FormDataContentType(), missingFile.Bytes(), http.StatusBadRequest, "avatar file is required"},
+		{"ContentType", "application/json", []byte("{}"), http.StatusUnsupportedMediaType,
+			"content type must be multipart/form-data, image/jpeg or image/png"},
+		{"Size", "image/jpeg", make([]byte, 17), http.StatusRequestEntityTooLarge,
+			"avatar image is too large, the maximum size is 16 bytes"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			setAvatarMock := new(userSetAvatarServiceMock)
+			handler := NewDefaultUserAvatar(avatarConfig, new(userMapperMock), setAvatarMock, new(userFindAvatarServiceMock))
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPut, "/api/v1/users/USER1/avatar", bytes.NewBuffer(test.body))
+			req.Header.Set("Content-Type", test.contentType)
+
+			r := testRouter()
+			r.PUT("/api/v1/users/:id/avatar", handler.SetAvatar)
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, test.status, w.Code)
+
+			var result libErrors.APIError
+			json.NewDecoder(w.Body).Decode(&result)
+
+			assert.Equal(t, test.message, result.Message)
+			setAvatarMock.AssertNotCalled(t, "Execute", mock.Anything)
+		})
+	}
+}
+
+func TestUserAvatar_GivenAnAvatar_WhenFindAvatar_ThenReturnTheImageWithCacheHeaders(t *testing.T) {
+	t.Log("Successfully get an user avatar")
+
+	updated := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	findAvatarMock := new(userFindAvatarServiceMock)
+	findAvatarMock.On("Execute", "USER1", 64).Return(domain.UserAvatarImage{
+		Data:        []byte("image"),
+		ContentType: "image/png",
+		Version:     "V1",
+		Size:        64,
+		UpdatedDate: updated,
+	}, nil)
+
+	handler := NewDefaultUserAvatar(avatarConfig, new(userMapperMock), new(userSetAvatarServiceMock), findAvatarMock)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/users/USER1/avatar?size=64", nil)
+
+	r := testRouter()
+	r.GET("/api/v1/users/:id/avatar", handler.FindAvatar)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "image", w.Body.String())
+	assert.Equal(t, "image/png", w.Header().Get("Content-Type"))
+	assert.Equal(t, `"V1-64"`, w.Header().Get("ETag"))
+	assert.Equal(t, "private, max-age=60", w.Header().Get("Cache-Control"))
+	assert.Equal(t, "Tue, 02 Jan 2024 03:04:05 GMT", w.Header().Get("Last-Modified"))
+
+	findAvatarMock.AssertExpectations(t)
+}
+
+func TestUserAvatar_GivenAMatchingETag_WhenFindAvatar_ThenReturnNotModified(t *testing.T) {
+	t.Log("Successfully get an user avatar that was not modified")
+
+	findAvatarMock := new(userFindAvatarServiceMock)
+	findAvatarMock.On("Execute", "USER1", 0).Return(domain.UserAvatarImage{
+		Data:        []byte("image"),
+		ContentType: "image/png",
+		Version:     "V1",
+		Size:        256,
+	}, nil)
+
+	handler := NewDefaultUserAvatar(avatarConfig, new(userMapperMock), new(userSetAvatarServiceMock), findAvatarMock)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/users/USER1/avatar", nil)
+	req.Header.Set("If-None-Match", `"V1-256"`)
+
+	r := testRouter()
+	r.GET("/api/v1/users/:id/avatar", handler.FindAvatar)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotModified, w.Code)
+	assert.Empty(t, w.Body.String())
+}
+
+func TestUserAvatar_GivenANotValidSize_WhenFindAvatar_ThenReturnBadRequestResponse(t *testing.T) {
+	t.Log("Failure to get an user avatar because the size is not valid")
+
+	findAvatarMock := new(userFindAvatarServiceMock)
+	handler := NewDefaultUserAvatar(avatarConfig, new(userMapperMock), new(userSetAvatarServiceMock), findAvatarMock)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/users/USER1/avatar?size=abc", nil)
+
+	r := testRouter()
+	r.GET("/api/v1/users/:id/avatar", handler.FindAvatar)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	findAvatarMock.AssertNotCalled(t, "Execute", mock.Anything, mock.Anything)
+}
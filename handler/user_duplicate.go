@@ -0,0 +1,110 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/desarrollogj/golang-api-example/domain"
+	appErrors "github.com/desarrollogj/golang-api-example/libs/errors"
+	appGin "github.com/desarrollogj/golang-api-example/libs/gin"
+	"github.com/desarrollogj/golang-api-example/user"
+	"github.com/gin-gonic/gin"
+)
+
+// UserDuplicate represents the method for duplicate users endpoints handlers
+type UserDuplicate interface {
+	FindDuplicates(c *gin.Context)
+	Merge(c *gin.Context)
+}
+
+// defaultUserDuplicate is the default implementation for UserDuplicate interface
+type defaultUserDuplicate struct {
+	mapper         UserMapper
+	findDuplicates user.FindDuplicates
+	merge          user.Merge
+}
+
+// NewDefaultUserDuplicate creates a defaultUserDuplicate handler
+func NewDefaultUserDuplicate(mapper UserMapper, findDuplicates user.FindDuplicates, merge user.Merge) defaultUserDuplicate {
+	validate = newValidator()
+	return defaultUserDuplicate{
+		mapper:         mapper,
+		findDuplicates: findDuplicates,
+		merge:          merge,
+	}
+}
+
+// FindDuplicates get the duplicate users report
+// @Tags user
+// @Summary Get the duplicate users report
+// @Description Get the groups of active users that share the same email, ignoring case, or have similar names. The oldest user of each group is the first one
+// @Produce json
+// @Success 200 {object} handler.UserDuplicateReportResponse
+// @Failure 401	{object} appErrors.APIError
+// @Failure 403	{object} appErrors.APIError
+// @Failure 500	{object} appErrors.APIError
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /users/duplicates [get]
+func (h defaultUserDuplicate) FindDuplicates(c *gin.Context) {
+	appGin.ErrorWrapper(h.executeFindDuplicates, c)
+}
+
+func (h defaultUserDuplicate) executeFindDuplicates(c *gin.Context) *appErrors.APIError {
+	report, err := h.findDuplicates.Execute()
+	if err != nil {
+		return appErrors.HandleBusinessError(err)
+	}
+
+	c.JSON(http.StatusOK, h.mapper.MapDomainDuplicateReportToResponse(report))
+	return nil
+}
+
+// Merge merge a duplicate user into an user
+// @Tags user
+// @Summary Merge a duplicate user
+// @Description Fold the duplicate user into the user. The user takes the duplicate profile data it is missing, and its groups, but not its roles. The duplicate is deleted, and its id redirects to the user
+// @Param id path string true "User id"
+// @Param request body handler.UserMergeRequest true "duplicate user"
+// @Param Idempotency-Key header string false "client key to process the request only once"
+// @Produce json
+// @Success 200 {object} handler.UserResponse
+// @Failure 400	{object} appErrors.APIError
+// @Failure 401	{object} appErrors.APIError
+// @Failure 403	{object} appErrors.APIError
+// @Failure 404	{object} appErrors.APIError
+// @Failure 422	{object} appErrors.APIError
+// @Failure 500	{object} appErrors.APIError
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /users/{id}/merge [post]
+func (h defaultUserDuplicate) Merge(c *gin.Context) {
+	appGin.ErrorWrapper(h.executeMerge, c)
+}
+
+func (h defaultUserDuplicate) executeMerge(c *gin.Context) *appErrors.APIError {
+	reference := c.Param("id")
+	if len(reference) == 0 {
+		return appErrors.NewBadRequest("user id is required").WithKey("user_id_required")
+	}
+	var req UserMergeRequest
+	if apiErr := bindJSON(c, &req); apiErr != nil {
+		return apiErr
+	}
+
+	input := domain.UserMergeInput{
+		Reference:          reference,
+		DuplicateReference: strings.TrimSpace(req.DuplicateId),
+	}
+	if identity, ok := GetIdentity(c); ok {
+		input.MergedBy = identity.Subject
+	}
+
+	merged, err := h.merge.Execute(input)
+	if err != nil {
+		return appErrors.HandleBusinessError(err)
+	}
+
+	c.JSON(http.StatusOK, h.mapper.MapDomainToResponse(merged))
+	return nil
+}
@@ -0,0 +1,107 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/desarrollogj/golang-api-example/domain"
+	libErrors "github.com/desarrollogj/golang-api-example/libs/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestUserDuplicate_WhenFindDuplicates_ThenReturnDuplicateReportResponse(t *testing.T) {
+	t.Log("Successfully get the duplicate users report")
+
+	report := domain.UserDuplicateReport{GeneratedDate: time.Now().UTC()}
+	responseReport := UserDuplicateReportResponse{
+		GeneratedDate: "2024-01-02T03:04:05Z",
+		Groups: []UserDuplicateGroupResponse{
+			{Reasons: []string{"email"}, Users: []UserResponse{{Id: "USER1"}, {Id: "USER2"}}},
+		},
+	}
+
+	mapperMock := new(userMapperMock)
+	mapperMock.On("MapDomainDuplicateReportToResponse", report).Return(responseReport)
+	findDuplicatesMock := new(userFindDuplicatesServiceMock)
+	findDuplicatesMock.On("Execute").Return(report, nil)
+
+	handler := NewDefaultUserDuplicate(mapperMock, findDuplicatesMock, new(userMergeServiceMock))
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/users/duplicates", nil)
+
+	r := testRouter()
+	r.GET("/api/v1/users/duplicates", handler.FindDuplicates)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var result UserDuplicateReportResponse
+	json.NewDecoder(w.Body).Decode(&result)
+
+	assert.Equal(t, responseReport, result)
+
+	mapperMock.AssertExpectations(t)
+	findDuplicatesMock.AssertExpectations(t)
+}
+
+func TestUserDuplicate_GivenAMergeRequest_WhenMerge_ThenReturnMergedUserResponse(t *testing.T) {
+	t.Log("Successfully merge a duplicate user")
+
+	domainUser := domain.User{GenericEntity: domain.GenericEntity{Reference: "USER1", IsActive: true}}
+	responseUser := UserResponse{Id: "USER1", IsActive: true}
+
+	mapperMock := new(userMapperMock)
+	mapperMock.On("MapDomainToResponse", domainUser).Return(responseUser)
+	mergeMock := new(userMergeServiceMock)
+	mergeMock.On("Execute", domain.UserMergeInput{
+		Reference:          "USER1",
+		DuplicateReference: "USER2",
+		MergedBy:           "CALLER",
+	}).Return(domainUser, nil)
+
+	handler := NewDefaultUserDuplicate(mapperMock, new(userFindDuplicatesServiceMock), mergeMock)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/users/USER1/merge", bytes.NewBufferString(`{"duplicateId":" USER2 "}`))
+
+	r := testRouter()
+	r.POST("/api/v1/users/:id/merge", withIdentity(domain.PermissionUsersAdmin), handler.Merge)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var result UserResponse
+	json.NewDecoder(w.Body).Decode(&result)
+
+	assert.Equal(t, responseUser, result)
+
+	mergeMock.AssertExpectations(t)
+}
+
+func TestUserDuplicate_GivenANotValidMergeRequest_WhenMerge_ThenReturnBadRequestResponse(t *testing.T) {
+	t.Log("Failure to merge a duplicate user because the request is not valid")
+
+	mergeMock := new(userMergeServiceMock)
+	handler := NewDefaultUserDuplicate(new(userMapperMock), new(userFindDuplicatesServiceMock), mergeMock)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/users/USER1/merge", bytes.NewBufferString(`{}`))
+
+	r := testRouter()
+	r.POST("/api/v1/users/:id/merge", handler.Merge)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	var err libErrors.APIError
+	json.NewDecoder(w.Body).Decode(&err)
+
+	assert.Equal(t, "request body is not valid", err.Message)
+	mergeMock.AssertNotCalled(t, "Execute", mock.Anything)
+}
@@ -0,0 +1,101 @@
+package handler
+
+import (
+	"net/http"
+
+	appErrors "github.com/desarrollogj/golang-api-example/libs/errors"
+	appGin "github.com/desarrollogj/golang-api-example/libs/gin"
+	"github.com/desarrollogj/golang-api-example/user"
+	"github.com/gin-gonic/gin"
+)
+
+// UserEmailVerification represents the method for user email verification endpoints handlers
+type UserEmailVerification interface {
+	Verify(c *gin.Context)
+	Resend(c *gin.Context)
+}
+
+// defaultUserEmailVerification is the default implementation for UserEmailVerification interface
+type defaultUserEmailVerification struct {
+	mapper      UserMapper
+	verifyEmail user.VerifyEmail
+	resend      user.ResendEmailVerification
+}
+
+// NewDefaultUserEmailVerification creates a defaultUserEmailVerification handler
+func NewDefaultUserEmailVerification(mapper UserMapper,
+	verifyEmail user.VerifyEmail,
+	resend user.ResendEmailVerification) defaultUserEmailVerification {
+	validate = newValidator()
+	return defaultUserEmailVerification{
+		mapper:      mapper,
+		verifyEmail: verifyEmail,
+		resend:      resend,
+	}
+}
+
+// Verify confirm an user email
+// @Tags user
+// @Summary Verify an user email
+// @Description Confirm an user email with the verification token sent to it. The user data is not returned, the endpoint does not require authentication
+// @Param request body handler.UserEmailVerificationRequest true "verification token"
+// @Produce json
+// @Success 204
+// @Failure 400	{object} appErrors.APIError
+// @Failure 500	{object} appErrors.APIError
+// @Router /users/verify-email [post]
+func (h defaultUserEmailVerification) Verify(c *gin.Context) {
+	appGin.ErrorWrapper(h.executeVerify, c)
+}
+
+func (h defaultUserEmailVerification) executeVerify(c *gin.Context) *appErrors.APIError {
+	var req UserEmailVerificationRequest
+	if apiErr := bindJSON(c, &req); apiErr != nil {
+		return apiErr
+	}
+
+	_, err := h.verifyEmail.Execute(req.Token)
+	if err != nil {
+		return appErrors.HandleBusinessError(err)
+	}
+
+	c.Status(http.StatusNoContent)
+	return nil
+}
+
+// Resend send again the email verification
+// @Tags user
+// @Summary Resend the email verification
+// @Description Send a new verification token to an user unverified email. It can not be sent again until the resend interval has passed
+// @Param id path string true "User id"
+// @Param Idempotency-Key header string false "client key to process the request only once"
+// @Produce json
+// @Success 202 {object} handler.UserResponse
+// @Failure 400	{object} appErrors.APIError
+// @Failure 401	{object} appErrors.APIError
+// @Failure 403	{object} appErrors.APIError
+// @Failure 404	{object} appErrors.APIError
+// @Failure 422	{object} appErrors.APIError
+// @Failure 429	{object} appErrors.APIError
+// @Failure 500	{object} appErrors.APIError
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /users/{id}/verify-email/resend [post]
+func (h defaultUserEmailVerification) Resend(c *gin.Context) {
+	appGin.ErrorWrapper(h.executeResend, c)
+}
+
+func (h defaultUserEmailVerification) executeResend(c *gin.Context) *appErrors.APIError {
+	reference := c.Param("id")
+	if len(reference) == 0 {
+		return appErrors.NewBadRequest("user id is required").WithKey("user_id_required")
+	}
+
+	updated, err := h.resend.Execute(reference)
+	if err != nil {
+		return appErrors.HandleBusinessError(err)
+	}
+
+	c.JSON(http.StatusAccepted, h.mapper.MapDomainToResponse(updated))
+	return nil
+}
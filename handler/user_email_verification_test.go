@@ -0,0 +1,169 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/desarrollogj/golang-api-example/domain"
+	libErrors "github.com/desarrollogj/golang-api-example/libs/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestUserEmailVerification_GivenAToken_WhenVerify_ThenReturnNoContentResponse(t *testing.T) {
+	t.Log("Successfully verify an user email, without returning the user data")
+
+	current := time.Now().UTC()
+	domainUser := domain.User{
+		GenericEntity: domain.GenericEntity{
+			Reference:   "USER1",
+			IsActive:    true,
+			CreatedDate: current,
+			UpdatedDate: current,
+		},
+		FirstName:         "Foo",
+		LastName:          "Bar",
+		Email:             "foobar@email.com",
+		EmailVerifiedDate: &current,
+	}
+
+	mapperMock := new(userMapperMock)
+	verifyMock := new(userVerifyEmailServiceMock)
+	verifyMock.On("Execute", "TOKEN").Return(domainUser, nil)
+	resendMock := new(userResendEmailVerificationServiceMock)
+
+	handler := NewDefaultUserEmailVerification(mapperMock, verifyMock, resendMock)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/users/verify-email", bytes.NewBufferString(`{"token":"TOKEN"}`))
+
+	r := testRouter()
+	r.POST("/api/v1/users/verify-email", handler.Verify)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Empty(t, w.Body.String())
+
+	verifyMock.AssertExpectations(t)
+	mapperMock.AssertNotCalled(t, "MapDomainToResponse", mock.Anything)
+}
+
+func TestUserEmailVerification_GivenARequestWithoutToken_WhenVerify_ThenReturnBadRequestResponse(t *testing.T) {
+	t.Log("Failure to verify an user email because the token is missing")
+
+	mapperMock := new(userMapperMock)
+	verifyMock := new(userVerifyEmailServiceMock)
+	resendMock := new(userResendEmailVerificationServiceMock)
+
+	handler := NewDefaultUserEmailVerification(mapperMock, verifyMock, resendMock)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/users/verify-email", bytes.NewBufferString(`{}`))
+
+	r := testRouter()
+	r.POST("/api/v1/users/verify-email", handler.Verify)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	verifyMock.AssertNotCalled(t, "Execute", mock.Anything)
+}
+
+func TestUserEmailVerification_GivenANotValidToken_WhenVerify_ThenReturnBadRequestResponse(t *testing.T) {
+	t.Log("Failure to verify an user email because the token is not valid")
+
+	mapperMock := new(userMapperMock)
+	verifyMock := new(userVerifyEmailServiceMock)
+	verifyMock.On("Execute", "TOKEN").
+		Return(domain.User{}, libErrors.NewValidationError("verification token is not valid or expired"))
+	resendMock := new(userResendEmailVerificationServiceMock)
+
+	handler := NewDefaultUserEmailVerification(mapperMock, verifyMock, resendMock)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/users/verify-email", bytes.NewBufferString(`{"token":"TOKEN"}`))
+
+	r := testRouter()
+	r.POST("/api/v1/users/verify-email", handler.Verify)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	var err libErrors.APIError
+	json.NewDecoder(w.Body).Decode(&err)
+
+	assert.Equal(t, "verification token is not valid or expired", err.Message)
+
+	verifyMock.AssertExpectations(t)
+}
+
+func TestUserEmailVerification_GivenAReference_WhenResend_ThenReturnAcceptedResponse(t *testing.T) {
+	t.Log("Successfully resend an user email verification")
+
+	domainUser := domain.User{
+		GenericEntity: domain.GenericEntity{Reference: "USER1"},
+		Email:         "foobar@email.com",
+	}
+	responseUser := UserResponse{
+		Id:    "USER1",
+		Email: "foobar@email.com",
+	}
+
+	mapperMock := new(userMapperMock)
+	mapperMock.On("MapDomainToResponse", domainUser).Return(responseUser)
+	verifyMock := new(userVerifyEmailServiceMock)
+	resendMock := new(userResendEmailVerificationServiceMock)
+	resendMock.On("Execute", "USER1").Return(domainUser, nil)
+
+	handler := NewDefaultUserEmailVerification(mapperMock, verifyMock, resendMock)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/users/USER1/verify-email/resend", nil)
+
+	r := testRouter()
+	r.POST("/api/v1/users/:id/verify-email/resend", handler.Resend)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusAccepted, w.Code)
+
+	var result UserResponse
+	json.NewDecoder(w.Body).Decode(&result)
+
+	assert.Equal(t, responseUser, result)
+
+	mapperMock.AssertExpectations(t)
+	resendMock.AssertExpectations(t)
+}
+
+func TestUserEmailVerification_GivenAThrottledReference_WhenResend_ThenReturnTooManyRequestsResponse(t *testing.T) {
+	t.Log("Failure to resend an user email verification because it was sent recently")
+
+	mapperMock := new(userMapperMock)
+	verifyMock := new(userVerifyEmailServiceMock)
+	resendMock := new(userResendEmailVerificationServiceMock)
+	resendMock.On("Execute", "USER1").
+		Return(domain.User{}, libErrors.NewTooManyRequestsError("verification email was already sent, retry in 30 seconds"))
+
+	handler := NewDefaultUserEmailVerification(mapperMock, verifyMock, resendMock)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/users/USER1/verify-email/resend", nil)
+
+	r := testRouter()
+	r.POST("/api/v1/users/:id/verify-email/resend", handler.Resend)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+
+	var err libErrors.APIError
+	json.NewDecoder(w.Body).Decode(&err)
+
+	assert.Equal(t, "verification email was already sent, retry in 30 seconds", err.Message)
+	assert.Equal(t, libErrors.TooManyRequestsErrorCode, err.Err)
+
+	resendMock.AssertExpectations(t)
+}
@@ -0,0 +1,71 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/desarrollogj/golang-api-example/domain"
+	appErrors "github.com/desarrollogj/golang-api-example/libs/errors"
+	appGin "github.com/desarrollogj/golang-api-example/libs/gin"
+	"github.com/desarrollogj/golang-api-example/user"
+	"github.com/gin-gonic/gin"
+)
+
+// UserExternalIDs represents the method for user external ids endpoints handlers
+type UserExternalIDs interface {
+	FindByExternalID(c *gin.Context)
+}
+
+// defaultUserExternalIDs is the default implementation for UserExternalIDs interface
+type defaultUserExternalIDs struct {
+	mapper           UserMapper
+	findByExternalID user.FindByExternalID
+}
+
+// NewDefaultUserExternalIDs creates a defaultUserExternalIDs handler
+func NewDefaultUserExternalIDs(mapper UserMapper, findByExternalID user.FindByExternalID) defaultUserExternalIDs {
+	return defaultUserExternalIDs{
+		mapper:           mapper,
+		findByExternalID: findByExternalID,
+	}
+}
+
+// FindByExternalID find an user by one of its external ids
+// @Tags user
+// @Summary Find an user by an external id
+// @Description Find an active user by its id in another system. The source is case insensitive. The external id of an user merged into another user returns that user
+// @Param source path string true "External id source"
+// @Param externalId path string true "External id"
+// @Produce json
+// @Success 200 {object} handler.UserResponse
+// @Failure 400	{object} appErrors.APIError
+// @Failure 401	{object} appErrors.APIError
+// @Failure 403	{object} appErrors.APIError
+// @Failure 404	{object} appErrors.APIError
+// @Failure 500	{object} appErrors.APIError
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /users/by-external/{source}/{externalId} [get]
+func (h defaultUserExternalIDs) FindByExternalID(c *gin.Context) {
+	appGin.ErrorWrapper(h.executeFindByExternalID, c)
+}
+
+func (h defaultUserExternalIDs) executeFindByExternalID(c *gin.Context) *appErrors.APIError {
+	externalID := domain.UserExternalID{
+		Source: c.Param("source"),
+		ID:     c.Param("externalId"),
+	}
+	if len(externalID.Source) == 0 {
+		return appErrors.NewBadRequest("external id source is required").WithKey("external_id_source_required")
+	}
+	if len(externalID.ID) == 0 {
+		return appErrors.NewBadRequest("external id is required").WithKey("external_id_param_required")
+	}
+
+	user, err := h.findByExternalID.Execute(externalID)
+	if err != nil {
+		return appErrors.HandleBusinessError(err)
+	}
+
+	c.JSON(http.StatusOK, h.mapper.MapDomainToResponse(user))
+	return nil
+}
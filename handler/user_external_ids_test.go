@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/desarrollogj/golang-api-example/domain"
+	libErrors "github.com/desarrollogj/golang-api-example/libs/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUserExternalIDs_GivenAnExternalID_WhenFindByExternalID_ThenReturnUserResponse(t *testing.T) {
+	t.Log("Successfully find an user by its external id")
+
+	domainUser := domain.User{
+		GenericEntity: domain.GenericEntity{Reference: "USER1", IsActive: true},
+		ExternalIDs:   []domain.UserExternalID{{Source: "crm", ID: "C/1"}},
+	}
+	responseUser := UserResponse{Id: "USER1", IsActive: true, ExternalIDs: []ExternalIDResponse{{Source: "crm", Id: "C/1"}}}
+
+	mapperMock := new(userMapperMock)
+	mapperMock.On("MapDomainToResponse", domainUser).Return(responseUser)
+	findMock := new(userFindByExternalIDServiceMock)
+	findMock.On("Execute", domain.UserExternalID{Source: "CRM", ID: "C/1"}).Return(domainUser, nil)
+
+	handler := NewDefaultUserExternalIDs(mapperMock, findMock)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/users/by-external/CRM/C%2F1", nil)
+
+	r := testRouter()
+	r.UseRawPath = true
+	r.GET("/api/v1/users/by-external/:source/:externalId", handler.FindByExternalID)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var result UserResponse
+	json.NewDecoder(w.Body).Decode(&result)
+
+	assert.Equal(t, responseUser, result)
+
+	mapperMock.AssertExpectations(t)
+	findMock.AssertExpectations(t)
+}
+
+func TestUserExternalIDs_GivenAnUnknownExternalID_WhenFindByExternalID_ThenReturnNotFoundResponse(t *testing.T) {
+	t.Log("Failure to find an user by an external id that nobody has")
+
+	findMock := new(userFindByExternalIDServiceMock)
+	findMock.On("Execute", domain.UserExternalID{Source: "crm", ID: "C-1"}).
+		Return(domain.User{}, libErrors.NewNotFoundError("user not found"))
+
+	handler := NewDefaultUserExternalIDs(new(userMapperMock), findMock)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/users/by-external/crm/C-1", nil)
+
+	r := testRouter()
+	r.GET("/api/v1/users/by-external/:source/:externalId", handler.FindByExternalID)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	var err libErrors.APIError
+	json.NewDecoder(w.Body).Decode(&err)
+
+	assert.Equal(t, "user not found", err.Message)
+
+	findMock.AssertExpectations(t)
+}
@@ -1,32 +1,105 @@
 package handler
 
 import (
+	"fmt"
 	"strings"
 	"time"
 
 	"github.com/desarrollogj/golang-api-example/domain"
 )
 
+const birthDateLayout = "2006-01-02"
+
 type UserResponse struct {
-	Id          string `json:"id"`
-	FirstName   string `json:"firstName"`
-	LastName    string `json:"lastName"`
-	Email       string `json:"email"`
-	IsActive    bool   `json:"isActive"`
-	CreatedDate string `json:"created"`
-	UpdatedDate string `json:"updated"`
+	Id                string                 `json:"id"`
+	FirstName         string                 `json:"firstName"`
+	LastName          string                 `json:"lastName"`
+	Email             string                 `json:"email"`
+	EmailVerified     bool                   `json:"emailVerified"`
+	EmailVerifiedDate string                 `json:"emailVerifiedDate,omitempty"`
+	Roles             []string               `json:"roles,omitempty"`
+	Attributes        map[string]interface{} `json:"attributes,omitempty" swaggertype:"object"`
+	Tags              []string               `json:"tags,omitempty"`
+	ExternalIDs       []ExternalIDResponse   `json:"externalIds,omitempty"`
+	AvatarUrl         string                 `json:"avatarUrl,omitempty"`
+	MergedInto        string                 `json:"mergedInto,omitempty"`
+	Phone             string                 `json:"phone,omitempty"`
+	BirthDate         string                 `json:"birthDate,omitempty"`
+	Locale            string                 `json:"locale,omitempty"`
+	Timezone          string                 `json:"timezone,omitempty"`
+	Address           *AddressResponse       `json:"address,omitempty"`
+	IsActive          bool                   `json:"isActive"`
+	Status            string                 `json:"status,omitempty"`
+	StatusReason      string                 `json:"statusReason,omitempty"`
+	StatusDate        string                 `json:"statusDate,omitempty"`
+	CreatedDate       string                 `json:"created"`
+	UpdatedDate       string                 `json:"updated"`
+}
+
+type ExternalIDResponse struct {
+	Source string `json:"source"`
+	Id     string `json:"id"`
+}
+
+type AddressResponse struct {
+	Line1      string `json:"line1"`
+	Line2      string `json:"line2,omitempty"`
+	City       string `json:"city"`
+	Region     string `json:"region,omitempty"`
+	PostalCode string `json:"postalCode,omitempty"`
+	Country    string `json:"country"`
 }
 
 type UserCreateRequest struct {
-	FirstName string `json:"firstName" validate:"required"`
-	LastName  string `json:"lastName" validate:"required"`
-	Email     string `json:"email" validate:"required,email"`
+	FirstName   string                 `json:"firstName" validate:"required"`
+	LastName    string                 `json:"lastName" validate:"required"`
+	Email       string                 `json:"email" validate:"required,email"`
+	Phone       string                 `json:"phone" validate:"omitempty,max=30"`
+	BirthDate   string                 `json:"birthDate" validate:"omitempty,datetime=2006-01-02"`
+	Locale      string                 `json:"locale" validate:"omitempty,bcp47_language_tag"`
+	Timezone    string                 `json:"timezone" validate:"omitempty,timezone"`
+	Address     *AddressRequest        `json:"address" validate:"omitempty"`
+	Attributes  map[string]interface{} `json:"attributes" swaggertype:"object"`
+	Tags        []string               `json:"tags" validate:"max=50"`
+	ExternalIDs []ExternalIDRequest    `json:"externalIds" validate:"max=20,dive"`
+}
+
+type ExternalIDRequest struct {
+	Source string `json:"source" validate:"required,max=50"`
+	Id     string `json:"id" validate:"required,max=200"`
+}
+
+type AddressRequest struct {
+	Line1      string `json:"line1" validate:"required,max=200"`
+	Line2      string `json:"line2" validate:"max=200"`
+	City       string `json:"city" validate:"required,max=100"`
+	Region     string `json:"region" validate:"max=100"`
+	PostalCode string `json:"postalCode" validate:"max=20"`
+	Country    string `json:"country" validate:"required,iso3166_1_alpha2"`
 }
 
 type UserUpdateRequest struct {
 	UserCreateRequest
 }
 
+type UserEmailVerificationRequest struct {
+	Token string `json:"token" validate:"required"`
+}
+
+type UserPasswordRequest struct {
+	Password string `json:"password" validate:"required"`
+	// CurrentPassword is required when the users change their own password
+	CurrentPassword string `json:"currentPassword,omitempty"`
+}
+
+type UserRolesRequest struct {
+	Roles []string `json:"roles" validate:"max=20,dive,required"`
+}
+
+type UserStatusChangeRequest struct {
+	Reason string `json:"reason" validate:"max=500"`
+}
+
 type UserSearchResponse struct {
 	Data     []UserResponse `json:"data"`
 	Total    int64          `json:"total"`
@@ -34,13 +107,42 @@ type UserSearchResponse struct {
 	PageSize int            `json:"size"`
 }
 
+type UserDataExportResponse struct {
+	Id            string                              `json:"id"`
+	GeneratedDate string                              `json:"generated"`
+	Sections      map[string][]map[string]interface{} `json:"sections"`
+}
+
+type UserDuplicateReportResponse struct {
+	GeneratedDate string                       `json:"generated"`
+	Groups        []UserDuplicateGroupResponse `json:"groups"`
+}
+
+type UserDuplicateGroupResponse struct {
+	Reasons []string       `json:"reasons"`
+	Users   []UserResponse `json:"users"`
+}
+
+type TagCountResponse struct {
+	Tag   string `json:"tag"`
+	Count int64  `json:"count"`
+}
+
+type UserMergeRequest struct {
+	DuplicateId string `json:"duplicateId" validate:"required"`
+}
+
 // UserMapper represents the method for user mappers
 type UserMapper interface {
 	MapDomainToResponse(user domain.User) UserResponse
 	MapDomainListToResponseList(users []domain.User) []UserResponse
 	MapCreateRequestToInput(request UserCreateRequest) domain.UserCreateInput
 	MapUpdateRequestToInput(reference string, request UserUpdateRequest) domain.UserUpdateInput
+	MapInputToUpdateRequest(input domain.UserCreateInput) UserUpdateRequest
 	MapDomainSearchOutputToResponse(output domain.UserSearchOutput) UserSearchResponse
+	MapDomainDataExportToResponse(export domain.UserDataExport) UserDataExportResponse
+	MapDomainDuplicateReportToResponse(report domain.UserDuplicateReport) UserDuplicateReportResponse
+	MapDomainTagCountsToResponse(tags []domain.TagCount) []TagCountResponse
 }
 
 // defaultUserMapper is the default implementation for UserMapper interface
@@ -55,13 +157,29 @@ func NewDefaultUserMapper() defaultUserMapper {
 // MapDomainToResponse mas a domain user to a response
 func (m defaultUserMapper) MapDomainToResponse(user domain.User) UserResponse {
 	return UserResponse{
-		Id:          user.Reference,
-		FirstName:   user.FirstName,
-		LastName:    user.LastName,
-		Email:       user.Email,
-		IsActive:    user.IsActive,
-		CreatedDate: user.CreatedDate.UTC().Format(time.RFC3339),
-		UpdatedDate: user.UpdatedDate.UTC().Format(time.RFC3339),
+		Id:                user.Reference,
+		FirstName:         user.FirstName,
+		LastName:          user.LastName,
+		Email:             user.Email,
+		EmailVerified:     user.EmailVerifiedDate != nil,
+		EmailVerifiedDate: m.mapOptionalDateToResponse(user.EmailVerifiedDate),
+		Roles:             user.Roles,
+		Attributes:        m.mapAttributesToResponse(user.Attributes),
+		Tags:              user.Tags,
+		ExternalIDs:       m.mapExternalIDsToResponse(user.ExternalIDs),
+		AvatarUrl:         m.mapAvatarUrlToResponse(user),
+		MergedInto:        user.MergedInto,
+		Phone:             user.Phone,
+		BirthDate:         m.mapBirthDateToResponse(user.BirthDate),
+		Locale:            user.Locale,
+		Timezone:          user.Timezone,
+		Address:           m.mapAddressToResponse(user.Address),
+		IsActive:          user.IsActive,
+		Status:            string(user.Status),
+		StatusReason:      user.StatusReason,
+		StatusDate:        m.mapDateToResponse(user.StatusDate),
+		CreatedDate:       user.CreatedDate.UTC().Format(time.RFC3339),
+		UpdatedDate:       user.UpdatedDate.UTC().Format(time.RFC3339),
 	}
 }
 
@@ -79,22 +197,59 @@ func (m defaultUserMapper) MapDomainListToResponseList(users []domain.User) []Us
 // MapCreateRequestToInput map create request to an input struct
 func (m defaultUserMapper) MapCreateRequestToInput(request UserCreateRequest) domain.UserCreateInput {
 	return domain.UserCreateInput{
-		FirstName: strings.TrimSpace(request.FirstName),
-		LastName:  strings.TrimSpace(request.LastName),
-		Email:     strings.TrimSpace(request.Email),
+		UserProfile: m.mapProfileToInput(request),
+		FirstName:   strings.TrimSpace(request.FirstName),
+		LastName:    strings.TrimSpace(request.LastName),
+		Email:       strings.TrimSpace(request.Email),
+		Attributes:  request.Attributes,
+		Tags:        request.Tags,
+		ExternalIDs: m.mapExternalIDsToInput(request.ExternalIDs),
 	}
 }
 
 // MapUpdateRequestToInput map update request to an input struct
 func (m defaultUserMapper) MapUpdateRequestToInput(reference string, request UserUpdateRequest) domain.UserUpdateInput {
 	return domain.UserUpdateInput{
-		UserCreateInput: domain.UserCreateInput{
-			FirstName: strings.TrimSpace(request.FirstName),
-			LastName:  strings.TrimSpace(request.LastName),
-			Email:     strings.TrimSpace(request.Email),
+		UserCreateInput: m.MapCreateRequestToInput(request.UserCreateRequest),
+		Reference:       strings.TrimSpace(reference),
+	}
+}
+
+// MapInputToUpdateRequest map an input struct to an update request, used as the document to patch
+func (m defaultUserMapper) MapInputToUpdateRequest(input domain.UserCreateInput) UserUpdateRequest {
+	request := UserUpdateRequest{
+		UserCreateRequest: UserCreateRequest{
+			FirstName: input.FirstName,
+			LastName:  input.LastName,
+			Email:     input.Email,
+			Phone:     input.Phone,
+			BirthDate: m.mapBirthDateToResponse(input.BirthDate),
+			Locale:    input.Locale,
+			Timezone:  input.Timezone,
+			// Never empty, so a patch can add an attribute to an user without attributes
+			Attributes:  map[string]interface{}{},
+			Tags:        append([]string{}, input.Tags...),
+			ExternalIDs: []ExternalIDRequest{},
 		},
-		Reference: strings.TrimSpace(reference),
 	}
+	for _, externalID := range input.ExternalIDs {
+		request.ExternalIDs = append(request.ExternalIDs, ExternalIDRequest{Source: externalID.Source, Id: externalID.ID})
+	}
+	for name, value := range m.mapAttributesToResponse(input.Attributes) {
+		request.Attributes[name] = value
+	}
+	if input.Address != nil {
+		request.Address = &AddressRequest{
+			Line1:      input.Address.Line1,
+			Line2:      input.Address.Line2,
+			City:       input.Address.City,
+			Region:     input.Address.Region,
+			PostalCode: input.Address.PostalCode,
+			Country:    input.Address.Country,
+		}
+	}
+
+	return request
 }
 
 // MapDomainSearchOutputToResponse map search output to a response struct
@@ -106,3 +261,153 @@ func (m defaultUserMapper) MapDomainSearchOutputToResponse(output domain.UserSea
 		PageSize: output.PageSize,
 	}
 }
+
+// MapDomainDataExportToResponse map an user data export to a response struct
+func (m defaultUserMapper) MapDomainDataExportToResponse(export domain.UserDataExport) UserDataExportResponse {
+	sections := map[string][]map[string]interface{}{}
+	for _, section := range export.Sections {
+		sections[section.Name] = section.Records
+	}
+
+	return UserDataExportResponse{
+		Id:            export.Reference,
+		GeneratedDate: export.GeneratedDate.UTC().Format(time.RFC3339),
+		Sections:      sections,
+	}
+}
+
+// MapDomainTagCountsToResponse map the tag counts to a response list
+func (m defaultUserMapper) MapDomainTagCountsToResponse(tags []domain.TagCount) []TagCountResponse {
+	response := []TagCountResponse{}
+	for _, tag := range tags {
+		response = append(response, TagCountResponse{Tag: tag.Tag, Count: tag.Count})
+	}
+
+	return response
+}
+
+// MapDomainDuplicateReportToResponse map a duplicate users report to a response struct
+func (m defaultUserMapper) MapDomainDuplicateReportToResponse(report domain.UserDuplicateReport) UserDuplicateReportResponse {
+	groups := []UserDuplicateGroupResponse{}
+	for _, group := range report.Groups {
+		groups = append(groups, UserDuplicateGroupResponse{
+			Reasons: group.Reasons,
+			Users:   m.MapDomainListToResponseList(group.Users),
+		})
+	}
+
+	return UserDuplicateReportResponse{
+		GeneratedDate: report.GeneratedDate.UTC().Format(time.RFC3339),
+		Groups:        groups,
+	}
+}
+
+func (m defaultUserMapper) mapProfileToInput(request UserCreateRequest) domain.UserProfile {
+	profile := domain.UserProfile{
+		Phone:    strings.TrimSpace(request.Phone),
+		Locale:   strings.TrimSpace(request.Locale),
+		Timezone: strings.TrimSpace(request.Timezone),
+	}
+	if birthDate, err := time.Parse(birthDateLayout, strings.TrimSpace(request.BirthDate)); err == nil {
+		profile.BirthDate = &birthDate
+	}
+	if request.Address != nil {
+		profile.Address = &domain.Address{
+			Line1:      strings.TrimSpace(request.Address.Line1),
+			Line2:      strings.TrimSpace(request.Address.Line2),
+			City:       strings.TrimSpace(request.Address.City),
+			Region:     strings.TrimSpace(request.Address.Region),
+			PostalCode: strings.TrimSpace(request.Address.PostalCode),
+			Country:    strings.TrimSpace(request.Address.Country),
+		}
+	}
+
+	return profile
+}
+
+func (m defaultUserMapper) mapBirthDateToResponse(birthDate *time.Time) string {
+	if birthDate == nil {
+		return ""
+	}
+
+	return birthDate.UTC().Format(birthDateLayout)
+}
+
+// mapAvatarUrlToResponse returns the avatar url. The version changes with every upload, so cached avatars are never stale
+func (m defaultUserMapper) mapAvatarUrlToResponse(user domain.User) string {
+	if user.Avatar == nil {
+		return ""
+	}
+
+	return fmt.Sprintf("/api/v1/users/%s/avatar?v=%s", user.Reference, user.Avatar.Version)
+}
+
+func (m defaultUserMapper) mapAddressToResponse(address *domain.Address) *AddressResponse {
+	if address == nil {
+		return nil
+	}
+
+	return &AddressResponse{
+		Line1:      address.Line1,
+		Line2:      address.Line2,
+		City:       address.City,
+		Region:     address.Region,
+		PostalCode: address.PostalCode,
+		Country:    address.Country,
+	}
+}
+
+func (m defaultUserMapper) mapExternalIDsToInput(externalIDs []ExternalIDRequest) []domain.UserExternalID {
+	if externalIDs == nil {
+		return nil
+	}
+
+	mapped := []domain.UserExternalID{}
+	for _, externalID := range externalIDs {
+		mapped = append(mapped, domain.UserExternalID{Source: externalID.Source, ID: externalID.Id})
+	}
+	return mapped
+}
+
+func (m defaultUserMapper) mapExternalIDsToResponse(externalIDs []domain.UserExternalID) []ExternalIDResponse {
+	if len(externalIDs) == 0 {
+		return nil
+	}
+
+	mapped := []ExternalIDResponse{}
+	for _, externalID := range externalIDs {
+		mapped = append(mapped, ExternalIDResponse{Source: externalID.Source, Id: externalID.ID})
+	}
+	return mapped
+}
+
+func (m defaultUserMapper) mapAttributesToResponse(attributes map[string]interface{}) map[string]interface{} {
+	if len(attributes) == 0 {
+		return nil
+	}
+
+	mapped := map[string]interface{}{}
+	for name, value := range attributes {
+		if date, ok := value.(time.Time); ok {
+			value = date.UTC().Format(birthDateLayout)
+		}
+		mapped[name] = value
+	}
+	return mapped
+}
+
+func (m defaultUserMapper) mapDateToResponse(date time.Time) string {
+	if date.IsZero() {
+		return ""
+	}
+
+	return date.UTC().Format(time.RFC3339)
+}
+
+func (m defaultUserMapper) mapOptionalDateToResponse(date *time.Time) string {
+	if date == nil {
+		return ""
+	}
+
+	return m.mapDateToResponse(*date)
+}
@@ -177,3 +177,225 @@ func TestUserMapper_GivenASearchOutputDomain_WhenMapDomainToResponse_ThenReturnS
 	assert.NotNil(t, response)
 	assert.Equal(t, searchResponse, response)
 }
+
+func TestUserMapper_GivenADataExportDomain_WhenMapDomainToResponse_ThenReturnDataExportResponse(t *testing.T) {
+	t.Log("Successfully map domain user data export to response")
+
+	current := time.Now().UTC()
+	domainExport := domain.UserDataExport{
+		Reference:     "USER1",
+		GeneratedDate: current,
+		Sections: []domain.UserDataExportSection{
+			{Name: "user", Records: []map[string]interface{}{{"id": "USER1"}}},
+			{Name: "audit", Records: []map[string]interface{}{}},
+		},
+	}
+	expectedResponse := UserDataExportResponse{
+		Id:            "USER1",
+		GeneratedDate: current.Format(time.RFC3339),
+		Sections: map[string][]map[string]interface{}{
+			"user":  {{"id": "USER1"}},
+			"audit": {},
+		},
+	}
+
+	mapper := NewDefaultUserMapper()
+	response := mapper.MapDomainDataExportToResponse(domainExport)
+
+	assert.Equal(t, expectedResponse, response)
+}
+
+func TestUserMapper_GivenADuplicateReportDomain_WhenMapDomainToResponse_ThenReturnDuplicateReportResponse(t *testing.T) {
+	t.Log("Successfully map domain duplicate users report to response")
+
+	current := time.Now().UTC()
+	currentStr := current.Format(time.RFC3339)
+	domainReport := domain.UserDuplicateReport{
+		GeneratedDate: current,
+		Groups: []domain.UserDuplicateGroup{
+			{
+				Reasons: []string{domain.UserDuplicateReasonEmail},
+				Users: []domain.User{
+					{GenericEntity: domain.GenericEntity{Reference: "USER1", CreatedDate: current, UpdatedDate: current}},
+					{GenericEntity: domain.GenericEntity{Reference: "USER2", CreatedDate: current, UpdatedDate: current}},
+				},
+			},
+		},
+	}
+	expectedResponse := UserDuplicateReportResponse{
+		GeneratedDate: currentStr,
+		Groups: []UserDuplicateGroupResponse{
+			{
+				Reasons: []string{"email"},
+				Users: []UserResponse{
+					{Id: "USER1", CreatedDate: currentStr, UpdatedDate: currentStr},
+					{Id: "USER2", CreatedDate: currentStr, UpdatedDate: currentStr},
+				},
+			},
+		},
+	}
+
+	mapper := NewDefaultUserMapper()
+	response := mapper.MapDomainDuplicateReportToResponse(domainReport)
+
+	assert.Equal(t, expectedResponse, response)
+}
+
+func TestUserMapper_GivenAUserDomainWithProfile_WhenMapDomainToResponse_ThenReturnUserResponseWithProfile(t *testing.T) {
+	t.Log("Successfully map domain user with profile and avatar to response user")
+
+	current := time.Now().UTC()
+	currentStr := current.Format(time.RFC3339)
+	birthDate := time.Date(1990, 5, 17, 0, 0, 0, 0, time.UTC)
+	domainUser := domain.User{
+		GenericEntity: domain.GenericEntity{
+			Reference:   "USER1",
+			IsActive:    true,
+			CreatedDate: current,
+			UpdatedDate: current,
+		},
+		UserProfile: domain.UserProfile{
+			Phone:     "+5491112345678",
+			BirthDate: &birthDate,
+			Locale:    "es-AR",
+			Timezone:  "America/Argentina/Buenos_Aires",
+			Address:   &domain.Address{Line1: "Street 123", City: "Buenos Aires", Country: "AR"},
+		},
+		FirstName: "Foo",
+		LastName:  "Bar",
+		Email:     "foobar@email.com",
+		Avatar:    &domain.UserAvatar{Version: "V1", ContentType: "image/png", Sizes: []int{256}},
+		Attributes: map[string]interface{}{
+			"plan":    "pro",
+			"seats":   10.0,
+			"hiredOn": time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+		},
+		Tags:        []string{"beta", "vip"},
+		ExternalIDs: []domain.UserExternalID{{Source: "crm", ID: "C-1"}},
+	}
+	responseUser := UserResponse{
+		Id:          "USER1",
+		FirstName:   "Foo",
+		LastName:    "Bar",
+		Email:       "foobar@email.com",
+		Attributes:  map[string]interface{}{"plan": "pro", "seats": 10.0, "hiredOn": "2024-03-01"},
+		Tags:        []string{"beta", "vip"},
+		ExternalIDs: []ExternalIDResponse{{Source: "crm", Id: "C-1"}},
+		AvatarUrl:   "/api/v1/users/USER1/avatar?v=V1",
+		Phone:       "+5491112345678",
+		BirthDate:   "1990-05-17",
+		Locale:      "es-AR",
+		Timezone:    "America/Argentina/Buenos_Aires",
+		Address:     &AddressResponse{Line1: "Street 123", City: "Buenos Aires", Country: "AR"},
+		IsActive:    true,
+		CreatedDate: currentStr,
+		UpdatedDate: currentStr,
+	}
+
+	mapper := NewDefaultUserMapper()
+	response := mapper.MapDomainToResponse(domainUser)
+
+	assert.Equal(t, responseUser, response)
+}
+
+func TestUserMapper_GivenACreateUserRequestWithProfile_WhenMapRequestToDomain_ThenReturnCreateUserDomainWithProfile(t *testing.T) {
+	t.Log("Successfully map create user request with profile to input")
+
+	request := UserCreateRequest{
+		FirstName:  "Foo",
+		LastName:   "Bar",
+		Email:      "foobar@email.com",
+		Phone:      " +54 9 11 1234-5678 ",
+		BirthDate:  "1990-05-17",
+		Locale:     "es-AR",
+		Timezone:   "America/Argentina/Buenos_Aires",
+		Address:    &AddressRequest{Line1: " Street 123 ", City: "Buenos Aires", Country: "AR"},
+		Attributes: map[string]interface{}{"plan": "pro"},
+		Tags:       []string{"VIP"},
+	}
+	birthDate := time.Date(1990, 5, 17, 0, 0, 0, 0, time.UTC)
+	expectedInput := domain.UserCreateInput{
+		UserProfile: domain.UserProfile{
+			Phone:     "+54 9 11 1234-5678",
+			BirthDate: &birthDate,
+			Locale:    "es-AR",
+			Timezone:  "America/Argentina/Buenos_Aires",
+			Address:   &domain.Address{Line1: "Street 123", City: "Buenos Aires", Country: "AR"},
+		},
+		FirstName:  "Foo",
+		LastName:   "Bar",
+		Email:      "foobar@email.com",
+		Attributes: map[string]interface{}{"plan": "pro"},
+		Tags:       []string{"VIP"},
+	}
+
+	mapper := NewDefaultUserMapper()
+	input := mapper.MapCreateRequestToInput(request)
+
+	assert.Equal(t, expectedInput, input)
+}
+
+func TestUserMapper_GivenAnInput_WhenMapInputToUpdateRequest_ThenReturnUpdateRequest(t *testing.T) {
+	t.Log("Successfully map an input to the update request used as patch document")
+
+	birthDate := time.Date(1990, 5, 17, 0, 0, 0, 0, time.UTC)
+	input := domain.UserCreateInput{
+		UserProfile: domain.UserProfile{
+			Phone:     "+5491112345678",
+			BirthDate: &birthDate,
+			Address:   &domain.Address{Line1: "Street 123", City: "Buenos Aires", Country: "AR"},
+		},
+		FirstName:   "Foo",
+		LastName:    "Bar",
+		Email:       "foobar@email.com",
+		Attributes:  map[string]interface{}{"plan": "pro", "hiredOn": time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)},
+		Tags:        []string{"vip"},
+		ExternalIDs: []domain.UserExternalID{{Source: "crm", ID: "C-1"}},
+	}
+	expectedRequest := UserUpdateRequest{
+		UserCreateRequest: UserCreateRequest{
+			FirstName:   "Foo",
+			LastName:    "Bar",
+			Email:       "foobar@email.com",
+			Phone:       "+5491112345678",
+			BirthDate:   "1990-05-17",
+			Address:     &AddressRequest{Line1: "Street 123", City: "Buenos Aires", Country: "AR"},
+			Attributes:  map[string]interface{}{"plan": "pro", "hiredOn": "2024-03-01"},
+			Tags:        []string{"vip"},
+			ExternalIDs: []ExternalIDRequest{{Source: "crm", Id: "C-1"}},
+		},
+	}
+	// The attribute dates are parsed back by the use cases
+	expectedInput := input
+	expectedInput.Attributes = map[string]interface{}{"plan": "pro", "hiredOn": "2024-03-01"}
+
+	mapper := NewDefaultUserMapper()
+	request := mapper.MapInputToUpdateRequest(input)
+
+	assert.Equal(t, expectedRequest, request)
+	assert.Equal(t, expectedInput, mapper.MapUpdateRequestToInput("USER1", request).UserCreateInput)
+}
+
+func TestUserMapper_GivenAnInputWithoutAttributes_WhenMapInputToUpdateRequest_ThenReturnEmptyAttributes(t *testing.T) {
+	t.Log("Successfully map an input without attributes, tags and external ids to a patch document where they can be added")
+
+	mapper := NewDefaultUserMapper()
+	request := mapper.MapInputToUpdateRequest(domain.UserCreateInput{FirstName: "Foo"})
+
+	assert.NotNil(t, request.Attributes)
+	assert.Empty(t, request.Attributes)
+	assert.NotNil(t, request.Tags)
+	assert.Empty(t, request.Tags)
+	assert.NotNil(t, request.ExternalIDs)
+	assert.Empty(t, request.ExternalIDs)
+}
+
+func TestUserMapper_GivenTagCounts_WhenMapDomainTagCountsToResponse_ThenReturnTagCountResponseList(t *testing.T) {
+	t.Log("Successfully map the tag counts to a response list")
+
+	mapper := NewDefaultUserMapper()
+	response := mapper.MapDomainTagCountsToResponse([]domain.TagCount{{Tag: "beta", Count: 2}, {Tag: "vip", Count: 1}})
+
+	assert.Equal(t, []TagCountResponse{{Tag: "beta", Count: 2}, {Tag: "vip", Count: 1}}, response)
+	assert.Equal(t, []TagCountResponse{}, mapper.MapDomainTagCountsToResponse(nil))
+}
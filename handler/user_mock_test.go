@@ -53,6 +53,17 @@ func (m *userMapperMock) MapCreateRequestToInput(request UserCreateRequest) doma
 	return t
 }
 
+func (m *userMapperMock) MapInputToUpdateRequest(input domain.UserCreateInput) UserUpdateRequest {
+	args := m.Called(input)
+
+	t, ok := args.Get(0).(UserUpdateRequest)
+	if !ok {
+		return UserUpdateRequest{}
+	}
+
+	return t
+}
+
 func (m *userMapperMock) MapUpdateRequestToInput(reference string, request UserUpdateRequest) domain.UserUpdateInput {
 	args := m.Called(reference, request)
 
@@ -75,6 +86,39 @@ func (m *userMapperMock) MapDomainSearchOutputToResponse(output domain.UserSearc
 	return t
 }
 
+func (m *userMapperMock) MapDomainDuplicateReportToResponse(report domain.UserDuplicateReport) UserDuplicateReportResponse {
+	args := m.Called(report)
+
+	t, ok := args.Get(0).(UserDuplicateReportResponse)
+	if !ok {
+		return UserDuplicateReportResponse{}
+	}
+
+	return t
+}
+
+func (m *userMapperMock) MapDomainTagCountsToResponse(tags []domain.TagCount) []TagCountResponse {
+	args := m.Called(tags)
+
+	t, ok := args.Get(0).([]TagCountResponse)
+	if !ok {
+		return []TagCountResponse{}
+	}
+
+	return t
+}
+
+func (m *userMapperMock) MapDomainDataExportToResponse(export domain.UserDataExport) UserDataExportResponse {
+	args := m.Called(export)
+
+	t, ok := args.Get(0).(UserDataExportResponse)
+	if !ok {
+		return UserDataExportResponse{}
+	}
+
+	return t
+}
+
 // Services
 type userCreateServiceMock struct {
 	mock.Mock
@@ -125,8 +169,23 @@ type userFindByReferenceServiceMock struct {
 	mock.Mock
 }
 
-func (s *userFindByReferenceServiceMock) Execute(reference string) (domain.User, error) {
-	args := s.Called(reference)
+func (s *userFindByReferenceServiceMock) Execute(reference string, includeInactive bool) (domain.User, error) {
+	args := s.Called(reference, includeInactive)
+
+	t, ok := args.Get(0).(domain.User)
+	if !ok {
+		return domain.User{}, errors.New("mock_error")
+	}
+
+	return t, args.Error(1)
+}
+
+type userFindByExternalIDServiceMock struct {
+	mock.Mock
+}
+
+func (s *userFindByExternalIDServiceMock) Execute(externalID domain.UserExternalID) (domain.User, error) {
+	args := s.Called(externalID)
 
 	t, ok := args.Get(0).(domain.User)
 	if !ok {
@@ -151,6 +210,21 @@ func (s *userUpdateServiceMock) Execute(input domain.UserUpdateInput) (domain.Us
 	return t, args.Error(1)
 }
 
+type userPatchServiceMock struct {
+	mock.Mock
+}
+
+func (s *userPatchServiceMock) Execute(input domain.UserPatchInput) (domain.User, error) {
+	args := s.Called(input.Reference)
+
+	t, ok := args.Get(0).(domain.User)
+	if !ok {
+		return domain.User{}, errors.New("mock_error")
+	}
+
+	return t, args.Error(1)
+}
+
 type userSearchServiceMock struct {
 	mock.Mock
 }
@@ -165,3 +239,207 @@ func (s *userSearchServiceMock) Execute(input domain.UserSearchInput) (domain.Us
 
 	return t, args.Error(1)
 }
+
+type userEraseServiceMock struct {
+	mock.Mock
+}
+
+func (s *userEraseServiceMock) Execute(reference string) (domain.User, error) {
+	args := s.Called(reference)
+
+	t, ok := args.Get(0).(domain.User)
+	if !ok {
+		return domain.User{}, errors.New("mock_error")
+	}
+
+	return t, args.Error(1)
+}
+
+type userChangeStatusServiceMock struct {
+	mock.Mock
+}
+
+func (s *userChangeStatusServiceMock) Execute(input domain.UserStatusChangeInput) (domain.User, error) {
+	args := s.Called(input)
+
+	t, ok := args.Get(0).(domain.User)
+	if !ok {
+		return domain.User{}, errors.New("mock_error")
+	}
+
+	return t, args.Error(1)
+}
+
+type userExportServiceMock struct {
+	mock.Mock
+}
+
+func (s *userExportServiceMock) Execute(reference string) (domain.UserDataExport, error) {
+	args := s.Called(reference)
+
+	t, ok := args.Get(0).(domain.UserDataExport)
+	if !ok {
+		return domain.UserDataExport{}, errors.New("mock_error")
+	}
+
+	return t, args.Error(1)
+}
+
+type userVerifyEmailServiceMock struct {
+	mock.Mock
+}
+
+func (s *userVerifyEmailServiceMock) Execute(token string) (domain.User, error) {
+	args := s.Called(token)
+
+	t, ok := args.Get(0).(domain.User)
+	if !ok {
+		return domain.User{}, errors.New("mock_error")
+	}
+
+	return t, args.Error(1)
+}
+
+type userResendEmailVerificationServiceMock struct {
+	mock.Mock
+}
+
+func (s *userResendEmailVerificationServiceMock) Execute(reference string) (domain.User, error) {
+	args := s.Called(reference)
+
+	t, ok := args.Get(0).(domain.User)
+	if !ok {
+		return domain.User{}, errors.New("mock_error")
+	}
+
+	return t, args.Error(1)
+}
+
+type userSetPasswordServiceMock struct {
+	mock.Mock
+}
+
+func (s *userSetPasswordServiceMock) Execute(input domain.UserPasswordInput) error {
+	args := s.Called(input)
+	return args.Error(0)
+}
+
+type userSetRolesServiceMock struct {
+	mock.Mock
+}
+
+func (s *userSetRolesServiceMock) Execute(input domain.UserRolesInput) (domain.User, error) {
+	args := s.Called(input)
+
+	t, ok := args.Get(0).(domain.User)
+	if !ok {
+		return domain.User{}, errors.New("mock_error")
+	}
+
+	return t, args.Error(1)
+}
+
+type userAddTagServiceMock struct {
+	mock.Mock
+}
+
+func (s *userAddTagServiceMock) Execute(input domain.UserTagInput) (domain.User, error) {
+	args := s.Called(input)
+
+	t, ok := args.Get(0).(domain.User)
+	if !ok {
+		return domain.User{}, errors.New("mock_error")
+	}
+
+	return t, args.Error(1)
+}
+
+type userRemoveTagServiceMock struct {
+	mock.Mock
+}
+
+func (s *userRemoveTagServiceMock) Execute(input domain.UserTagInput) (domain.User, error) {
+	args := s.Called(input)
+
+	t, ok := args.Get(0).(domain.User)
+	if !ok {
+		return domain.User{}, errors.New("mock_error")
+	}
+
+	return t, args.Error(1)
+}
+
+type userFindTagsServiceMock struct {
+	mock.Mock
+}
+
+func (s *userFindTagsServiceMock) Execute() ([]domain.TagCount, error) {
+	args := s.Called()
+
+	t, ok := args.Get(0).([]domain.TagCount)
+	if !ok {
+		return []domain.TagCount{}, errors.New("mock_error")
+	}
+
+	return t, args.Error(1)
+}
+
+type userSetAvatarServiceMock struct {
+	mock.Mock
+}
+
+func (s *userSetAvatarServiceMock) Execute(input domain.UserAvatarInput) (domain.User, error) {
+	args := s.Called(input)
+
+	t, ok := args.Get(0).(domain.User)
+	if !ok {
+		return domain.User{}, errors.New("mock_error")
+	}
+
+	return t, args.Error(1)
+}
+
+type userFindAvatarServiceMock struct {
+	mock.Mock
+}
+
+func (s *userFindAvatarServiceMock) Execute(reference string, size int) (domain.UserAvatarImage, error) {
+	args := s.Called(reference, size)
+
+	t, ok := args.Get(0).(domain.UserAvatarImage)
+	if !ok {
+		return domain.UserAvatarImage{}, errors.New("mock_error")
+	}
+
+	return t, args.Error(1)
+}
+
+type userFindDuplicatesServiceMock struct {
+	mock.Mock
+}
+
+func (s *userFindDuplicatesServiceMock) Execute() (domain.UserDuplicateReport, error) {
+	args := s.Called()
+
+	t, ok := args.Get(0).(domain.UserDuplicateReport)
+	if !ok {
+		return domain.UserDuplicateReport{}, errors.New("mock_error")
+	}
+
+	return t, args.Error(1)
+}
+
+type userMergeServiceMock struct {
+	mock.Mock
+}
+
+func (s *userMergeServiceMock) Execute(input domain.UserMergeInput) (domain.User, error) {
+	args := s.Called(input)
+
+	t, ok := args.Get(0).(domain.User)
+	if !ok {
+		return domain.User{}, errors.New("mock_error")
+	}
+
+	return t, args.Error(1)
+}
@@ -0,0 +1,79 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/desarrollogj/golang-api-example/domain"
+	appErrors "github.com/desarrollogj/golang-api-example/libs/errors"
+	appGin "github.com/desarrollogj/golang-api-example/libs/gin"
+	"github.com/desarrollogj/golang-api-example/user"
+	"github.com/gin-gonic/gin"
+)
+
+// UserPassword represents the method for user password endpoints handlers
+type UserPassword interface {
+	SetPassword(c *gin.Context)
+}
+
+// defaultUserPassword is the default implementation for UserPassword interface
+type defaultUserPassword struct {
+	setPassword user.SetPassword
+}
+
+// NewDefaultUserPassword creates a defaultUserPassword handler
+func NewDefaultUserPassword(setPassword user.SetPassword) defaultUserPassword {
+	validate = newValidator()
+	return defaultUserPassword{
+		setPassword: setPassword,
+	}
+}
+
+// SetPassword set an user password
+// @Tags user
+// @Summary Set an user password
+// @Description Set or replace an user password. The password must meet the password policy, and it is never returned.
+// @Description The users can change their own password with their current password, and the administrators (users:admin) any password
+// @Param id path string true "User id"
+// @Param request body handler.UserPasswordRequest true "user password"
+// @Success 204
+// @Failure 400	{object} appErrors.APIError
+// @Failure 401	{object} appErrors.APIError
+// @Failure 403	{object} appErrors.APIError
+// @Failure 404	{object} appErrors.APIError
+// @Failure 500	{object} appErrors.APIError
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /users/{id}/password [put]
+func (h defaultUserPassword) SetPassword(c *gin.Context) {
+	appGin.ErrorWrapper(h.executeSetPassword, c)
+}
+
+func (h defaultUserPassword) executeSetPassword(c *gin.Context) *appErrors.APIError {
+	reference := c.Param("id")
+	if len(reference) == 0 {
+		return appErrors.NewBadRequest("user id is required").WithKey("user_id_required")
+	}
+	// Anyone able to set a password can log in as the user, so only the user or an administrator can do it
+	identity, _ := GetIdentity(c)
+	isAdmin := identity.HasPermission(domain.PermissionUsersAdmin)
+	if !isAdmin && identity.Subject != reference {
+		return appErrors.NewForbidden("only the user or an administrator can set the user password").WithKey("user_password_forbidden")
+	}
+	var req UserPasswordRequest
+	if apiErr := bindJSON(c, &req); apiErr != nil {
+		return apiErr
+	}
+
+	err := h.setPassword.Execute(domain.UserPasswordInput{
+		Reference:              reference,
+		Password:               req.Password,
+		CurrentPassword:        req.CurrentPassword,
+		RequireCurrentPassword: !isAdmin,
+	})
+	if err != nil {
+		return appErrors.HandleBusinessError(err)
+	}
+
+	c.Status(http.StatusNoContent)
+	return nil
+}
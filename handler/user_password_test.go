@@ -0,0 +1,153 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/desarrollogj/golang-api-example/domain"
+	libErrors "github.com/desarrollogj/golang-api-example/libs/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestUserPassword_GivenAPasswordByAnAdministrator_WhenSetPassword_ThenReturnNoContentResponse(t *testing.T) {
+	t.Log("Successfully set an user password by an administrator, without the current password")
+
+	setPasswordMock := new(userSetPasswordServiceMock)
+	setPasswordMock.On("Execute", domain.UserPasswordInput{Reference: "USER1", Password: "Secret-Password1"}).Return(nil)
+
+	handler := NewDefaultUserPassword(setPasswordMock)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPut, "/api/v1/users/USER1/password", bytes.NewBufferString(`{"password":"Secret-Password1"}`))
+
+	r := testRouter()
+	r.PUT("/api/v1/users/:id/password", withIdentity(domain.PermissionUsersAdmin), handler.SetPassword)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Equal(t, 0, w.Body.Len())
+
+	setPasswordMock.AssertExpectations(t)
+}
+
+func TestUserPassword_GivenAWeakPassword_WhenSetPassword_ThenReturnBadRequestResponse(t *testing.T) {
+	t.Log("Failure to set an user password because it does not meet the policy")
+
+	setPasswordMock := new(userSetPasswordServiceMock)
+	setPasswordMock.On("Execute", mock.AnythingOfType("UserPasswordInput")).
+		Return(libErrors.NewValidationError("password must have at least 12 characters"))
+
+	handler := NewDefaultUserPassword(setPasswordMock)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPut, "/api/v1/users/USER1/password", bytes.NewBufferString(`{"password":"secret"}`))
+
+	r := testRouter()
+	r.PUT("/api/v1/users/:id/password", withIdentity(domain.PermissionUsersAdmin), handler.SetPassword)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	var err libErrors.APIError
+	json.NewDecoder(w.Body).Decode(&err)
+
+	assert.Equal(t, "password must have at least 12 characters", err.Message)
+
+	setPasswordMock.AssertExpectations(t)
+}
+
+func TestUserPassword_GivenARequestWithoutPassword_WhenSetPassword_ThenReturnBadRequestResponse(t *testing.T) {
+	t.Log("Failure to set an user password because the password is missing")
+
+	setPasswordMock := new(userSetPasswordServiceMock)
+
+	handler := NewDefaultUserPassword(setPasswordMock)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPut, "/api/v1/users/USER1/password", bytes.NewBufferString(`{}`))
+
+	r := testRouter()
+	r.PUT("/api/v1/users/:id/password", withIdentity(domain.PermissionUsersAdmin), handler.SetPassword)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	setPasswordMock.AssertNotCalled(t, "Execute", mock.Anything)
+}
+
+func TestUserPassword_GivenAPasswordByTheSameUser_WhenSetPassword_ThenReturnNoContentResponse(t *testing.T) {
+	t.Log("Successfully change the own password, with the current password")
+
+	setPasswordMock := new(userSetPasswordServiceMock)
+	setPasswordMock.On("Execute", domain.UserPasswordInput{
+		Reference:              "CALLER",
+		Password:               "Secret-Password1",
+		CurrentPassword:        "Old-Password1",
+		RequireCurrentPassword: true,
+	}).Return(nil)
+
+	handler := NewDefaultUserPassword(setPasswordMock)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPut, "/api/v1/users/CALLER/password",
+		bytes.NewBufferString(`{"password":"Secret-Password1","currentPassword":"Old-Password1"}`))
+
+	r := testRouter()
+	r.PUT("/api/v1/users/:id/password", withIdentity(domain.PermissionUsersWrite), handler.SetPassword)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+
+	setPasswordMock.AssertExpectations(t)
+}
+
+func TestUserPassword_GivenAPasswordOfOtherUserByAnEditor_WhenSetPassword_ThenReturnForbiddenResponse(t *testing.T) {
+	t.Log("Failure to set the password of other user because the caller is not an administrator")
+
+	setPasswordMock := new(userSetPasswordServiceMock)
+
+	handler := NewDefaultUserPassword(setPasswordMock)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPut, "/api/v1/users/ADMIN1/password", bytes.NewBufferString(`{"password":"Secret-Password1"}`))
+
+	r := testRouter()
+	r.PUT("/api/v1/users/:id/password", withIdentity(domain.PermissionUsersRead, domain.PermissionUsersWrite), handler.SetPassword)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	var err libErrors.APIError
+	json.NewDecoder(w.Body).Decode(&err)
+
+	assert.Equal(t, "forbidden", err.Err)
+	assert.Equal(t, "only the user or an administrator can set the user password", err.Message)
+
+	setPasswordMock.AssertNotCalled(t, "Execute", mock.Anything)
+}
+
+func TestUserPassword_GivenAWrongCurrentPassword_WhenSetPassword_ThenReturnForbiddenResponse(t *testing.T) {
+	t.Log("Failure to change the own password because the current password is not valid")
+
+	setPasswordMock := new(userSetPasswordServiceMock)
+	setPasswordMock.On("Execute", mock.AnythingOfType("UserPasswordInput")).
+		Return(libErrors.NewForbiddenError("current password is not valid"))
+
+	handler := NewDefaultUserPassword(setPasswordMock)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPut, "/api/v1/users/CALLER/password",
+		bytes.NewBufferString(`{"password":"Secret-Password1","currentPassword":"wrong"}`))
+
+	r := testRouter()
+	r.PUT("/api/v1/users/:id/password", withIdentity(domain.PermissionUsersWrite), handler.SetPassword)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	setPasswordMock.AssertExpectations(t)
+}
@@ -0,0 +1,162 @@
+package handler
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+
+	appErrors "github.com/desarrollogj/golang-api-example/libs/errors"
+	appGin "github.com/desarrollogj/golang-api-example/libs/gin"
+	"github.com/desarrollogj/golang-api-example/user"
+	"github.com/gin-gonic/gin"
+)
+
+// UserPrivacy represents the method for user data protection endpoints handlers
+type UserPrivacy interface {
+	Erase(c *gin.Context)
+	Export(c *gin.Context)
+}
+
+// defaultUserPrivacy is the default implementation for UserPrivacy interface
+type defaultUserPrivacy struct {
+	mapper UserMapper
+	erase  user.Erase
+	export user.Export
+}
+
+// NewDefaultUserPrivacy creates a defaultUserPrivacy handler
+func NewDefaultUserPrivacy(mapper UserMapper, erase user.Erase, export user.Export) defaultUserPrivacy {
+	return defaultUserPrivacy{
+		mapper: mapper,
+		erase:  erase,
+		export: export,
+	}
+}
+
+// Erase erase an user
+// @Tags user
+// @Summary Erase an user
+// @Description Irreversibly replaces the user personal data. The user id and dates are kept. Erasing an erased user has no effect
+// @Param id path string true "User id"
+// @Param Idempotency-Key header string false "client key to process the request only once"
+// @Produce json
+// @Success 200 {object} handler.UserResponse
+// @Failure 400	{object} appErrors.APIError
+// @Failure 401	{object} appErrors.APIError
+// @Failure 403	{object} appErrors.APIError
+// @Failure 404	{object} appErrors.APIError
+// @Failure 422	{object} appErrors.APIError
+// @Failure 500	{object} appErrors.APIError
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /users/{id}/erase [post]
+func (h defaultUserPrivacy) Erase(c *gin.Context) {
+	appGin.ErrorWrapper(h.executeErase, c)
+}
+
+func (h defaultUserPrivacy) executeErase(c *gin.Context) *appErrors.APIError {
+	reference := c.Param("id")
+	if len(reference) == 0 {
+		return appErrors.NewBadRequest("user id is required").WithKey("user_id_required")
+	}
+
+	erased, err := h.erase.Execute(reference)
+	if err != nil {
+		return appErrors.HandleBusinessError(err)
+	}
+
+	c.JSON(http.StatusOK, h.mapper.MapDomainToResponse(erased))
+	return nil
+}
+
+// Export export all the data held about an user
+// @Tags user
+// @Summary Export an user data
+// @Description Export all the data held about an user, including inactive users, as a JSON document or a zip file
+// @Param id path string true "User id"
+// @Param format query string false "Export format: json (default) or zip"
+// @Produce json
+// @Produce application/zip
+// @Success 200 {object} handler.UserDataExportResponse
+// @Failure 400	{object} appErrors.APIError
+// @Failure 401	{object} appErrors.APIError
+// @Failure 403	{object} appErrors.APIError
+// @Failure 404	{object} appErrors.APIError
+// @Failure 500	{object} appErrors.APIError
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /users/{id}/data-export [get]
+func (h defaultUserPrivacy) Export(c *gin.Context) {
+	appGin.ErrorWrapper(h.executeExport, c)
+}
+
+func (h defaultUserPrivacy) executeExport(c *gin.Context) *appErrors.APIError {
+	reference := c.Param("id")
+	if len(reference) == 0 {
+		return appErrors.NewBadRequest("user id is required").WithKey("user_id_required")
+	}
+	format := c.DefaultQuery("format", "json")
+	if format != "json" && format != "zip" {
+		return appErrors.NewBadRequest("format must be json or zip").WithKey("export_format_not_valid")
+	}
+
+	export, err := h.export.Execute(reference)
+	if err != nil {
+		return appErrors.HandleBusinessError(err)
+	}
+
+	response := h.mapper.MapDomainDataExportToResponse(export)
+	if format == "json" {
+		c.JSON(http.StatusOK, response)
+		return nil
+	}
+
+	content, err := dataExportZip(response)
+	if err != nil {
+		return appErrors.NewInternalServerError("unexpected error when create the export file").WithKey("export_file_failed")
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=\"user-%s-export.zip\"", response.Id))
+	c.Data(http.StatusOK, "application/zip", content)
+	return nil
+}
+
+// dataExportZip creates a zip file with the whole export and one file per section
+func dataExportZip(response UserDataExportResponse) ([]byte, error) {
+	buffer := new(bytes.Buffer)
+	archive := zip.NewWriter(buffer)
+
+	names := []string{}
+	for name := range response.Sections {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	if err := writeZipJSON(archive, "export.json", response); err != nil {
+		return nil, err
+	}
+	for _, name := range names {
+		if err := writeZipJSON(archive, fmt.Sprintf("sections/%s.json", name), response.Sections[name]); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := archive.Close(); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+func writeZipJSON(archive *zip.Writer, name string, content interface{}) error {
+	file, err := archive.Create(name)
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(content)
+}
@@ -0,0 +1,284 @@
+package handler
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/desarrollogj/golang-api-example/domain"
+	libErrors "github.com/desarrollogj/golang-api-example/libs/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUserPrivacy_GivenAnEraseRequest_WhenErase_ThenReturnErasedUserResponse(t *testing.T) {
+	t.Log("Successfully erase an user")
+
+	current := time.Now().UTC()
+	currentStr := current.Format(time.RFC3339)
+	reference := "USER1"
+	domainUser := domain.User{
+		GenericEntity: domain.GenericEntity{
+			Reference:   reference,
+			IsActive:    false,
+			CreatedDate: current,
+			UpdatedDate: current,
+		},
+		FirstName:  "erased",
+		LastName:   "erased",
+		Email:      "erased-USER1@erased.invalid",
+		ErasedDate: &current,
+	}
+	responseUser := UserResponse{
+		Id:          reference,
+		FirstName:   "erased",
+		LastName:    "erased",
+		Email:       "erased-USER1@erased.invalid",
+		IsActive:    false,
+		CreatedDate: currentStr,
+		UpdatedDate: currentStr,
+	}
+
+	mapperMock := new(userMapperMock)
+	mapperMock.On("MapDomainToResponse", domainUser).Return(responseUser)
+	eraseMock := new(userEraseServiceMock)
+	eraseMock.On("Execute", reference).Return(domainUser, nil)
+
+	exportMock := new(userExportServiceMock)
+
+	handler := NewDefaultUserPrivacy(mapperMock, eraseMock, exportMock)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/users/USER1/erase", nil)
+
+	r := testRouter()
+	r.POST("/api/v1/users/:id/erase", handler.Erase)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var result UserResponse
+	json.NewDecoder(w.Body).Decode(&result)
+
+	assert.Equal(t, responseUser, result)
+
+	mapperMock.AssertExpectations(t)
+	eraseMock.AssertExpectations(t)
+}
+
+func TestUserPrivacy_GivenAnEraseRequest_WhenErase_AndUserNotFound_ThenReturnNotFoundResponse(t *testing.T) {
+	t.Log("Failure to erase an user because it was not found")
+
+	reference := "USER1"
+
+	mapperMock := new(userMapperMock)
+	eraseMock := new(userEraseServiceMock)
+	eraseMock.On("Execute", reference).Return(domain.User{}, libErrors.NewNotFoundError("user not found"))
+
+	exportMock := new(userExportServiceMock)
+
+	handler := NewDefaultUserPrivacy(mapperMock, eraseMock, exportMock)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/users/USER1/erase", nil)
+
+	r := testRouter()
+	r.POST("/api/v1/users/:id/erase", handler.Erase)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	var err libErrors.APIError
+	json.NewDecoder(w.Body).Decode(&err)
+
+	assert.Equal(t, http.StatusNotFound, err.Status)
+	assert.Equal(t, "user not found", err.Message)
+
+	mapperMock.AssertExpectations(t)
+	eraseMock.AssertExpectations(t)
+}
+
+func TestUserPrivacy_GivenAnEraseRequest_WhenErase_AndServiceReturnedAnError_ThenReturnInternalServerErrorResponse(t *testing.T) {
+	t.Log("Failure to erase an user because service returned an error")
+
+	reference := "USER1"
+
+	mapperMock := new(userMapperMock)
+	eraseMock := new(userEraseServiceMock)
+	eraseMock.On("Execute", reference).Return(domain.User{}, errors.New("service error"))
+
+	exportMock := new(userExportServiceMock)
+
+	handler := NewDefaultUserPrivacy(mapperMock, eraseMock, exportMock)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/users/USER1/erase", nil)
+
+	r := testRouter()
+	r.POST("/api/v1/users/:id/erase", handler.Erase)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+
+	var err libErrors.APIError
+	json.NewDecoder(w.Body).Decode(&err)
+
+	assert.Equal(t, http.StatusInternalServerError, err.Status)
+	assert.Equal(t, "service error", err.Message)
+
+	mapperMock.AssertExpectations(t)
+	eraseMock.AssertExpectations(t)
+}
+
+func TestUserPrivacy_GivenAnExportRequest_WhenExport_ThenReturnExportResponse(t *testing.T) {
+	t.Log("Successfully export an user data as JSON")
+
+	current := time.Now().UTC()
+	reference := "USER1"
+	domainExport := domain.UserDataExport{
+		Reference:     reference,
+		GeneratedDate: current,
+		Sections: []domain.UserDataExportSection{
+			{Name: "user", Records: []map[string]interface{}{{"id": reference}}},
+		},
+	}
+	exportResponse := UserDataExportResponse{
+		Id:            reference,
+		GeneratedDate: current.Format(time.RFC3339),
+		Sections: map[string][]map[string]interface{}{
+			"user": {{"id": reference}},
+		},
+	}
+
+	mapperMock := new(userMapperMock)
+	mapperMock.On("MapDomainDataExportToResponse", domainExport).Return(exportResponse)
+	eraseMock := new(userEraseServiceMock)
+	exportMock := new(userExportServiceMock)
+	exportMock.On("Execute", reference).Return(domainExport, nil)
+
+	handler := NewDefaultUserPrivacy(mapperMock, eraseMock, exportMock)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/users/USER1/data-export", nil)
+
+	r := testRouter()
+	r.GET("/api/v1/users/:id/data-export", handler.Export)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var result UserDataExportResponse
+	json.NewDecoder(w.Body).Decode(&result)
+
+	assert.Equal(t, exportResponse, result)
+
+	mapperMock.AssertExpectations(t)
+	exportMock.AssertExpectations(t)
+}
+
+func TestUserPrivacy_GivenAZipExportRequest_WhenExport_ThenReturnZipFile(t *testing.T) {
+	t.Log("Successfully export an user data as a zip file")
+
+	current := time.Now().UTC()
+	reference := "USER1"
+	domainExport := domain.UserDataExport{
+		Reference:     reference,
+		GeneratedDate: current,
+	}
+	exportResponse := UserDataExportResponse{
+		Id:            reference,
+		GeneratedDate: current.Format(time.RFC3339),
+		Sections: map[string][]map[string]interface{}{
+			"user":  {{"id": reference}},
+			"audit": {},
+		},
+	}
+
+	mapperMock := new(userMapperMock)
+	mapperMock.On("MapDomainDataExportToResponse", domainExport).Return(exportResponse)
+	eraseMock := new(userEraseServiceMock)
+	exportMock := new(userExportServiceMock)
+	exportMock.On("Execute", reference).Return(domainExport, nil)
+
+	handler := NewDefaultUserPrivacy(mapperMock, eraseMock, exportMock)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/users/USER1/data-export?format=zip", nil)
+
+	r := testRouter()
+	r.GET("/api/v1/users/:id/data-export", handler.Export)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/zip", w.Header().Get("Content-Type"))
+	assert.Equal(t, "attachment; filename=\"user-USER1-export.zip\"", w.Header().Get("Content-Disposition"))
+
+	body, _ := io.ReadAll(w.Body)
+	archive, err := zip.NewReader(bytes.NewReader(body), int64(len(body)))
+	assert.Nil(t, err)
+
+	names := []string{}
+	for _, file := range archive.File {
+		names = append(names, file.Name)
+	}
+	assert.Equal(t, []string{"export.json", "sections/audit.json", "sections/user.json"}, names)
+
+	mapperMock.AssertExpectations(t)
+	exportMock.AssertExpectations(t)
+}
+
+func TestUserPrivacy_GivenAnExportRequestWithInvalidFormat_WhenExport_ThenReturnBadRequestResponse(t *testing.T) {
+	t.Log("Failure to export an user data because the format is not valid")
+
+	mapperMock := new(userMapperMock)
+	eraseMock := new(userEraseServiceMock)
+	exportMock := new(userExportServiceMock)
+
+	handler := NewDefaultUserPrivacy(mapperMock, eraseMock, exportMock)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/users/USER1/data-export?format=xml", nil)
+
+	r := testRouter()
+	r.GET("/api/v1/users/:id/data-export", handler.Export)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	var err libErrors.APIError
+	json.NewDecoder(w.Body).Decode(&err)
+
+	assert.Equal(t, "format must be json or zip", err.Message)
+
+	exportMock.AssertNotCalled(t, "Execute")
+}
+
+func TestUserPrivacy_GivenAnExportRequest_WhenExport_AndUserNotFound_ThenReturnNotFoundResponse(t *testing.T) {
+	t.Log("Failure to export an user data because the user was not found")
+
+	reference := "USER1"
+
+	mapperMock := new(userMapperMock)
+	eraseMock := new(userEraseServiceMock)
+	exportMock := new(userExportServiceMock)
+	exportMock.On("Execute", reference).Return(domain.UserDataExport{}, libErrors.NewNotFoundError("user not found"))
+
+	handler := NewDefaultUserPrivacy(mapperMock, eraseMock, exportMock)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/users/USER1/data-export", nil)
+
+	r := testRouter()
+	r.GET("/api/v1/users/:id/data-export", handler.Export)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	mapperMock.AssertExpectations(t)
+	exportMock.AssertExpectations(t)
+}
@@ -0,0 +1,79 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/desarrollogj/golang-api-example/domain"
+	appErrors "github.com/desarrollogj/golang-api-example/libs/errors"
+	appGin "github.com/desarrollogj/golang-api-example/libs/gin"
+	"github.com/desarrollogj/golang-api-example/user"
+	"github.com/gin-gonic/gin"
+)
+
+// UserRoles represents the method for user roles endpoints handlers
+type UserRoles interface {
+	SetRoles(c *gin.Context)
+}
+
+// defaultUserRoles is the default implementation for UserRoles interface
+type defaultUserRoles struct {
+	mapper   UserMapper
+	setRoles user.SetRoles
+}
+
+// NewDefaultUserRoles creates a defaultUserRoles handler
+func NewDefaultUserRoles(mapper UserMapper, setRoles user.SetRoles) defaultUserRoles {
+	validate = newValidator()
+	return defaultUserRoles{
+		mapper:   mapper,
+		setRoles: setRoles,
+	}
+}
+
+// SetRoles set an user roles
+// @Tags user
+// @Summary Set an user roles
+// @Description Replace the user roles. The roles must be configured, and users can not change their own roles
+// @Param id path string true "User id"
+// @Param request body handler.UserRolesRequest true "user roles"
+// @Produce json
+// @Success 200 {object} handler.UserResponse
+// @Failure 400	{object} appErrors.APIError
+// @Failure 401	{object} appErrors.APIError
+// @Failure 403	{object} appErrors.APIError
+// @Failure 404	{object} appErrors.APIError
+// @Failure 500	{object} appErrors.APIError
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /users/{id}/roles [put]
+func (h defaultUserRoles) SetRoles(c *gin.Context) {
+	appGin.ErrorWrapper(h.executeSetRoles, c)
+}
+
+func (h defaultUserRoles) executeSetRoles(c *gin.Context) *appErrors.APIError {
+	reference := c.Param("id")
+	if len(reference) == 0 {
+		return appErrors.NewBadRequest("user id is required").WithKey("user_id_required")
+	}
+	var req UserRolesRequest
+	if apiErr := bindJSON(c, &req); apiErr != nil {
+		return apiErr
+	}
+
+	input := domain.UserRolesInput{Reference: reference}
+	for _, role := range req.Roles {
+		input.Roles = append(input.Roles, strings.TrimSpace(role))
+	}
+	if identity, ok := GetIdentity(c); ok {
+		input.ChangedBy = identity.Subject
+	}
+
+	updated, err := h.setRoles.Execute(input)
+	if err != nil {
+		return appErrors.HandleBusinessError(err)
+	}
+
+	c.JSON(http.StatusOK, h.mapper.MapDomainToResponse(updated))
+	return nil
+}
@@ -0,0 +1,104 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/desarrollogj/golang-api-example/domain"
+	libErrors "github.com/desarrollogj/golang-api-example/libs/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestUserRoles_GivenARolesRequest_WhenSetRoles_ThenReturnUpdatedUserResponse(t *testing.T) {
+	t.Log("Successfully set an user roles")
+
+	domainUser := domain.User{
+		GenericEntity: domain.GenericEntity{Reference: "USER1", IsActive: true},
+		Email:         "foobar@email.com",
+		Roles:         []string{"editor"},
+	}
+	responseUser := UserResponse{
+		Id:       "USER1",
+		Email:    "foobar@email.com",
+		IsActive: true,
+		Roles:    []string{"editor"},
+	}
+
+	mapperMock := new(userMapperMock)
+	mapperMock.On("MapDomainToResponse", domainUser).Return(responseUser)
+	setRolesMock := new(userSetRolesServiceMock)
+	setRolesMock.On("Execute", domain.UserRolesInput{
+		Reference: "USER1",
+		Roles:     []string{"editor"},
+		ChangedBy: "CALLER",
+	}).Return(domainUser, nil)
+
+	handler := NewDefaultUserRoles(mapperMock, setRolesMock)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPut, "/api/v1/users/USER1/roles", bytes.NewBufferString(`{"roles":[" editor "]}`))
+
+	r := testRouter()
+	r.PUT("/api/v1/users/:id/roles", withIdentity(domain.PermissionUsersAdmin), handler.SetRoles)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var result UserResponse
+	json.NewDecoder(w.Body).Decode(&result)
+
+	assert.Equal(t, responseUser, result)
+
+	mapperMock.AssertExpectations(t)
+	setRolesMock.AssertExpectations(t)
+}
+
+func TestUserRoles_GivenANotValidRolesRequest_WhenSetRoles_ThenReturnBadRequestResponse(t *testing.T) {
+	t.Log("Failure to set an user roles because the request body is not valid")
+
+	setRolesMock := new(userSetRolesServiceMock)
+
+	handler := NewDefaultUserRoles(new(userMapperMock), setRolesMock)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPut, "/api/v1/users/USER1/roles", bytes.NewBufferString(`{"roles":[""]}`))
+
+	r := testRouter()
+	r.PUT("/api/v1/users/:id/roles", handler.SetRoles)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	setRolesMock.AssertNotCalled(t, "Execute", mock.Anything)
+}
+
+func TestUserRoles_GivenTheCallerReference_WhenSetRoles_ThenReturnForbiddenResponse(t *testing.T) {
+	t.Log("Failure to set an user roles because users can not change their own roles")
+
+	setRolesMock := new(userSetRolesServiceMock)
+	setRolesMock.On("Execute", mock.AnythingOfType("UserRolesInput")).
+		Return(domain.User{}, libErrors.NewForbiddenError("users can not change their own roles"))
+
+	handler := NewDefaultUserRoles(new(userMapperMock), setRolesMock)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPut, "/api/v1/users/CALLER/roles", bytes.NewBufferString(`{"roles":["admin"]}`))
+
+	r := testRouter()
+	r.PUT("/api/v1/users/:id/roles", withIdentity(domain.PermissionUsersAdmin), handler.SetRoles)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	var err libErrors.APIError
+	json.NewDecoder(w.Body).Decode(&err)
+
+	assert.Equal(t, "users can not change their own roles", err.Message)
+	assert.Equal(t, libErrors.ForbiddenErrorCode, err.Err)
+
+	setRolesMock.AssertExpectations(t)
+}
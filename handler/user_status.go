@@ -0,0 +1,107 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/desarrollogj/golang-api-example/domain"
+	appErrors "github.com/desarrollogj/golang-api-example/libs/errors"
+	appGin "github.com/desarrollogj/golang-api-example/libs/gin"
+	"github.com/desarrollogj/golang-api-example/user"
+	"github.com/gin-gonic/gin"
+)
+
+// UserStatus represents the method for user status endpoints handlers
+type UserStatus interface {
+	Suspend(c *gin.Context)
+	Reactivate(c *gin.Context)
+}
+
+// defaultUserStatus is the default implementation for UserStatus interface
+type defaultUserStatus struct {
+	mapper       UserMapper
+	changeStatus user.ChangeStatus
+}
+
+// NewDefaultUserStatus creates a defaultUserStatus handler
+func NewDefaultUserStatus(mapper UserMapper, changeStatus user.ChangeStatus) defaultUserStatus {
+	validate = newValidator()
+	return defaultUserStatus{
+		mapper:       mapper,
+		changeStatus: changeStatus,
+	}
+}
+
+// Suspend suspend an user
+// @Tags user
+// @Summary Suspend an user
+// @Description Suspend an active user. A reason is required
+// @Param id path string true "User id"
+// @Param request body handler.UserStatusChangeRequest true "suspension reason"
+// @Param Idempotency-Key header string false "client key to process the request only once"
+// @Produce json
+// @Success 200 {object} handler.UserResponse
+// @Failure 400	{object} appErrors.APIError
+// @Failure 401	{object} appErrors.APIError
+// @Failure 403	{object} appErrors.APIError
+// @Failure 404	{object} appErrors.APIError
+// @Failure 422	{object} appErrors.APIError
+// @Failure 500	{object} appErrors.APIError
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /users/{id}/suspend [post]
+func (h defaultUserStatus) Suspend(c *gin.Context) {
+	appGin.ErrorWrapper(func(c *gin.Context) *appErrors.APIError {
+		return h.executeChangeStatus(c, domain.UserStatusSuspended)
+	}, c)
+}
+
+// Reactivate reactivate an user
+// @Tags user
+// @Summary Reactivate an user
+// @Description Reactivate a suspended or deleted user
+// @Param id path string true "User id"
+// @Param request body handler.UserStatusChangeRequest false "reactivation reason"
+// @Param Idempotency-Key header string false "client key to process the request only once"
+// @Produce json
+// @Success 200 {object} handler.UserResponse
+// @Failure 400	{object} appErrors.APIError
+// @Failure 401	{object} appErrors.APIError
+// @Failure 403	{object} appErrors.APIError
+// @Failure 404	{object} appErrors.APIError
+// @Failure 422	{object} appErrors.APIError
+// @Failure 500	{object} appErrors.APIError
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /users/{id}/reactivate [post]
+func (h defaultUserStatus) Reactivate(c *gin.Context) {
+	appGin.ErrorWrapper(func(c *gin.Context) *appErrors.APIError {
+		return h.executeChangeStatus(c, domain.UserStatusActive)
+	}, c)
+}
+
+func (h defaultUserStatus) executeChangeStatus(c *gin.Context, status domain.UserStatus) *appErrors.APIError {
+	reference := c.Param("id")
+	if len(reference) == 0 {
+		return appErrors.NewBadRequest("user id is required").WithKey("user_id_required")
+	}
+	// The request body is optional
+	var req UserStatusChangeRequest
+	if c.Request.ContentLength != 0 {
+		if apiErr := bindJSON(c, &req); apiErr != nil {
+			return apiErr
+		}
+	}
+
+	updated, err := h.changeStatus.Execute(domain.UserStatusChangeInput{
+		Reference: reference,
+		Status:    status,
+		Reason:    strings.TrimSpace(req.Reason),
+	})
+	if err != nil {
+		return appErrors.HandleBusinessError(err)
+	}
+
+	c.JSON(http.StatusOK, h.mapper.MapDomainToResponse(updated))
+	return nil
+}
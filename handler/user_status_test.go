@@ -0,0 +1,165 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/desarrollogj/golang-api-example/domain"
+	libErrors "github.com/desarrollogj/golang-api-example/libs/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestUserStatus_GivenASuspendRequest_WhenSuspend_ThenReturnSuspendedUserResponse(t *testing.T) {
+	t.Log("Successfully suspend an user")
+
+	current := time.Now().UTC()
+	currentStr := current.Format(time.RFC3339)
+	reference := "USER1"
+	domainUser := domain.User{
+		GenericEntity: domain.GenericEntity{
+			Reference:   reference,
+			IsActive:    false,
+			CreatedDate: current,
+			UpdatedDate: current,
+		},
+		FirstName:    "Foo",
+		LastName:     "Bar",
+		Email:        "foobar@email.com",
+		Status:       domain.UserStatusSuspended,
+		StatusReason: "abuse report",
+		StatusDate:   current,
+	}
+	responseUser := UserResponse{
+		Id:           reference,
+		FirstName:    "Foo",
+		LastName:     "Bar",
+		Email:        "foobar@email.com",
+		IsActive:     false,
+		Status:       "suspended",
+		StatusReason: "abuse report",
+		StatusDate:   currentStr,
+		CreatedDate:  currentStr,
+		UpdatedDate:  currentStr,
+	}
+
+	mapperMock := new(userMapperMock)
+	mapperMock.On("MapDomainToResponse", domainUser).Return(responseUser)
+	changeStatusMock := new(userChangeStatusServiceMock)
+	changeStatusMock.On("Execute", domain.UserStatusChangeInput{
+		Reference: reference,
+		Status:    domain.UserStatusSuspended,
+		Reason:    "abuse report",
+	}).Return(domainUser, nil)
+
+	handler := NewDefaultUserStatus(mapperMock, changeStatusMock)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/users/USER1/suspend", bytes.NewBufferString(`{"reason":" abuse report "}`))
+
+	r := testRouter()
+	r.POST("/api/v1/users/:id/suspend", handler.Suspend)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var result UserResponse
+	json.NewDecoder(w.Body).Decode(&result)
+
+	assert.Equal(t, responseUser, result)
+
+	mapperMock.AssertExpectations(t)
+	changeStatusMock.AssertExpectations(t)
+}
+
+func TestUserStatus_GivenAReactivateRequestWithoutBody_WhenReactivate_ThenReturnActiveUserResponse(t *testing.T) {
+	t.Log("Successfully reactivate an user without a reason")
+
+	reference := "USER1"
+	domainUser := domain.User{
+		GenericEntity: domain.GenericEntity{
+			Reference: reference,
+			IsActive:  true,
+		},
+		Status: domain.UserStatusActive,
+	}
+	responseUser := UserResponse{
+		Id:       reference,
+		IsActive: true,
+		Status:   "active",
+	}
+
+	mapperMock := new(userMapperMock)
+	mapperMock.On("MapDomainToResponse", domainUser).Return(responseUser)
+	changeStatusMock := new(userChangeStatusServiceMock)
+	changeStatusMock.On("Execute", domain.UserStatusChangeInput{
+		Reference: reference,
+		Status:    domain.UserStatusActive,
+	}).Return(domainUser, nil)
+
+	handler := NewDefaultUserStatus(mapperMock, changeStatusMock)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/users/USER1/reactivate", nil)
+
+	r := testRouter()
+	r.POST("/api/v1/users/:id/reactivate", handler.Reactivate)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	mapperMock.AssertExpectations(t)
+	changeStatusMock.AssertExpectations(t)
+}
+
+func TestUserStatus_GivenANotValidRequest_WhenSuspend_ThenReturnBadRequestResponse(t *testing.T) {
+	t.Log("Failure to suspend an user because the request body is not valid")
+
+	mapperMock := new(userMapperMock)
+	changeStatusMock := new(userChangeStatusServiceMock)
+
+	handler := NewDefaultUserStatus(mapperMock, changeStatusMock)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/users/USER1/suspend", bytes.NewBufferString(`{"reason":`))
+
+	r := testRouter()
+	r.POST("/api/v1/users/:id/suspend", handler.Suspend)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	changeStatusMock.AssertNotCalled(t, "Execute", mock.Anything)
+}
+
+func TestUserStatus_GivenAnIllegalTransition_WhenSuspend_ThenReturnBadRequestResponse(t *testing.T) {
+	t.Log("Failure to suspend an user because the status transition is not allowed")
+
+	mapperMock := new(userMapperMock)
+	changeStatusMock := new(userChangeStatusServiceMock)
+	changeStatusMock.On("Execute", mock.AnythingOfType("UserStatusChangeInput")).
+		Return(domain.User{}, libErrors.NewValidationError("user can not change from deleted to suspended"))
+
+	handler := NewDefaultUserStatus(mapperMock, changeStatusMock)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/users/USER1/suspend", bytes.NewBufferString(`{"reason":"abuse report"}`))
+
+	r := testRouter()
+	r.POST("/api/v1/users/:id/suspend", handler.Suspend)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	var err libErrors.APIError
+	json.NewDecoder(w.Body).Decode(&err)
+
+	assert.Equal(t, "user can not change from deleted to suspended", err.Message)
+	assert.Equal(t, libErrors.ValidationErrorCode, err.Err)
+
+	changeStatusMock.AssertExpectations(t)
+}
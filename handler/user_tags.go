@@ -0,0 +1,147 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/desarrollogj/golang-api-example/domain"
+	appErrors "github.com/desarrollogj/golang-api-example/libs/errors"
+	appGin "github.com/desarrollogj/golang-api-example/libs/gin"
+	"github.com/desarrollogj/golang-api-example/user"
+	"github.com/gin-gonic/gin"
+)
+
+// UserTags represents the method for user tags endpoints handlers
+type UserTags interface {
+	AddTag(c *gin.Context)
+	RemoveTag(c *gin.Context)
+	FindTags(c *gin.Context)
+}
+
+// defaultUserTags is the default implementation for UserTags interface
+type defaultUserTags struct {
+	mapper    UserMapper
+	addTag    user.AddTag
+	removeTag user.RemoveTag
+	findTags  user.FindTags
+}
+
+// NewDefaultUserTags creates a defaultUserTags handler
+func NewDefaultUserTags(mapper UserMapper, addTag user.AddTag, removeTag user.RemoveTag, findTags user.FindTags) defaultUserTags {
+	return defaultUserTags{
+		mapper:    mapper,
+		addTag:    addTag,
+		removeTag: removeTag,
+		findTags:  findTags,
+	}
+}
+
+// AddTag add a tag to an user
+// @Tags user
+// @Summary Add an user tag
+// @Description Add a tag to an user. The tag is normalized to lower case, with hyphens instead of spaces. Adding a current tag has no effect
+// @Param id path string true "User id"
+// @Param tag path string true "Tag"
+// @Produce json
+// @Success 200 {object} handler.UserResponse
+// @Failure 400	{object} appErrors.APIError
+// @Failure 401	{object} appErrors.APIError
+// @Failure 403	{object} appErrors.APIError
+// @Failure 404	{object} appErrors.APIError
+// @Failure 500	{object} appErrors.APIError
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /users/{id}/tags/{tag} [put]
+func (h defaultUserTags) AddTag(c *gin.Context) {
+	appGin.ErrorWrapper(h.executeAddTag, c)
+}
+
+func (h defaultUserTags) executeAddTag(c *gin.Context) *appErrors.APIError {
+	input, apiErr := h.tagInput(c)
+	if apiErr != nil {
+		return apiErr
+	}
+
+	updated, err := h.addTag.Execute(input)
+	if err != nil {
+		return appErrors.HandleBusinessError(err)
+	}
+
+	c.JSON(http.StatusOK, h.mapper.MapDomainToResponse(updated))
+	return nil
+}
+
+// RemoveTag remove a tag from an user
+// @Tags user
+// @Summary Remove an user tag
+// @Description Remove a tag from an user
+// @Param id path string true "User id"
+// @Param tag path string true "Tag"
+// @Produce json
+// @Success 200 {object} handler.UserResponse
+// @Failure 400	{object} appErrors.APIError
+// @Failure 401	{object} appErrors.APIError
+// @Failure 403	{object} appErrors.APIError
+// @Failure 404	{object} appErrors.APIError
+// @Failure 500	{object} appErrors.APIError
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /users/{id}/tags/{tag} [delete]
+func (h defaultUserTags) RemoveTag(c *gin.Context) {
+	appGin.ErrorWrapper(h.executeRemoveTag, c)
+}
+
+func (h defaultUserTags) executeRemoveTag(c *gin.Context) *appErrors.APIError {
+	input, apiErr := h.tagInput(c)
+	if apiErr != nil {
+		return apiErr
+	}
+
+	updated, err := h.removeTag.Execute(input)
+	if err != nil {
+		return appErrors.HandleBusinessError(err)
+	}
+
+	c.JSON(http.StatusOK, h.mapper.MapDomainToResponse(updated))
+	return nil
+}
+
+// FindTags find the user tags
+// @Tags user
+// @Summary Find the user tags
+// @Description Find the tags of the active users with their number of users, most used first
+// @Produce json
+// @Success 200 {object} []handler.TagCountResponse
+// @Failure 401	{object} appErrors.APIError
+// @Failure 403	{object} appErrors.APIError
+// @Failure 500	{object} appErrors.APIError
+// @Security BearerAuth
+// @Security ApiKeyAuth
+// @Router /tags [get]
+func (h defaultUserTags) FindTags(c *gin.Context) {
+	appGin.ErrorWrapper(h.executeFindTags, c)
+}
+
+func (h defaultUserTags) executeFindTags(c *gin.Context) *appErrors.APIError {
+	tags, err := h.findTags.Execute()
+	if err != nil {
+		return appErrors.HandleBusinessError(err)
+	}
+
+	c.JSON(http.StatusOK, h.mapper.MapDomainTagCountsToResponse(tags))
+	return nil
+}
+
+func (h defaultUserTags) tagInput(c *gin.Context) (domain.UserTagInput, *appErrors.APIError) {
+	input := domain.UserTagInput{
+		Reference: c.Param("id"),
+		Tag:       c.Param("tag"),
+	}
+	if len(input.Reference) == 0 {
+		return domain.UserTagInput{}, appErrors.NewBadRequest("user id is required").WithKey("user_id_required")
+	}
+	if len(input.Tag) == 0 {
+		return domain.UserTagInput{}, appErrors.NewBadRequest("tag is required").WithKey("tag_required")
+	}
+
+	return input, nil
+}
@@ -0,0 +1,103 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/desarrollogj/golang-api-example/domain"
+	libErrors "github.com/desarrollogj/golang-api-example/libs/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUserTags_GivenATag_WhenAddTag_ThenReturnUpdatedUserResponse(t *testing.T) {
+	t.Log("Successfully add an user tag")
+
+	domainUser := domain.User{
+		GenericEntity: domain.GenericEntity{Reference: "USER1", IsActive: true},
+		Tags:          []string{"vip"},
+	}
+	responseUser := UserResponse{Id: "USER1", IsActive: true, Tags: []string{"vip"}}
+
+	mapperMock := new(userMapperMock)
+	mapperMock.On("MapDomainToResponse", domainUser).Return(responseUser)
+	addTagMock := new(userAddTagServiceMock)
+	addTagMock.On("Execute", domain.UserTagInput{Reference: "USER1", Tag: "VIP"}).Return(domainUser, nil)
+
+	handler := NewDefaultUserTags(mapperMock, addTagMock, new(userRemoveTagServiceMock), new(userFindTagsServiceMock))
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPut, "/api/v1/users/USER1/tags/VIP", nil)
+
+	r := testRouter()
+	r.PUT("/api/v1/users/:id/tags/:tag", handler.AddTag)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var result UserResponse
+	json.NewDecoder(w.Body).Decode(&result)
+
+	assert.Equal(t, responseUser, result)
+
+	mapperMock.AssertExpectations(t)
+	addTagMock.AssertExpectations(t)
+}
+
+func TestUserTags_GivenATagTheUserDoesNotHave_WhenRemoveTag_ThenReturnNotFoundResponse(t *testing.T) {
+	t.Log("Failure to remove an user tag because the user does not have it")
+
+	removeTagMock := new(userRemoveTagServiceMock)
+	removeTagMock.On("Execute", domain.UserTagInput{Reference: "USER1", Tag: "vip"}).
+		Return(domain.User{}, libErrors.NewNotFoundError("user tag not found"))
+
+	handler := NewDefaultUserTags(new(userMapperMock), new(userAddTagServiceMock), removeTagMock, new(userFindTagsServiceMock))
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodDelete, "/api/v1/users/USER1/tags/vip", nil)
+
+	r := testRouter()
+	r.DELETE("/api/v1/users/:id/tags/:tag", handler.RemoveTag)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	var err libErrors.APIError
+	json.NewDecoder(w.Body).Decode(&err)
+
+	assert.Equal(t, "user tag not found", err.Message)
+
+	removeTagMock.AssertExpectations(t)
+}
+
+func TestUserTags_WhenFindTags_ThenReturnTagCountResponseList(t *testing.T) {
+	t.Log("Successfully find the user tags")
+
+	tags := []domain.TagCount{{Tag: "beta", Count: 2}}
+	response := []TagCountResponse{{Tag: "beta", Count: 2}}
+
+	mapperMock := new(userMapperMock)
+	mapperMock.On("MapDomainTagCountsToResponse", tags).Return(response)
+	findTagsMock := new(userFindTagsServiceMock)
+	findTagsMock.On("Execute").Return(tags, nil)
+
+	handler := NewDefaultUserTags(mapperMock, new(userAddTagServiceMock), new(userRemoveTagServiceMock), findTagsMock)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/tags", nil)
+
+	r := testRouter()
+	r.GET("/api/v1/tags", handler.FindTags)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var result []TagCountResponse
+	json.NewDecoder(w.Body).Decode(&result)
+
+	assert.Equal(t, response, result)
+
+	mapperMock.AssertExpectations(t)
+	findTagsMock.AssertExpectations(t)
+}
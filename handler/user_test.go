@@ -11,7 +11,11 @@ import (
 	"time"
 
 	"github.com/desarrollogj/golang-api-example/domain"
+	"github.com/desarrollogj/golang-api-example/infrastructure/usertest"
 	libErrors "github.com/desarrollogj/golang-api-example/libs/errors"
+	appGin "github.com/desarrollogj/golang-api-example/libs/gin"
+	"github.com/desarrollogj/golang-api-example/libs/i18n"
+	"github.com/desarrollogj/golang-api-example/user"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -54,6 +58,7 @@ func TestUser_WhenFindAll_ThenReturnUserListResponse(t *testing.T) {
 	findByReferenceMock := new(userFindByReferenceServiceMock)
 	createMock := new(userCreateServiceMock)
 	updateMock := new(userUpdateServiceMock)
+	patchMock := new(userPatchServiceMock)
 	deleteMock := new(userDeleteServiceMock)
 	searchMock := new(userSearchServiceMock)
 
@@ -63,6 +68,7 @@ func TestUser_WhenFindAll_ThenReturnUserListResponse(t *testing.T) {
 		findByReferenceMock,
 		createMock,
 		updateMock,
+		patchMock,
 		deleteMock,
 		searchMock)
 
@@ -95,6 +101,7 @@ func TestUser_WhenFindAll_AndServiceReturnedAnError_ThenReturnInternalServerErro
 	findByReferenceMock := new(userFindByReferenceServiceMock)
 	createMock := new(userCreateServiceMock)
 	updateMock := new(userUpdateServiceMock)
+	patchMock := new(userPatchServiceMock)
 	deleteMock := new(userDeleteServiceMock)
 	searchMock := new(userSearchServiceMock)
 
@@ -104,6 +111,7 @@ func TestUser_WhenFindAll_AndServiceReturnedAnError_ThenReturnInternalServerErro
 		findByReferenceMock,
 		createMock,
 		updateMock,
+		patchMock,
 		deleteMock,
 		searchMock)
 
@@ -158,9 +166,10 @@ func TestUser_GivenAnId_WhenFindById_ThenReturnUserResponse(t *testing.T) {
 	mapperMock.On("MapDomainToResponse", domainUser).Return(responseUser)
 	findAllMock := new(userFindAllServiceMock)
 	findByReferenceMock := new(userFindByReferenceServiceMock)
-	findByReferenceMock.On("Execute", reference).Return(domainUser, nil)
+	findByReferenceMock.On("Execute", reference, false).Return(domainUser, nil)
 	createMock := new(userCreateServiceMock)
 	updateMock := new(userUpdateServiceMock)
+	patchMock := new(userPatchServiceMock)
 	deleteMock := new(userDeleteServiceMock)
 	searchMock := new(userSearchServiceMock)
 
@@ -170,6 +179,7 @@ func TestUser_GivenAnId_WhenFindById_ThenReturnUserResponse(t *testing.T) {
 		findByReferenceMock,
 		createMock,
 		updateMock,
+		patchMock,
 		deleteMock,
 		searchMock)
 
@@ -201,9 +211,10 @@ func TestUser_GivenAnId_WhenFindById_AndServiceReturnedAnError_ThenReturnInterna
 	mapperMock := new(userMapperMock)
 	findAllMock := new(userFindAllServiceMock)
 	findByReferenceMock := new(userFindByReferenceServiceMock)
-	findByReferenceMock.On("Execute", reference).Return(domain.User{}, errors.New("service error"))
+	findByReferenceMock.On("Execute", reference, false).Return(domain.User{}, errors.New("service error"))
 	createMock := new(userCreateServiceMock)
 	updateMock := new(userUpdateServiceMock)
+	patchMock := new(userPatchServiceMock)
 	deleteMock := new(userDeleteServiceMock)
 	searchMock := new(userSearchServiceMock)
 
@@ -213,6 +224,7 @@ func TestUser_GivenAnId_WhenFindById_AndServiceReturnedAnError_ThenReturnInterna
 		findByReferenceMock,
 		createMock,
 		updateMock,
+		patchMock,
 		deleteMock,
 		searchMock)
 
@@ -235,6 +247,137 @@ func TestUser_GivenAnId_WhenFindById_AndServiceReturnedAnError_ThenReturnInterna
 	findByReferenceMock.AssertExpectations(t)
 }
 
+func TestUser_GivenAnIdAndAnAdministrator_WhenFindByIdIncludingInactive_ThenReturnUserResponse(t *testing.T) {
+	t.Log("Successfully find an inactive user by its id as an administrator")
+
+	reference := "USER1"
+	domainUser := domain.User{GenericEntity: domain.GenericEntity{Reference: reference}}
+	responseUser := UserResponse{Id: reference}
+
+	config := newApplicationConfigurationMock()
+	mapperMock := new(userMapperMock)
+	mapperMock.On("MapDomainToResponse", domainUser).Return(responseUser)
+	findByReferenceMock := new(userFindByReferenceServiceMock)
+	findByReferenceMock.On("Execute", reference, true).Return(domainUser, nil)
+
+	handler := NewDefaultUser(config,
+		mapperMock,
+		new(userFindAllServiceMock),
+		findByReferenceMock,
+		new(userCreateServiceMock),
+		new(userUpdateServiceMock),
+		new(userPatchServiceMock),
+		new(userDeleteServiceMock),
+		new(userSearchServiceMock))
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/users/USER1?includeInactive=true", nil)
+
+	r := testRouter()
+	r.GET("/api/v1/users/:id", withIdentity(domain.PermissionUsersRead, domain.PermissionUsersAdmin), handler.FindByReference)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	mapperMock.AssertExpectations(t)
+	findByReferenceMock.AssertExpectations(t)
+}
+
+func TestUser_GivenAMergedUserId_WhenFindById_ThenRedirectToTheUserItWasMergedInto(t *testing.T) {
+	t.Log("Successfully redirect the id of a merged user")
+
+	config := newApplicationConfigurationMock()
+	mapperMock := new(userMapperMock)
+	findByReferenceMock := new(userFindByReferenceServiceMock)
+	findByReferenceMock.On("Execute", "USER1", false).Return(domain.User{GenericEntity: domain.GenericEntity{Reference: "USER2"}}, nil)
+
+	handler := NewDefaultUser(config,
+		mapperMock,
+		new(userFindAllServiceMock),
+		findByReferenceMock,
+		new(userCreateServiceMock),
+		new(userUpdateServiceMock),
+		new(userPatchServiceMock),
+		new(userDeleteServiceMock),
+		new(userSearchServiceMock))
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/users/USER1", nil)
+
+	r := testRouter()
+	r.GET("/api/v1/users/:id", handler.FindByReference)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusMovedPermanently, w.Code)
+	assert.Equal(t, "/api/v1/users/USER2", w.Header().Get("Location"))
+
+	mapperMock.AssertNotCalled(t, "MapDomainToResponse", mock.Anything)
+}
+
+func TestUser_GivenAMergedUserIdWithAQuery_WhenFindById_ThenRedirectKeepingTheQuery(t *testing.T) {
+	t.Log("Successfully redirect the id of a merged user with the request query")
+
+	config := newApplicationConfigurationMock()
+	mapperMock := new(userMapperMock)
+	findByReferenceMock := new(userFindByReferenceServiceMock)
+	findByReferenceMock.On("Execute", "USER1", false).Return(domain.User{GenericEntity: domain.GenericEntity{Reference: "USER2"}}, nil)
+
+	handler := NewDefaultUser(config,
+		mapperMock,
+		new(userFindAllServiceMock),
+		findByReferenceMock,
+		new(userCreateServiceMock),
+		new(userUpdateServiceMock),
+		new(userPatchServiceMock),
+		new(userDeleteServiceMock),
+		new(userSearchServiceMock))
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/users/USER1?lang=es", nil)
+
+	r := testRouter()
+	r.GET("/api/v1/users/:id", handler.FindByReference)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusMovedPermanently, w.Code)
+	assert.Equal(t, "/api/v1/users/USER2?lang=es", w.Header().Get("Location"))
+
+	mapperMock.AssertNotCalled(t, "MapDomainToResponse", mock.Anything)
+}
+
+func TestUser_GivenAnIdAndNotAnAdministrator_WhenFindByIdIncludingInactive_ThenReturnForbiddenResponse(t *testing.T) {
+	t.Log("Failure to find an inactive user by its id because the caller is not an administrator")
+
+	config := newApplicationConfigurationMock()
+	findByReferenceMock := new(userFindByReferenceServiceMock)
+
+	handler := NewDefaultUser(config,
+		new(userMapperMock),
+		new(userFindAllServiceMock),
+		findByReferenceMock,
+		new(userCreateServiceMock),
+		new(userUpdateServiceMock),
+		new(userPatchServiceMock),
+		new(userDeleteServiceMock),
+		new(userSearchServiceMock))
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/users/USER1?includeInactive=true", nil)
+
+	r := testRouter()
+	r.GET("/api/v1/users/:id", withIdentity(domain.PermissionUsersRead), handler.FindByReference)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	var err libErrors.APIError
+	json.NewDecoder(w.Body).Decode(&err)
+
+	assert.Equal(t, "only administrators can include inactive users", err.Message)
+
+	findByReferenceMock.AssertNotCalled(t, "Execute", mock.Anything, mock.Anything)
+}
+
 func TestUser_GivenACreateRequest_WhenCreate_ThenReturnCreatedUserResponse(t *testing.T) {
 	t.Log("Successfully create an user")
 
@@ -280,6 +423,7 @@ func TestUser_GivenACreateRequest_WhenCreate_ThenReturnCreatedUserResponse(t *te
 	createMock := new(userCreateServiceMock)
 	createMock.On("Execute", domainInput).Return(domainUser, nil)
 	updateMock := new(userUpdateServiceMock)
+	patchMock := new(userPatchServiceMock)
 	deleteMock := new(userDeleteServiceMock)
 	searchMock := new(userSearchServiceMock)
 
@@ -289,6 +433,7 @@ func TestUser_GivenACreateRequest_WhenCreate_ThenReturnCreatedUserResponse(t *te
 		findByReferenceMock,
 		createMock,
 		updateMock,
+		patchMock,
 		deleteMock,
 		searchMock)
 
@@ -323,6 +468,7 @@ func TestUser_GivenANotValidCreateRequest_WhenCreate_ThenReturnBadRequestRespons
 	findByReferenceMock := new(userFindByReferenceServiceMock)
 	createMock := new(userCreateServiceMock)
 	updateMock := new(userUpdateServiceMock)
+	patchMock := new(userPatchServiceMock)
 	deleteMock := new(userDeleteServiceMock)
 	searchMock := new(userSearchServiceMock)
 
@@ -332,6 +478,7 @@ func TestUser_GivenANotValidCreateRequest_WhenCreate_ThenReturnBadRequestRespons
 		findByReferenceMock,
 		createMock,
 		updateMock,
+		patchMock,
 		deleteMock,
 		searchMock)
 
@@ -353,6 +500,7 @@ func TestUser_GivenANotValidCreateRequest_WhenCreate_ThenReturnBadRequestRespons
 	assert.NotNil(t, err)
 	assert.Equal(t, http.StatusBadRequest, err.Status)
 	assert.Equal(t, "request body is not valid", err.Message)
+	assert.Equal(t, []libErrors.ErrorCause{{Rule: "type", Param: "object", Message: "request body must be of type object"}}, err.Causes)
 
 	mapperMock.AssertExpectations(t)
 	createMock.AssertExpectations(t)
@@ -371,6 +519,233 @@ func TestUser_GivenACreateRequestWithNotValidData_WhenCreate_ThenReturnBadReques
 	findByReferenceMock := new(userFindByReferenceServiceMock)
 	createMock := new(userCreateServiceMock)
 	updateMock := new(userUpdateServiceMock)
+	patchMock := new(userPatchServiceMock)
+	deleteMock := new(userDeleteServiceMock)
+	searchMock := new(userSearchServiceMock)
+
+	handler := NewDefaultUser(config,
+		mapperMock,
+		findAllMock,
+		findByReferenceMock,
+		createMock,
+		updateMock,
+		patchMock,
+		deleteMock,
+		searchMock)
+
+	buffer := new(bytes.Buffer)
+	json.NewEncoder(buffer).Encode(request)
+	requestData := buffer.Bytes()
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/users", bytes.NewBuffer(requestData))
+
+	r := testRouter()
+	r.POST("/api/v1/users", handler.Create)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	var err libErrors.APIError
+	json.NewDecoder(w.Body).Decode(&err)
+
+	assert.NotNil(t, err)
+	assert.Equal(t, http.StatusBadRequest, err.Status)
+	assert.Equal(t, "request body is not valid", err.Message)
+	assert.Equal(t, []libErrors.ErrorCause{
+		{Field: "lastName", Rule: "required", Message: "lastName is a required field"},
+		{Field: "email", Rule: "required", Message: "email is a required field"},
+	}, err.Causes)
+
+	mapperMock.AssertExpectations(t)
+	createMock.AssertExpectations(t)
+}
+
+func TestUser_GivenACreateRequestWithNotValidDataAcceptingProblemDetails_WhenCreate_ThenReturnBadRequestProblemDetails(t *testing.T) {
+	t.Log("Failure create an user because request has not a valid data, with the error in the Problem Details format")
+
+	request := UserCreateRequest{
+		FirstName: "Foo",
+		LastName:  "Bar",
+		Email:     "not an email",
+	}
+
+	config := newApplicationConfigurationMock()
+	mapperMock := new(userMapperMock)
+	createMock := new(userCreateServiceMock)
+
+	handler := NewDefaultUser(config,
+		mapperMock,
+		new(userFindAllServiceMock),
+		new(userFindByReferenceServiceMock),
+		createMock,
+		new(userUpdateServiceMock),
+		new(userPatchServiceMock),
+		new(userDeleteServiceMock),
+		new(userSearchServiceMock))
+
+	buffer := new(bytes.Buffer)
+	json.NewEncoder(buffer).Encode(request)
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/users?dryRun=false", buffer)
+	req.Header.Set("Accept", "application/problem+json")
+
+	r := testRouter()
+	r.Use(appGin.ErrorFormatMiddleware(appGin.ErrorFormatJSON, "https://api.example.com/problems/"))
+	r.POST("/api/v1/users", handler.Create)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, "application/problem+json", w.Header().Get("Content-Type"))
+
+	var problem libErrors.ProblemDetails
+	json.NewDecoder(w.Body).Decode(&problem)
+
+	assert.Equal(t, libErrors.ProblemDetails{
+		Type:     "https://api.example.com/problems/bad_request",
+		Title:    "invalid request parameters",
+		Status:   http.StatusBadRequest,
+		Detail:   "request body is not valid",
+		Instance: "/api/v1/users?dryRun=false",
+		Code:     "bad_request",
+		Causes: []libErrors.ErrorCause{
+			{Field: "email", Rule: "email", Message: "email must be a valid email address"},
+		},
+	}, problem)
+	createMock.AssertExpectations(t)
+}
+
+func TestUser_GivenACreateRequestWithNotValidDataInSpanish_WhenCreate_ThenReturnBadRequestResponseInSpanish(t *testing.T) {
+	t.Log("Failure create an user because request has not a valid data, with the messages in the accepted language")
+
+	request := UserCreateRequest{
+		FirstName: "Foo",
+		LastName:  "Bar",
+		Email:     "not an email",
+	}
+
+	config := newApplicationConfigurationMock()
+	mapperMock := new(userMapperMock)
+	createMock := new(userCreateServiceMock)
+
+	handler := NewDefaultUser(config,
+		mapperMock,
+		new(userFindAllServiceMock),
+		new(userFindByReferenceServiceMock),
+		createMock,
+		new(userUpdateServiceMock),
+		new(userPatchServiceMock),
+		new(userDeleteServiceMock),
+		new(userSearchServiceMock))
+
+	buffer := new(bytes.Buffer)
+	json.NewEncoder(buffer).Encode(request)
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/users", buffer)
+	req.Header.Set("Accept-Language", "es-AR,es;q=0.9,en;q=0.8")
+
+	r := testRouter()
+	r.Use(appGin.LanguageMiddleware(i18n.English))
+	r.POST("/api/v1/users", handler.Create)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, "es", w.Header().Get("Content-Language"))
+
+	var err libErrors.APIError
+	json.NewDecoder(w.Body).Decode(&err)
+
+	assert.Equal(t, "el cuerpo de la solicitud no es válido", err.Message)
+	assert.Equal(t, []libErrors.ErrorCause{
+		{Field: "email", Rule: "email", Message: "email debe ser una dirección de correo electrónico válida"},
+	}, err.Causes)
+	createMock.AssertExpectations(t)
+}
+
+func TestUser_GivenACreateRequestInSpanish_WhenCreate_AndServiceReturnedAnError_ThenReturnItsMessageLanguage(t *testing.T) {
+	t.Log("Failure to create an user in Spanish, with the use case errors translated and the unexpected errors in English")
+
+	request := UserCreateRequest{
+		FirstName: "Foo",
+		LastName:  "Bar",
+		Email:     "foobar@email.com",
+	}
+	domainInput := domain.UserCreateInput{
+		FirstName: "Foo",
+		LastName:  "Bar",
+		Email:     "foobar@email.com",
+	}
+
+	cases := []struct {
+		err      error
+		language string
+		message  string
+	}{
+		{
+			err:      libErrors.NewConflictError("external id crm/1 belongs to another user").WithKey("external_id_taken", "crm", "1"),
+			language: "es",
+			message:  "el id externo crm/1 pertenece a otro usuario",
+		},
+		{
+			err:      libErrors.NewFatalError("unexpected error when create the user"),
+			language: "en",
+			message:  "unexpected error when create the user",
+		},
+	}
+	for _, c := range cases {
+		mapperMock := new(userMapperMock)
+		mapperMock.On("MapCreateRequestToInput", request).Return(domainInput)
+		createMock := new(userCreateServiceMock)
+		createMock.On("Execute", domainInput).Return(domain.User{}, c.err)
+
+		handler := NewDefaultUser(newApplicationConfigurationMock(),
+			mapperMock,
+			new(userFindAllServiceMock),
+			new(userFindByReferenceServiceMock),
+			createMock,
+			new(userUpdateServiceMock),
+			new(userPatchServiceMock),
+			new(userDeleteServiceMock),
+			new(userSearchServiceMock))
+
+		buffer := new(bytes.Buffer)
+		json.NewEncoder(buffer).Encode(request)
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/users", buffer)
+		req.Header.Set("Accept-Language", "es")
+
+		r := testRouter()
+		r.Use(appGin.LanguageMiddleware(i18n.English))
+		r.POST("/api/v1/users", handler.Create)
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, c.language, w.Header().Get("Content-Language"))
+
+		var err libErrors.APIError
+		json.NewDecoder(w.Body).Decode(&err)
+
+		assert.Equal(t, c.message, err.Message)
+	}
+}
+
+func TestUser_GivenACreateRequestWithNotValidProfile_WhenCreate_ThenReturnBadRequestResponse(t *testing.T) {
+	t.Log("Failure create an user because request has not a valid profile")
+
+	request := UserCreateRequest{
+		FirstName: "Foo",
+		LastName:  "Bar",
+		Email:     "foobar@email.com",
+		BirthDate: "17/05/1990",
+		Timezone:  "America/Nowhere",
+		Address:   &AddressRequest{Line1: "Street 123", City: "Buenos Aires", Country: "ARG"},
+	}
+
+	config := newApplicationConfigurationMock()
+	mapperMock := new(userMapperMock)
+	findAllMock := new(userFindAllServiceMock)
+	findByReferenceMock := new(userFindByReferenceServiceMock)
+	createMock := new(userCreateServiceMock)
+	updateMock := new(userUpdateServiceMock)
+	patchMock := new(userPatchServiceMock)
 	deleteMock := new(userDeleteServiceMock)
 	searchMock := new(userSearchServiceMock)
 
@@ -380,6 +755,7 @@ func TestUser_GivenACreateRequestWithNotValidData_WhenCreate_ThenReturnBadReques
 		findByReferenceMock,
 		createMock,
 		updateMock,
+		patchMock,
 		deleteMock,
 		searchMock)
 
@@ -401,6 +777,11 @@ func TestUser_GivenACreateRequestWithNotValidData_WhenCreate_ThenReturnBadReques
 	assert.NotNil(t, err)
 	assert.Equal(t, http.StatusBadRequest, err.Status)
 	assert.Equal(t, "request body is not valid", err.Message)
+	assert.Equal(t, []libErrors.ErrorCause{
+		{Field: "birthDate", Rule: "datetime", Param: "2006-01-02", Message: "birthDate does not match the 2006-01-02 format"},
+		{Field: "timezone", Rule: "timezone", Message: "timezone must be a valid IANA time zone name"},
+		{Field: "address.country", Rule: "iso3166_1_alpha2", Message: "country must be an ISO 3166-1 alpha-2 code"},
+	}, err.Causes)
 
 	mapperMock.AssertExpectations(t)
 	createMock.AssertExpectations(t)
@@ -428,6 +809,7 @@ func TestUser_GivenACreateRequest_WhenCreate_AndServiceReturnedAnError_ThenRetur
 	createMock := new(userCreateServiceMock)
 	createMock.On("Execute", domainInput).Return(domain.User{}, errors.New("service error"))
 	updateMock := new(userUpdateServiceMock)
+	patchMock := new(userPatchServiceMock)
 	deleteMock := new(userDeleteServiceMock)
 	searchMock := new(userSearchServiceMock)
 
@@ -437,6 +819,7 @@ func TestUser_GivenACreateRequest_WhenCreate_AndServiceReturnedAnError_ThenRetur
 		findByReferenceMock,
 		createMock,
 		updateMock,
+		patchMock,
 		deleteMock,
 		searchMock)
 
@@ -513,6 +896,7 @@ func TestUser_GivenAnUpdateRequest_WhenUpdate_ThenReturnUpdatedUserResponse(t *t
 	findByReferenceMock := new(userFindByReferenceServiceMock)
 	createMock := new(userCreateServiceMock)
 	updateMock := new(userUpdateServiceMock)
+	patchMock := new(userPatchServiceMock)
 	updateMock.On("Execute", domainInput).Return(domainUser, nil)
 	deleteMock := new(userDeleteServiceMock)
 	searchMock := new(userSearchServiceMock)
@@ -523,6 +907,7 @@ func TestUser_GivenAnUpdateRequest_WhenUpdate_ThenReturnUpdatedUserResponse(t *t
 		findByReferenceMock,
 		createMock,
 		updateMock,
+		patchMock,
 		deleteMock,
 		searchMock)
 
@@ -557,6 +942,7 @@ func TestUser_GivenANotValidUpdateRequest_WhenUpdate_ThenReturnBadRequestRespons
 	findByReferenceMock := new(userFindByReferenceServiceMock)
 	createMock := new(userCreateServiceMock)
 	updateMock := new(userUpdateServiceMock)
+	patchMock := new(userPatchServiceMock)
 	deleteMock := new(userDeleteServiceMock)
 	searchMock := new(userSearchServiceMock)
 
@@ -566,6 +952,7 @@ func TestUser_GivenANotValidUpdateRequest_WhenUpdate_ThenReturnBadRequestRespons
 		findByReferenceMock,
 		createMock,
 		updateMock,
+		patchMock,
 		deleteMock,
 		searchMock)
 
@@ -607,6 +994,7 @@ func TestUser_GivenAnUpdateRequestWithNotValidData_WhenUpdate_ThenReturnBadReque
 	findByReferenceMock := new(userFindByReferenceServiceMock)
 	createMock := new(userCreateServiceMock)
 	updateMock := new(userUpdateServiceMock)
+	patchMock := new(userPatchServiceMock)
 	deleteMock := new(userDeleteServiceMock)
 	searchMock := new(userSearchServiceMock)
 
@@ -616,6 +1004,7 @@ func TestUser_GivenAnUpdateRequestWithNotValidData_WhenUpdate_ThenReturnBadReque
 		findByReferenceMock,
 		createMock,
 		updateMock,
+		patchMock,
 		deleteMock,
 		searchMock)
 
@@ -669,6 +1058,7 @@ func TestUser_GivenAnUpdateRequest_WhenUpdate_AndServiceReturnedAnError_ThenRetu
 	findByReferenceMock := new(userFindByReferenceServiceMock)
 	createMock := new(userCreateServiceMock)
 	updateMock := new(userUpdateServiceMock)
+	patchMock := new(userPatchServiceMock)
 	updateMock.On("Execute", domainInput).Return(domain.User{}, errors.New("service error"))
 	deleteMock := new(userDeleteServiceMock)
 	searchMock := new(userSearchServiceMock)
@@ -679,6 +1069,7 @@ func TestUser_GivenAnUpdateRequest_WhenUpdate_AndServiceReturnedAnError_ThenRetu
 		findByReferenceMock,
 		createMock,
 		updateMock,
+		patchMock,
 		deleteMock,
 		searchMock)
 
@@ -739,6 +1130,7 @@ func TestUser_GivenADeleteRequest_WhenDelete_ThenReturnDeletedUserResponse(t *te
 	findByReferenceMock := new(userFindByReferenceServiceMock)
 	createMock := new(userCreateServiceMock)
 	updateMock := new(userUpdateServiceMock)
+	patchMock := new(userPatchServiceMock)
 	deleteMock := new(userDeleteServiceMock)
 	deleteMock.On("Execute", reference).Return(domainUser, nil)
 	searchMock := new(userSearchServiceMock)
@@ -749,6 +1141,7 @@ func TestUser_GivenADeleteRequest_WhenDelete_ThenReturnDeletedUserResponse(t *te
 		findByReferenceMock,
 		createMock,
 		updateMock,
+		patchMock,
 		deleteMock,
 		searchMock)
 
@@ -782,6 +1175,7 @@ func TestUser_GivenADeleteRequest_WhenDelete_AndServiceReturnedAnError_ThenRetur
 	findByReferenceMock := new(userFindByReferenceServiceMock)
 	createMock := new(userCreateServiceMock)
 	updateMock := new(userUpdateServiceMock)
+	patchMock := new(userPatchServiceMock)
 	deleteMock := new(userDeleteServiceMock)
 	deleteMock.On("Execute", reference).Return(domain.User{}, errors.New("service error"))
 	searchMock := new(userSearchServiceMock)
@@ -792,6 +1186,7 @@ func TestUser_GivenADeleteRequest_WhenDelete_AndServiceReturnedAnError_ThenRetur
 		findByReferenceMock,
 		createMock,
 		updateMock,
+		patchMock,
 		deleteMock,
 		searchMock)
 
@@ -867,6 +1262,7 @@ func TestUser_WhenSearch_ThenReturnSearchUserResponse(t *testing.T) {
 	findByReferenceMock := new(userFindByReferenceServiceMock)
 	createMock := new(userCreateServiceMock)
 	updateMock := new(userUpdateServiceMock)
+	patchMock := new(userPatchServiceMock)
 	deleteMock := new(userDeleteServiceMock)
 	searchMock := new(userSearchServiceMock)
 	searchMock.On("Execute", mock.AnythingOfType("UserSearchInput")).Return(domainSearchOutput, nil)
@@ -877,6 +1273,7 @@ func TestUser_WhenSearch_ThenReturnSearchUserResponse(t *testing.T) {
 		findByReferenceMock,
 		createMock,
 		updateMock,
+		patchMock,
 		deleteMock,
 		searchMock)
 
@@ -953,6 +1350,7 @@ func TestUser_WhenSearch_AndPageAndSizeAreNotSet_ThenReturnSearchUserResponseUsi
 	findByReferenceMock := new(userFindByReferenceServiceMock)
 	createMock := new(userCreateServiceMock)
 	updateMock := new(userUpdateServiceMock)
+	patchMock := new(userPatchServiceMock)
 	deleteMock := new(userDeleteServiceMock)
 	searchMock := new(userSearchServiceMock)
 	searchMock.On("Execute", mock.AnythingOfType("UserSearchInput")).Return(domainSearchOutput, nil)
@@ -963,6 +1361,7 @@ func TestUser_WhenSearch_AndPageAndSizeAreNotSet_ThenReturnSearchUserResponseUsi
 		findByReferenceMock,
 		createMock,
 		updateMock,
+		patchMock,
 		deleteMock,
 		searchMock)
 
@@ -997,6 +1396,7 @@ func TestUser_WhenSearch_AndServiceFails_ThenReturnInternalServiceError(t *testi
 	findByReferenceMock := new(userFindByReferenceServiceMock)
 	createMock := new(userCreateServiceMock)
 	updateMock := new(userUpdateServiceMock)
+	patchMock := new(userPatchServiceMock)
 	deleteMock := new(userDeleteServiceMock)
 	searchMock := new(userSearchServiceMock)
 	searchMock.On("Execute", mock.AnythingOfType("UserSearchInput")).Return(domain.UserSearchOutput{}, errors.New("service error"))
@@ -1007,6 +1407,7 @@ func TestUser_WhenSearch_AndServiceFails_ThenReturnInternalServiceError(t *testi
 		findByReferenceMock,
 		createMock,
 		updateMock,
+		patchMock,
 		deleteMock,
 		searchMock)
 
@@ -1033,3 +1434,240 @@ func TestUser_WhenSearch_AndServiceFails_ThenReturnInternalServiceError(t *testi
 	mapperMock.AssertExpectations(t)
 	searchMock.AssertExpectations(t)
 }
+
+func TestUser_WhenSearch_AndAttributeFiltersAreSet_ThenSearchByAttributes(t *testing.T) {
+	t.Log("Successfully search users filtering by custom attributes")
+
+	output := domain.UserSearchOutput{SearchOutput: domain.SearchOutput{Total: 0, Page: 1, PageSize: 10}}
+	searchMock := new(userSearchServiceMock)
+	searchMock.On("Execute", mock.MatchedBy(func(input domain.UserSearchInput) bool {
+		return assert.ObjectsAreEqual(map[string]interface{}{"plan": "pro", "seats": "10"}, input.Attributes)
+	})).Return(output, nil)
+
+	handler := NewDefaultUser(newApplicationConfigurationMock(),
+		NewDefaultUserMapper(),
+		new(userFindAllServiceMock),
+		new(userFindByReferenceServiceMock),
+		new(userCreateServiceMock),
+		new(userUpdateServiceMock),
+		new(userPatchServiceMock),
+		new(userDeleteServiceMock),
+		searchMock)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/users/search", nil)
+	q := req.URL.Query()
+	q.Add("filter", "attr.plan==pro")
+	q.Add("filter", "attr.seats==10")
+	req.URL.RawQuery = q.Encode()
+
+	r := testRouter()
+	r.GET("/api/v1/users/search", handler.Search)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	searchMock.AssertExpectations(t)
+}
+
+func TestUser_WhenSearch_AndTagFiltersAreSet_ThenSearchByTags(t *testing.T) {
+	t.Log("Successfully search users filtering by all tags and any tag")
+
+	output := domain.UserSearchOutput{SearchOutput: domain.SearchOutput{Total: 0, Page: 1, PageSize: 10}}
+	searchMock := new(userSearchServiceMock)
+	searchMock.On("Execute", mock.MatchedBy(func(input domain.UserSearchInput) bool {
+		return assert.ObjectsAreEqual([]string{"beta", "vip"}, input.Tags) &&
+			assert.ObjectsAreEqual([]string{"partner"}, input.AnyTags)
+	})).Return(output, nil)
+
+	handler := NewDefaultUser(newApplicationConfigurationMock(),
+		NewDefaultUserMapper(),
+		new(userFindAllServiceMock),
+		new(userFindByReferenceServiceMock),
+		new(userCreateServiceMock),
+		new(userUpdateServiceMock),
+		new(userPatchServiceMock),
+		new(userDeleteServiceMock),
+		searchMock)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/users/search?tags=beta,%20vip,&anyTag=partner", nil)
+
+	r := testRouter()
+	r.GET("/api/v1/users/search", handler.Search)
+	r.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	searchMock.AssertExpectations(t)
+}
+
+func TestUser_WhenSearch_AndAttributeFilterIsNotValid_ThenReturnBadRequest(t *testing.T) {
+	t.Log("Failure when search users because an attribute filter has not the expected format")
+
+	handler := NewDefaultUser(newApplicationConfigurationMock(),
+		NewDefaultUserMapper(),
+		new(userFindAllServiceMock),
+		new(userFindByReferenceServiceMock),
+		new(userCreateServiceMock),
+		new(userUpdateServiceMock),
+		new(userPatchServiceMock),
+		new(userDeleteServiceMock),
+		new(userSearchServiceMock))
+
+	for _, filter := range []string{"plan==pro", "attr.plan=pro", "attr.==pro"} {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/users/search", nil)
+		q := req.URL.Query()
+		q.Add("filter", filter)
+		req.URL.RawQuery = q.Encode()
+
+		r := testRouter()
+		r.GET("/api/v1/users/search", handler.Search)
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code, filter)
+	}
+}
+
+func newPatchUserHandlerMock(users ...domain.User) defaultUser {
+	repository := usertest.NewInMemoryUserRepository()
+	for _, user := range users {
+		repository.Create(user)
+	}
+
+	return NewDefaultUser(newApplicationConfigurationMock(),
+		NewDefaultUserMapper(),
+		new(userFindAllServiceMock),
+		new(userFindByReferenceServiceMock),
+		new(userCreateServiceMock),
+		new(userUpdateServiceMock),
+		user.NewDefaultPatch(domain.UserAttributesConfiguration{}, repository),
+		new(userDeleteServiceMock),
+		new(userSearchServiceMock))
+}
+
+func newPatchUserMock() domain.User {
+	current := time.Now().UTC()
+	return domain.User{
+		GenericEntity: domain.GenericEntity{
+			Reference:   "USER1",
+			IsActive:    true,
+			CreatedDate: current,
+			UpdatedDate: current,
+		},
+		UserProfile: domain.UserProfile{
+			Locale: "es-AR",
+		},
+		FirstName: "Foo",
+		LastName:  "Bar",
+		Email:     "foobar@email.com",
+	}
+}
+
+func servePatchRequest(handler defaultUser, contentType string, patch string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPatch, "/api/v1/users/USER1", bytes.NewBufferString(patch))
+	req.Header.Set("Content-Type", contentType)
+
+	r := testRouter()
+	r.PATCH("/api/v1/users/:id", handler.Patch)
+	r.ServeHTTP(w, req)
+
+	return w
+}
+
+func TestUser_GivenAMergePatchRequest_WhenPatch_ThenReturnPatchedUserResponse(t *testing.T) {
+	t.Log("Successfully patch an user with a JSON Merge Patch")
+
+	handler := newPatchUserHandlerMock(newPatchUserMock())
+
+	w := servePatchRequest(handler, MergePatchContentType, `{"firstName":"Another Foo","locale":null,"address":{"line1":"Street 123","city":"Buenos Aires","country":"AR"}}`)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response UserResponse
+	json.NewDecoder(w.Body).Decode(&response)
+
+	assert.Equal(t, "Another Foo", response.FirstName)
+	assert.Equal(t, "Bar", response.LastName)
+	assert.Equal(t, "", response.Locale)
+	assert.Equal(t, &AddressResponse{Line1: "Street 123", City: "Buenos Aires", Country: "AR"}, response.Address)
+}
+
+func TestUser_GivenAJSONPatchRequest_WhenPatch_ThenReturnPatchedUserResponse(t *testing.T) {
+	t.Log("Successfully patch an user with a JSON Patch")
+
+	handler := newPatchUserHandlerMock(newPatchUserMock())
+
+	w := servePatchRequest(handler, JSONPatchContentType, `[{"op":"test","path":"/email","value":"foobar@email.com"},{"op":"replace","path":"/email","value":"anotherfoobar@email.com"}]`)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var response UserResponse
+	json.NewDecoder(w.Body).Decode(&response)
+
+	assert.Equal(t, "Foo", response.FirstName)
+	assert.Equal(t, "anotherfoobar@email.com", response.Email)
+	assert.Equal(t, "es-AR", response.Locale)
+}
+
+func TestUser_GivenAJSONPatchRequestWithAFailedTest_WhenPatch_ThenReturnConflictResponse(t *testing.T) {
+	t.Log("Failure patch an user because a JSON Patch test operation failed")
+
+	handler := newPatchUserHandlerMock(newPatchUserMock())
+
+	w := servePatchRequest(handler, JSONPatchContentType, `[{"op":"test","path":"/email","value":"another@email.com"},{"op":"replace","path":"/email","value":"anotherfoobar@email.com"}]`)
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+
+	var err libErrors.APIError
+	json.NewDecoder(w.Body).Decode(&err)
+
+	assert.Equal(t, "patch test operation failed", err.Message)
+}
+
+func TestUser_GivenAPatchRequestWithNotValidData_WhenPatch_ThenReturnBadRequestResponse(t *testing.T) {
+	t.Log("Failure patch an user because the patched data is not valid")
+
+	handler := newPatchUserHandlerMock(newPatchUserMock())
+	cases := map[string]string{
+		"not valid email": `{"email":"foobar"}`,
+		"required field":  `{"firstName":null}`,
+		"unknown field":   `{"nickname":"foo"}`,
+		"empty body":      ``,
+	}
+
+	for name, patch := range cases {
+		w := servePatchRequest(handler, MergePatchContentType, patch)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code, name)
+
+		var err libErrors.APIError
+		json.NewDecoder(w.Body).Decode(&err)
+
+		assert.Equal(t, "request body is not valid", err.Message, name)
+	}
+}
+
+func TestUser_GivenAPatchRequestWithNotSupportedContentType_WhenPatch_ThenReturnUnsupportedMediaTypeResponse(t *testing.T) {
+	t.Log("Failure patch an user because the content type is not a patch format")
+
+	patchMock := new(userPatchServiceMock)
+	handler := newPatchUserHandlerMock()
+	handler.patch = patchMock
+
+	w := servePatchRequest(handler, "application/json", `{"firstName":"Another Foo"}`)
+
+	assert.Equal(t, http.StatusUnsupportedMediaType, w.Code)
+
+	patchMock.AssertNotCalled(t, "Execute", mock.Anything)
+}
+
+func TestUser_GivenAPatchRequest_WhenPatchAndUserNotFound_ThenReturnNotFoundResponse(t *testing.T) {
+	t.Log("Failure patch an user because it does not exist")
+
+	handler := newPatchUserHandlerMock()
+
+	w := servePatchRequest(handler, MergePatchContentType, `{"firstName":"Another Foo"}`)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
@@ -0,0 +1,203 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+
+	appErrors "github.com/desarrollogj/golang-api-example/libs/errors"
+	appGin "github.com/desarrollogj/golang-api-example/libs/gin"
+	"github.com/desarrollogj/golang-api-example/libs/i18n"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+const (
+	invalidBodyMessage   = "request body is not valid"
+	invalidBodyKey       = "request_body_not_valid"
+	unknownFieldErrorTag = "json: unknown field "
+)
+
+var (
+	validatorOnce     sync.Once
+	requestsValidator *validator.Validate
+)
+
+// newValidator returns the requests validator, with the JSON names of the fields and the translations of its messages.
+// It is created once, because the translations are registered in translators shared by every validator
+func newValidator() *validator.Validate {
+	validatorOnce.Do(func() {
+		requestsValidator = validator.New()
+		requestsValidator.RegisterTagNameFunc(func(field reflect.StructField) string {
+			name := strings.Split(field.Tag.Get("json"), ",")[0]
+			if name == "-" {
+				return ""
+			}
+			return name
+		})
+		// The translations are constant, so they can only fail by a programming error
+		if err := i18n.RegisterValidatorTranslations(requestsValidator); err != nil {
+			panic(err)
+		}
+	})
+	return requestsValidator
+}
+
+// bindJSON binds the JSON request body and validates it. The error has the causes of the invalid fields
+func bindJSON(c *gin.Context, req interface{}) *appErrors.APIError {
+	if err := c.ShouldBindWith(req, binding.JSON); err != nil {
+		return appErrors.NewBadRequest(invalidBodyMessage).WithKey(invalidBodyKey).WithCauses(decodeErrorCauses(err)...)
+	}
+	if err := validate.Struct(req); err != nil {
+		causes := validationErrorCauses(req, err, appGin.GetLanguage(c))
+		return appErrors.NewBadRequest(invalidBodyMessage).WithKey(invalidBodyKey).WithCauses(causes...)
+	}
+
+	return nil
+}
+
+// decodeErrorCauses returns the causes of a JSON decode error
+func decodeErrorCauses(err error) []appErrors.ErrorCause {
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.Is(err, io.EOF):
+		return []appErrors.ErrorCause{{Rule: "required", Message: "request body is required", Key: "request_body_required"}}
+	case errors.Is(err, io.ErrUnexpectedEOF):
+		return []appErrors.ErrorCause{{Rule: "json", Message: "request body is not a complete JSON document", Key: "request_body_incomplete"}}
+	case errors.As(err, &syntaxErr):
+		return []appErrors.ErrorCause{{
+			Rule:    "json",
+			Message: fmt.Sprintf("request body is not a valid JSON document, error at offset %d", syntaxErr.Offset),
+			Key:     "request_body_malformed",
+			Args:    []interface{}{syntaxErr.Offset},
+		}}
+	case errors.As(err, &typeErr):
+		jsonType := jsonTypeName(typeErr.Type)
+		cause := appErrors.ErrorCause{
+			Field:   typeErr.Field,
+			Rule:    "type",
+			Param:   jsonType,
+			Message: fmt.Sprintf("%s must be of type %s", fieldName(typeErr.Field), jsonType),
+			Key:     "field_type",
+			Args:    []interface{}{typeErr.Field, jsonType},
+		}
+		if len(typeErr.Field) == 0 {
+			cause.Key, cause.Args = "request_body_type", []interface{}{jsonType}
+		}
+		return []appErrors.ErrorCause{cause}
+	case strings.HasPrefix(err.Error(), unknownFieldErrorTag):
+		field, unquoteErr := strconv.Unquote(strings.TrimPrefix(err.Error(), unknownFieldErrorTag))
+		if unquoteErr != nil {
+			field = strings.TrimPrefix(err.Error(), unknownFieldErrorTag)
+		}
+		return []appErrors.ErrorCause{{
+			Field:   field,
+			Rule:    "unknown",
+			Message: fmt.Sprintf("%s is not a known field", field),
+			Key:     "field_unknown",
+			Args:    []interface{}{field},
+		}}
+	default:
+		return nil
+	}
+}
+
+// validationErrorCauses returns a cause for each invalid field of a request, with its JSON path and the message in a language
+func validationErrorCauses(req interface{}, err error, lang string) []appErrors.ErrorCause {
+	var validationErrs validator.ValidationErrors
+	if !errors.As(err, &validationErrs) {
+		return nil
+	}
+
+	translator := i18n.ValidatorTranslator(lang)
+	causes := []appErrors.ErrorCause{}
+	for _, fieldErr := range validationErrs {
+		causes = append(causes, appErrors.ErrorCause{
+			Field:   jsonFieldPath(reflect.TypeOf(req), fieldErr.StructNamespace()),
+			Rule:    fieldErr.Tag(),
+			Param:   fieldErr.Param(),
+			Message: fieldErr.Translate(translator),
+		})
+	}
+	return causes
+}
+
+// jsonFieldPath converts the struct namespace of a field, like UserCreateRequest.Address.City, to its JSON path, like address.city.
+// The fields of embedded structs are promoted, as in the JSON document
+func jsonFieldPath(t reflect.Type, namespace string) string {
+	segments := strings.Split(namespace, ".")
+	path := []string{}
+	// The first segment is the request type
+	for _, segment := range segments[1:] {
+		name, index := segment, ""
+		if i := strings.Index(segment, "["); i >= 0 {
+			name, index = segment[:i], segment[i:]
+		}
+
+		for t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		if t.Kind() != reflect.Struct {
+			path = append(path, segment)
+			continue
+		}
+		field, ok := t.FieldByName(name)
+		if !ok {
+			path = append(path, segment)
+			continue
+		}
+		t = field.Type
+		if len(index) > 0 {
+			for t.Kind() == reflect.Ptr {
+				t = t.Elem()
+			}
+			if t.Kind() == reflect.Slice || t.Kind() == reflect.Array || t.Kind() == reflect.Map {
+				t = t.Elem()
+			}
+		}
+		if field.Anonymous {
+			continue
+		}
+
+		jsonName := strings.Split(field.Tag.Get("json"), ",")[0]
+		if len(jsonName) == 0 {
+			jsonName = field.Name
+		}
+		path = append(path, jsonName+index)
+	}
+
+	return strings.Join(path, ".")
+}
+
+// jsonTypeName returns the JSON type of a Go type
+func jsonTypeName(t reflect.Type) string {
+	switch t.Kind() {
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	default:
+		return "object"
+	}
+}
+
+// fieldName returns the name of a field in the messages. The whole request body has no field
+func fieldName(field string) string {
+	if len(field) == 0 {
+		return "request body"
+	}
+	return field
+}
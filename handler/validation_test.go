@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	libErrors "github.com/desarrollogj/golang-api-example/libs/errors"
+	"github.com/desarrollogj/golang-api-example/libs/i18n"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidationErrorCauses_GivenAnUpdateRequestWithNotValidFields_WhenGetCauses_ThenReturnTheJSONPaths(t *testing.T) {
+	t.Log("Successfully get the causes of a not valid request with the JSON path of the fields")
+
+	validate = newValidator()
+	req := UserUpdateRequest{UserCreateRequest{
+		FirstName:   "Foo",
+		LastName:    "Bar",
+		Email:       "not an email",
+		ExternalIDs: []ExternalIDRequest{{Source: "crm", Id: "C-1"}, {Id: "C-2"}},
+	}}
+
+	causes := validationErrorCauses(req, validate.Struct(req), i18n.English)
+
+	assert.Equal(t, []libErrors.ErrorCause{
+		{Field: "email", Rule: "email", Message: "email must be a valid email address"},
+		{Field: "externalIds[1].source", Rule: "required", Message: "source is a required field"},
+	}, causes)
+}
+
+func TestValidationErrorCauses_GivenARequestWithTooManyItems_WhenGetCauses_ThenReturnTheMaximum(t *testing.T) {
+	t.Log("Successfully get the cause of a list with too many items")
+
+	validate = newValidator()
+	req := UserRolesRequest{Roles: make([]string, 21)}
+	for i := range req.Roles {
+		req.Roles[i] = "viewer"
+	}
+
+	causes := validationErrorCauses(req, validate.Struct(req), i18n.English)
+
+	assert.Equal(t, []libErrors.ErrorCause{{Field: "roles", Rule: "max", Param: "20", Message: "roles must contain at maximum 20 items"}}, causes)
+}
+
+func TestDecodeErrorCauses_GivenNotValidJSONDocuments_WhenGetCauses_ThenReturnTheCause(t *testing.T) {
+	t.Log("Successfully get the cause of a request body that can not be decoded")
+
+	cases := map[string]struct {
+		body  string
+		cause libErrors.ErrorCause
+	}{
+		"empty":         {"", libErrors.ErrorCause{Rule: "required", Message: "request body is required"}},
+		"syntax":        {`{"firstName": }`, libErrors.ErrorCause{Rule: "json", Message: "request body is not a valid JSON document, error at offset 15"}},
+		"incomplete":    {`{"firstName": "Foo"`, libErrors.ErrorCause{Rule: "json", Message: "request body is not a complete JSON document"}},
+		"type":          {`{"firstName": 10}`, libErrors.ErrorCause{Field: "firstName", Rule: "type", Param: "string", Message: "firstName must be of type string"}},
+		"nested type":   {`{"address": {"city": true}}`, libErrors.ErrorCause{Field: "address.city", Rule: "type", Param: "string", Message: "address.city must be of type string"}},
+		"unknown field": {`{"nickname": "Foo"}`, libErrors.ErrorCause{Field: "nickname", Rule: "unknown", Message: "nickname is not a known field"}},
+	}
+
+	for name, c := range cases {
+		var req UserUpdateRequest
+		decoder := json.NewDecoder(bytes.NewReader([]byte(c.body)))
+		decoder.DisallowUnknownFields()
+		err := decoder.Decode(&req)
+
+		causes := decodeErrorCauses(err)
+
+		assert.Len(t, causes, 1, name)
+		assert.Equal(t, c.cause.Field, causes[0].Field, name)
+		assert.Equal(t, c.cause.Rule, causes[0].Rule, name)
+		assert.Equal(t, c.cause.Param, causes[0].Param, name)
+		assert.Equal(t, c.cause.Message, causes[0].Message, name)
+	}
+}
+
+func TestDecodeErrorCauses_GivenAnotherError_WhenGetCauses_ThenReturnNoCauses(t *testing.T) {
+	t.Log("Successfully get no causes of an error that is not a decode error")
+
+	assert.Nil(t, decodeErrorCauses(errors.New("read error")))
+}
@@ -0,0 +1,44 @@
+package idempotency
+
+import (
+	"errors"
+
+	"github.com/desarrollogj/golang-api-example/domain"
+	"github.com/stretchr/testify/mock"
+)
+
+type repositoryMock struct {
+	mock.Mock
+}
+
+func (m *repositoryMock) Acquire(record domain.IdempotencyRecord) (domain.IdempotencyRecord, bool, error) {
+	args := m.Called(record)
+
+	current, ok := args.Get(0).(domain.IdempotencyRecord)
+	if !ok {
+		return domain.IdempotencyRecord{}, false, errors.New("mock error")
+	}
+
+	return current, args.Bool(1), args.Error(2)
+}
+
+func (m *repositoryMock) Complete(record domain.IdempotencyRecord) error {
+	args := m.Called(record)
+	return args.Error(0)
+}
+
+func (m *repositoryMock) Release(record domain.IdempotencyRecord) error {
+	args := m.Called(record)
+	return args.Error(0)
+}
+
+func (m *repositoryMock) DeleteByResource(resourceReference string) (int64, error) {
+	args := m.Called(resourceReference)
+
+	removed, ok := args.Get(0).(int64)
+	if !ok {
+		return 0, errors.New("mock error")
+	}
+
+	return removed, args.Error(1)
+}
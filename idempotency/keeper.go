@@ -0,0 +1,104 @@
+package idempotency
+
+import (
+	"time"
+
+	"github.com/desarrollogj/golang-api-example/domain"
+	"github.com/desarrollogj/golang-api-example/infrastructure"
+	"github.com/desarrollogj/golang-api-example/libs/errors"
+	"github.com/desarrollogj/golang-api-example/libs/logger"
+)
+
+// pollInterval is the time between the checks of a key locked by a concurrent request
+const pollInterval = 100 * time.Millisecond
+
+// Keeper represents the methods to be implemented to process the requests with the same idempotency key only once
+type Keeper interface {
+	// Begin locks the key for a new request, and returns the processing record. When the key was already used
+	// by the same request, it returns the completed record with the response to replay
+	Begin(key string, fingerprint string) (domain.IdempotencyRecord, error)
+	// Complete stores the response of a processing record
+	Complete(record domain.IdempotencyRecord) error
+	// Release unlocks the key of a processing record without storing a response
+	Release(record domain.IdempotencyRecord) error
+}
+
+// defaultKeeper is the default implementation of Keeper interface
+type defaultKeeper struct {
+	config     domain.IdempotencyConfiguration
+	repository infrastructure.IdempotencyRepository
+}
+
+// NewDefaultKeeper creates a defaultKeeper instance
+func NewDefaultKeeper(config domain.IdempotencyConfiguration, repository infrastructure.IdempotencyRepository) defaultKeeper {
+	return defaultKeeper{
+		config:     config,
+		repository: repository,
+	}
+}
+
+// Begin waits while a concurrent request with the same key is processed, up to the configured wait timeout.
+// Reusing the key with a different request is not allowed
+func (k defaultKeeper) Begin(key string, fingerprint string) (domain.IdempotencyRecord, error) {
+	deadline := time.Now().Add(time.Duration(k.config.WaitTimeoutSeconds) * time.Second)
+	for {
+		// Mongo stores the dates in milliseconds, and the record is found by its creation date
+		now := time.Now().UTC().Truncate(time.Millisecond)
+		record := domain.IdempotencyRecord{
+			Key:         key,
+			Fingerprint: fingerprint,
+			Status:      domain.IdempotencyStatusProcessing,
+			LockedUntil: now.Add(time.Duration(k.config.LockTimeoutSeconds) * time.Second),
+			CreatedDate: now,
+			ExpiresDate: now.Add(time.Duration(k.config.TTLSeconds) * time.Second),
+		}
+
+		current, acquired, err := k.repository.Acquire(record)
+		if err != nil {
+			errMsg := "unexpected error when acquire the idempotency key"
+			logger.AppLog.Error().Err(err).Msg(errMsg)
+			return domain.IdempotencyRecord{}, errors.NewFatalError(errMsg)
+		}
+		if acquired {
+			return record, nil
+		}
+		// An empty record was removed after the key was found used, so it is acquired again right away
+		if len(current.Key) > 0 {
+			if current.Fingerprint != fingerprint {
+				return domain.IdempotencyRecord{}, errors.NewUnprocessableError("idempotency key was already used with a different request").WithKey("idempotency_key_reused")
+			}
+			if current.Status == domain.IdempotencyStatusCompleted {
+				return current, nil
+			}
+		}
+		if !time.Now().Before(deadline) {
+			return domain.IdempotencyRecord{}, errors.NewConflictError("a request with the same idempotency key is being processed").WithKey("idempotency_key_processing")
+		}
+		if len(current.Key) > 0 {
+			time.Sleep(pollInterval)
+		}
+	}
+}
+
+func (k defaultKeeper) Complete(record domain.IdempotencyRecord) error {
+	record.Status = domain.IdempotencyStatusCompleted
+	err := k.repository.Complete(record)
+	if err != nil {
+		errMsg := "unexpected error when store the idempotent response"
+		logger.AppLog.Error().Err(err).Msg(errMsg)
+		return errors.NewFatalError(errMsg)
+	}
+
+	return nil
+}
+
+func (k defaultKeeper) Release(record domain.IdempotencyRecord) error {
+	err := k.repository.Release(record)
+	if err != nil {
+		errMsg := "unexpected error when release the idempotency key"
+		logger.AppLog.Error().Err(err).Msg(errMsg)
+		return errors.NewFatalError(errMsg)
+	}
+
+	return nil
+}
@@ -0,0 +1,154 @@
+package idempotency
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/desarrollogj/golang-api-example/domain"
+	appErrors "github.com/desarrollogj/golang-api-example/libs/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var config = domain.IdempotencyConfiguration{
+	TTLSeconds:         86400,
+	LockTimeoutSeconds: 60,
+	WaitTimeoutSeconds: 1,
+}
+
+func TestKeeper_GivenAFreeKey_WhenBegin_ThenReturnAProcessingRecord(t *testing.T) {
+	t.Log("Successfully lock a free idempotency key")
+
+	repositoryMock := new(repositoryMock)
+	repositoryMock.On("Acquire", mock.MatchedBy(func(record domain.IdempotencyRecord) bool {
+		return record.Key == "KEY1" && record.Fingerprint == "FP1" && record.Status == domain.IdempotencyStatusProcessing &&
+			record.LockedUntil.Equal(record.CreatedDate.Add(time.Minute)) &&
+			record.ExpiresDate.Equal(record.CreatedDate.Add(24*time.Hour))
+	})).Return(domain.IdempotencyRecord{}, true, nil)
+
+	keeper := NewDefaultKeeper(config, repositoryMock)
+
+	record, err := keeper.Begin("KEY1", "FP1")
+
+	assert.Nil(t, err)
+	assert.Equal(t, "KEY1", record.Key)
+	assert.Equal(t, domain.IdempotencyStatusProcessing, record.Status)
+	repositoryMock.AssertExpectations(t)
+}
+
+func TestKeeper_GivenACompletedKey_WhenBeginTheSameRequest_ThenReturnTheCompletedRecord(t *testing.T) {
+	t.Log("Successfully return the response of a completed request to replay it")
+
+	completed := domain.IdempotencyRecord{
+		Key:            "KEY1",
+		Fingerprint:    "FP1",
+		Status:         domain.IdempotencyStatusCompleted,
+		ResponseStatus: 201,
+	}
+	repositoryMock := new(repositoryMock)
+	repositoryMock.On("Acquire", mock.AnythingOfType("IdempotencyRecord")).Return(completed, false, nil)
+
+	keeper := NewDefaultKeeper(config, repositoryMock)
+
+	record, err := keeper.Begin("KEY1", "FP1")
+
+	assert.Nil(t, err)
+	assert.Equal(t, completed, record)
+}
+
+func TestKeeper_GivenAUsedKey_WhenBeginADifferentRequest_ThenReturnAnUnprocessableError(t *testing.T) {
+	t.Log("Failure to reuse an idempotency key with a different request")
+
+	repositoryMock := new(repositoryMock)
+	repositoryMock.On("Acquire", mock.AnythingOfType("IdempotencyRecord")).
+		Return(domain.IdempotencyRecord{Key: "KEY1", Fingerprint: "FP1", Status: domain.IdempotencyStatusCompleted}, false, nil)
+
+	keeper := NewDefaultKeeper(config, repositoryMock)
+
+	_, err := keeper.Begin("KEY1", "FP2")
+
+	assert.NotNil(t, err)
+	assert.Equal(t, "idempotency key was already used with a different request", err.Error())
+	assert.Equal(t, appErrors.UnprocessableErrorCode, err.(*appErrors.BusinessError).Err)
+}
+
+func TestKeeper_GivenAKeyBeingProcessed_WhenBegin_ThenWaitForTheResponse(t *testing.T) {
+	t.Log("Successfully wait for a concurrent request with the same idempotency key")
+
+	processing := domain.IdempotencyRecord{Key: "KEY1", Fingerprint: "FP1", Status: domain.IdempotencyStatusProcessing}
+	completed := domain.IdempotencyRecord{Key: "KEY1", Fingerprint: "FP1", Status: domain.IdempotencyStatusCompleted, ResponseStatus: 201}
+	repositoryMock := new(repositoryMock)
+	repositoryMock.On("Acquire", mock.AnythingOfType("IdempotencyRecord")).Return(processing, false, nil).Twice()
+	repositoryMock.On("Acquire", mock.AnythingOfType("IdempotencyRecord")).Return(completed, false, nil).Once()
+
+	keeper := NewDefaultKeeper(config, repositoryMock)
+
+	record, err := keeper.Begin("KEY1", "FP1")
+
+	assert.Nil(t, err)
+	assert.Equal(t, completed, record)
+	repositoryMock.AssertNumberOfCalls(t, "Acquire", 3)
+}
+
+func TestKeeper_GivenAKeyBeingProcessed_WhenBeginAndTheWaitTimedOut_ThenReturnAConflictError(t *testing.T) {
+	t.Log("Failure to begin a request while a concurrent request with the same idempotency key is processed")
+
+	processing := domain.IdempotencyRecord{Key: "KEY1", Fingerprint: "FP1", Status: domain.IdempotencyStatusProcessing}
+	repositoryMock := new(repositoryMock)
+	repositoryMock.On("Acquire", mock.AnythingOfType("IdempotencyRecord")).Return(processing, false, nil)
+
+	keeper := NewDefaultKeeper(domain.IdempotencyConfiguration{WaitTimeoutSeconds: 0}, repositoryMock)
+
+	_, err := keeper.Begin("KEY1", "FP1")
+
+	assert.NotNil(t, err)
+	assert.Equal(t, "a request with the same idempotency key is being processed", err.Error())
+	assert.Equal(t, appErrors.ConflictErrorCode, err.(*appErrors.BusinessError).Err)
+}
+
+func TestKeeper_GivenAKey_WhenBeginAndRepositoryFailed_ThenReturnAFatalError(t *testing.T) {
+	t.Log("Failure to begin a request because the repository returned an error")
+
+	repositoryMock := new(repositoryMock)
+	repositoryMock.On("Acquire", mock.AnythingOfType("IdempotencyRecord")).Return(domain.IdempotencyRecord{}, false, errors.New("repository error"))
+
+	keeper := NewDefaultKeeper(config, repositoryMock)
+
+	_, err := keeper.Begin("KEY1", "FP1")
+
+	assert.NotNil(t, err)
+	assert.Equal(t, "unexpected error when acquire the idempotency key", err.Error())
+}
+
+func TestKeeper_GivenAProcessingRecord_WhenComplete_ThenStoreItAsCompleted(t *testing.T) {
+	t.Log("Successfully store the response of a request")
+
+	record := domain.IdempotencyRecord{Key: "KEY1", Status: domain.IdempotencyStatusProcessing, ResponseStatus: 201}
+	completed := record
+	completed.Status = domain.IdempotencyStatusCompleted
+	repositoryMock := new(repositoryMock)
+	repositoryMock.On("Complete", completed).Return(nil)
+
+	keeper := NewDefaultKeeper(config, repositoryMock)
+
+	err := keeper.Complete(record)
+
+	assert.Nil(t, err)
+	repositoryMock.AssertExpectations(t)
+}
+
+func TestKeeper_GivenAProcessingRecord_WhenReleaseAndRepositoryFailed_ThenReturnAFatalError(t *testing.T) {
+	t.Log("Failure to release an idempotency key because the repository returned an error")
+
+	record := domain.IdempotencyRecord{Key: "KEY1", Status: domain.IdempotencyStatusProcessing}
+	repositoryMock := new(repositoryMock)
+	repositoryMock.On("Release", record).Return(errors.New("repository error"))
+
+	keeper := NewDefaultKeeper(config, repositoryMock)
+
+	err := keeper.Release(record)
+
+	assert.NotNil(t, err)
+	assert.Equal(t, "unexpected error when release the idempotency key", err.Error())
+}
@@ -0,0 +1,154 @@
+package infrastructure
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/desarrollogj/golang-api-example/domain"
+	"github.com/desarrollogj/golang-api-example/libs/database"
+	"github.com/desarrollogj/golang-api-example/libs/logger"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// APIKeyRepository represents the methods to be implemented by API keys repositories
+type APIKeyRepository interface {
+	FindAll() ([]domain.APIKey, error)
+	FindByReference(reference string) (domain.APIKey, error)
+	FindByPrefix(prefix string) (domain.APIKey, error)
+	Create(key domain.APIKey) (domain.APIKey, error)
+	Update(key domain.APIKey) (domain.APIKey, error)
+	UpdateLastUsed(reference string, date time.Time) error
+}
+
+// mongoAPIKeyRepository is the MongoDB implementation of APIKeyRepository
+type mongoAPIKeyRepository struct {
+	config domain.MongoRepositoryConfiguration
+	mapper APIKeyMongoRepositoryMapper
+}
+
+// NewMongoAPIKeyRepository creates a new mongoAPIKeyRepository
+func NewMongoAPIKeyRepository(config domain.MongoRepositoryConfiguration, mapper APIKeyMongoRepositoryMapper) mongoAPIKeyRepository {
+	return mongoAPIKeyRepository{
+		config: config,
+		mapper: mapper,
+	}
+}
+
+func (r mongoAPIKeyRepository) FindAll() ([]domain.APIKey, error) {
+	client := database.Mongo.Client
+	collection := client.Database(r.config.Database).Collection(r.config.APIKeysCollection)
+
+	sort := options.Find().SetSort(bson.D{{Key: "created_date", Value: 1}})
+	cur, err := collection.Find(context.TODO(), bson.D{}, sort)
+	if err != nil {
+		errMsg := "unexpected error when find all API keys"
+		logger.AppLog.Error().Err(err).Msg(errMsg)
+		return []domain.APIKey{}, errors.New(errMsg)
+	}
+
+	mongoKeys := []MongoAPIKey{}
+	err = cur.All(context.TODO(), &mongoKeys)
+	if err != nil {
+		errMsg := "unexpected error when find all API keys"
+		logger.AppLog.Error().Err(err).Msg(errMsg)
+		return []domain.APIKey{}, errors.New(errMsg)
+	}
+
+	keys := []domain.APIKey{}
+	for _, key := range mongoKeys {
+		keys = append(keys, r.mapper.MapRepositoryToDomain(key))
+	}
+
+	return keys, nil
+}
+
+// FindByReference returns the API key, or an empty one when it does not exist
+func (r mongoAPIKeyRepository) FindByReference(reference string) (domain.APIKey, error) {
+	return r.findOne(bson.D{{Key: "reference", Value: reference}})
+}
+
+// FindByPrefix returns the API key with the prefix, or an empty one when it does not exist
+func (r mongoAPIKeyRepository) FindByPrefix(prefix string) (domain.APIKey, error) {
+	return r.findOne(bson.D{{Key: "prefix", Value: prefix}})
+}
+
+func (r mongoAPIKeyRepository) Create(key domain.APIKey) (domain.APIKey, error) {
+	client := database.Mongo.Client
+	collection := client.Database(r.config.Database).Collection(r.config.APIKeysCollection)
+
+	mongoKey := r.mapper.MapDomainToRepository(key)
+	mongoKey.ID = primitive.NewObjectID()
+	_, err := collection.InsertOne(context.TODO(), mongoKey)
+	if err != nil {
+		errMsg := "unexpected error when create the API key"
+		logger.AppLog.Error().Err(err).Msg(errMsg)
+		return domain.APIKey{}, errors.New(errMsg)
+	}
+
+	return key, nil
+}
+
+// Update replaces the API key data. The last used date is kept, it is only changed by UpdateLastUsed
+func (r mongoAPIKeyRepository) Update(key domain.APIKey) (domain.APIKey, error) {
+	client := database.Mongo.Client
+	collection := client.Database(r.config.Database).Collection(r.config.APIKeysCollection)
+
+	mongoKey := r.mapper.MapDomainToRepository(key)
+	update := bson.D{{Key: "$set", Value: bson.D{
+		{Key: "name", Value: mongoKey.Name},
+		{Key: "prefix", Value: mongoKey.Prefix},
+		{Key: "secret_hash", Value: mongoKey.SecretHash},
+		{Key: "scopes", Value: mongoKey.Scopes},
+		{Key: "expires_date", Value: mongoKey.ExpiresDate},
+		{Key: "revoked_date", Value: mongoKey.RevokedDate},
+		{Key: "updated_date", Value: mongoKey.UpdatedDate},
+	}}}
+	result, err := collection.UpdateOne(context.TODO(), bson.D{{Key: "reference", Value: key.Reference}}, update)
+	if err != nil {
+		errMsg := "unexpected error when update the API key"
+		logger.AppLog.Error().Err(err).Msg(errMsg)
+		return domain.APIKey{}, errors.New(errMsg)
+	}
+	if result.MatchedCount != 1 {
+		return domain.APIKey{}, errors.New("API key to update was not found")
+	}
+
+	return key, nil
+}
+
+func (r mongoAPIKeyRepository) UpdateLastUsed(reference string, date time.Time) error {
+	client := database.Mongo.Client
+	collection := client.Database(r.config.Database).Collection(r.config.APIKeysCollection)
+
+	update := bson.D{{Key: "$set", Value: bson.D{{Key: "last_used_date", Value: date}}}}
+	_, err := collection.UpdateOne(context.TODO(), bson.D{{Key: "reference", Value: reference}}, update)
+	if err != nil {
+		errMsg := "unexpected error when update the API key last used date"
+		logger.AppLog.Error().Err(err).Msg(errMsg)
+		return errors.New(errMsg)
+	}
+
+	return nil
+}
+
+func (r mongoAPIKeyRepository) findOne(filter bson.D) (domain.APIKey, error) {
+	client := database.Mongo.Client
+	collection := client.Database(r.config.Database).Collection(r.config.APIKeysCollection)
+
+	key := MongoAPIKey{}
+	err := collection.FindOne(context.TODO(), filter).Decode(&key)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return domain.APIKey{}, nil
+		}
+		errMsg := "unexpected error when find the API key"
+		logger.AppLog.Error().Err(err).Msg(errMsg)
+		return domain.APIKey{}, errors.New(errMsg)
+	}
+
+	return r.mapper.MapRepositoryToDomain(key), nil
+}
@@ -0,0 +1,48 @@
+package infrastructure
+
+import "github.com/desarrollogj/golang-api-example/domain"
+
+// APIKeyMongoRepositoryMapper represents the methods to be implemented by mongo API keys mapper
+type APIKeyMongoRepositoryMapper interface {
+	MapDomainToRepository(key domain.APIKey) MongoAPIKey
+	MapRepositoryToDomain(key MongoAPIKey) domain.APIKey
+}
+
+// defaultAPIKeyMongoRepositoryMapper is the default implementation of APIKeyMongoRepositoryMapper
+type defaultAPIKeyMongoRepositoryMapper struct {
+}
+
+// NewDefaultAPIKeyMongoRepositoryMapper creates a new defaultAPIKeyMongoRepositoryMapper
+func NewDefaultAPIKeyMongoRepositoryMapper() defaultAPIKeyMongoRepositoryMapper {
+	return defaultAPIKeyMongoRepositoryMapper{}
+}
+
+func (m defaultAPIKeyMongoRepositoryMapper) MapDomainToRepository(key domain.APIKey) MongoAPIKey {
+	return MongoAPIKey{
+		Reference:    key.Reference,
+		Name:         key.Name,
+		Prefix:       key.Prefix,
+		SecretHash:   key.SecretHash,
+		Scopes:       key.Scopes,
+		ExpiresDate:  key.ExpiresDate,
+		LastUsedDate: key.LastUsedDate,
+		RevokedDate:  key.RevokedDate,
+		CreatedDate:  key.CreatedDate,
+		UpdatedDate:  key.UpdatedDate,
+	}
+}
+
+func (m defaultAPIKeyMongoRepositoryMapper) MapRepositoryToDomain(key MongoAPIKey) domain.APIKey {
+	return domain.APIKey{
+		Reference:    key.Reference,
+		Name:         key.Name,
+		Prefix:       key.Prefix,
+		SecretHash:   key.SecretHash,
+		Scopes:       key.Scopes,
+		ExpiresDate:  key.ExpiresDate,
+		LastUsedDate: key.LastUsedDate,
+		RevokedDate:  key.RevokedDate,
+		CreatedDate:  key.CreatedDate,
+		UpdatedDate:  key.UpdatedDate,
+	}
+}
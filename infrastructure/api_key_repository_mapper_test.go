@@ -0,0 +1,44 @@
+package infrastructure
+
+import (
+	"testing"
+	"time"
+
+	"github.com/desarrollogj/golang-api-example/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIKeyMongoRepositoryMapper_GivenDomainData_WhenMap_ThenMapToRepositoryDataAndBack(t *testing.T) {
+	t.Log("Should map API key domain data to API key repository data and back")
+
+	now := time.Now().UTC()
+	expires := now.Add(time.Hour)
+	domainKey := domain.APIKey{
+		Reference:    "KEY1",
+		Name:         "batch",
+		Prefix:       "0123456789ab",
+		SecretHash:   "hash",
+		Scopes:       []string{domain.APIKeyScopeRead},
+		ExpiresDate:  &expires,
+		LastUsedDate: &now,
+		CreatedDate:  now,
+		UpdatedDate:  now,
+	}
+	expectedRepoKey := MongoAPIKey{
+		Reference:    "KEY1",
+		Name:         "batch",
+		Prefix:       "0123456789ab",
+		SecretHash:   "hash",
+		Scopes:       []string{domain.APIKeyScopeRead},
+		ExpiresDate:  &expires,
+		LastUsedDate: &now,
+		CreatedDate:  now,
+		UpdatedDate:  now,
+	}
+
+	mapper := NewDefaultAPIKeyMongoRepositoryMapper()
+	repoKey := mapper.MapDomainToRepository(domainKey)
+
+	assert.Equal(t, expectedRepoKey, repoKey)
+	assert.Equal(t, domainKey, mapper.MapRepositoryToDomain(repoKey))
+}
@@ -0,0 +1,78 @@
+package infrastructure
+
+import (
+	"context"
+	"errors"
+
+	"github.com/desarrollogj/golang-api-example/domain"
+	"github.com/desarrollogj/golang-api-example/libs/database"
+	"github.com/desarrollogj/golang-api-example/libs/logger"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// AuditRepository represents the methods to be implemented by audit repositories
+type AuditRepository interface {
+	Create(entry domain.AuditEntry) (domain.AuditEntry, error)
+	FindByEntity(entityType string, entityReference string) ([]domain.AuditEntry, error)
+}
+
+// mongoAuditRepository is the MongoDB implementation of AuditRepository
+type mongoAuditRepository struct {
+	config domain.MongoRepositoryConfiguration
+	mapper AuditMongoRepositoryMapper
+}
+
+// NewMongoAuditRepository creates a new mongoAuditRepository
+func NewMongoAuditRepository(config domain.MongoRepositoryConfiguration, mapper AuditMongoRepositoryMapper) mongoAuditRepository {
+	return mongoAuditRepository{
+		config: config,
+		mapper: mapper,
+	}
+}
+
+func (r mongoAuditRepository) Create(entry domain.AuditEntry) (domain.AuditEntry, error) {
+	client := database.Mongo.Client
+	collection := client.Database(r.config.Database).Collection(r.config.AuditCollection)
+
+	mongoEntry := r.mapper.MapDomainToRepository(entry)
+	mongoEntry.ID = primitive.NewObjectID()
+	_, err := collection.InsertOne(context.TODO(), mongoEntry)
+	if err != nil {
+		errMsg := "unexpected error when create the audit entry"
+		logger.AppLog.Error().Err(err).Msg(errMsg)
+		return domain.AuditEntry{}, errors.New(errMsg)
+	}
+
+	return entry, nil
+}
+
+func (r mongoAuditRepository) FindByEntity(entityType string, entityReference string) ([]domain.AuditEntry, error) {
+	client := database.Mongo.Client
+	collection := client.Database(r.config.Database).Collection(r.config.AuditCollection)
+
+	filter := bson.D{{Key: "entity_type", Value: entityType}, {Key: "entity_reference", Value: entityReference}}
+	sort := options.Find().SetSort(bson.D{{Key: "created_date", Value: 1}})
+	cur, err := collection.Find(context.TODO(), filter, sort)
+	if err != nil {
+		errMsg := "unexpected error when find audit entries by entity"
+		logger.AppLog.Error().Err(err).Msg(errMsg)
+		return []domain.AuditEntry{}, errors.New(errMsg)
+	}
+
+	mongoEntries := []MongoAuditEntry{}
+	err = cur.All(context.TODO(), &mongoEntries)
+	if err != nil {
+		errMsg := "unexpected error when find audit entries by entity"
+		logger.AppLog.Error().Err(err).Msg(errMsg)
+		return []domain.AuditEntry{}, errors.New(errMsg)
+	}
+
+	entries := []domain.AuditEntry{}
+	for _, entry := range mongoEntries {
+		entries = append(entries, r.mapper.MapRepositoryToDomain(entry))
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,40 @@
+package infrastructure
+
+import "github.com/desarrollogj/golang-api-example/domain"
+
+// AuditMongoRepositoryMapper represents the methods to be implemented by mongo audit entries mapper
+type AuditMongoRepositoryMapper interface {
+	MapDomainToRepository(entry domain.AuditEntry) MongoAuditEntry
+	MapRepositoryToDomain(entry MongoAuditEntry) domain.AuditEntry
+}
+
+// defaultAuditMongoRepositoryMapper is the default implementation of AuditMongoRepositoryMapper
+type defaultAuditMongoRepositoryMapper struct {
+}
+
+// NewDefaultAuditMongoRepositoryMapper creates a new defaultAuditMongoRepositoryMapper
+func NewDefaultAuditMongoRepositoryMapper() defaultAuditMongoRepositoryMapper {
+	return defaultAuditMongoRepositoryMapper{}
+}
+
+func (m defaultAuditMongoRepositoryMapper) MapDomainToRepository(entry domain.AuditEntry) MongoAuditEntry {
+	return MongoAuditEntry{
+		Reference:       entry.Reference,
+		Action:          entry.Action,
+		EntityType:      entry.EntityType,
+		EntityReference: entry.EntityReference,
+		Details:         entry.Details,
+		CreatedDate:     entry.CreatedDate,
+	}
+}
+
+func (m defaultAuditMongoRepositoryMapper) MapRepositoryToDomain(entry MongoAuditEntry) domain.AuditEntry {
+	return domain.AuditEntry{
+		Reference:       entry.Reference,
+		Action:          entry.Action,
+		EntityType:      entry.EntityType,
+		EntityReference: entry.EntityReference,
+		Details:         entry.Details,
+		CreatedDate:     entry.CreatedDate,
+	}
+}
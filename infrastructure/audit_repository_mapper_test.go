@@ -0,0 +1,61 @@
+package infrastructure
+
+import (
+	"testing"
+	"time"
+
+	"github.com/desarrollogj/golang-api-example/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuditMongoRepositoryMapper_GivenDomainData_WhenMap_ThenMapToRepositoryData(t *testing.T) {
+	t.Log("Should map audit entry domain data to audit entry repository data")
+
+	now := time.Now().UTC()
+	domainEntry := domain.AuditEntry{
+		Reference:       "AUDIT1",
+		Action:          domain.AuditActionUserErase,
+		EntityType:      domain.AuditEntityUser,
+		EntityReference: "USER1",
+		Details:         map[string]string{"key": "value"},
+		CreatedDate:     now,
+	}
+	expectedRepoEntry := MongoAuditEntry{
+		Reference:       "AUDIT1",
+		Action:          "user_erased",
+		EntityType:      "user",
+		EntityReference: "USER1",
+		Details:         map[string]string{"key": "value"},
+		CreatedDate:     now,
+	}
+
+	mapper := NewDefaultAuditMongoRepositoryMapper()
+	repoEntry := mapper.MapDomainToRepository(domainEntry)
+
+	assert.Equal(t, expectedRepoEntry, repoEntry)
+}
+
+func TestAuditMongoRepositoryMapper_GivenRepositoryData_WhenMap_ThenMapToDomainData(t *testing.T) {
+	t.Log("Should map audit entry repository data to audit entry domain data")
+
+	now := time.Now().UTC()
+	repoEntry := MongoAuditEntry{
+		Reference:       "AUDIT1",
+		Action:          "user_erased",
+		EntityType:      "user",
+		EntityReference: "USER1",
+		CreatedDate:     now,
+	}
+	expectedDomainEntry := domain.AuditEntry{
+		Reference:       "AUDIT1",
+		Action:          domain.AuditActionUserErase,
+		EntityType:      domain.AuditEntityUser,
+		EntityReference: "USER1",
+		CreatedDate:     now,
+	}
+
+	mapper := NewDefaultAuditMongoRepositoryMapper()
+	domainEntry := mapper.MapRepositoryToDomain(repoEntry)
+
+	assert.Equal(t, expectedDomainEntry, domainEntry)
+}
@@ -0,0 +1,95 @@
+package infrastructure
+
+import (
+	"context"
+	"errors"
+
+	"github.com/desarrollogj/golang-api-example/domain"
+	"github.com/desarrollogj/golang-api-example/libs/database"
+	"github.com/desarrollogj/golang-api-example/libs/logger"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// CredentialRepository represents the methods to be implemented by credentials repositories
+type CredentialRepository interface {
+	FindByUserReference(userReference string) (domain.Credential, error)
+	Save(credential domain.Credential) (domain.Credential, error)
+	DeleteByUserReference(userReference string) error
+}
+
+// mongoCredentialRepository is the MongoDB implementation of CredentialRepository
+type mongoCredentialRepository struct {
+	config domain.MongoRepositoryConfiguration
+	mapper CredentialMongoRepositoryMapper
+}
+
+// NewMongoCredentialRepository creates a new mongoCredentialRepository
+func NewMongoCredentialRepository(config domain.MongoRepositoryConfiguration, mapper CredentialMongoRepositoryMapper) mongoCredentialRepository {
+	return mongoCredentialRepository{
+		config: config,
+		mapper: mapper,
+	}
+}
+
+// FindByUserReference returns the user credential, or an empty one when the user has no credential
+func (r mongoCredentialRepository) FindByUserReference(userReference string) (domain.Credential, error) {
+	client := database.Mongo.Client
+	collection := client.Database(r.config.Database).Collection(r.config.CredentialsCollection)
+
+	credential := MongoCredential{}
+	err := collection.FindOne(context.TODO(), bson.D{{Key: "user_reference", Value: userReference}}).Decode(&credential)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return domain.Credential{}, nil
+		}
+		errMsg := "unexpected error when find credential by user reference"
+		logger.AppLog.Error().Err(err).Msg(errMsg)
+		return domain.Credential{}, errors.New(errMsg)
+	}
+
+	return r.mapper.MapRepositoryToDomain(credential), nil
+}
+
+// Save creates or replaces the user credential. The creation date of an existing credential is kept
+func (r mongoCredentialRepository) Save(credential domain.Credential) (domain.Credential, error) {
+	client := database.Mongo.Client
+	collection := client.Database(r.config.Database).Collection(r.config.CredentialsCollection)
+
+	mongoCredential := r.mapper.MapDomainToRepository(credential)
+	filter := bson.D{{Key: "user_reference", Value: credential.UserReference}}
+	update := bson.D{
+		{Key: "$set", Value: bson.D{
+			{Key: "password_hash", Value: mongoCredential.PasswordHash},
+			{Key: "updated_date", Value: mongoCredential.UpdatedDate},
+		}},
+		{Key: "$setOnInsert", Value: bson.D{
+			{Key: "_id", Value: primitive.NewObjectID()},
+			{Key: "created_date", Value: mongoCredential.CreatedDate},
+		}},
+	}
+	_, err := collection.UpdateOne(context.TODO(), filter, update, options.Update().SetUpsert(true))
+	if err != nil {
+		errMsg := "unexpected error when save the credential"
+		logger.AppLog.Error().Err(err).Msg(errMsg)
+		return domain.Credential{}, errors.New(errMsg)
+	}
+
+	return credential, nil
+}
+
+func (r mongoCredentialRepository) DeleteByUserReference(userReference string) error {
+	client := database.Mongo.Client
+	collection := client.Database(r.config.Database).Collection(r.config.CredentialsCollection)
+
+	_, err := collection.DeleteOne(context.TODO(), bson.D{{Key: "user_reference", Value: userReference}})
+	if err != nil {
+		errMsg := "unexpected error when delete the credential"
+		logger.AppLog.Error().Err(err).Msg(errMsg)
+		return errors.New(errMsg)
+	}
+
+	return nil
+}
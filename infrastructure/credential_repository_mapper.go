@@ -0,0 +1,36 @@
+package infrastructure
+
+import "github.com/desarrollogj/golang-api-example/domain"
+
+// CredentialMongoRepositoryMapper represents the methods to be implemented by mongo credentials mapper
+type CredentialMongoRepositoryMapper interface {
+	MapDomainToRepository(credential domain.Credential) MongoCredential
+	MapRepositoryToDomain(credential MongoCredential) domain.Credential
+}
+
+// defaultCredentialMongoRepositoryMapper is the default implementation of CredentialMongoRepositoryMapper
+type defaultCredentialMongoRepositoryMapper struct {
+}
+
+// NewDefaultCredentialMongoRepositoryMapper creates a new defaultCredentialMongoRepositoryMapper
+func NewDefaultCredentialMongoRepositoryMapper() defaultCredentialMongoRepositoryMapper {
+	return defaultCredentialMongoRepositoryMapper{}
+}
+
+func (m defaultCredentialMongoRepositoryMapper) MapDomainToRepository(credential domain.Credential) MongoCredential {
+	return MongoCredential{
+		UserReference: credential.UserReference,
+		PasswordHash:  credential.PasswordHash,
+		CreatedDate:   credential.CreatedDate,
+		UpdatedDate:   credential.UpdatedDate,
+	}
+}
+
+func (m defaultCredentialMongoRepositoryMapper) MapRepositoryToDomain(credential MongoCredential) domain.Credential {
+	return domain.Credential{
+		UserReference: credential.UserReference,
+		PasswordHash:  credential.PasswordHash,
+		CreatedDate:   credential.CreatedDate,
+		UpdatedDate:   credential.UpdatedDate,
+	}
+}
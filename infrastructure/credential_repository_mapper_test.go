@@ -0,0 +1,33 @@
+package infrastructure
+
+import (
+	"testing"
+	"time"
+
+	"github.com/desarrollogj/golang-api-example/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCredentialMongoRepositoryMapper_GivenDomainData_WhenMap_ThenMapToRepositoryDataAndBack(t *testing.T) {
+	t.Log("Should map credential domain data to credential repository data and back")
+
+	now := time.Now().UTC()
+	domainCredential := domain.Credential{
+		UserReference: "USER1",
+		PasswordHash:  "$2a$10$hash",
+		CreatedDate:   now,
+		UpdatedDate:   now,
+	}
+	expectedRepoCredential := MongoCredential{
+		UserReference: "USER1",
+		PasswordHash:  "$2a$10$hash",
+		CreatedDate:   now,
+		UpdatedDate:   now,
+	}
+
+	mapper := NewDefaultCredentialMongoRepositoryMapper()
+	repoCredential := mapper.MapDomainToRepository(domainCredential)
+
+	assert.Equal(t, expectedRepoCredential, repoCredential)
+	assert.Equal(t, domainCredential, mapper.MapRepositoryToDomain(repoCredential))
+}
@@ -0,0 +1,259 @@
+package infrastructure
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/desarrollogj/golang-api-example/domain"
+	"github.com/desarrollogj/golang-api-example/libs/database"
+	"github.com/desarrollogj/golang-api-example/libs/logger"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// GroupRepository represents the methods to be implemented by groups repositories
+type GroupRepository interface {
+	FindAllActive() ([]domain.Group, error)
+	FindActiveByReference(reference string) (domain.Group, error)
+	FindActiveByMember(userReference string) ([]domain.Group, error)
+	FindByMember(userReference string) ([]domain.Group, error)
+	Create(group domain.Group) (domain.Group, error)
+	Update(group domain.Group) (domain.Group, error)
+	AddMember(groupReference string, member domain.GroupMember) (bool, error)
+	RemoveMember(groupReference string, userReference string, removedDate time.Time) (bool, error)
+	RemoveMemberFromAll(userReference string) (int64, error)
+	MoveMember(userReference string, toUserReference string, movedDate time.Time) (int64, error)
+}
+
+// mongoGroupRepository is the MongoDB implementation of GroupRepository
+type mongoGroupRepository struct {
+	config domain.MongoRepositoryConfiguration
+	mapper GroupMongoRepositoryMapper
+}
+
+// NewMongoGroupRepository creates a new mongoGroupRepository
+func NewMongoGroupRepository(config domain.MongoRepositoryConfiguration, mapper GroupMongoRepositoryMapper) mongoGroupRepository {
+	return mongoGroupRepository{
+		config: config,
+		mapper: mapper,
+	}
+}
+
+func (r mongoGroupRepository) FindAllActive() ([]domain.Group, error) {
+	return r.find(bson.D{{Key: "is_active", Value: true}})
+}
+
+// FindActiveByReference returns the active group, or an empty one when it does not exist
+func (r mongoGroupRepository) FindActiveByReference(reference string) (domain.Group, error) {
+	client := database.Mongo.Client
+	collection := client.Database(r.config.Database).Collection(r.config.GroupsCollection)
+
+	group := MongoGroup{}
+	filter := bson.D{{Key: "reference", Value: reference}, {Key: "is_active", Value: true}}
+	err := collection.FindOne(context.TODO(), filter).Decode(&group)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return domain.Group{}, nil
+		}
+		errMsg := "unexpected error when find group by its reference"
+		logger.AppLog.Error().Err(err).Msg(errMsg)
+		return domain.Group{}, errors.New(errMsg)
+	}
+
+	return r.mapper.MapRepositoryToDomain(group), nil
+}
+
+// FindActiveByMember returns the active groups the user belongs to
+func (r mongoGroupRepository) FindActiveByMember(userReference string) ([]domain.Group, error) {
+	return r.find(bson.D{{Key: "members.user_reference", Value: userReference}, {Key: "is_active", Value: true}})
+}
+
+// FindByMember returns the groups the user belongs to, including the inactive ones
+func (r mongoGroupRepository) FindByMember(userReference string) ([]domain.Group, error) {
+	return r.find(bson.D{{Key: "members.user_reference", Value: userReference}})
+}
+
+func (r mongoGroupRepository) Create(group domain.Group) (domain.Group, error) {
+	client := database.Mongo.Client
+	collection := client.Database(r.config.Database).Collection(r.config.GroupsCollection)
+
+	mongoGroup := r.mapper.MapDomainToRepository(group)
+	mongoGroup.ID = primitive.NewObjectID()
+	_, err := collection.InsertOne(context.TODO(), mongoGroup)
+	if err != nil {
+		errMsg := "unexpected error when create the group"
+		logger.AppLog.Error().Err(err).Msg(errMsg)
+		return domain.Group{}, errors.New(errMsg)
+	}
+
+	return group, nil
+}
+
+func (r mongoGroupRepository) Update(group domain.Group) (domain.Group, error) {
+	client := database.Mongo.Client
+	collection := client.Database(r.config.Database).Collection(r.config.GroupsCollection)
+
+	// The members are changed only by AddMember and RemoveMember, so concurrent membership changes are not lost
+	mongoGroup := r.mapper.MapDomainToRepository(group)
+	update := bson.D{{Key: "$set", Value: bson.D{
+		{Key: "name", Value: mongoGroup.Name},
+		{Key: "description", Value: mongoGroup.Description},
+		{Key: "is_active", Value: mongoGroup.IsActive},
+		{Key: "updated_date", Value: mongoGroup.UpdatedDate},
+	}}}
+	result, err := collection.UpdateOne(context.TODO(), bson.D{{Key: "reference", Value: group.Reference}}, update)
+	if err != nil {
+		errMsg := "unexpected error when update the group"
+		logger.AppLog.Error().Err(err).Msg(errMsg)
+		return domain.Group{}, errors.New(errMsg)
+	}
+	if result.MatchedCount != 1 {
+		return domain.Group{}, errors.New("group to update was not found")
+	}
+
+	return group, nil
+}
+
+// AddMember adds the member to the active group, unless the user is already a member. It returns false when the
+// group does not exist or the user is already a member
+func (r mongoGroupRepository) AddMember(groupReference string, member domain.GroupMember) (bool, error) {
+	client := database.Mongo.Client
+	collection := client.Database(r.config.Database).Collection(r.config.GroupsCollection)
+
+	// The members have their added date, so $addToSet would not detect an user added concurrently
+	filter := bson.D{
+		{Key: "reference", Value: groupReference},
+		{Key: "is_active", Value: true},
+		{Key: "members.user_reference", Value: bson.D{{Key: "$ne", Value: member.UserReference}}},
+	}
+	update := bson.D{
+		{Key: "$push", Value: bson.D{{Key: "members", Value: MongoGroupMember{
+			UserReference: member.UserReference,
+			AddedDate:     member.AddedDate,
+		}}}},
+		{Key: "$set", Value: bson.D{{Key: "updated_date", Value: member.AddedDate}}},
+	}
+	result, err := collection.UpdateOne(context.TODO(), filter, update)
+	if err != nil {
+		errMsg := "unexpected error when add the group member"
+		logger.AppLog.Error().Err(err).Msg(errMsg)
+		return false, errors.New(errMsg)
+	}
+
+	return result.ModifiedCount == 1, nil
+}
+
+// RemoveMember removes the user from the active group. It returns false when the group does not exist or the user
+// is not a member
+func (r mongoGroupRepository) RemoveMember(groupReference string, userReference string, removedDate time.Time) (bool, error) {
+	client := database.Mongo.Client
+	collection := client.Database(r.config.Database).Collection(r.config.GroupsCollection)
+
+	filter := bson.D{
+		{Key: "reference", Value: groupReference},
+		{Key: "is_active", Value: true},
+		{Key: "members.user_reference", Value: userReference},
+	}
+	update := bson.D{
+		{Key: "$pull", Value: bson.D{
+			{Key: "members", Value: bson.D{{Key: "user_reference", Value: userReference}}},
+		}},
+		{Key: "$set", Value: bson.D{{Key: "updated_date", Value: removedDate}}},
+	}
+	result, err := collection.UpdateOne(context.TODO(), filter, update)
+	if err != nil {
+		errMsg := "unexpected error when remove the group member"
+		logger.AppLog.Error().Err(err).Msg(errMsg)
+		return false, errors.New(errMsg)
+	}
+
+	return result.ModifiedCount == 1, nil
+}
+
+// RemoveMemberFromAll removes the user from all the groups, including the inactive ones, and returns the number of groups changed
+func (r mongoGroupRepository) RemoveMemberFromAll(userReference string) (int64, error) {
+	client := database.Mongo.Client
+	collection := client.Database(r.config.Database).Collection(r.config.GroupsCollection)
+
+	filter := bson.D{{Key: "members.user_reference", Value: userReference}}
+	update := bson.D{{Key: "$pull", Value: bson.D{
+		{Key: "members", Value: bson.D{{Key: "user_reference", Value: userReference}}},
+	}}}
+	result, err := collection.UpdateMany(context.TODO(), filter, update)
+	if err != nil {
+		errMsg := "unexpected error when remove the member from the groups"
+		logger.AppLog.Error().Err(err).Msg(errMsg)
+		return 0, errors.New(errMsg)
+	}
+
+	return result.ModifiedCount, nil
+}
+
+// MoveMember replaces the user by another user in all the groups, including the inactive ones, and returns the number
+// of groups changed. The other user keeps the user added date, unless it was already a member. Moving the members
+// again completes a move that failed
+func (r mongoGroupRepository) MoveMember(userReference string, toUserReference string, movedDate time.Time) (int64, error) {
+	client := database.Mongo.Client
+	collection := client.Database(r.config.Database).Collection(r.config.GroupsCollection)
+
+	replaceFilter := bson.D{{Key: "$and", Value: bson.A{
+		bson.D{{Key: "members.user_reference", Value: userReference}},
+		bson.D{{Key: "members.user_reference", Value: bson.D{{Key: "$ne", Value: toUserReference}}}},
+	}}}
+	replace := bson.D{{Key: "$set", Value: bson.D{
+		{Key: "members.$[member].user_reference", Value: toUserReference},
+		{Key: "updated_date", Value: movedDate},
+	}}}
+	replaceOptions := options.Update().SetArrayFilters(options.ArrayFilters{
+		Filters: []interface{}{bson.D{{Key: "member.user_reference", Value: userReference}}},
+	})
+	replaced, err := collection.UpdateMany(context.TODO(), replaceFilter, replace, replaceOptions)
+	if err != nil {
+		errMsg := "unexpected error when move the member to the groups"
+		logger.AppLog.Error().Err(err).Msg(errMsg)
+		return 0, errors.New(errMsg)
+	}
+
+	// The groups the other user already belonged to only lose the user
+	removeFilter := bson.D{{Key: "members.user_reference", Value: userReference}}
+	remove := bson.D{
+		{Key: "$pull", Value: bson.D{
+			{Key: "members", Value: bson.D{{Key: "user_reference", Value: userReference}}},
+		}},
+		{Key: "$set", Value: bson.D{{Key: "updated_date", Value: movedDate}}},
+	}
+	removed, err := collection.UpdateMany(context.TODO(), removeFilter, remove)
+	if err != nil {
+		errMsg := "unexpected error when move the member to the groups"
+		logger.AppLog.Error().Err(err).Msg(errMsg)
+		return 0, errors.New(errMsg)
+	}
+
+	return replaced.ModifiedCount + removed.ModifiedCount, nil
+}
+
+func (r mongoGroupRepository) find(filter bson.D) ([]domain.Group, error) {
+	client := database.Mongo.Client
+	collection := client.Database(r.config.Database).Collection(r.config.GroupsCollection)
+
+	sort := options.Find().SetSort(bson.D{{Key: "name", Value: 1}})
+	cur, err := collection.Find(context.TODO(), filter, sort)
+	if err != nil {
+		errMsg := "unexpected error when find groups"
+		logger.AppLog.Error().Err(err).Msg(errMsg)
+		return []domain.Group{}, errors.New(errMsg)
+	}
+
+	groups := []MongoGroup{}
+	err = cur.All(context.TODO(), &groups)
+	if err != nil {
+		errMsg := "unexpected error when find groups"
+		logger.AppLog.Error().Err(err).Msg(errMsg)
+		return []domain.Group{}, errors.New(errMsg)
+	}
+
+	return r.mapper.MapRepositoryListToDomainList(groups), nil
+}
@@ -0,0 +1,71 @@
+package infrastructure
+
+import "github.com/desarrollogj/golang-api-example/domain"
+
+// GroupMongoRepositoryMapper represents the methods to be implemented by mongo groups mapper
+type GroupMongoRepositoryMapper interface {
+	MapDomainToRepository(group domain.Group) MongoGroup
+	MapRepositoryToDomain(group MongoGroup) domain.Group
+	MapRepositoryListToDomainList(groups []MongoGroup) []domain.Group
+}
+
+// defaultGroupMongoRepositoryMapper is the default implementation of GroupMongoRepositoryMapper
+type defaultGroupMongoRepositoryMapper struct {
+}
+
+// NewDefaultGroupMongoRepositoryMapper creates a new defaultGroupMongoRepositoryMapper
+func NewDefaultGroupMongoRepositoryMapper() defaultGroupMongoRepositoryMapper {
+	return defaultGroupMongoRepositoryMapper{}
+}
+
+// MapDomainToRepository maps a domain group to a repository group. The members are always stored as an array
+func (m defaultGroupMongoRepositoryMapper) MapDomainToRepository(group domain.Group) MongoGroup {
+	members := []MongoGroupMember{}
+	for _, member := range group.Members {
+		members = append(members, MongoGroupMember{
+			UserReference: member.UserReference,
+			AddedDate:     member.AddedDate,
+		})
+	}
+
+	return MongoGroup{
+		Reference:   group.Reference,
+		Name:        group.Name,
+		Description: group.Description,
+		Members:     members,
+		IsActive:    group.IsActive,
+		CreatedDate: group.CreatedDate,
+		UpdatedDate: group.UpdatedDate,
+	}
+}
+
+func (m defaultGroupMongoRepositoryMapper) MapRepositoryToDomain(group MongoGroup) domain.Group {
+	var members []domain.GroupMember
+	for _, member := range group.Members {
+		members = append(members, domain.GroupMember{
+			UserReference: member.UserReference,
+			AddedDate:     member.AddedDate,
+		})
+	}
+
+	return domain.Group{
+		GenericEntity: domain.GenericEntity{
+			Reference:   group.Reference,
+			IsActive:    group.IsActive,
+			CreatedDate: group.CreatedDate,
+			UpdatedDate: group.UpdatedDate,
+		},
+		Name:        group.Name,
+		Description: group.Description,
+		Members:     members,
+	}
+}
+
+func (m defaultGroupMongoRepositoryMapper) MapRepositoryListToDomainList(groups []MongoGroup) []domain.Group {
+	domainGroups := []domain.Group{}
+	for _, group := range groups {
+		domainGroups = append(domainGroups, m.MapRepositoryToDomain(group))
+	}
+
+	return domainGroups
+}
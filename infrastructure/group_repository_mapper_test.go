@@ -0,0 +1,51 @@
+package infrastructure
+
+import (
+	"testing"
+	"time"
+
+	"github.com/desarrollogj/golang-api-example/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGroupMongoRepositoryMapper_GivenDomainData_WhenMap_ThenMapToRepositoryDataAndBack(t *testing.T) {
+	t.Log("Should map group domain data to group repository data and back")
+
+	now := time.Now().UTC()
+	domainGroup := domain.Group{
+		GenericEntity: domain.GenericEntity{
+			Reference:   "GROUP1",
+			IsActive:    true,
+			CreatedDate: now,
+			UpdatedDate: now,
+		},
+		Name:        "Backend",
+		Description: "Backend team",
+		Members:     []domain.GroupMember{{UserReference: "USER1", AddedDate: now}},
+	}
+	expectedRepoGroup := MongoGroup{
+		Reference:   "GROUP1",
+		Name:        "Backend",
+		Description: "Backend team",
+		Members:     []MongoGroupMember{{UserReference: "USER1", AddedDate: now}},
+		IsActive:    true,
+		CreatedDate: now,
+		UpdatedDate: now,
+	}
+
+	mapper := NewDefaultGroupMongoRepositoryMapper()
+	repoGroup := mapper.MapDomainToRepository(domainGroup)
+
+	assert.Equal(t, expectedRepoGroup, repoGroup)
+	assert.Equal(t, []domain.Group{domainGroup}, mapper.MapRepositoryListToDomainList([]MongoGroup{repoGroup}))
+}
+
+func TestGroupMongoRepositoryMapper_GivenAGroupWithoutMembers_WhenMapToRepository_ThenStoreAnEmptyArray(t *testing.T) {
+	t.Log("Should map a group without members to an empty members array")
+
+	mapper := NewDefaultGroupMongoRepositoryMapper()
+	repoGroup := mapper.MapDomainToRepository(domain.Group{GenericEntity: domain.GenericEntity{Reference: "GROUP1"}})
+
+	assert.NotNil(t, repoGroup.Members)
+	assert.Empty(t, repoGroup.Members)
+}
@@ -0,0 +1,207 @@
+package infrastructure
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/desarrollogj/golang-api-example/domain"
+	"github.com/desarrollogj/golang-api-example/libs/database"
+	"github.com/desarrollogj/golang-api-example/libs/logger"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// IdempotencyRepository represents the methods to be implemented by idempotency records repositories
+type IdempotencyRepository interface {
+	// Acquire stores the processing record when its key is free, expired, or locked by the same request with an expired lock.
+	// Otherwise it returns the current record of the key and false. The current record is empty if it was removed meanwhile
+	Acquire(record domain.IdempotencyRecord) (domain.IdempotencyRecord, bool, error)
+	// Complete stores the response of the processing record
+	Complete(record domain.IdempotencyRecord) error
+	// Release removes the processing record, so the request can be retried
+	Release(record domain.IdempotencyRecord) error
+	// DeleteByResource removes the stored responses about a resource, and returns how many were removed
+	DeleteByResource(resourceReference string) (int64, error)
+}
+
+// mongoIdempotencyRepository is the MongoDB implementation of IdempotencyRepository
+type mongoIdempotencyRepository struct {
+	config domain.MongoRepositoryConfiguration
+	mapper IdempotencyMongoRepositoryMapper
+}
+
+// NewMongoIdempotencyRepository creates a new mongoIdempotencyRepository
+func NewMongoIdempotencyRepository(config domain.MongoRepositoryConfiguration, mapper IdempotencyMongoRepositoryMapper) mongoIdempotencyRepository {
+	return mongoIdempotencyRepository{
+		config: config,
+		mapper: mapper,
+	}
+}
+
+func (r mongoIdempotencyRepository) Acquire(record domain.IdempotencyRecord) (domain.IdempotencyRecord, bool, error) {
+	client := database.Mongo.Client
+	collection := client.Database(r.config.Database).Collection(r.config.IdempotencyCollection)
+
+	mongoRecord, err := r.mapper.MapDomainToRepository(record)
+	if err != nil {
+		errMsg := "unexpected error when acquire the idempotency key"
+		logger.AppLog.Error().Err(err).Msg(errMsg)
+		return domain.IdempotencyRecord{}, false, errors.New(errMsg)
+	}
+	_, err = collection.InsertOne(context.TODO(), mongoRecord)
+	if err == nil {
+		return record, true, nil
+	}
+	if !mongo.IsDuplicateKeyError(err) {
+		errMsg := "unexpected error when acquire the idempotency key"
+		logger.AppLog.Error().Err(err).Msg(errMsg)
+		return domain.IdempotencyRecord{}, false, errors.New(errMsg)
+	}
+
+	// The TTL index removes the expired records periodically, so they can still be found.
+	// The lock of a request that never completed, for example because the instance stopped, expires too
+	now := record.CreatedDate
+	filter := bson.D{
+		{Key: "_id", Value: record.Key},
+		{Key: "$or", Value: bson.A{
+			bson.D{{Key: "expires_date", Value: bson.D{{Key: "$lte", Value: now}}}},
+			bson.D{
+				{Key: "status", Value: domain.IdempotencyStatusProcessing},
+				{Key: "fingerprint", Value: record.Fingerprint},
+				{Key: "locked_until", Value: bson.D{{Key: "$lte", Value: now}}},
+			},
+		}},
+	}
+	result, err := collection.ReplaceOne(context.TODO(), filter, mongoRecord)
+	if err != nil {
+		errMsg := "unexpected error when acquire the idempotency key"
+		logger.AppLog.Error().Err(err).Msg(errMsg)
+		return domain.IdempotencyRecord{}, false, errors.New(errMsg)
+	}
+	if result.MatchedCount == 1 {
+		return record, true, nil
+	}
+
+	current := MongoIdempotencyRecord{}
+	err = collection.FindOne(context.TODO(), bson.D{{Key: "_id", Value: record.Key}}).Decode(&current)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return domain.IdempotencyRecord{}, false, nil
+		}
+		errMsg := "unexpected error when find the idempotency record"
+		logger.AppLog.Error().Err(err).Msg(errMsg)
+		return domain.IdempotencyRecord{}, false, errors.New(errMsg)
+	}
+
+	found, err := r.mapper.MapRepositoryToDomain(current)
+	if err != nil {
+		errMsg := "unexpected error when find the idempotency record"
+		logger.AppLog.Error().Err(err).Msg(errMsg)
+		return domain.IdempotencyRecord{}, false, errors.New(errMsg)
+	}
+
+	return found, false, nil
+}
+
+// Complete fails when the record is not processed by the same request anymore
+func (r mongoIdempotencyRepository) Complete(record domain.IdempotencyRecord) error {
+	client := database.Mongo.Client
+	collection := client.Database(r.config.Database).Collection(r.config.IdempotencyCollection)
+
+	mongoRecord, err := r.mapper.MapDomainToRepository(record)
+	if err != nil {
+		errMsg := "unexpected error when complete the idempotency record"
+		logger.AppLog.Error().Err(err).Msg(errMsg)
+		return errors.New(errMsg)
+	}
+	filter := bson.D{
+		{Key: "_id", Value: record.Key},
+		{Key: "status", Value: domain.IdempotencyStatusProcessing},
+		{Key: "fingerprint", Value: record.Fingerprint},
+		{Key: "created_date", Value: record.CreatedDate},
+	}
+	update := bson.D{{Key: "$set", Value: bson.D{
+		{Key: "status", Value: mongoRecord.Status},
+		{Key: "response_status", Value: mongoRecord.ResponseStatus},
+		{Key: "response_headers", Value: mongoRecord.ResponseHeaders},
+		{Key: "response_body", Value: mongoRecord.ResponseBody},
+		{Key: "encrypted_response_body", Value: mongoRecord.EncryptedResponseBody},
+		{Key: "encryption", Value: mongoRecord.Encryption},
+		{Key: "resource_reference", Value: mongoRecord.ResourceReference},
+		{Key: "expires_date", Value: mongoRecord.ExpiresDate},
+	}}}
+	result, err := collection.UpdateOne(context.TODO(), filter, update)
+	if err != nil {
+		errMsg := "unexpected error when complete the idempotency record"
+		logger.AppLog.Error().Err(err).Msg(errMsg)
+		return errors.New(errMsg)
+	}
+	if result.MatchedCount != 1 {
+		return errors.New("idempotency record to complete was not found")
+	}
+
+	return nil
+}
+
+// Release only removes the record while it is processed by the same request
+func (r mongoIdempotencyRepository) Release(record domain.IdempotencyRecord) error {
+	client := database.Mongo.Client
+	collection := client.Database(r.config.Database).Collection(r.config.IdempotencyCollection)
+
+	filter := bson.D{
+		{Key: "_id", Value: record.Key},
+		{Key: "status", Value: domain.IdempotencyStatusProcessing},
+		{Key: "created_date", Value: record.CreatedDate},
+	}
+	_, err := collection.DeleteOne(context.TODO(), filter)
+	if err != nil {
+		errMsg := "unexpected error when release the idempotency key"
+		logger.AppLog.Error().Err(err).Msg(errMsg)
+		return errors.New(errMsg)
+	}
+
+	return nil
+}
+
+// DeleteByResource removes the records of every status, so the responses of an erased user are not replayed
+func (r mongoIdempotencyRepository) DeleteByResource(resourceReference string) (int64, error) {
+	client := database.Mongo.Client
+	collection := client.Database(r.config.Database).Collection(r.config.IdempotencyCollection)
+
+	result, err := collection.DeleteMany(context.TODO(), bson.D{{Key: "resource_reference", Value: resourceReference}})
+	if err != nil {
+		errMsg := "unexpected error when delete the idempotency records of the resource"
+		logger.AppLog.Error().Err(err).Msg(errMsg)
+		return 0, errors.New(errMsg)
+	}
+
+	return result.DeletedCount, nil
+}
+
+// idempotencyIndexes are the TTL index that removes the expired idempotency records, and the index of their resources
+var idempotencyIndexes = []mongo.IndexModel{
+	{
+		Keys:    bson.D{{Key: "expires_date", Value: 1}},
+		Options: options.Index().SetName("expires_date").SetExpireAfterSeconds(0),
+	},
+	{
+		Keys: bson.D{{Key: "resource_reference", Value: 1}},
+		Options: options.Index().SetName("resource_reference").
+			SetPartialFilterExpression(bson.D{{Key: "resource_reference", Value: bson.D{{Key: "$exists", Value: true}}}}),
+	},
+}
+
+// EnsureIdempotencyIndexes creates the idempotency records indexes. Running it again has no effect
+func EnsureIdempotencyIndexes(config domain.MongoRepositoryConfiguration) error {
+	client := database.Mongo.Client
+	collection := client.Database(config.Database).Collection(config.IdempotencyCollection)
+
+	_, err := collection.Indexes().CreateMany(context.TODO(), idempotencyIndexes)
+	if err != nil {
+		return fmt.Errorf("unable to create idempotency indexes: %w", err)
+	}
+
+	return nil
+}
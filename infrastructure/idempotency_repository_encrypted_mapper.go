@@ -0,0 +1,67 @@
+package infrastructure
+
+import (
+	"fmt"
+
+	"github.com/desarrollogj/golang-api-example/domain"
+	"github.com/desarrollogj/golang-api-example/libs/encryption"
+)
+
+// responseBodyField is the field name authenticated with the encrypted response bodies
+const responseBodyField = "response_body"
+
+// encryptedIdempotencyMongoRepositoryMapper is an IdempotencyMongoRepositoryMapper that encrypts the stored response
+// bodies, since they have the personal data of the users. Each record is encrypted with its own data key
+type encryptedIdempotencyMongoRepositoryMapper struct {
+	mapper  IdempotencyMongoRepositoryMapper
+	keyring *encryption.Keyring
+}
+
+// NewEncryptedIdempotencyMongoRepositoryMapper creates a new encryptedIdempotencyMongoRepositoryMapper decorating a plain mapper
+func NewEncryptedIdempotencyMongoRepositoryMapper(mapper IdempotencyMongoRepositoryMapper, keyring *encryption.Keyring) encryptedIdempotencyMongoRepositoryMapper {
+	return encryptedIdempotencyMongoRepositoryMapper{
+		mapper:  mapper,
+		keyring: keyring,
+	}
+}
+
+func (m encryptedIdempotencyMongoRepositoryMapper) MapDomainToRepository(record domain.IdempotencyRecord) (MongoIdempotencyRecord, error) {
+	plain, err := m.mapper.MapDomainToRepository(record)
+	if err != nil || len(plain.ResponseBody) == 0 {
+		return plain, err
+	}
+
+	dataKey, err := m.keyring.GenerateDataKey()
+	if err != nil {
+		return MongoIdempotencyRecord{}, fmt.Errorf("unable to encrypt idempotency record: %w", err)
+	}
+	encrypted, err := dataKey.Encrypt(responseBodyField, string(plain.ResponseBody))
+	if err != nil {
+		return MongoIdempotencyRecord{}, fmt.Errorf("unable to encrypt idempotency record: %w", err)
+	}
+	plain.ResponseBody = nil
+	plain.EncryptedResponseBody = encrypted
+	plain.Encryption = &MongoEncryption{KeyID: dataKey.KeyID, DataKey: dataKey.Wrapped}
+
+	return plain, nil
+}
+
+// MapRepositoryToDomain fails when the response body can not be decrypted, so a wrong response is never replayed
+func (m encryptedIdempotencyMongoRepositoryMapper) MapRepositoryToDomain(record MongoIdempotencyRecord) (domain.IdempotencyRecord, error) {
+	// Records stored before the encryption was enabled are in clear text
+	if record.Encryption != nil {
+		dataKey, err := m.keyring.UnwrapDataKey(record.Encryption.KeyID, record.Encryption.DataKey)
+		if err != nil {
+			return domain.IdempotencyRecord{}, fmt.Errorf("unable to decrypt idempotency record: %w", err)
+		}
+		body, err := dataKey.Decrypt(responseBodyField, record.EncryptedResponseBody)
+		if err != nil {
+			return domain.IdempotencyRecord{}, fmt.Errorf("unable to decrypt idempotency record: %w", err)
+		}
+		record.ResponseBody = []byte(body)
+		record.EncryptedResponseBody = ""
+		record.Encryption = nil
+	}
+
+	return m.mapper.MapRepositoryToDomain(record)
+}
@@ -0,0 +1,48 @@
+package infrastructure
+
+import "github.com/desarrollogj/golang-api-example/domain"
+
+// IdempotencyMongoRepositoryMapper represents the methods to be implemented by mongo idempotency records mapper
+type IdempotencyMongoRepositoryMapper interface {
+	MapDomainToRepository(record domain.IdempotencyRecord) (MongoIdempotencyRecord, error)
+	MapRepositoryToDomain(record MongoIdempotencyRecord) (domain.IdempotencyRecord, error)
+}
+
+// defaultIdempotencyMongoRepositoryMapper is the default implementation of IdempotencyMongoRepositoryMapper
+type defaultIdempotencyMongoRepositoryMapper struct {
+}
+
+// NewDefaultIdempotencyMongoRepositoryMapper creates a new defaultIdempotencyMongoRepositoryMapper
+func NewDefaultIdempotencyMongoRepositoryMapper() defaultIdempotencyMongoRepositoryMapper {
+	return defaultIdempotencyMongoRepositoryMapper{}
+}
+
+func (m defaultIdempotencyMongoRepositoryMapper) MapDomainToRepository(record domain.IdempotencyRecord) (MongoIdempotencyRecord, error) {
+	return MongoIdempotencyRecord{
+		Key:               record.Key,
+		Fingerprint:       record.Fingerprint,
+		Status:            record.Status,
+		ResponseStatus:    record.ResponseStatus,
+		ResponseHeaders:   record.ResponseHeaders,
+		ResponseBody:      record.ResponseBody,
+		ResourceReference: record.ResourceReference,
+		LockedUntil:       record.LockedUntil,
+		CreatedDate:       record.CreatedDate,
+		ExpiresDate:       record.ExpiresDate,
+	}, nil
+}
+
+func (m defaultIdempotencyMongoRepositoryMapper) MapRepositoryToDomain(record MongoIdempotencyRecord) (domain.IdempotencyRecord, error) {
+	return domain.IdempotencyRecord{
+		Key:               record.Key,
+		Fingerprint:       record.Fingerprint,
+		Status:            record.Status,
+		ResponseStatus:    record.ResponseStatus,
+		ResponseHeaders:   record.ResponseHeaders,
+		ResponseBody:      record.ResponseBody,
+		ResourceReference: record.ResourceReference,
+		LockedUntil:       record.LockedUntil,
+		CreatedDate:       record.CreatedDate,
+		ExpiresDate:       record.ExpiresDate,
+	}, nil
+}
@@ -0,0 +1,89 @@
+package infrastructure
+
+import (
+	"testing"
+	"time"
+
+	"github.com/desarrollogj/golang-api-example/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIdempotencyMongoRepositoryMapper_GivenDomainData_WhenMap_ThenMapToRepositoryDataAndBack(t *testing.T) {
+	t.Log("Should map idempotency record domain data to idempotency record repository data and back")
+
+	now := time.Now().UTC()
+	domainRecord := domain.IdempotencyRecord{
+		Key:               "KEY1",
+		Fingerprint:       "fingerprint",
+		Status:            domain.IdempotencyStatusCompleted,
+		ResponseStatus:    201,
+		ResponseHeaders:   map[string][]string{"Content-Type": {"application/json"}},
+		ResponseBody:      []byte(`{"id":"USER1"}`),
+		ResourceReference: "USER1",
+		LockedUntil:       now,
+		CreatedDate:       now,
+		ExpiresDate:       now.Add(24 * time.Hour),
+	}
+	expectedRepoRecord := MongoIdempotencyRecord{
+		Key:               "KEY1",
+		Fingerprint:       "fingerprint",
+		Status:            domain.IdempotencyStatusCompleted,
+		ResponseStatus:    201,
+		ResponseHeaders:   map[string][]string{"Content-Type": {"application/json"}},
+		ResponseBody:      []byte(`{"id":"USER1"}`),
+		ResourceReference: "USER1",
+		LockedUntil:       now,
+		CreatedDate:       now,
+		ExpiresDate:       now.Add(24 * time.Hour),
+	}
+
+	mapper := NewDefaultIdempotencyMongoRepositoryMapper()
+	repoRecord, err := mapper.MapDomainToRepository(domainRecord)
+
+	assert.Nil(t, err)
+	assert.Equal(t, expectedRepoRecord, repoRecord)
+	mapped, err := mapper.MapRepositoryToDomain(repoRecord)
+	assert.Nil(t, err)
+	assert.Equal(t, domainRecord, mapped)
+}
+
+func TestEncryptedIdempotencyMongoRepositoryMapper_GivenDomainData_WhenMap_ThenEncryptTheResponseBodyAndBack(t *testing.T) {
+	t.Log("Should encrypt the stored response body, which has the user personal data, and decrypt it back")
+
+	now := time.Now().UTC()
+	domainRecord := domain.IdempotencyRecord{
+		Key:               "KEY1",
+		Status:            domain.IdempotencyStatusCompleted,
+		ResponseStatus:    201,
+		ResponseBody:      []byte(`{"id":"USER1","email":"foobar@test.com"}`),
+		ResourceReference: "USER1",
+		CreatedDate:       now,
+	}
+
+	mapper := NewEncryptedIdempotencyMongoRepositoryMapper(NewDefaultIdempotencyMongoRepositoryMapper(), newKeyringMock(t, "KEY1"))
+	repoRecord, err := mapper.MapDomainToRepository(domainRecord)
+
+	assert.Nil(t, err)
+	assert.Nil(t, repoRecord.ResponseBody)
+	assert.NotEmpty(t, repoRecord.EncryptedResponseBody)
+	assert.NotContains(t, repoRecord.EncryptedResponseBody, "foobar")
+	assert.Equal(t, "KEY1", repoRecord.Encryption.KeyID)
+	assert.Equal(t, "USER1", repoRecord.ResourceReference)
+
+	mapped, err := mapper.MapRepositoryToDomain(repoRecord)
+	assert.Nil(t, err)
+	assert.Equal(t, domainRecord, mapped)
+}
+
+func TestEncryptedIdempotencyMongoRepositoryMapper_GivenTamperedRepositoryData_WhenMap_ThenReturnAnError(t *testing.T) {
+	t.Log("Should not replay a response body that can not be decrypted")
+
+	mapper := NewEncryptedIdempotencyMongoRepositoryMapper(NewDefaultIdempotencyMongoRepositoryMapper(), newKeyringMock(t, "KEY1"))
+	repoRecord, err := mapper.MapDomainToRepository(domain.IdempotencyRecord{Key: "KEY1", ResponseBody: []byte(`{"id":"USER1"}`)})
+	assert.Nil(t, err)
+	repoRecord.EncryptedResponseBody = repoRecord.EncryptedResponseBody[1:]
+
+	_, err = mapper.MapRepositoryToDomain(repoRecord)
+
+	assert.NotNil(t, err)
+}
@@ -3,18 +3,141 @@ package infrastructure
 import (
 	"time"
 
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 // Repository entities
 
 type MongoUser struct {
+	ID                        primitive.ObjectID `bson:"_id"`
+	Reference                 string             `bson:"reference"`
+	FirstName                 string             `bson:"first_name"`
+	LastName                  string             `bson:"last_name"`
+	Email                     string             `bson:"email"`
+	EmailIndex                string             `bson:"email_index,omitempty"`
+	EmailVerifiedDate         *time.Time         `bson:"email_verified_date,omitempty"`
+	EmailVerificationSentDate *time.Time         `bson:"email_verification_sent_date,omitempty"`
+	Roles                     []string           `bson:"roles,omitempty"`
+	Attributes                bson.M             `bson:"attributes,omitempty"`
+	Tags                      []string           `bson:"tags,omitempty"`
+	ExternalIDs               []MongoExternalID  `bson:"external_ids,omitempty"`
+	Avatar                    *MongoUserAvatar   `bson:"avatar,omitempty"`
+	Phone                     string             `bson:"phone,omitempty"`
+	BirthDate                 *time.Time         `bson:"birth_date,omitempty"`
+	EncryptedBirthDate        string             `bson:"encrypted_birth_date,omitempty"`
+	Locale                    string             `bson:"locale,omitempty"`
+	Timezone                  string             `bson:"timezone,omitempty"`
+	Address                   *MongoAddress      `bson:"address,omitempty"`
+	IsActive                  bool               `bson:"is_active"`
+	Status                    string             `bson:"status"`
+	StatusReason              string             `bson:"status_reason,omitempty"`
+	StatusDate                time.Time          `bson:"status_date"`
+	CreatedDate               time.Time          `bson:"created_date"`
+	UpdatedDate               time.Time          `bson:"updated_date"`
+	ErasedDate                *time.Time         `bson:"erased_date,omitempty"`
+	MergedInto                string             `bson:"merged_into,omitempty"`
+	Encryption                *MongoEncryption   `bson:"encryption,omitempty"`
+}
+
+// MongoTagCount is the result of the tags count aggregation
+type MongoTagCount struct {
+	Tag   string `bson:"_id"`
+	Count int64  `bson:"count"`
+}
+
+type MongoExternalID struct {
+	Source string `bson:"source"`
+	ID     string `bson:"id"`
+}
+
+type MongoAddress struct {
+	Line1      string `bson:"line1"`
+	Line2      string `bson:"line2,omitempty"`
+	City       string `bson:"city"`
+	Region     string `bson:"region,omitempty"`
+	PostalCode string `bson:"postal_code,omitempty"`
+	Country    string `bson:"country"`
+}
+
+type MongoUserAvatar struct {
+	Version     string    `bson:"version"`
+	ContentType string    `bson:"content_type"`
+	Sizes       []int     `bson:"sizes"`
+	UpdatedDate time.Time `bson:"updated_date"`
+}
+
+// MongoEncryption holds the wrapped data key used to encrypt the document fields and the id of the key that wrapped it
+type MongoEncryption struct {
+	KeyID   string `bson:"key_id"`
+	DataKey []byte `bson:"data_key"`
+}
+
+type MongoAuditEntry struct {
+	ID              primitive.ObjectID `bson:"_id"`
+	Reference       string             `bson:"reference"`
+	Action          string             `bson:"action"`
+	EntityType      string             `bson:"entity_type"`
+	EntityReference string             `bson:"entity_reference"`
+	Details         map[string]string  `bson:"details,omitempty"`
+	CreatedDate     time.Time          `bson:"created_date"`
+}
+
+// MongoCredential is stored in its own collection, so the password hashes are never loaded with the users
+type MongoCredential struct {
+	ID            primitive.ObjectID `bson:"_id"`
+	UserReference string             `bson:"user_reference"`
+	PasswordHash  string             `bson:"password_hash"`
+	CreatedDate   time.Time          `bson:"created_date"`
+	UpdatedDate   time.Time          `bson:"updated_date"`
+}
+
+// MongoAPIKey is stored in its own collection. The prefix identifies the key, and only the secret hash is stored
+type MongoAPIKey struct {
+	ID           primitive.ObjectID `bson:"_id"`
+	Reference    string             `bson:"reference"`
+	Name         string             `bson:"name"`
+	Prefix       string             `bson:"prefix"`
+	SecretHash   string             `bson:"secret_hash"`
+	Scopes       []string           `bson:"scopes"`
+	ExpiresDate  *time.Time         `bson:"expires_date,omitempty"`
+	LastUsedDate *time.Time         `bson:"last_used_date,omitempty"`
+	RevokedDate  *time.Time         `bson:"revoked_date,omitempty"`
+	CreatedDate  time.Time          `bson:"created_date"`
+	UpdatedDate  time.Time          `bson:"updated_date"`
+}
+
+// MongoIdempotencyRecord is stored in its own collection, with the scoped idempotency key as id.
+// The expired records are removed by a TTL index
+type MongoIdempotencyRecord struct {
+	Key             string              `bson:"_id"`
+	Fingerprint     string              `bson:"fingerprint"`
+	Status          string              `bson:"status"`
+	ResponseStatus  int                 `bson:"response_status,omitempty"`
+	ResponseHeaders map[string][]string `bson:"response_headers,omitempty"`
+	ResponseBody    []byte              `bson:"response_body,omitempty"`
+	// EncryptedResponseBody replaces the response body when the personal data is encrypted
+	EncryptedResponseBody string           `bson:"encrypted_response_body,omitempty"`
+	Encryption            *MongoEncryption `bson:"encryption,omitempty"`
+	ResourceReference     string           `bson:"resource_reference,omitempty"`
+	LockedUntil           time.Time        `bson:"locked_until"`
+	CreatedDate           time.Time        `bson:"created_date"`
+	ExpiresDate           time.Time        `bson:"expires_date"`
+}
+
+// MongoGroup is stored in its own collection, with its members embedded
+type MongoGroup struct {
 	ID          primitive.ObjectID `bson:"_id"`
 	Reference   string             `bson:"reference"`
-	FirstName   string             `bson:"first_name"`
-	LastName    string             `bson:"last_name"`
-	Email       string             `bson:"email"`
+	Name        string             `bson:"name"`
+	Description string             `bson:"description,omitempty"`
+	Members     []MongoGroupMember `bson:"members"`
 	IsActive    bool               `bson:"is_active"`
 	CreatedDate time.Time          `bson:"created_date"`
 	UpdatedDate time.Time          `bson:"updated_date"`
 }
+
+type MongoGroupMember struct {
+	UserReference string    `bson:"user_reference"`
+	AddedDate     time.Time `bson:"added_date"`
+}
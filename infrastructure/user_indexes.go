@@ -0,0 +1,58 @@
+package infrastructure
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/desarrollogj/golang-api-example/domain"
+	"github.com/desarrollogj/golang-api-example/libs/database"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	// externalIDsIndexName is the name of the external ids unique index
+	externalIDsIndexName = "external_ids"
+	// emailIndexName is the name of the email blind index unique index
+	emailIndexName = "email_index"
+)
+
+// userIndexes are the indexes used by the users search and lookups
+var userIndexes = []mongo.IndexModel{
+	// tags is an array, so it is a multikey index on each tag
+	{Keys: bson.D{{Key: "tags", Value: 1}}, Options: options.Index().SetName("tags")},
+	// Each external id pair belongs to a single user. The whole {source, id} document is indexed, so the pairs
+	// are matched as written by the mapper. The users without external ids are not indexed
+	{
+		Keys: bson.D{{Key: "external_ids", Value: 1}},
+		Options: options.Index().SetName(externalIDsIndexName).SetUnique(true).
+			SetPartialFilterExpression(bson.D{{Key: "external_ids", Value: bson.D{{Key: "$exists", Value: true}}}}),
+	},
+	// Each email belongs to a single active user. Only the encrypted documents have the email blind index, and the
+	// inactive users do not keep their email taken
+	{
+		Keys: bson.D{{Key: "email_index", Value: 1}},
+		Options: options.Index().SetName(emailIndexName).SetUnique(true).
+			SetPartialFilterExpression(bson.D{
+				{Key: "email_index", Value: bson.D{{Key: "$exists", Value: true}}},
+				{Key: "is_active", Value: true},
+			}),
+	},
+}
+
+// EnsureUserIndexes creates the users collection indexes. Existing indexes are kept, so running it again has no effect.
+// Each index is created on its own, so an unique index rejected by the stored data does not prevent the others
+func EnsureUserIndexes(config domain.MongoRepositoryConfiguration) error {
+	client := database.Mongo.Client
+	collection := client.Database(config.Database).Collection(config.UsersCollection)
+
+	var failed error
+	for _, index := range userIndexes {
+		if _, err := collection.Indexes().CreateOne(context.TODO(), index); err != nil && failed == nil {
+			failed = fmt.Errorf("unable to create users index %s: %w", *index.Options.Name, err)
+		}
+	}
+
+	return failed
+}
@@ -0,0 +1,145 @@
+package infrastructure
+
+import (
+	"context"
+	"time"
+
+	"github.com/desarrollogj/golang-api-example/domain"
+	"github.com/desarrollogj/golang-api-example/libs/database"
+	"github.com/desarrollogj/golang-api-example/libs/logger"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// UserReencryptionJob re-encrypts in background the users that are stored in clear text
+// or encrypted with a key different from the keyring active key (for example, after a key rotation)
+type UserReencryptionJob struct {
+	config     domain.MongoRepositoryConfiguration
+	encryption domain.EncryptionConfiguration
+	mapper     encryptedMongoRepositoryMapper
+}
+
+// NewUserReencryptionJob creates a new UserReencryptionJob
+func NewUserReencryptionJob(config domain.MongoRepositoryConfiguration, encryption domain.EncryptionConfiguration, mapper encryptedMongoRepositoryMapper) UserReencryptionJob {
+	return UserReencryptionJob{
+		config:     config,
+		encryption: encryption,
+		mapper:     mapper,
+	}
+}
+
+// Run re-encrypts outdated users in batches until the context is done. The job does nothing if the interval or the batch
+// size are not positive
+func (j UserReencryptionJob) Run(ctx context.Context) {
+	if j.encryption.ReencryptionIntervalSeconds <= 0 || j.encryption.ReencryptionBatchSize <= 0 {
+		logger.AppLog.Warn().Msg("users re-encryption is disabled, the interval and the batch size must be positive")
+		return
+	}
+	interval := time.Duration(j.encryption.ReencryptionIntervalSeconds) * time.Second
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		// The users are read in id order, after the last one of the previous batch, so the users that can not be
+		// decrypted are skipped instead of being selected again on every batch. They are retried on the next run
+		after := primitive.NilObjectID
+		for {
+			found, last, err := j.reencryptBatch(ctx, after)
+			if err != nil {
+				logger.AppLog.Error().Err(err).Msg("unexpected error when re-encrypt users")
+				break
+			}
+			if found < j.encryption.ReencryptionBatchSize || ctx.Err() != nil {
+				break
+			}
+			after = last
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// reencryptBatch re-encrypts the outdated users with an id greater than after. It returns how many users were found
+// and the id of the last one
+func (j UserReencryptionJob) reencryptBatch(ctx context.Context, after primitive.ObjectID) (int, primitive.ObjectID, error) {
+	client := database.Mongo.Client
+	collection := client.Database(j.config.Database).Collection(j.config.UsersCollection)
+
+	filter := bson.D{
+		{Key: "_id", Value: bson.D{{Key: "$gt", Value: after}}},
+		{Key: "$or", Value: bson.A{
+			bson.D{{Key: "encryption", Value: bson.D{{Key: "$exists", Value: false}}}},
+			bson.D{{Key: "encryption.key_id", Value: bson.D{{Key: "$ne", Value: j.mapper.keyring.ActiveKeyID()}}}},
+		}},
+	}
+	limit := int64(j.encryption.ReencryptionBatchSize)
+	cur, err := collection.Find(ctx, filter, &options.FindOptions{Limit: &limit, Sort: bson.D{{Key: "_id", Value: 1}}})
+	if err != nil {
+		return 0, after, err
+	}
+
+	users := []MongoUser{}
+	if err = cur.All(ctx, &users); err != nil {
+		return 0, after, err
+	}
+	last := after
+	if len(users) > 0 {
+		last = users[len(users)-1].ID
+	}
+
+	processed := 0
+	failed := 0
+	duplicated := 0
+	for _, user := range users {
+		if ctx.Err() != nil {
+			return len(users), last, nil
+		}
+
+		decrypted, err := j.mapper.decrypt(user)
+		if err != nil {
+			// Leave the document as it is, otherwise its data would be lost
+			logger.AppLog.Error().Err(err).Str("reference", user.Reference).Msg("unable to decrypt user to re-encrypt")
+			failed++
+			continue
+		}
+
+		encrypted, err := j.mapper.encrypt(decrypted)
+		if err != nil {
+			return len(users), last, err
+		}
+
+		// The user could be updated meanwhile, in that case it is already encrypted with the active key
+		_, err = collection.ReplaceOne(ctx,
+			bson.D{{Key: "_id", Value: user.ID}, {Key: "updated_date", Value: user.UpdatedDate}},
+			encrypted)
+		if err != nil {
+			// The email unique index rejects the active users with the email of another active user, they are kept
+			// as they are until the duplicates are merged
+			if mongo.IsDuplicateKeyError(err) {
+				logger.AppLog.Warn().Err(err).Str("reference", user.Reference).Msg("unable to re-encrypt user, its email belongs to another active user")
+				duplicated++
+				continue
+			}
+			return len(users), last, err
+		}
+		processed++
+	}
+
+	if processed > 0 {
+		logger.AppLog.Info().Int("count", processed).Msg("users re-encrypted")
+	}
+	if failed > 0 {
+		logger.AppLog.Warn().Int("count", failed).Msg("users skipped because they can not be decrypted")
+	}
+	if duplicated > 0 {
+		logger.AppLog.Warn().Int("count", duplicated).Msg("users skipped because their email belongs to another active user")
+	}
+
+	return len(users), last, nil
+}
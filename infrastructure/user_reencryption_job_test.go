@@ -0,0 +1,94 @@
+package infrastructure
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/desarrollogj/golang-api-example/domain"
+	"github.com/desarrollogj/golang-api-example/libs/database"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+func TestUserReencryptionJob_GivenNoInterval_WhenRun_ThenReturnWithoutProcessing(t *testing.T) {
+	t.Log("Re-encryption job should be disabled when the interval is not positive, instead of panicking")
+
+	mapper := NewEncryptedMongoRepositoryMapper(NewDefaultMongoRepositoryMapper(), newKeyringMock(t, "KEY1"))
+	job := NewUserReencryptionJob(domain.MongoRepositoryConfiguration{}, domain.EncryptionConfiguration{
+		ReencryptionIntervalSeconds: 0,
+		ReencryptionBatchSize:       100,
+	}, mapper)
+
+	done := make(chan struct{})
+	go func() {
+		job.Run(context.Background())
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		assert.Fail(t, "the job should return when the interval is not positive")
+	}
+}
+
+// TestUserReencryptionJob_GivenActiveUsersWithTheSameEmail_WhenReencryptBatch_ThenSkipTheDuplicates runs against a
+// real MongoDB, because the duplicates are rejected by the email unique index. It is skipped unless MONGO_TEST_URI is set
+func TestUserReencryptionJob_GivenActiveUsersWithTheSameEmail_WhenReencryptBatch_ThenSkipTheDuplicates(t *testing.T) {
+	t.Log("Re-encryption job should skip the users rejected by the email unique index and re-encrypt the next ones")
+
+	uri := os.Getenv("MONGO_TEST_URI")
+	if len(uri) == 0 {
+		t.Skip("MONGO_TEST_URI is not set")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri))
+	if err != nil {
+		t.Fatal(err)
+	}
+	previous := database.Mongo
+	database.Mongo = &database.MongoDB{Client: client}
+	config := domain.MongoRepositoryConfiguration{Database: "example_reencryption_test", UsersCollection: "users"}
+	t.Cleanup(func() {
+		client.Database(config.Database).Drop(context.Background())
+		client.Disconnect(context.Background())
+		database.Mongo = previous
+	})
+	collection := client.Database(config.Database).Collection(config.UsersCollection)
+	collection.Drop(ctx)
+	if err := EnsureUserIndexes(config); err != nil {
+		t.Fatal(err)
+	}
+
+	plain := NewDefaultMongoRepositoryMapper()
+	for _, user := range []domain.User{
+		{GenericEntity: domain.GenericEntity{Reference: "USER1", IsActive: true}, Email: "foo@email.com"},
+		{GenericEntity: domain.GenericEntity{Reference: "USER2", IsActive: true}, Email: "foo@email.com"},
+		{GenericEntity: domain.GenericEntity{Reference: "USER3", IsActive: true}, Email: "bar@email.com"},
+	} {
+		document, _ := plain.MapDomainToRepository(user)
+		document.ID = primitive.NewObjectID()
+		if _, err := collection.InsertOne(ctx, document); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	mapper := NewEncryptedMongoRepositoryMapper(plain, newKeyringMock(t, "KEY1"))
+	job := NewUserReencryptionJob(config, domain.EncryptionConfiguration{ReencryptionIntervalSeconds: 60, ReencryptionBatchSize: 10}, mapper)
+
+	found, _, err := job.reencryptBatch(ctx, primitive.NilObjectID)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 3, found)
+	for reference, encrypted := range map[string]bool{"USER1": true, "USER2": false, "USER3": true} {
+		document := MongoUser{}
+		collection.FindOne(ctx, bson.D{{Key: "reference", Value: reference}}).Decode(&document)
+		assert.Equal(t, encrypted, document.Encryption != nil, reference)
+	}
+}
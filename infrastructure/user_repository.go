@@ -4,6 +4,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"regexp"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/desarrollogj/golang-api-example/domain"
@@ -15,15 +18,36 @@ import (
 	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
+var (
+	// ErrDuplicateExternalID is returned when an user is stored with an external id that belongs to another user
+	ErrDuplicateExternalID = errors.New("external id belongs to another user")
+	// ErrDuplicateEmail is returned when an active user is stored with the email of another active user. The emails
+	// are only unique when the personal data is encrypted, by their blind index
+	ErrDuplicateEmail = errors.New("email belongs to another user")
+	// ErrUserModified is returned when an user is patched, or its status changed, and it was modified after it was read
+	ErrUserModified = errors.New("user was modified while it was updated")
+	// ErrUndecryptableUser is returned when an user whose personal data could not be decrypted is stored.
+	// Its personal data was read blank, so storing it would replace the encrypted data
+	ErrUndecryptableUser = errors.New("user personal data could not be decrypted")
+	// ErrEncryptedNameSearch is returned when the users are searched by name and the names are stored encrypted
+	ErrEncryptedNameSearch = errors.New("users can not be searched by name when the personal data is encrypted")
+)
+
 // UserRepository represents the methods to be implemented by users repositories
 type UserRepository interface {
 	FindAllActive() ([]domain.User, error)
 	FindActiveByReference(reference string) (domain.User, error)
 	FindByReference(reference string) (domain.User, error)
-	SearchActive(input domain.UserSearchInput) (domain.UserSearchOutput, error)
+	FindAllActiveByEmail(email string) ([]domain.User, error)
+	FindByExternalID(source string, id string) (domain.User, error)
+	Search(input domain.UserSearchInput) (domain.UserSearchOutput, error)
+	CountTags() ([]domain.TagCount, error)
 	Create(user domain.User) (domain.User, error)
 	Update(user domain.User) (domain.User, error)
-	Delete(reference string) (domain.User, error)
+	Patch(user domain.User) (domain.User, error)
+	AddTag(reference string, tag string, maxTags int, updatedDate time.Time) (bool, error)
+	RemoveTag(reference string, tag string, updatedDate time.Time) (bool, error)
+	ChangeStatus(user domain.User, from domain.UserStatus) (domain.User, error)
 }
 
 // mongoUserRepository is the MongoDB implementation of UserRepository
@@ -102,45 +126,129 @@ func (r mongoUserRepository) findByReference(reference string, onlyActives bool)
 	return user, nil
 }
 
-func (r mongoUserRepository) SearchActive(input domain.UserSearchInput) (domain.UserSearchOutput, error) {
+// FindAllActiveByEmail finds the active users with the complete email, ignoring the case. Without the personal data
+// encryption the emails are not unique, so several users can be returned
+func (r mongoUserRepository) FindAllActiveByEmail(email string) ([]domain.User, error) {
+	client := database.Mongo.Client
+	collection := client.Database(r.config.Database).Collection(r.config.UsersCollection)
+
+	filter := bson.D{{Key: "is_active", Value: true}}
+	// Encrypted emails can only be found by their blind index
+	if index := r.mapper.MapEmailToIndex(email); len(index) > 0 {
+		filter = append(filter, bson.E{Key: "email_index", Value: index})
+	} else {
+		pattern := fmt.Sprintf("^%s$", regexp.QuoteMeta(email))
+		filter = append(filter, bson.E{Key: "email", Value: primitive.Regex{Pattern: pattern, Options: "i"}})
+	}
+
+	users := []MongoUser{}
+	cur, err := collection.Find(context.TODO(), filter, options.Find().SetSort(bson.D{{Key: "reference", Value: 1}}))
+	if err == nil {
+		err = cur.All(context.TODO(), &users)
+	}
+	if err != nil {
+		errMsg := "unexpected error when find users by their email"
+		logger.AppLog.Error().Err(err).Msg(errMsg)
+		return []domain.User{}, errors.New(errMsg)
+	}
+
+	return r.mapper.MapRepositoryListToDomainList(users), nil
+}
+
+// FindByExternalID finds an user, active or not, by one of its external ids
+func (r mongoUserRepository) FindByExternalID(source string, id string) (domain.User, error) {
 	client := database.Mongo.Client
 	collection := client.Database(r.config.Database).Collection(r.config.UsersCollection)
 
-	filters := bson.D{{Key: "is_active", Value: true}}
+	user := MongoUser{}
+	filter := bson.D{{Key: "external_ids", Value: MongoExternalID{Source: source, ID: id}}}
+	err := collection.FindOne(context.TODO(), filter).Decode(&user)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return domain.User{}, nil
+		}
+		errMsg := "unexpected error when find user by its external id"
+		logger.AppLog.Error().Err(err).Msg(errMsg)
+		return domain.User{}, errors.New(errMsg)
+	}
+
+	return r.mapper.MapRepositoryToDomain(user), nil
+}
+
+// Search returns a page of the active users matching the input filters. The inactive users are only included if it is requested.
+// The encrypted names can not be matched by prefix, so the name filters are rejected when the personal data is encrypted
+func (r mongoUserRepository) Search(input domain.UserSearchInput) (domain.UserSearchOutput, error) {
+	if r.mapper.IsEncrypted() && (len(input.FirstName) > 0 || len(input.LastName) > 0) {
+		return domain.UserSearchOutput{}, ErrEncryptedNameSearch
+	}
+
+	client := database.Mongo.Client
+	collection := client.Database(r.config.Database).Collection(r.config.UsersCollection)
+
+	filters := bson.D{}
+	if !input.IncludeInactive {
+		filters = append(filters, bson.E{Key: "is_active", Value: true})
+	}
 	if len(input.FirstName) > 0 {
-		filter := fmt.Sprintf("^%s", input.FirstName)
+		filter := fmt.Sprintf("^%s", regexp.QuoteMeta(input.FirstName))
 		filters = append(filters, bson.E{Key: "first_name", Value: primitive.Regex{Pattern: filter, Options: "i"}})
 	}
 	if len(input.LastName) > 0 {
-		filter := fmt.Sprintf("^%s", input.LastName)
+		filter := fmt.Sprintf("^%s", regexp.QuoteMeta(input.LastName))
 		filters = append(filters, bson.E{Key: "last_name", Value: primitive.Regex{Pattern: filter, Options: "i"}})
 	}
 	if len(input.Email) > 0 {
-		filter := fmt.Sprintf("^%s", input.LastName)
-		filters = append(filters, bson.E{Key: "email", Value: primitive.Regex{Pattern: filter, Options: "i"}})
+		// Encrypted emails can only be found by their blind index, so they must match completely
+		if index := r.mapper.MapEmailToIndex(input.Email); len(index) > 0 {
+			filters = append(filters, bson.E{Key: "email_index", Value: index})
+		} else {
+			filter := fmt.Sprintf("^%s", regexp.QuoteMeta(input.Email))
+			filters = append(filters, bson.E{Key: "email", Value: primitive.Regex{Pattern: filter, Options: "i"}})
+		}
+	}
+	if len(input.Country) > 0 {
+		filters = append(filters, bson.E{Key: "address.country", Value: input.Country})
+	}
+	if len(input.Locale) > 0 {
+		filters = append(filters, bson.E{Key: "locale", Value: input.Locale})
+	}
+	names := []string{}
+	for name := range input.Attributes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		filters = append(filters, bson.E{Key: "attributes." + name, Value: input.Attributes[name]})
+	}
+	if len(input.Tags) > 0 {
+		filters = append(filters, bson.E{Key: "tags", Value: bson.D{{Key: "$all", Value: input.Tags}}})
+	}
+	if len(input.AnyTags) > 0 {
+		filters = append(filters, bson.E{Key: "tags", Value: bson.D{{Key: "$in", Value: input.AnyTags}}})
 	}
 	limit := int64(input.PageSize)
 	skip := int64((input.Page * input.PageSize) - input.PageSize)
-	paging := options.FindOptions{Limit: &limit, Skip: &skip}
+	// Sorted by insertion, so the pages are stable
+	paging := options.FindOptions{Limit: &limit, Skip: &skip, Sort: bson.D{{Key: "_id", Value: 1}}}
 
 	users := []MongoUser{}
 	cur, err := collection.Find(context.TODO(), filters, &paging)
 	if err != nil {
-		errMsg := "unexpected error when search active users"
+		errMsg := "unexpected error when search users"
 		logger.AppLog.Error().Err(err).Msg(errMsg)
 		return domain.UserSearchOutput{}, errors.New(errMsg)
 	}
 
 	err = cur.All(context.TODO(), &users)
 	if err != nil {
-		errMsg := "unexpected error when search active users"
+		errMsg := "unexpected error when search users"
 		logger.AppLog.Error().Err(err).Msg(errMsg)
 		return domain.UserSearchOutput{}, errors.New(errMsg)
 	}
 
 	total, err := collection.CountDocuments(context.TODO(), filters)
 	if err != nil {
-		errMsg := "unexpected error when search active users"
+		errMsg := "unexpected error when search users"
 		logger.AppLog.Error().Err(err).Msg(errMsg)
 		return domain.UserSearchOutput{}, errors.New(errMsg)
 	}
@@ -148,14 +256,59 @@ func (r mongoUserRepository) SearchActive(input domain.UserSearchInput) (domain.
 	return r.mapper.MapRepositorySearchActiveToOutput(users, total, input.Page, input.PageSize), nil
 }
 
+// CountTags returns the number of active users with each tag, most used first
+func (r mongoUserRepository) CountTags() ([]domain.TagCount, error) {
+	client := database.Mongo.Client
+	collection := client.Database(r.config.Database).Collection(r.config.UsersCollection)
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: bson.D{{Key: "is_active", Value: true}}}},
+		{{Key: "$unwind", Value: "$tags"}},
+		{{Key: "$group", Value: bson.D{{Key: "_id", Value: "$tags"}, {Key: "count", Value: bson.D{{Key: "$sum", Value: 1}}}}}},
+		{{Key: "$sort", Value: bson.D{{Key: "count", Value: -1}, {Key: "_id", Value: 1}}}},
+	}
+	cur, err := collection.Aggregate(context.TODO(), pipeline)
+	if err != nil {
+		errMsg := "unexpected error when count user tags"
+		logger.AppLog.Error().Err(err).Msg(errMsg)
+		return []domain.TagCount{}, errors.New(errMsg)
+	}
+
+	counts := []MongoTagCount{}
+	err = cur.All(context.TODO(), &counts)
+	if err != nil {
+		errMsg := "unexpected error when count user tags"
+		logger.AppLog.Error().Err(err).Msg(errMsg)
+		return []domain.TagCount{}, errors.New(errMsg)
+	}
+
+	tags := []domain.TagCount{}
+	for _, count := range counts {
+		tags = append(tags, domain.TagCount{Tag: count.Tag, Count: count.Count})
+	}
+	return tags, nil
+}
+
 func (r mongoUserRepository) Create(user domain.User) (domain.User, error) {
+	if user.Undecryptable {
+		return domain.User{}, ErrUndecryptableUser
+	}
+
 	client := database.Mongo.Client
 	collection := client.Database(r.config.Database).Collection(r.config.UsersCollection)
 
-	mongoUser := r.mapper.MapDomainToRepository(user)
+	mongoUser, err := r.mapper.MapDomainToRepository(user)
+	if err != nil {
+		errMsg := "unexpected error when create the user"
+		logger.AppLog.Error().Err(err).Msg(errMsg)
+		return domain.User{}, errors.New(errMsg)
+	}
 	mongoUser.ID = primitive.NewObjectID()
-	_, err := collection.InsertOne(context.TODO(), mongoUser)
+	_, err = collection.InsertOne(context.TODO(), mongoUser)
 	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return domain.User{}, duplicateKeyError(err)
+		}
 		errMsg := "unexpected error when create the user"
 		logger.AppLog.Error().Err(err).Msg(errMsg)
 		return domain.User{}, errors.New(errMsg)
@@ -165,6 +318,10 @@ func (r mongoUserRepository) Create(user domain.User) (domain.User, error) {
 }
 
 func (r mongoUserRepository) Update(user domain.User) (domain.User, error) {
+	if user.Undecryptable {
+		return domain.User{}, ErrUndecryptableUser
+	}
+
 	client := database.Mongo.Client
 	collection := client.Database(r.config.Database).Collection(r.config.UsersCollection)
 
@@ -177,10 +334,18 @@ func (r mongoUserRepository) Update(user domain.User) (domain.User, error) {
 	}
 
 	// Update document
-	updatedUser := r.mapper.MapDomainToRepository(user)
+	updatedUser, err := r.mapper.MapDomainToRepository(user)
+	if err != nil {
+		errMsg := "unexpected error when update the user"
+		logger.AppLog.Error().Err(err).Msg(errMsg)
+		return domain.User{}, errors.New(errMsg)
+	}
 	updatedUser.ID = currentUser.ID
 	result, err := collection.ReplaceOne(context.TODO(), bson.D{{Key: "reference", Value: user.Reference}}, updatedUser)
 	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return domain.User{}, duplicateKeyError(err)
+		}
 		errMsg := "unexpected error when update the user"
 		logger.AppLog.Error().Err(err).Msg(errMsg)
 		return domain.User{}, errors.New(errMsg)
@@ -195,33 +360,239 @@ func (r mongoUserRepository) Update(user domain.User) (domain.User, error) {
 	return user, nil
 }
 
-func (r mongoUserRepository) Delete(reference string) (domain.User, error) {
+// encryptionKeeper is implemented by the mappers that encrypt the documents, so the patches compare the decrypted
+// personal data instead of its encrypted values
+type encryptionKeeper interface {
+	keepEncryption(current MongoUser, patched MongoUser) MongoUser
+}
+
+// Patch updates only the document fields that changed. The update fails if the document was modified after it was read
+func (r mongoUserRepository) Patch(user domain.User) (domain.User, error) {
+	if user.Undecryptable {
+		return domain.User{}, ErrUndecryptableUser
+	}
+
 	client := database.Mongo.Client
 	collection := client.Database(r.config.Database).Collection(r.config.UsersCollection)
 
 	// Find document to update
-	currentUser, err := r.findByReference(reference, true)
+	currentUser, err := r.findByReference(user.Reference, false)
 	if err != nil {
 		return domain.User{}, err
 	} else if len(currentUser.Reference) == 0 {
-		return domain.User{}, errors.New("user to delete was not found")
+		return domain.User{}, errors.New("user to patch was not found")
 	}
 
-	// Mark document as deleted
-	currentUser.IsActive = false
-	currentUser.UpdatedDate = time.Now().UTC()
-	result, err := collection.ReplaceOne(context.TODO(), bson.D{{Key: "reference", Value: reference}}, currentUser)
+	// Update changed fields
+	patchedUser, err := r.mapper.MapDomainToRepository(user)
+	if err != nil {
+		errMsg := "unexpected error when patch the user"
+		logger.AppLog.Error().Err(err).Msg(errMsg)
+		return domain.User{}, errors.New(errMsg)
+	}
+	patchedUser.ID = currentUser.ID
+	if keeper, ok := r.mapper.(encryptionKeeper); ok {
+		patchedUser = keeper.keepEncryption(currentUser, patchedUser)
+	}
+	update, err := changedFields(currentUser, patchedUser)
 	if err != nil {
-		errMsg := "unexpected error when mark the user as deleted"
+		errMsg := "unexpected error when patch the user"
 		logger.AppLog.Error().Err(err).Msg(errMsg)
 		return domain.User{}, errors.New(errMsg)
 	}
+	if len(update) == 0 {
+		return user, nil
+	}
 
-	if result.MatchedCount != 1 || result.ModifiedCount != 1 {
-		errMsg := "unexpected error when mark the user as deleted. Matched update elements was not one"
-		logger.AppLog.Error().Int64("matchedCount", result.MatchedCount).Int64("modifiedCount", result.ModifiedCount).Msg(errMsg)
+	filter := bson.D{
+		{Key: "_id", Value: currentUser.ID},
+		{Key: "updated_date", Value: currentUser.UpdatedDate},
+	}
+	result, err := collection.UpdateOne(context.TODO(), filter, update)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return domain.User{}, duplicateKeyError(err)
+		}
+		errMsg := "unexpected error when patch the user"
+		logger.AppLog.Error().Err(err).Msg(errMsg)
 		return domain.User{}, errors.New(errMsg)
 	}
 
-	return r.mapper.MapRepositoryToDomain(currentUser), nil
+	if result.MatchedCount != 1 {
+		logger.AppLog.Warn().Str("reference", user.Reference).Msg("the user was modified while it was patched")
+		return domain.User{}, ErrUserModified
+	}
+
+	return user, nil
+}
+
+// duplicateKeyError returns the error of the unique index that rejected a document. The errors of other
+// unique indexes, like the _id one, are returned as they are
+func duplicateKeyError(err error) error {
+	switch {
+	case strings.Contains(err.Error(), "index: "+emailIndexName+" "):
+		return ErrDuplicateEmail
+	case strings.Contains(err.Error(), "index: "+externalIDsIndexName+" "):
+		return ErrDuplicateExternalID
+	}
+
+	return err
+}
+
+// changedFields returns the update with the top level document fields that are different between both documents
+func changedFields(current MongoUser, patched MongoUser) (bson.D, error) {
+	currentRaw, err := bson.Marshal(current)
+	if err != nil {
+		return nil, err
+	}
+	patchedRaw, err := bson.Marshal(patched)
+	if err != nil {
+		return nil, err
+	}
+	currentDoc := bson.Raw(currentRaw)
+	patchedDoc := bson.Raw(patchedRaw)
+
+	set := bson.D{}
+	unset := bson.D{}
+	patchedElements, err := patchedDoc.Elements()
+	if err != nil {
+		return nil, err
+	}
+	for _, element := range patchedElements {
+		currentValue, err := currentDoc.LookupErr(element.Key())
+		if err != nil || !currentValue.Equal(element.Value()) {
+			set = append(set, bson.E{Key: element.Key(), Value: element.Value()})
+		}
+	}
+	currentElements, err := currentDoc.Elements()
+	if err != nil {
+		return nil, err
+	}
+	for _, element := range currentElements {
+		if _, err := patchedDoc.LookupErr(element.Key()); err != nil {
+			unset = append(unset, bson.E{Key: element.Key(), Value: ""})
+		}
+	}
+
+	update := bson.D{}
+	if len(set) > 0 {
+		update = append(update, bson.E{Key: "$set", Value: set})
+	}
+	if len(unset) > 0 {
+		update = append(update, bson.E{Key: "$unset", Value: unset})
+	}
+
+	return update, nil
+}
+
+// AddTag adds the tag to the user tags, kept sorted, unless the user already has it or has the maximum number of tags.
+// Erased users are not changed. It returns false when the user was not changed
+func (r mongoUserRepository) AddTag(reference string, tag string, maxTags int, updatedDate time.Time) (bool, error) {
+	client := database.Mongo.Client
+	collection := client.Database(r.config.Database).Collection(r.config.UsersCollection)
+
+	filter := bson.D{
+		{Key: "reference", Value: reference},
+		{Key: "erased_date", Value: bson.D{{Key: "$exists", Value: false}}},
+		{Key: "tags", Value: bson.D{{Key: "$ne", Value: tag}}},
+		{Key: fmt.Sprintf("tags.%d", maxTags-1), Value: bson.D{{Key: "$exists", Value: false}}},
+	}
+	update := bson.D{
+		{Key: "$push", Value: bson.D{{Key: "tags", Value: bson.D{
+			{Key: "$each", Value: bson.A{tag}},
+			{Key: "$sort", Value: 1},
+		}}}},
+		{Key: "$set", Value: bson.D{{Key: "updated_date", Value: updatedDate}}},
+	}
+	result, err := collection.UpdateOne(context.TODO(), filter, update)
+	if err != nil {
+		errMsg := "unexpected error when add the user tag"
+		logger.AppLog.Error().Err(err).Msg(errMsg)
+		return false, errors.New(errMsg)
+	}
+
+	return result.ModifiedCount == 1, nil
+}
+
+// RemoveTag removes the tag from the user tags. It returns false when the user does not have the tag
+func (r mongoUserRepository) RemoveTag(reference string, tag string, updatedDate time.Time) (bool, error) {
+	client := database.Mongo.Client
+	collection := client.Database(r.config.Database).Collection(r.config.UsersCollection)
+
+	filter := bson.D{{Key: "reference", Value: reference}, {Key: "tags", Value: tag}}
+	update := bson.D{
+		{Key: "$pull", Value: bson.D{{Key: "tags", Value: tag}}},
+		{Key: "$set", Value: bson.D{{Key: "updated_date", Value: updatedDate}}},
+	}
+	result, err := collection.UpdateOne(context.TODO(), filter, update)
+	if err != nil {
+		errMsg := "unexpected error when remove the user tag"
+		logger.AppLog.Error().Err(err).Msg(errMsg)
+		return false, errors.New(errMsg)
+	}
+	if result.ModifiedCount != 1 {
+		return false, nil
+	}
+
+	// The users without tags are stored without the field, as the mapper does
+	emptyFilter := bson.D{{Key: "reference", Value: reference}, {Key: "tags", Value: bson.D{{Key: "$size", Value: 0}}}}
+	_, err = collection.UpdateOne(context.TODO(), emptyFilter, bson.D{{Key: "$unset", Value: bson.D{{Key: "tags", Value: ""}}}})
+	if err != nil {
+		logger.AppLog.Error().Err(err).Str("reference", reference).Msg("unable to remove the empty user tags")
+	}
+
+	return true, nil
+}
+
+// ChangeStatus replaces the user only while it keeps the status it had when it was read, so two concurrent status
+// changes can not both succeed. It returns ErrUserModified when the user status changed meanwhile
+func (r mongoUserRepository) ChangeStatus(user domain.User, from domain.UserStatus) (domain.User, error) {
+	if user.Undecryptable {
+		return domain.User{}, ErrUndecryptableUser
+	}
+
+	client := database.Mongo.Client
+	collection := client.Database(r.config.Database).Collection(r.config.UsersCollection)
+
+	// Find document to update
+	currentUser, err := r.findByReference(user.Reference, false)
+	if err != nil {
+		return domain.User{}, err
+	} else if len(currentUser.Reference) == 0 {
+		return domain.User{}, errors.New("user to change status was not found")
+	}
+
+	// Update document
+	updatedUser, err := r.mapper.MapDomainToRepository(user)
+	if err != nil {
+		errMsg := "unexpected error when change the user status"
+		logger.AppLog.Error().Err(err).Msg(errMsg)
+		return domain.User{}, errors.New(errMsg)
+	}
+	updatedUser.ID = currentUser.ID
+	filter := bson.D{{Key: "reference", Value: user.Reference}}
+	// The users stored before the status was added have their status in the active mark
+	filter = append(filter, bson.E{Key: "$or", Value: bson.A{
+		bson.D{{Key: "status", Value: string(from)}},
+		bson.D{
+			{Key: "status", Value: bson.D{{Key: "$exists", Value: false}}},
+			{Key: "is_active", Value: from == domain.UserStatusActive},
+		},
+	}})
+	result, err := collection.ReplaceOne(context.TODO(), filter, updatedUser)
+	if err != nil {
+		if mongo.IsDuplicateKeyError(err) {
+			return domain.User{}, duplicateKeyError(err)
+		}
+		errMsg := "unexpected error when change the user status"
+		logger.AppLog.Error().Err(err).Msg(errMsg)
+		return domain.User{}, errors.New(errMsg)
+	}
+
+	if result.MatchedCount != 1 {
+		logger.AppLog.Warn().Str("reference", user.Reference).Msg("the user status changed while it was updated")
+		return domain.User{}, ErrUserModified
+	}
+
+	return user, nil
 }
@@ -0,0 +1,223 @@
+package infrastructure
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/desarrollogj/golang-api-example/domain"
+	"github.com/desarrollogj/golang-api-example/libs/encryption"
+	"github.com/desarrollogj/golang-api-example/libs/logger"
+)
+
+// encryptedMongoRepositoryMapper is an UserMongoRepositoryMapper that encrypts the user personal data.
+// Each document is encrypted with its own data key, wrapped by the keyring active key
+type encryptedMongoRepositoryMapper struct {
+	mapper  UserMongoRepositoryMapper
+	keyring *encryption.Keyring
+}
+
+// NewEncryptedMongoRepositoryMapper creates a new encryptedMongoRepositoryMapper decorating a plain mapper
+func NewEncryptedMongoRepositoryMapper(mapper UserMongoRepositoryMapper, keyring *encryption.Keyring) encryptedMongoRepositoryMapper {
+	return encryptedMongoRepositoryMapper{
+		mapper:  mapper,
+		keyring: keyring,
+	}
+}
+
+func (m encryptedMongoRepositoryMapper) MapDomainToRepository(user domain.User) (MongoUser, error) {
+	plain, err := m.mapper.MapDomainToRepository(user)
+	if err != nil {
+		return MongoUser{}, err
+	}
+
+	// A failure here means the keyring or the random source are broken, the document must never be stored in clear text
+	encrypted, err := m.encrypt(plain)
+	if err != nil {
+		return MongoUser{}, fmt.Errorf("unable to encrypt user %s: %w", user.Reference, err)
+	}
+
+	return encrypted, nil
+}
+
+func (m encryptedMongoRepositoryMapper) MapRepositoryToDomain(user MongoUser) domain.User {
+	decrypted, err := m.decrypt(user)
+	if err != nil {
+		// The user is returned with its personal data blank, and marked so the repository refuses to store it
+		logger.AppLog.Error().Err(err).Str("reference", user.Reference).Msg("unable to decrypt user")
+		decrypted = copyUser(user)
+		for _, value := range encryptedFields(&decrypted) {
+			*value = ""
+		}
+		mapped := m.mapper.MapRepositoryToDomain(decrypted)
+		mapped.Undecryptable = true
+		return mapped
+	}
+
+	return m.mapper.MapRepositoryToDomain(decrypted)
+}
+
+func (m encryptedMongoRepositoryMapper) MapRepositoryListToDomainList(users []MongoUser) []domain.User {
+	mappedUsers := []domain.User{}
+
+	for _, user := range users {
+		mappedUsers = append(mappedUsers, m.MapRepositoryToDomain(user))
+	}
+
+	return mappedUsers
+}
+
+func (m encryptedMongoRepositoryMapper) MapRepositorySearchActiveToOutput(users []MongoUser, total int64, page int, size int) domain.UserSearchOutput {
+	return domain.UserSearchOutput{
+		SearchOutput: domain.SearchOutput{
+			Total:    total,
+			Page:     page,
+			PageSize: size,
+		},
+		Users: m.MapRepositoryListToDomainList(users),
+	}
+}
+
+// MapEmailToIndex returns the email blind index, used to find users by email without decrypting them
+func (m encryptedMongoRepositoryMapper) MapEmailToIndex(email string) string {
+	return m.keyring.BlindIndex(email)
+}
+
+// IsEncrypted returns true, the personal data is stored encrypted
+func (m encryptedMongoRepositoryMapper) IsEncrypted() bool {
+	return true
+}
+
+// keepEncryption returns the patched document with the current encrypted personal data and data key, when the decrypted
+// personal data of both documents is the same. Each encryption stores different values, so comparing the encrypted
+// documents would always find the personal data changed. Documents with a previous key get the new encryption
+func (m encryptedMongoRepositoryMapper) keepEncryption(current MongoUser, patched MongoUser) MongoUser {
+	if !m.isCurrent(current) {
+		return patched
+	}
+	decryptedCurrent, err := m.decrypt(current)
+	if err != nil {
+		return patched
+	}
+	decryptedPatched, err := m.decrypt(patched)
+	if err != nil {
+		return patched
+	}
+
+	currentFields := encryptedFields(&decryptedCurrent)
+	patchedFields := encryptedFields(&decryptedPatched)
+	if len(currentFields) != len(patchedFields) || !sameDate(decryptedCurrent.BirthDate, decryptedPatched.BirthDate) {
+		return patched
+	}
+	for field, value := range patchedFields {
+		if currentValue, found := currentFields[field]; !found || *currentValue != *value {
+			return patched
+		}
+	}
+
+	kept := copyUser(patched)
+	storedFields := encryptedFields(&current)
+	for field, value := range encryptedFields(&kept) {
+		*value = *storedFields[field]
+	}
+	kept.BirthDate = current.BirthDate
+	kept.EmailIndex = current.EmailIndex
+	kept.Encryption = current.Encryption
+
+	return kept
+}
+
+func sameDate(a *time.Time, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
+
+// isCurrent returns true when the document is encrypted with the keyring active key
+func (m encryptedMongoRepositoryMapper) isCurrent(user MongoUser) bool {
+	return user.Encryption != nil && user.Encryption.KeyID == m.keyring.ActiveKeyID()
+}
+
+func (m encryptedMongoRepositoryMapper) encrypt(user MongoUser) (MongoUser, error) {
+	dataKey, err := m.keyring.GenerateDataKey()
+	if err != nil {
+		return MongoUser{}, err
+	}
+
+	encrypted := copyUser(user)
+	encrypted.EmailIndex = m.keyring.BlindIndex(user.Email)
+	// The birth date is stored as an encrypted text instead of a date
+	if user.BirthDate != nil {
+		encrypted.EncryptedBirthDate = user.BirthDate.Format(time.RFC3339)
+		encrypted.BirthDate = nil
+	}
+	encrypted.Encryption = &MongoEncryption{KeyID: dataKey.KeyID, DataKey: dataKey.Wrapped}
+	for field, value := range encryptedFields(&encrypted) {
+		if *value, err = dataKey.Encrypt(field, *value); err != nil {
+			return MongoUser{}, err
+		}
+	}
+
+	return encrypted, nil
+}
+
+func (m encryptedMongoRepositoryMapper) decrypt(user MongoUser) (MongoUser, error) {
+	// Documents stored before the encryption was enabled are in clear text
+	if user.Encryption == nil {
+		return user, nil
+	}
+
+	dataKey, err := m.keyring.UnwrapDataKey(user.Encryption.KeyID, user.Encryption.DataKey)
+	if err != nil {
+		return MongoUser{}, err
+	}
+
+	decrypted := copyUser(user)
+	for field, value := range encryptedFields(&decrypted) {
+		if *value, err = dataKey.Decrypt(field, *value); err != nil {
+			return MongoUser{}, err
+		}
+	}
+	if len(decrypted.EncryptedBirthDate) > 0 {
+		birthDate, err := time.Parse(time.RFC3339, decrypted.EncryptedBirthDate)
+		if err != nil {
+			return MongoUser{}, err
+		}
+		decrypted.BirthDate = &birthDate
+		decrypted.EncryptedBirthDate = ""
+	}
+
+	return decrypted, nil
+}
+
+// encryptedFields returns the document fields holding personal data, keyed by their bson path
+func encryptedFields(user *MongoUser) map[string]*string {
+	fields := map[string]*string{
+		"first_name": &user.FirstName,
+		"last_name":  &user.LastName,
+		"email":      &user.Email,
+		"phone":      &user.Phone,
+		"birth_date": &user.EncryptedBirthDate,
+	}
+	// The country is not encrypted, the users are searched by it
+	if user.Address != nil {
+		fields["address.line1"] = &user.Address.Line1
+		fields["address.line2"] = &user.Address.Line2
+		fields["address.city"] = &user.Address.City
+		fields["address.region"] = &user.Address.Region
+		fields["address.postal_code"] = &user.Address.PostalCode
+	}
+
+	return fields
+}
+
+// copyUser copies a document, including its nested documents, so it can be modified without changing the original
+func copyUser(user MongoUser) MongoUser {
+	copied := user
+	if user.Address != nil {
+		address := *user.Address
+		copied.Address = &address
+	}
+
+	return copied
+}
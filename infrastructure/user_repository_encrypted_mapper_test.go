@@ -0,0 +1,280 @@
+package infrastructure
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/desarrollogj/golang-api-example/domain"
+	"github.com/desarrollogj/golang-api-example/libs/encryption"
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func newKeyringMock(t *testing.T, activeKey string) *encryption.Keyring {
+	keys := map[string][]byte{
+		"KEY1": bytes.Repeat([]byte{1}, 32),
+		"KEY2": bytes.Repeat([]byte{2}, 32),
+	}
+	keyring, err := encryption.NewKeyring(activeKey, keys, bytes.Repeat([]byte{3}, 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return keyring
+}
+
+func TestEncryptedMongoUserRepositoryMapper_GivenDomainData_WhenMap_ThenEncryptPersonalData(t *testing.T) {
+	t.Log("Should map user domain data to user repository data with encrypted personal data")
+
+	now := time.Now().UTC()
+	domainUser := domain.User{
+		GenericEntity: domain.GenericEntity{
+			Reference:   "USER1",
+			IsActive:    true,
+			CreatedDate: now,
+			UpdatedDate: now,
+		},
+		FirstName:  "Foo",
+		LastName:   "Bar",
+		Email:      "foobar@test.com",
+		Status:     domain.UserStatusActive,
+		StatusDate: now,
+	}
+	keyring := newKeyringMock(t, "KEY1")
+
+	mapper := NewEncryptedMongoRepositoryMapper(NewDefaultMongoRepositoryMapper(), keyring)
+	repoUser, err := mapper.MapDomainToRepository(domainUser)
+
+	assert.Nil(t, err)
+
+	assert.Equal(t, "USER1", repoUser.Reference)
+	assert.True(t, repoUser.IsActive)
+	assert.Equal(t, now, repoUser.CreatedDate)
+	assert.NotEqual(t, "Foo", repoUser.FirstName)
+	assert.NotEqual(t, "Bar", repoUser.LastName)
+	assert.NotEqual(t, "foobar@test.com", repoUser.Email)
+	assert.Equal(t, keyring.BlindIndex("foobar@test.com"), repoUser.EmailIndex)
+	assert.NotNil(t, repoUser.Encryption)
+	assert.Equal(t, "KEY1", repoUser.Encryption.KeyID)
+
+	assert.Equal(t, domainUser, mapper.MapRepositoryToDomain(repoUser))
+}
+
+func TestEncryptedMongoUserRepositoryMapper_GivenPlainRepositoryData_WhenMap_ThenMapToDomainData(t *testing.T) {
+	t.Log("Should map user repository data stored before the encryption was enabled")
+
+	now := time.Now().UTC()
+	repositoryUser := MongoUser{
+		Reference:   "USER1",
+		FirstName:   "Foo",
+		LastName:    "Bar",
+		Email:       "foobar@test.com",
+		IsActive:    true,
+		Status:      "active",
+		StatusDate:  now,
+		CreatedDate: now,
+		UpdatedDate: now,
+	}
+	expectedDomainUser := domain.User{
+		GenericEntity: domain.GenericEntity{
+			Reference:   "USER1",
+			IsActive:    true,
+			CreatedDate: now,
+			UpdatedDate: now,
+		},
+		FirstName:  "Foo",
+		LastName:   "Bar",
+		Email:      "foobar@test.com",
+		Status:     domain.UserStatusActive,
+		StatusDate: now,
+	}
+
+	mapper := NewEncryptedMongoRepositoryMapper(NewDefaultMongoRepositoryMapper(), newKeyringMock(t, "KEY1"))
+	domainUser := mapper.MapRepositoryToDomain(repositoryUser)
+
+	assert.Equal(t, expectedDomainUser, domainUser)
+	assert.False(t, mapper.isCurrent(repositoryUser))
+}
+
+func TestEncryptedMongoUserRepositoryMapper_GivenRotatedKeyring_WhenMap_ThenMapToDomainData(t *testing.T) {
+	t.Log("Should map user repository data encrypted with a previous key")
+
+	domainUser := domain.User{
+		GenericEntity: domain.GenericEntity{Reference: "USER1"},
+		FirstName:     "Foo",
+		LastName:      "Bar",
+		Email:         "foobar@test.com",
+		Status:        domain.UserStatusActive,
+	}
+	previousMapper := NewEncryptedMongoRepositoryMapper(NewDefaultMongoRepositoryMapper(), newKeyringMock(t, "KEY1"))
+	repoUser, err := previousMapper.MapDomainToRepository(domainUser)
+
+	assert.Nil(t, err)
+
+	mapper := NewEncryptedMongoRepositoryMapper(NewDefaultMongoRepositoryMapper(), newKeyringMock(t, "KEY2"))
+
+	assert.False(t, mapper.isCurrent(repoUser))
+	assert.Equal(t, domainUser, mapper.MapRepositoryToDomain(repoUser))
+	repoUser, err = mapper.MapDomainToRepository(domainUser)
+	assert.Nil(t, err)
+	assert.True(t, mapper.isCurrent(repoUser))
+}
+
+func TestEncryptedMongoUserRepositoryMapper_GivenTamperedRepositoryData_WhenMap_ThenPersonalDataIsEmpty(t *testing.T) {
+	t.Log("Should not return personal data that can not be decrypted")
+
+	domainUser := domain.User{
+		GenericEntity: domain.GenericEntity{Reference: "USER1"},
+		FirstName:     "Foo",
+		LastName:      "Bar",
+		Email:         "foobar@test.com",
+		Status:        domain.UserStatusActive,
+	}
+	mapper := NewEncryptedMongoRepositoryMapper(NewDefaultMongoRepositoryMapper(), newKeyringMock(t, "KEY1"))
+	repoUser, err := mapper.MapDomainToRepository(domainUser)
+
+	assert.Nil(t, err)
+	repoUser.FirstName, repoUser.LastName = repoUser.LastName, repoUser.FirstName
+
+	mapped := mapper.MapRepositoryToDomain(repoUser)
+
+	assert.Equal(t, "USER1", mapped.Reference)
+	assert.Equal(t, "", mapped.FirstName)
+	assert.Equal(t, "", mapped.LastName)
+	assert.Equal(t, "", mapped.Email)
+	assert.True(t, mapped.Undecryptable)
+}
+
+func TestEncryptedMongoUserRepositoryMapper_GivenRepositoryDataWithAnUnknownKey_WhenMap_ThenMarkTheUserAsUndecryptable(t *testing.T) {
+	t.Log("Should mark the users encrypted with a key that is not in the keyring, so they are not stored blank")
+
+	domainUser := domain.User{
+		GenericEntity: domain.GenericEntity{Reference: "USER1"},
+		FirstName:     "Foo",
+		Email:         "foobar@test.com",
+	}
+	previousKeyring, err := encryption.NewKeyring("KEY3", map[string][]byte{"KEY3": bytes.Repeat([]byte{4}, 32)}, bytes.Repeat([]byte{3}, 32))
+	assert.Nil(t, err)
+	repoUser, err := NewEncryptedMongoRepositoryMapper(NewDefaultMongoRepositoryMapper(), previousKeyring).MapDomainToRepository(domainUser)
+	assert.Nil(t, err)
+
+	mapper := NewEncryptedMongoRepositoryMapper(NewDefaultMongoRepositoryMapper(), newKeyringMock(t, "KEY1"))
+	mapped := mapper.MapRepositoryToDomain(repoUser)
+
+	assert.True(t, mapped.Undecryptable)
+	assert.Equal(t, "", mapped.FirstName)
+}
+
+func TestEncryptedMongoUserRepositoryMapper_WhenMapEmailToIndex_ThenReturnBlindIndex(t *testing.T) {
+	t.Log("Should map an email to its blind index")
+
+	keyring := newKeyringMock(t, "KEY1")
+	mapper := NewEncryptedMongoRepositoryMapper(NewDefaultMongoRepositoryMapper(), keyring)
+
+	assert.Equal(t, keyring.BlindIndex("foobar@test.com"), mapper.MapEmailToIndex("FooBar@test.com"))
+	assert.Equal(t, "", NewDefaultMongoRepositoryMapper().MapEmailToIndex("foobar@test.com"))
+}
+
+func TestEncryptedMongoUserRepositoryMapper_GivenDomainDataWithProfile_WhenMap_ThenEncryptTheProfile(t *testing.T) {
+	t.Log("Should encrypt the user phone, birth date and address without changing the domain data")
+
+	birthDate := time.Date(1990, 5, 17, 0, 0, 0, 0, time.UTC)
+	domainUser := domain.User{
+		GenericEntity: domain.GenericEntity{Reference: "USER1"},
+		UserProfile: domain.UserProfile{
+			Phone:     "+5491112345678",
+			BirthDate: &birthDate,
+			Address:   &domain.Address{Line1: "Street 123", City: "Buenos Aires", Region: "CABA", PostalCode: "C1000", Country: "AR"},
+		},
+		FirstName: "Foo",
+		LastName:  "Bar",
+		Email:     "foobar@test.com",
+		Status:    domain.UserStatusActive,
+	}
+	keyring := newKeyringMock(t, "KEY1")
+
+	mapper := NewEncryptedMongoRepositoryMapper(NewDefaultMongoRepositoryMapper(), keyring)
+	repoUser, err := mapper.MapDomainToRepository(domainUser)
+
+	assert.Nil(t, err)
+
+	assert.NotEqual(t, "+5491112345678", repoUser.Phone)
+	assert.Nil(t, repoUser.BirthDate)
+	assert.NotEmpty(t, repoUser.EncryptedBirthDate)
+	assert.NotContains(t, repoUser.EncryptedBirthDate, "1990")
+	assert.NotEqual(t, "Street 123", repoUser.Address.Line1)
+	assert.NotEqual(t, "Buenos Aires", repoUser.Address.City)
+	assert.NotEqual(t, "CABA", repoUser.Address.Region)
+	assert.NotEqual(t, "C1000", repoUser.Address.PostalCode)
+	assert.Equal(t, "AR", repoUser.Address.Country)
+	assert.Equal(t, "Street 123", domainUser.Address.Line1)
+
+	assert.Equal(t, domainUser, mapper.MapRepositoryToDomain(repoUser))
+}
+
+func TestEncryptedMongoUserRepositoryMapper_GivenAPatchWithoutPersonalDataChanges_WhenKeepEncryption_ThenOnlyTheChangedFieldsAreUpdated(t *testing.T) {
+	t.Log("Should keep the stored encrypted personal data when a patch does not change it")
+
+	birthDate := time.Date(1990, 5, 17, 0, 0, 0, 0, time.UTC)
+	domainUser := domain.User{
+		GenericEntity: domain.GenericEntity{Reference: "USER1"},
+		UserProfile: domain.UserProfile{
+			Phone:     "+5491112345678",
+			BirthDate: &birthDate,
+			Address:   &domain.Address{Line1: "Street 123", City: "Buenos Aires", Country: "AR"},
+			Locale:    "es-AR",
+		},
+		FirstName: "Foo",
+		LastName:  "Bar",
+		Email:     "foobar@test.com",
+		Status:    domain.UserStatusActive,
+	}
+	mapper := NewEncryptedMongoRepositoryMapper(NewDefaultMongoRepositoryMapper(), newKeyringMock(t, "KEY1"))
+	current, err := mapper.MapDomainToRepository(domainUser)
+	assert.Nil(t, err)
+
+	domainUser.Locale = "en-US"
+	domainUser.Address = &domain.Address{Line1: "Street 123", City: "Buenos Aires", Country: "UY"}
+	patched, err := mapper.MapDomainToRepository(domainUser)
+	assert.Nil(t, err)
+
+	update, err := changedFields(current, mapper.keepEncryption(current, patched))
+
+	assert.Nil(t, err)
+	set := update[0].Value.(bson.D)
+	assert.Len(t, update, 1)
+	assert.Len(t, set, 2)
+	assert.Equal(t, "locale", set[0].Key)
+	assert.Equal(t, "address", set[1].Key)
+	assert.Equal(t, domainUser, mapper.MapRepositoryToDomain(mapper.keepEncryption(current, patched)))
+}
+
+func TestEncryptedMongoUserRepositoryMapper_GivenAPatchWithPersonalDataChanges_WhenKeepEncryption_ThenReturnTheNewEncryption(t *testing.T) {
+	t.Log("Should store the new encryption when a patch changes the personal data, or the data has a previous key")
+
+	domainUser := domain.User{
+		GenericEntity: domain.GenericEntity{Reference: "USER1"},
+		FirstName:     "Foo",
+		LastName:      "Bar",
+		Email:         "foobar@test.com",
+		Status:        domain.UserStatusActive,
+	}
+	mapper := NewEncryptedMongoRepositoryMapper(NewDefaultMongoRepositoryMapper(), newKeyringMock(t, "KEY1"))
+	current, err := mapper.MapDomainToRepository(domainUser)
+	assert.Nil(t, err)
+
+	domainUser.Phone = "+5491112345678"
+	patched, err := mapper.MapDomainToRepository(domainUser)
+	assert.Nil(t, err)
+
+	assert.Equal(t, patched, mapper.keepEncryption(current, patched))
+
+	domainUser.Phone = ""
+	previousMapper := NewEncryptedMongoRepositoryMapper(NewDefaultMongoRepositoryMapper(), newKeyringMock(t, "KEY2"))
+	previous, err := previousMapper.MapDomainToRepository(domainUser)
+	assert.Nil(t, err)
+	patched, err = mapper.MapDomainToRepository(domainUser)
+	assert.Nil(t, err)
+
+	assert.Equal(t, patched, mapper.keepEncryption(previous, patched))
+}
@@ -0,0 +1,78 @@
+package infrastructure
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestChangedFields_GivenTwoDocuments_WhenCompare_ThenReturnOnlyTheChangedFields(t *testing.T) {
+	t.Log("Should set the changed fields and unset the removed ones")
+
+	now := time.Date(2023, 3, 1, 10, 0, 0, 0, time.UTC)
+	current := MongoUser{
+		ID:          primitive.NewObjectID(),
+		Reference:   "USER1",
+		FirstName:   "Foo",
+		LastName:    "Bar",
+		Email:       "foobar@test.com",
+		Locale:      "es-AR",
+		Address:     &MongoAddress{Line1: "Street 123", City: "Buenos Aires", Country: "AR"},
+		IsActive:    true,
+		CreatedDate: now,
+		UpdatedDate: now,
+	}
+	patched := copyUser(current)
+	patched.FirstName = "Another Foo"
+	patched.Locale = ""
+	patched.Address.City = "La Plata"
+	patched.UpdatedDate = now.Add(time.Minute)
+
+	update, err := changedFields(current, patched)
+
+	assert.Nil(t, err)
+	set := update.Map()["$set"].(bson.D).Map()
+	unset := update.Map()["$unset"].(bson.D).Map()
+	assert.Len(t, set, 3)
+	assert.Equal(t, "Another Foo", set["first_name"].(bson.RawValue).StringValue())
+	assert.Equal(t, "La Plata", set["address"].(bson.RawValue).Document().Lookup("city").StringValue())
+	assert.Equal(t, now.Add(time.Minute), set["updated_date"].(bson.RawValue).Time().UTC())
+	assert.Equal(t, map[string]interface{}{"locale": ""}, map[string]interface{}(unset))
+}
+
+func TestChangedFields_GivenTwoEqualDocuments_WhenCompare_ThenReturnAnEmptyUpdate(t *testing.T) {
+	t.Log("Should not update anything when the documents are equal")
+
+	current := MongoUser{
+		ID:        primitive.NewObjectID(),
+		Reference: "USER1",
+		FirstName: "Foo",
+	}
+
+	update, err := changedFields(current, copyUser(current))
+
+	assert.Nil(t, err)
+	assert.Empty(t, update)
+}
+
+func TestDuplicateKeyError_GivenADuplicateKeyError_WhenMap_ThenReturnTheErrorOfItsIndex(t *testing.T) {
+	t.Log("Should return the error of the unique index that rejected the document")
+
+	cases := []struct {
+		message  string
+		expected error
+	}{
+		{"E11000 duplicate key error collection: users.users index: email_index dup key: { email_index: \"abc\" }", ErrDuplicateEmail},
+		{"E11000 duplicate key error collection: users.users index: external_ids dup key: { external_ids: { source: \"crm\", id: \"1\" } }", ErrDuplicateExternalID},
+	}
+	for _, c := range cases {
+		assert.Equal(t, c.expected, duplicateKeyError(errors.New(c.message)))
+	}
+
+	other := errors.New("E11000 duplicate key error collection: users.users index: _id_ dup key: { _id: ObjectId('1') }")
+	assert.Equal(t, other, duplicateKeyError(other))
+}
@@ -1,13 +1,22 @@
 package infrastructure
 
-import "github.com/desarrollogj/golang-api-example/domain"
+import (
+	"time"
+
+	"github.com/desarrollogj/golang-api-example/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
 
 // UserMongoRepositoryMapper represents the methods to be implemented by mongo domain entities mapper
 type UserMongoRepositoryMapper interface {
-	MapDomainToRepository(user domain.User) MongoUser
+	MapDomainToRepository(user domain.User) (MongoUser, error)
 	MapRepositoryToDomain(user MongoUser) domain.User
 	MapRepositoryListToDomainList(users []MongoUser) []domain.User
 	MapRepositorySearchActiveToOutput(users []MongoUser, total int64, page int, size int) domain.UserSearchOutput
+	MapEmailToIndex(email string) string
+	// IsEncrypted returns true when the personal data is stored encrypted, so it can only be matched completely by its blind index
+	IsEncrypted() bool
 }
 
 // defaultMongoRepositoryMapper is the default implementation of UserMongoRepositoryMapper
@@ -19,16 +28,33 @@ func NewDefaultMongoRepositoryMapper() defaultMongoRepositoryMapper {
 	return defaultMongoRepositoryMapper{}
 }
 
-func (m defaultMongoRepositoryMapper) MapDomainToRepository(user domain.User) MongoUser {
+func (m defaultMongoRepositoryMapper) MapDomainToRepository(user domain.User) (MongoUser, error) {
 	return MongoUser{
-		Reference:   user.Reference,
-		FirstName:   user.FirstName,
-		LastName:    user.LastName,
-		Email:       user.Email,
-		IsActive:    user.IsActive,
-		CreatedDate: user.CreatedDate,
-		UpdatedDate: user.UpdatedDate,
-	}
+		Reference:                 user.Reference,
+		FirstName:                 user.FirstName,
+		LastName:                  user.LastName,
+		Email:                     user.Email,
+		EmailVerifiedDate:         user.EmailVerifiedDate,
+		EmailVerificationSentDate: user.EmailVerificationSentDate,
+		Roles:                     user.Roles,
+		Attributes:                m.mapAttributesToRepository(user.Attributes),
+		Tags:                      user.Tags,
+		ExternalIDs:               m.mapExternalIDsToRepository(user.ExternalIDs),
+		Avatar:                    m.mapAvatarToRepository(user.Avatar),
+		Phone:                     user.Phone,
+		BirthDate:                 user.BirthDate,
+		Locale:                    user.Locale,
+		Timezone:                  user.Timezone,
+		Address:                   m.mapAddressToRepository(user.Address),
+		IsActive:                  user.IsActive,
+		Status:                    string(domain.ResolveUserStatus(user.Status, user.IsActive)),
+		StatusReason:              user.StatusReason,
+		StatusDate:                mapStatusDate(user.StatusDate, user.UpdatedDate),
+		CreatedDate:               user.CreatedDate,
+		UpdatedDate:               user.UpdatedDate,
+		ErasedDate:                user.ErasedDate,
+		MergedInto:                user.MergedInto,
+	}, nil
 }
 
 func (m defaultMongoRepositoryMapper) MapRepositoryToDomain(user MongoUser) domain.User {
@@ -39,9 +65,28 @@ func (m defaultMongoRepositoryMapper) MapRepositoryToDomain(user MongoUser) doma
 			CreatedDate: user.CreatedDate,
 			UpdatedDate: user.UpdatedDate,
 		},
-		FirstName: user.FirstName,
-		LastName:  user.LastName,
-		Email:     user.Email,
+		UserProfile: domain.UserProfile{
+			Phone:     user.Phone,
+			BirthDate: user.BirthDate,
+			Locale:    user.Locale,
+			Timezone:  user.Timezone,
+			Address:   m.mapAddressToDomain(user.Address),
+		},
+		FirstName:                 user.FirstName,
+		LastName:                  user.LastName,
+		Email:                     user.Email,
+		EmailVerifiedDate:         user.EmailVerifiedDate,
+		EmailVerificationSentDate: user.EmailVerificationSentDate,
+		Roles:                     user.Roles,
+		Attributes:                m.mapAttributesToDomain(user.Attributes),
+		Tags:                      user.Tags,
+		ExternalIDs:               m.mapExternalIDsToDomain(user.ExternalIDs),
+		Avatar:                    m.mapAvatarToDomain(user.Avatar),
+		Status:                    domain.ResolveUserStatus(domain.UserStatus(user.Status), user.IsActive),
+		StatusReason:              user.StatusReason,
+		StatusDate:                mapStatusDate(user.StatusDate, user.UpdatedDate),
+		ErasedDate:                user.ErasedDate,
+		MergedInto:                user.MergedInto,
 	}
 }
 
@@ -65,3 +110,137 @@ func (m defaultMongoRepositoryMapper) MapRepositorySearchActiveToOutput(users []
 		Users: m.MapRepositoryListToDomainList(users),
 	}
 }
+
+func (m defaultMongoRepositoryMapper) mapAddressToRepository(address *domain.Address) *MongoAddress {
+	if address == nil {
+		return nil
+	}
+
+	return &MongoAddress{
+		Line1:      address.Line1,
+		Line2:      address.Line2,
+		City:       address.City,
+		Region:     address.Region,
+		PostalCode: address.PostalCode,
+		Country:    address.Country,
+	}
+}
+
+func (m defaultMongoRepositoryMapper) mapAddressToDomain(address *MongoAddress) *domain.Address {
+	if address == nil {
+		return nil
+	}
+
+	return &domain.Address{
+		Line1:      address.Line1,
+		Line2:      address.Line2,
+		City:       address.City,
+		Region:     address.Region,
+		PostalCode: address.PostalCode,
+		Country:    address.Country,
+	}
+}
+
+func (m defaultMongoRepositoryMapper) mapAttributesToRepository(attributes map[string]interface{}) bson.M {
+	if len(attributes) == 0 {
+		return nil
+	}
+
+	mapped := bson.M{}
+	for name, value := range attributes {
+		mapped[name] = value
+	}
+	return mapped
+}
+
+// mapAttributesToDomain returns the attributes with the same types the user use cases produce: mongo stores
+// the dates as its own date type, and the numbers may be read as integers
+func (m defaultMongoRepositoryMapper) mapAttributesToDomain(attributes bson.M) map[string]interface{} {
+	if len(attributes) == 0 {
+		return nil
+	}
+
+	mapped := map[string]interface{}{}
+	for name, value := range attributes {
+		switch typed := value.(type) {
+		case primitive.DateTime:
+			mapped[name] = typed.Time().UTC()
+		case int32:
+			mapped[name] = float64(typed)
+		case int64:
+			mapped[name] = float64(typed)
+		default:
+			mapped[name] = value
+		}
+	}
+	return mapped
+}
+
+func (m defaultMongoRepositoryMapper) mapExternalIDsToRepository(externalIDs []domain.UserExternalID) []MongoExternalID {
+	if len(externalIDs) == 0 {
+		return nil
+	}
+
+	mapped := []MongoExternalID{}
+	for _, externalID := range externalIDs {
+		mapped = append(mapped, MongoExternalID{Source: externalID.Source, ID: externalID.ID})
+	}
+	return mapped
+}
+
+func (m defaultMongoRepositoryMapper) mapExternalIDsToDomain(externalIDs []MongoExternalID) []domain.UserExternalID {
+	if len(externalIDs) == 0 {
+		return nil
+	}
+
+	mapped := []domain.UserExternalID{}
+	for _, externalID := range externalIDs {
+		mapped = append(mapped, domain.UserExternalID{Source: externalID.Source, ID: externalID.ID})
+	}
+	return mapped
+}
+
+func (m defaultMongoRepositoryMapper) mapAvatarToRepository(avatar *domain.UserAvatar) *MongoUserAvatar {
+	if avatar == nil {
+		return nil
+	}
+
+	return &MongoUserAvatar{
+		Version:     avatar.Version,
+		ContentType: avatar.ContentType,
+		Sizes:       avatar.Sizes,
+		UpdatedDate: avatar.UpdatedDate,
+	}
+}
+
+func (m defaultMongoRepositoryMapper) mapAvatarToDomain(avatar *MongoUserAvatar) *domain.UserAvatar {
+	if avatar == nil {
+		return nil
+	}
+
+	return &domain.UserAvatar{
+		Version:     avatar.Version,
+		ContentType: avatar.ContentType,
+		Sizes:       avatar.Sizes,
+		UpdatedDate: avatar.UpdatedDate,
+	}
+}
+
+// MapEmailToIndex returns the blind index of an email. Plain documents are not indexed, so it is always empty
+func (m defaultMongoRepositoryMapper) MapEmailToIndex(email string) string {
+	return ""
+}
+
+// IsEncrypted returns false, the plain documents store the personal data in clear text
+func (m defaultMongoRepositoryMapper) IsEncrypted() bool {
+	return false
+}
+
+// mapStatusDate returns the user status date, or its update date when the status was never changed
+func mapStatusDate(statusDate time.Time, updatedDate time.Time) time.Time {
+	if statusDate.IsZero() {
+		return updatedDate
+	}
+
+	return statusDate
+}
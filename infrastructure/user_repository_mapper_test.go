@@ -6,6 +6,8 @@ import (
 
 	"github.com/desarrollogj/golang-api-example/domain"
 	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 func TestMongoUserRepositoryMapper_GivenDomainData_WhenMap_ThenMapToRepositoryData(t *testing.T) {
@@ -19,9 +21,11 @@ func TestMongoUserRepositoryMapper_GivenDomainData_WhenMap_ThenMapToRepositoryDa
 			CreatedDate: now,
 			UpdatedDate: now,
 		},
-		FirstName: "Foo",
-		LastName:  "Bar",
-		Email:     "foobar@test.com",
+		FirstName:  "Foo",
+		LastName:   "Bar",
+		Email:      "foobar@test.com",
+		Status:     domain.UserStatusActive,
+		StatusDate: now,
 	}
 	expectedRepoUser := MongoUser{
 		Reference:   "USER1",
@@ -29,12 +33,16 @@ func TestMongoUserRepositoryMapper_GivenDomainData_WhenMap_ThenMapToRepositoryDa
 		LastName:    "Bar",
 		Email:       "foobar@test.com",
 		IsActive:    true,
+		Status:      "active",
+		StatusDate:  now,
 		CreatedDate: now,
 		UpdatedDate: now,
 	}
 
 	mapper := NewDefaultMongoRepositoryMapper()
-	repoUser := mapper.MapDomainToRepository(domainUser)
+	repoUser, err := mapper.MapDomainToRepository(domainUser)
+
+	assert.Nil(t, err)
 
 	assert.NotNil(t, repoUser)
 	assert.Equal(t, expectedRepoUser, repoUser)
@@ -50,6 +58,8 @@ func TestMongoUserRepositoryMapper_GivenRepositoryData_WhenMap_ThenMapToDomainDa
 		LastName:    "Bar",
 		Email:       "foobar@test.com",
 		IsActive:    true,
+		Status:      "active",
+		StatusDate:  now,
 		CreatedDate: now,
 		UpdatedDate: now,
 	}
@@ -60,9 +70,11 @@ func TestMongoUserRepositoryMapper_GivenRepositoryData_WhenMap_ThenMapToDomainDa
 			CreatedDate: now,
 			UpdatedDate: now,
 		},
-		FirstName: "Foo",
-		LastName:  "Bar",
-		Email:     "foobar@test.com",
+		FirstName:  "Foo",
+		LastName:   "Bar",
+		Email:      "foobar@test.com",
+		Status:     domain.UserStatusActive,
+		StatusDate: now,
 	}
 
 	mapper := NewDefaultMongoRepositoryMapper()
@@ -83,6 +95,8 @@ func TestMongoUserRepositoryMapper_GivenRepositoryDataList_WhenMap_ThenMapToDoma
 			LastName:    "Bar",
 			Email:       "foobar@test.com",
 			IsActive:    true,
+			Status:      "active",
+			StatusDate:  now,
 			CreatedDate: now,
 			UpdatedDate: now,
 		},
@@ -95,9 +109,11 @@ func TestMongoUserRepositoryMapper_GivenRepositoryDataList_WhenMap_ThenMapToDoma
 				CreatedDate: now,
 				UpdatedDate: now,
 			},
-			FirstName: "Foo",
-			LastName:  "Bar",
-			Email:     "foobar@test.com",
+			FirstName:  "Foo",
+			LastName:   "Bar",
+			Email:      "foobar@test.com",
+			Status:     domain.UserStatusActive,
+			StatusDate: now,
 		},
 	}
 
@@ -122,6 +138,8 @@ func TestMongoUserRepositoryMapper_GivenRepositorySearcuResults_WhenMap_ThenMapT
 			LastName:    "Bar",
 			Email:       "foobar@test.com",
 			IsActive:    true,
+			Status:      "active",
+			StatusDate:  now,
 			CreatedDate: now,
 			UpdatedDate: now,
 		},
@@ -134,9 +152,11 @@ func TestMongoUserRepositoryMapper_GivenRepositorySearcuResults_WhenMap_ThenMapT
 				CreatedDate: now,
 				UpdatedDate: now,
 			},
-			FirstName: "Foo",
-			LastName:  "Bar",
-			Email:     "foobar@test.com",
+			FirstName:  "Foo",
+			LastName:   "Bar",
+			Email:      "foobar@test.com",
+			Status:     domain.UserStatusActive,
+			StatusDate: now,
 		},
 	}
 	expectedDomainSearchOutput := domain.UserSearchOutput{
@@ -154,3 +174,72 @@ func TestMongoUserRepositoryMapper_GivenRepositorySearcuResults_WhenMap_ThenMapT
 	assert.NotNil(t, domainSearchOutput)
 	assert.Equal(t, expectedDomainSearchOutput, domainSearchOutput)
 }
+
+func TestMongoUserRepositoryMapper_GivenDomainDataWithProfile_WhenMap_ThenKeepTheProfile(t *testing.T) {
+	t.Log("Should map user domain profile to user repository data and back")
+
+	now := time.Now().UTC()
+	birthDate := time.Date(1990, 5, 17, 0, 0, 0, 0, time.UTC)
+	domainUser := domain.User{
+		GenericEntity: domain.GenericEntity{
+			Reference:   "USER1",
+			IsActive:    true,
+			CreatedDate: now,
+			UpdatedDate: now,
+		},
+		UserProfile: domain.UserProfile{
+			Phone:     "+5491112345678",
+			BirthDate: &birthDate,
+			Locale:    "es-AR",
+			Timezone:  "America/Argentina/Buenos_Aires",
+			Address:   &domain.Address{Line1: "Street 123", City: "Buenos Aires", PostalCode: "C1000", Country: "AR"},
+		},
+		FirstName:  "Foo",
+		LastName:   "Bar",
+		Email:      "foobar@test.com",
+		Avatar:     &domain.UserAvatar{Version: "V1", ContentType: "image/png", Sizes: []int{256, 64}, UpdatedDate: now},
+		Status:     domain.UserStatusActive,
+		StatusDate: now,
+	}
+
+	mapper := NewDefaultMongoRepositoryMapper()
+	repoUser, err := mapper.MapDomainToRepository(domainUser)
+
+	assert.Nil(t, err)
+
+	assert.Equal(t, "+5491112345678", repoUser.Phone)
+	assert.Equal(t, &MongoUserAvatar{Version: "V1", ContentType: "image/png", Sizes: []int{256, 64}, UpdatedDate: now}, repoUser.Avatar)
+	assert.Equal(t, &birthDate, repoUser.BirthDate)
+	assert.Equal(t, &MongoAddress{Line1: "Street 123", City: "Buenos Aires", PostalCode: "C1000", Country: "AR"}, repoUser.Address)
+	assert.Equal(t, domainUser, mapper.MapRepositoryToDomain(repoUser))
+}
+
+func TestMongoUserRepositoryMapper_GivenStoredAttributes_WhenMap_ThenReturnTheDomainTypes(t *testing.T) {
+	t.Log("Should map user repository attributes to the domain attribute types")
+
+	hiredOn := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	repoUser := MongoUser{
+		Reference: "USER1",
+		Attributes: bson.M{
+			"plan":       "pro",
+			"seats":      int32(10),
+			"licenses":   int64(3),
+			"newsletter": true,
+			"hiredOn":    primitive.NewDateTimeFromTime(hiredOn),
+		},
+	}
+
+	mapper := NewDefaultMongoRepositoryMapper()
+	domainUser := mapper.MapRepositoryToDomain(repoUser)
+
+	assert.Equal(t, map[string]interface{}{
+		"plan":       "pro",
+		"seats":      10.0,
+		"licenses":   3.0,
+		"newsletter": true,
+		"hiredOn":    hiredOn,
+	}, domainUser.Attributes)
+	emptyUser, err := mapper.MapDomainToRepository(domain.User{})
+	assert.Nil(t, err)
+	assert.Nil(t, emptyUser.Attributes)
+}
@@ -0,0 +1,79 @@
+package infrastructure_test
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/desarrollogj/golang-api-example/domain"
+	"github.com/desarrollogj/golang-api-example/infrastructure"
+	"github.com/desarrollogj/golang-api-example/infrastructure/usertest"
+	"github.com/desarrollogj/golang-api-example/libs/database"
+	"github.com/desarrollogj/golang-api-example/libs/encryption"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// TestMongoUserRepository_Contract runs the users repository contract against a MongoDB instance.
+// It is skipped unless MONGO_TEST_URI is set (for example, MONGO_TEST_URI=mongodb://localhost:27017/)
+func TestMongoUserRepository_Contract(t *testing.T) {
+	t.Log("Mongo repository should fulfill the users repository contract")
+
+	runMongoContract(t, infrastructure.NewDefaultMongoRepositoryMapper(), usertest.ContractOptions{})
+}
+
+// TestMongoUserRepository_EncryptedContract runs the users repository contract against a MongoDB instance, with the
+// personal data encrypted. It is skipped unless MONGO_TEST_URI is set
+func TestMongoUserRepository_EncryptedContract(t *testing.T) {
+	t.Log("Mongo repository with encrypted personal data should fulfill the users repository contract")
+
+	keyring, err := encryption.NewKeyring("KEY1", map[string][]byte{
+		"KEY1": []byte("0123456789abcdef0123456789abcdef"),
+	}, []byte("fedcba9876543210fedcba9876543210"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	mapper := infrastructure.NewEncryptedMongoRepositoryMapper(infrastructure.NewDefaultMongoRepositoryMapper(), keyring)
+
+	runMongoContract(t, mapper, usertest.ContractOptions{EncryptedPersonalData: true})
+}
+
+func runMongoContract(t *testing.T, mapper infrastructure.UserMongoRepositoryMapper, contractOptions usertest.ContractOptions) {
+	uri := os.Getenv("MONGO_TEST_URI")
+	if len(uri) == 0 {
+		t.Skip("MONGO_TEST_URI is not set")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	client, err := mongo.Connect(ctx, options.Client().ApplyURI(uri))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = client.Ping(ctx, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	previous := database.Mongo
+	database.Mongo = &database.MongoDB{Client: client}
+	config := domain.MongoRepositoryConfiguration{
+		Database:        "example_contract_test",
+		UsersCollection: "users",
+	}
+	t.Cleanup(func() {
+		client.Database(config.Database).Drop(context.Background())
+		client.Disconnect(context.Background())
+		database.Mongo = previous
+	})
+
+	usertest.RunUserRepositoryContract(t, func(t *testing.T) infrastructure.UserRepository {
+		if err := client.Database(config.Database).Collection(config.UsersCollection).Drop(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+		if err := infrastructure.EnsureUserIndexes(config); err != nil {
+			t.Fatal(err)
+		}
+		return infrastructure.NewMongoUserRepository(config, mapper)
+	}, contractOptions)
+}
@@ -0,0 +1,226 @@
+package infrastructure
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/desarrollogj/golang-api-example/domain"
+	"github.com/desarrollogj/golang-api-example/libs/database"
+	"github.com/desarrollogj/golang-api-example/libs/logger"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	// defaultSchemaReportLimit is the number of documents that violate the schema logged without a configured limit
+	defaultSchemaReportLimit = 20
+	// maxSchemaReportLimit is the maximum number of documents that violate the schema logged at startup
+	maxSchemaReportLimit = 1000
+)
+
+// userSchemaPatterns are the patterns applied to clear text fields. They can not be applied to encrypted fields
+var userSchemaPatterns = map[string]string{
+	"email": `^[^@\s]+@[^@\s]+\.[^@\s]+$`,
+	"phone": `^\+[1-9][0-9]{7,14}$`,
+}
+
+// userSchemaPlainPatterns are the patterns applied to fields that are never encrypted
+var userSchemaPlainPatterns = map[string]string{
+	"address.country": `^[A-Z]{2}$`,
+}
+
+// userSchemaStatuses are the allowed values of the user status
+var userSchemaStatuses = bson.A{
+	string(domain.UserStatusPending),
+	string(domain.UserStatusActive),
+	string(domain.UserStatusSuspended),
+	string(domain.UserStatusDeleted),
+}
+
+// UserMongoSchema returns the $jsonSchema of the users collection, derived from MongoUser.
+// Fields without omitempty are required. When the data is encrypted, the field patterns are not included
+func UserMongoSchema(encrypted bool) bson.M {
+	patterns := map[string]string{}
+	for path, pattern := range userSchemaPlainPatterns {
+		patterns[path] = pattern
+	}
+	if !encrypted {
+		for path, pattern := range userSchemaPatterns {
+			patterns[path] = pattern
+		}
+	}
+
+	schema := structSchema(reflect.TypeOf(MongoUser{}), "", patterns)
+	schema["properties"].(bson.M)["status"].(bson.M)["enum"] = userSchemaStatuses
+	return schema
+}
+
+// structSchema returns the schema of a struct. Patterns are keyed by the field path from the root document
+func structSchema(structType reflect.Type, path string, patterns map[string]string) bson.M {
+	required := bson.A{}
+	properties := bson.M{}
+
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		tag := strings.Split(field.Tag.Get("bson"), ",")
+		name := tag[0]
+		if len(name) == 0 || name == "-" {
+			continue
+		}
+
+		omitEmpty := false
+		for _, option := range tag[1:] {
+			omitEmpty = omitEmpty || option == "omitempty"
+		}
+		if !omitEmpty {
+			required = append(required, name)
+		}
+
+		fieldPath := name
+		if len(path) > 0 {
+			fieldPath = path + "." + name
+		}
+		property := fieldSchema(field.Type, fieldPath, patterns)
+		if pattern, ok := patterns[fieldPath]; ok {
+			property["pattern"] = pattern
+		}
+		properties[name] = property
+	}
+
+	schema := bson.M{
+		"bsonType":   "object",
+		"properties": properties,
+	}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
+
+func fieldSchema(fieldType reflect.Type, path string, patterns map[string]string) bson.M {
+	if fieldType.Kind() == reflect.Pointer {
+		fieldType = fieldType.Elem()
+	}
+
+	switch {
+	case fieldType == reflect.TypeOf(time.Time{}):
+		return bson.M{"bsonType": "date"}
+	case fieldType == reflect.TypeOf(primitive.ObjectID{}):
+		return bson.M{"bsonType": "objectId"}
+	case fieldType.Kind() == reflect.Slice && fieldType.Elem().Kind() == reflect.Uint8:
+		return bson.M{"bsonType": "binData"}
+	case fieldType.Kind() == reflect.Slice:
+		return bson.M{"bsonType": "array", "items": fieldSchema(fieldType.Elem(), path, patterns)}
+	case fieldType.Kind() == reflect.Struct:
+		return structSchema(fieldType, path, patterns)
+	case fieldType.Kind() == reflect.Map:
+		return bson.M{"bsonType": "object"}
+	case fieldType.Kind() == reflect.String:
+		return bson.M{"bsonType": "string"}
+	case fieldType.Kind() == reflect.Bool:
+		return bson.M{"bsonType": "bool"}
+	case fieldType.Kind() >= reflect.Int && fieldType.Kind() <= reflect.Uint64:
+		return bson.M{"bsonType": bson.A{"int", "long"}}
+	case fieldType.Kind() == reflect.Float32 || fieldType.Kind() == reflect.Float64:
+		return bson.M{"bsonType": "double"}
+	default:
+		return bson.M{}
+	}
+}
+
+// ApplyUserSchema applies the users $jsonSchema validator to the users collection (creating it if it does not exist)
+// and reports the existing documents that violate it
+func ApplyUserSchema(config domain.MongoRepositoryConfiguration, validation domain.SchemaValidationConfiguration, encrypted bool) error {
+	if validation.Level != "off" && validation.Level != "moderate" && validation.Level != "strict" {
+		return fmt.Errorf("schema validation level %s is not valid", validation.Level)
+	}
+	if validation.Action != "error" && validation.Action != "warn" {
+		return fmt.Errorf("schema validation action %s is not valid", validation.Action)
+	}
+
+	client := database.Mongo.Client
+	db := client.Database(config.Database)
+	schema := UserMongoSchema(encrypted)
+	validator := bson.M{"$jsonSchema": schema}
+
+	names, err := db.ListCollectionNames(context.TODO(), bson.D{{Key: "name", Value: config.UsersCollection}})
+	if err != nil {
+		return fmt.Errorf("unable to list collections: %w", err)
+	}
+
+	if len(names) == 0 {
+		opts := options.CreateCollection().
+			SetValidator(validator).
+			SetValidationLevel(validation.Level).
+			SetValidationAction(validation.Action)
+		if err = db.CreateCollection(context.TODO(), config.UsersCollection, opts); err != nil {
+			return fmt.Errorf("unable to create users collection: %w", err)
+		}
+	} else {
+		command := bson.D{
+			{Key: "collMod", Value: config.UsersCollection},
+			{Key: "validator", Value: validator},
+			{Key: "validationLevel", Value: validation.Level},
+			{Key: "validationAction", Value: validation.Action},
+		}
+		if err = db.RunCommand(context.TODO(), command).Err(); err != nil {
+			return fmt.Errorf("unable to apply users schema: %w", err)
+		}
+	}
+	logger.AppLog.Info().Str("level", validation.Level).Str("action", validation.Action).Msg("users schema applied")
+
+	return reportUserSchemaViolations(config, validation, schema)
+}
+
+// schemaReportLimit returns the number of documents that violate the schema to log. Without limit, the default limit is
+// used, since a zero limit would log every document of the collection. Larger limits are reduced to the maximum
+func schemaReportLimit(configured int) int64 {
+	if configured <= 0 {
+		return defaultSchemaReportLimit
+	}
+	if configured > maxSchemaReportLimit {
+		return maxSchemaReportLimit
+	}
+
+	return int64(configured)
+}
+
+// reportUserSchemaViolations logs the number of documents that violate the schema and the references of some of them
+func reportUserSchemaViolations(config domain.MongoRepositoryConfiguration, validation domain.SchemaValidationConfiguration, schema bson.M) error {
+	client := database.Mongo.Client
+	collection := client.Database(config.Database).Collection(config.UsersCollection)
+
+	filter := bson.D{{Key: "$nor", Value: bson.A{bson.D{{Key: "$jsonSchema", Value: schema}}}}}
+	total, err := collection.CountDocuments(context.TODO(), filter)
+	if err != nil {
+		return fmt.Errorf("unable to count users that violate the schema: %w", err)
+	}
+	if total == 0 {
+		return nil
+	}
+
+	opts := options.Find().SetLimit(schemaReportLimit(validation.ReportLimit)).SetProjection(bson.D{{Key: "reference", Value: 1}})
+	cur, err := collection.Find(context.TODO(), filter, opts)
+	if err != nil {
+		return fmt.Errorf("unable to find users that violate the schema: %w", err)
+	}
+
+	invalid := []struct {
+		ID        primitive.ObjectID `bson:"_id"`
+		Reference string             `bson:"reference"`
+	}{}
+	if err = cur.All(context.TODO(), &invalid); err != nil {
+		return fmt.Errorf("unable to find users that violate the schema: %w", err)
+	}
+
+	for _, document := range invalid {
+		logger.AppLog.Warn().Str("id", document.ID.Hex()).Str("reference", document.Reference).Msg("user violates the users schema")
+	}
+	logger.AppLog.Warn().Int64("total", total).Msg("existing users violate the users schema")
+
+	return nil
+}
@@ -0,0 +1,85 @@
+package infrastructure
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestUserMongoSchema_GivenPlainData_WhenDerive_ThenReturnSchemaWithPatterns(t *testing.T) {
+	t.Log("Should derive the users schema from the repository entity")
+
+	schema := UserMongoSchema(false)
+
+	assert.Equal(t, "object", schema["bsonType"])
+	assert.Equal(t, bson.A{"_id", "reference", "first_name", "last_name", "email", "is_active", "status", "status_date", "created_date", "updated_date"}, schema["required"])
+
+	properties := schema["properties"].(bson.M)
+	assert.Equal(t, bson.M{"bsonType": "objectId"}, properties["_id"])
+	assert.Equal(t, bson.M{"bsonType": "string"}, properties["reference"])
+	assert.Equal(t, bson.M{"bsonType": "string", "pattern": userSchemaPatterns["email"]}, properties["email"])
+	assert.Equal(t, bson.M{"bsonType": "bool"}, properties["is_active"])
+	assert.Equal(t, bson.M{"bsonType": "string", "enum": bson.A{"pending", "active", "suspended", "deleted"}}, properties["status"])
+	assert.Equal(t, bson.M{"bsonType": "date"}, properties["created_date"])
+	assert.Equal(t, bson.M{"bsonType": "date"}, properties["erased_date"])
+	assert.Equal(t, bson.M{
+		"bsonType": "object",
+		"required": bson.A{"key_id", "data_key"},
+		"properties": bson.M{
+			"key_id":   bson.M{"bsonType": "string"},
+			"data_key": bson.M{"bsonType": "binData"},
+		},
+	}, properties["encryption"])
+}
+
+func TestUserMongoSchema_GivenEncryptedData_WhenDerive_ThenReturnSchemaWithoutPatterns(t *testing.T) {
+	t.Log("Should derive the users schema without patterns when the data is encrypted")
+
+	schema := UserMongoSchema(true)
+
+	properties := schema["properties"].(bson.M)
+	assert.Equal(t, bson.M{"bsonType": "string"}, properties["email"])
+}
+
+func TestUserSchemaPatterns_GivenAnEmail_WhenMatch_ThenValidateTheEmail(t *testing.T) {
+	t.Log("Email pattern should accept emails and the erased users tombstone")
+
+	pattern := regexp.MustCompile(userSchemaPatterns["email"])
+
+	assert.True(t, pattern.MatchString("foobar@email.com"))
+	assert.True(t, pattern.MatchString("erased-USER1@erased.invalid"))
+	assert.False(t, pattern.MatchString("foobar"))
+	assert.False(t, pattern.MatchString("foo bar@email.com"))
+}
+
+func TestUserSchemaPatterns_GivenAPhone_WhenMatch_ThenValidateThePhone(t *testing.T) {
+	t.Log("Phone pattern should accept only E.164 numbers")
+
+	pattern := regexp.MustCompile(userSchemaPatterns["phone"])
+
+	assert.True(t, pattern.MatchString("+5491112345678"))
+	assert.False(t, pattern.MatchString("1112345678"))
+	assert.False(t, pattern.MatchString("+54 11 1234 5678"))
+}
+
+func TestUserMongoSchema_GivenPlainData_WhenDerive_ThenReturnAddressSchema(t *testing.T) {
+	t.Log("Should derive the users address schema with the country pattern")
+
+	plain := UserMongoSchema(false)["properties"].(bson.M)["address"].(bson.M)
+	encrypted := UserMongoSchema(true)["properties"].(bson.M)["address"].(bson.M)
+
+	assert.Equal(t, bson.A{"line1", "city", "country"}, plain["required"])
+	assert.Equal(t, bson.M{"bsonType": "string", "pattern": "^[A-Z]{2}$"}, plain["properties"].(bson.M)["country"])
+	assert.Equal(t, bson.M{"bsonType": "string", "pattern": "^[A-Z]{2}$"}, encrypted["properties"].(bson.M)["country"])
+}
+
+func TestSchemaReportLimit_GivenALimit_WhenResolve_ThenReturnABoundedLimit(t *testing.T) {
+	t.Log("Should log a bounded number of documents that violate the schema, using the default without limit")
+
+	assert.Equal(t, int64(20), schemaReportLimit(0))
+	assert.Equal(t, int64(20), schemaReportLimit(-1))
+	assert.Equal(t, int64(5), schemaReportLimit(5))
+	assert.Equal(t, int64(1000), schemaReportLimit(5000))
+}
@@ -0,0 +1,39 @@
+package infrastructure
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/desarrollogj/golang-api-example/domain"
+	"github.com/desarrollogj/golang-api-example/libs/database"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// userStatusMigration sets the status of the users stored before the status was added:
+// active users are active and inactive users are deleted. The status date is their update date
+var userStatusMigration = mongo.Pipeline{
+	{{Key: "$set", Value: bson.D{
+		{Key: "status", Value: bson.D{{Key: "$cond", Value: bson.A{
+			"$is_active",
+			string(domain.UserStatusActive),
+			string(domain.UserStatusDeleted),
+		}}}},
+		{Key: "status_date", Value: "$updated_date"},
+	}}},
+}
+
+// MigrateUserStatus maps the is_active value of the users without status to a status.
+// It returns the number of migrated users. Running it again has no effect
+func MigrateUserStatus(config domain.MongoRepositoryConfiguration) (int64, error) {
+	client := database.Mongo.Client
+	collection := client.Database(config.Database).Collection(config.UsersCollection)
+
+	filter := bson.D{{Key: "status", Value: bson.D{{Key: "$exists", Value: false}}}}
+	result, err := collection.UpdateMany(context.TODO(), filter, userStatusMigration)
+	if err != nil {
+		return 0, fmt.Errorf("unable to migrate users status: %w", err)
+	}
+
+	return result.ModifiedCount, nil
+}
@@ -0,0 +1,883 @@
+package usertest
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/desarrollogj/golang-api-example/domain"
+	"github.com/desarrollogj/golang-api-example/infrastructure"
+	"github.com/stretchr/testify/assert"
+)
+
+// RepositoryFactory creates an empty repository for each contract test
+type RepositoryFactory func(t *testing.T) infrastructure.UserRepository
+
+// ContractOptions are the behaviors that differ between the UserRepository configurations
+type ContractOptions struct {
+	// EncryptedPersonalData is true when the personal data is stored encrypted. The names can not be searched,
+	// and the emails are only matched completely
+	EncryptedPersonalData bool
+}
+
+// RunUserRepositoryContract verifies that an UserRepository implementation behaves as the user use cases assume
+func RunUserRepositoryContract(t *testing.T, newRepository RepositoryFactory, options ContractOptions) {
+	t.Run("Create and find by reference", func(t *testing.T) {
+		repository := newRepository(t)
+		user := newUser("USER1", "Foo", "Bar", "foobar@email.com", true)
+
+		created, err := repository.Create(user)
+		assert.Nil(t, err)
+		assertUser(t, user, created)
+
+		found, err := repository.FindByReference("USER1")
+		assert.Nil(t, err)
+		assertUser(t, user, found)
+
+		found, err = repository.FindActiveByReference("USER1")
+		assert.Nil(t, err)
+		assertUser(t, user, found)
+	})
+
+	t.Run("Find by reference returns an empty user when it was not found", func(t *testing.T) {
+		repository := newRepository(t)
+
+		found, err := repository.FindByReference("UNKNOWN")
+		assert.Nil(t, err)
+		assert.Equal(t, "", found.Reference)
+
+		found, err = repository.FindActiveByReference("UNKNOWN")
+		assert.Nil(t, err)
+		assert.Equal(t, "", found.Reference)
+	})
+
+	t.Run("Find active by reference ignores inactive users", func(t *testing.T) {
+		repository := newRepository(t)
+		user := newUser("USER1", "Foo", "Bar", "foobar@email.com", false)
+		repository.Create(user)
+
+		found, err := repository.FindActiveByReference("USER1")
+		assert.Nil(t, err)
+		assert.Equal(t, "", found.Reference)
+
+		found, err = repository.FindByReference("USER1")
+		assert.Nil(t, err)
+		assertUser(t, user, found)
+	})
+
+	t.Run("Find all active ignores inactive users", func(t *testing.T) {
+		repository := newRepository(t)
+		repository.Create(newUser("USER1", "Foo", "Bar", "foobar@email.com", true))
+		repository.Create(newUser("USER2", "Foo", "Bar", "foobar@email.com", false))
+
+		users, err := repository.FindAllActive()
+		assert.Nil(t, err)
+		assert.Equal(t, []string{"USER1"}, references(users))
+	})
+
+	t.Run("Find all active returns an empty list when there are no users", func(t *testing.T) {
+		repository := newRepository(t)
+
+		users, err := repository.FindAllActive()
+		assert.Nil(t, err)
+		assert.NotNil(t, users)
+		assert.Empty(t, users)
+	})
+
+	t.Run("Update replaces the user data", func(t *testing.T) {
+		repository := newRepository(t)
+		user := newUser("USER1", "Foo", "Bar", "foobar@email.com", true)
+		repository.Create(user)
+
+		user.FirstName = "Another Foo"
+		user.LastName = "Another Bar"
+		user.Email = "anotherfoobar@email.com"
+		user.UpdatedDate = user.UpdatedDate.Add(time.Minute)
+		updated, err := repository.Update(user)
+		assert.Nil(t, err)
+		assertUser(t, user, updated)
+
+		found, _ := repository.FindByReference("USER1")
+		assertUser(t, user, found)
+	})
+
+	t.Run("Update fails when the user does not exist", func(t *testing.T) {
+		repository := newRepository(t)
+
+		_, err := repository.Update(newUser("UNKNOWN", "Foo", "Bar", "foobar@email.com", true))
+		assert.NotNil(t, err)
+	})
+
+	t.Run("Patch updates the changed user data", func(t *testing.T) {
+		repository := newRepository(t)
+		user := newUser("USER1", "Foo", "Bar", "foobar@email.com", true)
+		user.Locale = "es-AR"
+		repository.Create(user)
+
+		user.FirstName = "Another Foo"
+		user.Locale = ""
+		user.Phone = "+5491112345678"
+		user.UpdatedDate = user.UpdatedDate.Add(time.Minute)
+		patched, err := repository.Patch(user)
+		assert.Nil(t, err)
+		assertUser(t, user, patched)
+
+		found, _ := repository.FindByReference("USER1")
+		assertUser(t, user, found)
+	})
+
+	t.Run("Update and patch refuse an user whose personal data could not be decrypted", func(t *testing.T) {
+		repository := newRepository(t)
+		user := newUser("USER1", "Foo", "Bar", "foobar@email.com", true)
+		repository.Create(user)
+
+		undecryptable := user
+		undecryptable.FirstName = ""
+		undecryptable.Email = ""
+		undecryptable.Undecryptable = true
+		undecryptable.UpdatedDate = user.UpdatedDate.Add(time.Minute)
+		_, err := repository.Update(undecryptable)
+		assert.Equal(t, infrastructure.ErrUndecryptableUser, err)
+		_, err = repository.Patch(undecryptable)
+		assert.Equal(t, infrastructure.ErrUndecryptableUser, err)
+
+		found, _ := repository.FindByReference("USER1")
+		assertUser(t, user, found)
+	})
+
+	t.Run("Patch fails when the user does not exist", func(t *testing.T) {
+		repository := newRepository(t)
+
+		_, err := repository.Patch(newUser("UNKNOWN", "Foo", "Bar", "foobar@email.com", true))
+		assert.NotNil(t, err)
+	})
+
+	t.Run("Status is preserved", func(t *testing.T) {
+		repository := newRepository(t)
+		user := newUser("USER1", "Foo", "Bar", "foobar@email.com", false)
+		user.Status = domain.UserStatusSuspended
+		user.StatusReason = "abuse report"
+		repository.Create(user)
+
+		found, _ := repository.FindByReference("USER1")
+		assertUser(t, user, found)
+
+		found, _ = repository.FindActiveByReference("USER1")
+		assert.Equal(t, "", found.Reference)
+	})
+
+	t.Run("Email verification is preserved", func(t *testing.T) {
+		repository := newRepository(t)
+		user := newUser("USER1", "Foo", "Bar", "foobar@email.com", true)
+		sent := user.CreatedDate
+		user.EmailVerificationSentDate = &sent
+		repository.Create(user)
+
+		verified := user.UpdatedDate.Add(time.Minute)
+		user.EmailVerifiedDate = &verified
+		user.UpdatedDate = verified
+		repository.Update(user)
+
+		found, _ := repository.FindByReference("USER1")
+		assertUser(t, user, found)
+	})
+
+	t.Run("Roles are preserved", func(t *testing.T) {
+		repository := newRepository(t)
+		user := newUser("USER1", "Foo", "Bar", "foobar@email.com", true)
+		repository.Create(user)
+
+		user.Roles = []string{"admin"}
+		user.UpdatedDate = user.UpdatedDate.Add(time.Minute)
+		repository.Update(user)
+
+		found, _ := repository.FindByReference("USER1")
+		assertUser(t, user, found)
+	})
+
+	t.Run("Avatar is preserved", func(t *testing.T) {
+		repository := newRepository(t)
+		user := newUser("USER1", "Foo", "Bar", "foobar@email.com", true)
+		repository.Create(user)
+
+		user.UpdatedDate = user.UpdatedDate.Add(time.Minute)
+		user.Avatar = &domain.UserAvatar{Version: "V1", ContentType: "image/png", Sizes: []int{256, 64}, UpdatedDate: user.UpdatedDate}
+		repository.Update(user)
+
+		found, _ := repository.FindByReference("USER1")
+		assertUser(t, user, found)
+	})
+
+	t.Run("Attributes are preserved", func(t *testing.T) {
+		repository := newRepository(t)
+		user := newUser("USER1", "Foo", "Bar", "foobar@email.com", true)
+		repository.Create(user)
+
+		user.UpdatedDate = user.UpdatedDate.Add(time.Minute)
+		user.Attributes = map[string]interface{}{
+			"plan":       "pro",
+			"seats":      10.0,
+			"newsletter": true,
+			"hiredOn":    time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+		}
+		repository.Update(user)
+
+		found, _ := repository.FindByReference("USER1")
+		assertUser(t, user, found)
+	})
+
+	t.Run("Tags are preserved", func(t *testing.T) {
+		repository := newRepository(t)
+		user := newUser("USER1", "Foo", "Bar", "foobar@email.com", true)
+		user.Tags = []string{"beta", "vip"}
+		repository.Create(user)
+
+		found, _ := repository.FindByReference("USER1")
+		assertUser(t, user, found)
+	})
+
+	t.Run("AddTag adds the tag sorted and keeps the other user data", func(t *testing.T) {
+		repository := newRepository(t)
+		user := newUser("USER1", "Foo", "Bar", "foobar@email.com", true)
+		user.Tags = []string{"beta", "vip"}
+		repository.Create(user)
+
+		updated := user.UpdatedDate.Add(time.Minute)
+		ok, err := repository.AddTag("USER1", "partner", 3, updated)
+		assert.Nil(t, err)
+		assert.True(t, ok)
+
+		user.Tags = []string{"beta", "partner", "vip"}
+		user.UpdatedDate = updated
+		found, _ := repository.FindByReference("USER1")
+		assertUser(t, user, found)
+	})
+
+	t.Run("AddTag does not change the user when it has the tag or the maximum tags", func(t *testing.T) {
+		repository := newRepository(t)
+		user := newUser("USER1", "Foo", "Bar", "foobar@email.com", true)
+		user.Tags = []string{"beta", "vip"}
+		repository.Create(user)
+
+		ok, err := repository.AddTag("USER1", "vip", 3, user.UpdatedDate.Add(time.Minute))
+		assert.Nil(t, err)
+		assert.False(t, ok)
+
+		ok, err = repository.AddTag("USER1", "partner", 2, user.UpdatedDate.Add(time.Minute))
+		assert.Nil(t, err)
+		assert.False(t, ok)
+
+		found, _ := repository.FindByReference("USER1")
+		assertUser(t, user, found)
+	})
+
+	t.Run("AddTag does not change an erased user", func(t *testing.T) {
+		repository := newRepository(t)
+		user := newUser("USER1", "Foo", "Bar", "foobar@email.com", false)
+		erased := user.UpdatedDate
+		user.ErasedDate = &erased
+		repository.Create(user)
+
+		ok, err := repository.AddTag("USER1", "vip", 3, user.UpdatedDate.Add(time.Minute))
+		assert.Nil(t, err)
+		assert.False(t, ok)
+	})
+
+	t.Run("RemoveTag removes the tag and keeps the other user data", func(t *testing.T) {
+		repository := newRepository(t)
+		user := newUser("USER1", "Foo", "Bar", "foobar@email.com", true)
+		user.Tags = []string{"beta", "vip"}
+		repository.Create(user)
+
+		updated := user.UpdatedDate.Add(time.Minute)
+		ok, err := repository.RemoveTag("USER1", "vip", updated)
+		assert.Nil(t, err)
+		assert.True(t, ok)
+
+		user.Tags = []string{"beta"}
+		user.UpdatedDate = updated
+		found, _ := repository.FindByReference("USER1")
+		assertUser(t, user, found)
+
+		ok, err = repository.RemoveTag("USER1", "beta", updated)
+		assert.Nil(t, err)
+		assert.True(t, ok)
+
+		found, _ = repository.FindByReference("USER1")
+		assert.Empty(t, found.Tags)
+	})
+
+	t.Run("RemoveTag does not change the user when it does not have the tag", func(t *testing.T) {
+		repository := newRepository(t)
+		user := newUser("USER1", "Foo", "Bar", "foobar@email.com", true)
+		user.Tags = []string{"beta"}
+		repository.Create(user)
+
+		ok, err := repository.RemoveTag("USER1", "vip", user.UpdatedDate.Add(time.Minute))
+		assert.Nil(t, err)
+		assert.False(t, ok)
+
+		found, _ := repository.FindByReference("USER1")
+		assertUser(t, user, found)
+	})
+
+	t.Run("External ids are preserved", func(t *testing.T) {
+		repository := newRepository(t)
+		user := newUser("USER1", "Foo", "Bar", "foobar@email.com", true)
+		user.ExternalIDs = []domain.UserExternalID{{Source: "crm", ID: "C-1"}, {Source: "erp", ID: "42"}}
+		repository.Create(user)
+
+		found, _ := repository.FindByReference("USER1")
+		assertUser(t, user, found)
+	})
+
+	t.Run("Find by external id returns the user, active or not", func(t *testing.T) {
+		repository := newRepository(t)
+		user1 := newUser("USER1", "Foo", "Bar", "foobar@email.com", true)
+		user1.ExternalIDs = []domain.UserExternalID{{Source: "crm", ID: "C-1"}, {Source: "erp", ID: "42"}}
+		repository.Create(user1)
+		user2 := newUser("USER2", "John", "Doe", "johndoe@email.com", false)
+		user2.ExternalIDs = []domain.UserExternalID{{Source: "crm", ID: "C-2"}}
+		repository.Create(user2)
+
+		found, err := repository.FindByExternalID("erp", "42")
+		assert.Nil(t, err)
+		assertUser(t, user1, found)
+
+		found, err = repository.FindByExternalID("crm", "C-2")
+		assert.Nil(t, err)
+		assertUser(t, user2, found)
+
+		// The source and the id of a pair are not mixed between pairs
+		found, err = repository.FindByExternalID("crm", "42")
+		assert.Nil(t, err)
+		assert.Equal(t, "", found.Reference)
+	})
+
+	t.Run("External ids belong to a single user", func(t *testing.T) {
+		repository := newRepository(t)
+		user1 := newUser("USER1", "Foo", "Bar", "foobar@email.com", true)
+		user1.ExternalIDs = []domain.UserExternalID{{Source: "crm", ID: "C-1"}, {Source: "erp", ID: "42"}}
+		repository.Create(user1)
+
+		user2 := newUser("USER2", "John", "Doe", "johndoe@email.com", true)
+		user2.ExternalIDs = []domain.UserExternalID{{Source: "erp", ID: "42"}}
+		_, err := repository.Create(user2)
+		assert.Equal(t, infrastructure.ErrDuplicateExternalID, err)
+
+		// Another pair with a source and an id used in different pairs is allowed
+		user2.ExternalIDs = []domain.UserExternalID{{Source: "crm", ID: "42"}}
+		_, err = repository.Create(user2)
+		assert.Nil(t, err)
+
+		user2.UpdatedDate = user2.UpdatedDate.Add(time.Minute)
+		user2.ExternalIDs = []domain.UserExternalID{{Source: "crm", ID: "C-1"}}
+		_, err = repository.Update(user2)
+		assert.Equal(t, infrastructure.ErrDuplicateExternalID, err)
+
+		// The user can store its own external ids again
+		user1.UpdatedDate = user1.UpdatedDate.Add(time.Minute)
+		_, err = repository.Update(user1)
+		assert.Nil(t, err)
+	})
+
+	t.Run("Merge pointer is preserved", func(t *testing.T) {
+		repository := newRepository(t)
+		user := newUser("USER1", "Foo", "Bar", "foobar@email.com", true)
+		repository.Create(user)
+
+		user.UpdatedDate = user.UpdatedDate.Add(time.Minute)
+		user.IsActive = false
+		user.Status = domain.UserStatusDeleted
+		user.MergedInto = "USER2"
+		repository.Update(user)
+
+		found, _ := repository.FindByReference("USER1")
+		assertUser(t, user, found)
+	})
+
+	t.Run("Change status stores the user with its new status", func(t *testing.T) {
+		repository := newRepository(t)
+		user := newUser("USER1", "Foo", "Bar", "foobar@email.com", true)
+		repository.Create(user)
+
+		user.IsActive = false
+		user.Status = domain.UserStatusDeleted
+		user.StatusDate = user.UpdatedDate.Add(time.Minute)
+		user.UpdatedDate = user.StatusDate
+		changed, err := repository.ChangeStatus(user, domain.UserStatusActive)
+		assert.Nil(t, err)
+		assert.Equal(t, domain.UserStatusDeleted, changed.Status)
+
+		found, _ := repository.FindActiveByReference("USER1")
+		assert.Equal(t, "", found.Reference)
+
+		found, _ = repository.FindByReference("USER1")
+		assert.Equal(t, "USER1", found.Reference)
+		assert.False(t, found.IsActive)
+		assert.Equal(t, domain.UserStatusDeleted, found.Status)
+		assert.Equal(t, "Foo", found.FirstName)
+	})
+
+	t.Run("Change status releases the external ids removed from the user", func(t *testing.T) {
+		repository := newRepository(t)
+		user := newUser("USER1", "Foo", "Bar", "foobar@email.com", true)
+		user.ExternalIDs = []domain.UserExternalID{{Source: "crm", ID: "C-1"}}
+		repository.Create(user)
+
+		user.IsActive = false
+		user.Status = domain.UserStatusDeleted
+		user.ExternalIDs = nil
+		_, err := repository.ChangeStatus(user, domain.UserStatusActive)
+		assert.Nil(t, err)
+
+		found, _ := repository.FindByExternalID("crm", "C-1")
+		assert.Equal(t, "", found.Reference)
+
+		other := newUser("USER2", "Foo", "Bar", "another@email.com", true)
+		other.ExternalIDs = []domain.UserExternalID{{Source: "crm", ID: "C-1"}}
+		_, err = repository.Create(other)
+		assert.Nil(t, err)
+	})
+
+	t.Run("Update releases the external ids removed from a deleted user", func(t *testing.T) {
+		repository := newRepository(t)
+		user := newUser("USER1", "Foo", "Bar", "foobar@email.com", false)
+		user.Status = domain.UserStatusDeleted
+		user.ExternalIDs = []domain.UserExternalID{{Source: "crm", ID: "C-1"}}
+		repository.Create(user)
+
+		user.ExternalIDs = nil
+		user.UpdatedDate = user.UpdatedDate.Add(time.Minute)
+		_, err := repository.Update(user)
+		assert.Nil(t, err)
+
+		other := newUser("USER2", "Foo", "Bar", "another@email.com", true)
+		other.ExternalIDs = []domain.UserExternalID{{Source: "crm", ID: "C-1"}}
+		_, err = repository.Create(other)
+		assert.Nil(t, err)
+	})
+
+	t.Run("Change status fails when the user status changed meanwhile", func(t *testing.T) {
+		repository := newRepository(t)
+		user := newUser("USER1", "Foo", "Bar", "foobar@email.com", true)
+		repository.Create(user)
+
+		suspended := user
+		suspended.Status = domain.UserStatusSuspended
+		_, err := repository.ChangeStatus(suspended, domain.UserStatusActive)
+		assert.Nil(t, err)
+
+		deleted := user
+		deleted.IsActive = false
+		deleted.Status = domain.UserStatusDeleted
+		_, err = repository.ChangeStatus(deleted, domain.UserStatusActive)
+		assert.Equal(t, infrastructure.ErrUserModified, err)
+
+		found, _ := repository.FindByReference("USER1")
+		assert.Equal(t, domain.UserStatusSuspended, found.Status)
+	})
+
+	t.Run("Change status fails when the user does not exist", func(t *testing.T) {
+		repository := newRepository(t)
+
+		_, err := repository.ChangeStatus(newUser("UNKNOWN", "Foo", "Bar", "foobar@email.com", true), domain.UserStatusActive)
+		assert.NotNil(t, err)
+	})
+
+	t.Run("Find all active by email matches the complete email ignoring the case", func(t *testing.T) {
+		repository := newRepository(t)
+		user := newUser("USER1", "Foo", "Bar", "foobar@email.com", true)
+		repository.Create(user)
+		repository.Create(newUser("USER2", "Foo", "Bar", "inactive@email.com", false))
+
+		found, err := repository.FindAllActiveByEmail("FooBar@Email.com")
+		assert.Nil(t, err)
+		assert.Len(t, found, 1)
+		assertUser(t, user, found[0])
+
+		found, _ = repository.FindAllActiveByEmail("foobar@email")
+		assert.Empty(t, found)
+
+		found, _ = repository.FindAllActiveByEmail("inactive@email.com")
+		assert.Empty(t, found)
+	})
+
+	if !options.EncryptedPersonalData {
+		t.Run("Find all active by email returns every user with a shared email", func(t *testing.T) {
+			repository := newRepository(t)
+			second := newUser("USER2", "Foo", "Bar", "foobar@email.com", true)
+			first := newUser("USER1", "Foo", "Bar", "FooBar@email.com", true)
+			repository.Create(second)
+			repository.Create(first)
+
+			found, err := repository.FindAllActiveByEmail("foobar@email.com")
+			assert.Nil(t, err)
+			assert.Len(t, found, 2)
+			assertUser(t, first, found[0])
+			assertUser(t, second, found[1])
+		})
+	}
+
+	t.Run("Search active pages the results", func(t *testing.T) {
+		repository := newRepository(t)
+		for i := 1; i <= 5; i++ {
+			repository.Create(newUser(fmt.Sprintf("USER%d", i), "Foo", "Bar", "foobar@email.com", true))
+		}
+		repository.Create(newUser("USER6", "Foo", "Bar", "foobar@email.com", false))
+
+		all := []string{}
+		for page, size := range map[int]int{1: 2, 2: 2, 3: 1} {
+			output, err := repository.Search(newSearchInput(page, 2))
+			assert.Nil(t, err)
+			assert.Equal(t, int64(5), output.Total)
+			assert.Equal(t, page, output.Page)
+			assert.Equal(t, 2, output.PageSize)
+			assert.Len(t, output.Users, size)
+			all = append(all, references(output.Users)...)
+		}
+		assert.ElementsMatch(t, []string{"USER1", "USER2", "USER3", "USER4", "USER5"}, all)
+
+		output, err := repository.Search(newSearchInput(4, 2))
+		assert.Nil(t, err)
+		assert.Equal(t, int64(5), output.Total)
+		assert.Empty(t, output.Users)
+	})
+
+	t.Run("Search filters by case insensitive prefix", func(t *testing.T) {
+		if options.EncryptedPersonalData {
+			t.Skip("the encrypted names and emails can not be matched by prefix")
+		}
+		repository := newRepository(t)
+		repository.Create(newUser("USER1", "Foo", "Bar", "foobar@email.com", true))
+		repository.Create(newUser("USER2", "Fred", "Baz", "fredbaz@email.com", true))
+		repository.Create(newUser("USER3", "John", "Bar", "johnbar@email.com", true))
+		repository.Create(newUser("USER4", "Foo", "Bar", "inactive@email.com", false))
+
+		input := newSearchInput(1, 10)
+		input.FirstName = "f"
+		output, _ := repository.Search(input)
+		assert.ElementsMatch(t, []string{"USER1", "USER2"}, references(output.Users))
+		assert.Equal(t, int64(2), output.Total)
+
+		input = newSearchInput(1, 10)
+		input.LastName = "BAR"
+		output, _ = repository.Search(input)
+		assert.ElementsMatch(t, []string{"USER1", "USER3"}, references(output.Users))
+
+		input = newSearchInput(1, 10)
+		input.FirstName = "Fo"
+		input.LastName = "Ba"
+		output, _ = repository.Search(input)
+		assert.ElementsMatch(t, []string{"USER1"}, references(output.Users))
+
+		input = newSearchInput(1, 10)
+		input.Email = "fredbaz@"
+		output, _ = repository.Search(input)
+		assert.ElementsMatch(t, []string{"USER2"}, references(output.Users))
+
+		input = newSearchInput(1, 10)
+		input.LastName = "oo"
+		output, _ = repository.Search(input)
+		assert.Empty(t, output.Users)
+	})
+
+	t.Run("Create rejects the encrypted email of another active user", func(t *testing.T) {
+		if !options.EncryptedPersonalData {
+			t.Skip("the personal data is not encrypted")
+		}
+		repository := newRepository(t)
+		repository.Create(newUser("USER1", "Foo", "Bar", "foobar@email.com", true))
+		repository.Create(newUser("USER2", "Foo", "Bar", "inactive@email.com", false))
+
+		_, err := repository.Create(newUser("USER3", "Fred", "Baz", "FooBar@Email.com", true))
+		assert.Equal(t, infrastructure.ErrDuplicateEmail, err)
+
+		_, err = repository.Create(newUser("USER4", "Fred", "Baz", "inactive@email.com", true))
+		assert.Nil(t, err)
+	})
+
+	t.Run("Search by encrypted personal data rejects the names and matches the complete email", func(t *testing.T) {
+		if !options.EncryptedPersonalData {
+			t.Skip("the personal data is not encrypted")
+		}
+		repository := newRepository(t)
+		repository.Create(newUser("USER1", "Foo", "Bar", "foobar@email.com", true))
+		repository.Create(newUser("USER2", "Fred", "Baz", "fredbaz@email.com", true))
+
+		input := newSearchInput(1, 10)
+		input.FirstName = "Foo"
+		_, err := repository.Search(input)
+		assert.Equal(t, infrastructure.ErrEncryptedNameSearch, err)
+
+		input = newSearchInput(1, 10)
+		input.LastName = "Bar"
+		_, err = repository.Search(input)
+		assert.Equal(t, infrastructure.ErrEncryptedNameSearch, err)
+
+		input = newSearchInput(1, 10)
+		input.Email = "FredBaz@Email.com"
+		output, err := repository.Search(input)
+		assert.Nil(t, err)
+		assert.ElementsMatch(t, []string{"USER2"}, references(output.Users))
+		assert.Equal(t, "Fred", output.Users[0].FirstName)
+
+		input = newSearchInput(1, 10)
+		input.Email = "fredbaz@"
+		output, err = repository.Search(input)
+		assert.Nil(t, err)
+		assert.Empty(t, output.Users)
+	})
+
+	t.Run("Search filters by country and locale", func(t *testing.T) {
+		repository := newRepository(t)
+		user1 := newUser("USER1", "Foo", "Bar", "foobar@email.com", true)
+		user1.Locale = "es-AR"
+		user1.Address = &domain.Address{Line1: "Street 123", City: "Buenos Aires", Country: "AR"}
+		repository.Create(user1)
+		user2 := newUser("USER2", "Foo", "Bar", "foobar@email.com", true)
+		user2.Locale = "en-US"
+		user2.Address = &domain.Address{Line1: "Street 456", City: "Mendoza", Country: "AR"}
+		repository.Create(user2)
+		repository.Create(newUser("USER3", "Foo", "Bar", "foobar@email.com", true))
+
+		input := newSearchInput(1, 10)
+		input.Country = "AR"
+		output, _ := repository.Search(input)
+		assert.ElementsMatch(t, []string{"USER1", "USER2"}, references(output.Users))
+
+		input = newSearchInput(1, 10)
+		input.Locale = "es-AR"
+		output, _ = repository.Search(input)
+		assert.ElementsMatch(t, []string{"USER1"}, references(output.Users))
+
+		input = newSearchInput(1, 10)
+		input.Country = "UY"
+		output, _ = repository.Search(input)
+		assert.Empty(t, output.Users)
+	})
+
+	t.Run("Search filters by attributes", func(t *testing.T) {
+		repository := newRepository(t)
+		user1 := newUser("USER1", "Foo", "Bar", "foobar@email.com", true)
+		user1.Attributes = map[string]interface{}{"plan": "pro", "seats": 10.0}
+		repository.Create(user1)
+		user2 := newUser("USER2", "Foo", "Bar", "foobar@email.com", true)
+		user2.Attributes = map[string]interface{}{"plan": "pro", "seats": 5.0}
+		repository.Create(user2)
+		repository.Create(newUser("USER3", "Foo", "Bar", "foobar@email.com", true))
+
+		input := newSearchInput(1, 10)
+		input.Attributes = map[string]interface{}{"plan": "pro"}
+		output, _ := repository.Search(input)
+		assert.ElementsMatch(t, []string{"USER1", "USER2"}, references(output.Users))
+
+		input.Attributes = map[string]interface{}{"plan": "pro", "seats": 10.0}
+		output, _ = repository.Search(input)
+		assert.ElementsMatch(t, []string{"USER1"}, references(output.Users))
+
+		input.Attributes = map[string]interface{}{"plan": "free"}
+		output, _ = repository.Search(input)
+		assert.Empty(t, output.Users)
+	})
+
+	t.Run("Search filters by all tags or any tag", func(t *testing.T) {
+		repository := newRepository(t)
+		user1 := newUser("USER1", "Foo", "Bar", "foobar@email.com", true)
+		user1.Tags = []string{"beta", "vip"}
+		repository.Create(user1)
+		user2 := newUser("USER2", "Foo", "Bar", "foobar@email.com", true)
+		user2.Tags = []string{"beta"}
+		repository.Create(user2)
+		user3 := newUser("USER3", "Foo", "Bar", "foobar@email.com", true)
+		user3.Tags = []string{"partner"}
+		repository.Create(user3)
+
+		input := newSearchInput(1, 10)
+		input.Tags = []string{"beta", "vip"}
+		output, _ := repository.Search(input)
+		assert.ElementsMatch(t, []string{"USER1"}, references(output.Users))
+
+		input = newSearchInput(1, 10)
+		input.AnyTags = []string{"vip", "partner"}
+		output, _ = repository.Search(input)
+		assert.ElementsMatch(t, []string{"USER1", "USER3"}, references(output.Users))
+
+		input = newSearchInput(1, 10)
+		input.Tags = []string{"beta"}
+		input.AnyTags = []string{"vip", "partner"}
+		output, _ = repository.Search(input)
+		assert.ElementsMatch(t, []string{"USER1"}, references(output.Users))
+	})
+
+	t.Run("Count tags of active users", func(t *testing.T) {
+		repository := newRepository(t)
+		user1 := newUser("USER1", "Foo", "Bar", "foobar@email.com", true)
+		user1.Tags = []string{"beta", "vip"}
+		repository.Create(user1)
+		user2 := newUser("USER2", "Foo", "Bar", "foobar@email.com", true)
+		user2.Tags = []string{"beta"}
+		repository.Create(user2)
+		user3 := newUser("USER3", "Foo", "Bar", "foobar@email.com", false)
+		user3.Tags = []string{"beta", "partner"}
+		repository.Create(user3)
+		repository.Create(newUser("USER4", "Foo", "Bar", "foobar@email.com", true))
+
+		tags, err := repository.CountTags()
+
+		assert.Nil(t, err)
+		assert.Equal(t, []domain.TagCount{{Tag: "beta", Count: 2}, {Tag: "vip", Count: 1}}, tags)
+	})
+
+	t.Run("Search includes inactive users only if it is requested", func(t *testing.T) {
+		repository := newRepository(t)
+		repository.Create(newUser("USER1", "Foo", "Bar", "foobar@email.com", true))
+		repository.Create(newUser("USER2", "Foo", "Bar", "inactive@email.com", false))
+
+		input := newSearchInput(1, 10)
+		output, _ := repository.Search(input)
+		assert.ElementsMatch(t, []string{"USER1"}, references(output.Users))
+
+		input.IncludeInactive = true
+		output, _ = repository.Search(input)
+		assert.ElementsMatch(t, []string{"USER1", "USER2"}, references(output.Users))
+		assert.Equal(t, int64(2), output.Total)
+	})
+
+	t.Run("Search handles the filters as literals", func(t *testing.T) {
+		if options.EncryptedPersonalData {
+			t.Skip("the encrypted names can not be searched")
+		}
+		repository := newRepository(t)
+		repository.Create(newUser("USER1", "Foo", "Bar", "foobar@email.com", true))
+
+		input := newSearchInput(1, 10)
+		input.FirstName = ".*"
+		output, err := repository.Search(input)
+		assert.Nil(t, err)
+		assert.Empty(t, output.Users)
+	})
+
+	t.Run("Profile is preserved", func(t *testing.T) {
+		repository := newRepository(t)
+		birthDate := time.Date(1990, 5, 17, 0, 0, 0, 0, time.UTC)
+		user := newUser("USER1", "Foo", "Bar", "foobar@email.com", true)
+		user.UserProfile = domain.UserProfile{
+			Phone:     "+5491112345678",
+			BirthDate: &birthDate,
+			Locale:    "es-AR",
+			Timezone:  "America/Argentina/Buenos_Aires",
+			Address: &domain.Address{
+				Line1:      "Street 123",
+				Line2:      "Floor 1",
+				City:       "Buenos Aires",
+				Region:     "CABA",
+				PostalCode: "C1000",
+				Country:    "AR",
+			},
+		}
+		repository.Create(user)
+
+		found, _ := repository.FindByReference("USER1")
+		assertUser(t, user, found)
+	})
+
+	t.Run("Timestamps are preserved", func(t *testing.T) {
+		repository := newRepository(t)
+		user := newUser("USER1", "Foo", "Bar", "foobar@email.com", true)
+		user.CreatedDate = time.Date(2023, 2, 1, 23, 58, 18, 0, time.UTC)
+		user.UpdatedDate = time.Date(2023, 3, 1, 10, 0, 0, 0, time.UTC)
+		repository.Create(user)
+
+		found, _ := repository.FindByReference("USER1")
+		assert.True(t, user.CreatedDate.Equal(found.CreatedDate))
+		assert.True(t, user.UpdatedDate.Equal(found.UpdatedDate))
+	})
+}
+
+func newUser(reference string, firstName string, lastName string, email string, isActive bool) domain.User {
+	// Truncated to milliseconds, as stored by MongoDB
+	now := time.Now().UTC().Add(-time.Hour).Truncate(time.Millisecond)
+	status := domain.UserStatusActive
+	if !isActive {
+		status = domain.UserStatusDeleted
+	}
+	return domain.User{
+		GenericEntity: domain.GenericEntity{
+			Reference:   reference,
+			IsActive:    isActive,
+			CreatedDate: now,
+			UpdatedDate: now,
+		},
+		FirstName:  firstName,
+		LastName:   lastName,
+		Email:      email,
+		Status:     status,
+		StatusDate: now,
+	}
+}
+
+func newSearchInput(page int, size int) domain.UserSearchInput {
+	return domain.UserSearchInput{
+		SearchInput: domain.SearchInput{
+			Page:     page,
+			PageSize: size,
+		},
+	}
+}
+
+func references(users []domain.User) []string {
+	refs := []string{}
+	for _, user := range users {
+		refs = append(refs, user.Reference)
+	}
+	return refs
+}
+
+// assertUser compares two users. Dates are compared as instants, since the time zone can change when they are stored
+func assertUser(t *testing.T, expected domain.User, actual domain.User) {
+	t.Helper()
+
+	assert.Equal(t, expected.Reference, actual.Reference)
+	assert.Equal(t, expected.IsActive, actual.IsActive)
+	assert.Equal(t, expected.FirstName, actual.FirstName)
+	assert.Equal(t, expected.LastName, actual.LastName)
+	assert.Equal(t, expected.Email, actual.Email)
+	assert.Equal(t, expected.Status, actual.Status)
+	assert.Equal(t, expected.StatusReason, actual.StatusReason)
+	assert.True(t, expected.StatusDate.Equal(actual.StatusDate), "status date %s != %s", expected.StatusDate, actual.StatusDate)
+	assert.True(t, expected.CreatedDate.Equal(actual.CreatedDate), "created date %s != %s", expected.CreatedDate, actual.CreatedDate)
+	assert.True(t, expected.UpdatedDate.Equal(actual.UpdatedDate), "updated date %s != %s", expected.UpdatedDate, actual.UpdatedDate)
+	assert.Equal(t, expected.Phone, actual.Phone)
+	assert.Equal(t, expected.Locale, actual.Locale)
+	assert.Equal(t, expected.Timezone, actual.Timezone)
+	assert.Equal(t, expected.Address, actual.Address)
+	assert.ElementsMatch(t, expected.Roles, actual.Roles)
+	assert.Equal(t, expected.Attributes, actual.Attributes)
+	assert.Equal(t, expected.Tags, actual.Tags)
+	assert.Equal(t, expected.ExternalIDs, actual.ExternalIDs)
+	assert.Equal(t, expected.Avatar == nil, actual.Avatar == nil)
+	if expected.Avatar != nil && actual.Avatar != nil {
+		assert.Equal(t, expected.Avatar.Version, actual.Avatar.Version)
+		assert.Equal(t, expected.Avatar.ContentType, actual.Avatar.ContentType)
+		assert.Equal(t, expected.Avatar.Sizes, actual.Avatar.Sizes)
+		assert.True(t, expected.Avatar.UpdatedDate.Equal(actual.Avatar.UpdatedDate))
+	}
+	assert.Equal(t, expected.BirthDate == nil, actual.BirthDate == nil)
+	if expected.BirthDate != nil && actual.BirthDate != nil {
+		assert.True(t, expected.BirthDate.Equal(*actual.BirthDate))
+	}
+	assert.Equal(t, expected.MergedInto, actual.MergedInto)
+	assert.Equal(t, expected.ErasedDate == nil, actual.ErasedDate == nil)
+	if expected.ErasedDate != nil && actual.ErasedDate != nil {
+		assert.True(t, expected.ErasedDate.Equal(*actual.ErasedDate))
+	}
+	assert.Equal(t, expected.EmailVerifiedDate == nil, actual.EmailVerifiedDate == nil)
+	if expected.EmailVerifiedDate != nil && actual.EmailVerifiedDate != nil {
+		assert.True(t, expected.EmailVerifiedDate.Equal(*actual.EmailVerifiedDate))
+	}
+	assert.Equal(t, expected.EmailVerificationSentDate == nil, actual.EmailVerificationSentDate == nil)
+	if expected.EmailVerificationSentDate != nil && actual.EmailVerificationSentDate != nil {
+		assert.True(t, expected.EmailVerificationSentDate.Equal(*actual.EmailVerificationSentDate))
+	}
+}
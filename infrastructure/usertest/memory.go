@@ -0,0 +1,345 @@
+package usertest
+
+import (
+	"errors"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/desarrollogj/golang-api-example/domain"
+	"github.com/desarrollogj/golang-api-example/infrastructure"
+)
+
+// inMemoryUserRepository is an in memory implementation of infrastructure.UserRepository.
+// It is the reference implementation of the repository contract, useful as a fake in tests
+type inMemoryUserRepository struct {
+	mutex sync.RWMutex
+	users []domain.User
+}
+
+// NewInMemoryUserRepository creates an empty inMemoryUserRepository
+func NewInMemoryUserRepository() *inMemoryUserRepository {
+	return &inMemoryUserRepository{
+		users: []domain.User{},
+	}
+}
+
+func (r *inMemoryUserRepository) FindAllActive() ([]domain.User, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	users := []domain.User{}
+	for _, user := range r.users {
+		if user.IsActive {
+			users = append(users, user)
+		}
+	}
+
+	return users, nil
+}
+
+func (r *inMemoryUserRepository) FindActiveByReference(reference string) (domain.User, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	index := r.indexOf(reference)
+	if index < 0 || !r.users[index].IsActive {
+		return domain.User{}, nil
+	}
+
+	return r.users[index], nil
+}
+
+func (r *inMemoryUserRepository) FindByReference(reference string) (domain.User, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	index := r.indexOf(reference)
+	if index < 0 {
+		return domain.User{}, nil
+	}
+
+	return r.users[index], nil
+}
+
+func (r *inMemoryUserRepository) FindAllActiveByEmail(email string) ([]domain.User, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	users := []domain.User{}
+	for _, user := range r.users {
+		if user.IsActive && strings.EqualFold(user.Email, email) {
+			users = append(users, user)
+		}
+	}
+	sort.Slice(users, func(i, j int) bool { return users[i].Reference < users[j].Reference })
+
+	return users, nil
+}
+
+func (r *inMemoryUserRepository) FindByExternalID(source string, id string) (domain.User, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	externalID := domain.UserExternalID{Source: source, ID: id}
+	for _, user := range r.users {
+		for _, userExternalID := range user.ExternalIDs {
+			if userExternalID == externalID {
+				return user, nil
+			}
+		}
+	}
+
+	return domain.User{}, nil
+}
+
+func (r *inMemoryUserRepository) Search(input domain.UserSearchInput) (domain.UserSearchOutput, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	found := []domain.User{}
+	for _, user := range r.users {
+		if (user.IsActive || input.IncludeInactive) &&
+			hasPrefix(user.FirstName, input.FirstName) &&
+			hasPrefix(user.LastName, input.LastName) &&
+			hasPrefix(user.Email, input.Email) &&
+			(len(input.Country) == 0 || (user.Address != nil && user.Address.Country == input.Country)) &&
+			(len(input.Locale) == 0 || user.Locale == input.Locale) &&
+			hasAttributes(user, input.Attributes) &&
+			hasAllTags(user, input.Tags) &&
+			(len(input.AnyTags) == 0 || hasAnyTag(user, input.AnyTags)) {
+			found = append(found, user)
+		}
+	}
+
+	from := (input.Page * input.PageSize) - input.PageSize
+	to := from + input.PageSize
+	if from > len(found) {
+		from = len(found)
+	}
+	if to > len(found) {
+		to = len(found)
+	}
+
+	return domain.UserSearchOutput{
+		SearchOutput: domain.SearchOutput{
+			Total:    int64(len(found)),
+			Page:     input.Page,
+			PageSize: input.PageSize,
+		},
+		Users: append([]domain.User{}, found[from:to]...),
+	}, nil
+}
+
+func (r *inMemoryUserRepository) CountTags() ([]domain.TagCount, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	counts := map[string]int64{}
+	for _, user := range r.users {
+		if user.IsActive {
+			for _, tag := range user.Tags {
+				counts[tag]++
+			}
+		}
+	}
+
+	tags := []domain.TagCount{}
+	for tag, count := range counts {
+		tags = append(tags, domain.TagCount{Tag: tag, Count: count})
+	}
+	sort.Slice(tags, func(i, j int) bool {
+		if tags[i].Count != tags[j].Count {
+			return tags[i].Count > tags[j].Count
+		}
+		return tags[i].Tag < tags[j].Tag
+	})
+	return tags, nil
+}
+
+func (r *inMemoryUserRepository) Create(user domain.User) (domain.User, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if user.Undecryptable {
+		return domain.User{}, infrastructure.ErrUndecryptableUser
+	}
+
+	if r.hasExternalIDsOfOthers(user) {
+		return domain.User{}, infrastructure.ErrDuplicateExternalID
+	}
+	r.users = append(r.users, user)
+	return user, nil
+}
+
+func (r *inMemoryUserRepository) Update(user domain.User) (domain.User, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if user.Undecryptable {
+		return domain.User{}, infrastructure.ErrUndecryptableUser
+	}
+
+	index := r.indexOf(user.Reference)
+	if index < 0 {
+		return domain.User{}, errors.New("user to update was not found")
+	}
+	if r.hasExternalIDsOfOthers(user) {
+		return domain.User{}, infrastructure.ErrDuplicateExternalID
+	}
+
+	r.users[index] = user
+	return user, nil
+}
+
+func (r *inMemoryUserRepository) Patch(user domain.User) (domain.User, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if user.Undecryptable {
+		return domain.User{}, infrastructure.ErrUndecryptableUser
+	}
+
+	index := r.indexOf(user.Reference)
+	if index < 0 {
+		return domain.User{}, errors.New("user to patch was not found")
+	}
+	if r.hasExternalIDsOfOthers(user) {
+		return domain.User{}, infrastructure.ErrDuplicateExternalID
+	}
+
+	r.users[index] = user
+	return user, nil
+}
+
+func (r *inMemoryUserRepository) AddTag(reference string, tag string, maxTags int, updatedDate time.Time) (bool, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	index := r.indexOf(reference)
+	if index < 0 || r.users[index].ErasedDate != nil || len(r.users[index].Tags) >= maxTags {
+		return false, nil
+	}
+	for _, current := range r.users[index].Tags {
+		if current == tag {
+			return false, nil
+		}
+	}
+
+	tags := append(append([]string{}, r.users[index].Tags...), tag)
+	sort.Strings(tags)
+	r.users[index].Tags = tags
+	r.users[index].UpdatedDate = updatedDate
+	return true, nil
+}
+
+func (r *inMemoryUserRepository) RemoveTag(reference string, tag string, updatedDate time.Time) (bool, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	index := r.indexOf(reference)
+	if index < 0 {
+		return false, nil
+	}
+
+	var tags []string
+	for _, current := range r.users[index].Tags {
+		if current != tag {
+			tags = append(tags, current)
+		}
+	}
+	if len(tags) == len(r.users[index].Tags) {
+		return false, nil
+	}
+	r.users[index].Tags = tags
+	r.users[index].UpdatedDate = updatedDate
+	return true, nil
+}
+
+func (r *inMemoryUserRepository) ChangeStatus(user domain.User, from domain.UserStatus) (domain.User, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if user.Undecryptable {
+		return domain.User{}, infrastructure.ErrUndecryptableUser
+	}
+
+	index := r.indexOf(user.Reference)
+	if index < 0 {
+		return domain.User{}, errors.New("user to change status was not found")
+	}
+	if domain.ResolveUserStatus(r.users[index].Status, r.users[index].IsActive) != from {
+		return domain.User{}, infrastructure.ErrUserModified
+	}
+	if r.hasExternalIDsOfOthers(user) {
+		return domain.User{}, infrastructure.ErrDuplicateExternalID
+	}
+
+	r.users[index] = user
+	return user, nil
+}
+
+func (r *inMemoryUserRepository) indexOf(reference string) int {
+	for i, user := range r.users {
+		if user.Reference == reference {
+			return i
+		}
+	}
+	return -1
+}
+
+// hasExternalIDsOfOthers checks if another user has one of the user external ids, like the mongo unique index
+func (r *inMemoryUserRepository) hasExternalIDsOfOthers(user domain.User) bool {
+	for _, other := range r.users {
+		if other.Reference == user.Reference {
+			continue
+		}
+		for _, externalID := range user.ExternalIDs {
+			for _, otherExternalID := range other.ExternalIDs {
+				if externalID == otherExternalID {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// hasPrefix checks a case insensitive prefix. An empty prefix matches any value
+func hasPrefix(value string, prefix string) bool {
+	return strings.HasPrefix(strings.ToLower(value), strings.ToLower(prefix))
+}
+
+// hasAttributes checks that the user has every attribute with the same value
+func hasAttributes(user domain.User, attributes map[string]interface{}) bool {
+	for name, value := range attributes {
+		if !reflect.DeepEqual(user.Attributes[name], value) {
+			return false
+		}
+	}
+	return true
+}
+
+// hasAllTags checks that the user has every tag
+func hasAllTags(user domain.User, tags []string) bool {
+	for _, tag := range tags {
+		if !hasAnyTag(user, []string{tag}) {
+			return false
+		}
+	}
+	return true
+}
+
+// hasAnyTag checks that the user has at least one of the tags
+func hasAnyTag(user domain.User, tags []string) bool {
+	for _, tag := range tags {
+		for _, userTag := range user.Tags {
+			if userTag == tag {
+				return true
+			}
+		}
+	}
+	return false
+}
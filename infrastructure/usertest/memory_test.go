@@ -0,0 +1,15 @@
+package usertest
+
+import (
+	"testing"
+
+	"github.com/desarrollogj/golang-api-example/infrastructure"
+)
+
+func TestInMemoryUserRepository_Contract(t *testing.T) {
+	t.Log("In memory repository should fulfill the users repository contract")
+
+	RunUserRepositoryContract(t, func(t *testing.T) infrastructure.UserRepository {
+		return NewInMemoryUserRepository()
+	}, ContractOptions{})
+}
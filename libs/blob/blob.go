@@ -0,0 +1,15 @@
+package blob
+
+import "errors"
+
+// ErrNotFound is returned when the blob does not exist
+var ErrNotFound = errors.New("blob not found")
+
+// BlobStore represents the methods to be implemented by binary objects stores.
+// Keys are slash separated paths, for example "avatars/USER1/256"
+type BlobStore interface {
+	Put(key string, data []byte) error
+	Get(key string) ([]byte, error)
+	// Delete removes the blob. Deleting a missing blob is not an error
+	Delete(key string) error
+}
@@ -0,0 +1,92 @@
+package blob
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// localBlobStore stores each blob in a file of a local directory
+type localBlobStore struct {
+	directory string
+}
+
+// NewLocalBlobStore creates a BlobStore that writes the blobs to files in the directory
+func NewLocalBlobStore(directory string) localBlobStore {
+	return localBlobStore{
+		directory: directory,
+	}
+}
+
+// Put writes the blob to a temporary file and then renames it, so readers never get a partial blob
+func (s localBlobStore) Put(key string, data []byte) error {
+	file, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(file), 0o750); err != nil {
+		return fmt.Errorf("unable to create the blob directory: %w", err)
+	}
+
+	temp, err := os.CreateTemp(filepath.Dir(file), ".blob-*")
+	if err != nil {
+		return fmt.Errorf("unable to create the blob file: %w", err)
+	}
+	defer os.Remove(temp.Name())
+
+	_, err = temp.Write(data)
+	if closeErr := temp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("unable to write the blob file: %w", err)
+	}
+	if err := os.Rename(temp.Name(), file); err != nil {
+		return fmt.Errorf("unable to write the blob file: %w", err)
+	}
+
+	return nil
+}
+
+func (s localBlobStore) Get(key string) ([]byte, error) {
+	file, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(file)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("unable to read the blob file: %w", err)
+	}
+
+	return data, nil
+}
+
+func (s localBlobStore) Delete(key string) error {
+	file, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(file); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("unable to delete the blob file: %w", err)
+	}
+
+	return nil
+}
+
+// path returns the file of the key. Keys can not leave the store directory
+func (s localBlobStore) path(key string) (string, error) {
+	cleaned := path.Clean(key)
+	if len(key) == 0 || cleaned != key || path.IsAbs(key) || cleaned == ".." || strings.HasPrefix(cleaned, "../") {
+		return "", fmt.Errorf("blob key %q is not valid", key)
+	}
+
+	return filepath.Join(s.directory, filepath.FromSlash(cleaned)), nil
+}
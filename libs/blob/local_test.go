@@ -0,0 +1,49 @@
+package blob
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocalBlobStore_GivenABlob_WhenPutAndGet_ThenReturnItsData(t *testing.T) {
+	t.Log("Successfully store and read a blob")
+
+	directory := t.TempDir()
+	store := NewLocalBlobStore(directory)
+
+	err := store.Put("avatars/USER1/64", []byte("image"))
+	assert.Nil(t, err)
+
+	data, err := store.Get("avatars/USER1/64")
+	assert.Nil(t, err)
+	assert.Equal(t, []byte("image"), data)
+
+	files, _ := os.ReadDir(filepath.Join(directory, "avatars", "USER1"))
+	assert.Len(t, files, 1)
+}
+
+func TestLocalBlobStore_GivenADeletedBlob_WhenGet_ThenReturnNotFound(t *testing.T) {
+	t.Log("Failure to read a blob because it was deleted")
+
+	store := NewLocalBlobStore(t.TempDir())
+	store.Put("avatars/USER1/64", []byte("image"))
+
+	assert.Nil(t, store.Delete("avatars/USER1/64"))
+	assert.Nil(t, store.Delete("avatars/USER1/64"))
+
+	_, err := store.Get("avatars/USER1/64")
+	assert.Equal(t, ErrNotFound, err)
+}
+
+func TestLocalBlobStore_GivenAKeyOutsideTheDirectory_WhenPut_ThenReturnAnError(t *testing.T) {
+	t.Log("Failure to store a blob because its key is not valid")
+
+	store := NewLocalBlobStore(t.TempDir())
+
+	for _, key := range []string{"", "../outside", "/etc/passwd", "avatars/../../outside", "avatars//64"} {
+		assert.NotNil(t, store.Put(key, []byte("image")), key)
+	}
+}
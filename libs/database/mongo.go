@@ -19,7 +19,8 @@ type MongoDB struct {
 func MongoConnect() *MongoDB {
 	// connect
 	opts := options.Client().ApplyURI(appConfig.String("database.connectionString"))
-	ctx, _ := context.WithTimeout(context.Background(), 10*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
 	client, err := mongo.Connect(ctx, opts)
 	if err != nil {
 		logger.AppLog.Fatal().Err(err).Msg("unable to create a Connection")
@@ -43,7 +44,9 @@ func MongoDisconnect() {
 		return
 	}
 
-	if err := Mongo.Client.Disconnect(context.TODO()); err != nil {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := Mongo.Client.Disconnect(ctx); err != nil {
 		logger.AppLog.Fatal().Err(err).Msg("unable to close connection")
 	}
 
@@ -0,0 +1,150 @@
+package encryption
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const keySize = 32
+
+// DataKey is a per record key used to encrypt fields. It is stored wrapped (encrypted) by a keyring key
+type DataKey struct {
+	KeyID   string
+	Wrapped []byte
+	aead    cipher.AEAD
+}
+
+// GenerateDataKey creates a new random data key wrapped with the active keyring key
+func (k *Keyring) GenerateDataKey() (*DataKey, error) {
+	plain := make([]byte, keySize)
+	if _, err := rand.Read(plain); err != nil {
+		return nil, fmt.Errorf("unable to generate data key: %w", err)
+	}
+
+	wrapped, err := seal(k.keys[k.activeKey], plain, []byte(k.activeKey))
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := newAEAD(plain)
+	if err != nil {
+		return nil, err
+	}
+
+	return &DataKey{KeyID: k.activeKey, Wrapped: wrapped, aead: aead}, nil
+}
+
+// UnwrapDataKey decrypts a data key previously wrapped with the keyring key identified by keyID
+func (k *Keyring) UnwrapDataKey(keyID string, wrapped []byte) (*DataKey, error) {
+	key, ok := k.keys[keyID]
+	if !ok {
+		return nil, fmt.Errorf("key %s was not found in the keyring", keyID)
+	}
+
+	plain, err := open(key, wrapped, []byte(keyID))
+	if err != nil {
+		return nil, fmt.Errorf("unable to unwrap data key: %w", err)
+	}
+
+	aead, err := newAEAD(plain)
+	if err != nil {
+		return nil, err
+	}
+
+	return &DataKey{KeyID: keyID, Wrapped: wrapped, aead: aead}, nil
+}
+
+// BlindIndex computes a deterministic keyed hash of a value, so it can be used for lookups and unique indexes
+// without storing the value in clear text. The value is normalized (trimmed and lower cased) before hashing
+func (k *Keyring) BlindIndex(value string) string {
+	normalized := strings.ToLower(strings.TrimSpace(value))
+	if len(normalized) == 0 {
+		return ""
+	}
+
+	mac := hmac.New(sha256.New, k.indexKey)
+	mac.Write([]byte(normalized))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// Encrypt encrypts a field value. The field name is authenticated, so a ciphertext cannot be moved to another field
+func (d *DataKey) Encrypt(field string, value string) (string, error) {
+	if len(value) == 0 {
+		return "", nil
+	}
+
+	nonce := make([]byte, d.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("unable to generate nonce: %w", err)
+	}
+
+	sealed := d.aead.Seal(nonce, nonce, []byte(value), []byte(field))
+	return base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt decrypts a field value encrypted with Encrypt
+func (d *DataKey) Decrypt(field string, value string) (string, error) {
+	if len(value) == 0 {
+		return "", nil
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(value)
+	if err != nil {
+		return "", fmt.Errorf("unable to decode %s ciphertext: %w", field, err)
+	}
+
+	nonceSize := d.aead.NonceSize()
+	if len(sealed) < nonceSize {
+		return "", fmt.Errorf("%s ciphertext is too short", field)
+	}
+
+	plain, err := d.aead.Open(nil, sealed[:nonceSize], sealed[nonceSize:], []byte(field))
+	if err != nil {
+		return "", fmt.Errorf("unable to decrypt %s: %w", field, err)
+	}
+
+	return string(plain), nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create cipher: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
+
+func seal(key []byte, plain []byte, additionalData []byte) ([]byte, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("unable to generate nonce: %w", err)
+	}
+
+	return aead.Seal(nonce, nonce, plain, additionalData), nil
+}
+
+func open(key []byte, sealed []byte, additionalData []byte) ([]byte, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonceSize := aead.NonceSize()
+	if len(sealed) < nonceSize {
+		return nil, errors.New("sealed data is too short")
+	}
+
+	return aead.Open(nil, sealed[:nonceSize], sealed[nonceSize:], additionalData)
+}
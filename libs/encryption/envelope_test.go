@@ -0,0 +1,105 @@
+package encryption
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestKeyring(t *testing.T, activeKey string) *Keyring {
+	keys := map[string][]byte{
+		"k1": bytes.Repeat([]byte{1}, 32),
+		"k2": bytes.Repeat([]byte{3}, 32),
+	}
+	keyring, err := NewKeyring(activeKey, keys, bytes.Repeat([]byte{2}, 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return keyring
+}
+
+func TestDataKeyEncryptDecrypt(t *testing.T) {
+	t.Log("A value encrypted with a data key should be decrypted with the unwrapped data key")
+
+	keyring := newTestKeyring(t, "k1")
+	dataKey, err := keyring.GenerateDataKey()
+	assert.Nil(t, err)
+	assert.Equal(t, "k1", dataKey.KeyID)
+
+	encrypted, err := dataKey.Encrypt("email", "foobar@email.com")
+	assert.Nil(t, err)
+	assert.NotEqual(t, "foobar@email.com", encrypted)
+
+	unwrapped, err := keyring.UnwrapDataKey(dataKey.KeyID, dataKey.Wrapped)
+	assert.Nil(t, err)
+
+	decrypted, err := unwrapped.Decrypt("email", encrypted)
+	assert.Nil(t, err)
+	assert.Equal(t, "foobar@email.com", decrypted)
+}
+
+func TestDataKeyDecryptWithAnotherField(t *testing.T) {
+	t.Log("A value encrypted for a field should not be decrypted as another field")
+
+	keyring := newTestKeyring(t, "k1")
+	dataKey, _ := keyring.GenerateDataKey()
+	encrypted, _ := dataKey.Encrypt("email", "foobar@email.com")
+
+	_, err := dataKey.Decrypt("first_name", encrypted)
+
+	assert.NotNil(t, err)
+}
+
+func TestDataKeyEncryptEmptyValue(t *testing.T) {
+	t.Log("An empty value should remain empty")
+
+	keyring := newTestKeyring(t, "k1")
+	dataKey, _ := keyring.GenerateDataKey()
+
+	encrypted, err := dataKey.Encrypt("email", "")
+
+	assert.Nil(t, err)
+	assert.Equal(t, "", encrypted)
+}
+
+func TestUnwrapDataKeyAfterRotation(t *testing.T) {
+	t.Log("A data key wrapped with a previous key should be unwrapped after the active key was rotated")
+
+	previous := newTestKeyring(t, "k1")
+	dataKey, _ := previous.GenerateDataKey()
+	encrypted, _ := dataKey.Encrypt("email", "foobar@email.com")
+
+	rotated := newTestKeyring(t, "k2")
+	unwrapped, err := rotated.UnwrapDataKey(dataKey.KeyID, dataKey.Wrapped)
+	assert.Nil(t, err)
+
+	decrypted, err := unwrapped.Decrypt("email", encrypted)
+	assert.Nil(t, err)
+	assert.Equal(t, "foobar@email.com", decrypted)
+}
+
+func TestUnwrapDataKeyWithUnknownKey(t *testing.T) {
+	t.Log("Unwrap a data key should fail when its key is not in the keyring")
+
+	keyring := newTestKeyring(t, "k1")
+	dataKey, _ := keyring.GenerateDataKey()
+
+	_, err := keyring.UnwrapDataKey("k3", dataKey.Wrapped)
+
+	assert.NotNil(t, err)
+	assert.Equal(t, "key k3 was not found in the keyring", err.Error())
+}
+
+func TestBlindIndex(t *testing.T) {
+	t.Log("Blind index should be deterministic and case insensitive")
+
+	keyring := newTestKeyring(t, "k1")
+	rotated := newTestKeyring(t, "k2")
+
+	assert.NotEmpty(t, keyring.BlindIndex("foobar@email.com"))
+	assert.Equal(t, keyring.BlindIndex("foobar@email.com"), keyring.BlindIndex(" FooBar@Email.com "))
+	assert.Equal(t, keyring.BlindIndex("foobar@email.com"), rotated.BlindIndex("foobar@email.com"))
+	assert.NotEqual(t, keyring.BlindIndex("foobar@email.com"), keyring.BlindIndex("foo@email.com"))
+	assert.Equal(t, "", keyring.BlindIndex(""))
+}
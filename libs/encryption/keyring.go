@@ -0,0 +1,86 @@
+package encryption
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// keyringFile represents the keyring file structure
+type keyringFile struct {
+	ActiveKey string           `json:"activeKey"`
+	IndexKey  string           `json:"indexKey"`
+	Keys      []keyringFileKey `json:"keys"`
+}
+
+type keyringFileKey struct {
+	ID     string `json:"id"`
+	Secret string `json:"secret"`
+}
+
+// Keyring holds the key encryption keys used to wrap data keys and the key used to compute blind indexes
+type Keyring struct {
+	activeKey string
+	keys      map[string][]byte
+	indexKey  []byte
+}
+
+// NewKeyring creates and validates a Keyring. All keys must be 32 bytes long (AES-256)
+func NewKeyring(activeKey string, keys map[string][]byte, indexKey []byte) (*Keyring, error) {
+	if len(activeKey) == 0 {
+		return nil, errors.New("active key id is required")
+	}
+	if _, ok := keys[activeKey]; !ok {
+		return nil, fmt.Errorf("active key %s was not found in the keyring", activeKey)
+	}
+	for id, key := range keys {
+		if len(key) != keySize {
+			return nil, fmt.Errorf("key %s must be %d bytes long", id, keySize)
+		}
+	}
+	if len(indexKey) != keySize {
+		return nil, fmt.Errorf("index key must be %d bytes long", keySize)
+	}
+
+	return &Keyring{
+		activeKey: activeKey,
+		keys:      keys,
+		indexKey:  indexKey,
+	}, nil
+}
+
+// LoadKeyring loads a Keyring from a local JSON file. Secrets are base64 encoded
+func LoadKeyring(path string) (*Keyring, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read keyring file: %w", err)
+	}
+
+	var file keyringFile
+	if err = json.Unmarshal(content, &file); err != nil {
+		return nil, fmt.Errorf("unable to decode keyring file: %w", err)
+	}
+
+	keys := make(map[string][]byte, len(file.Keys))
+	for _, key := range file.Keys {
+		secret, err := base64.StdEncoding.DecodeString(key.Secret)
+		if err != nil {
+			return nil, fmt.Errorf("unable to decode key %s: %w", key.ID, err)
+		}
+		keys[key.ID] = secret
+	}
+
+	indexKey, err := base64.StdEncoding.DecodeString(file.IndexKey)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode index key: %w", err)
+	}
+
+	return NewKeyring(file.ActiveKey, keys, indexKey)
+}
+
+// ActiveKeyID returns the id of the key used to wrap new data keys
+func (k *Keyring) ActiveKeyID() string {
+	return k.activeKey
+}
@@ -0,0 +1,56 @@
+package encryption
+
+import (
+	"bytes"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadKeyring(t *testing.T) {
+	t.Log("Load keyring should read and decode the keyring file")
+
+	secret := base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{1}, 32))
+	indexKey := base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{2}, 32))
+	path := filepath.Join(t.TempDir(), "keyring.json")
+	content := `{"activeKey":"k1","indexKey":"` + indexKey + `","keys":[{"id":"k1","secret":"` + secret + `"}]}`
+	os.WriteFile(path, []byte(content), 0600)
+
+	keyring, err := LoadKeyring(path)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "k1", keyring.ActiveKeyID())
+}
+
+func TestLoadKeyringWithMissingFile(t *testing.T) {
+	t.Log("Load keyring should fail when the file does not exist")
+
+	_, err := LoadKeyring(filepath.Join(t.TempDir(), "missing.json"))
+
+	assert.NotNil(t, err)
+}
+
+func TestNewKeyringWithUnknownActiveKey(t *testing.T) {
+	t.Log("New keyring should fail when the active key is not in the keyring")
+
+	keys := map[string][]byte{"k1": bytes.Repeat([]byte{1}, 32)}
+
+	_, err := NewKeyring("k2", keys, bytes.Repeat([]byte{2}, 32))
+
+	assert.NotNil(t, err)
+	assert.Equal(t, "active key k2 was not found in the keyring", err.Error())
+}
+
+func TestNewKeyringWithInvalidKeySize(t *testing.T) {
+	t.Log("New keyring should fail when a key is not 32 bytes long")
+
+	keys := map[string][]byte{"k1": bytes.Repeat([]byte{1}, 16)}
+
+	_, err := NewKeyring("k1", keys, bytes.Repeat([]byte{2}, 32))
+
+	assert.NotNil(t, err)
+	assert.Equal(t, "key k1 must be 32 bytes long", err.Error())
+}
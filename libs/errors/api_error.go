@@ -4,6 +4,8 @@ import (
 	"errors"
 	"net/http"
 	"strings"
+
+	"github.com/desarrollogj/golang-api-example/libs/i18n"
 )
 
 const (
@@ -13,6 +15,12 @@ const (
 	InternalServerErrorMessage = "internal Server Error"
 	NotFoundErrorMessage       = "not found"
 	UnathorizedErrorMessage    = "unauthorized"
+	ForbiddenMessage           = "access to the resource is forbidden"
+	ConflictMessage            = "the request conflicts with the current state of the resource"
+	UnsupportedMediaMessage    = "unsupported media type"
+	PayloadTooLargeMessage     = "request body is too large"
+	TooManyRequestsMessage     = "too many requests"
+	UnprocessableMessage       = "the request can not be processed"
 )
 
 // NewAPIError creates and initializes an APIError.
@@ -24,54 +32,103 @@ func NewAPIError(code int, message string, err string) *APIError {
 	}
 }
 
-// NewBadRequest creates an API Error for an invalid or malformed request.
-func NewBadRequest(messages ...string) *APIError {
-	message := BadRequestMessage
+// newDefinedAPIError creates an API Error with the status and default message of its code definition. The messages replace
+// the default message, which is translated with the code as key. Custom messages are not translated.
+func newDefinedAPIError(code string, messages []string) *APIError {
+	definition := mustDefinition(code)
 	if len(messages) > 0 {
-		message = strings.Join(messages, " - ")
+		return NewAPIError(definition.Status, strings.Join(messages, " - "), definition.Code)
 	}
 
-	return NewAPIError(http.StatusBadRequest, message, "bad_request")
+	apiErr := NewAPIError(definition.Status, definition.Message, definition.Code)
+	apiErr.Key = definition.Code
+	return apiErr
 }
 
-// NewResourceNotFound creates an API Error for an unexisting resource.
-func NewResourceNotFound(messages ...string) *APIError {
-	message := ResourceNotFoundMessage
-	if len(messages) > 0 {
-		message = strings.Join(messages, " - ")
+// WithKey sets the code of the message in the catalogs, with its arguments, to translate it.
+func (e *APIError) WithKey(key string, args ...interface{}) *APIError {
+	e.Key = key
+	e.Args = args
+	return e
+}
+
+// Localize returns a copy of the error with the message and causes in a language. The messages without key are not translated.
+func (e *APIError) Localize(lang string) *APIError {
+	localized := *e
+	if message, ok := i18n.Text(lang, e.Key, e.Args...); ok {
+		localized.Message = message
 	}
+	if len(e.Causes) > 0 {
+		localized.Causes = make([]ErrorCause, len(e.Causes))
+		for i, cause := range e.Causes {
+			if message, ok := i18n.Text(lang, cause.Key, cause.Args...); ok {
+				cause.Message = message
+			}
+			localized.Causes[i] = cause
+		}
+	}
+	return &localized
+}
 
-	return NewAPIError(http.StatusNotFound, message, "not_found")
+// WithCauses adds the causes of the error.
+func (e *APIError) WithCauses(causes ...ErrorCause) *APIError {
+	e.Causes = append(e.Causes, causes...)
+	return e
+}
+
+// NewBadRequest creates an API Error for an invalid or malformed request.
+func NewBadRequest(messages ...string) *APIError {
+	return newDefinedAPIError(BadRequestErrorCode, messages)
+}
+
+// NewResourceNotFound creates an API Error for an unexisting resource.
+func NewResourceNotFound(messages ...string) *APIError {
+	return newDefinedAPIError(NotFoundErrorCode, messages)
 }
 
 // NewMethodNotAllowed creates an API Error for a forbidden verb on a resource.
 func NewMethodNotAllowed(messages ...string) *APIError {
-	message := MethodNotAllowedMessage
-	if len(messages) > 0 {
-		message = strings.Join(messages, " - ")
-	}
-
-	return NewAPIError(http.StatusMethodNotAllowed, message, "method_not_allowed")
+	return newDefinedAPIError(MethodNotAllowedErrorCode, messages)
 }
 
 // NewUnauthorizedError creates an API Error for an unauthorized access on a resource.
 func NewUnauthorizedError(messages ...string) *APIError {
-	message := UnathorizedErrorMessage
-	if len(messages) > 0 {
-		message = strings.Join(messages, " - ")
-	}
+	return newDefinedAPIError(UnauthorizedErrorCode, messages)
+}
+
+// NewForbidden creates an API Error for an authenticated request that is not allowed on a resource.
+func NewForbidden(messages ...string) *APIError {
+	return newDefinedAPIError(ForbiddenErrorCode, messages)
+}
+
+// NewConflict creates an API Error for a request that conflicts with the current state of a resource.
+func NewConflict(messages ...string) *APIError {
+	return newDefinedAPIError(ConflictErrorCode, messages)
+}
+
+// NewUnsupportedMediaType creates an API Error for a request body with an unsupported content type.
+func NewUnsupportedMediaType(messages ...string) *APIError {
+	return newDefinedAPIError(UnsupportedMediaTypeErrorCode, messages)
+}
 
-	return NewAPIError(http.StatusUnauthorized, message, "unauthorized")
+// NewPayloadTooLarge creates an API Error for a request body that exceeds the allowed size.
+func NewPayloadTooLarge(messages ...string) *APIError {
+	return newDefinedAPIError(PayloadTooLargeErrorCode, messages)
+}
+
+// NewTooManyRequests creates an API Error for a request that was throttled.
+func NewTooManyRequests(messages ...string) *APIError {
+	return newDefinedAPIError(TooManyRequestsErrorCode, messages)
+}
+
+// NewUnprocessableEntity creates an API Error for a well formed request that can not be processed.
+func NewUnprocessableEntity(messages ...string) *APIError {
+	return newDefinedAPIError(UnprocessableErrorCode, messages)
 }
 
 // NewInternalServerError creates an API Error for an unexpected condition.
 func NewInternalServerError(messages ...string) *APIError {
-	message := InternalServerErrorMessage
-	if len(messages) > 0 {
-		message = strings.Join(messages, " - ")
-	}
-
-	return NewAPIError(http.StatusInternalServerError, message, "internal_error")
+	return newDefinedAPIError(InternalErrorCode, messages)
 }
 
 // HandleBusinessError handles errors from services and use cases. Converts the errors to their REST equivalent
@@ -79,15 +136,23 @@ func HandleBusinessError(err error) *APIError {
 	var bisErr *BusinessError
 	switch {
 	case errors.As(err, &bisErr):
-		if bisErr.Err == NotFoundErrorCode {
-			return NewResourceNotFound(bisErr.Msg)
-		} else if bisErr.Err == UnauthorizedErrorCode {
-			return NewUnauthorizedError(bisErr.Msg)
-		} else if !bisErr.Fatal {
-			return NewAPIError(http.StatusBadRequest, bisErr.Msg, bisErr.Err)
+		apiErr := businessErrorToAPIError(bisErr).WithCauses(bisErr.Causes...)
+		if len(bisErr.Key) > 0 {
+			apiErr.WithKey(bisErr.Key, bisErr.Args...)
 		}
-		return NewAPIError(http.StatusInternalServerError, bisErr.Msg, bisErr.Err)
+		return apiErr
 	default:
 		return NewInternalServerError(err.Error())
 	}
 }
+
+// businessErrorToAPIError returns the API Error with the status of the business error code definition.
+// The errors with unknown codes are bad requests, or internal errors when they are fatal
+func businessErrorToAPIError(bisErr *BusinessError) *APIError {
+	if definition, ok := Definition(bisErr.Err); ok {
+		return NewAPIError(definition.Status, bisErr.Msg, bisErr.Err)
+	} else if !bisErr.Fatal {
+		return NewAPIError(http.StatusBadRequest, bisErr.Msg, bisErr.Err)
+	}
+	return NewAPIError(http.StatusInternalServerError, bisErr.Msg, bisErr.Err)
+}
@@ -58,6 +58,66 @@ func TestNewUnauthorizedError(t *testing.T) {
 	assert.Equal(t, "unauthorized", err.Err)
 }
 
+func TestNewForbidden(t *testing.T) {
+	t.Log("NewForbidden should return a forbidden error")
+
+	err := NewForbidden("some error")
+
+	assert.Equal(t, http.StatusForbidden, err.Status)
+	assert.Equal(t, "some error", err.Message)
+	assert.Equal(t, "forbidden", err.Err)
+}
+
+func TestNewConflict(t *testing.T) {
+	t.Log("NewConflict should return a conflict error")
+
+	err := NewConflict("some error")
+
+	assert.Equal(t, http.StatusConflict, err.Status)
+	assert.Equal(t, "some error", err.Message)
+	assert.Equal(t, "conflict", err.Err)
+}
+
+func TestNewUnsupportedMediaType(t *testing.T) {
+	t.Log("NewUnsupportedMediaType should return an unsupported media type error")
+
+	err := NewUnsupportedMediaType("some error")
+
+	assert.Equal(t, http.StatusUnsupportedMediaType, err.Status)
+	assert.Equal(t, "some error", err.Message)
+	assert.Equal(t, "unsupported_media_type", err.Err)
+}
+
+func TestNewPayloadTooLarge(t *testing.T) {
+	t.Log("NewPayloadTooLarge should return a payload too large error")
+
+	err := NewPayloadTooLarge("some error")
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, err.Status)
+	assert.Equal(t, "some error", err.Message)
+	assert.Equal(t, "payload_too_large", err.Err)
+}
+
+func TestNewTooManyRequests(t *testing.T) {
+	t.Log("NewTooManyRequests should return a too many requests error")
+
+	err := NewTooManyRequests("some error")
+
+	assert.Equal(t, http.StatusTooManyRequests, err.Status)
+	assert.Equal(t, "some error", err.Message)
+	assert.Equal(t, "too_many_requests", err.Err)
+}
+
+func TestNewUnprocessableEntity(t *testing.T) {
+	t.Log("NewUnprocessableEntity should return an unprocessable entity error")
+
+	err := NewUnprocessableEntity("some error")
+
+	assert.Equal(t, http.StatusUnprocessableEntity, err.Status)
+	assert.Equal(t, "some error", err.Message)
+	assert.Equal(t, "unprocessable_entity", err.Err)
+}
+
 func TestHandleBusinessErrorWithResourceNotFoundError(t *testing.T) {
 	t.Log("NewResourceNotFound should be get when a business NotFoundError is passed by parameters")
 
@@ -82,6 +142,20 @@ func TestHandleBusinessErrorWithNonFatalBusinessError(t *testing.T) {
 	assert.Equal(t, "not_fatal_error", apiErr.Err)
 }
 
+func TestHandleBusinessErrorWithValidationErrorCauses(t *testing.T) {
+	t.Log("Bad Request Api error with causes should be get when a BusinessError with causes is passed by parameters")
+
+	cause := ErrorCause{Field: "address.country", Rule: "iso3166_1_alpha2", Message: "address country must be an ISO 3166-1 alpha-2 code"}
+	validationErr := NewValidationError("Validation error").WithCauses(cause)
+
+	apiErr := HandleBusinessError(validationErr)
+
+	assert.Equal(t, http.StatusBadRequest, apiErr.Status)
+	assert.Equal(t, "Validation error", apiErr.Message)
+	assert.Equal(t, ValidationErrorCode, apiErr.Err)
+	assert.Equal(t, []ErrorCause{cause}, apiErr.Causes)
+}
+
 func TestHandleBusinessErrorWithFatalBusinessError(t *testing.T) {
 	t.Log("ServerError Api error should be get when a fatal BusinessError is passed by parameters")
 
@@ -117,3 +191,98 @@ func TestHandleBusinessErrorWithUnauthorizedError(t *testing.T) {
 	assert.Equal(t, "unauthorized resource", apiErr.Message)
 	assert.Equal(t, "unauthorized", apiErr.Err)
 }
+
+func TestHandleBusinessErrorWithConflictError(t *testing.T) {
+	t.Log("Conflict Api error should be get when a ConflictError is passed by parameters")
+
+	conflictErr := NewConflictError("conflicting resource")
+
+	apiErr := HandleBusinessError(conflictErr)
+
+	assert.Equal(t, http.StatusConflict, apiErr.Status)
+	assert.Equal(t, "conflicting resource", apiErr.Message)
+	assert.Equal(t, "conflict", apiErr.Err)
+}
+
+func TestHandleBusinessErrorWithTooManyRequestsError(t *testing.T) {
+	t.Log("Too many requests Api error should be get when a TooManyRequestsError is passed by parameters")
+
+	throttledErr := NewTooManyRequestsError("retry later")
+
+	apiErr := HandleBusinessError(throttledErr)
+
+	assert.Equal(t, http.StatusTooManyRequests, apiErr.Status)
+	assert.Equal(t, "retry later", apiErr.Message)
+	assert.Equal(t, "too_many_requests", apiErr.Err)
+}
+
+func TestHandleBusinessErrorWithUnprocessableError(t *testing.T) {
+	t.Log("Unprocessable entity Api error should be get when an UnprocessableError is passed by parameters")
+
+	unprocessableErr := NewUnprocessableError("key was used with another request")
+
+	apiErr := HandleBusinessError(unprocessableErr)
+
+	assert.Equal(t, http.StatusUnprocessableEntity, apiErr.Status)
+	assert.Equal(t, "key was used with another request", apiErr.Message)
+	assert.Equal(t, "unprocessable_entity", apiErr.Err)
+}
+
+func TestHandleBusinessErrorWithForbiddenError(t *testing.T) {
+	t.Log("Forbidden Api error should be get when a ForbiddenError is passed by parameters")
+
+	forbiddenErr := NewForbiddenError("permission required")
+
+	apiErr := HandleBusinessError(forbiddenErr)
+
+	assert.Equal(t, http.StatusForbidden, apiErr.Status)
+	assert.Equal(t, "permission required", apiErr.Message)
+	assert.Equal(t, "forbidden", apiErr.Err)
+}
+
+func TestLocalizeWithDefaultMessage(t *testing.T) {
+	t.Log("Localized Api error should have the default message of its code in the language")
+
+	apiErr := NewResourceNotFound().Localize("es")
+
+	assert.Equal(t, http.StatusNotFound, apiErr.Status)
+	assert.Equal(t, "recurso no encontrado", apiErr.Message)
+	assert.Equal(t, "not_found", apiErr.Err)
+}
+
+func TestLocalizeWithKeyAndCauses(t *testing.T) {
+	t.Log("Localized Api error should have the message and causes with key in the language, and keep the other messages")
+
+	apiErr := NewBadRequest("request body is not valid").
+		WithKey("request_body_not_valid").
+		WithCauses(
+			ErrorCause{Field: "tags", Rule: "unknown", Message: "tags is not a known field", Key: "field_unknown", Args: []interface{}{"tags"}},
+			ErrorCause{Field: "email", Rule: "email", Message: "email must be a valid email address"})
+
+	localized := apiErr.Localize("es")
+
+	assert.Equal(t, "el cuerpo de la solicitud no es válido", localized.Message)
+	assert.Equal(t, "tags no es un campo conocido", localized.Causes[0].Message)
+	assert.Equal(t, "email must be a valid email address", localized.Causes[1].Message)
+	assert.Equal(t, "request body is not valid", apiErr.Message)
+	assert.Equal(t, "tags is not a known field", apiErr.Causes[0].Message)
+}
+
+func TestLocalizeWithoutKey(t *testing.T) {
+	t.Log("Localized Api error should keep a message without key")
+
+	apiErr := NewConflict("email is already used").Localize("es")
+
+	assert.Equal(t, "email is already used", apiErr.Message)
+}
+
+func TestHandleBusinessErrorWithKey(t *testing.T) {
+	t.Log("Api error should have the key of the BusinessError passed by parameters")
+
+	notFoundErr := NewNotFoundError("user not found").WithKey("user_not_found")
+
+	apiErr := HandleBusinessError(notFoundErr)
+
+	assert.Equal(t, "user not found", apiErr.Message)
+	assert.Equal(t, "usuario no encontrado", apiErr.Localize("es").Message)
+}
@@ -0,0 +1,160 @@
+package errors
+
+import (
+	"net/http"
+	"sort"
+)
+
+const (
+	BadRequestErrorCode           = "bad_request"
+	ValidationErrorCode           = "validation_error"
+	UnauthorizedErrorCode         = "unauthorized"
+	ForbiddenErrorCode            = "forbidden"
+	NotFoundErrorCode             = "not_found"
+	MethodNotAllowedErrorCode     = "method_not_allowed"
+	ConflictErrorCode             = "conflict"
+	PayloadTooLargeErrorCode      = "payload_too_large"
+	UnsupportedMediaTypeErrorCode = "unsupported_media_type"
+	UnprocessableErrorCode        = "unprocessable_entity"
+	TooManyRequestsErrorCode      = "too_many_requests"
+	InternalErrorCode             = "internal_error"
+	FatalErrorCode                = "fatal_error"
+)
+
+// ErrorDefinition describes an error code returned by the API
+type ErrorDefinition struct {
+	Code   string
+	Status int
+	// Message is the default message of the errors with the code
+	Message     string
+	Description string
+	// Retryable is true when the same request can succeed if it is sent again later
+	Retryable bool
+}
+
+// definitions are the error codes returned by the API
+var definitions = map[string]ErrorDefinition{}
+
+func init() {
+	for _, definition := range []ErrorDefinition{
+		{
+			Code:        BadRequestErrorCode,
+			Status:      http.StatusBadRequest,
+			Message:     BadRequestMessage,
+			Description: "The request parameters or body are not valid. The causes have the invalid fields.",
+		},
+		{
+			Code:        ValidationErrorCode,
+			Status:      http.StatusBadRequest,
+			Message:     "the request data is not valid",
+			Description: "The request data breaks a business rule, like a profile or custom attribute validation.",
+		},
+		{
+			Code:        UnauthorizedErrorCode,
+			Status:      http.StatusUnauthorized,
+			Message:     UnathorizedErrorMessage,
+			Description: "The request has no valid credentials: the access token or API key is missing, not valid or expired.",
+		},
+		{
+			Code:        ForbiddenErrorCode,
+			Status:      http.StatusForbidden,
+			Message:     ForbiddenMessage,
+			Description: "The caller is authenticated but has not the permission or API key scope required by the operation.",
+		},
+		{
+			Code:        NotFoundErrorCode,
+			Status:      http.StatusNotFound,
+			Message:     ResourceNotFoundMessage,
+			Description: "The route or the requested resource does not exist.",
+		},
+		{
+			Code:        MethodNotAllowedErrorCode,
+			Status:      http.StatusMethodNotAllowed,
+			Message:     MethodNotAllowedMessage,
+			Description: "The route exists but does not support the HTTP method.",
+		},
+		{
+			Code:        ConflictErrorCode,
+			Status:      http.StatusConflict,
+			Message:     ConflictMessage,
+			Description: "The request conflicts with the current state of the resource, like a duplicated email. A request with the same idempotency key still processed can be sent again later.",
+		},
+		{
+			Code:        PayloadTooLargeErrorCode,
+			Status:      http.StatusRequestEntityTooLarge,
+			Message:     PayloadTooLargeMessage,
+			Description: "The request body exceeds the allowed size.",
+		},
+		{
+			Code:        UnsupportedMediaTypeErrorCode,
+			Status:      http.StatusUnsupportedMediaType,
+			Message:     UnsupportedMediaMessage,
+			Description: "The content type of the request body is not supported by the operation.",
+		},
+		{
+			Code:        UnprocessableErrorCode,
+			Status:      http.StatusUnprocessableEntity,
+			Message:     UnprocessableMessage,
+			Description: "The request is well formed but can not be processed, like an idempotency key reused with a different request.",
+		},
+		{
+			Code:        TooManyRequestsErrorCode,
+			Status:      http.StatusTooManyRequests,
+			Message:     TooManyRequestsMessage,
+			Description: "The request was throttled. It can be sent again later.",
+			Retryable:   true,
+		},
+		{
+			Code:        InternalErrorCode,
+			Status:      http.StatusInternalServerError,
+			Message:     InternalServerErrorMessage,
+			Description: "An unexpected condition prevented the request to be processed.",
+			Retryable:   true,
+		},
+		{
+			Code:        FatalErrorCode,
+			Status:      http.StatusInternalServerError,
+			Message:     "unexpected error",
+			Description: "A service or dependency, like the database, failed to process the request.",
+			Retryable:   true,
+		},
+	} {
+		definitions[definition.Code] = definition
+	}
+}
+
+// Definition returns the definition of an error code. It returns false for an unknown code
+func Definition(code string) (ErrorDefinition, bool) {
+	definition, ok := definitions[code]
+	return definition, ok
+}
+
+// IsKnownCode returns if an error code has a definition
+func IsKnownCode(code string) bool {
+	_, ok := definitions[code]
+	return ok
+}
+
+// Definitions returns the definitions of all the error codes, sorted by status and code
+func Definitions() []ErrorDefinition {
+	all := make([]ErrorDefinition, 0, len(definitions))
+	for _, definition := range definitions {
+		all = append(all, definition)
+	}
+	sort.Slice(all, func(i, j int) bool {
+		if all[i].Status != all[j].Status {
+			return all[i].Status < all[j].Status
+		}
+		return all[i].Code < all[j].Code
+	})
+	return all
+}
+
+// mustDefinition returns the definition of an error code of the constructors. An unknown code is a programming error
+func mustDefinition(code string) ErrorDefinition {
+	definition, ok := definitions[code]
+	if !ok {
+		panic("errors: unknown error code " + code)
+	}
+	return definition
+}
@@ -0,0 +1,214 @@
+package errors
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/desarrollogj/golang-api-example/libs/i18n"
+	"github.com/stretchr/testify/assert"
+)
+
+// codeArguments are the constructors with an error code argument, and its position
+var codeArguments = map[string]int{
+	"NewBusinessError": 1,
+	"NewAPIError":      2,
+}
+
+func TestDefinitionsAreComplete(t *testing.T) {
+	t.Log("Every error definition should have a status, a default message translated in every language and a description")
+
+	for _, definition := range Definitions() {
+		assert.NotEmpty(t, definition.Code)
+		assert.GreaterOrEqual(t, definition.Status, 400, definition.Code)
+		assert.NotEmpty(t, definition.Description, definition.Code)
+
+		message, ok := i18n.Text(i18n.DefaultLanguage, definition.Code)
+		assert.True(t, ok, definition.Code)
+		assert.Equal(t, definition.Message, message, definition.Code)
+		for _, lang := range i18n.Languages {
+			_, ok := i18n.Text(lang, definition.Code)
+			assert.True(t, ok, lang+" "+definition.Code)
+		}
+	}
+}
+
+func TestDefinitionsAreSortedByStatus(t *testing.T) {
+	t.Log("Definitions should return all the error codes sorted by status and code")
+
+	all := Definitions()
+
+	assert.Len(t, all, len(definitions))
+	for i := 1; i < len(all); i++ {
+		assert.True(t, all[i-1].Status < all[i].Status ||
+			(all[i-1].Status == all[i].Status && all[i-1].Code < all[i].Code))
+	}
+}
+
+func TestConstructorsUseKnownCodes(t *testing.T) {
+	t.Log("The errors of every constructor should have a known code")
+
+	apiErrs := []*APIError{
+		NewBadRequest(),
+		NewResourceNotFound(),
+		NewMethodNotAllowed(),
+		NewUnauthorizedError(),
+		NewForbidden(),
+		NewConflict(),
+		NewUnsupportedMediaType(),
+		NewPayloadTooLarge(),
+		NewTooManyRequests(),
+		NewUnprocessableEntity(),
+		NewInternalServerError(),
+	}
+	for _, apiErr := range apiErrs {
+		definition, ok := Definition(apiErr.Err)
+		assert.True(t, ok, apiErr.Err)
+		assert.Equal(t, definition.Status, apiErr.Status, apiErr.Err)
+		assert.Equal(t, definition.Message, apiErr.Message, apiErr.Err)
+	}
+
+	bisErrs := []*BusinessError{
+		NewFatalError("msg"),
+		NewNotFoundError("msg"),
+		NewValidationError("msg"),
+		NewBusinessUnauthorizedError("msg"),
+		NewForbiddenError("msg"),
+		NewConflictError("msg"),
+		NewTooManyRequestsError("msg"),
+		NewUnprocessableError("msg"),
+	}
+	for _, bisErr := range bisErrs {
+		assert.True(t, IsKnownCode(bisErr.Err), bisErr.Err)
+	}
+}
+
+// messageConstructors are the constructors of the errors whose message is returned to the clients. The fatal errors
+// are left out, their messages are only in the default language
+var messageConstructors = map[string]bool{
+	"NewBusinessError":             true,
+	"NewValidationError":           true,
+	"NewNotFoundError":             true,
+	"NewBusinessUnauthorizedError": true,
+	"NewForbiddenError":            true,
+	"NewConflictError":             true,
+	"NewTooManyRequestsError":      true,
+	"NewUnprocessableError":        true,
+	"NewBadRequest":                true,
+	"NewResourceNotFound":          true,
+	"NewMethodNotAllowed":          true,
+	"NewUnauthorizedError":         true,
+	"NewForbidden":                 true,
+	"NewConflict":                  true,
+	"NewUnsupportedMediaType":      true,
+	"NewPayloadTooLarge":           true,
+	"NewTooManyRequests":           true,
+	"NewUnprocessableEntity":       true,
+	"NewInternalServerError":       true,
+}
+
+// keyArguments are the functions with a message key argument, and its position
+var keyArguments = map[string]int{
+	"WithKey":              0,
+	"fieldValidationError": 3,
+	"abortForbidden":       2,
+	"abortUnauthorized":    2,
+}
+
+// inspectSources calls the inspect function with every call of the application sources, skipping the tests,
+// the generated docs and this package
+func inspectSources(t *testing.T, inspect func(fset *token.FileSet, call *ast.CallExpr, name string, keyed bool)) {
+	root := filepath.Join("..", "..")
+	fset := token.NewFileSet()
+	err := filepath.WalkDir(root, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() && (entry.Name() == "docs" || strings.HasPrefix(entry.Name(), ".")) && path != root {
+			return filepath.SkipDir
+		}
+		if entry.IsDir() || !strings.HasSuffix(path, ".go") || strings.HasSuffix(path, "_test.go") {
+			return nil
+		}
+
+		file, err := parser.ParseFile(fset, path, nil, 0)
+		if err != nil {
+			return err
+		}
+		// The calls whose result is chained with a key
+		keyed := map[*ast.CallExpr]bool{}
+		ast.Inspect(file, func(node ast.Node) bool {
+			if call, ok := node.(*ast.CallExpr); ok {
+				if selector, ok := call.Fun.(*ast.SelectorExpr); ok && selector.Sel.Name == "WithKey" {
+					if inner, ok := selector.X.(*ast.CallExpr); ok {
+						keyed[inner] = true
+					}
+				}
+			}
+			return true
+		})
+		ast.Inspect(file, func(node ast.Node) bool {
+			call, ok := node.(*ast.CallExpr)
+			if !ok {
+				return true
+			}
+			name := ""
+			switch fun := call.Fun.(type) {
+			case *ast.SelectorExpr:
+				name = fun.Sel.Name
+			case *ast.Ident:
+				name = fun.Name
+			}
+			inspect(fset, call, name, keyed[call])
+			return true
+		})
+		return nil
+	})
+
+	assert.Nil(t, err)
+}
+
+func TestSourcesUseKnownCodes(t *testing.T) {
+	t.Log("The error codes written in the application sources should be in the definitions")
+
+	inspectSources(t, func(fset *token.FileSet, call *ast.CallExpr, name string, keyed bool) {
+		position, ok := codeArguments[name]
+		if !ok || len(call.Args) <= position {
+			return
+		}
+		if literal, ok := call.Args[position].(*ast.BasicLit); ok && literal.Kind == token.STRING {
+			code, _ := strconv.Unquote(literal.Value)
+			assert.True(t, IsKnownCode(code), "unknown error code %s at %s", code, fset.Position(literal.Pos()))
+		}
+	})
+}
+
+func TestSourcesUseKnownMessageKeys(t *testing.T) {
+	t.Log("The errors with a custom message in the application sources should have a message key of the catalogs")
+
+	inspectSources(t, func(fset *token.FileSet, call *ast.CallExpr, name string, keyed bool) {
+		if messageConstructors[name] && len(call.Args) > 0 && !isErrorsPackage(fset, call) {
+			assert.True(t, keyed, "error message without key at %s", fset.Position(call.Pos()))
+		}
+
+		position, ok := keyArguments[name]
+		if !ok || len(call.Args) <= position {
+			return
+		}
+		if literal, ok := call.Args[position].(*ast.BasicLit); ok && literal.Kind == token.STRING {
+			key, _ := strconv.Unquote(literal.Value)
+			_, known := i18n.Text(i18n.DefaultLanguage, key)
+			assert.True(t, known, "unknown message key %s at %s", key, fset.Position(literal.Pos()))
+		}
+	})
+}
+
+// isErrorsPackage returns true for the calls of this package, which create the errors from the messages of other errors
+func isErrorsPackage(fset *token.FileSet, call *ast.CallExpr) bool {
+	return filepath.Base(filepath.Dir(fset.Position(call.Pos()).Filename)) == "errors"
+}
@@ -8,14 +8,34 @@ type APIMessage struct {
 
 // BusinessError represents the standard error structure for the services and use cases error.
 type BusinessError struct {
-	Msg   string
-	Err   string
-	Fatal bool
+	Msg    string
+	Err    string
+	Fatal  bool
+	Causes []ErrorCause
+	// Key is the code of the message in the catalogs, and Args its arguments. The message is not translated without key
+	Key  string
+	Args []interface{}
 }
 
 // APIError represents the standard error structure for the HTTP responses.
 type APIError struct {
-	Status  int    `json:"status"`
-	Message string `json:"message"`
-	Err     string `json:"error"`
+	Status  int           `json:"status"`
+	Message string        `json:"message"`
+	Err     string        `json:"error"`
+	Causes  []ErrorCause  `json:"causes,omitempty"`
+	Key     string        `json:"-"`
+	Args    []interface{} `json:"-"`
+}
+
+// ErrorCause represents the detail of an invalid field of a request.
+type ErrorCause struct {
+	// Field is the JSON path of the field, like address.city or externalIds[0].source. It is empty for the whole request body
+	Field string `json:"field,omitempty"`
+	// Rule is the failed validation rule, like required or max
+	Rule string `json:"rule"`
+	// Param is the parameter of the rule, like the maximum length
+	Param   string        `json:"param,omitempty"`
+	Message string        `json:"message"`
+	Key     string        `json:"-"`
+	Args    []interface{} `json:"-"`
 }
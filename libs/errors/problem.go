@@ -0,0 +1,53 @@
+package errors
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/desarrollogj/golang-api-example/libs/i18n"
+)
+
+// ProblemContentType is the media type of the RFC 7807 Problem Details responses
+const ProblemContentType = "application/problem+json"
+
+// ProblemTypeBlank is the problem type of the errors without a type URI, whose title is the HTTP status text
+const ProblemTypeBlank = "about:blank"
+
+// ProblemDetails represents an error response in the RFC 7807 Problem Details format.
+// The error code and the causes are extension members
+type ProblemDetails struct {
+	// Type is the URI that identifies the problem type, the error code under the problem types base URI
+	Type string `json:"type"`
+	// Title is the summary of the problem type, the same for all the problems of the type
+	Title  string `json:"title"`
+	Status int    `json:"status"`
+	// Detail is the explanation of this occurrence of the problem
+	Detail string `json:"detail,omitempty"`
+	// Instance is the URI of the request that originated the problem
+	Instance string       `json:"instance,omitempty"`
+	Code     string       `json:"code"`
+	Causes   []ErrorCause `json:"causes,omitempty"`
+}
+
+// Problem returns the error in the Problem Details format, in a language. The type is the error code under
+// the problem types base URI, or about:blank without base URI. The instance is the URI of the request
+func (e *APIError) Problem(lang string, typeBaseURI string, instance string) *ProblemDetails {
+	localized := e.Localize(lang)
+
+	problem := &ProblemDetails{
+		Type:     ProblemTypeBlank,
+		Title:    http.StatusText(e.Status),
+		Status:   e.Status,
+		Detail:   localized.Message,
+		Instance: instance,
+		Code:     e.Err,
+		Causes:   localized.Causes,
+	}
+	if len(typeBaseURI) > 0 && len(e.Err) > 0 {
+		problem.Type = strings.TrimSuffix(typeBaseURI, "/") + "/" + e.Err
+		if title, ok := i18n.Text(lang, e.Err); ok {
+			problem.Title = title
+		}
+	}
+	return problem
+}
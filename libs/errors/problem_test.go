@@ -0,0 +1,43 @@
+package errors
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProblemWithTypeBaseURI(t *testing.T) {
+	t.Log("Problem should return the error code under the base URI as type, and the translated messages")
+
+	err := NewResourceNotFound("user not found").WithKey("user_not_found")
+
+	problem := err.Problem("es", "https://api.example.com/problems", "/api/v1/users/123")
+
+	assert.Equal(t, &ProblemDetails{
+		Type:     "https://api.example.com/problems/not_found",
+		Title:    "recurso no encontrado",
+		Status:   http.StatusNotFound,
+		Detail:   "usuario no encontrado",
+		Instance: "/api/v1/users/123",
+		Code:     "not_found",
+	}, problem)
+}
+
+func TestProblemWithoutTypeBaseURI(t *testing.T) {
+	t.Log("Problem should return about:blank as type and the HTTP status text as title without base URI")
+
+	err := NewBadRequest("some error").WithCauses(ErrorCause{Field: "email", Rule: "required", Message: "email is required"})
+
+	problem := err.Problem("en", "", "/api/v1/users")
+
+	assert.Equal(t, &ProblemDetails{
+		Type:     ProblemTypeBlank,
+		Title:    "Bad Request",
+		Status:   http.StatusBadRequest,
+		Detail:   "some error",
+		Instance: "/api/v1/users",
+		Code:     "bad_request",
+		Causes:   []ErrorCause{{Field: "email", Rule: "required", Message: "email is required"}},
+	}, problem)
+}
@@ -5,17 +5,23 @@ import (
 	"net/http"
 )
 
-const (
-	FatalErrorCode        = "fatal_error"
-	NotFoundErrorCode     = "not_found"
-	ValidationErrorCode   = "validation_error"
-	UnauthorizedErrorCode = "unauthorized"
-)
-
 func (e *BusinessError) Error() string {
 	return e.Msg
 }
 
+// WithCauses adds the causes of the error, like the invalid fields of a validation error
+func (e *BusinessError) WithCauses(causes ...ErrorCause) *BusinessError {
+	e.Causes = append(e.Causes, causes...)
+	return e
+}
+
+// WithKey sets the code of the message in the catalogs, with its arguments, to translate it
+func (e *BusinessError) WithKey(key string, args ...interface{}) *BusinessError {
+	e.Key = key
+	e.Args = args
+	return e
+}
+
 // NewBusinessError creates and initializes a BusinessError.
 func NewBusinessError(msg string, err string) *BusinessError {
 	return &BusinessError{
@@ -25,40 +31,54 @@ func NewBusinessError(msg string, err string) *BusinessError {
 	}
 }
 
-// NewFatalError creates and initializes a BusinessError with fatal mark
-func NewFatalError(msg string) *BusinessError {
+// newDefinedBusinessError creates a BusinessError with a code of the definitions. The errors of the server error codes are fatal
+func newDefinedBusinessError(msg string, code string) *BusinessError {
+	definition := mustDefinition(code)
 	return &BusinessError{
 		Msg:   msg,
-		Err:   FatalErrorCode,
-		Fatal: true,
+		Err:   definition.Code,
+		Fatal: definition.Status >= http.StatusInternalServerError,
 	}
 }
 
+// NewFatalError creates and initializes a BusinessError with fatal mark
+func NewFatalError(msg string) *BusinessError {
+	return newDefinedBusinessError(msg, FatalErrorCode)
+}
+
 // NewNotFoundError creates and initializes a not found BusinessError
 func NewNotFoundError(msg string) *BusinessError {
-	return &BusinessError{
-		Msg:   msg,
-		Err:   NotFoundErrorCode,
-		Fatal: false,
-	}
+	return newDefinedBusinessError(msg, NotFoundErrorCode)
 }
 
 // NewValidationError creates and initializes a validation BusinessError
 func NewValidationError(msg string) *BusinessError {
-	return &BusinessError{
-		Msg:   msg,
-		Err:   ValidationErrorCode,
-		Fatal: false,
-	}
+	return newDefinedBusinessError(msg, ValidationErrorCode)
 }
 
 // NewBusinessUnauthorizedError creates and initializes an unauthorized BusinessError
 func NewBusinessUnauthorizedError(msg string) *BusinessError {
-	return &BusinessError{
-		Msg:   msg,
-		Err:   UnauthorizedErrorCode,
-		Fatal: false,
-	}
+	return newDefinedBusinessError(msg, UnauthorizedErrorCode)
+}
+
+// NewForbiddenError creates and initializes a forbidden BusinessError
+func NewForbiddenError(msg string) *BusinessError {
+	return newDefinedBusinessError(msg, ForbiddenErrorCode)
+}
+
+// NewConflictError creates and initializes a conflict BusinessError
+func NewConflictError(msg string) *BusinessError {
+	return newDefinedBusinessError(msg, ConflictErrorCode)
+}
+
+// NewTooManyRequestsError creates and initializes a throttling BusinessError
+func NewTooManyRequestsError(msg string) *BusinessError {
+	return newDefinedBusinessError(msg, TooManyRequestsErrorCode)
+}
+
+// NewUnprocessableError creates and initializes a BusinessError for a well formed request that can not be processed
+func NewUnprocessableError(msg string) *BusinessError {
+	return newDefinedBusinessError(msg, UnprocessableErrorCode)
 }
 
 // HandleFetcherResponse handles errors from fetchers returning an BusinessError
@@ -74,6 +94,8 @@ func HandleFetcherErrorResponse(status int, response []byte) *BusinessError {
 		return NewBusinessError(apiErr.Message, apiErr.Err)
 	case http.StatusUnauthorized:
 		return NewBusinessUnauthorizedError(apiErr.Message)
+	case http.StatusForbidden:
+		return NewForbiddenError(apiErr.Message)
 	case http.StatusNotFound:
 		return NewNotFoundError(apiErr.Message)
 	default:
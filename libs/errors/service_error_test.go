@@ -64,6 +64,57 @@ func TestNewValidationErrorError(t *testing.T) {
 	assert.False(t, err.Fatal)
 }
 
+func TestNewValidationErrorWithCauses(t *testing.T) {
+	t.Log("New validation error with causes should return a new validation error with the invalid fields")
+
+	err := NewValidationError("test message").WithCauses(ErrorCause{Field: "email", Rule: "email", Message: "email is not valid"})
+
+	assert.Equal(t, "test message", err.Error())
+	assert.Equal(t, ValidationErrorCode, err.Err)
+	assert.Equal(t, []ErrorCause{{Field: "email", Rule: "email", Message: "email is not valid"}}, err.Causes)
+}
+
+func TestNewConflictError(t *testing.T) {
+	t.Log("New conflict error should return a new conflict error")
+
+	err := NewConflictError("test message")
+
+	assert.Equal(t, "test message", err.Error())
+	assert.Equal(t, "test message", err.Msg)
+	assert.Equal(t, ConflictErrorCode, err.Err)
+	assert.False(t, err.Fatal)
+}
+
+func TestNewTooManyRequestsError(t *testing.T) {
+	t.Log("New too many requests error should return a new throttling error")
+
+	err := NewTooManyRequestsError("test message")
+
+	assert.Equal(t, "test message", err.Error())
+	assert.Equal(t, TooManyRequestsErrorCode, err.Err)
+	assert.False(t, err.Fatal)
+}
+
+func TestNewUnprocessableError(t *testing.T) {
+	t.Log("New unprocessable error should return a new unprocessable error")
+
+	err := NewUnprocessableError("test message")
+
+	assert.Equal(t, "test message", err.Error())
+	assert.Equal(t, UnprocessableErrorCode, err.Err)
+	assert.False(t, err.Fatal)
+}
+
+func TestNewForbiddenError(t *testing.T) {
+	t.Log("New forbidden error should return a new forbidden error")
+
+	err := NewForbiddenError("test message")
+
+	assert.Equal(t, "test message", err.Error())
+	assert.Equal(t, ForbiddenErrorCode, err.Err)
+	assert.False(t, err.Fatal)
+}
+
 func TestHandleFetcherErrorResponseBadRequest(t *testing.T) {
 	t.Log("Handle fetcher error response should return a Business Error when a bad request response was received")
 
@@ -124,6 +175,25 @@ func TestHandleFetcherErrorResponseUnauthorized(t *testing.T) {
 	assert.False(t, err.Fatal)
 }
 
+func TestHandleFetcherErrorResponseForbidden(t *testing.T) {
+	t.Log("Handle fetcher error response should return a Business Error when a forbidden response was received")
+
+	response := APIError{
+		Status:  403,
+		Err:     "forbidden",
+		Message: "User forbidden",
+	}
+	buffer := new(bytes.Buffer)
+	json.NewEncoder(buffer).Encode(response)
+	responseData := buffer.Bytes()
+
+	err := HandleFetcherErrorResponse(http.StatusForbidden, responseData)
+
+	assert.Equal(t, response.Message, err.Error())
+	assert.Equal(t, response.Err, err.Err)
+	assert.False(t, err.Fatal)
+}
+
 func TestHandleFetcherErrorResponseInternalServerError(t *testing.T) {
 	t.Log("Handle fetcher error response should return a Business Error when an internal server error was received")
 
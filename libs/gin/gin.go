@@ -3,11 +3,35 @@ package gin
 import (
 	"fmt"
 	"strconv"
+	"strings"
 
 	"github.com/desarrollogj/golang-api-example/libs/errors"
+	"github.com/desarrollogj/golang-api-example/libs/i18n"
 	"github.com/gin-gonic/gin"
 )
 
+const (
+	// LanguageKey is the context key of the language of the response messages
+	LanguageKey = "language"
+	// ErrorFormatKey is the context key of the format of the error responses
+	ErrorFormatKey = "errorFormat"
+	// ProblemTypeBaseURIKey is the context key of the base URI of the problem types
+	ProblemTypeBaseURIKey = "problemTypeBaseURI"
+)
+
+const (
+	// ErrorFormatJSON is the format of the errors as APIError JSON documents
+	ErrorFormatJSON = "json"
+	// ErrorFormatProblem is the format of the errors as RFC 7807 Problem Details documents
+	ErrorFormatProblem = "problem"
+)
+
+// errorFormatMediaTypes are the media types of the error formats, to negotiate them with the Accept header
+var errorFormatMediaTypes = map[string]string{
+	ErrorFormatJSON:    gin.MIMEJSON,
+	ErrorFormatProblem: errors.ProblemContentType,
+}
+
 // WrapperFunc is the func type for the custom handlers.
 type WrapperFunc func(c *gin.Context) *errors.APIError
 
@@ -15,21 +39,108 @@ type WrapperFunc func(c *gin.Context) *errors.APIError
 func ErrorWrapper(handlerFunc WrapperFunc, c *gin.Context) {
 	err := handlerFunc(c)
 	if err != nil {
-		c.JSON(err.Status, err)
+		RenderError(c, err)
+	}
+}
+
+// AbortWithError aborts the request with the error, in the request language
+func AbortWithError(c *gin.Context, err *errors.APIError) {
+	c.Abort()
+	RenderError(c, err)
+}
+
+// RenderError writes the error response in the request language and error format. The messages without key, like the
+// unexpected errors, are written in the default language, so the Content-Language header is changed to it
+func RenderError(c *gin.Context, err *errors.APIError) {
+	lang := GetLanguage(c)
+	if _, ok := i18n.Text(lang, err.Key, err.Args...); !ok {
+		c.Header("Content-Language", i18n.DefaultLanguage)
+	}
+	if GetErrorFormat(c) != ErrorFormatProblem {
+		c.JSON(err.Status, err.Localize(lang))
+		return
+	}
+
+	// The JSON render keeps the content type when it is already set
+	c.Header("Content-Type", errors.ProblemContentType)
+	c.JSON(err.Status, err.Problem(lang, c.GetString(ProblemTypeBaseURIKey), c.Request.URL.RequestURI()))
+}
+
+// IsErrorFormat returns if a format of the error responses is supported
+func IsErrorFormat(format string) bool {
+	_, ok := errorFormatMediaTypes[format]
+	return ok
+}
+
+// ErrorFormatMiddleware creates a middleware that selects the format of the error responses with the Accept header,
+// between the APIError JSON and the Problem Details formats. The default format is used when the Accept header prefers none of them.
+// The problem types are the error codes under the base URI, or about:blank without base URI
+func ErrorFormatMiddleware(defaultFormat string, problemTypeBaseURI string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(ErrorFormatKey, negotiateErrorFormat(c, defaultFormat))
+		c.Set(ProblemTypeBaseURIKey, problemTypeBaseURI)
+		c.Next()
+	}
+}
+
+// GetErrorFormat returns the format of the error responses. Without the error format middleware, it is selected with the Accept header
+func GetErrorFormat(c *gin.Context) string {
+	if format := c.GetString(ErrorFormatKey); len(format) > 0 {
+		return format
+	}
+	return negotiateErrorFormat(c, ErrorFormatJSON)
+}
+
+// negotiateErrorFormat returns the error format preferred by the Accept header, or the default format when it prefers none of them
+func negotiateErrorFormat(c *gin.Context, defaultFormat string) string {
+	offered := []string{errorFormatMediaTypes[defaultFormat]}
+	for format, mediaType := range errorFormatMediaTypes {
+		if format != defaultFormat {
+			offered = append(offered, mediaType)
+		}
+	}
+
+	switch c.NegotiateFormat(offered...) {
+	case errors.ProblemContentType:
+		return ErrorFormatProblem
+	case gin.MIMEJSON:
+		return ErrorFormatJSON
+	default:
+		return defaultFormat
+	}
+}
+
+// LanguageMiddleware creates a middleware that selects the language of the response messages with the Accept-Language header,
+// and returns it in the Content-Language header. The default language is used when no supported language is accepted
+func LanguageMiddleware(defaultLanguage string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		lang := i18n.Negotiate(c.GetHeader("Accept-Language"), defaultLanguage)
+		c.Set(LanguageKey, lang)
+		c.Header("Content-Language", lang)
+		c.Next()
+	}
+}
+
+// GetLanguage returns the language of the response messages. Without the language middleware, it is selected with the Accept-Language header
+func GetLanguage(c *gin.Context) string {
+	if lang := c.GetString(LanguageKey); len(lang) > 0 {
+		return lang
 	}
+	return i18n.Negotiate(c.GetHeader("Accept-Language"), i18n.DefaultLanguage)
 }
 
 // NoRouteHandler handles requests for non registered routes
 func NoRouteHandler(c *gin.Context) {
 	ErrorWrapper(func(c *gin.Context) *errors.APIError {
-		return errors.NewResourceNotFound(fmt.Sprintf("Resource not found for %s.", c.Request.URL.Path))
+		return errors.NewResourceNotFound(fmt.Sprintf("Resource not found for %s.", c.Request.URL.Path)).WithKey("route_not_found", c.Request.URL.Path)
 	}, c)
 }
 
 // MethodNotAllowedHandler handles requests for registered routes with invalid http methods on their requests
 func MethodNotAllowedHandler(c *gin.Context) {
 	ErrorWrapper(func(c *gin.Context) *errors.APIError {
-		return errors.NewMethodNotAllowed("Method not allowed - %s - %s ", c.Request.Method, c.Request.URL.Path)
+		return errors.NewMethodNotAllowed(fmt.Sprintf("Method not allowed - %s - %s", c.Request.Method, c.Request.URL.Path)).
+			WithKey("route_method_not_allowed", c.Request.Method, c.Request.URL.Path)
 	}, c)
 }
 
@@ -42,3 +153,24 @@ func GetIntQuery(key string, c *gin.Context) int {
 	}
 	return intValue
 }
+
+// GetBoolQuery recovers a boolean value from the querystring
+func GetBoolQuery(key string, c *gin.Context) bool {
+	value := c.Query(key)
+	boolValue, err := strconv.ParseBool(value)
+	if err != nil {
+		boolValue = false
+	}
+	return boolValue
+}
+
+// GetListQuery recovers a comma separated list from the querystring. Empty values are ignored
+func GetListQuery(key string, c *gin.Context) []string {
+	var values []string
+	for _, value := range strings.Split(c.Query(key), ",") {
+		if value = strings.TrimSpace(value); len(value) > 0 {
+			values = append(values, value)
+		}
+	}
+	return values
+}
@@ -0,0 +1,44 @@
+package gin
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/desarrollogj/golang-api-example/libs/errors"
+	"github.com/desarrollogj/golang-api-example/libs/i18n"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMethodNotAllowedHandler_GivenARouteWithAnotherMethod_WhenRequest_ThenReturnTheMethodAndPath(t *testing.T) {
+	t.Log("Failure to request a route with a not allowed method, in the request language")
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.HandleMethodNotAllowed = true
+	r.Use(LanguageMiddleware(i18n.English))
+	r.NoMethod(MethodNotAllowedHandler)
+	r.GET("/api/v1/users", func(c *gin.Context) {})
+
+	messages := map[string]string{
+		"en": "Method not allowed - DELETE - /api/v1/users",
+		"es": "método no permitido - DELETE - /api/v1/users",
+	}
+	for lang, message := range messages {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodDelete, "/api/v1/users", nil)
+		req.Header.Set("Accept-Language", lang)
+
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+		assert.Equal(t, lang, w.Header().Get("Content-Language"))
+
+		var err errors.APIError
+		json.NewDecoder(w.Body).Decode(&err)
+
+		assert.Equal(t, message, err.Message)
+	}
+}
@@ -0,0 +1,266 @@
+package i18n
+
+// catalogs are the messages of each language, keyed by the error codes and the codes of the specific messages.
+// The messages are fmt formats, and the arguments are not translated
+var catalogs = map[string]map[string]string{
+	English: {
+		// Error codes
+		"bad_request":            "invalid request parameters",
+		"not_found":              "resource not found",
+		"method_not_allowed":     "method not allowed on the current resource",
+		"internal_error":         "internal Server Error",
+		"unauthorized":           "unauthorized",
+		"forbidden":              "access to the resource is forbidden",
+		"conflict":               "the request conflicts with the current state of the resource",
+		"unsupported_media_type": "unsupported media type",
+		"payload_too_large":      "request body is too large",
+		"too_many_requests":      "too many requests",
+		"unprocessable_entity":   "the request can not be processed",
+		"validation_error":       "the request data is not valid",
+		"fatal_error":            "unexpected error",
+
+		// Requests
+		"request_body_not_valid":       "request body is not valid",
+		"request_body_required":        "request body is required",
+		"request_body_incomplete":      "request body is not a complete JSON document",
+		"request_body_malformed":       "request body is not a valid JSON document, error at offset %d",
+		"request_body_type":            "request body must be of type %s",
+		"field_type":                   "%s must be of type %s",
+		"field_unknown":                "%s is not a known field",
+		"user_id_required":             "user id is required",
+		"group_id_required":            "group id is required",
+		"request_body_unreadable":      "unable to read the request body",
+		"route_not_found":              "Resource not found for %s.",
+		"route_method_not_allowed":     "Method not allowed - %s - %s",
+		"include_inactive_forbidden":   "only administrators can include inactive users",
+		"patch_content_type_not_valid": "content type must be %s or %s",
+		"patch_not_valid":              "patch document is not valid",
+		"patch_not_applicable":         "patch can not be applied",
+		"patch_test_failed":            "patch test operation failed",
+		"export_format_not_valid":      "format must be json or zip",
+		"export_file_failed":           "unexpected error when create the export file",
+
+		// Authentication and authorization
+		"access_token_required":        "bearer access token is required",
+		"access_token_not_valid":       "access token is not valid or expired",
+		"caller_identity_required":     "caller identity is required",
+		"permission_required":          "permission %s is required",
+		"api_key_scope_required":       "API key requires the %s scope",
+		"credentials_not_valid":        "email or password is not valid",
+		"api_key_not_valid":            "API key is not valid, expired or revoked",
+		"api_key_id_required":          "API key id is required",
+		"api_key_not_found":            "API key not found",
+		"api_key_revoked":              "API key was revoked and can not be rotated",
+		"api_key_expiration_not_valid": "API key expiration date must be in the future",
+		"api_key_scopes_required":      "API key requires at least one scope",
+		"api_key_scope_not_valid":      "API key scope %s is not valid, valid scopes are %s",
+
+		// Users
+		"user_not_found":                   "user not found",
+		"user_erased":                      "user was erased and can not be updated",
+		"user_modified":                    "the user was modified while it was updated, try again",
+		"user_undecryptable":               "the user personal data can not be decrypted, so the user can not be updated",
+		"user_phone_not_valid":             "phone must be an international number in E.164 format",
+		"user_birth_date_not_valid":        "birth date must be between 1900-01-01 and today",
+		"user_locale_not_valid":            "locale must be a valid BCP 47 language tag",
+		"user_timezone_not_valid":          "timezone must be a valid IANA time zone name",
+		"user_country_not_valid":           "address country must be an ISO 3166-1 alpha-2 code",
+		"verification_token_invalid":       "verification token is not valid or expired",
+		"user_name_search_encrypted":       "users can not be searched by first or last name when the personal data is encrypted",
+		"user_password_forbidden":          "only the user or an administrator can set the user password",
+		"current_password_not_valid":       "current password is not valid",
+		"user_merged":                      "user was merged into %s and can not be updated",
+		"user_merge_itself":                "an user can not be merged into itself",
+		"duplicate_user_not_found":         "duplicate user not found",
+		"user_status_transition_not_valid": "user can not change from %s to %s",
+		"user_suspension_reason_required":  "a reason is required to suspend an user",
+		"user_role_not_valid":              "role %s is not valid",
+		"user_own_roles_forbidden":         "users can not change their own roles",
+		"user_email_taken":                 "the email belongs to another user",
+		"user_email_already_verified":      "user email is already verified",
+		"verification_email_already_sent":  "verification email was already sent, retry in %d seconds",
+		"password_too_long":                "password must have at most %d bytes",
+		"password_policy_not_met":          "password does not meet the password policy",
+		"password_min_length":              "password must have at least %d characters",
+		"password_uppercase_required":      "password must have an uppercase letter",
+		"password_lowercase_required":      "password must have a lowercase letter",
+		"password_digit_required":          "password must have a digit",
+		"password_symbol_required":         "password must have a symbol",
+
+		// Avatars
+		"avatar_required":               "avatar image is required",
+		"avatar_file_required":          "avatar file is required",
+		"avatar_too_large":              "avatar image is too large, the maximum size is %d bytes",
+		"avatar_dimensions_too_large":   "avatar image is too large, the maximum dimensions are %dx%d pixels",
+		"avatar_format_not_valid":       "avatar image must be a JPEG or PNG image",
+		"avatar_content_type_not_valid": "content type must be multipart/form-data, image/jpeg or image/png",
+		"avatar_not_valid":              "avatar image is not valid",
+		"avatar_size_not_valid":         "avatar size %d is not valid, valid sizes are %s",
+		"avatar_size_param_not_valid":   "size is not valid",
+		"user_avatar_not_found":         "user avatar not found",
+
+		// Tags, external ids and attributes
+		"tag_required":                      "tag is required",
+		"tag_too_long":                      "tag %s is longer than %d characters",
+		"tag_not_valid":                     "tag %s is not valid",
+		"user_tags_limit":                   "an user can not have more than %d tags",
+		"user_tag_not_found":                "user tag not found",
+		"external_id_source_required":       "external id source is required",
+		"external_id_source_too_long":       "external id source %s is longer than %d characters",
+		"external_id_source_not_valid":      "external id source %s is not valid",
+		"external_id_param_required":        "external id is required",
+		"external_id_required":              "external id of source %s is required",
+		"external_id_too_long":              "external id of source %s is longer than %d characters",
+		"user_external_ids_limit":           "an user can not have more than %d external ids",
+		"external_id_taken":                 "external id %s/%s belongs to another user",
+		"external_id_taken_by_another_user": "an external id belongs to another user",
+		"attribute_not_valid":               "attribute %s is not valid",
+		"attribute_required":                "attribute %s is required",
+		"attribute_string":                  "attribute %s must be a string",
+		"attribute_number":                  "attribute %s must be a number",
+		"attribute_boolean":                 "attribute %s must be a boolean",
+		"attribute_date":                    "attribute %s must be a date (%s)",
+		"attribute_oneof":                   "attribute %s must be one of %s",
+		"attribute_filter_value_required":   "attribute %s filter value is required",
+		"attribute_filter_not_valid":        "filter %s is not valid, the format is attr.<name>==<value>",
+
+		// Groups
+		"group_not_found":        "group not found",
+		"group_member_not_found": "group member not found",
+
+		// Idempotency
+		"idempotency_key_too_long":   "idempotency key is too long",
+		"idempotency_key_reused":     "idempotency key was already used with a different request",
+		"idempotency_key_processing": "a request with the same idempotency key is being processed",
+	},
+	Spanish: {
+		// Error codes
+		"bad_request":            "parámetros de la solicitud no válidos",
+		"not_found":              "recurso no encontrado",
+		"method_not_allowed":     "método no permitido en el recurso",
+		"internal_error":         "error interno del servidor",
+		"unauthorized":           "no autorizado",
+		"forbidden":              "el acceso al recurso está prohibido",
+		"conflict":               "la solicitud entra en conflicto con el estado actual del recurso",
+		"unsupported_media_type": "tipo de contenido no soportado",
+		"payload_too_large":      "el cuerpo de la solicitud es demasiado grande",
+		"too_many_requests":      "demasiadas solicitudes",
+		"unprocessable_entity":   "la solicitud no puede ser procesada",
+		"validation_error":       "los datos de la solicitud no son válidos",
+		"fatal_error":            "error inesperado",
+
+		// Requests
+		"request_body_not_valid":       "el cuerpo de la solicitud no es válido",
+		"request_body_required":        "el cuerpo de la solicitud es requerido",
+		"request_body_incomplete":      "el cuerpo de la solicitud no es un documento JSON completo",
+		"request_body_malformed":       "el cuerpo de la solicitud no es un documento JSON válido, error en la posición %d",
+		"request_body_type":            "el cuerpo de la solicitud debe ser de tipo %s",
+		"field_type":                   "%s debe ser de tipo %s",
+		"field_unknown":                "%s no es un campo conocido",
+		"user_id_required":             "el id del usuario es requerido",
+		"group_id_required":            "el id del grupo es requerido",
+		"request_body_unreadable":      "no se pudo leer el cuerpo de la solicitud",
+		"route_not_found":              "recurso no encontrado para %s.",
+		"route_method_not_allowed":     "método no permitido - %s - %s",
+		"include_inactive_forbidden":   "solo los administradores pueden incluir los usuarios inactivos",
+		"patch_content_type_not_valid": "el tipo de contenido debe ser %s o %s",
+		"patch_not_valid":              "el documento de modificación no es válido",
+		"patch_not_applicable":         "la modificación no puede ser aplicada",
+		"patch_test_failed":            "la operación test de la modificación falló",
+		"export_format_not_valid":      "el formato debe ser json o zip",
+		"export_file_failed":           "error inesperado al crear el archivo de exportación",
+
+		// Authentication and authorization
+		"access_token_required":        "se requiere un token de acceso bearer",
+		"access_token_not_valid":       "el token de acceso no es válido o expiró",
+		"caller_identity_required":     "se requiere la identidad del solicitante",
+		"permission_required":          "se requiere el permiso %s",
+		"api_key_scope_required":       "la API key requiere el alcance %s",
+		"credentials_not_valid":        "el email o la contraseña no son válidos",
+		"api_key_not_valid":            "la API key no es válida, expiró o fue revocada",
+		"api_key_id_required":          "el id de la API key es requerido",
+		"api_key_not_found":            "API key no encontrada",
+		"api_key_revoked":              "la API key fue revocada y no puede ser rotada",
+		"api_key_expiration_not_valid": "la fecha de expiración de la API key debe ser futura",
+		"api_key_scopes_required":      "la API key requiere al menos un alcance",
+		"api_key_scope_not_valid":      "el alcance %s de la API key no es válido, los alcances válidos son %s",
+
+		// Users
+		"user_not_found":                   "usuario no encontrado",
+		"user_erased":                      "el usuario fue borrado y no puede ser actualizado",
+		"user_modified":                    "el usuario fue modificado mientras se actualizaba, intente nuevamente",
+		"user_undecryptable":               "los datos personales del usuario no pueden ser descifrados, por lo que el usuario no puede ser actualizado",
+		"user_phone_not_valid":             "el teléfono debe ser un número internacional en formato E.164",
+		"user_birth_date_not_valid":        "la fecha de nacimiento debe estar entre 1900-01-01 y hoy",
+		"user_locale_not_valid":            "el idioma debe ser una etiqueta de idioma BCP 47 válida",
+		"user_timezone_not_valid":          "la zona horaria debe ser un nombre de zona horaria IANA válido",
+		"user_country_not_valid":           "el país de la dirección debe ser un código ISO 3166-1 alfa-2",
+		"verification_token_invalid":       "el token de verificación no es válido o expiró",
+		"user_name_search_encrypted":       "los usuarios no pueden ser buscados por nombre o apellido cuando los datos personales están encriptados",
+		"user_password_forbidden":          "solo el usuario o un administrador pueden establecer la contraseña del usuario",
+		"current_password_not_valid":       "la contraseña actual no es válida",
+		"user_merged":                      "el usuario fue fusionado en %s y no puede ser actualizado",
+		"user_merge_itself":                "un usuario no puede ser fusionado consigo mismo",
+		"duplicate_user_not_found":         "usuario duplicado no encontrado",
+		"user_status_transition_not_valid": "el usuario no puede cambiar de %s a %s",
+		"user_suspension_reason_required":  "se requiere un motivo para suspender un usuario",
+		"user_role_not_valid":              "el rol %s no es válido",
+		"user_own_roles_forbidden":         "los usuarios no pueden cambiar sus propios roles",
+		"user_email_taken":                 "el email pertenece a otro usuario",
+		"user_email_already_verified":      "el email del usuario ya está verificado",
+		"verification_email_already_sent":  "el email de verificación ya fue enviado, reintente en %d segundos",
+		"password_too_long":                "la contraseña debe tener como máximo %d bytes",
+		"password_policy_not_met":          "la contraseña no cumple la política de contraseñas",
+		"password_min_length":              "la contraseña debe tener al menos %d caracteres",
+		"password_uppercase_required":      "la contraseña debe tener una letra mayúscula",
+		"password_lowercase_required":      "la contraseña debe tener una letra minúscula",
+		"password_digit_required":          "la contraseña debe tener un dígito",
+		"password_symbol_required":         "la contraseña debe tener un símbolo",
+
+		// Avatars
+		"avatar_required":               "la imagen del avatar es requerida",
+		"avatar_file_required":          "el archivo del avatar es requerido",
+		"avatar_too_large":              "la imagen del avatar es demasiado grande, el tamaño máximo es %d bytes",
+		"avatar_dimensions_too_large":   "la imagen del avatar es demasiado grande, las dimensiones máximas son %dx%d píxeles",
+		"avatar_format_not_valid":       "la imagen del avatar debe ser una imagen JPEG o PNG",
+		"avatar_content_type_not_valid": "el tipo de contenido debe ser multipart/form-data, image/jpeg o image/png",
+		"avatar_not_valid":              "la imagen del avatar no es válida",
+		"avatar_size_not_valid":         "el tamaño de avatar %d no es válido, los tamaños válidos son %s",
+		"avatar_size_param_not_valid":   "el tamaño no es válido",
+		"user_avatar_not_found":         "avatar del usuario no encontrado",
+
+		// Tags, external ids and attributes
+		"tag_required":                      "la etiqueta es requerida",
+		"tag_too_long":                      "la etiqueta %s tiene más de %d caracteres",
+		"tag_not_valid":                     "la etiqueta %s no es válida",
+		"user_tags_limit":                   "un usuario no puede tener más de %d etiquetas",
+		"user_tag_not_found":                "etiqueta del usuario no encontrada",
+		"external_id_source_required":       "el origen del id externo es requerido",
+		"external_id_source_too_long":       "el origen del id externo %s tiene más de %d caracteres",
+		"external_id_source_not_valid":      "el origen del id externo %s no es válido",
+		"external_id_param_required":        "el id externo es requerido",
+		"external_id_required":              "el id externo del origen %s es requerido",
+		"external_id_too_long":              "el id externo del origen %s tiene más de %d caracteres",
+		"user_external_ids_limit":           "un usuario no puede tener más de %d ids externos",
+		"external_id_taken":                 "el id externo %s/%s pertenece a otro usuario",
+		"external_id_taken_by_another_user": "un id externo pertenece a otro usuario",
+		"attribute_not_valid":               "el atributo %s no es válido",
+		"attribute_required":                "el atributo %s es requerido",
+		"attribute_string":                  "el atributo %s debe ser un texto",
+		"attribute_number":                  "el atributo %s debe ser un número",
+		"attribute_boolean":                 "el atributo %s debe ser un booleano",
+		"attribute_date":                    "el atributo %s debe ser una fecha (%s)",
+		"attribute_oneof":                   "el atributo %s debe ser uno de %s",
+		"attribute_filter_value_required":   "el valor del filtro del atributo %s es requerido",
+		"attribute_filter_not_valid":        "el filtro %s no es válido, el formato es attr.<nombre>==<valor>",
+
+		// Groups
+		"group_not_found":        "grupo no encontrado",
+		"group_member_not_found": "miembro del grupo no encontrado",
+
+		// Idempotency
+		"idempotency_key_too_long":   "la clave de idempotencia es demasiado larga",
+		"idempotency_key_reused":     "la clave de idempotencia ya fue usada con una solicitud diferente",
+		"idempotency_key_processing": "se está procesando una solicitud con la misma clave de idempotencia",
+	},
+}
@@ -0,0 +1,60 @@
+package i18n
+
+import (
+	"fmt"
+
+	"golang.org/x/text/language"
+)
+
+const (
+	English = "en"
+	Spanish = "es"
+	// DefaultLanguage is the language of the messages written in the code
+	DefaultLanguage = English
+)
+
+// Languages are the supported languages
+var Languages = []string{English, Spanish}
+
+var matcher = language.NewMatcher([]language.Tag{language.English, language.Spanish})
+
+// IsSupported returns if a language has a message catalog
+func IsSupported(lang string) bool {
+	_, ok := catalogs[lang]
+	return ok
+}
+
+// Negotiate returns the supported language that best matches an Accept-Language header, like es-AR,es;q=0.9,en;q=0.8.
+// The fallback language is returned when no language matches
+func Negotiate(acceptLanguage string, fallback string) string {
+	tags, _, err := language.ParseAcceptLanguage(acceptLanguage)
+	if err != nil || len(tags) == 0 {
+		return fallback
+	}
+
+	_, index, confidence := matcher.Match(tags...)
+	if confidence == language.No {
+		return fallback
+	}
+	return Languages[index]
+}
+
+// Text returns the message of a key in a language, formatted with the arguments. The message of the default language
+// is returned when the language has no message for the key. It returns false when the key is not in the catalogs
+func Text(lang string, key string, args ...interface{}) (string, bool) {
+	if len(key) == 0 {
+		return "", false
+	}
+
+	format, ok := catalogs[lang][key]
+	if !ok {
+		format, ok = catalogs[DefaultLanguage][key]
+	}
+	if !ok {
+		return "", false
+	}
+	if len(args) == 0 {
+		return format, true
+	}
+	return fmt.Sprintf(format, args...), true
+}
@@ -0,0 +1,94 @@
+package i18n
+
+import (
+	"testing"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNegotiate_GivenAcceptLanguageHeaders_WhenNegotiate_ThenReturnTheBestSupportedLanguage(t *testing.T) {
+	t.Log("Successfully select the supported language that best matches the Accept-Language header")
+
+	cases := map[string]string{
+		"es":                        Spanish,
+		"es-AR":                     Spanish,
+		"es-AR,es;q=0.9,en;q=0.8":   Spanish,
+		"en-US,en;q=0.9":            English,
+		"fr-FR,fr;q=0.9,es;q=0.5":   Spanish,
+		"pt-BR;q=0.9,en-GB;q=0.8":   English,
+		"de-DE":                     English,
+		"":                          English,
+		"not a language header;q=x": English,
+	}
+
+	for header, expected := range cases {
+		assert.Equal(t, expected, Negotiate(header, English), header)
+	}
+}
+
+func TestNegotiate_GivenAnUnsupportedLanguage_WhenNegotiate_ThenReturnTheFallbackLanguage(t *testing.T) {
+	t.Log("Successfully return the fallback language when no supported language is accepted")
+
+	assert.Equal(t, Spanish, Negotiate("de-DE", Spanish))
+	assert.Equal(t, Spanish, Negotiate("", Spanish))
+}
+
+func TestText_GivenAKey_WhenText_ThenReturnTheFormattedMessageOfTheLanguage(t *testing.T) {
+	t.Log("Successfully get the message of a key in a language")
+
+	message, ok := Text(Spanish, "permission_required", "users:admin")
+
+	assert.True(t, ok)
+	assert.Equal(t, "se requiere el permiso users:admin", message)
+}
+
+func TestText_GivenAnUnsupportedLanguage_WhenText_ThenReturnTheDefaultLanguageMessage(t *testing.T) {
+	t.Log("Successfully get the default language message of a key for an unsupported language")
+
+	message, ok := Text("fr", "user_not_found")
+
+	assert.True(t, ok)
+	assert.Equal(t, "user not found", message)
+}
+
+func TestText_GivenAnUnknownKey_WhenText_ThenReturnNotFound(t *testing.T) {
+	t.Log("Failure to get the message of a key that is not in the catalogs")
+
+	_, ok := Text(Spanish, "unknown_key")
+	assert.False(t, ok)
+
+	_, ok = Text(Spanish, "")
+	assert.False(t, ok)
+}
+
+func TestCatalogs_GivenTheSupportedLanguages_WhenCompare_ThenAllHaveTheSameKeys(t *testing.T) {
+	t.Log("Every catalog has a message for every key")
+
+	for _, lang := range Languages {
+		assert.True(t, IsSupported(lang), lang)
+		for key := range catalogs[DefaultLanguage] {
+			assert.Contains(t, catalogs[lang], key, lang)
+		}
+		assert.Len(t, catalogs[lang], len(catalogs[DefaultLanguage]), lang)
+	}
+}
+
+func TestValidatorTranslator_GivenALanguage_WhenTranslateAValidationError_ThenReturnTheMessageInTheLanguage(t *testing.T) {
+	t.Log("Successfully translate the validation errors, including the rules without a default translation")
+
+	type request struct {
+		Name     string `validate:"required"`
+		Timezone string `validate:"timezone"`
+	}
+	v := validator.New()
+	err := RegisterValidatorTranslations(v)
+	assert.Nil(t, err)
+
+	validationErrs := v.Struct(request{Timezone: "America/Nowhere"}).(validator.ValidationErrors)
+
+	assert.Equal(t, "Name es un campo requerido", validationErrs[0].Translate(ValidatorTranslator(Spanish)))
+	assert.Equal(t, "Timezone debe ser un nombre de zona horaria IANA válido", validationErrs[1].Translate(ValidatorTranslator(Spanish)))
+	assert.Equal(t, "Name is a required field", validationErrs[0].Translate(ValidatorTranslator(English)))
+	assert.Equal(t, "Timezone must be a valid IANA time zone name", validationErrs[1].Translate(ValidatorTranslator("fr")))
+}
@@ -0,0 +1,75 @@
+package i18n
+
+import (
+	"github.com/go-playground/locales/en"
+	"github.com/go-playground/locales/es"
+	ut "github.com/go-playground/universal-translator"
+	"github.com/go-playground/validator/v10"
+	enTranslations "github.com/go-playground/validator/v10/translations/en"
+	esTranslations "github.com/go-playground/validator/v10/translations/es"
+)
+
+var universalTranslator = ut.New(en.New(), en.New(), es.New())
+
+// validatorTranslations are the messages of the validation rules without a default translation.
+// {0} is the field name and {1} the rule parameter
+var validatorTranslations = map[string]map[string]string{
+	English: {
+		"timezone":           "{0} must be a valid IANA time zone name",
+		"bcp47_language_tag": "{0} must be a valid BCP 47 language tag",
+		"iso3166_1_alpha2":   "{0} must be an ISO 3166-1 alpha-2 code",
+	},
+	Spanish: {
+		"datetime":           "{0} no cumple con el formato {1}",
+		"timezone":           "{0} debe ser un nombre de zona horaria IANA válido",
+		"bcp47_language_tag": "{0} debe ser una etiqueta de idioma BCP 47 válida",
+		"iso3166_1_alpha2":   "{0} debe ser un código ISO 3166-1 alfa-2",
+	},
+}
+
+// RegisterValidatorTranslations registers the messages of the validation rules of every supported language in a validator.
+// The translators are shared, so it can only be called once
+func RegisterValidatorTranslations(v *validator.Validate) error {
+	if err := enTranslations.RegisterDefaultTranslations(v, ValidatorTranslator(English)); err != nil {
+		return err
+	}
+	if err := esTranslations.RegisterDefaultTranslations(v, ValidatorTranslator(Spanish)); err != nil {
+		return err
+	}
+
+	for lang, translations := range validatorTranslations {
+		translator := ValidatorTranslator(lang)
+		for tag, text := range translations {
+			err := v.RegisterTranslation(tag, translator, registerTranslation(tag, text), translateFieldError)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// ValidatorTranslator returns the translator of the validation errors of a language.
+// The default language translator is returned for an unsupported language
+func ValidatorTranslator(lang string) ut.Translator {
+	translator, ok := universalTranslator.GetTranslator(lang)
+	if !ok {
+		translator, _ = universalTranslator.GetTranslator(DefaultLanguage)
+	}
+	return translator
+}
+
+func registerTranslation(tag string, text string) validator.RegisterTranslationsFunc {
+	return func(translator ut.Translator) error {
+		return translator.Add(tag, text, true)
+	}
+}
+
+func translateFieldError(translator ut.Translator, fieldErr validator.FieldError) string {
+	text, err := translator.T(fieldErr.Tag(), fieldErr.Field(), fieldErr.Param())
+	if err != nil {
+		return fieldErr.Error()
+	}
+	return text
+}
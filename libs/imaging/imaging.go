@@ -0,0 +1,109 @@
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+)
+
+const (
+	ContentTypeJPEG = "image/jpeg"
+	ContentTypePNG  = "image/png"
+)
+
+const jpegQuality = 85
+
+// DetectContentType returns the content type sniffed from the data, ignoring any declared type
+func DetectContentType(data []byte) string {
+	return http.DetectContentType(data)
+}
+
+// Thumbnail returns a square thumbnail of the image center. The side is the size, or the image shortest side if it is smaller,
+// so images are never enlarged. Each thumbnail pixel is the average of the image pixels it covers
+func Thumbnail(src image.Image, size int) *image.RGBA {
+	bounds := src.Bounds()
+	side := bounds.Dx()
+	if bounds.Dy() < side {
+		side = bounds.Dy()
+	}
+	target := size
+	if side < target {
+		target = side
+	}
+
+	// Copy the centered square, so the pixels can be read directly whatever the source model is
+	crop := image.NewRGBA(image.Rect(0, 0, side, side))
+	origin := image.Pt(bounds.Min.X+(bounds.Dx()-side)/2, bounds.Min.Y+(bounds.Dy()-side)/2)
+	draw.Draw(crop, crop.Bounds(), src, origin, draw.Src)
+
+	thumbnail := image.NewRGBA(image.Rect(0, 0, target, target))
+	if target == 0 {
+		return thumbnail
+	}
+	for y := 0; y < target; y++ {
+		y0, y1 := y*side/target, (y+1)*side/target
+		for x := 0; x < target; x++ {
+			x0, x1 := x*side/target, (x+1)*side/target
+			var sum [4]int
+			for sy := y0; sy < y1; sy++ {
+				offset := crop.PixOffset(x0, sy)
+				for sx := x0; sx < x1; sx++ {
+					for c := 0; c < 4; c++ {
+						sum[c] += int(crop.Pix[offset+c])
+					}
+					offset += 4
+				}
+			}
+			count := (x1 - x0) * (y1 - y0)
+			offset := thumbnail.PixOffset(x, y)
+			for c := 0; c < 4; c++ {
+				thumbnail.Pix[offset+c] = uint8((sum[c] + count/2) / count)
+			}
+		}
+	}
+
+	return thumbnail
+}
+
+// Encode encodes the image as JPEG or PNG
+func Encode(img image.Image, contentType string) ([]byte, error) {
+	var buffer bytes.Buffer
+	var err error
+	switch contentType {
+	case ContentTypeJPEG:
+		err = jpeg.Encode(&buffer, img, &jpeg.Options{Quality: jpegQuality})
+	case ContentTypePNG:
+		err = png.Encode(&buffer, img)
+	default:
+		return nil, fmt.Errorf("content type %s can not be encoded", contentType)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return buffer.Bytes(), nil
+}
+
+// DecodeConfig returns the image dimensions without decoding the whole image
+func DecodeConfig(data []byte) (image.Config, error) {
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return image.Config{}, err
+	}
+	if config.Width <= 0 || config.Height <= 0 {
+		return image.Config{}, errors.New("image dimensions are not valid")
+	}
+
+	return config, nil
+}
+
+// Decode decodes a JPEG or PNG image
+func Decode(data []byte) (image.Image, error) {
+	img, _, err := image.Decode(bytes.NewReader(data))
+	return img, err
+}
@@ -0,0 +1,84 @@
+package imaging
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newImage(width int, height int, fill func(x, y int) color.Color) image.Image {
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, fill(x, y))
+		}
+	}
+	return img
+}
+
+func TestThumbnail_GivenALandscapeImage_WhenThumbnail_ThenReturnTheCenteredSquareResized(t *testing.T) {
+	t.Log("Successfully create a thumbnail of the image center")
+
+	// The left and right quarters are red, the center is blue
+	img := newImage(8, 4, func(x, y int) color.Color {
+		if x < 2 || x >= 6 {
+			return color.NRGBA{R: 255, A: 255}
+		}
+		return color.NRGBA{B: 255, A: 255}
+	})
+
+	thumbnail := Thumbnail(img, 2)
+
+	assert.Equal(t, image.Rect(0, 0, 2, 2), thumbnail.Bounds())
+	for y := 0; y < 2; y++ {
+		for x := 0; x < 2; x++ {
+			assert.Equal(t, color.RGBA{B: 255, A: 255}, thumbnail.RGBAAt(x, y))
+		}
+	}
+}
+
+func TestThumbnail_GivenAnImageSmallerThanTheSize_WhenThumbnail_ThenDoNotEnlargeIt(t *testing.T) {
+	t.Log("Successfully create a thumbnail without enlarging the image")
+
+	img := newImage(3, 5, func(x, y int) color.Color { return color.White })
+
+	thumbnail := Thumbnail(img, 64)
+
+	assert.Equal(t, image.Rect(0, 0, 3, 3), thumbnail.Bounds())
+}
+
+func TestThumbnail_GivenAnImage_WhenThumbnail_ThenAverageThePixels(t *testing.T) {
+	t.Log("Successfully average the pixels covered by each thumbnail pixel")
+
+	img := newImage(2, 2, func(x, y int) color.Color {
+		if (x+y)%2 == 0 {
+			return color.NRGBA{R: 200, G: 100, A: 255}
+		}
+		return color.NRGBA{R: 0, G: 0, A: 255}
+	})
+
+	thumbnail := Thumbnail(img, 1)
+
+	assert.Equal(t, color.RGBA{R: 100, G: 50, A: 255}, thumbnail.RGBAAt(0, 0))
+}
+
+func TestEncode_GivenAnImage_WhenEncodeAndDecode_ThenDetectItsContentType(t *testing.T) {
+	t.Log("Successfully encode an image as JPEG and PNG")
+
+	img := newImage(4, 4, func(x, y int) color.Color { return color.White })
+
+	for _, contentType := range []string{ContentTypeJPEG, ContentTypePNG} {
+		data, err := Encode(img, contentType)
+		assert.Nil(t, err)
+		assert.Equal(t, contentType, DetectContentType(data))
+
+		config, err := DecodeConfig(data)
+		assert.Nil(t, err)
+		assert.Equal(t, 4, config.Width)
+	}
+
+	_, err := Encode(img, "image/gif")
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,178 @@
+package jwks
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"sync"
+	"time"
+)
+
+// DefaultReloadInterval is the minimum time between two reloads of the key set file
+const DefaultReloadInterval = time.Minute
+
+var ErrKeyNotFound = errors.New("key not found in key set")
+
+// jsonWebKey is a public JSON Web Key (RFC 7517). Only RSA and EC P-256 keys are supported
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Crv string `json:"crv"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// KeySet holds the public keys of a local JWKS file, by their key id.
+// Keys are rotated editing the file: the file is reloaded when it was modified or an unknown key id is received,
+// checking it at most once per reload interval, so the removed keys stop being trusted too
+type KeySet struct {
+	mutex          sync.RWMutex
+	file           string
+	keys           map[string]crypto.PublicKey
+	modified       time.Time
+	loaded         time.Time
+	reloadInterval time.Duration
+}
+
+// Load reads a JWKS file
+func Load(file string) (*KeySet, error) {
+	keySet := &KeySet{
+		file:           file,
+		reloadInterval: DefaultReloadInterval,
+	}
+	if err := keySet.load(); err != nil {
+		return nil, err
+	}
+
+	return keySet, nil
+}
+
+// Key returns the public key with the key id
+func (s *KeySet) Key(kid string) (crypto.PublicKey, error) {
+	s.mutex.RLock()
+	_, ok := s.keys[kid]
+	check := time.Since(s.loaded) >= s.reloadInterval
+	s.mutex.RUnlock()
+
+	if check && (!ok || s.fileModified()) {
+		if err := s.load(); err != nil {
+			return nil, err
+		}
+	} else if check {
+		s.mutex.Lock()
+		s.loaded = time.Now()
+		s.mutex.Unlock()
+	}
+
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	if key, ok := s.keys[kid]; ok {
+		return key, nil
+	}
+
+	return nil, ErrKeyNotFound
+}
+
+// fileModified returns true when the modification time of the file is not the one of the loaded keys
+func (s *KeySet) fileModified() bool {
+	info, err := os.Stat(s.file)
+	if err != nil {
+		return true
+	}
+
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return !info.ModTime().Equal(s.modified)
+}
+
+func (s *KeySet) load() error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	// The load time is updated even on failure, so a broken file is not read on every request
+	s.loaded = time.Now()
+	info, err := os.Stat(s.file)
+	if err != nil {
+		return fmt.Errorf("unable to read key set: %w", err)
+	}
+	data, err := os.ReadFile(s.file)
+	if err != nil {
+		return fmt.Errorf("unable to read key set: %w", err)
+	}
+	var document struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &document); err != nil {
+		return fmt.Errorf("unable to parse key set: %w", err)
+	}
+
+	keys := map[string]crypto.PublicKey{}
+	for _, jwk := range document.Keys {
+		if len(jwk.Kid) == 0 {
+			return errors.New("key set keys must have a kid")
+		}
+		key, err := jwk.publicKey()
+		if err != nil {
+			return fmt.Errorf("unable to parse key %s: %w", jwk.Kid, err)
+		}
+		keys[jwk.Kid] = key
+	}
+	s.keys = keys
+	s.modified = info.ModTime()
+
+	return nil
+}
+
+func (k jsonWebKey) publicKey() (crypto.PublicKey, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeInt(k.E)
+		if err != nil {
+			return nil, err
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		if k.Crv != "P-256" {
+			return nil, fmt.Errorf("curve %s is not supported", k.Crv)
+		}
+		x, err := decodeInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		if !elliptic.P256().IsOnCurve(x, y) {
+			return nil, errors.New("point is not on the curve")
+		}
+		return &ecdsa.PublicKey{Curve: elliptic.P256(), X: x, Y: y}, nil
+	default:
+		return nil, fmt.Errorf("key type %s is not supported", k.Kty)
+	}
+}
+
+func decodeInt(value string) (*big.Int, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, errors.New("empty key parameter")
+	}
+
+	return new(big.Int).SetBytes(data), nil
+}
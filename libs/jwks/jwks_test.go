@@ -0,0 +1,129 @@
+package jwks
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func encode(value *big.Int) string {
+	return base64.RawURLEncoding.EncodeToString(value.Bytes())
+}
+
+func writeKeySet(t *testing.T, file string, keys ...map[string]string) {
+	data, _ := json.Marshal(map[string]interface{}{"keys": keys})
+	if err := os.WriteFile(file, data, 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func rsaKey(t *testing.T, kid string) (*rsa.PrivateKey, map[string]string) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key, map[string]string{"kty": "RSA", "kid": kid, "n": encode(key.N), "e": encode(big.NewInt(int64(key.E)))}
+}
+
+func ecKey(t *testing.T, kid string) (*ecdsa.PrivateKey, map[string]string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key, map[string]string{"kty": "EC", "kid": kid, "crv": "P-256", "x": encode(key.X), "y": encode(key.Y)}
+}
+
+func TestKeySet_GivenAJWKSFile_WhenLoad_ThenReturnTheKeysByKid(t *testing.T) {
+	t.Log("Should load the RSA and EC keys of a JWKS file by their kid")
+
+	rsaPrivate, rsaJWK := rsaKey(t, "rsa-1")
+	ecPrivate, ecJWK := ecKey(t, "ec-1")
+	file := filepath.Join(t.TempDir(), "jwks.json")
+	writeKeySet(t, file, rsaJWK, ecJWK)
+
+	keySet, err := Load(file)
+	assert.Nil(t, err)
+
+	key, err := keySet.Key("rsa-1")
+	assert.Nil(t, err)
+	assert.True(t, rsaPrivate.PublicKey.Equal(key))
+
+	key, err = keySet.Key("ec-1")
+	assert.Nil(t, err)
+	assert.True(t, ecPrivate.PublicKey.Equal(key))
+
+	_, err = keySet.Key("unknown")
+	assert.Equal(t, ErrKeyNotFound, err)
+}
+
+func TestKeySet_GivenARotatedJWKSFile_WhenKeyIsUnknown_ThenReloadTheFile(t *testing.T) {
+	t.Log("Should reload the JWKS file when a key is unknown and the reload interval passed")
+
+	_, firstJWK := rsaKey(t, "key-1")
+	_, secondJWK := ecKey(t, "key-2")
+	file := filepath.Join(t.TempDir(), "jwks.json")
+	writeKeySet(t, file, firstJWK)
+	keySet, _ := Load(file)
+
+	writeKeySet(t, file, firstJWK, secondJWK)
+	_, err := keySet.Key("key-2")
+	assert.Equal(t, ErrKeyNotFound, err)
+
+	keySet.reloadInterval = 0
+	_, err = keySet.Key("key-2")
+	assert.Nil(t, err)
+}
+
+func TestKeySet_GivenANotValidJWKSFile_WhenLoad_ThenReturnAnError(t *testing.T) {
+	t.Log("Should not load a JWKS file with unsupported or incomplete keys")
+
+	dir := t.TempDir()
+
+	_, err := Load(filepath.Join(dir, "missing.json"))
+	assert.NotNil(t, err)
+
+	file := filepath.Join(dir, "jwks.json")
+	writeKeySet(t, file, map[string]string{"kty": "oct", "kid": "key-1", "k": "c2VjcmV0"})
+	_, err = Load(file)
+	assert.NotNil(t, err)
+
+	_, jwk := rsaKey(t, "")
+	writeKeySet(t, file, jwk)
+	_, err = Load(file)
+	assert.NotNil(t, err)
+}
+
+func TestKeySet_GivenAKeyRemovedFromTheJWKSFile_WhenReloadIntervalPassed_ThenRejectTheKey(t *testing.T) {
+	t.Log("Should reload the modified JWKS file and stop trusting the removed keys")
+
+	_, firstJWK := rsaKey(t, "key-1")
+	_, secondJWK := ecKey(t, "key-2")
+	file := filepath.Join(t.TempDir(), "jwks.json")
+	writeKeySet(t, file, firstJWK, secondJWK)
+	keySet, _ := Load(file)
+
+	writeKeySet(t, file, secondJWK)
+	modified := time.Now().Add(time.Minute)
+	if err := os.Chtimes(file, modified, modified); err != nil {
+		t.Fatal(err)
+	}
+	_, err := keySet.Key("key-1")
+	assert.Nil(t, err)
+
+	keySet.reloadInterval = 0
+	_, err = keySet.Key("key-1")
+	assert.Equal(t, ErrKeyNotFound, err)
+
+	_, err = keySet.Key("key-2")
+	assert.Nil(t, err)
+}
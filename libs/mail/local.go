@@ -0,0 +1,96 @@
+package mail
+
+import (
+	"bytes"
+	"fmt"
+	"net/mail"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/desarrollogj/golang-api-example/libs/logger"
+	"github.com/google/uuid"
+)
+
+// logMailer writes the messages to the application log, instead of sending them.
+// The log can not be purged, so the recipient is masked and the body, with personal data and tokens, is not written
+type logMailer struct {
+}
+
+// NewLogMailer creates a Mailer that logs the messages. Intended for local use
+func NewLogMailer() logMailer {
+	return logMailer{}
+}
+
+func (m logMailer) Send(message Message) error {
+	logger.AppLog.Info().
+		Str("from", message.From).
+		Str("to", maskedAddress(message.To)).
+		Str("subject", message.Subject).
+		Int("bodyLength", len(message.Body)).
+		Msg("email message")
+	return nil
+}
+
+// Purge does nothing, the logged messages have no personal data
+func (m logMailer) Purge(to string) error {
+	return nil
+}
+
+// maskedAddress returns the address with its local part replaced, keeping its domain
+func maskedAddress(address string) string {
+	if at := strings.LastIndex(address, "@"); at >= 0 {
+		return "***" + address[at:]
+	}
+	return "***"
+}
+
+// fileMailer writes each message to an .eml file in a directory, instead of sending them
+type fileMailer struct {
+	directory string
+}
+
+// NewFileMailer creates a Mailer that writes the messages to files. Intended for local use
+func NewFileMailer(directory string) fileMailer {
+	return fileMailer{
+		directory: directory,
+	}
+}
+
+func (m fileMailer) Send(message Message) error {
+	if err := os.MkdirAll(m.directory, 0o750); err != nil {
+		return fmt.Errorf("unable to create the mail directory: %w", err)
+	}
+
+	name := fmt.Sprintf("%s-%s.eml", time.Now().UTC().Format("20060102T150405"), uuid.NewString())
+	if err := os.WriteFile(filepath.Join(m.directory, name), message.Bytes(), 0o640); err != nil {
+		return fmt.Errorf("unable to write the mail file: %w", err)
+	}
+
+	return nil
+}
+
+// Purge deletes the files of the messages sent to the address, comparing the address ignoring the case
+func (m fileMailer) Purge(to string) error {
+	files, err := filepath.Glob(filepath.Join(m.directory, "*.eml"))
+	if err != nil {
+		return fmt.Errorf("unable to list the mail files: %w", err)
+	}
+
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			return fmt.Errorf("unable to read the mail file: %w", err)
+		}
+		message, err := mail.ReadMessage(bytes.NewReader(content))
+		if err != nil || !strings.EqualFold(message.Header.Get("To"), to) {
+			continue
+		}
+		if err := os.Remove(file); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("unable to delete the mail file: %w", err)
+		}
+	}
+
+	return nil
+}
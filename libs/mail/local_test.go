@@ -0,0 +1,75 @@
+package mail
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/desarrollogj/golang-api-example/libs/logger"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileMailer_GivenAMessage_WhenSend_ThenWriteAnEmlFile(t *testing.T) {
+	t.Log("Successfully write a message to a file")
+
+	directory := filepath.Join(t.TempDir(), "mail")
+	mailer := NewFileMailer(directory)
+
+	err := mailer.Send(Message{
+		From:    "noreply@example.com",
+		To:      "foobar@email.com",
+		Subject: "Verify your email",
+		Body:    "Line 1\nLine 2",
+	})
+
+	assert.Nil(t, err)
+	files, _ := filepath.Glob(filepath.Join(directory, "*.eml"))
+	assert.Len(t, files, 1)
+	content, _ := os.ReadFile(files[0])
+	assert.Contains(t, string(content), "To: foobar@email.com\r\n")
+	assert.Contains(t, string(content), "\r\n\r\nLine 1\r\nLine 2")
+}
+
+func TestFileMailer_GivenMessagesToSeveralAddresses_WhenPurge_ThenDeleteTheMessagesToTheAddress(t *testing.T) {
+	t.Log("Successfully delete the files of the messages sent to an address")
+
+	directory := filepath.Join(t.TempDir(), "mail")
+	mailer := NewFileMailer(directory)
+	mailer.Send(Message{From: "noreply@example.com", To: "foobar@email.com", Subject: "Verify your email", Body: "Hello Foo"})
+	mailer.Send(Message{From: "noreply@example.com", To: "another@email.com", Subject: "Verify your email", Body: "Hello Another"})
+
+	err := mailer.Purge("FooBar@Email.com")
+
+	assert.Nil(t, err)
+	files, _ := filepath.Glob(filepath.Join(directory, "*.eml"))
+	assert.Len(t, files, 1)
+	content, _ := os.ReadFile(files[0])
+	assert.Contains(t, string(content), "To: another@email.com\r\n")
+
+	assert.Nil(t, NewFileMailer(filepath.Join(t.TempDir(), "missing")).Purge("foobar@email.com"))
+}
+
+func TestLogMailer_GivenAMessage_WhenSend_ThenLogItWithoutPersonalData(t *testing.T) {
+	t.Log("The log mailer should not write the recipient address nor the body to the log, since the log can not be purged")
+
+	buffer := new(bytes.Buffer)
+	previous := logger.AppLog
+	logger.AppLog = zerolog.New(buffer)
+	defer func() { logger.AppLog = previous }()
+
+	err := NewLogMailer().Send(Message{
+		From:    "noreply@example.com",
+		To:      "foobar@email.com",
+		Subject: "Verify your email",
+		Body:    "Hello Foo, confirm your email: http://localhost/verify?token=TOKEN1",
+	})
+
+	assert.Nil(t, err)
+	assert.Contains(t, buffer.String(), `"to":"***@email.com"`)
+	assert.NotContains(t, buffer.String(), "foobar")
+	assert.NotContains(t, buffer.String(), "TOKEN1")
+	assert.NotContains(t, buffer.String(), "Hello Foo")
+	assert.Nil(t, NewLogMailer().Purge("foobar@email.com"))
+}
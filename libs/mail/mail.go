@@ -0,0 +1,38 @@
+package mail
+
+import (
+	"bytes"
+	"fmt"
+	"mime"
+	"strings"
+	"time"
+)
+
+// Message represents a plain text email
+type Message struct {
+	From    string
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer represents the methods to be implemented by email senders
+type Mailer interface {
+	Send(message Message) error
+	// Purge removes the copies kept of the messages sent to an address, like when its user is erased
+	Purge(to string) error
+}
+
+// Bytes returns the message in RFC 5322 format
+func (m Message) Bytes() []byte {
+	var buffer bytes.Buffer
+	fmt.Fprintf(&buffer, "From: %s\r\n", m.From)
+	fmt.Fprintf(&buffer, "To: %s\r\n", m.To)
+	fmt.Fprintf(&buffer, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", m.Subject))
+	fmt.Fprintf(&buffer, "Date: %s\r\n", time.Now().UTC().Format(time.RFC1123Z))
+	buffer.WriteString("MIME-Version: 1.0\r\n")
+	buffer.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	buffer.WriteString("\r\n")
+	buffer.WriteString(strings.ReplaceAll(strings.ReplaceAll(m.Body, "\r\n", "\n"), "\n", "\r\n"))
+	return buffer.Bytes()
+}
@@ -0,0 +1,57 @@
+package mail
+
+import (
+	"fmt"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strconv"
+)
+
+// SMTPConfiguration holds the SMTP server connection data. The authentication is used only when the username is set
+type SMTPConfiguration struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+}
+
+// smtpMailer sends the messages to a SMTP server
+type smtpMailer struct {
+	config SMTPConfiguration
+}
+
+// NewSMTPMailer creates a Mailer that sends the messages to a SMTP server
+func NewSMTPMailer(config SMTPConfiguration) smtpMailer {
+	return smtpMailer{
+		config: config,
+	}
+}
+
+// Purge does nothing, the sent messages are not kept
+func (m smtpMailer) Purge(to string) error {
+	return nil
+}
+
+func (m smtpMailer) Send(message Message) error {
+	from, err := mail.ParseAddress(message.From)
+	if err != nil {
+		return fmt.Errorf("sender address is not valid: %w", err)
+	}
+	to, err := mail.ParseAddress(message.To)
+	if err != nil {
+		return fmt.Errorf("recipient address is not valid: %w", err)
+	}
+
+	var auth smtp.Auth
+	if len(m.config.Username) > 0 {
+		auth = smtp.PlainAuth("", m.config.Username, m.config.Password, m.config.Host)
+	}
+
+	address := net.JoinHostPort(m.config.Host, strconv.Itoa(m.config.Port))
+	if err := smtp.SendMail(address, auth, from.Address, []string{to.Address}, message.Bytes()); err != nil {
+		return fmt.Errorf("unable to send the email: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,129 @@
+package mail
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// smtpStandIn is a minimal SMTP server that accepts one message and records it
+type smtpStandIn struct {
+	listener net.Listener
+	from     string
+	to       []string
+	data     string
+	done     chan struct{}
+}
+
+func newSMTPStandIn(t *testing.T) *smtpStandIn {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := &smtpStandIn{listener: listener, done: make(chan struct{})}
+	go server.serve()
+	t.Cleanup(func() { listener.Close() })
+	return server
+}
+
+func (s *smtpStandIn) port() int {
+	return s.listener.Addr().(*net.TCPAddr).Port
+}
+
+func (s *smtpStandIn) serve() {
+	defer close(s.done)
+	conn, err := s.listener.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	reader := bufio.NewReader(conn)
+	reply := func(line string) { fmt.Fprintf(conn, "%s\r\n", line) }
+	reply("220 localhost ESMTP")
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		command := strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(command, "EHLO"), strings.HasPrefix(command, "HELO"):
+			reply("250 localhost")
+		case strings.HasPrefix(command, "MAIL FROM:"):
+			s.from = strings.Trim(strings.TrimPrefix(command, "MAIL FROM:"), "<>")
+			reply("250 OK")
+		case strings.HasPrefix(command, "RCPT TO:"):
+			s.to = append(s.to, strings.Trim(strings.TrimPrefix(command, "RCPT TO:"), "<>"))
+			reply("250 OK")
+		case command == "DATA":
+			reply("354 End data with <CR><LF>.<CR><LF>")
+			var data strings.Builder
+			for {
+				dataLine, err := reader.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if dataLine == ".\r\n" {
+					break
+				}
+				data.WriteString(dataLine)
+			}
+			s.data = data.String()
+			reply("250 OK")
+		case command == "QUIT":
+			reply("221 Bye")
+			return
+		default:
+			reply("250 OK")
+		}
+	}
+}
+
+func TestSMTPMailer_GivenAMessage_WhenSend_ThenTheServerReceivesIt(t *testing.T) {
+	t.Log("Successfully send a message to a SMTP server")
+
+	server := newSMTPStandIn(t)
+	mailer := NewSMTPMailer(SMTPConfiguration{Host: "127.0.0.1", Port: server.port()})
+
+	err := mailer.Send(Message{
+		From:    "Users API <noreply@example.com>",
+		To:      "foobar@email.com",
+		Subject: "Verify your email",
+		Body:    "Your token is ABC",
+	})
+	<-server.done
+
+	assert.Nil(t, err)
+	assert.Equal(t, "noreply@example.com", server.from)
+	assert.Equal(t, []string{"foobar@email.com"}, server.to)
+	assert.Contains(t, server.data, "Subject: Verify your email\r\n")
+	assert.Contains(t, server.data, "\r\n\r\nYour token is ABC")
+}
+
+func TestSMTPMailer_GivenANotValidRecipient_WhenSend_ThenReturnAnError(t *testing.T) {
+	t.Log("Failure to send a message to a not valid recipient")
+
+	mailer := NewSMTPMailer(SMTPConfiguration{Host: "127.0.0.1", Port: 25})
+
+	err := mailer.Send(Message{From: "noreply@example.com", To: "foobar", Subject: "Test"})
+
+	assert.NotNil(t, err)
+}
+
+func TestSMTPMailer_GivenAnUnavailableServer_WhenSend_ThenReturnAnError(t *testing.T) {
+	t.Log("Failure to send a message because the server is not available")
+
+	listener, _ := net.Listen("tcp", "127.0.0.1:0")
+	port := listener.Addr().(*net.TCPAddr).Port
+	listener.Close()
+	mailer := NewSMTPMailer(SMTPConfiguration{Host: "127.0.0.1", Port: port})
+
+	err := mailer.Send(Message{From: "noreply@example.com", To: "foobar@email.com", Subject: "Test"})
+
+	assert.NotNil(t, err)
+}
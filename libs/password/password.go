@@ -0,0 +1,40 @@
+package password
+
+import (
+	"errors"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// MaxLength is the maximum password length in bytes. bcrypt ignores the bytes after it
+const MaxLength = 72
+
+var ErrTooLong = errors.New("password is too long")
+
+// dummyHash is compared when there is no hash to compare, so a missing credential takes as long as a wrong password
+var dummyHash, _ = bcrypt.GenerateFromPassword([]byte("dummy password"), bcrypt.DefaultCost)
+
+// Hash returns the bcrypt hash of a password
+func Hash(password string) (string, error) {
+	if len(password) > MaxLength {
+		return "", ErrTooLong
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+
+	return string(hash), nil
+}
+
+// Compare returns true when the password matches the hash. An empty hash never matches,
+// but it is compared against a dummy hash to take the same time
+func Compare(hash string, password string) bool {
+	if len(hash) == 0 {
+		bcrypt.CompareHashAndPassword(dummyHash, []byte(password))
+		return false
+	}
+
+	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil
+}
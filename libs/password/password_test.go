@@ -0,0 +1,34 @@
+package password
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHash_GivenAPassword_WhenCompare_ThenMatchOnlyTheSamePassword(t *testing.T) {
+	t.Log("A hashed password should only match itself")
+
+	hash, err := Hash("Secret-Password1")
+
+	assert.Nil(t, err)
+	assert.NotEqual(t, "Secret-Password1", hash)
+	assert.True(t, Compare(hash, "Secret-Password1"))
+	assert.False(t, Compare(hash, "secret-password1"))
+}
+
+func TestHash_GivenATooLongPassword_WhenHash_ThenReturnAnError(t *testing.T) {
+	t.Log("Passwords longer than bcrypt supports should not be hashed")
+
+	_, err := Hash(strings.Repeat("a", MaxLength+1))
+
+	assert.Equal(t, ErrTooLong, err)
+}
+
+func TestCompare_GivenAnEmptyHash_WhenCompare_ThenDoNotMatch(t *testing.T) {
+	t.Log("A missing hash should never match")
+
+	assert.False(t, Compare("", ""))
+	assert.False(t, Compare("", "Secret-Password1"))
+}
@@ -0,0 +1,70 @@
+package reference
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/oklog/ulid/v2"
+)
+
+const (
+	KindUUIDv4 = "uuidv4"
+	KindUUIDv7 = "uuidv7"
+	KindULID   = "ulid"
+)
+
+// prefixPattern are the prefixes allowed, since the references are used in urls, file names and tombstone emails
+var prefixPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{0,20}$`)
+
+// Generator represents the method to be implemented by entity reference generators
+type Generator interface {
+	Generate() string
+}
+
+// generator creates references with a kind specific function, adding an optional prefix
+type generator struct {
+	prefix   string
+	generate func() string
+}
+
+// NewGenerator creates a Generator of a kind. An empty kind is a UUIDv4 generator.
+// The prefix, for example "usr_", is added before every reference. It can only have up to 20 letters, digits,
+// hyphens and underscores
+func NewGenerator(kind string, prefix string) (Generator, error) {
+	if !prefixPattern.MatchString(prefix) {
+		return nil, fmt.Errorf("reference prefix %q not valid, it can only have up to 20 letters, digits, hyphens and underscores", prefix)
+	}
+
+	var generate func() string
+	switch strings.ToLower(kind) {
+	case "", KindUUIDv4:
+		generate = uuid.NewString
+	case KindUUIDv7:
+		generate = newUUIDv7
+	case KindULID:
+		generate = newULID
+	default:
+		return nil, fmt.Errorf("unknown reference generator %q", kind)
+	}
+
+	return generator{
+		prefix:   prefix,
+		generate: generate,
+	}, nil
+}
+
+func (g generator) Generate() string {
+	return g.prefix + g.generate()
+}
+
+// newUUIDv7 returns a time ordered UUID. uuid.NewV7 only fails when the random source fails, like uuid.NewString
+func newUUIDv7() string {
+	return uuid.Must(uuid.NewV7()).String()
+}
+
+// newULID returns a time ordered ULID in lowercase, monotonic within the same millisecond
+func newULID() string {
+	return strings.ToLower(ulid.Make().String())
+}
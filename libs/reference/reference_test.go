@@ -0,0 +1,70 @@
+package reference
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewGenerator_GivenAKind_WhenGenerate_ThenReturnAReferenceOfThatKind(t *testing.T) {
+	t.Log("New generator creates references of the configured kind")
+
+	cases := []struct {
+		kind    string
+		pattern string
+	}{
+		{"", `^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`},
+		{KindUUIDv4, `^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`},
+		{KindUUIDv7, `^[0-9a-f]{8}-[0-9a-f]{4}-7[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`},
+		{KindULID, `^[0-9a-hjkmnp-tv-z]{26}$`},
+	}
+	for _, c := range cases {
+		generator, err := NewGenerator(c.kind, "")
+		assert.Nil(t, err)
+		assert.Regexp(t, regexp.MustCompile(c.pattern), generator.Generate(), c.kind)
+	}
+}
+
+func TestNewGenerator_GivenAPrefix_WhenGenerate_ThenReturnAPrefixedReference(t *testing.T) {
+	t.Log("New generator adds the prefix to the references")
+
+	generator, err := NewGenerator(KindULID, "usr_")
+
+	assert.Nil(t, err)
+	assert.Regexp(t, `^usr_[0-9a-hjkmnp-tv-z]{26}$`, generator.Generate())
+}
+
+func TestNewGenerator_GivenATimeOrderedKind_WhenGenerate_ThenReferencesAreSorted(t *testing.T) {
+	t.Log("UUIDv7 and ULID references sort in generation order")
+
+	for _, kind := range []string{KindUUIDv7, KindULID} {
+		generator, _ := NewGenerator(kind, "")
+		previous := generator.Generate()
+		for i := 0; i < 100; i++ {
+			next := generator.Generate()
+			assert.Less(t, previous, next, kind)
+			previous = next
+		}
+	}
+}
+
+func TestNewGenerator_GivenAnUnknownKind_WhenCreate_ThenReturnError(t *testing.T) {
+	t.Log("New generator fails with an unknown kind")
+
+	generator, err := NewGenerator("sequence", "")
+
+	assert.Nil(t, generator)
+	assert.EqualError(t, err, `unknown reference generator "sequence"`)
+}
+
+func TestNewGenerator_GivenAnUnsafePrefix_WhenCreate_ThenReturnError(t *testing.T) {
+	t.Log("New generator fails with a prefix that is not safe in urls and file names")
+
+	for _, prefix := range []string{"usr/", "../", "usr?", "usr ", "usr.", "a_very_long_reference_prefix"} {
+		generator, err := NewGenerator(KindULID, prefix)
+
+		assert.Nil(t, generator, prefix)
+		assert.NotNil(t, err, prefix)
+	}
+}
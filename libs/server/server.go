@@ -0,0 +1,96 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/desarrollogj/golang-api-example/libs/logger"
+)
+
+// Configuration are the timeouts of the HTTP server and of its graceful shutdown
+type Configuration struct {
+	Addr              string
+	ReadTimeout       time.Duration
+	ReadHeaderTimeout time.Duration
+	WriteTimeout      time.Duration
+	IdleTimeout       time.Duration
+	// DrainPeriod is the time between the readiness change and the shutdown, so the load balancers stop sending requests
+	DrainPeriod time.Duration
+	// ShutdownTimeout is how long the shutdown waits for the in-flight requests to finish
+	ShutdownTimeout time.Duration
+}
+
+var ready atomic.Bool
+
+// IsReady returns if the server accepts requests. It is not ready before it starts and while it shuts down
+func IsReady() bool {
+	return ready.Load()
+}
+
+// New creates an HTTP server with the timeouts of the configuration
+func New(config Configuration, handler http.Handler) *http.Server {
+	return &http.Server{
+		Addr:              config.Addr,
+		Handler:           handler,
+		ReadTimeout:       config.ReadTimeout,
+		ReadHeaderTimeout: config.ReadHeaderTimeout,
+		WriteTimeout:      config.WriteTimeout,
+		IdleTimeout:       config.IdleTimeout,
+	}
+}
+
+// Run starts the server and shuts it down gracefully when the context is done: the server is marked not ready,
+// the in-flight and new requests are served during the drain period, and then it stops accepting connections and waits
+// for the in-flight requests until the shutdown timeout. It returns the error of the server or of an expired shutdown
+func Run(ctx context.Context, srv *http.Server, config Configuration) error {
+	listener, err := net.Listen("tcp", srv.Addr)
+	if err != nil {
+		return err
+	}
+	return serve(ctx, srv, listener, config)
+}
+
+func serve(ctx context.Context, srv *http.Server, listener net.Listener, config Configuration) error {
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- srv.Serve(listener)
+	}()
+	ready.Store(true)
+	logger.AppLog.Info().Str("addr", listener.Addr().String()).Msg("server started")
+
+	select {
+	case err := <-serveErr:
+		ready.Store(false)
+		return err
+	case <-ctx.Done():
+	}
+
+	ready.Store(false)
+	logger.AppLog.Info().Dur("drainPeriod", config.DrainPeriod).Msg("server is not ready, draining requests")
+	timer := time.NewTimer(config.DrainPeriod)
+	select {
+	case err := <-serveErr:
+		timer.Stop()
+		return err
+	case <-timer.C:
+	}
+
+	logger.AppLog.Info().Dur("timeout", config.ShutdownTimeout).Msg("shutting down the server")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), config.ShutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		// Close the connections of the requests that did not finish, before the resources they use are closed
+		_ = srv.Close()
+		return err
+	}
+	if err := <-serveErr; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+
+	logger.AppLog.Info().Msg("server stopped")
+	return nil
+}
@@ -0,0 +1,94 @@
+package server
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestServeShutsDownGracefully(t *testing.T) {
+	t.Log("Serve should be ready until the context is done, and finish the in-flight requests before it stops")
+
+	started := make(chan struct{})
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		time.Sleep(100 * time.Millisecond)
+		w.Write([]byte("done"))
+	})
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	config := Configuration{DrainPeriod: 50 * time.Millisecond, ShutdownTimeout: time.Second}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	result := make(chan error, 1)
+	go func() {
+		result <- serve(ctx, New(config, handler), listener, config)
+	}()
+	assert.Eventually(t, IsReady, time.Second, 10*time.Millisecond)
+
+	response := make(chan string, 1)
+	go func() {
+		res, err := http.Get("http://" + listener.Addr().String())
+		if err != nil {
+			response <- err.Error()
+			return
+		}
+		defer res.Body.Close()
+		body, _ := io.ReadAll(res.Body)
+		response <- string(body)
+	}()
+	<-started
+	cancel()
+
+	assert.Eventually(t, func() bool { return !IsReady() }, time.Second, 10*time.Millisecond)
+	assert.Equal(t, "done", <-response)
+	assert.Nil(t, <-result)
+	_, err = net.Dial("tcp", listener.Addr().String())
+	assert.NotNil(t, err)
+}
+
+func TestServeShutdownTimeout(t *testing.T) {
+	t.Log("Serve should return an error when the in-flight requests do not finish before the shutdown timeout")
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	defer close(release)
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+	})
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	config := Configuration{ShutdownTimeout: 50 * time.Millisecond}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	result := make(chan error, 1)
+	go func() {
+		result <- serve(ctx, New(config, handler), listener, config)
+	}()
+	go http.Get("http://" + listener.Addr().String())
+	<-started
+	cancel()
+
+	assert.ErrorIs(t, <-result, context.DeadlineExceeded)
+	assert.False(t, IsReady())
+}
+
+func TestRunWithAnAddressInUse(t *testing.T) {
+	t.Log("Run should return an error when the server can not listen on its address")
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.Nil(t, err)
+	defer listener.Close()
+	config := Configuration{Addr: listener.Addr().String()}
+
+	err = Run(context.Background(), New(config, http.NotFoundHandler()), config)
+
+	assert.NotNil(t, err)
+	assert.False(t, IsReady())
+}
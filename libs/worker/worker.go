@@ -0,0 +1,53 @@
+package worker
+
+import (
+	"context"
+	"sync"
+
+	"github.com/desarrollogj/golang-api-example/libs/logger"
+)
+
+// Func is the function executed by a background worker. It must return when the context is done
+type Func func(ctx context.Context)
+
+type registeredWorker struct {
+	name string
+	fn   Func
+}
+
+var (
+	workers []registeredWorker
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+)
+
+// Register adds a background worker. Registered workers are executed by Start
+func Register(name string, fn Func) {
+	workers = append(workers, registeredWorker{name: name, fn: fn})
+}
+
+// Start runs every registered worker in its own goroutine
+func Start() {
+	var ctx context.Context
+	ctx, cancel = context.WithCancel(context.Background())
+
+	for _, w := range workers {
+		wg.Add(1)
+		go func(w registeredWorker) {
+			defer wg.Done()
+			logger.AppLog.Info().Str("worker", w.name).Msg("worker started")
+			w.fn(ctx)
+			logger.AppLog.Info().Str("worker", w.name).Msg("worker stopped")
+		}(w)
+	}
+}
+
+// Stop cancels the running workers and waits until all of them have returned
+func Stop() {
+	if cancel == nil {
+		return
+	}
+
+	cancel()
+	wg.Wait()
+}
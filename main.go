@@ -1,12 +1,21 @@
 package main
 
 import (
+	"context"
 	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+	_ "time/tzdata"
 
 	_ "github.com/desarrollogj/golang-api-example/docs"
+	"github.com/desarrollogj/golang-api-example/domain"
 	"github.com/desarrollogj/golang-api-example/libs/database"
 	"github.com/desarrollogj/golang-api-example/libs/logger"
+	"github.com/desarrollogj/golang-api-example/libs/server"
 	"github.com/desarrollogj/golang-api-example/libs/system"
+	"github.com/desarrollogj/golang-api-example/libs/worker"
 	"github.com/desarrollogj/golang-api-example/router"
 	"github.com/gin-gonic/gin"
 	"github.com/gookit/config/v2"
@@ -16,11 +25,16 @@ import (
 // @title           Users example api
 // @version         0.0.1
 // @description     A CRUD example api using Go language
-// @BasePath  		/api/v1/users
+// @BasePath  		/api/v1
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
+// @description Bearer access token issued by the login endpoint, as "Bearer {token}"
+// @securityDefinitions.apikey ApiKeyAuth
+// @in header
+// @name X-API-Key
+// @description API key created with the API keys endpoints
 func main() {
-	// Defer database disconnection (if database client was created)
-	defer database.MongoDisconnect()
-
 	// Load configuration
 	config.WithOptions(config.ParseEnv)
 	config.AddDriver(json.Driver)
@@ -44,10 +58,38 @@ func main() {
 	// Create HTTP router and start
 	gin.SetMode(config.String("ginMode", "debug"))
 	r := router.CreateRouter()
-	port := config.String("port")
-	err = r.Run(fmt.Sprintf(":%s", port))
+
+	// Start background workers
+	worker.Start()
+
+	serverConfig := domain.ServerConfiguration{}
+	err = config.BindStruct("server", &serverConfig)
 	if err != nil {
-		fmt.Printf("error while starting application")
-		panic(err)
+		logger.AppLog.Fatal().Err(err).Msg("unable to load server configuration")
+	}
+	httpConfig := server.Configuration{
+		Addr:              fmt.Sprintf(":%s", config.String("port")),
+		ReadTimeout:       time.Duration(serverConfig.ReadTimeoutSeconds) * time.Second,
+		ReadHeaderTimeout: time.Duration(serverConfig.ReadHeaderTimeoutSeconds) * time.Second,
+		WriteTimeout:      time.Duration(serverConfig.WriteTimeoutSeconds) * time.Second,
+		IdleTimeout:       time.Duration(serverConfig.IdleTimeoutSeconds) * time.Second,
+		DrainPeriod:       time.Duration(serverConfig.DrainSeconds) * time.Second,
+		ShutdownTimeout:   time.Duration(serverConfig.ShutdownTimeoutSeconds) * time.Second,
+	}
+
+	// Serve until SIGINT or SIGTERM, then stop the server before the workers and the database they use
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	err = server.Run(ctx, server.New(httpConfig, r), httpConfig)
+	stop()
+	if err != nil {
+		logger.AppLog.Error().Err(err).Msg("server stopped with an error")
+	}
+
+	worker.Stop()
+	logger.AppLog.Info().Msg("workers stopped")
+	database.MongoDisconnect()
+
+	if err != nil {
+		os.Exit(1)
 	}
 }
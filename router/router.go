@@ -1,11 +1,20 @@
 package router
 
 import (
+	"github.com/desarrollogj/golang-api-example/auth"
 	"github.com/desarrollogj/golang-api-example/domain"
+	"github.com/desarrollogj/golang-api-example/group"
 	"github.com/desarrollogj/golang-api-example/handler"
+	"github.com/desarrollogj/golang-api-example/idempotency"
 	"github.com/desarrollogj/golang-api-example/infrastructure"
+	"github.com/desarrollogj/golang-api-example/libs/blob"
+	"github.com/desarrollogj/golang-api-example/libs/encryption"
 	appGin "github.com/desarrollogj/golang-api-example/libs/gin"
+	"github.com/desarrollogj/golang-api-example/libs/i18n"
 	"github.com/desarrollogj/golang-api-example/libs/logger"
+	"github.com/desarrollogj/golang-api-example/libs/mail"
+	"github.com/desarrollogj/golang-api-example/libs/reference"
+	"github.com/desarrollogj/golang-api-example/libs/worker"
 	"github.com/desarrollogj/golang-api-example/user"
 	"github.com/gin-gonic/gin"
 	"github.com/gookit/config/v2"
@@ -17,9 +26,37 @@ import (
 func CreateRouter() *gin.Engine {
 	router := gin.New()
 
-	router.Use(gin.Recovery(), logger.GinCustomLogger())
+	localizationConfig := domain.LocalizationConfiguration{}
+	err := config.BindStruct("localization", &localizationConfig)
+	if err != nil {
+		logger.AppLog.Fatal().Err(err).Msg("unable to load localization configuration")
+	}
+	if len(localizationConfig.DefaultLanguage) == 0 {
+		localizationConfig.DefaultLanguage = i18n.DefaultLanguage
+	}
+	if !i18n.IsSupported(localizationConfig.DefaultLanguage) {
+		logger.AppLog.Fatal().Str("language", localizationConfig.DefaultLanguage).Msg("localization default language is not supported")
+	}
+
+	errorsConfig := domain.ErrorsConfiguration{}
+	err = config.BindStruct("errors", &errorsConfig)
+	if err != nil {
+		logger.AppLog.Fatal().Err(err).Msg("unable to load errors configuration")
+	}
+	if len(errorsConfig.Format) == 0 {
+		errorsConfig.Format = appGin.ErrorFormatJSON
+	}
+	if !appGin.IsErrorFormat(errorsConfig.Format) {
+		logger.AppLog.Fatal().Str("format", errorsConfig.Format).Msg("errors format is not supported")
+	}
+
+	router.Use(gin.Recovery(), logger.GinCustomLogger(),
+		appGin.LanguageMiddleware(localizationConfig.DefaultLanguage),
+		appGin.ErrorFormatMiddleware(errorsConfig.Format, errorsConfig.ProblemTypeBaseURI))
 
 	router.HandleMethodNotAllowed = true
+	// Route with the escaped path, so an escaped slash in a parameter, like an external id, does not split it
+	router.UseRawPath = true
 
 	router.NoMethod(appGin.MethodNotAllowedHandler)
 	router.NoRoute(appGin.NoRouteHandler)
@@ -48,17 +85,192 @@ func configureMappings(router *gin.Engine) {
 		logger.AppLog.Fatal().Err(err).Msg("unable to load application configuration")
 	}
 
+	encryptionConfig := domain.EncryptionConfiguration{}
+	err = config.BindStruct("encryption", &encryptionConfig)
+	if err != nil {
+		logger.AppLog.Fatal().Err(err).Msg("unable to load encryption configuration")
+	}
+
+	schemaValidationConfig := domain.SchemaValidationConfiguration{}
+	err = config.BindStruct("database.schemaValidation", &schemaValidationConfig)
+	if err != nil {
+		logger.AppLog.Fatal().Err(err).Msg("unable to load schema validation configuration")
+	}
+
+	emailVerificationConfig := domain.EmailVerificationConfiguration{}
+	err = config.BindStruct("emailVerification", &emailVerificationConfig)
+	if err != nil {
+		logger.AppLog.Fatal().Err(err).Msg("unable to load email verification configuration")
+	}
+	if len(emailVerificationConfig.Secret) < domain.MinSigningSecretLength {
+		logger.AppLog.Fatal().Int("minLength", domain.MinSigningSecretLength).Msg("email verification secret is required and must not be short")
+	}
+
+	mailConfig := domain.MailConfiguration{}
+	err = config.BindStruct("mail", &mailConfig)
+	if err != nil {
+		logger.AppLog.Fatal().Err(err).Msg("unable to load mail configuration")
+	}
+
+	authConfig := domain.AuthConfiguration{}
+	err = config.BindStruct("auth", &authConfig)
+	if err != nil {
+		logger.AppLog.Fatal().Err(err).Msg("unable to load auth configuration")
+	}
+
+	authorizationConfig := domain.AuthorizationConfiguration{}
+	err = config.BindStruct("authorization", &authorizationConfig)
+	if err != nil {
+		logger.AppLog.Fatal().Err(err).Msg("unable to load authorization configuration")
+	}
+
+	avatarConfig := domain.AvatarConfiguration{}
+	err = config.BindStruct("avatar", &avatarConfig)
+	if err != nil {
+		logger.AppLog.Fatal().Err(err).Msg("unable to load avatar configuration")
+	}
+
+	duplicateDetectionConfig := domain.DuplicateDetectionConfiguration{}
+	err = config.BindStruct("duplicateDetection", &duplicateDetectionConfig)
+	if err != nil {
+		logger.AppLog.Fatal().Err(err).Msg("unable to load duplicate detection configuration")
+	}
+
+	attributesConfig := domain.UserAttributesConfiguration{}
+	err = config.BindStruct("attributes", &attributesConfig)
+	if err != nil {
+		logger.AppLog.Fatal().Err(err).Msg("unable to load attributes configuration")
+	}
+	err = user.CheckAttributesConfiguration(attributesConfig)
+	if err != nil {
+		logger.AppLog.Fatal().Err(err).Msg("attributes configuration is not valid")
+	}
+
+	referenceConfig := domain.ReferenceConfiguration{}
+	err = config.BindStruct("reference", &referenceConfig)
+	if err != nil {
+		logger.AppLog.Fatal().Err(err).Msg("unable to load reference configuration")
+	}
+
+	idempotencyConfig := domain.IdempotencyConfiguration{}
+	err = config.BindStruct("idempotency", &idempotencyConfig)
+	if err != nil {
+		logger.AppLog.Fatal().Err(err).Msg("unable to load idempotency configuration")
+	}
+
 	// Infrastructure
-	userMongoRepositoryMapper := infrastructure.NewDefaultMongoRepositoryMapper()
+	migrated, err := infrastructure.MigrateUserStatus(mongoRepoConfig)
+	if err != nil {
+		logger.AppLog.Error().Err(err).Msg("unable to migrate users status")
+	} else if migrated > 0 {
+		logger.AppLog.Info().Int64("users", migrated).Msg("users status migrated")
+	}
+	err = infrastructure.EnsureUserIndexes(mongoRepoConfig)
+	if err != nil {
+		logger.AppLog.Error().Err(err).Msg("unable to create users indexes")
+	}
+	err = infrastructure.EnsureIdempotencyIndexes(mongoRepoConfig)
+	if err != nil {
+		logger.AppLog.Error().Err(err).Msg("unable to create idempotency keys indexes")
+	}
+	if schemaValidationConfig.Enabled {
+		err = infrastructure.ApplyUserSchema(mongoRepoConfig, schemaValidationConfig, encryptionConfig.Enabled)
+		if err != nil {
+			logger.AppLog.Error().Err(err).Msg("unable to apply users schema validation")
+		}
+	}
+	var userMongoRepositoryMapper infrastructure.UserMongoRepositoryMapper = infrastructure.NewDefaultMongoRepositoryMapper()
+	var idempotencyMongoRepositoryMapper infrastructure.IdempotencyMongoRepositoryMapper = infrastructure.NewDefaultIdempotencyMongoRepositoryMapper()
+	if encryptionConfig.Enabled {
+		keyring, err := encryption.LoadKeyring(encryptionConfig.KeyringFile)
+		if err != nil {
+			logger.AppLog.Fatal().Err(err).Msg("unable to load encryption keyring")
+		}
+		encryptedMapper := infrastructure.NewEncryptedMongoRepositoryMapper(userMongoRepositoryMapper, keyring)
+		userMongoRepositoryMapper = encryptedMapper
+		// The stored idempotent responses have the users personal data too
+		idempotencyMongoRepositoryMapper = infrastructure.NewEncryptedIdempotencyMongoRepositoryMapper(idempotencyMongoRepositoryMapper, keyring)
+
+		if encryptionConfig.ReencryptionIntervalSeconds > 0 {
+			reencryptionJob := infrastructure.NewUserReencryptionJob(mongoRepoConfig, encryptionConfig, encryptedMapper)
+			worker.Register("user-reencryption", reencryptionJob.Run)
+		}
+	}
 	userMongoRepository := infrastructure.NewMongoUserRepository(mongoRepoConfig, userMongoRepositoryMapper)
+	auditMongoRepositoryMapper := infrastructure.NewDefaultAuditMongoRepositoryMapper()
+	auditMongoRepository := infrastructure.NewMongoAuditRepository(mongoRepoConfig, auditMongoRepositoryMapper)
+	credentialMongoRepositoryMapper := infrastructure.NewDefaultCredentialMongoRepositoryMapper()
+	credentialMongoRepository := infrastructure.NewMongoCredentialRepository(mongoRepoConfig, credentialMongoRepositoryMapper)
+	apiKeyMongoRepositoryMapper := infrastructure.NewDefaultAPIKeyMongoRepositoryMapper()
+	apiKeyMongoRepository := infrastructure.NewMongoAPIKeyRepository(mongoRepoConfig, apiKeyMongoRepositoryMapper)
+	groupMongoRepositoryMapper := infrastructure.NewDefaultGroupMongoRepositoryMapper()
+	groupMongoRepository := infrastructure.NewMongoGroupRepository(mongoRepoConfig, groupMongoRepositoryMapper)
+	idempotencyMongoRepository := infrastructure.NewMongoIdempotencyRepository(mongoRepoConfig, idempotencyMongoRepositoryMapper)
+
+	avatarStore := blob.NewLocalBlobStore(avatarConfig.Directory)
+
+	mailer := newMailer(mailConfig)
+
+	userReferenceGenerator, err := reference.NewGenerator(referenceConfig.Generator, referenceConfig.Prefix)
+	if err != nil {
+		logger.AppLog.Fatal().Err(err).Msg("reference configuration is not valid")
+	}
+
+	tokenIssuer, err := auth.NewDefaultTokenIssuer(authConfig)
+	if err != nil {
+		logger.AppLog.Fatal().Err(err).Msg("unable to load access token keys")
+	}
+
+	identityMiddleware, err := handler.NewIdentityMiddleware(authorizationConfig)
+	if err != nil {
+		logger.AppLog.Fatal().Err(err).Msg("unable to load authorization roles")
+	}
 
 	// Services
+	emailVerifier := user.NewDefaultEmailVerifier(emailVerificationConfig, mailer)
 	userFindAllUC := user.NewDefaultFindAll(userMongoRepository)
 	userFindByReferenceUC := user.NewDefaultFindByReference(userMongoRepository)
-	userCreateUC := user.NewDefaultCreate(userMongoRepository)
-	userUpdateUC := user.NewDefaultUpdate(userMongoRepository)
-	userDeleteUC := user.NewDefaultDelete(userMongoRepository)
-	userSearchUC := user.NewDefaulSearch(userMongoRepository)
+	userFindByExternalIDUC := user.NewDefaultFindByExternalID(userMongoRepository)
+	userCreateUC := user.NewDefaultCreate(attributesConfig, userMongoRepository, emailVerifier, userReferenceGenerator)
+	userUpdateUC := user.NewDefaultUpdate(attributesConfig, userMongoRepository)
+	userPatchUC := user.NewDefaultPatch(attributesConfig, userMongoRepository)
+	userDeleteUC := user.NewDefaultDelete(userMongoRepository, groupMongoRepository)
+	userSearchUC := user.NewDefaulSearch(attributesConfig, userMongoRepository)
+	userChangeStatusUC := user.NewDefaultChangeStatus(userMongoRepository, auditMongoRepository)
+	userVerifyEmailUC := user.NewDefaultVerifyEmail(userMongoRepository, emailVerifier)
+	userResendEmailVerificationUC := user.NewDefaultResendEmailVerification(emailVerificationConfig, userMongoRepository, emailVerifier)
+	userSetPasswordUC := user.NewDefaultSetPassword(authConfig.PasswordPolicy, userMongoRepository, credentialMongoRepository)
+	userSetRolesUC := user.NewDefaultSetRoles(authorizationConfig, userMongoRepository, auditMongoRepository)
+	userSetAvatarUC := user.NewDefaultSetAvatar(avatarConfig, userMongoRepository, avatarStore)
+	userFindAvatarUC := user.NewDefaultFindAvatar(userMongoRepository, avatarStore)
+	userFindDuplicatesUC := user.NewDuplicateDetectionJob(duplicateDetectionConfig, user.NewDefaultFindDuplicates(duplicateDetectionConfig, userMongoRepository))
+	worker.Register("user-duplicate-detection", userFindDuplicatesUC.Run)
+	userEraseUC := user.NewDefaultErase(userMongoRepository, groupMongoRepository, auditMongoRepository, credentialMongoRepository, idempotencyMongoRepository, avatarStore, emailVerifier, userFindDuplicatesUC)
+	userAddTagUC := user.NewDefaultAddTag(userMongoRepository)
+	userRemoveTagUC := user.NewDefaultRemoveTag(userMongoRepository)
+	userFindTagsUC := user.NewDefaultFindTags(userMongoRepository)
+	userMergeUC := user.NewDefaultMerge(userMongoRepository, groupMongoRepository, auditMongoRepository)
+	userExportUC := user.NewDefaultExport(userMongoRepository,
+		user.NewUserRecordContributor(),
+		user.NewAuditContributor(auditMongoRepository),
+		user.NewGroupMembershipContributor(groupMongoRepository),
+		user.NewCredentialContributor(credentialMongoRepository),
+		user.NewAvatarContributor(avatarStore))
+	authLoginUC := auth.NewDefaultLogin(userMongoRepository, credentialMongoRepository, tokenIssuer)
+	authenticateAPIKeyUC := auth.NewDefaultAuthenticateAPIKey(apiKeyMongoRepository)
+	findAllAPIKeysUC := auth.NewDefaultFindAllAPIKeys(apiKeyMongoRepository)
+	createAPIKeyUC := auth.NewDefaultCreateAPIKey(apiKeyMongoRepository)
+	rotateAPIKeyUC := auth.NewDefaultRotateAPIKey(apiKeyMongoRepository)
+	revokeAPIKeyUC := auth.NewDefaultRevokeAPIKey(apiKeyMongoRepository)
+	groupFindAllUC := group.NewDefaultFindAll(groupMongoRepository)
+	groupFindByReferenceUC := group.NewDefaultFindByReference(groupMongoRepository)
+	groupCreateUC := group.NewDefaultCreate(groupMongoRepository)
+	groupUpdateUC := group.NewDefaultUpdate(groupMongoRepository)
+	groupDeleteUC := group.NewDefaultDelete(groupMongoRepository)
+	groupAddMemberUC := group.NewDefaultAddMember(groupMongoRepository, userMongoRepository)
+	groupRemoveMemberUC := group.NewDefaultRemoveMember(groupMongoRepository)
+	groupFindByMemberUC := group.NewDefaultFindByMember(groupMongoRepository, userMongoRepository)
+	idempotencyKeeper := idempotency.NewDefaultKeeper(idempotencyConfig, idempotencyMongoRepository)
 
 	// Handlers
 	userMapper := handler.NewDefaultUserMapper()
@@ -68,17 +280,105 @@ func configureMappings(router *gin.Engine) {
 		userFindByReferenceUC,
 		userCreateUC,
 		userUpdateUC,
+		userPatchUC,
 		userDeleteUC,
 		userSearchUC)
+	userPrivacyHandler := handler.NewDefaultUserPrivacy(userMapper, userEraseUC, userExportUC)
+	userStatusHandler := handler.NewDefaultUserStatus(userMapper, userChangeStatusUC)
+	userPasswordHandler := handler.NewDefaultUserPassword(userSetPasswordUC)
+	userRolesHandler := handler.NewDefaultUserRoles(userMapper, userSetRolesUC)
+	userAvatarHandler := handler.NewDefaultUserAvatar(avatarConfig, userMapper, userSetAvatarUC, userFindAvatarUC)
+	userDuplicateHandler := handler.NewDefaultUserDuplicate(userMapper, userFindDuplicatesUC, userMergeUC)
+	userTagsHandler := handler.NewDefaultUserTags(userMapper, userAddTagUC, userRemoveTagUC, userFindTagsUC)
+	userExternalIDsHandler := handler.NewDefaultUserExternalIDs(userMapper, userFindByExternalIDUC)
+	authHandler := handler.NewDefaultAuth(authLoginUC)
+	apiKeyHandler := handler.NewDefaultAPIKey(handler.NewDefaultAPIKeyMapper(), findAllAPIKeysUC, createAPIKeyUC, rotateAPIKeyUC, revokeAPIKeyUC)
+	userEmailVerificationHandler := handler.NewDefaultUserEmailVerification(userMapper, userVerifyEmailUC, userResendEmailVerificationUC)
+	groupHandler := handler.NewDefaultGroup(handler.NewDefaultGroupMapper(),
+		groupFindAllUC,
+		groupFindByReferenceUC,
+		groupCreateUC,
+		groupUpdateUC,
+		groupDeleteUC,
+		groupAddMemberUC,
+		groupRemoveMemberUC,
+		groupFindByMemberUC)
 
 	// Routes
 	router.GET("/health", handler.Health)
+	router.GET("/ready", handler.Readiness)
 
 	api := router.Group("/api/v1")
-	api.GET("/users/search", userHandler.Search)
-	api.GET("/users", userHandler.FindAll)
-	api.GET("/users/:id", userHandler.FindByReference)
-	api.POST("/users", userHandler.Create)
-	api.PUT("/users/:id", userHandler.Update)
-	api.DELETE("/users/:id", userHandler.Delete)
+	if authConfig.Enabled {
+		api.Use(handler.NewAPIKeyMiddleware(authenticateAPIKeyUC))
+		api.Use(handler.NewAuthMiddleware(tokenIssuer,
+			"POST /api/v1/auth/login",
+			"POST /api/v1/users/verify-email",
+			"GET /api/v1/errors"))
+	}
+	api.Use(identityMiddleware)
+
+	canRead := handler.Authorize(domain.PermissionUsersRead)
+	canWrite := handler.Authorize(domain.PermissionUsersWrite)
+	canAdmin := handler.Authorize(domain.PermissionUsersAdmin)
+	// Login and API keys responses have secrets, so they are not stored to be replayed
+	idempotent := handler.NewIdempotencyMiddleware(idempotencyKeeper)
+	api.GET("/users/search", canRead, userHandler.Search)
+	api.GET("/users/duplicates", canAdmin, userDuplicateHandler.FindDuplicates)
+	api.GET("/users/by-external/:source/:externalId", canRead, userExternalIDsHandler.FindByExternalID)
+	api.GET("/users", canRead, userHandler.FindAll)
+	api.GET("/users/:id", canRead, userHandler.FindByReference)
+	api.POST("/users", canWrite, idempotent, userHandler.Create)
+	api.POST("/users/verify-email", userEmailVerificationHandler.Verify)
+	api.PUT("/users/:id", canWrite, userHandler.Update)
+	api.PATCH("/users/:id", canWrite, userHandler.Patch)
+	api.DELETE("/users/:id", canAdmin, userHandler.Delete)
+	api.POST("/users/:id/verify-email/resend", canWrite, idempotent, userEmailVerificationHandler.Resend)
+	api.PUT("/users/:id/password", canWrite, userPasswordHandler.SetPassword)
+	api.PUT("/users/:id/roles", canAdmin, userRolesHandler.SetRoles)
+	api.PUT("/users/:id/avatar", canWrite, userAvatarHandler.SetAvatar)
+	api.GET("/users/:id/avatar", canRead, userAvatarHandler.FindAvatar)
+	api.POST("/users/:id/suspend", canAdmin, idempotent, userStatusHandler.Suspend)
+	api.POST("/users/:id/reactivate", canAdmin, idempotent, userStatusHandler.Reactivate)
+	api.POST("/users/:id/merge", canAdmin, idempotent, userDuplicateHandler.Merge)
+	api.POST("/users/:id/erase", canAdmin, idempotent, userPrivacyHandler.Erase)
+	api.GET("/users/:id/data-export", canAdmin, userPrivacyHandler.Export)
+	api.PUT("/users/:id/tags/:tag", canWrite, userTagsHandler.AddTag)
+	api.DELETE("/users/:id/tags/:tag", canWrite, userTagsHandler.RemoveTag)
+	api.GET("/users/:id/groups", canRead, groupHandler.FindByMember)
+	api.GET("/tags", canRead, userTagsHandler.FindTags)
+	api.GET("/groups", canRead, groupHandler.FindAll)
+	api.GET("/groups/:id", canRead, groupHandler.FindByReference)
+	api.POST("/groups", canWrite, idempotent, groupHandler.Create)
+	api.PUT("/groups/:id", canWrite, groupHandler.Update)
+	api.DELETE("/groups/:id", canAdmin, groupHandler.Delete)
+	api.PUT("/groups/:id/members/:userId", canWrite, groupHandler.AddMember)
+	api.DELETE("/groups/:id/members/:userId", canWrite, groupHandler.RemoveMember)
+	api.POST("/auth/login", authHandler.Login)
+	api.GET("/errors", handler.ErrorCatalog)
+
+	apiKeys := api.Group("/api-keys",
+		handler.RequireAPIKeyScope(domain.APIKeyScopeAdmin),
+		handler.Authorize(domain.PermissionAPIKeysAdmin))
+	apiKeys.GET("", apiKeyHandler.FindAll)
+	apiKeys.POST("", apiKeyHandler.Create)
+	apiKeys.POST("/:id/rotate", apiKeyHandler.Rotate)
+	apiKeys.POST("/:id/revoke", apiKeyHandler.Revoke)
+}
+
+// newMailer creates the mailer for the configured driver. The log mailer is used by default
+func newMailer(mailConfig domain.MailConfiguration) mail.Mailer {
+	switch mailConfig.Driver {
+	case "smtp":
+		return mail.NewSMTPMailer(mail.SMTPConfiguration{
+			Host:     mailConfig.SMTP.Host,
+			Port:     mailConfig.SMTP.Port,
+			Username: mailConfig.SMTP.Username,
+			Password: mailConfig.SMTP.Password,
+		})
+	case "file":
+		return mail.NewFileMailer(mailConfig.Directory)
+	default:
+		return mail.NewLogMailer()
+	}
 }
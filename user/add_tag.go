@@ -0,0 +1,72 @@
+package user
+
+import (
+	"sort"
+	"time"
+
+	"github.com/desarrollogj/golang-api-example/domain"
+	"github.com/desarrollogj/golang-api-example/infrastructure"
+	"github.com/desarrollogj/golang-api-example/libs/errors"
+	"github.com/desarrollogj/golang-api-example/libs/logger"
+)
+
+// AddTag represents the method to be implemented to add a tag to an user
+type AddTag interface {
+	Execute(input domain.UserTagInput) (domain.User, error)
+}
+
+// defaultAddTag is the default implementation of AddTag interface
+type defaultAddTag struct {
+	repository infrastructure.UserRepository
+}
+
+// NewDefaultAddTag creates a defaultAddTag instance
+func NewDefaultAddTag(repository infrastructure.UserRepository) defaultAddTag {
+	return defaultAddTag{
+		repository: repository,
+	}
+}
+
+// Execute add a normalized tag to an User. Adding a current tag has no effect.
+// The tag is added atomically, so the concurrent changes of the user are kept
+func (s defaultAddTag) Execute(input domain.UserTagInput) (domain.User, error) {
+	tag, err := NormalizeTag(input.Tag)
+	if err != nil {
+		return domain.User{}, err
+	}
+
+	currentUser, err := findUserToTag(s.repository, input.Reference)
+	if err != nil {
+		return domain.User{}, err
+	}
+	if containsString(currentUser.Tags, tag) {
+		return currentUser, nil
+	}
+	if len(currentUser.Tags) >= maxUserTags {
+		return domain.User{}, tagsLimitError()
+	}
+
+	updatedDate := time.Now().UTC()
+	ok, err := s.repository.AddTag(currentUser.Reference, tag, maxUserTags, updatedDate)
+	if err != nil {
+		errMsg := "unexpected error when add the user tag"
+		logger.AppLog.Error().Err(err).Msg(errMsg)
+		return domain.User{}, errors.NewFatalError(errMsg)
+	}
+	if !ok {
+		// The user changed after it was read: it was erased, got the tag or reached the limit
+		currentUser, err = findUserToTag(s.repository, input.Reference)
+		if err != nil {
+			return domain.User{}, err
+		}
+		if containsString(currentUser.Tags, tag) {
+			return currentUser, nil
+		}
+		return domain.User{}, tagsLimitError()
+	}
+
+	currentUser.Tags = append(append([]string{}, currentUser.Tags...), tag)
+	sort.Strings(currentUser.Tags)
+	currentUser.UpdatedDate = updatedDate
+	return currentUser, nil
+}
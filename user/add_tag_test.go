@@ -0,0 +1,155 @@
+package user
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/desarrollogj/golang-api-example/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestAddTag_GivenANewTag_WhenExecute_ThenAddTheNormalizedTag(t *testing.T) {
+	t.Log("Successfully add a tag to an User")
+
+	currentUser := domain.User{
+		GenericEntity: domain.GenericEntity{Reference: "REF1", IsActive: true},
+		Tags:          []string{"vip"},
+	}
+	repositoryMock := new(repositoryMock)
+	repositoryMock.On("FindByReference", "REF1").Return(currentUser, nil)
+	repositoryMock.On("AddTag", "REF1", "early-adopter", maxUserTags, mock.AnythingOfType("time.Time")).Return(true, nil)
+
+	useCase := NewDefaultAddTag(repositoryMock)
+
+	user, err := useCase.Execute(domain.UserTagInput{Reference: "REF1", Tag: "Early Adopter"})
+
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"early-adopter", "vip"}, user.Tags)
+	assert.False(t, user.UpdatedDate.IsZero())
+
+	repositoryMock.AssertExpectations(t)
+}
+
+func TestAddTag_GivenACurrentTag_WhenExecute_ThenReturnTheUserWithoutChanges(t *testing.T) {
+	t.Log("Successfully add a tag the User already has, so nothing is stored")
+
+	currentUser := domain.User{
+		GenericEntity: domain.GenericEntity{Reference: "REF1", IsActive: true},
+		Tags:          []string{"vip"},
+	}
+	repositoryMock := new(repositoryMock)
+	repositoryMock.On("FindByReference", "REF1").Return(currentUser, nil)
+
+	useCase := NewDefaultAddTag(repositoryMock)
+
+	user, err := useCase.Execute(domain.UserTagInput{Reference: "REF1", Tag: "VIP"})
+
+	assert.Nil(t, err)
+	assert.Equal(t, currentUser, user)
+
+	repositoryMock.AssertNotCalled(t, "AddTag", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestAddTag_GivenAnUserWithTheMaximumTags_WhenExecute_ThenReturnAValidationError(t *testing.T) {
+	t.Log("Failure to add a tag to an User because it has the maximum number of tags")
+
+	tags := []string{}
+	for i := 0; i < maxUserTags; i++ {
+		tags = append(tags, fmt.Sprintf("tag-%d", i))
+	}
+	repositoryMock := new(repositoryMock)
+	repositoryMock.On("FindByReference", "REF1").Return(domain.User{GenericEntity: domain.GenericEntity{Reference: "REF1"}, Tags: tags}, nil)
+
+	useCase := NewDefaultAddTag(repositoryMock)
+
+	_, err := useCase.Execute(domain.UserTagInput{Reference: "REF1", Tag: "vip"})
+
+	assert.NotNil(t, err)
+	assert.Equal(t, "an user can not have more than 50 tags", err.Error())
+
+	repositoryMock.AssertNotCalled(t, "AddTag", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestAddTag_GivenATagAddedConcurrently_WhenExecute_ThenReturnTheCurrentUser(t *testing.T) {
+	t.Log("Successfully add a tag another request added after the User was read")
+
+	currentUser := domain.User{GenericEntity: domain.GenericEntity{Reference: "REF1", IsActive: true}}
+	taggedUser := domain.User{GenericEntity: domain.GenericEntity{Reference: "REF1", IsActive: true}, Tags: []string{"vip"}}
+	repositoryMock := new(repositoryMock)
+	repositoryMock.On("FindByReference", "REF1").Return(currentUser, nil).Once()
+	repositoryMock.On("FindByReference", "REF1").Return(taggedUser, nil).Once()
+	repositoryMock.On("AddTag", "REF1", "vip", maxUserTags, mock.AnythingOfType("time.Time")).Return(false, nil)
+
+	useCase := NewDefaultAddTag(repositoryMock)
+
+	user, err := useCase.Execute(domain.UserTagInput{Reference: "REF1", Tag: "vip"})
+
+	assert.Nil(t, err)
+	assert.Equal(t, taggedUser, user)
+
+	repositoryMock.AssertExpectations(t)
+}
+
+func TestAddTag_GivenAnUserErasedConcurrently_WhenExecute_ThenReturnAValidationError(t *testing.T) {
+	t.Log("Failure to add a tag to an User because it was erased after it was read")
+
+	erased := time.Now().UTC()
+	repositoryMock := new(repositoryMock)
+	repositoryMock.On("FindByReference", "REF1").Return(domain.User{GenericEntity: domain.GenericEntity{Reference: "REF1"}}, nil).Once()
+	repositoryMock.On("FindByReference", "REF1").Return(domain.User{GenericEntity: domain.GenericEntity{Reference: "REF1"}, ErasedDate: &erased}, nil).Once()
+	repositoryMock.On("AddTag", "REF1", "vip", maxUserTags, mock.AnythingOfType("time.Time")).Return(false, nil)
+
+	useCase := NewDefaultAddTag(repositoryMock)
+
+	_, err := useCase.Execute(domain.UserTagInput{Reference: "REF1", Tag: "vip"})
+
+	assert.NotNil(t, err)
+	assert.Equal(t, "user was erased and can not be updated", err.Error())
+}
+
+func TestAddTag_GivenANotValidTag_WhenExecute_ThenReturnAValidationError(t *testing.T) {
+	t.Log("Failure to add a tag to an User because the tag is not valid")
+
+	repositoryMock := new(repositoryMock)
+
+	useCase := NewDefaultAddTag(repositoryMock)
+
+	_, err := useCase.Execute(domain.UserTagInput{Reference: "REF1", Tag: "vip!"})
+
+	assert.NotNil(t, err)
+	assert.Equal(t, "tag vip! is not valid", err.Error())
+
+	repositoryMock.AssertNotCalled(t, "FindByReference", mock.Anything)
+}
+
+func TestAddTag_GivenAnUnknownUser_WhenExecute_ThenReturnANotFoundError(t *testing.T) {
+	t.Log("Failure to add a tag to an User because it does not exist")
+
+	repositoryMock := new(repositoryMock)
+	repositoryMock.On("FindByReference", "REF1").Return(domain.User{}, nil)
+
+	useCase := NewDefaultAddTag(repositoryMock)
+
+	_, err := useCase.Execute(domain.UserTagInput{Reference: "REF1", Tag: "vip"})
+
+	assert.NotNil(t, err)
+	assert.Equal(t, "user not found", err.Error())
+}
+
+func TestAddTag_GivenANewTag_WhenExecuteAndUpdateReturnedAnError_ThenReturnAFatalError(t *testing.T) {
+	t.Log("Failure to add a tag to an User because repository returned an error")
+
+	repositoryMock := new(repositoryMock)
+	repositoryMock.On("FindByReference", "REF1").Return(domain.User{GenericEntity: domain.GenericEntity{Reference: "REF1"}}, nil)
+	repositoryMock.On("AddTag", "REF1", "vip", maxUserTags, mock.AnythingOfType("time.Time")).Return(false, errors.New("repository error"))
+
+	useCase := NewDefaultAddTag(repositoryMock)
+
+	_, err := useCase.Execute(domain.UserTagInput{Reference: "REF1", Tag: "vip"})
+
+	assert.NotNil(t, err)
+	assert.Equal(t, "unexpected error when add the user tag", err.Error())
+}
@@ -0,0 +1,173 @@
+package user
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/desarrollogj/golang-api-example/domain"
+	"github.com/desarrollogj/golang-api-example/libs/errors"
+)
+
+// AttributeDateLayout is the format of date attributes
+const AttributeDateLayout = "2006-01-02"
+
+// CheckAttributesConfiguration checks that every attribute definition has a known type, and enums have values
+func CheckAttributesConfiguration(config domain.UserAttributesConfiguration) error {
+	for name, definition := range config.Definitions {
+		switch definition.Type {
+		case domain.UserAttributeTypeString, domain.UserAttributeTypeNumber, domain.UserAttributeTypeBool, domain.UserAttributeTypeDate:
+		case domain.UserAttributeTypeEnum:
+			if len(definition.Values) == 0 {
+				return fmt.Errorf("attribute %s is an enum without values", name)
+			}
+		default:
+			return fmt.Errorf("attribute %s type %s is not valid", name, definition.Type)
+		}
+	}
+
+	return nil
+}
+
+// normalizeAttributes validates the attributes with their definitions, and converts the values to the attribute type:
+// trimmed strings, float64 numbers, booleans and UTC dates. Empty values are removed
+func normalizeAttributes(config domain.UserAttributesConfiguration, attributes map[string]interface{}) (map[string]interface{}, error) {
+	normalized := map[string]interface{}{}
+	for _, name := range sortedKeys(attributes) {
+		definition, ok := config.Definitions[name]
+		if !ok {
+			return nil, fieldValidationError("attributes."+name, "unknown", "", "attribute_not_valid", fmt.Sprintf("attribute %s is not valid", name), name)
+		}
+		value, err := normalizeAttribute(name, definition, attributes[name])
+		if err != nil {
+			return nil, err
+		}
+		if value != nil {
+			normalized[name] = value
+		}
+	}
+
+	required := []string{}
+	for name, definition := range config.Definitions {
+		if _, ok := normalized[name]; definition.Required && !ok {
+			required = append(required, name)
+		}
+	}
+	if len(required) > 0 {
+		sort.Strings(required)
+		return nil, fieldValidationError("attributes."+required[0], "required", "", "attribute_required",
+			fmt.Sprintf("attribute %s is required", required[0]), required[0])
+	}
+
+	if len(normalized) == 0 {
+		return nil, nil
+	}
+	return normalized, nil
+}
+
+// normalizeAttributeFilters parses the search filters to the attribute type
+func normalizeAttributeFilters(config domain.UserAttributesConfiguration, filters map[string]interface{}) (map[string]interface{}, error) {
+	normalized := map[string]interface{}{}
+	for _, name := range sortedKeys(filters) {
+		definition, ok := config.Definitions[name]
+		if !ok {
+			return nil, errors.NewValidationError(fmt.Sprintf("attribute %s is not valid", name)).WithKey("attribute_not_valid", name)
+		}
+
+		value := filters[name]
+		if text, ok := value.(string); ok {
+			switch definition.Type {
+			case domain.UserAttributeTypeNumber:
+				if number, err := strconv.ParseFloat(text, 64); err == nil {
+					value = number
+				}
+			case domain.UserAttributeTypeBool:
+				if boolean, err := strconv.ParseBool(text); err == nil {
+					value = boolean
+				}
+			}
+		}
+		value, err := normalizeAttribute(name, definition, value)
+		if err != nil {
+			return nil, err
+		}
+		if value == nil {
+			return nil, errors.NewValidationError(fmt.Sprintf("attribute %s filter value is required", name)).WithKey("attribute_filter_value_required", name)
+		}
+		normalized[name] = value
+	}
+
+	return normalized, nil
+}
+
+func normalizeAttribute(name string, definition domain.UserAttributeDefinition, value interface{}) (interface{}, error) {
+	if value == nil {
+		return nil, nil
+	}
+
+	switch definition.Type {
+	case domain.UserAttributeTypeString:
+		text, ok := value.(string)
+		if !ok {
+			return nil, fieldValidationError("attributes."+name, "type", "string", "attribute_string", fmt.Sprintf("attribute %s must be a string", name), name)
+		}
+		if text = strings.TrimSpace(text); len(text) > 0 {
+			return text, nil
+		}
+		return nil, nil
+	case domain.UserAttributeTypeNumber:
+		switch number := value.(type) {
+		case float64:
+			return number, nil
+		case int:
+			return float64(number), nil
+		case int64:
+			return float64(number), nil
+		}
+		return nil, fieldValidationError("attributes."+name, "type", "number", "attribute_number", fmt.Sprintf("attribute %s must be a number", name), name)
+	case domain.UserAttributeTypeBool:
+		boolean, ok := value.(bool)
+		if !ok {
+			return nil, fieldValidationError("attributes."+name, "type", "boolean", "attribute_boolean", fmt.Sprintf("attribute %s must be a boolean", name), name)
+		}
+		return boolean, nil
+	case domain.UserAttributeTypeDate:
+		switch date := value.(type) {
+		case time.Time:
+			return date.UTC(), nil
+		case string:
+			if len(strings.TrimSpace(date)) == 0 {
+				return nil, nil
+			}
+			if parsed, err := time.Parse(AttributeDateLayout, strings.TrimSpace(date)); err == nil {
+				return parsed, nil
+			}
+		}
+		return nil, fieldValidationError("attributes."+name, "datetime", AttributeDateLayout, "attribute_date",
+			fmt.Sprintf("attribute %s must be a date (%s)", name, AttributeDateLayout), name, AttributeDateLayout)
+	case domain.UserAttributeTypeEnum:
+		text, ok := value.(string)
+		if ok && len(strings.TrimSpace(text)) == 0 {
+			return nil, nil
+		}
+		if ok && containsString(definition.Values, strings.TrimSpace(text)) {
+			return strings.TrimSpace(text), nil
+		}
+		return nil, fieldValidationError("attributes."+name, "oneof", strings.Join(definition.Values, " "), "attribute_oneof",
+			fmt.Sprintf("attribute %s must be one of %s", name, strings.Join(definition.Values, ", ")), name, strings.Join(definition.Values, ", "))
+	default:
+		return nil, errors.NewValidationError(fmt.Sprintf("attribute %s is not valid", name)).WithKey("attribute_not_valid", name)
+	}
+}
+
+// sortedKeys returns the map keys sorted, so the validation errors do not depend on the map order
+func sortedKeys(values map[string]interface{}) []string {
+	keys := []string{}
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
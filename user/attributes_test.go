@@ -0,0 +1,106 @@
+package user
+
+import (
+	"testing"
+	"time"
+
+	"github.com/desarrollogj/golang-api-example/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+var attributesConfig = domain.UserAttributesConfiguration{
+	Definitions: map[string]domain.UserAttributeDefinition{
+		"plan":       {Type: domain.UserAttributeTypeEnum, Values: []string{"free", "pro"}},
+		"employeeId": {Type: domain.UserAttributeTypeString},
+		"seats":      {Type: domain.UserAttributeTypeNumber},
+		"newsletter": {Type: domain.UserAttributeTypeBool},
+		"hiredOn":    {Type: domain.UserAttributeTypeDate},
+	},
+}
+
+func TestNormalizeAttributes_GivenValidAttributes_WhenNormalize_ThenReturnTypedValues(t *testing.T) {
+	t.Log("Successfully normalize the User attributes")
+
+	attributes, err := normalizeAttributes(attributesConfig, map[string]interface{}{
+		"plan":       " pro ",
+		"employeeId": " E-1 ",
+		"seats":      10,
+		"newsletter": true,
+		"hiredOn":    "2024-03-01",
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"plan":       "pro",
+		"employeeId": "E-1",
+		"seats":      10.0,
+		"newsletter": true,
+		"hiredOn":    time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+	}, attributes)
+}
+
+func TestNormalizeAttributes_GivenNotValidAttributes_WhenNormalize_ThenReturnAValidationError(t *testing.T) {
+	t.Log("Failure to normalize the User attributes because they are not valid")
+
+	required := domain.UserAttributesConfiguration{
+		Definitions: map[string]domain.UserAttributeDefinition{
+			"plan": {Type: domain.UserAttributeTypeString, Required: true},
+		},
+	}
+	tests := []struct {
+		name       string
+		config     domain.UserAttributesConfiguration
+		attributes map[string]interface{}
+		expected   string
+	}{
+		{"Unknown", attributesConfig, map[string]interface{}{"other": "value"}, "attribute other is not valid"},
+		{"String", attributesConfig, map[string]interface{}{"employeeId": 1.0}, "attribute employeeId must be a string"},
+		{"Number", attributesConfig, map[string]interface{}{"seats": "ten"}, "attribute seats must be a number"},
+		{"Bool", attributesConfig, map[string]interface{}{"newsletter": "yes"}, "attribute newsletter must be a boolean"},
+		{"Date", attributesConfig, map[string]interface{}{"hiredOn": "01/03/2024"}, "attribute hiredOn must be a date (2006-01-02)"},
+		{"Enum", attributesConfig, map[string]interface{}{"plan": "gold"}, "attribute plan must be one of free, pro"},
+		{"Required", required, map[string]interface{}{"plan": " "}, "attribute plan is required"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := normalizeAttributes(test.config, test.attributes)
+
+			assert.NotNil(t, err)
+			assert.Equal(t, test.expected, err.Error())
+		})
+	}
+}
+
+func TestNormalizeAttributeFilters_GivenTextFilters_WhenNormalize_ThenParseThemToTheAttributeType(t *testing.T) {
+	t.Log("Successfully parse the attribute search filters")
+
+	filters, err := normalizeAttributeFilters(attributesConfig, map[string]interface{}{
+		"plan":       "pro",
+		"seats":      "10",
+		"newsletter": "true",
+		"hiredOn":    "2024-03-01",
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"plan":       "pro",
+		"seats":      10.0,
+		"newsletter": true,
+		"hiredOn":    time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+	}, filters)
+
+	_, err = normalizeAttributeFilters(attributesConfig, map[string]interface{}{"seats": "many"})
+	assert.Equal(t, "attribute seats must be a number", err.Error())
+}
+
+func TestCheckAttributesConfiguration_GivenAConfiguration_WhenCheck_ThenValidateTheDefinitions(t *testing.T) {
+	t.Log("Successfully check the attributes configuration")
+
+	assert.Nil(t, CheckAttributesConfiguration(attributesConfig))
+	assert.EqualError(t, CheckAttributesConfiguration(domain.UserAttributesConfiguration{
+		Definitions: map[string]domain.UserAttributeDefinition{"plan": {Type: domain.UserAttributeTypeEnum}},
+	}), "attribute plan is an enum without values")
+	assert.EqualError(t, CheckAttributesConfiguration(domain.UserAttributesConfiguration{
+		Definitions: map[string]domain.UserAttributeDefinition{"plan": {Type: "list"}},
+	}), "attribute plan type list is not valid")
+}
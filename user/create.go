@@ -5,9 +5,8 @@ import (
 
 	"github.com/desarrollogj/golang-api-example/domain"
 	"github.com/desarrollogj/golang-api-example/infrastructure"
-	"github.com/desarrollogj/golang-api-example/libs/errors"
 	"github.com/desarrollogj/golang-api-example/libs/logger"
-	"github.com/google/uuid"
+	"github.com/desarrollogj/golang-api-example/libs/reference"
 )
 
 // Create represents the method to be implemented to create an user
@@ -17,36 +16,76 @@ type Create interface {
 
 // defaultCreate is the default implementation of Create interface
 type defaultCreate struct {
+	config     domain.UserAttributesConfiguration
 	repository infrastructure.UserRepository
+	verifier   EmailVerifier
+	generator  reference.Generator
 }
 
 // NewDefaultCreate creates a defaultCreate instance
-func NewDefaultCreate(repository infrastructure.UserRepository) defaultCreate {
+func NewDefaultCreate(config domain.UserAttributesConfiguration, repository infrastructure.UserRepository, verifier EmailVerifier,
+	generator reference.Generator) defaultCreate {
 	return defaultCreate{
+		config:     config,
 		repository: repository,
+		verifier:   verifier,
+		generator:  generator,
 	}
 }
 
-// Create an User
+// Create an User. The email is created unverified and a verification token is sent to it.
+// A sending failure does not fail the creation, since the token can be sent again
 func (s defaultCreate) Execute(input domain.UserCreateInput) (domain.User, error) {
+	profile, err := normalizeProfile(input.UserProfile)
+	if err != nil {
+		return domain.User{}, err
+	}
+	attributes, err := normalizeAttributes(s.config, input.Attributes)
+	if err != nil {
+		return domain.User{}, err
+	}
+	tags, err := normalizeTags(input.Tags)
+	if err != nil {
+		return domain.User{}, err
+	}
+	externalIDs, err := normalizeExternalIDs(input.ExternalIDs)
+	if err != nil {
+		return domain.User{}, err
+	}
+	err = checkExternalIDsAvailable(s.repository, "", externalIDs)
+	if err != nil {
+		return domain.User{}, err
+	}
+
 	created := time.Now().UTC()
 	user := domain.User{
 		GenericEntity: domain.GenericEntity{
-			Reference:   uuid.NewString(),
+			Reference:   s.generator.Generate(),
 			IsActive:    true,
 			CreatedDate: created,
 			UpdatedDate: created,
 		},
-		FirstName: input.FirstName,
-		LastName:  input.LastName,
-		Email:     input.Email,
+		UserProfile: profile,
+		FirstName:   input.FirstName,
+		LastName:    input.LastName,
+		Email:       input.Email,
+		Attributes:  attributes,
+		Tags:        tags,
+		ExternalIDs: externalIDs,
+		Status:      domain.UserStatusActive,
+		StatusDate:  created,
+		// The email is not verified until the user confirms it with the token sent below
+		EmailVerificationSentDate: &created,
+	}
+
+	user, err = s.repository.Create(user)
+	if err != nil {
+		return domain.User{}, storeError(err, "unexpected error when create the user")
 	}
 
-	user, err := s.repository.Create(user)
+	err = s.verifier.Send(user)
 	if err != nil {
-		errMsg := "unexpected error when create the user"
-		logger.AppLog.Error().Err(err).Msg(errMsg)
-		return domain.User{}, errors.NewFatalError(errMsg)
+		logger.AppLog.Error().Err(err).Str("reference", user.Reference).Msg("unable to send the verification email")
 	}
 
 	return user, nil
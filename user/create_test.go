@@ -3,8 +3,10 @@ package user
 import (
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/desarrollogj/golang-api-example/domain"
+	"github.com/desarrollogj/golang-api-example/infrastructure"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
@@ -23,9 +25,15 @@ func TestCreate_GivenAnUser_WhenExecute_ThenCreateAnUser(t *testing.T) {
 		Email:     "foobar@email.com",
 	}
 	repositoryMock := new(repositoryMock)
-	repositoryMock.On("Create", mock.AnythingOfType("User")).Return(createdUser, nil)
+	repositoryMock.On("Create", mock.MatchedBy(func(user domain.User) bool {
+		return user.Reference == "USER1" && user.EmailVerifiedDate == nil && user.EmailVerificationSentDate != nil
+	})).Return(createdUser, nil)
+	verifierMock := new(emailVerifierMock)
+	verifierMock.On("Send", createdUser).Return(nil)
+	generatorMock := new(referenceGeneratorMock)
+	generatorMock.On("Generate").Return("USER1")
 
-	useCase := NewDefaultCreate(repositoryMock)
+	useCase := NewDefaultCreate(attributesConfig, repositoryMock, verifierMock, generatorMock)
 
 	created, err := useCase.Execute(input)
 
@@ -34,6 +42,7 @@ func TestCreate_GivenAnUser_WhenExecute_ThenCreateAnUser(t *testing.T) {
 	assert.Equal(t, createdUser, created)
 
 	repositoryMock.AssertExpectations(t)
+	verifierMock.AssertExpectations(t)
 }
 
 func TestCreate_GivenAnUser_WhenExecute_AndRepositoryFailred_ThenReturnAFatalError(t *testing.T) {
@@ -46,8 +55,10 @@ func TestCreate_GivenAnUser_WhenExecute_AndRepositoryFailred_ThenReturnAFatalErr
 	}
 	repositoryMock := new(repositoryMock)
 	repositoryMock.On("Create", mock.AnythingOfType("User")).Return(domain.User{}, errors.New("repository error"))
+	generatorMock := new(referenceGeneratorMock)
+	generatorMock.On("Generate").Return("USER1")
 
-	useCase := NewDefaultCreate(repositoryMock)
+	useCase := NewDefaultCreate(attributesConfig, repositoryMock, new(emailVerifierMock), generatorMock)
 
 	_, err := useCase.Execute(input)
 
@@ -56,3 +67,200 @@ func TestCreate_GivenAnUser_WhenExecute_AndRepositoryFailred_ThenReturnAFatalErr
 
 	repositoryMock.AssertExpectations(t)
 }
+
+func TestCreate_GivenAnUserWithNotValidProfile_WhenExecute_ThenReturnAValidationError(t *testing.T) {
+	t.Log("Failure to create an User because the profile is not valid")
+
+	input := domain.UserCreateInput{
+		UserProfile: domain.UserProfile{Phone: "11 1234-5678"},
+		FirstName:   "Foo",
+		LastName:    "Bar",
+		Email:       "foobar@email.com",
+	}
+	repositoryMock := new(repositoryMock)
+
+	useCase := NewDefaultCreate(attributesConfig, repositoryMock, new(emailVerifierMock), new(referenceGeneratorMock))
+
+	_, err := useCase.Execute(input)
+
+	assert.NotNil(t, err)
+	assert.Equal(t, "phone must be an international number in E.164 format", err.Error())
+
+	repositoryMock.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+func TestCreate_GivenAnUser_WhenExecuteAndVerificationEmailFailed_ThenCreateAnUser(t *testing.T) {
+	t.Log("Successfully create a User even when the verification email could not be sent")
+
+	input := domain.UserCreateInput{
+		FirstName: "Foo",
+		LastName:  "Bar",
+		Email:     "foobar@email.com",
+	}
+	createdUser := domain.User{
+		FirstName: "Foo",
+		LastName:  "Bar",
+		Email:     "foobar@email.com",
+	}
+	repositoryMock := new(repositoryMock)
+	repositoryMock.On("Create", mock.AnythingOfType("User")).Return(createdUser, nil)
+	verifierMock := new(emailVerifierMock)
+	verifierMock.On("Send", createdUser).Return(errors.New("mailer error"))
+	generatorMock := new(referenceGeneratorMock)
+	generatorMock.On("Generate").Return("USER1")
+
+	useCase := NewDefaultCreate(attributesConfig, repositoryMock, verifierMock, generatorMock)
+
+	created, err := useCase.Execute(input)
+
+	assert.Nil(t, err)
+	assert.Equal(t, createdUser, created)
+
+	repositoryMock.AssertExpectations(t)
+	verifierMock.AssertExpectations(t)
+}
+
+func TestCreate_GivenAnUserWithAttributes_WhenExecute_ThenStoreTheTypedAttributes(t *testing.T) {
+	t.Log("Successfully create a User with custom attributes")
+
+	input := domain.UserCreateInput{
+		FirstName:  "Foo",
+		LastName:   "Bar",
+		Email:      "foobar@email.com",
+		Attributes: map[string]interface{}{"plan": "pro", "seats": 10.0, "hiredOn": "2024-03-01"},
+	}
+	repositoryMock := new(repositoryMock)
+	repositoryMock.On("Create", mock.MatchedBy(func(user domain.User) bool {
+		return assert.ObjectsAreEqual(map[string]interface{}{
+			"plan":    "pro",
+			"seats":   10.0,
+			"hiredOn": time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+		}, user.Attributes)
+	})).Return(domain.User{Email: "foobar@email.com"}, nil)
+	verifierMock := new(emailVerifierMock)
+	verifierMock.On("Send", mock.AnythingOfType("User")).Return(nil)
+	generatorMock := new(referenceGeneratorMock)
+	generatorMock.On("Generate").Return("USER1")
+
+	useCase := NewDefaultCreate(attributesConfig, repositoryMock, verifierMock, generatorMock)
+
+	_, err := useCase.Execute(input)
+
+	assert.Nil(t, err)
+	repositoryMock.AssertExpectations(t)
+}
+
+func TestCreate_GivenAnUserWithNotValidAttributes_WhenExecute_ThenReturnAValidationError(t *testing.T) {
+	t.Log("Failure to create an User because an attribute is not valid")
+
+	input := domain.UserCreateInput{
+		FirstName:  "Foo",
+		LastName:   "Bar",
+		Email:      "foobar@email.com",
+		Attributes: map[string]interface{}{"plan": "gold"},
+	}
+	repositoryMock := new(repositoryMock)
+
+	useCase := NewDefaultCreate(attributesConfig, repositoryMock, new(emailVerifierMock), new(referenceGeneratorMock))
+
+	_, err := useCase.Execute(input)
+
+	assert.NotNil(t, err)
+	assert.Equal(t, "attribute plan must be one of free, pro", err.Error())
+	repositoryMock.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+func TestCreate_GivenAnUserWithExternalIDs_WhenExecute_ThenStoreTheNormalizedExternalIDs(t *testing.T) {
+	t.Log("Successfully create a User with external ids")
+
+	input := domain.UserCreateInput{
+		FirstName:   "Foo",
+		LastName:    "Bar",
+		Email:       "foobar@email.com",
+		ExternalIDs: []domain.UserExternalID{{Source: " ERP ", ID: " 42 "}, {Source: "crm", ID: "C-1"}, {Source: "erp", ID: "42"}},
+	}
+	repositoryMock := new(repositoryMock)
+	repositoryMock.On("FindByExternalID", "crm", "C-1").Return(domain.User{}, nil)
+	repositoryMock.On("FindByExternalID", "erp", "42").Return(domain.User{}, nil)
+	repositoryMock.On("Create", mock.MatchedBy(func(user domain.User) bool {
+		return assert.ObjectsAreEqual([]domain.UserExternalID{{Source: "crm", ID: "C-1"}, {Source: "erp", ID: "42"}}, user.ExternalIDs)
+	})).Return(domain.User{Email: "foobar@email.com"}, nil)
+	verifierMock := new(emailVerifierMock)
+	verifierMock.On("Send", mock.AnythingOfType("User")).Return(nil)
+	generatorMock := new(referenceGeneratorMock)
+	generatorMock.On("Generate").Return("USER1")
+
+	useCase := NewDefaultCreate(attributesConfig, repositoryMock, verifierMock, generatorMock)
+
+	_, err := useCase.Execute(input)
+
+	assert.Nil(t, err)
+	repositoryMock.AssertExpectations(t)
+}
+
+func TestCreate_GivenAnExternalIDOfAnotherUser_WhenExecute_ThenReturnAConflictError(t *testing.T) {
+	t.Log("Failure to create an User because an external id belongs to another user")
+
+	input := domain.UserCreateInput{
+		FirstName:   "Foo",
+		LastName:    "Bar",
+		Email:       "foobar@email.com",
+		ExternalIDs: []domain.UserExternalID{{Source: "erp", ID: "42"}},
+	}
+	repositoryMock := new(repositoryMock)
+	repositoryMock.On("FindByExternalID", "erp", "42").Return(domain.User{GenericEntity: domain.GenericEntity{Reference: "USER2"}}, nil)
+
+	useCase := NewDefaultCreate(attributesConfig, repositoryMock, new(emailVerifierMock), new(referenceGeneratorMock))
+
+	_, err := useCase.Execute(input)
+
+	assert.NotNil(t, err)
+	assert.Equal(t, "external id erp/42 belongs to another user", err.Error())
+	repositoryMock.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+func TestCreate_GivenAnExternalIDTakenWhileCreating_WhenExecute_ThenReturnAConflictError(t *testing.T) {
+	t.Log("Failure to create an User because the repository rejected a duplicated external id")
+
+	input := domain.UserCreateInput{
+		FirstName:   "Foo",
+		LastName:    "Bar",
+		Email:       "foobar@email.com",
+		ExternalIDs: []domain.UserExternalID{{Source: "erp", ID: "42"}},
+	}
+	repositoryMock := new(repositoryMock)
+	repositoryMock.On("FindByExternalID", "erp", "42").Return(domain.User{}, nil)
+	repositoryMock.On("Create", mock.AnythingOfType("User")).Return(domain.User{}, infrastructure.ErrDuplicateExternalID)
+	generatorMock := new(referenceGeneratorMock)
+	generatorMock.On("Generate").Return("USER1")
+
+	useCase := NewDefaultCreate(attributesConfig, repositoryMock, new(emailVerifierMock), generatorMock)
+
+	_, err := useCase.Execute(input)
+
+	assert.NotNil(t, err)
+	assert.Equal(t, "an external id belongs to another user", err.Error())
+	repositoryMock.AssertExpectations(t)
+}
+
+func TestCreate_GivenAnEmailTakenByAnotherUser_WhenExecute_ThenReturnAConflictError(t *testing.T) {
+	t.Log("Failure to create an User because the repository rejected the email of another active user")
+
+	input := domain.UserCreateInput{
+		FirstName: "Foo",
+		LastName:  "Bar",
+		Email:     "foobar@email.com",
+	}
+	repositoryMock := new(repositoryMock)
+	repositoryMock.On("Create", mock.AnythingOfType("User")).Return(domain.User{}, infrastructure.ErrDuplicateEmail)
+	generatorMock := new(referenceGeneratorMock)
+	generatorMock.On("Generate").Return("USER1")
+
+	useCase := NewDefaultCreate(attributesConfig, repositoryMock, new(emailVerifierMock), generatorMock)
+
+	_, err := useCase.Execute(input)
+
+	assert.NotNil(t, err)
+	assert.Equal(t, "the email belongs to another user", err.Error())
+	repositoryMock.AssertExpectations(t)
+}
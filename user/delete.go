@@ -17,37 +17,71 @@ type Delete interface {
 
 // defaultDelete is the default implementation of Delete interface
 type defaultDelete struct {
-	repository infrastructure.UserRepository
+	repository      infrastructure.UserRepository
+	groupRepository infrastructure.GroupRepository
 }
 
 // NewDefaultDelete creates a defaultDelete instance
-func NewDefaultDelete(repository infrastructure.UserRepository) defaultDelete {
+func NewDefaultDelete(repository infrastructure.UserRepository, groupRepository infrastructure.GroupRepository) defaultDelete {
 	return defaultDelete{
-		repository: repository,
+		repository:      repository,
+		groupRepository: groupRepository,
 	}
 }
 
-// Execute delete an User
+// Execute delete an User, release its external ids and remove it from its groups. A cleanup failure does not fail the
+// deletion: deleting an user that is already deleted returns not found, but removes its memberships again.
+// The merged users are deleted too, but keep their external ids to redirect to the user they were merged into
 func (s defaultDelete) Execute(reference string) (domain.User, error) {
-	currentUser, err := s.repository.FindActiveByReference(reference)
+	currentUser, err := s.repository.FindByReference(reference)
 	if err != nil {
 		errMsg := fmt.Sprintf("unexpected error when try to get user with reference %s", reference)
 		logger.AppLog.Error().Err(err).Msg(errMsg)
 		return domain.User{}, errors.NewFatalError(errMsg)
 	}
 	if len(currentUser.Reference) == 0 {
-		return domain.User{}, errors.NewNotFoundError("user not found")
+		return domain.User{}, errors.NewNotFoundError("user not found").WithKey("user_not_found")
+	}
+	if statusOf(currentUser) == domain.UserStatusDeleted {
+		s.releaseExternalIDs(currentUser)
+		removeMemberships(s.groupRepository, currentUser.Reference)
+		return domain.User{}, errors.NewNotFoundError("user not found").WithKey("user_not_found")
 	}
 
-	currentUser.IsActive = false
-	currentUser.UpdatedDate = time.Now().UTC()
+	previous := statusOf(currentUser)
+	if err := transition(&currentUser, domain.UserStatusDeleted, "", time.Now().UTC()); err != nil {
+		return domain.User{}, err
+	}
+	// The unique index keeps the external ids of the stored users, so the deleted users release them
+	currentUser.ExternalIDs = nil
 
-	deleted, err := s.repository.Update(currentUser)
+	deleted, err := s.repository.ChangeStatus(currentUser, previous)
 	if err != nil {
-		errMsg := "unexpected error when delete the user"
-		logger.AppLog.Error().Err(err).Msg(errMsg)
-		return domain.User{}, errors.NewFatalError(errMsg)
+		return domain.User{}, storeError(err, "unexpected error when delete the user")
 	}
 
+	removeMemberships(s.groupRepository, deleted.Reference)
+
 	return deleted, nil
 }
+
+// releaseExternalIDs removes the external ids kept by an user deleted before they were released.
+// A failure is logged, the deletion of the user is kept
+func (s defaultDelete) releaseExternalIDs(user domain.User) {
+	if len(user.ExternalIDs) == 0 || len(user.MergedInto) > 0 {
+		return
+	}
+
+	user.ExternalIDs = nil
+	if _, err := s.repository.Update(user); err != nil {
+		logger.AppLog.Error().Err(err).Str("reference", user.Reference).Msg("unable to release the user external ids")
+	}
+}
+
+// removeMemberships removes a deleted user from all its groups. A failure is logged, the deletion of the user is kept
+func removeMemberships(groupRepository infrastructure.GroupRepository, reference string) {
+	_, err := groupRepository.RemoveMemberFromAll(reference)
+	if err != nil {
+		logger.AppLog.Error().Err(err).Str("reference", reference).Msg("unable to remove the user from its groups")
+	}
+}
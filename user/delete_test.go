@@ -18,6 +18,7 @@ func TestDelete_GivenAReference_WhenExecute_ThenDeleteAnUser(t *testing.T) {
 			Reference: reference,
 			IsActive:  true,
 		},
+		ExternalIDs: []domain.UserExternalID{{Source: "crm", ID: "C-1"}},
 	}
 	deletedUser := domain.User{
 		GenericEntity: domain.GenericEntity{
@@ -26,10 +27,14 @@ func TestDelete_GivenAReference_WhenExecute_ThenDeleteAnUser(t *testing.T) {
 		},
 	}
 	repositoryMock := new(repositoryMock)
-	repositoryMock.On("FindActiveByReference", reference).Return(currentUser, nil)
-	repositoryMock.On("Update", mock.AnythingOfType("User")).Return(deletedUser, nil)
+	repositoryMock.On("FindByReference", reference).Return(currentUser, nil)
+	repositoryMock.On("ChangeStatus", mock.MatchedBy(func(user domain.User) bool {
+		return !user.IsActive && user.Status == domain.UserStatusDeleted && !user.StatusDate.IsZero() && user.ExternalIDs == nil
+	}), domain.UserStatusActive).Return(deletedUser, nil)
+	groupRepositoryMock := new(groupRepositoryMock)
+	groupRepositoryMock.On("RemoveMemberFromAll", reference).Return(int64(1), nil)
 
-	useCase := NewDefaultDelete(repositoryMock)
+	useCase := NewDefaultDelete(repositoryMock, groupRepositoryMock)
 
 	deleted, err := useCase.Execute(reference)
 
@@ -38,6 +43,7 @@ func TestDelete_GivenAReference_WhenExecute_ThenDeleteAnUser(t *testing.T) {
 	assert.Equal(t, deletedUser, deleted)
 
 	repositoryMock.AssertExpectations(t)
+	groupRepositoryMock.AssertExpectations(t)
 }
 
 func TestDelete_GivenAReference_WhenExecuteAndFindReturnedAnError_ThenReturnAnError(t *testing.T) {
@@ -45,9 +51,10 @@ func TestDelete_GivenAReference_WhenExecuteAndFindReturnedAnError_ThenReturnAnEr
 
 	reference := "REF1"
 	repositoryMock := new(repositoryMock)
-	repositoryMock.On("FindActiveByReference", reference).Return(domain.User{}, errors.New("repository error"))
+	repositoryMock.On("FindByReference", reference).Return(domain.User{}, errors.New("repository error"))
+	groupRepositoryMock := new(groupRepositoryMock)
 
-	useCase := NewDefaultDelete(repositoryMock)
+	useCase := NewDefaultDelete(repositoryMock, groupRepositoryMock)
 
 	_, err := useCase.Execute(reference)
 
@@ -62,9 +69,10 @@ func TestDelete_GivenAReference_WhenExecuteAndUserNotFound_ThenReturnAnError(t *
 
 	reference := "REF1"
 	repositoryMock := new(repositoryMock)
-	repositoryMock.On("FindActiveByReference", reference).Return(domain.User{}, nil)
+	repositoryMock.On("FindByReference", reference).Return(domain.User{}, nil)
+	groupRepositoryMock := new(groupRepositoryMock)
 
-	useCase := NewDefaultDelete(repositoryMock)
+	useCase := NewDefaultDelete(repositoryMock, groupRepositoryMock)
 
 	_, err := useCase.Execute(reference)
 
@@ -85,10 +93,11 @@ func TestDelete_GivenAReference_WhenExecuteAndUpdateReturnedAnError_ThenReturnAF
 		},
 	}
 	repositoryMock := new(repositoryMock)
-	repositoryMock.On("FindActiveByReference", reference).Return(currentUser, nil)
-	repositoryMock.On("Update", mock.AnythingOfType("User")).Return(domain.User{}, errors.New("repository error"))
+	repositoryMock.On("FindByReference", reference).Return(currentUser, nil)
+	repositoryMock.On("ChangeStatus", mock.AnythingOfType("User"), domain.UserStatusActive).Return(domain.User{}, errors.New("repository error"))
+	groupRepositoryMock := new(groupRepositoryMock)
 
-	useCase := NewDefaultDelete(repositoryMock)
+	useCase := NewDefaultDelete(repositoryMock, groupRepositoryMock)
 
 	_, err := useCase.Execute(reference)
 
@@ -97,3 +106,144 @@ func TestDelete_GivenAReference_WhenExecuteAndUpdateReturnedAnError_ThenReturnAF
 
 	repositoryMock.AssertExpectations(t)
 }
+
+func TestDelete_GivenADeletedUser_WhenExecute_ThenRemoveItsGroupsAndReturnANotFoundError(t *testing.T) {
+	t.Log("Failure to delete an User because it was already deleted, but its groups cleanup is retried")
+
+	reference := "REF1"
+	currentUser := domain.User{
+		GenericEntity: domain.GenericEntity{
+			Reference: reference,
+			IsActive:  false,
+		},
+		Status: domain.UserStatusDeleted,
+	}
+	repositoryMock := new(repositoryMock)
+	repositoryMock.On("FindByReference", reference).Return(currentUser, nil)
+	groupRepositoryMock := new(groupRepositoryMock)
+	groupRepositoryMock.On("RemoveMemberFromAll", reference).Return(int64(1), nil)
+
+	useCase := NewDefaultDelete(repositoryMock, groupRepositoryMock)
+
+	_, err := useCase.Execute(reference)
+
+	assert.NotNil(t, err)
+	assert.Equal(t, "user not found", err.Error())
+
+	repositoryMock.AssertExpectations(t)
+	groupRepositoryMock.AssertExpectations(t)
+	repositoryMock.AssertNotCalled(t, "Update", mock.Anything)
+}
+
+func TestDelete_GivenADeletedUserWithExternalIDs_WhenExecute_ThenReleaseTheExternalIDsAndReturnANotFoundError(t *testing.T) {
+	t.Log("Failure to delete an User because it was already deleted, but the external ids it kept are released")
+
+	reference := "REF1"
+	currentUser := domain.User{
+		GenericEntity: domain.GenericEntity{
+			Reference: reference,
+			IsActive:  false,
+		},
+		Status:      domain.UserStatusDeleted,
+		ExternalIDs: []domain.UserExternalID{{Source: "crm", ID: "C-1"}},
+	}
+	repositoryMock := new(repositoryMock)
+	repositoryMock.On("FindByReference", reference).Return(currentUser, nil)
+	repositoryMock.On("Update", mock.MatchedBy(func(user domain.User) bool {
+		return user.Reference == reference && user.Status == domain.UserStatusDeleted && user.ExternalIDs == nil
+	})).Return(currentUser, nil)
+	groupRepositoryMock := new(groupRepositoryMock)
+	groupRepositoryMock.On("RemoveMemberFromAll", reference).Return(int64(1), nil)
+
+	useCase := NewDefaultDelete(repositoryMock, groupRepositoryMock)
+
+	_, err := useCase.Execute(reference)
+
+	assert.NotNil(t, err)
+	assert.Equal(t, "user not found", err.Error())
+
+	repositoryMock.AssertExpectations(t)
+	groupRepositoryMock.AssertExpectations(t)
+}
+
+func TestDelete_GivenAMergedUser_WhenExecute_ThenKeepItsExternalIDsAndReturnANotFoundError(t *testing.T) {
+	t.Log("Failure to delete an User because it was merged, and its external ids are kept to find the user it was merged into")
+
+	reference := "REF1"
+	currentUser := domain.User{
+		GenericEntity: domain.GenericEntity{
+			Reference: reference,
+			IsActive:  false,
+		},
+		Status:      domain.UserStatusDeleted,
+		MergedInto:  "REF2",
+		ExternalIDs: []domain.UserExternalID{{Source: "crm", ID: "C-1"}},
+	}
+	repositoryMock := new(repositoryMock)
+	repositoryMock.On("FindByReference", reference).Return(currentUser, nil)
+	groupRepositoryMock := new(groupRepositoryMock)
+	groupRepositoryMock.On("RemoveMemberFromAll", reference).Return(int64(1), nil)
+
+	useCase := NewDefaultDelete(repositoryMock, groupRepositoryMock)
+
+	_, err := useCase.Execute(reference)
+
+	assert.NotNil(t, err)
+	assert.Equal(t, "user not found", err.Error())
+
+	repositoryMock.AssertNotCalled(t, "Update", mock.Anything)
+}
+
+func TestDelete_GivenASuspendedUser_WhenExecute_ThenDeleteAnUser(t *testing.T) {
+	t.Log("Successfully delete a suspended User")
+
+	reference := "REF1"
+	currentUser := domain.User{
+		GenericEntity: domain.GenericEntity{
+			Reference: reference,
+			IsActive:  false,
+		},
+		Status: domain.UserStatusSuspended,
+	}
+	repositoryMock := new(repositoryMock)
+	repositoryMock.On("FindByReference", reference).Return(currentUser, nil)
+	repositoryMock.On("ChangeStatus", mock.MatchedBy(func(user domain.User) bool {
+		return user.Status == domain.UserStatusDeleted
+	}), domain.UserStatusSuspended).Return(currentUser, nil)
+	groupRepositoryMock := new(groupRepositoryMock)
+	groupRepositoryMock.On("RemoveMemberFromAll", reference).Return(int64(1), nil)
+
+	useCase := NewDefaultDelete(repositoryMock, groupRepositoryMock)
+
+	_, err := useCase.Execute(reference)
+
+	assert.Nil(t, err)
+
+	repositoryMock.AssertExpectations(t)
+	groupRepositoryMock.AssertExpectations(t)
+}
+
+func TestDelete_GivenAReference_WhenExecuteAndGroupsCleanupFailed_ThenDeleteAnUser(t *testing.T) {
+	t.Log("Successfully delete an User even if it could not be removed from its groups")
+
+	reference := "REF1"
+	currentUser := domain.User{
+		GenericEntity: domain.GenericEntity{
+			Reference: reference,
+			IsActive:  true,
+		},
+	}
+	repositoryMock := new(repositoryMock)
+	repositoryMock.On("FindByReference", reference).Return(currentUser, nil)
+	repositoryMock.On("ChangeStatus", mock.AnythingOfType("User"), domain.UserStatusActive).Return(currentUser, nil)
+	groupRepositoryMock := new(groupRepositoryMock)
+	groupRepositoryMock.On("RemoveMemberFromAll", reference).Return(int64(0), errors.New("repository error"))
+
+	useCase := NewDefaultDelete(repositoryMock, groupRepositoryMock)
+
+	_, err := useCase.Execute(reference)
+
+	assert.Nil(t, err)
+
+	groupRepositoryMock.AssertExpectations(t)
+}
@@ -0,0 +1,105 @@
+package user
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/desarrollogj/golang-api-example/domain"
+	"github.com/desarrollogj/golang-api-example/libs/logger"
+)
+
+// MinDuplicateDetectionIntervalSeconds is the minimum time between two reports. Building the report compares
+// the names of every pair of users, so it is never built on each request
+const MinDuplicateDetectionIntervalSeconds = 300
+
+// DuplicateDetectionJob builds the duplicate users report in background, and serves the last report built.
+// It implements FindDuplicates, so it can replace the use case in the handlers
+type DuplicateDetectionJob struct {
+	config         domain.DuplicateDetectionConfiguration
+	findDuplicates FindDuplicates
+	mutex          sync.RWMutex
+	report         *domain.UserDuplicateReport
+}
+
+// NewDuplicateDetectionJob creates a new DuplicateDetectionJob. Intervals below the minimum, or without interval,
+// are raised to MinDuplicateDetectionIntervalSeconds
+func NewDuplicateDetectionJob(config domain.DuplicateDetectionConfiguration, findDuplicates FindDuplicates) *DuplicateDetectionJob {
+	if config.IntervalSeconds < MinDuplicateDetectionIntervalSeconds {
+		config.IntervalSeconds = MinDuplicateDetectionIntervalSeconds
+	}
+	return &DuplicateDetectionJob{
+		config:         config,
+		findDuplicates: findDuplicates,
+	}
+}
+
+// Run refreshes the report at the configured interval until the context is done
+func (j *DuplicateDetectionJob) Run(ctx context.Context) {
+	ticker := time.NewTicker(time.Duration(j.config.IntervalSeconds) * time.Second)
+	defer ticker.Stop()
+
+	for {
+		if _, err := j.refresh(); err == nil {
+			logger.AppLog.Info().Msg("duplicate users report refreshed")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Execute returns the last report built. The report is built when the job did not run yet
+func (j *DuplicateDetectionJob) Execute() (domain.UserDuplicateReport, error) {
+	j.mutex.RLock()
+	report := j.report
+	j.mutex.RUnlock()
+	if report != nil {
+		return *report, nil
+	}
+
+	return j.refresh()
+}
+
+func (j *DuplicateDetectionJob) Name() string {
+	return "duplicate users report"
+}
+
+// Purge removes an erased user from the last report, with the groups that are left with a single user
+func (j *DuplicateDetectionJob) Purge(user domain.User) error {
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+	if j.report == nil {
+		return nil
+	}
+
+	report := domain.UserDuplicateReport{GeneratedDate: j.report.GeneratedDate, Groups: []domain.UserDuplicateGroup{}}
+	for _, group := range j.report.Groups {
+		users := []domain.User{}
+		for _, groupUser := range group.Users {
+			if groupUser.Reference != user.Reference {
+				users = append(users, groupUser)
+			}
+		}
+		if len(users) > 1 {
+			report.Groups = append(report.Groups, domain.UserDuplicateGroup{Reasons: group.Reasons, Users: users})
+		}
+	}
+	j.report = &report
+	return nil
+}
+
+func (j *DuplicateDetectionJob) refresh() (domain.UserDuplicateReport, error) {
+	report, err := j.findDuplicates.Execute()
+	if err != nil {
+		return domain.UserDuplicateReport{}, err
+	}
+
+	j.mutex.Lock()
+	j.report = &report
+	j.mutex.Unlock()
+	return report, nil
+}
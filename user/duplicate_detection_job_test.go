@@ -0,0 +1,104 @@
+package user
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/desarrollogj/golang-api-example/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type findDuplicatesMock struct {
+	mock.Mock
+}
+
+func (m *findDuplicatesMock) Execute() (domain.UserDuplicateReport, error) {
+	args := m.Called()
+	return args.Get(0).(domain.UserDuplicateReport), args.Error(1)
+}
+
+func TestDuplicateDetectionJob_GivenARunningJob_WhenExecute_ThenReturnTheLastReport(t *testing.T) {
+	t.Log("Successfully get the report built in background")
+
+	report := domain.UserDuplicateReport{GeneratedDate: time.Now().UTC()}
+	findDuplicatesMock := new(findDuplicatesMock)
+	refreshed := make(chan struct{})
+	findDuplicatesMock.On("Execute").Return(report, nil).Once().Run(func(mock.Arguments) { close(refreshed) })
+
+	job := NewDuplicateDetectionJob(domain.DuplicateDetectionConfiguration{IntervalSeconds: 3600}, findDuplicatesMock)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		job.Run(ctx)
+		close(done)
+	}()
+	<-refreshed
+	cancel()
+	<-done
+
+	found, err := job.Execute()
+
+	assert.Nil(t, err)
+	assert.Equal(t, report, found)
+	findDuplicatesMock.AssertNumberOfCalls(t, "Execute", 1)
+}
+
+func TestDuplicateDetectionJob_GivenAJobThatDidNotRun_WhenExecute_ThenBuildTheReport(t *testing.T) {
+	t.Log("Successfully build the report when the job did not run")
+
+	report := domain.UserDuplicateReport{GeneratedDate: time.Now().UTC()}
+	findDuplicatesMock := new(findDuplicatesMock)
+	findDuplicatesMock.On("Execute").Return(report, nil).Once()
+
+	job := NewDuplicateDetectionJob(domain.DuplicateDetectionConfiguration{IntervalSeconds: 3600}, findDuplicatesMock)
+
+	first, err := job.Execute()
+	assert.Nil(t, err)
+	second, _ := job.Execute()
+
+	assert.Equal(t, report, first)
+	assert.Equal(t, report, second)
+	findDuplicatesMock.AssertNumberOfCalls(t, "Execute", 1)
+}
+
+func TestDuplicateDetectionJob_GivenAReportWithAnErasedUser_WhenPurge_ThenRemoveTheUserFromTheReport(t *testing.T) {
+	t.Log("Successfully remove an erased user from the last report")
+
+	first := domain.User{GenericEntity: domain.GenericEntity{Reference: "USER1"}, Email: "foo@email.com"}
+	second := domain.User{GenericEntity: domain.GenericEntity{Reference: "USER2"}, Email: "foo@email.com"}
+	third := domain.User{GenericEntity: domain.GenericEntity{Reference: "USER3"}, UserProfile: domain.UserProfile{Phone: "+5491112345678"}}
+	fourth := domain.User{GenericEntity: domain.GenericEntity{Reference: "USER4"}, UserProfile: domain.UserProfile{Phone: "+5491112345678"}}
+	fifth := domain.User{GenericEntity: domain.GenericEntity{Reference: "USER5"}, UserProfile: domain.UserProfile{Phone: "+5491112345678"}}
+	report := domain.UserDuplicateReport{
+		GeneratedDate: time.Now().UTC(),
+		Groups: []domain.UserDuplicateGroup{
+			{Reasons: []string{"email"}, Users: []domain.User{first, second}},
+			{Reasons: []string{"phone"}, Users: []domain.User{third, fourth, fifth}},
+		},
+	}
+	findDuplicatesMock := new(findDuplicatesMock)
+	findDuplicatesMock.On("Execute").Return(report, nil).Once()
+
+	job := NewDuplicateDetectionJob(domain.DuplicateDetectionConfiguration{IntervalSeconds: 3600}, findDuplicatesMock)
+	_, _ = job.Execute()
+
+	assert.Nil(t, job.Purge(first))
+	assert.Nil(t, job.Purge(fourth))
+	found, err := job.Execute()
+
+	assert.Nil(t, err)
+	assert.Equal(t, []domain.UserDuplicateGroup{{Reasons: []string{"phone"}, Users: []domain.User{third, fifth}}}, found.Groups)
+	findDuplicatesMock.AssertNumberOfCalls(t, "Execute", 1)
+}
+
+func TestNewDuplicateDetectionJob_GivenAnIntervalBelowTheMinimum_WhenCreate_ThenUseTheMinimumInterval(t *testing.T) {
+	t.Log("The report is never refreshed more often than the minimum interval")
+
+	for _, interval := range []int{0, 10} {
+		job := NewDuplicateDetectionJob(domain.DuplicateDetectionConfiguration{IntervalSeconds: interval}, new(findDuplicatesMock))
+
+		assert.Equal(t, MinDuplicateDetectionIntervalSeconds, job.config.IntervalSeconds)
+	}
+}
@@ -0,0 +1,95 @@
+package user
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/desarrollogj/golang-api-example/domain"
+	"github.com/desarrollogj/golang-api-example/libs/mail"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const emailVerificationAudience = "email-verification"
+
+// EmailVerifier represents the methods to be implemented to issue and check email verification tokens
+type EmailVerifier interface {
+	Send(user domain.User) error
+	Verify(token string) (EmailVerificationClaims, error)
+}
+
+// EmailVerificationClaims are the claims of an email verification token. The subject is the user reference
+type EmailVerificationClaims struct {
+	Email string `json:"email"`
+	jwt.RegisteredClaims
+}
+
+// defaultEmailVerifier is the default implementation of EmailVerifier interface.
+// Tokens are HMAC signed JWT, so they do not need to be stored
+type defaultEmailVerifier struct {
+	config domain.EmailVerificationConfiguration
+	mailer mail.Mailer
+}
+
+// NewDefaultEmailVerifier creates a defaultEmailVerifier instance
+func NewDefaultEmailVerifier(config domain.EmailVerificationConfiguration, mailer mail.Mailer) defaultEmailVerifier {
+	return defaultEmailVerifier{
+		config: config,
+		mailer: mailer,
+	}
+}
+
+// Send issues a new verification token for the user email and sends it by email
+func (v defaultEmailVerifier) Send(user domain.User) error {
+	token, err := v.issue(user, time.Now().UTC())
+	if err != nil {
+		return err
+	}
+
+	ttl := time.Duration(v.config.TokenTTLSeconds) * time.Second
+	return v.mailer.Send(mail.Message{
+		From:    v.config.From,
+		To:      user.Email,
+		Subject: "Confirm your email address",
+		Body: fmt.Sprintf("Hello %s,\n\nPlease confirm your email address using the following link:\n\n%s\n\nThe link expires in %s.\n",
+			user.FirstName, fmt.Sprintf(v.config.LinkFormat, token), ttl),
+	})
+}
+
+func (v defaultEmailVerifier) Name() string {
+	return "verification emails"
+}
+
+// Purge removes the copies kept of the verification emails sent to the user
+func (v defaultEmailVerifier) Purge(user domain.User) error {
+	return v.mailer.Purge(user.Email)
+}
+
+// Verify checks the token signature, audience and expiration, and returns its claims
+func (v defaultEmailVerifier) Verify(token string) (EmailVerificationClaims, error) {
+	claims := EmailVerificationClaims{}
+	_, err := jwt.ParseWithClaims(token, &claims, func(t *jwt.Token) (interface{}, error) {
+		return []byte(v.config.Secret), nil
+	},
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Name}),
+		jwt.WithAudience(emailVerificationAudience),
+		jwt.WithExpirationRequired())
+	if err != nil {
+		return EmailVerificationClaims{}, err
+	}
+
+	return claims, nil
+}
+
+func (v defaultEmailVerifier) issue(user domain.User, now time.Time) (string, error) {
+	claims := EmailVerificationClaims{
+		Email: user.Email,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   user.Reference,
+			Audience:  jwt.ClaimStrings{emailVerificationAudience},
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(time.Duration(v.config.TokenTTLSeconds) * time.Second)),
+		},
+	}
+
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(v.config.Secret))
+}
@@ -0,0 +1,112 @@
+package user
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/desarrollogj/golang-api-example/domain"
+	"github.com/desarrollogj/golang-api-example/libs/mail"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newEmailVerificationConfigurationMock() domain.EmailVerificationConfiguration {
+	return domain.EmailVerificationConfiguration{
+		Secret:                "secret",
+		From:                  "no-reply@example.com",
+		LinkFormat:            "http://localhost/verify-email?token=%s",
+		TokenTTLSeconds:       3600,
+		ResendIntervalSeconds: 60,
+	}
+}
+
+func TestEmailVerifier_GivenAnUser_WhenSend_ThenSendAVerifiableToken(t *testing.T) {
+	t.Log("Successfully send a verification token that can be verified")
+
+	user := domain.User{
+		GenericEntity: domain.GenericEntity{Reference: "USER1"},
+		FirstName:     "Foo",
+		Email:         "foobar@email.com",
+	}
+	var sent mail.Message
+	mailerMock := new(mailerMock)
+	mailerMock.On("Send", mock.MatchedBy(func(message mail.Message) bool {
+		sent = message
+		return true
+	})).Return(nil)
+
+	verifier := NewDefaultEmailVerifier(newEmailVerificationConfigurationMock(), mailerMock)
+
+	err := verifier.Send(user)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "no-reply@example.com", sent.From)
+	assert.Equal(t, "foobar@email.com", sent.To)
+
+	prefix := "http://localhost/verify-email?token="
+	start := strings.Index(sent.Body, prefix)
+	assert.True(t, start >= 0)
+	token := strings.Fields(sent.Body[start+len(prefix):])[0]
+
+	claims, err := verifier.Verify(token)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "USER1", claims.Subject)
+	assert.Equal(t, "foobar@email.com", claims.Email)
+
+	mailerMock.AssertExpectations(t)
+}
+
+func TestEmailVerifier_GivenAnUser_WhenSendAndMailerFailed_ThenReturnAnError(t *testing.T) {
+	t.Log("Failure to send a verification token because the mailer returned an error")
+
+	mailerMock := new(mailerMock)
+	mailerMock.On("Send", mock.AnythingOfType("Message")).Return(errors.New("mailer error"))
+
+	verifier := NewDefaultEmailVerifier(newEmailVerificationConfigurationMock(), mailerMock)
+
+	err := verifier.Send(domain.User{GenericEntity: domain.GenericEntity{Reference: "USER1"}})
+
+	assert.NotNil(t, err)
+}
+
+func TestEmailVerifier_GivenAnExpiredToken_WhenVerify_ThenReturnAnError(t *testing.T) {
+	t.Log("Failure to verify a token because it is expired")
+
+	verifier := NewDefaultEmailVerifier(newEmailVerificationConfigurationMock(), new(mailerMock))
+	token, _ := verifier.issue(domain.User{GenericEntity: domain.GenericEntity{Reference: "USER1"}}, time.Now().Add(-2*time.Hour))
+
+	_, err := verifier.Verify(token)
+
+	assert.NotNil(t, err)
+}
+
+func TestEmailVerifier_GivenATokenSignedWithOtherSecret_WhenVerify_ThenReturnAnError(t *testing.T) {
+	t.Log("Failure to verify a token because its signature is not valid")
+
+	config := newEmailVerificationConfigurationMock()
+	config.Secret = "other"
+	other := NewDefaultEmailVerifier(config, new(mailerMock))
+	token, _ := other.issue(domain.User{GenericEntity: domain.GenericEntity{Reference: "USER1"}}, time.Now())
+
+	verifier := NewDefaultEmailVerifier(newEmailVerificationConfigurationMock(), new(mailerMock))
+	_, err := verifier.Verify(token)
+
+	assert.NotNil(t, err)
+}
+
+func TestEmailVerifier_GivenAnErasedUser_WhenPurge_ThenPurgeTheEmailsSentToTheUser(t *testing.T) {
+	t.Log("Successfully purge the verification emails sent to an erased user")
+
+	mailerMock := new(mailerMock)
+	mailerMock.On("Purge", "foobar@email.com").Return(nil)
+
+	verifier := NewDefaultEmailVerifier(newEmailVerificationConfigurationMock(), mailerMock)
+
+	err := verifier.Purge(domain.User{GenericEntity: domain.GenericEntity{Reference: "USER1"}, Email: "foobar@email.com"})
+
+	assert.Nil(t, err)
+	mailerMock.AssertExpectations(t)
+}
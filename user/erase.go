@@ -0,0 +1,190 @@
+package user
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/desarrollogj/golang-api-example/domain"
+	"github.com/desarrollogj/golang-api-example/infrastructure"
+	"github.com/desarrollogj/golang-api-example/libs/blob"
+	"github.com/desarrollogj/golang-api-example/libs/errors"
+	"github.com/desarrollogj/golang-api-example/libs/logger"
+	"github.com/google/uuid"
+)
+
+const (
+	ErasedFirstName   = "erased"
+	ErasedLastName    = "erased"
+	ErasedEmailFormat = "erased-%s@erased.invalid"
+)
+
+// Erase represents the method to be implemented to erase (anonymize) an user
+type Erase interface {
+	Execute(reference string) (domain.User, error)
+}
+
+// ErasurePurger represents a holder of user personal data outside the repositories, like a cache or the copies of the sent emails.
+// Each purger forgets the user when it is erased
+type ErasurePurger interface {
+	Name() string
+	Purge(user domain.User) error
+}
+
+// defaultErase is the default implementation of Erase interface
+type defaultErase struct {
+	repository            infrastructure.UserRepository
+	groupRepository       infrastructure.GroupRepository
+	auditRepository       infrastructure.AuditRepository
+	credentialRepository  infrastructure.CredentialRepository
+	idempotencyRepository infrastructure.IdempotencyRepository
+	avatarStore           blob.BlobStore
+	purgers               []ErasurePurger
+}
+
+// NewDefaultErase creates a defaultErase instance
+func NewDefaultErase(repository infrastructure.UserRepository,
+	groupRepository infrastructure.GroupRepository,
+	auditRepository infrastructure.AuditRepository,
+	credentialRepository infrastructure.CredentialRepository,
+	idempotencyRepository infrastructure.IdempotencyRepository,
+	avatarStore blob.BlobStore,
+	purgers ...ErasurePurger) defaultErase {
+	return defaultErase{
+		repository:            repository,
+		groupRepository:       groupRepository,
+		auditRepository:       auditRepository,
+		credentialRepository:  credentialRepository,
+		idempotencyRepository: idempotencyRepository,
+		avatarStore:           avatarStore,
+		purgers:               purgers,
+	}
+}
+
+// Execute irreversibly replaces the user personal data with tombstone values.
+// The reference and dates are kept, so other records can still point to the user. The user credentials, avatar,
+// group memberships and stored idempotent responses are removed, and the purgers forget the user. Erasing an erased user registers its erasure, if a previous erasure failed to do it
+func (s defaultErase) Execute(reference string) (domain.User, error) {
+	currentUser, err := s.repository.FindByReference(reference)
+	if err != nil {
+		errMsg := fmt.Sprintf("unexpected error when try to get user with reference %s", reference)
+		logger.AppLog.Error().Err(err).Msg(errMsg)
+		return domain.User{}, errors.NewFatalError(errMsg)
+	}
+	if len(currentUser.Reference) == 0 {
+		return domain.User{}, errors.NewNotFoundError("user not found").WithKey("user_not_found")
+	}
+	if currentUser.ErasedDate != nil {
+		// A previous erasure could fail after the user was erased, before it was audited
+		if err := s.auditErasure(currentUser); err != nil {
+			return domain.User{}, err
+		}
+		return currentUser, nil
+	}
+
+	err = s.credentialRepository.DeleteByUserReference(currentUser.Reference)
+	if err != nil {
+		errMsg := "unexpected error when delete the user credentials"
+		logger.AppLog.Error().Err(err).Msg(errMsg)
+		return domain.User{}, errors.NewFatalError(errMsg)
+	}
+
+	// An erased user is deleted, so it must not remain in any group
+	_, err = s.groupRepository.RemoveMemberFromAll(currentUser.Reference)
+	if err != nil {
+		errMsg := "unexpected error when remove the user from its groups"
+		logger.AppLog.Error().Err(err).Msg(errMsg)
+		return domain.User{}, errors.NewFatalError(errMsg)
+	}
+
+	// The stored responses have the user personal data, they must not be replayed after the erasure
+	_, err = s.idempotencyRepository.DeleteByResource(currentUser.Reference)
+	if err != nil {
+		errMsg := "unexpected error when delete the user idempotent responses"
+		logger.AppLog.Error().Err(err).Msg(errMsg)
+		return domain.User{}, errors.NewFatalError(errMsg)
+	}
+
+	if currentUser.Avatar != nil {
+		err = deleteAvatar(s.avatarStore, currentUser.Reference, currentUser.Avatar)
+		if err != nil {
+			errMsg := "unexpected error when delete the user avatar"
+			logger.AppLog.Error().Err(err).Msg(errMsg)
+			return domain.User{}, errors.NewFatalError(errMsg)
+		}
+	}
+
+	// The purgers get the user before its personal data is replaced, like its email
+	for _, purger := range s.purgers {
+		if err := purger.Purge(currentUser); err != nil {
+			errMsg := fmt.Sprintf("unexpected error when purge the user %s", purger.Name())
+			logger.AppLog.Error().Err(err).Msg(errMsg)
+			return domain.User{}, errors.NewFatalError(errMsg)
+		}
+	}
+
+	erased := time.Now().UTC()
+	currentUser.FirstName = ErasedFirstName
+	currentUser.LastName = ErasedLastName
+	currentUser.Email = fmt.Sprintf(ErasedEmailFormat, currentUser.Reference)
+	currentUser.UserProfile = domain.UserProfile{}
+	currentUser.Attributes = nil
+	currentUser.Tags = nil
+	currentUser.ExternalIDs = nil
+	currentUser.Avatar = nil
+	// An erased user is deleted, whatever its previous status was
+	currentUser.IsActive = false
+	currentUser.Status = domain.UserStatusDeleted
+	currentUser.StatusReason = "erased"
+	currentUser.StatusDate = erased
+	currentUser.UpdatedDate = erased
+	currentUser.ErasedDate = &erased
+	// Every personal data field was replaced, so an user that could not be decrypted can be stored
+	currentUser.Undecryptable = false
+
+	updated, err := s.repository.Update(currentUser)
+	if err != nil {
+		errMsg := "unexpected error when erase the user"
+		logger.AppLog.Error().Err(err).Msg(errMsg)
+		return domain.User{}, errors.NewFatalError(errMsg)
+	}
+
+	if err := s.registerErasure(updated.Reference, erased); err != nil {
+		return domain.User{}, err
+	}
+
+	return updated, nil
+}
+
+// auditErasure registers the erasure of an erased user, unless it is already registered
+func (s defaultErase) auditErasure(user domain.User) error {
+	entries, err := s.auditRepository.FindByEntity(domain.AuditEntityUser, user.Reference)
+	if err != nil {
+		errMsg := "unexpected error when find the user erasure"
+		logger.AppLog.Error().Err(err).Msg(errMsg)
+		return errors.NewFatalError(errMsg)
+	}
+	for _, entry := range entries {
+		if entry.Action == domain.AuditActionUserErase {
+			return nil
+		}
+	}
+
+	return s.registerErasure(user.Reference, *user.ErasedDate)
+}
+
+func (s defaultErase) registerErasure(reference string, erased time.Time) error {
+	_, err := s.auditRepository.Create(domain.AuditEntry{
+		Reference:       uuid.NewString(),
+		Action:          domain.AuditActionUserErase,
+		EntityType:      domain.AuditEntityUser,
+		EntityReference: reference,
+		CreatedDate:     erased,
+	})
+	if err != nil {
+		errMsg := "unexpected error when register the user erasure"
+		logger.AppLog.Error().Err(err).Msg(errMsg)
+		return errors.NewFatalError(errMsg)
+	}
+
+	return nil
+}
@@ -0,0 +1,465 @@
+package user
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/desarrollogj/golang-api-example/domain"
+	"github.com/desarrollogj/golang-api-example/libs/blob"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestErase_GivenAnUser_WhenExecute_ThenEraseTheUser(t *testing.T) {
+	t.Log("Successfully erase an User")
+
+	reference := "REF1"
+	created := time.Now().UTC().Add(-time.Hour)
+	currentUser := domain.User{
+		GenericEntity: domain.GenericEntity{
+			Reference:   reference,
+			IsActive:    true,
+			CreatedDate: created,
+			UpdatedDate: created,
+		},
+		UserProfile: domain.UserProfile{
+			Phone:   "+5491112345678",
+			Address: &domain.Address{Line1: "Street 123", City: "Buenos Aires", Country: "AR"},
+		},
+		FirstName:  "Foo",
+		LastName:   "Bar",
+		Email:      "foobar@email.com",
+		Attributes: map[string]interface{}{"plan": "pro"},
+		Tags:       []string{"vip"},
+	}
+	erasedDate := time.Now().UTC()
+	erasedUser := domain.User{
+		GenericEntity: domain.GenericEntity{
+			Reference:   reference,
+			IsActive:    false,
+			CreatedDate: created,
+			UpdatedDate: erasedDate,
+		},
+		FirstName:  "erased",
+		LastName:   "erased",
+		Email:      "erased-REF1@erased.invalid",
+		ErasedDate: &erasedDate,
+	}
+	repositoryMock := new(repositoryMock)
+	repositoryMock.On("FindByReference", reference).Return(currentUser, nil)
+	repositoryMock.On("Update", mock.MatchedBy(func(user domain.User) bool {
+		return user.Reference == reference &&
+			user.FirstName == "erased" &&
+			user.LastName == "erased" &&
+			user.Email == "erased-REF1@erased.invalid" &&
+			user.UserProfile == domain.UserProfile{} &&
+			user.Attributes == nil &&
+			user.Tags == nil &&
+			!user.IsActive &&
+			user.Status == domain.UserStatusDeleted &&
+			user.CreatedDate == created &&
+			user.ErasedDate != nil
+	})).Return(erasedUser, nil)
+	auditRepositoryMock := new(auditRepositoryMock)
+	auditRepositoryMock.On("Create", mock.MatchedBy(func(entry domain.AuditEntry) bool {
+		return entry.Action == domain.AuditActionUserErase &&
+			entry.EntityType == domain.AuditEntityUser &&
+			entry.EntityReference == reference
+	})).Return(domain.AuditEntry{}, nil)
+
+	credentialRepositoryMock := new(credentialRepositoryMock)
+	credentialRepositoryMock.On("DeleteByUserReference", reference).Return(nil)
+
+	groupRepositoryMock := new(groupRepositoryMock)
+	groupRepositoryMock.On("RemoveMemberFromAll", reference).Return(int64(1), nil)
+	idempotencyRepositoryMock := new(idempotencyRepositoryMock)
+	idempotencyRepositoryMock.On("DeleteByResource", reference).Return(int64(1), nil)
+	purgerMock := new(erasurePurgerMock)
+	purgerMock.On("Purge", currentUser).Return(nil)
+
+	useCase := NewDefaultErase(repositoryMock, groupRepositoryMock, auditRepositoryMock, credentialRepositoryMock, idempotencyRepositoryMock, blob.NewLocalBlobStore(t.TempDir()), purgerMock)
+
+	erased, err := useCase.Execute(reference)
+
+	assert.Nil(t, err)
+	assert.Equal(t, erasedUser, erased)
+
+	repositoryMock.AssertExpectations(t)
+	groupRepositoryMock.AssertExpectations(t)
+	auditRepositoryMock.AssertExpectations(t)
+	credentialRepositoryMock.AssertExpectations(t)
+	idempotencyRepositoryMock.AssertExpectations(t)
+	purgerMock.AssertExpectations(t)
+}
+
+func TestErase_GivenAnUndecryptableUser_WhenExecute_ThenEraseTheUser(t *testing.T) {
+	t.Log("Successfully erase an User whose personal data can not be decrypted, since all of it is replaced")
+
+	reference := "REF1"
+	currentUser := domain.User{
+		GenericEntity: domain.GenericEntity{Reference: reference, IsActive: true},
+		Undecryptable: true,
+	}
+	repositoryMock := new(repositoryMock)
+	repositoryMock.On("FindByReference", reference).Return(currentUser, nil)
+	repositoryMock.On("Update", mock.MatchedBy(func(user domain.User) bool {
+		return user.ErasedDate != nil && user.FirstName == "erased" && !user.Undecryptable
+	})).Return(currentUser, nil)
+	auditRepositoryMock := new(auditRepositoryMock)
+	auditRepositoryMock.On("Create", mock.AnythingOfType("AuditEntry")).Return(domain.AuditEntry{}, nil)
+	credentialRepositoryMock := new(credentialRepositoryMock)
+	credentialRepositoryMock.On("DeleteByUserReference", reference).Return(nil)
+	groupRepositoryMock := new(groupRepositoryMock)
+	groupRepositoryMock.On("RemoveMemberFromAll", reference).Return(int64(1), nil)
+	idempotencyRepositoryMock := new(idempotencyRepositoryMock)
+	idempotencyRepositoryMock.On("DeleteByResource", reference).Return(int64(1), nil)
+
+	useCase := NewDefaultErase(repositoryMock, groupRepositoryMock, auditRepositoryMock, credentialRepositoryMock, idempotencyRepositoryMock, blob.NewLocalBlobStore(t.TempDir()))
+
+	_, err := useCase.Execute(reference)
+
+	assert.Nil(t, err)
+
+	repositoryMock.AssertExpectations(t)
+}
+
+func TestErase_GivenAnErasedUser_WhenExecute_ThenReturnTheUserWithoutChanges(t *testing.T) {
+	t.Log("Successfully erase an User that was already erased")
+
+	reference := "REF1"
+	erasedDate := time.Now().UTC()
+	currentUser := domain.User{
+		GenericEntity: domain.GenericEntity{
+			Reference: reference,
+		},
+		FirstName:  "erased",
+		LastName:   "erased",
+		Email:      "erased-REF1@erased.invalid",
+		ErasedDate: &erasedDate,
+	}
+	repositoryMock := new(repositoryMock)
+	repositoryMock.On("FindByReference", reference).Return(currentUser, nil)
+	auditRepositoryMock := new(auditRepositoryMock)
+	auditRepositoryMock.On("FindByEntity", domain.AuditEntityUser, reference).Return([]domain.AuditEntry{
+		{Reference: "AUDIT1", Action: domain.AuditActionUserErase, EntityType: domain.AuditEntityUser, EntityReference: reference},
+	}, nil)
+	groupRepositoryMock := new(groupRepositoryMock)
+
+	useCase := NewDefaultErase(repositoryMock, groupRepositoryMock, auditRepositoryMock, new(credentialRepositoryMock), new(idempotencyRepositoryMock), blob.NewLocalBlobStore(t.TempDir()))
+
+	erased, err := useCase.Execute(reference)
+
+	assert.Nil(t, err)
+	assert.Equal(t, currentUser, erased)
+
+	repositoryMock.AssertExpectations(t)
+	repositoryMock.AssertNotCalled(t, "Update", mock.Anything)
+	auditRepositoryMock.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+func TestErase_GivenAnErasedUserWithoutAudit_WhenExecute_ThenRegisterTheErasure(t *testing.T) {
+	t.Log("Successfully register the erasure of an User erased by a previous erasure that failed to audit it")
+
+	reference := "REF1"
+	erasedDate := time.Now().UTC()
+	currentUser := domain.User{
+		GenericEntity: domain.GenericEntity{
+			Reference: reference,
+		},
+		FirstName:  "erased",
+		LastName:   "erased",
+		Email:      "erased-REF1@erased.invalid",
+		ErasedDate: &erasedDate,
+	}
+	repositoryMock := new(repositoryMock)
+	repositoryMock.On("FindByReference", reference).Return(currentUser, nil)
+	auditRepositoryMock := new(auditRepositoryMock)
+	auditRepositoryMock.On("FindByEntity", domain.AuditEntityUser, reference).Return([]domain.AuditEntry{
+		{Reference: "AUDIT1", Action: domain.AuditActionUserMerge, EntityType: domain.AuditEntityUser, EntityReference: reference},
+	}, nil)
+	auditRepositoryMock.On("Create", mock.MatchedBy(func(entry domain.AuditEntry) bool {
+		return entry.Action == domain.AuditActionUserErase &&
+			entry.EntityReference == reference &&
+			entry.CreatedDate == erasedDate
+	})).Return(domain.AuditEntry{}, nil)
+	groupRepositoryMock := new(groupRepositoryMock)
+
+	useCase := NewDefaultErase(repositoryMock, groupRepositoryMock, auditRepositoryMock, new(credentialRepositoryMock), new(idempotencyRepositoryMock), blob.NewLocalBlobStore(t.TempDir()))
+
+	erased, err := useCase.Execute(reference)
+
+	assert.Nil(t, err)
+	assert.Equal(t, currentUser, erased)
+
+	repositoryMock.AssertNotCalled(t, "Update", mock.Anything)
+	auditRepositoryMock.AssertExpectations(t)
+}
+
+func TestErase_GivenAnUser_WhenExecuteAndUserNotFound_ThenReturnANotFoundError(t *testing.T) {
+	t.Log("Failure to erase an User because it was not found")
+
+	reference := "REF1"
+	repositoryMock := new(repositoryMock)
+	repositoryMock.On("FindByReference", reference).Return(domain.User{}, nil)
+	auditRepositoryMock := new(auditRepositoryMock)
+	groupRepositoryMock := new(groupRepositoryMock)
+
+	useCase := NewDefaultErase(repositoryMock, groupRepositoryMock, auditRepositoryMock, new(credentialRepositoryMock), new(idempotencyRepositoryMock), blob.NewLocalBlobStore(t.TempDir()))
+
+	_, err := useCase.Execute(reference)
+
+	assert.NotNil(t, err)
+	assert.Equal(t, "user not found", err.Error())
+
+	repositoryMock.AssertExpectations(t)
+}
+
+func TestErase_GivenAnUser_WhenExecuteAndFindReturnedAnError_ThenReturnAFatalError(t *testing.T) {
+	t.Log("Failure to erase an User because find returned an unexpected error")
+
+	reference := "REF1"
+	repositoryMock := new(repositoryMock)
+	repositoryMock.On("FindByReference", reference).Return(domain.User{}, errors.New("repository error"))
+	auditRepositoryMock := new(auditRepositoryMock)
+	groupRepositoryMock := new(groupRepositoryMock)
+
+	useCase := NewDefaultErase(repositoryMock, groupRepositoryMock, auditRepositoryMock, new(credentialRepositoryMock), new(idempotencyRepositoryMock), blob.NewLocalBlobStore(t.TempDir()))
+
+	_, err := useCase.Execute(reference)
+
+	assert.NotNil(t, err)
+	assert.Equal(t, "unexpected error when try to get user with reference REF1", err.Error())
+
+	repositoryMock.AssertExpectations(t)
+}
+
+func TestErase_GivenAnUser_WhenExecuteAndUpdateReturnedAnError_ThenReturnAFatalError(t *testing.T) {
+	t.Log("Failure to erase an User because update returned an unexpected error")
+
+	reference := "REF1"
+	currentUser := domain.User{
+		GenericEntity: domain.GenericEntity{
+			Reference: reference,
+			IsActive:  true,
+		},
+		FirstName: "Foo",
+		LastName:  "Bar",
+		Email:     "foobar@email.com",
+	}
+	repositoryMock := new(repositoryMock)
+	repositoryMock.On("FindByReference", reference).Return(currentUser, nil)
+	repositoryMock.On("Update", mock.AnythingOfType("User")).Return(domain.User{}, errors.New("repository error"))
+	auditRepositoryMock := new(auditRepositoryMock)
+
+	credentialRepositoryMock := new(credentialRepositoryMock)
+	credentialRepositoryMock.On("DeleteByUserReference", reference).Return(nil)
+
+	groupRepositoryMock := new(groupRepositoryMock)
+	groupRepositoryMock.On("RemoveMemberFromAll", reference).Return(int64(1), nil)
+	idempotencyRepositoryMock := new(idempotencyRepositoryMock)
+	idempotencyRepositoryMock.On("DeleteByResource", reference).Return(int64(1), nil)
+
+	useCase := NewDefaultErase(repositoryMock, groupRepositoryMock, auditRepositoryMock, credentialRepositoryMock, idempotencyRepositoryMock, blob.NewLocalBlobStore(t.TempDir()))
+
+	_, err := useCase.Execute(reference)
+
+	assert.NotNil(t, err)
+	assert.Equal(t, "unexpected error when erase the user", err.Error())
+
+	repositoryMock.AssertExpectations(t)
+	auditRepositoryMock.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+func TestErase_GivenAnUser_WhenExecuteAndAuditReturnedAnError_ThenReturnAFatalError(t *testing.T) {
+	t.Log("Failure to erase an User because the audit entry could not be created")
+
+	reference := "REF1"
+	currentUser := domain.User{
+		GenericEntity: domain.GenericEntity{
+			Reference: reference,
+			IsActive:  true,
+		},
+		FirstName: "Foo",
+		LastName:  "Bar",
+		Email:     "foobar@email.com",
+	}
+	repositoryMock := new(repositoryMock)
+	repositoryMock.On("FindByReference", reference).Return(currentUser, nil)
+	repositoryMock.On("Update", mock.AnythingOfType("User")).Return(currentUser, nil)
+	auditRepositoryMock := new(auditRepositoryMock)
+	auditRepositoryMock.On("Create", mock.AnythingOfType("AuditEntry")).Return(domain.AuditEntry{}, errors.New("repository error"))
+
+	credentialRepositoryMock := new(credentialRepositoryMock)
+	credentialRepositoryMock.On("DeleteByUserReference", reference).Return(nil)
+
+	groupRepositoryMock := new(groupRepositoryMock)
+	groupRepositoryMock.On("RemoveMemberFromAll", reference).Return(int64(1), nil)
+	idempotencyRepositoryMock := new(idempotencyRepositoryMock)
+	idempotencyRepositoryMock.On("DeleteByResource", reference).Return(int64(1), nil)
+
+	useCase := NewDefaultErase(repositoryMock, groupRepositoryMock, auditRepositoryMock, credentialRepositoryMock, idempotencyRepositoryMock, blob.NewLocalBlobStore(t.TempDir()))
+
+	_, err := useCase.Execute(reference)
+
+	assert.NotNil(t, err)
+	assert.Equal(t, "unexpected error when register the user erasure", err.Error())
+
+	repositoryMock.AssertExpectations(t)
+	auditRepositoryMock.AssertExpectations(t)
+}
+
+func TestErase_GivenAnUser_WhenExecuteAndCredentialDeleteReturnedAnError_ThenReturnAFatalError(t *testing.T) {
+	t.Log("Failure to erase an User because the credentials could not be deleted")
+
+	reference := "REF1"
+	currentUser := domain.User{
+		GenericEntity: domain.GenericEntity{
+			Reference: reference,
+			IsActive:  true,
+		},
+		FirstName: "Foo",
+		LastName:  "Bar",
+		Email:     "foobar@email.com",
+	}
+	repositoryMock := new(repositoryMock)
+	repositoryMock.On("FindByReference", reference).Return(currentUser, nil)
+	auditRepositoryMock := new(auditRepositoryMock)
+	credentialRepositoryMock := new(credentialRepositoryMock)
+	credentialRepositoryMock.On("DeleteByUserReference", reference).Return(errors.New("repository error"))
+
+	groupRepositoryMock := new(groupRepositoryMock)
+
+	useCase := NewDefaultErase(repositoryMock, groupRepositoryMock, auditRepositoryMock, credentialRepositoryMock, new(idempotencyRepositoryMock), blob.NewLocalBlobStore(t.TempDir()))
+
+	_, err := useCase.Execute(reference)
+
+	assert.NotNil(t, err)
+	assert.Equal(t, "unexpected error when delete the user credentials", err.Error())
+
+	repositoryMock.AssertNotCalled(t, "Update", mock.Anything)
+}
+
+func TestErase_GivenAnUser_WhenExecuteAndGroupsCleanupReturnedAnError_ThenReturnAFatalError(t *testing.T) {
+	t.Log("Failure to erase an User because it could not be removed from its groups")
+
+	reference := "REF1"
+	currentUser := domain.User{
+		GenericEntity: domain.GenericEntity{
+			Reference: reference,
+			IsActive:  true,
+		},
+		FirstName: "Foo",
+		LastName:  "Bar",
+		Email:     "foobar@email.com",
+	}
+	repositoryMock := new(repositoryMock)
+	repositoryMock.On("FindByReference", reference).Return(currentUser, nil)
+	credentialRepositoryMock := new(credentialRepositoryMock)
+	credentialRepositoryMock.On("DeleteByUserReference", reference).Return(nil)
+	groupRepositoryMock := new(groupRepositoryMock)
+	groupRepositoryMock.On("RemoveMemberFromAll", reference).Return(int64(0), errors.New("repository error"))
+
+	useCase := NewDefaultErase(repositoryMock, groupRepositoryMock, new(auditRepositoryMock), credentialRepositoryMock, new(idempotencyRepositoryMock), blob.NewLocalBlobStore(t.TempDir()))
+
+	_, err := useCase.Execute(reference)
+
+	assert.NotNil(t, err)
+	assert.Equal(t, "unexpected error when remove the user from its groups", err.Error())
+
+	repositoryMock.AssertNotCalled(t, "Update", mock.Anything)
+}
+
+func TestErase_GivenAnUser_WhenExecuteAndIdempotencyCleanupReturnedAnError_ThenReturnAFatalError(t *testing.T) {
+	t.Log("Failure to erase an User because its stored idempotent responses could not be deleted")
+
+	reference := "REF1"
+	repositoryMock := new(repositoryMock)
+	repositoryMock.On("FindByReference", reference).Return(domain.User{GenericEntity: domain.GenericEntity{Reference: reference, IsActive: true}}, nil)
+	credentialRepositoryMock := new(credentialRepositoryMock)
+	credentialRepositoryMock.On("DeleteByUserReference", reference).Return(nil)
+	groupRepositoryMock := new(groupRepositoryMock)
+	groupRepositoryMock.On("RemoveMemberFromAll", reference).Return(int64(1), nil)
+	idempotencyRepositoryMock := new(idempotencyRepositoryMock)
+	idempotencyRepositoryMock.On("DeleteByResource", reference).Return(int64(0), errors.New("repository error"))
+
+	useCase := NewDefaultErase(repositoryMock, groupRepositoryMock, new(auditRepositoryMock), credentialRepositoryMock, idempotencyRepositoryMock, blob.NewLocalBlobStore(t.TempDir()))
+
+	_, err := useCase.Execute(reference)
+
+	assert.NotNil(t, err)
+	assert.Equal(t, "unexpected error when delete the user idempotent responses", err.Error())
+
+	repositoryMock.AssertNotCalled(t, "Update", mock.Anything)
+}
+
+func TestErase_GivenAnUserWithAvatar_WhenExecute_ThenDeleteTheAvatar(t *testing.T) {
+	t.Log("Successfully erase an User and its avatar")
+
+	reference := "REF1"
+	avatar := &domain.UserAvatar{Version: "V1", ContentType: "image/png", Sizes: []int{64}}
+	currentUser := domain.User{
+		GenericEntity: domain.GenericEntity{
+			Reference: reference,
+			IsActive:  true,
+		},
+		FirstName: "Foo",
+		LastName:  "Bar",
+		Email:     "foobar@email.com",
+		Avatar:    avatar,
+	}
+	store := blob.NewLocalBlobStore(t.TempDir())
+	assert.Nil(t, store.Put(avatarKey(reference, "V1", 64), []byte("image")))
+
+	repositoryMock := new(repositoryMock)
+	repositoryMock.On("FindByReference", reference).Return(currentUser, nil)
+	repositoryMock.On("Update", mock.MatchedBy(func(user domain.User) bool {
+		return user.Avatar == nil
+	})).Return(currentUser, nil)
+	auditRepositoryMock := new(auditRepositoryMock)
+	auditRepositoryMock.On("Create", mock.AnythingOfType("AuditEntry")).Return(domain.AuditEntry{}, nil)
+	credentialRepositoryMock := new(credentialRepositoryMock)
+	credentialRepositoryMock.On("DeleteByUserReference", reference).Return(nil)
+
+	groupRepositoryMock := new(groupRepositoryMock)
+	groupRepositoryMock.On("RemoveMemberFromAll", reference).Return(int64(1), nil)
+	idempotencyRepositoryMock := new(idempotencyRepositoryMock)
+	idempotencyRepositoryMock.On("DeleteByResource", reference).Return(int64(1), nil)
+
+	useCase := NewDefaultErase(repositoryMock, groupRepositoryMock, auditRepositoryMock, credentialRepositoryMock, idempotencyRepositoryMock, store)
+
+	_, err := useCase.Execute(reference)
+
+	assert.Nil(t, err)
+	_, err = store.Get(avatarKey(reference, "V1", 64))
+	assert.Equal(t, blob.ErrNotFound, err)
+
+	repositoryMock.AssertExpectations(t)
+}
+
+func TestErase_GivenAnUser_WhenExecuteAndPurgerReturnedAnError_ThenReturnAFatalError(t *testing.T) {
+	t.Log("Failure to erase an User because a purger could not forget it")
+
+	reference := "REF1"
+	currentUser := domain.User{GenericEntity: domain.GenericEntity{Reference: reference, IsActive: true}, Email: "foobar@email.com"}
+	repositoryMock := new(repositoryMock)
+	repositoryMock.On("FindByReference", reference).Return(currentUser, nil)
+	credentialRepositoryMock := new(credentialRepositoryMock)
+	credentialRepositoryMock.On("DeleteByUserReference", reference).Return(nil)
+	groupRepositoryMock := new(groupRepositoryMock)
+	groupRepositoryMock.On("RemoveMemberFromAll", reference).Return(int64(1), nil)
+	idempotencyRepositoryMock := new(idempotencyRepositoryMock)
+	idempotencyRepositoryMock.On("DeleteByResource", reference).Return(int64(1), nil)
+	purgerMock := new(erasurePurgerMock)
+	purgerMock.On("Name").Return("verification emails")
+	purgerMock.On("Purge", currentUser).Return(errors.New("mailer error"))
+
+	useCase := NewDefaultErase(repositoryMock, groupRepositoryMock, new(auditRepositoryMock), credentialRepositoryMock, idempotencyRepositoryMock, blob.NewLocalBlobStore(t.TempDir()), purgerMock)
+
+	_, err := useCase.Execute(reference)
+
+	assert.NotNil(t, err)
+	assert.Equal(t, "unexpected error when purge the user verification emails", err.Error())
+
+	repositoryMock.AssertNotCalled(t, "Update", mock.Anything)
+}
@@ -0,0 +1,70 @@
+package user
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/desarrollogj/golang-api-example/domain"
+	"github.com/desarrollogj/golang-api-example/infrastructure"
+	"github.com/desarrollogj/golang-api-example/libs/errors"
+	"github.com/desarrollogj/golang-api-example/libs/logger"
+)
+
+// DataContributor represents a source of data held about an user. Each contributor adds its own section to the user data export
+type DataContributor interface {
+	Name() string
+	Contribute(user domain.User) ([]map[string]interface{}, error)
+}
+
+// Export represents the method to be implemented to export all the data held about an user
+type Export interface {
+	Execute(reference string) (domain.UserDataExport, error)
+}
+
+// defaultExport is the default implementation of Export interface
+type defaultExport struct {
+	repository   infrastructure.UserRepository
+	contributors []DataContributor
+}
+
+// NewDefaultExport creates a defaultExport instance
+func NewDefaultExport(repository infrastructure.UserRepository, contributors ...DataContributor) defaultExport {
+	return defaultExport{
+		repository:   repository,
+		contributors: contributors,
+	}
+}
+
+// Execute export an user data. Inactive users are exported too
+func (s defaultExport) Execute(reference string) (domain.UserDataExport, error) {
+	user, err := s.repository.FindByReference(reference)
+	if err != nil {
+		errMsg := fmt.Sprintf("unexpected error when try to get user with reference %s", reference)
+		logger.AppLog.Error().Err(err).Msg(errMsg)
+		return domain.UserDataExport{}, errors.NewFatalError(errMsg)
+	}
+	if len(user.Reference) == 0 {
+		return domain.UserDataExport{}, errors.NewNotFoundError("user not found").WithKey("user_not_found")
+	}
+
+	export := domain.UserDataExport{
+		Reference:     user.Reference,
+		GeneratedDate: time.Now().UTC(),
+		Sections:      []domain.UserDataExportSection{},
+	}
+	for _, contributor := range s.contributors {
+		records, err := contributor.Contribute(user)
+		if err != nil {
+			errMsg := fmt.Sprintf("unexpected error when export %s data", contributor.Name())
+			logger.AppLog.Error().Err(err).Msg(errMsg)
+			return domain.UserDataExport{}, errors.NewFatalError(errMsg)
+		}
+
+		export.Sections = append(export.Sections, domain.UserDataExportSection{
+			Name:    contributor.Name(),
+			Records: records,
+		})
+	}
+
+	return export, nil
+}
@@ -0,0 +1,258 @@
+package user
+
+import (
+	"encoding/base64"
+	"time"
+
+	"github.com/desarrollogj/golang-api-example/domain"
+	"github.com/desarrollogj/golang-api-example/infrastructure"
+	"github.com/desarrollogj/golang-api-example/libs/blob"
+)
+
+// userRecordContributor exports the user record
+type userRecordContributor struct {
+}
+
+// NewUserRecordContributor creates an userRecordContributor instance
+func NewUserRecordContributor() userRecordContributor {
+	return userRecordContributor{}
+}
+
+func (c userRecordContributor) Name() string {
+	return "user"
+}
+
+func (c userRecordContributor) Contribute(user domain.User) ([]map[string]interface{}, error) {
+	record := map[string]interface{}{
+		"id":        user.Reference,
+		"firstName": user.FirstName,
+		"lastName":  user.LastName,
+		"email":     user.Email,
+		"isActive":  user.IsActive,
+		"created":   user.CreatedDate.UTC().Format(time.RFC3339),
+		"updated":   user.UpdatedDate.UTC().Format(time.RFC3339),
+	}
+	if len(user.Phone) > 0 {
+		record["phone"] = user.Phone
+	}
+	if user.BirthDate != nil {
+		record["birthDate"] = user.BirthDate.UTC().Format("2006-01-02")
+	}
+	if len(user.Locale) > 0 {
+		record["locale"] = user.Locale
+	}
+	if len(user.Timezone) > 0 {
+		record["timezone"] = user.Timezone
+	}
+	if user.Address != nil {
+		record["address"] = map[string]interface{}{
+			"line1":      user.Address.Line1,
+			"line2":      user.Address.Line2,
+			"city":       user.Address.City,
+			"region":     user.Address.Region,
+			"postalCode": user.Address.PostalCode,
+			"country":    user.Address.Country,
+		}
+	}
+	if user.EmailVerifiedDate != nil {
+		record["emailVerified"] = user.EmailVerifiedDate.UTC().Format(time.RFC3339)
+	}
+	if user.EmailVerificationSentDate != nil {
+		record["emailVerificationSent"] = user.EmailVerificationSentDate.UTC().Format(time.RFC3339)
+	}
+	if len(user.Roles) > 0 {
+		record["roles"] = user.Roles
+	}
+	if len(user.Attributes) > 0 {
+		attributes := map[string]interface{}{}
+		for name, value := range user.Attributes {
+			if date, ok := value.(time.Time); ok {
+				value = date.UTC().Format(AttributeDateLayout)
+			}
+			attributes[name] = value
+		}
+		record["attributes"] = attributes
+	}
+	if len(user.Tags) > 0 {
+		record["tags"] = user.Tags
+	}
+	if user.Avatar != nil {
+		record["avatar"] = map[string]interface{}{
+			"version":     user.Avatar.Version,
+			"contentType": user.Avatar.ContentType,
+			"sizes":       user.Avatar.Sizes,
+			"updated":     user.Avatar.UpdatedDate.UTC().Format(time.RFC3339),
+		}
+	}
+	if len(user.ExternalIDs) > 0 {
+		externalIDs := []map[string]interface{}{}
+		for _, externalID := range user.ExternalIDs {
+			externalIDs = append(externalIDs, map[string]interface{}{"source": externalID.Source, "id": externalID.ID})
+		}
+		record["externalIds"] = externalIDs
+	}
+	if len(user.Status) > 0 {
+		record["status"] = string(user.Status)
+		record["statusDate"] = user.StatusDate.UTC().Format(time.RFC3339)
+	}
+	if len(user.StatusReason) > 0 {
+		record["statusReason"] = user.StatusReason
+	}
+	if user.ErasedDate != nil {
+		record["erased"] = user.ErasedDate.UTC().Format(time.RFC3339)
+	}
+	if len(user.MergedInto) > 0 {
+		record["mergedInto"] = user.MergedInto
+	}
+
+	return []map[string]interface{}{record}, nil
+}
+
+// auditContributor exports the audit entries registered for the user
+type auditContributor struct {
+	repository infrastructure.AuditRepository
+}
+
+// NewAuditContributor creates an auditContributor instance
+func NewAuditContributor(repository infrastructure.AuditRepository) auditContributor {
+	return auditContributor{
+		repository: repository,
+	}
+}
+
+func (c auditContributor) Name() string {
+	return "audit"
+}
+
+func (c auditContributor) Contribute(user domain.User) ([]map[string]interface{}, error) {
+	entries, err := c.repository.FindByEntity(domain.AuditEntityUser, user.Reference)
+	if err != nil {
+		return nil, err
+	}
+
+	records := []map[string]interface{}{}
+	for _, entry := range entries {
+		records = append(records, map[string]interface{}{
+			"id":      entry.Reference,
+			"action":  entry.Action,
+			"details": entry.Details,
+			"created": entry.CreatedDate.UTC().Format(time.RFC3339),
+		})
+	}
+
+	return records, nil
+}
+
+// groupMembershipContributor exports the groups the user belongs to, including the inactive ones
+type groupMembershipContributor struct {
+	repository infrastructure.GroupRepository
+}
+
+// NewGroupMembershipContributor creates a groupMembershipContributor instance
+func NewGroupMembershipContributor(repository infrastructure.GroupRepository) groupMembershipContributor {
+	return groupMembershipContributor{
+		repository: repository,
+	}
+}
+
+func (c groupMembershipContributor) Name() string {
+	return "groups"
+}
+
+func (c groupMembershipContributor) Contribute(user domain.User) ([]map[string]interface{}, error) {
+	groups, err := c.repository.FindByMember(user.Reference)
+	if err != nil {
+		return nil, err
+	}
+
+	records := []map[string]interface{}{}
+	for _, group := range groups {
+		for _, member := range group.Members {
+			if member.UserReference != user.Reference {
+				continue
+			}
+			records = append(records, map[string]interface{}{
+				"id":       group.Reference,
+				"name":     group.Name,
+				"isActive": group.IsActive,
+				"added":    member.AddedDate.UTC().Format(time.RFC3339),
+			})
+		}
+	}
+
+	return records, nil
+}
+
+// credentialContributor exports the metadata of the user password. The password hash is never exported
+type credentialContributor struct {
+	repository infrastructure.CredentialRepository
+}
+
+// NewCredentialContributor creates a credentialContributor instance
+func NewCredentialContributor(repository infrastructure.CredentialRepository) credentialContributor {
+	return credentialContributor{
+		repository: repository,
+	}
+}
+
+func (c credentialContributor) Name() string {
+	return "credentials"
+}
+
+func (c credentialContributor) Contribute(user domain.User) ([]map[string]interface{}, error) {
+	credential, err := c.repository.FindByUserReference(user.Reference)
+	if err != nil {
+		return nil, err
+	}
+
+	records := []map[string]interface{}{}
+	if len(credential.UserReference) > 0 {
+		records = append(records, map[string]interface{}{
+			"type":    "password",
+			"created": credential.CreatedDate.UTC().Format(time.RFC3339),
+			"updated": credential.UpdatedDate.UTC().Format(time.RFC3339),
+		})
+	}
+
+	return records, nil
+}
+
+// avatarContributor exports the avatar thumbnails of the user, encoded in base64
+type avatarContributor struct {
+	store blob.BlobStore
+}
+
+// NewAvatarContributor creates an avatarContributor instance
+func NewAvatarContributor(store blob.BlobStore) avatarContributor {
+	return avatarContributor{
+		store: store,
+	}
+}
+
+func (c avatarContributor) Name() string {
+	return "avatar"
+}
+
+func (c avatarContributor) Contribute(user domain.User) ([]map[string]interface{}, error) {
+	records := []map[string]interface{}{}
+	if user.Avatar == nil {
+		return records, nil
+	}
+
+	for _, size := range user.Avatar.Sizes {
+		data, err := c.store.Get(avatarKey(user.Reference, user.Avatar.Version, size))
+		if err == blob.ErrNotFound {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, map[string]interface{}{
+			"size":        size,
+			"contentType": user.Avatar.ContentType,
+			"data":        base64.StdEncoding.EncodeToString(data),
+		})
+	}
+
+	return records, nil
+}
@@ -0,0 +1,398 @@
+package user
+
+import (
+	"encoding/base64"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/desarrollogj/golang-api-example/domain"
+	"github.com/desarrollogj/golang-api-example/libs/blob"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUserRecordContributor_GivenAnUser_WhenContribute_ThenReturnTheUserRecord(t *testing.T) {
+	t.Log("Successfully contribute the user record")
+
+	now := time.Now().UTC()
+	nowStr := now.Format(time.RFC3339)
+	user := domain.User{
+		GenericEntity: domain.GenericEntity{
+			Reference:   "REF1",
+			IsActive:    false,
+			CreatedDate: now,
+			UpdatedDate: now,
+		},
+		FirstName:  "erased",
+		LastName:   "erased",
+		Email:      "erased-REF1@erased.invalid",
+		ErasedDate: &now,
+	}
+
+	contributor := NewUserRecordContributor()
+	records, err := contributor.Contribute(user)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "user", contributor.Name())
+	assert.Equal(t, []map[string]interface{}{
+		{
+			"id":        "REF1",
+			"firstName": "erased",
+			"lastName":  "erased",
+			"email":     "erased-REF1@erased.invalid",
+			"isActive":  false,
+			"created":   nowStr,
+			"updated":   nowStr,
+			"erased":    nowStr,
+		},
+	}, records)
+}
+
+func TestUserRecordContributor_GivenAnUserWithProfile_WhenContribute_ThenReturnTheProfile(t *testing.T) {
+	t.Log("Successfully contribute the user record with its profile")
+
+	now := time.Now().UTC()
+	nowStr := now.Format(time.RFC3339)
+	birthDate := time.Date(1990, 5, 17, 0, 0, 0, 0, time.UTC)
+	user := domain.User{
+		GenericEntity: domain.GenericEntity{
+			Reference:   "REF1",
+			IsActive:    true,
+			CreatedDate: now,
+			UpdatedDate: now,
+		},
+		UserProfile: domain.UserProfile{
+			Phone:     "+5491112345678",
+			BirthDate: &birthDate,
+			Locale:    "es-AR",
+			Timezone:  "America/Argentina/Buenos_Aires",
+			Address:   &domain.Address{Line1: "Street 123", City: "Buenos Aires", Country: "AR"},
+		},
+		FirstName:    "Foo",
+		LastName:     "Bar",
+		Email:        "foobar@email.com",
+		Attributes:   map[string]interface{}{"plan": "pro", "hiredOn": time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)},
+		Tags:         []string{"vip"},
+		Status:       domain.UserStatusSuspended,
+		StatusReason: "abuse report",
+		StatusDate:   now,
+	}
+
+	contributor := NewUserRecordContributor()
+	records, err := contributor.Contribute(user)
+
+	assert.Nil(t, err)
+	assert.Equal(t, []map[string]interface{}{
+		{
+			"id":           "REF1",
+			"firstName":    "Foo",
+			"lastName":     "Bar",
+			"email":        "foobar@email.com",
+			"isActive":     true,
+			"created":      nowStr,
+			"updated":      nowStr,
+			"phone":        "+5491112345678",
+			"birthDate":    "1990-05-17",
+			"locale":       "es-AR",
+			"timezone":     "America/Argentina/Buenos_Aires",
+			"status":       "suspended",
+			"statusReason": "abuse report",
+			"statusDate":   nowStr,
+			"attributes":   map[string]interface{}{"plan": "pro", "hiredOn": "2024-03-01"},
+			"tags":         []string{"vip"},
+			"address": map[string]interface{}{
+				"line1":      "Street 123",
+				"line2":      "",
+				"city":       "Buenos Aires",
+				"region":     "",
+				"postalCode": "",
+				"country":    "AR",
+			},
+		},
+	}, records)
+}
+
+// userRecordFields are the user record keys of each user field. The fields without key are not in the user record
+var userRecordFields = map[string]string{
+	"Reference":                 "id",
+	"IsActive":                  "isActive",
+	"CreatedDate":               "created",
+	"UpdatedDate":               "updated",
+	"Phone":                     "phone",
+	"BirthDate":                 "birthDate",
+	"Locale":                    "locale",
+	"Timezone":                  "timezone",
+	"Address":                   "address",
+	"FirstName":                 "firstName",
+	"LastName":                  "lastName",
+	"Email":                     "email",
+	"EmailVerifiedDate":         "emailVerified",
+	"EmailVerificationSentDate": "emailVerificationSent",
+	"Roles":                     "roles",
+	"Attributes":                "attributes",
+	"Tags":                      "tags",
+	"ExternalIDs":               "externalIds",
+	"Avatar":                    "avatar",
+	"Status":                    "status",
+	"StatusReason":              "statusReason",
+	"StatusDate":                "statusDate",
+	"ErasedDate":                "erased",
+	"MergedInto":                "mergedInto",
+	"Undecryptable":             "",
+}
+
+// newFullyPopulatedUser creates an user with all its fields
+func newFullyPopulatedUser(now time.Time) domain.User {
+	birthDate := time.Date(1990, 5, 17, 0, 0, 0, 0, time.UTC)
+	return domain.User{
+		GenericEntity: domain.GenericEntity{Reference: "REF1", IsActive: true, CreatedDate: now, UpdatedDate: now},
+		UserProfile: domain.UserProfile{
+			Phone:     "+5491112345678",
+			BirthDate: &birthDate,
+			Locale:    "es-AR",
+			Timezone:  "America/Argentina/Buenos_Aires",
+			Address:   &domain.Address{Line1: "Street 123", City: "Buenos Aires", Country: "AR"},
+		},
+		FirstName:                 "Foo",
+		LastName:                  "Bar",
+		Email:                     "foobar@email.com",
+		EmailVerifiedDate:         &now,
+		EmailVerificationSentDate: &now,
+		Roles:                     []string{"admin"},
+		Attributes:                map[string]interface{}{"plan": "pro"},
+		Tags:                      []string{"vip"},
+		ExternalIDs:               []domain.UserExternalID{{Source: "crm", ID: "1"}},
+		Avatar:                    &domain.UserAvatar{Version: "V1", ContentType: "image/png", Sizes: []int{64}, UpdatedDate: now},
+		Status:                    domain.UserStatusSuspended,
+		StatusReason:              "abuse report",
+		StatusDate:                now,
+		ErasedDate:                &now,
+		MergedInto:                "REF2",
+		Undecryptable:             true,
+	}
+}
+
+func TestUserRecordContributor_GivenAFullyPopulatedUser_WhenContribute_ThenExportEveryField(t *testing.T) {
+	t.Log("Successfully contribute every field of the user record, so the new user fields are not left out of the export")
+
+	user := newFullyPopulatedUser(time.Now().UTC())
+
+	records, err := NewUserRecordContributor().Contribute(user)
+
+	assert.Nil(t, err)
+	assert.Len(t, records, 1)
+
+	var checkFields func(value reflect.Value)
+	checkFields = func(value reflect.Value) {
+		for i := 0; i < value.NumField(); i++ {
+			field := value.Type().Field(i)
+			if field.Anonymous {
+				checkFields(value.Field(i))
+				continue
+			}
+			key, ok := userRecordFields[field.Name]
+			assert.True(t, ok, "user field %s has no user record key", field.Name)
+			assert.False(t, value.Field(i).IsZero(), "user field %s is not populated", field.Name)
+			if len(key) > 0 {
+				assert.Contains(t, records[0], key, field.Name)
+			}
+		}
+	}
+	checkFields(reflect.ValueOf(user))
+
+	assert.Equal(t, "REF2", records[0]["mergedInto"])
+	assert.Equal(t, user.EmailVerificationSentDate.Format(time.RFC3339), records[0]["emailVerificationSent"])
+}
+
+func TestCredentialContributor_GivenAnUserWithPassword_WhenContribute_ThenReturnTheCredentialMetadata(t *testing.T) {
+	t.Log("Successfully contribute the user password metadata, without its hash")
+
+	now := time.Now().UTC()
+	user := newFullyPopulatedUser(now)
+	credentialRepositoryMock := new(credentialRepositoryMock)
+	credentialRepositoryMock.On("FindByUserReference", "REF1").
+		Return(domain.Credential{UserReference: "REF1", PasswordHash: "HASH", CreatedDate: now, UpdatedDate: now}, nil)
+
+	contributor := NewCredentialContributor(credentialRepositoryMock)
+	records, err := contributor.Contribute(user)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "credentials", contributor.Name())
+	assert.Equal(t, []map[string]interface{}{
+		{
+			"type":    "password",
+			"created": now.Format(time.RFC3339),
+			"updated": now.Format(time.RFC3339),
+		},
+	}, records)
+
+	credentialRepositoryMock.AssertExpectations(t)
+}
+
+func TestCredentialContributor_GivenAnUserWithoutPassword_WhenContribute_ThenReturnNoRecords(t *testing.T) {
+	t.Log("Successfully contribute no credentials for an user without password")
+
+	credentialRepositoryMock := new(credentialRepositoryMock)
+	credentialRepositoryMock.On("FindByUserReference", "REF1").Return(domain.Credential{}, nil)
+
+	records, err := NewCredentialContributor(credentialRepositoryMock).Contribute(newFullyPopulatedUser(time.Now().UTC()))
+
+	assert.Nil(t, err)
+	assert.Empty(t, records)
+
+	credentialRepositoryMock.On("FindByUserReference", "REF2").Return(domain.Credential{}, errors.New("repository error"))
+	_, err = NewCredentialContributor(credentialRepositoryMock).Contribute(domain.User{GenericEntity: domain.GenericEntity{Reference: "REF2"}})
+
+	assert.NotNil(t, err)
+}
+
+func TestAvatarContributor_GivenAnUserWithAvatar_WhenContribute_ThenReturnTheThumbnails(t *testing.T) {
+	t.Log("Successfully contribute the user avatar thumbnails, skipping the missing ones")
+
+	user := newFullyPopulatedUser(time.Now().UTC())
+	user.Avatar.Sizes = []int{64, 256}
+	store := blob.NewLocalBlobStore(t.TempDir())
+	store.Put(avatarKey("REF1", "V1", 64), []byte("thumbnail"))
+
+	contributor := NewAvatarContributor(store)
+	records, err := contributor.Contribute(user)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "avatar", contributor.Name())
+	assert.Equal(t, []map[string]interface{}{
+		{
+			"size":        64,
+			"contentType": "image/png",
+			"data":        base64.StdEncoding.EncodeToString([]byte("thumbnail")),
+		},
+	}, records)
+
+	records, err = contributor.Contribute(domain.User{GenericEntity: domain.GenericEntity{Reference: "REF2"}})
+
+	assert.Nil(t, err)
+	assert.Empty(t, records)
+}
+
+func TestAuditContributor_GivenAnUser_WhenContribute_ThenReturnTheAuditRecords(t *testing.T) {
+	t.Log("Successfully contribute the user audit entries")
+
+	now := time.Now().UTC()
+	user := domain.User{
+		GenericEntity: domain.GenericEntity{
+			Reference: "REF1",
+		},
+	}
+	entries := []domain.AuditEntry{
+		{
+			Reference:       "AUDIT1",
+			Action:          domain.AuditActionUserErase,
+			EntityType:      domain.AuditEntityUser,
+			EntityReference: "REF1",
+			CreatedDate:     now,
+		},
+	}
+	auditRepositoryMock := new(auditRepositoryMock)
+	auditRepositoryMock.On("FindByEntity", domain.AuditEntityUser, "REF1").Return(entries, nil)
+
+	contributor := NewAuditContributor(auditRepositoryMock)
+	records, err := contributor.Contribute(user)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "audit", contributor.Name())
+	assert.Equal(t, []map[string]interface{}{
+		{
+			"id":      "AUDIT1",
+			"action":  "user_erased",
+			"details": map[string]string(nil),
+			"created": now.Format(time.RFC3339),
+		},
+	}, records)
+
+	auditRepositoryMock.AssertExpectations(t)
+}
+
+func TestAuditContributor_GivenAnUser_WhenContributeAndRepositoryReturnedAnError_ThenReturnTheError(t *testing.T) {
+	t.Log("Failure to contribute the user audit entries because the repository returned an error")
+
+	user := domain.User{
+		GenericEntity: domain.GenericEntity{
+			Reference: "REF1",
+		},
+	}
+	auditRepositoryMock := new(auditRepositoryMock)
+	auditRepositoryMock.On("FindByEntity", domain.AuditEntityUser, "REF1").Return([]domain.AuditEntry{}, errors.New("repository error"))
+
+	contributor := NewAuditContributor(auditRepositoryMock)
+	_, err := contributor.Contribute(user)
+
+	assert.NotNil(t, err)
+
+	auditRepositoryMock.AssertExpectations(t)
+}
+
+func TestGroupMembershipContributor_GivenAnUser_WhenContribute_ThenReturnTheGroupRecords(t *testing.T) {
+	t.Log("Successfully contribute the user group memberships, including the inactive groups")
+
+	added := time.Now().UTC()
+	user := domain.User{
+		GenericEntity: domain.GenericEntity{
+			Reference: "REF1",
+		},
+	}
+	groups := []domain.Group{
+		{
+			GenericEntity: domain.GenericEntity{Reference: "GROUP1", IsActive: true},
+			Name:          "Team",
+			Members:       []domain.GroupMember{{UserReference: "REF2", AddedDate: added}, {UserReference: "REF1", AddedDate: added}},
+		},
+		{
+			GenericEntity: domain.GenericEntity{Reference: "GROUP2", IsActive: false},
+			Name:          "Old team",
+			Members:       []domain.GroupMember{{UserReference: "REF1", AddedDate: added}},
+		},
+	}
+	groupRepositoryMock := new(groupRepositoryMock)
+	groupRepositoryMock.On("FindByMember", "REF1").Return(groups, nil)
+
+	contributor := NewGroupMembershipContributor(groupRepositoryMock)
+	records, err := contributor.Contribute(user)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "groups", contributor.Name())
+	assert.Equal(t, []map[string]interface{}{
+		{
+			"id":       "GROUP1",
+			"name":     "Team",
+			"isActive": true,
+			"added":    added.Format(time.RFC3339),
+		},
+		{
+			"id":       "GROUP2",
+			"name":     "Old team",
+			"isActive": false,
+			"added":    added.Format(time.RFC3339),
+		},
+	}, records)
+
+	groupRepositoryMock.AssertExpectations(t)
+}
+
+func TestGroupMembershipContributor_GivenAnUser_WhenContributeAndRepositoryReturnedAnError_ThenReturnTheError(t *testing.T) {
+	t.Log("Failure to contribute the user group memberships because the repository returned an error")
+
+	user := domain.User{
+		GenericEntity: domain.GenericEntity{
+			Reference: "REF1",
+		},
+	}
+	groupRepositoryMock := new(groupRepositoryMock)
+	groupRepositoryMock.On("FindByMember", "REF1").Return([]domain.Group{}, errors.New("repository error"))
+
+	contributor := NewGroupMembershipContributor(groupRepositoryMock)
+	_, err := contributor.Contribute(user)
+
+	assert.NotNil(t, err)
+
+	groupRepositoryMock.AssertExpectations(t)
+}
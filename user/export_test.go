@@ -0,0 +1,112 @@
+package user
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/desarrollogj/golang-api-example/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExport_GivenAReference_WhenExecute_ThenReturnEachContributorSection(t *testing.T) {
+	t.Log("Successfully export an User data")
+
+	reference := "REF1"
+	currentUser := domain.User{
+		GenericEntity: domain.GenericEntity{
+			Reference: reference,
+			IsActive:  false,
+		},
+		FirstName: "Foo",
+		LastName:  "Bar",
+		Email:     "foobar@email.com",
+	}
+	userRecords := []map[string]interface{}{{"id": reference}}
+	auditRecords := []map[string]interface{}{}
+	repositoryMock := new(repositoryMock)
+	repositoryMock.On("FindByReference", reference).Return(currentUser, nil)
+	userContributorMock := new(dataContributorMock)
+	userContributorMock.On("Name").Return("user")
+	userContributorMock.On("Contribute", currentUser).Return(userRecords, nil)
+	auditContributorMock := new(dataContributorMock)
+	auditContributorMock.On("Name").Return("audit")
+	auditContributorMock.On("Contribute", currentUser).Return(auditRecords, nil)
+
+	useCase := NewDefaultExport(repositoryMock, userContributorMock, auditContributorMock)
+
+	export, err := useCase.Execute(reference)
+
+	assert.Nil(t, err)
+	assert.Equal(t, reference, export.Reference)
+	assert.False(t, export.GeneratedDate.IsZero())
+	assert.Equal(t, []domain.UserDataExportSection{
+		{Name: "user", Records: userRecords},
+		{Name: "audit", Records: auditRecords},
+	}, export.Sections)
+
+	repositoryMock.AssertExpectations(t)
+	userContributorMock.AssertExpectations(t)
+	auditContributorMock.AssertExpectations(t)
+}
+
+func TestExport_GivenAReference_WhenExecuteAndUserNotFound_ThenReturnANotFoundError(t *testing.T) {
+	t.Log("Failure to export an User data because the user was not found")
+
+	reference := "REF1"
+	repositoryMock := new(repositoryMock)
+	repositoryMock.On("FindByReference", reference).Return(domain.User{}, nil)
+	contributorMock := new(dataContributorMock)
+
+	useCase := NewDefaultExport(repositoryMock, contributorMock)
+
+	_, err := useCase.Execute(reference)
+
+	assert.NotNil(t, err)
+	assert.Equal(t, "user not found", err.Error())
+
+	repositoryMock.AssertExpectations(t)
+	contributorMock.AssertNotCalled(t, "Contribute")
+}
+
+func TestExport_GivenAReference_WhenExecuteAndFindReturnedAnError_ThenReturnAFatalError(t *testing.T) {
+	t.Log("Failure to export an User data because find returned an unexpected error")
+
+	reference := "REF1"
+	repositoryMock := new(repositoryMock)
+	repositoryMock.On("FindByReference", reference).Return(domain.User{}, errors.New("repository error"))
+
+	useCase := NewDefaultExport(repositoryMock)
+
+	_, err := useCase.Execute(reference)
+
+	assert.NotNil(t, err)
+	assert.Equal(t, "unexpected error when try to get user with reference REF1", err.Error())
+
+	repositoryMock.AssertExpectations(t)
+}
+
+func TestExport_GivenAReference_WhenExecuteAndAContributorReturnedAnError_ThenReturnAFatalError(t *testing.T) {
+	t.Log("Failure to export an User data because a contributor returned an unexpected error")
+
+	reference := "REF1"
+	currentUser := domain.User{
+		GenericEntity: domain.GenericEntity{
+			Reference: reference,
+		},
+	}
+	repositoryMock := new(repositoryMock)
+	repositoryMock.On("FindByReference", reference).Return(currentUser, nil)
+	contributorMock := new(dataContributorMock)
+	contributorMock.On("Name").Return("audit")
+	contributorMock.On("Contribute", currentUser).Return(nil, errors.New("contributor error"))
+
+	useCase := NewDefaultExport(repositoryMock, contributorMock)
+
+	_, err := useCase.Execute(reference)
+
+	assert.NotNil(t, err)
+	assert.Equal(t, "unexpected error when export audit data", err.Error())
+
+	repositoryMock.AssertExpectations(t)
+	contributorMock.AssertExpectations(t)
+}
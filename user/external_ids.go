@@ -0,0 +1,128 @@
+package user
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/desarrollogj/golang-api-example/domain"
+	"github.com/desarrollogj/golang-api-example/infrastructure"
+	"github.com/desarrollogj/golang-api-example/libs/errors"
+	"github.com/desarrollogj/golang-api-example/libs/logger"
+)
+
+const (
+	maxExternalIDSourceLength = 50
+	maxExternalIDLength       = 200
+	maxUserExternalIDs        = 20
+)
+
+// NormalizeExternalID returns the external id with its source in lower case and its id without surrounding spaces.
+// Sources can only have letters, digits, hyphens, underscores and dots
+func NormalizeExternalID(externalID domain.UserExternalID) (domain.UserExternalID, error) {
+	source := strings.ToLower(strings.TrimSpace(externalID.Source))
+	if len(source) == 0 {
+		return domain.UserExternalID{}, errors.NewValidationError("external id source is required").WithKey("external_id_source_required")
+	}
+	if utf8.RuneCountInString(source) > maxExternalIDSourceLength {
+		return domain.UserExternalID{}, errors.NewValidationError(fmt.Sprintf("external id source %s is longer than %d characters", source, maxExternalIDSourceLength)).
+			WithKey("external_id_source_too_long", source, maxExternalIDSourceLength)
+	}
+	for _, r := range source {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && !strings.ContainsRune("-_.", r) {
+			return domain.UserExternalID{}, errors.NewValidationError(fmt.Sprintf("external id source %s is not valid", source)).WithKey("external_id_source_not_valid", source)
+		}
+	}
+
+	id := strings.TrimSpace(externalID.ID)
+	if len(id) == 0 {
+		return domain.UserExternalID{}, errors.NewValidationError(fmt.Sprintf("external id of source %s is required", source)).WithKey("external_id_required", source)
+	}
+	if utf8.RuneCountInString(id) > maxExternalIDLength {
+		return domain.UserExternalID{}, errors.NewValidationError(fmt.Sprintf("external id of source %s is longer than %d characters", source, maxExternalIDLength)).
+			WithKey("external_id_too_long", source, maxExternalIDLength)
+	}
+
+	return domain.UserExternalID{Source: source, ID: id}, nil
+}
+
+// normalizeExternalIDs returns the normalized external ids, sorted by source and id and without duplicates
+func normalizeExternalIDs(externalIDs []domain.UserExternalID) ([]domain.UserExternalID, error) {
+	normalized := []domain.UserExternalID{}
+	for _, externalID := range externalIDs {
+		externalID, err := NormalizeExternalID(externalID)
+		if err != nil {
+			return nil, err
+		}
+		if !containsExternalID(normalized, externalID) {
+			normalized = append(normalized, externalID)
+		}
+	}
+	if len(normalized) > maxUserExternalIDs {
+		return nil, errors.NewValidationError(fmt.Sprintf("an user can not have more than %d external ids", maxUserExternalIDs)).WithKey("user_external_ids_limit", maxUserExternalIDs)
+	}
+	if len(normalized) == 0 {
+		return nil, nil
+	}
+
+	sort.Slice(normalized, func(i, j int) bool {
+		if normalized[i].Source != normalized[j].Source {
+			return normalized[i].Source < normalized[j].Source
+		}
+		return normalized[i].ID < normalized[j].ID
+	})
+	return normalized, nil
+}
+
+// checkExternalIDsAvailable fails when an external id belongs to another user than the referenced one.
+// Deleted users release their external ids, so a pair is only taken by the user that still has it
+func checkExternalIDsAvailable(repository infrastructure.UserRepository, reference string, externalIDs []domain.UserExternalID) error {
+	for _, externalID := range externalIDs {
+		owner, err := repository.FindByExternalID(externalID.Source, externalID.ID)
+		if err != nil {
+			errMsg := fmt.Sprintf("unexpected error when try to get user with external id %s/%s", externalID.Source, externalID.ID)
+			logger.AppLog.Error().Err(err).Msg(errMsg)
+			return errors.NewFatalError(errMsg)
+		}
+		if len(owner.Reference) > 0 && owner.Reference != reference {
+			return errors.NewConflictError(fmt.Sprintf("external id %s/%s belongs to another user", externalID.Source, externalID.ID)).
+				WithKey("external_id_taken", externalID.Source, externalID.ID)
+		}
+	}
+
+	return nil
+}
+
+// storeError returns the business error of a failed user store. The external ids unique index rejects
+// the pairs that were taken by another user after they were checked, and the email unique index rejects
+// the emails of other active users. A patch or status change of an user modified meanwhile can be sent again, and an user whose
+// personal data could not be decrypted is never stored
+func storeError(err error, errMsg string) error {
+	if err == infrastructure.ErrDuplicateExternalID {
+		return errors.NewConflictError("an external id belongs to another user").WithKey("external_id_taken_by_another_user")
+	}
+	if err == infrastructure.ErrDuplicateEmail {
+		return errors.NewConflictError("the email belongs to another user").WithKey("user_email_taken")
+	}
+	if err == infrastructure.ErrUserModified {
+		return errors.NewConflictError("the user was modified while it was updated, try again").WithKey("user_modified")
+	}
+	if err == infrastructure.ErrUndecryptableUser {
+		return errors.NewUnprocessableError("the user personal data can not be decrypted, so the user can not be updated").WithKey("user_undecryptable")
+	}
+
+	logger.AppLog.Error().Err(err).Msg(errMsg)
+	return errors.NewFatalError(errMsg)
+}
+
+func containsExternalID(externalIDs []domain.UserExternalID, externalID domain.UserExternalID) bool {
+	for _, current := range externalIDs {
+		if current == externalID {
+			return true
+		}
+	}
+
+	return false
+}
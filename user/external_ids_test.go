@@ -0,0 +1,70 @@
+package user
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/desarrollogj/golang-api-example/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeExternalID_GivenExternalIDs_WhenNormalize_ThenReturnTheNormalizedExternalIDOrAValidationError(t *testing.T) {
+	t.Log("Successfully normalize the external ids")
+
+	tests := []struct {
+		externalID domain.UserExternalID
+		expected   domain.UserExternalID
+		err        string
+	}{
+		{domain.UserExternalID{Source: " CRM ", ID: " C-1 "}, domain.UserExternalID{Source: "crm", ID: "C-1"}, ""},
+		{domain.UserExternalID{Source: "legacy_erp.v2", ID: "Id With Spaces"}, domain.UserExternalID{Source: "legacy_erp.v2", ID: "Id With Spaces"}, ""},
+		{domain.UserExternalID{Source: " ", ID: "42"}, domain.UserExternalID{}, "external id source is required"},
+		{domain.UserExternalID{Source: "my crm", ID: "42"}, domain.UserExternalID{}, "external id source my crm is not valid"},
+		{domain.UserExternalID{Source: "crm", ID: " "}, domain.UserExternalID{}, "external id of source crm is required"},
+		{domain.UserExternalID{Source: "crm", ID: strings.Repeat("1", 201)}, domain.UserExternalID{}, "external id of source crm is longer than 200 characters"},
+	}
+	for _, test := range tests {
+		externalID, err := NormalizeExternalID(test.externalID)
+		if len(test.err) > 0 {
+			assert.EqualError(t, err, test.err)
+			continue
+		}
+		assert.Nil(t, err)
+		assert.Equal(t, test.expected, externalID)
+	}
+}
+
+func TestNormalizeExternalIDs_GivenExternalIDs_WhenNormalize_ThenReturnSortedExternalIDsWithoutDuplicates(t *testing.T) {
+	t.Log("Successfully normalize an external id list")
+
+	externalIDs, err := normalizeExternalIDs([]domain.UserExternalID{{Source: "erp", ID: "42"}, {Source: "CRM", ID: "C-2"}, {Source: "crm", ID: "C-1"}, {Source: "Erp", ID: "42"}})
+
+	assert.Nil(t, err)
+	assert.Equal(t, []domain.UserExternalID{{Source: "crm", ID: "C-1"}, {Source: "crm", ID: "C-2"}, {Source: "erp", ID: "42"}}, externalIDs)
+
+	externalIDs, err = normalizeExternalIDs([]domain.UserExternalID{})
+	assert.Nil(t, err)
+	assert.Nil(t, externalIDs)
+
+	tooMany := []domain.UserExternalID{}
+	for i := 0; i <= maxUserExternalIDs; i++ {
+		tooMany = append(tooMany, domain.UserExternalID{Source: "crm", ID: fmt.Sprint(i)})
+	}
+	_, err = normalizeExternalIDs(tooMany)
+	assert.EqualError(t, err, "an user can not have more than 20 external ids")
+}
+
+func TestCheckExternalIDsAvailable_GivenExternalIDs_WhenCheck_ThenOnlyFailForTheExternalIDsOfOtherUsers(t *testing.T) {
+	t.Log("External ids of the same user are available, the ones of other users are not")
+
+	repositoryMock := new(repositoryMock)
+	repositoryMock.On("FindByExternalID", "crm", "C-1").Return(domain.User{GenericEntity: domain.GenericEntity{Reference: "USER1"}}, nil)
+	repositoryMock.On("FindByExternalID", "erp", "42").Return(domain.User{}, nil)
+
+	err := checkExternalIDsAvailable(repositoryMock, "USER1", []domain.UserExternalID{{Source: "crm", ID: "C-1"}, {Source: "erp", ID: "42"}})
+	assert.Nil(t, err)
+
+	err = checkExternalIDsAvailable(repositoryMock, "USER2", []domain.UserExternalID{{Source: "crm", ID: "C-1"}})
+	assert.EqualError(t, err, "external id crm/C-1 belongs to another user")
+}
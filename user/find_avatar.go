@@ -0,0 +1,88 @@
+package user
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/desarrollogj/golang-api-example/domain"
+	"github.com/desarrollogj/golang-api-example/infrastructure"
+	"github.com/desarrollogj/golang-api-example/libs/blob"
+	"github.com/desarrollogj/golang-api-example/libs/errors"
+	"github.com/desarrollogj/golang-api-example/libs/logger"
+)
+
+// FindAvatar represents the method to be implemented to get an user avatar thumbnail
+type FindAvatar interface {
+	Execute(reference string, size int) (domain.UserAvatarImage, error)
+}
+
+// defaultFindAvatar is the default implementation of FindAvatar interface
+type defaultFindAvatar struct {
+	repository infrastructure.UserRepository
+	store      blob.BlobStore
+}
+
+// NewDefaultFindAvatar creates a defaultFindAvatar instance
+func NewDefaultFindAvatar(repository infrastructure.UserRepository, store blob.BlobStore) defaultFindAvatar {
+	return defaultFindAvatar{
+		repository: repository,
+		store:      store,
+	}
+}
+
+// Execute get an active user avatar thumbnail. Without a size, the first size stored is returned
+func (s defaultFindAvatar) Execute(reference string, size int) (domain.UserAvatarImage, error) {
+	user, err := s.repository.FindActiveByReference(reference)
+	if err != nil {
+		errMsg := fmt.Sprintf("unexpected error when try to get user with reference %s", reference)
+		logger.AppLog.Error().Err(err).Msg(errMsg)
+		return domain.UserAvatarImage{}, errors.NewFatalError(errMsg)
+	}
+	if len(user.Reference) == 0 {
+		return domain.UserAvatarImage{}, errors.NewNotFoundError("user not found").WithKey("user_not_found")
+	}
+	if user.Avatar == nil || len(user.Avatar.Sizes) == 0 {
+		return domain.UserAvatarImage{}, errors.NewNotFoundError("user avatar not found").WithKey("user_avatar_not_found")
+	}
+
+	if size == 0 {
+		size = user.Avatar.Sizes[0]
+	}
+	if !containsInt(user.Avatar.Sizes, size) {
+		sizes := []string{}
+		for _, valid := range user.Avatar.Sizes {
+			sizes = append(sizes, strconv.Itoa(valid))
+		}
+		return domain.UserAvatarImage{}, errors.NewValidationError(fmt.Sprintf("avatar size %d is not valid, valid sizes are %s",
+			size, strings.Join(sizes, ", "))).
+			WithKey("avatar_size_not_valid", size, strings.Join(sizes, ", "))
+	}
+
+	data, err := s.store.Get(avatarKey(user.Reference, user.Avatar.Version, size))
+	if err != nil {
+		if err == blob.ErrNotFound {
+			return domain.UserAvatarImage{}, errors.NewNotFoundError("user avatar not found").WithKey("user_avatar_not_found")
+		}
+		errMsg := "unexpected error when get the user avatar"
+		logger.AppLog.Error().Err(err).Msg(errMsg)
+		return domain.UserAvatarImage{}, errors.NewFatalError(errMsg)
+	}
+
+	return domain.UserAvatarImage{
+		Data:        data,
+		ContentType: user.Avatar.ContentType,
+		Version:     user.Avatar.Version,
+		Size:        size,
+		UpdatedDate: user.Avatar.UpdatedDate,
+	}, nil
+}
+
+func containsInt(values []int, value int) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
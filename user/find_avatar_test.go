@@ -0,0 +1,82 @@
+package user
+
+import (
+	"testing"
+
+	"github.com/desarrollogj/golang-api-example/domain"
+	"github.com/desarrollogj/golang-api-example/libs/blob"
+	"github.com/stretchr/testify/assert"
+)
+
+func avatarUser(reference string) domain.User {
+	return domain.User{
+		GenericEntity: domain.GenericEntity{Reference: reference, IsActive: true},
+		Avatar:        &domain.UserAvatar{Version: "V1", ContentType: "image/png", Sizes: []int{256, 64}},
+	}
+}
+
+func TestFindAvatar_GivenAnUserWithAvatar_WhenExecute_ThenReturnTheThumbnail(t *testing.T) {
+	t.Log("Successfully get an User avatar")
+
+	reference := "REF1"
+	repositoryMock := new(repositoryMock)
+	repositoryMock.On("FindActiveByReference", reference).Return(avatarUser(reference), nil)
+	store := blob.NewLocalBlobStore(t.TempDir())
+	assert.Nil(t, store.Put(avatarKey(reference, "V1", 256), []byte("large")))
+	assert.Nil(t, store.Put(avatarKey(reference, "V1", 64), []byte("small")))
+
+	useCase := NewDefaultFindAvatar(repositoryMock, store)
+
+	image, err := useCase.Execute(reference, 0)
+	assert.Nil(t, err)
+	assert.Equal(t, []byte("large"), image.Data)
+	assert.Equal(t, 256, image.Size)
+	assert.Equal(t, "image/png", image.ContentType)
+	assert.Equal(t, "V1", image.Version)
+
+	image, err = useCase.Execute(reference, 64)
+	assert.Nil(t, err)
+	assert.Equal(t, []byte("small"), image.Data)
+}
+
+func TestFindAvatar_GivenAnInvalidSize_WhenExecute_ThenReturnAValidationError(t *testing.T) {
+	t.Log("Failure to get an User avatar because the size is not valid")
+
+	repositoryMock := new(repositoryMock)
+	repositoryMock.On("FindActiveByReference", "REF1").Return(avatarUser("REF1"), nil)
+
+	useCase := NewDefaultFindAvatar(repositoryMock, blob.NewLocalBlobStore(t.TempDir()))
+
+	_, err := useCase.Execute("REF1", 100)
+
+	assert.NotNil(t, err)
+	assert.Equal(t, "avatar size 100 is not valid, valid sizes are 256, 64", err.Error())
+}
+
+func TestFindAvatar_GivenAnUserWithoutAvatar_WhenExecute_ThenReturnANotFoundError(t *testing.T) {
+	t.Log("Failure to get an User avatar because the user has no avatar")
+
+	repositoryMock := new(repositoryMock)
+	repositoryMock.On("FindActiveByReference", "REF1").Return(domain.User{GenericEntity: domain.GenericEntity{Reference: "REF1"}}, nil)
+
+	useCase := NewDefaultFindAvatar(repositoryMock, blob.NewLocalBlobStore(t.TempDir()))
+
+	_, err := useCase.Execute("REF1", 0)
+
+	assert.NotNil(t, err)
+	assert.Equal(t, "user avatar not found", err.Error())
+}
+
+func TestFindAvatar_GivenAMissingThumbnail_WhenExecute_ThenReturnANotFoundError(t *testing.T) {
+	t.Log("Failure to get an User avatar because the thumbnail is not stored")
+
+	repositoryMock := new(repositoryMock)
+	repositoryMock.On("FindActiveByReference", "REF1").Return(avatarUser("REF1"), nil)
+
+	useCase := NewDefaultFindAvatar(repositoryMock, blob.NewLocalBlobStore(t.TempDir()))
+
+	_, err := useCase.Execute("REF1", 0)
+
+	assert.NotNil(t, err)
+	assert.Equal(t, "user avatar not found", err.Error())
+}
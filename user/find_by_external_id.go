@@ -0,0 +1,52 @@
+package user
+
+import (
+	"fmt"
+
+	"github.com/desarrollogj/golang-api-example/domain"
+	"github.com/desarrollogj/golang-api-example/infrastructure"
+	"github.com/desarrollogj/golang-api-example/libs/errors"
+	"github.com/desarrollogj/golang-api-example/libs/logger"
+)
+
+// FindByExternalID represents the method to be implemented to get an user by one of its external ids
+type FindByExternalID interface {
+	Execute(externalID domain.UserExternalID) (domain.User, error)
+}
+
+// defaultFindByExternalID is the default implementation of FindByExternalID interface
+type defaultFindByExternalID struct {
+	repository infrastructure.UserRepository
+}
+
+// NewDefaultFindByExternalID creates a defaultFindByExternalID instance
+func NewDefaultFindByExternalID(repository infrastructure.UserRepository) defaultFindByExternalID {
+	return defaultFindByExternalID{
+		repository: repository,
+	}
+}
+
+// Execute get an active user by one of its external ids. The deleted users release their external ids, but the merged
+// users keep them, so an user that was merged into another user returns the user it was merged into
+func (s defaultFindByExternalID) Execute(externalID domain.UserExternalID) (domain.User, error) {
+	externalID, err := NormalizeExternalID(externalID)
+	if err != nil {
+		return domain.User{}, err
+	}
+
+	user, err := s.repository.FindByExternalID(externalID.Source, externalID.ID)
+	if err == nil && len(user.Reference) > 0 && !user.IsActive {
+		user, err = findMergedInto(s.repository, user.Reference)
+	}
+	if err != nil {
+		errMsg := fmt.Sprintf("unexpected error when try to get user with external id %s/%s", externalID.Source, externalID.ID)
+		logger.AppLog.Error().Err(err).Msg(errMsg)
+		return domain.User{}, errors.NewFatalError(errMsg)
+	}
+
+	if len(user.Reference) == 0 {
+		return domain.User{}, errors.NewNotFoundError("user not found").WithKey("user_not_found")
+	}
+
+	return user, nil
+}
@@ -0,0 +1,116 @@
+package user
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/desarrollogj/golang-api-example/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindByExternalID_GivenAnExternalID_WhenExecute_ThenGetAnUser(t *testing.T) {
+	t.Log("Successfully get an User by its external id")
+
+	user := domain.User{
+		GenericEntity: domain.GenericEntity{
+			Reference: "USER1",
+			IsActive:  true,
+		},
+		ExternalIDs: []domain.UserExternalID{{Source: "crm", ID: "C-1"}},
+	}
+	repositoryMock := new(repositoryMock)
+	repositoryMock.On("FindByExternalID", "crm", "C-1").Return(user, nil)
+
+	useCase := NewDefaultFindByExternalID(repositoryMock)
+
+	foundUser, err := useCase.Execute(domain.UserExternalID{Source: "CRM", ID: "C-1"})
+
+	assert.Nil(t, err)
+	assert.Equal(t, user, foundUser)
+
+	repositoryMock.AssertExpectations(t)
+}
+
+func TestFindByExternalID_GivenAnUnknownExternalID_WhenExecute_ThenReturnNotFoundError(t *testing.T) {
+	t.Log("Failure to get an User by an external id that nobody has")
+
+	repositoryMock := new(repositoryMock)
+	repositoryMock.On("FindByExternalID", "crm", "C-1").Return(domain.User{}, nil)
+
+	useCase := NewDefaultFindByExternalID(repositoryMock)
+
+	_, err := useCase.Execute(domain.UserExternalID{Source: "crm", ID: "C-1"})
+
+	assert.NotNil(t, err)
+	assert.Equal(t, "user not found", err.Error())
+
+	repositoryMock.AssertExpectations(t)
+}
+
+func TestFindByExternalID_GivenADeletedUserExternalID_WhenExecute_ThenReturnNotFoundError(t *testing.T) {
+	t.Log("Failure to get an User by its external id because the user is deleted")
+
+	user := domain.User{GenericEntity: domain.GenericEntity{Reference: "USER1", IsActive: false}}
+	repositoryMock := new(repositoryMock)
+	repositoryMock.On("FindByExternalID", "crm", "C-1").Return(user, nil)
+	repositoryMock.On("FindByReference", "USER1").Return(user, nil)
+
+	useCase := NewDefaultFindByExternalID(repositoryMock)
+
+	_, err := useCase.Execute(domain.UserExternalID{Source: "crm", ID: "C-1"})
+
+	assert.NotNil(t, err)
+	assert.Equal(t, "user not found", err.Error())
+
+	repositoryMock.AssertExpectations(t)
+}
+
+func TestFindByExternalID_GivenAMergedUserExternalID_WhenExecute_ThenGetTheUserItWasMergedInto(t *testing.T) {
+	t.Log("Successfully get the surviving User by the external id of a merged duplicate")
+
+	merged := domain.User{GenericEntity: domain.GenericEntity{Reference: "USER2", IsActive: false}, MergedInto: "USER1"}
+	survivor := domain.User{GenericEntity: domain.GenericEntity{Reference: "USER1", IsActive: true}}
+	repositoryMock := new(repositoryMock)
+	repositoryMock.On("FindByExternalID", "crm", "C-2").Return(merged, nil)
+	repositoryMock.On("FindByReference", "USER2").Return(merged, nil)
+	repositoryMock.On("FindActiveByReference", "USER1").Return(survivor, nil)
+
+	useCase := NewDefaultFindByExternalID(repositoryMock)
+
+	foundUser, err := useCase.Execute(domain.UserExternalID{Source: "crm", ID: "C-2"})
+
+	assert.Nil(t, err)
+	assert.Equal(t, survivor, foundUser)
+
+	repositoryMock.AssertExpectations(t)
+}
+
+func TestFindByExternalID_GivenANotValidExternalID_WhenExecute_ThenReturnAValidationError(t *testing.T) {
+	t.Log("Failure to get an User by a not valid external id")
+
+	repositoryMock := new(repositoryMock)
+
+	useCase := NewDefaultFindByExternalID(repositoryMock)
+
+	_, err := useCase.Execute(domain.UserExternalID{Source: "my crm", ID: "C-1"})
+
+	assert.NotNil(t, err)
+	assert.Equal(t, "external id source my crm is not valid", err.Error())
+	repositoryMock.AssertNotCalled(t, "FindByExternalID", "my crm", "C-1")
+}
+
+func TestFindByExternalID_GivenAnExternalID_WhenExecute_AndRepositoryReturnedAnError_ThenReturnFatalError(t *testing.T) {
+	t.Log("Failure to get an User by its external id because repository returned an error")
+
+	repositoryMock := new(repositoryMock)
+	repositoryMock.On("FindByExternalID", "crm", "C-1").Return(domain.User{}, errors.New("repository error"))
+
+	useCase := NewDefaultFindByExternalID(repositoryMock)
+
+	_, err := useCase.Execute(domain.UserExternalID{Source: "crm", ID: "C-1"})
+
+	assert.NotNil(t, err)
+	assert.Equal(t, "unexpected error when try to get user with external id crm/C-1", err.Error())
+
+	repositoryMock.AssertExpectations(t)
+}
@@ -11,7 +11,7 @@ import (
 
 // FindByReference represents the method to be implemented to get an user by its reference
 type FindByReference interface {
-	Execute(reference string) (domain.User, error)
+	Execute(reference string, includeInactive bool) (domain.User, error)
 }
 
 // defaultFindByReference is the default implementation of FindByReference interface
@@ -26,9 +26,21 @@ func NewDefaultFindByReference(repository infrastructure.UserRepository) default
 	}
 }
 
-// Execute get an user by its reference
-func (s defaultFindByReference) Execute(reference string) (domain.User, error) {
-	user, err := s.repository.FindActiveByReference(reference)
+// maxMergeRedirects limits the merged users followed, in case an user was merged into a merged user
+const maxMergeRedirects = 5
+
+// Execute get an active user by its reference. The inactive users are only returned if it is requested.
+// An user that was merged into another user returns the user it was merged into
+func (s defaultFindByReference) Execute(reference string, includeInactive bool) (domain.User, error) {
+	find := s.repository.FindActiveByReference
+	if includeInactive {
+		find = s.repository.FindByReference
+	}
+
+	user, err := find(reference)
+	if err == nil && len(user.Reference) == 0 && !includeInactive {
+		user, err = findMergedInto(s.repository, reference)
+	}
 	if err != nil {
 		errMsg := fmt.Sprintf("unexpected error when try to get user with reference %s", reference)
 		logger.AppLog.Error().Err(err).Msg(errMsg)
@@ -36,8 +48,26 @@ func (s defaultFindByReference) Execute(reference string) (domain.User, error) {
 	}
 
 	if len(user.Reference) == 0 {
-		return domain.User{}, errors.NewNotFoundError("user not found")
+		return domain.User{}, errors.NewNotFoundError("user not found").WithKey("user_not_found")
 	}
 
 	return user, nil
 }
+
+// findMergedInto follows the merged users until an active user is found
+func findMergedInto(repository infrastructure.UserRepository, reference string) (domain.User, error) {
+	for i := 0; i < maxMergeRedirects; i++ {
+		merged, err := repository.FindByReference(reference)
+		if err != nil || len(merged.MergedInto) == 0 {
+			return domain.User{}, err
+		}
+
+		reference = merged.MergedInto
+		survivor, err := repository.FindActiveByReference(reference)
+		if err != nil || len(survivor.Reference) > 0 {
+			return survivor, err
+		}
+	}
+
+	return domain.User{}, nil
+}
@@ -26,7 +26,7 @@ func TestFindByReference_GivenAReference_WhenExecute_ThenGetAnUser(t *testing.T)
 
 	useCase := NewDefaultFindByReference(repositoryMock)
 
-	foundUser, err := useCase.Execute(reference)
+	foundUser, err := useCase.Execute(reference, false)
 
 	assert.Nil(t, err)
 	assert.NotNil(t, foundUser)
@@ -41,10 +41,11 @@ func TestFindByReference_GivenAReference_WhenExecute_AndUsesDoesNotExist_ThenRet
 	reference := "USER1"
 	repositoryMock := new(repositoryMock)
 	repositoryMock.On("FindActiveByReference", reference).Return(domain.User{}, nil)
+	repositoryMock.On("FindByReference", reference).Return(domain.User{}, nil)
 
 	useCase := NewDefaultFindByReference(repositoryMock)
 
-	_, err := useCase.Execute(reference)
+	_, err := useCase.Execute(reference, false)
 
 	assert.NotNil(t, err)
 	assert.Equal(t, "user not found", err.Error())
@@ -61,10 +62,61 @@ func TestFindByReference_GivenAReference_WhenExecute_AndRepositoryRetunedAnError
 
 	useCase := NewDefaultFindByReference(repositoryMock)
 
-	_, err := useCase.Execute(reference)
+	_, err := useCase.Execute(reference, false)
 
 	assert.NotNil(t, err)
 	assert.Equal(t, "unexpected error when try to get user with reference USER1", err.Error())
 
 	repositoryMock.AssertExpectations(t)
 }
+
+func TestFindByReference_GivenAnInactiveUserReference_WhenExecuteIncludingInactive_ThenGetTheUser(t *testing.T) {
+	t.Log("Successfully get an inactive User by its reference")
+
+	reference := "USER1"
+	user := domain.User{
+		GenericEntity: domain.GenericEntity{
+			Reference: reference,
+			IsActive:  false,
+		},
+		Status: domain.UserStatusSuspended,
+	}
+	repositoryMock := new(repositoryMock)
+	repositoryMock.On("FindByReference", reference).Return(user, nil)
+
+	useCase := NewDefaultFindByReference(repositoryMock)
+
+	foundUser, err := useCase.Execute(reference, true)
+
+	assert.Nil(t, err)
+	assert.Equal(t, user, foundUser)
+
+	repositoryMock.AssertNotCalled(t, "FindActiveByReference", reference)
+}
+
+func TestFindByReference_GivenAMergedUserReference_WhenExecute_ThenGetTheUserItWasMergedInto(t *testing.T) {
+	t.Log("Successfully get the User that a merged User was merged into")
+
+	merged := domain.User{
+		GenericEntity: domain.GenericEntity{Reference: "USER1"},
+		Status:        domain.UserStatusDeleted,
+		MergedInto:    "USER2",
+	}
+	survivor := domain.User{
+		GenericEntity: domain.GenericEntity{Reference: "USER2", IsActive: true},
+		Status:        domain.UserStatusActive,
+	}
+	repositoryMock := new(repositoryMock)
+	repositoryMock.On("FindActiveByReference", "USER1").Return(domain.User{}, nil)
+	repositoryMock.On("FindByReference", "USER1").Return(merged, nil)
+	repositoryMock.On("FindActiveByReference", "USER2").Return(survivor, nil)
+
+	useCase := NewDefaultFindByReference(repositoryMock)
+
+	foundUser, err := useCase.Execute("USER1", false)
+
+	assert.Nil(t, err)
+	assert.Equal(t, survivor, foundUser)
+
+	repositoryMock.AssertExpectations(t)
+}
@@ -0,0 +1,199 @@
+package user
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/desarrollogj/golang-api-example/domain"
+	"github.com/desarrollogj/golang-api-example/infrastructure"
+	"github.com/desarrollogj/golang-api-example/libs/errors"
+	"github.com/desarrollogj/golang-api-example/libs/logger"
+)
+
+// FindDuplicates represents the method to be implemented to get the duplicate users report
+type FindDuplicates interface {
+	Execute() (domain.UserDuplicateReport, error)
+}
+
+// defaultFindDuplicates is the default implementation of FindDuplicates interface
+type defaultFindDuplicates struct {
+	config     domain.DuplicateDetectionConfiguration
+	repository infrastructure.UserRepository
+}
+
+// NewDefaultFindDuplicates creates a defaultFindDuplicates instance
+func NewDefaultFindDuplicates(config domain.DuplicateDetectionConfiguration, repository infrastructure.UserRepository) defaultFindDuplicates {
+	return defaultFindDuplicates{
+		config:     config,
+		repository: repository,
+	}
+}
+
+// Execute groups the active users that share the same email, ignoring case and spaces, or have similar names.
+// The names of every pair of users not grouped yet are compared, so it is meant to run in background
+func (s defaultFindDuplicates) Execute() (domain.UserDuplicateReport, error) {
+	users, err := s.repository.FindAllActive()
+	if err != nil {
+		errMsg := "unexpected error when try to get all users"
+		logger.AppLog.Error().Err(err).Msg(errMsg)
+		return domain.UserDuplicateReport{}, errors.NewFatalError(errMsg)
+	}
+
+	emails := make([]string, len(users))
+	names := make([]string, len(users))
+	for i, user := range users {
+		emails[i] = strings.ToLower(strings.TrimSpace(user.Email))
+		names[i] = normalizeName(user.FirstName + " " + user.LastName)
+	}
+
+	// Users are linked in pairs, and the groups are the connected users
+	parents := make([]int, len(users))
+	for i := range parents {
+		parents[i] = i
+	}
+	var root func(i int) int
+	root = func(i int) int {
+		if parents[i] != i {
+			parents[i] = root(parents[i])
+		}
+		return parents[i]
+	}
+	// reasons holds why each pair of users was linked
+	reasons := map[[2]int]string{}
+	link := func(i int, j int, reason string) {
+		reasons[[2]int{i, j}] = reason
+		parents[root(j)] = root(i)
+	}
+	// The users with the same email are linked to the first one, without comparing every pair
+	firstByEmail := map[string]int{}
+	for i := range users {
+		if len(emails[i]) == 0 {
+			continue
+		}
+		if first, found := firstByEmail[emails[i]]; found {
+			link(first, i, domain.UserDuplicateReasonEmail)
+		} else {
+			firstByEmail[emails[i]] = i
+		}
+	}
+	if s.config.NameSimilarityThreshold > 0 {
+		lengths := make([]int, len(users))
+		for i := range names {
+			lengths[i] = len([]rune(names[i]))
+		}
+		for i := range users {
+			for j := i + 1; j < len(users); j++ {
+				if len(names[i]) == 0 || len(names[j]) == 0 || root(i) == root(j) {
+					continue
+				}
+				// The edit distance is at least the length difference, so names too different in length are not compared
+				if 1-float64(absInt(lengths[i]-lengths[j]))/float64(maxInt(lengths[i], lengths[j])) < s.config.NameSimilarityThreshold {
+					continue
+				}
+				if nameSimilarity(names[i], names[j]) >= s.config.NameSimilarityThreshold {
+					link(i, j, domain.UserDuplicateReasonName)
+				}
+			}
+		}
+	}
+
+	members := map[int][]int{}
+	for i := range users {
+		members[root(i)] = append(members[root(i)], i)
+	}
+	groupReasons := map[int][]string{}
+	for pair, reason := range reasons {
+		r := root(pair[0])
+		if !containsString(groupReasons[r], reason) {
+			groupReasons[r] = append(groupReasons[r], reason)
+		}
+	}
+	groups := []domain.UserDuplicateGroup{}
+	for r, indexes := range members {
+		if len(indexes) < 2 {
+			continue
+		}
+		group := domain.UserDuplicateGroup{Reasons: groupReasons[r]}
+		for _, i := range indexes {
+			group.Users = append(group.Users, users[i])
+		}
+		sort.Strings(group.Reasons)
+		sort.Slice(group.Users, func(a, b int) bool {
+			return isOlder(group.Users[a], group.Users[b])
+		})
+		groups = append(groups, group)
+	}
+	sort.Slice(groups, func(a, b int) bool {
+		return isOlder(groups[a].Users[0], groups[b].Users[0])
+	})
+
+	return domain.UserDuplicateReport{
+		GeneratedDate: time.Now().UTC(),
+		Groups:        groups,
+	}, nil
+}
+
+func isOlder(a domain.User, b domain.User) bool {
+	if !a.CreatedDate.Equal(b.CreatedDate) {
+		return a.CreatedDate.Before(b.CreatedDate)
+	}
+	return a.Reference < b.Reference
+}
+
+// normalizeName returns the name in lower case, with single spaces between words
+func normalizeName(name string) string {
+	return strings.Join(strings.Fields(strings.ToLower(name)), " ")
+}
+
+// nameSimilarity returns a value between 0 (different) and 1 (equal), based on the edit distance between the names
+func nameSimilarity(a string, b string) float64 {
+	ra, rb := []rune(a), []rune(b)
+	longest := len(ra)
+	if len(rb) > longest {
+		longest = len(rb)
+	}
+	if longest == 0 {
+		return 1
+	}
+
+	previous := make([]int, len(rb)+1)
+	current := make([]int, len(rb)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		current[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			current[j] = minInt(previous[j]+1, minInt(current[j-1]+1, previous[j-1]+cost))
+		}
+		previous, current = current, previous
+	}
+
+	return 1 - float64(previous[len(rb)])/float64(longest)
+}
+
+func minInt(a int, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxInt(a int, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func absInt(a int) int {
+	if a < 0 {
+		return -a
+	}
+	return a
+}
@@ -0,0 +1,88 @@
+package user
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/desarrollogj/golang-api-example/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func duplicateCandidate(reference string, firstName string, lastName string, email string, created time.Time) domain.User {
+	return domain.User{
+		GenericEntity: domain.GenericEntity{Reference: reference, IsActive: true, CreatedDate: created},
+		FirstName:     firstName,
+		LastName:      lastName,
+		Email:         email,
+	}
+}
+
+func TestFindDuplicates_GivenDuplicateUsers_WhenExecute_ThenGroupThem(t *testing.T) {
+	t.Log("Successfully group the duplicate Users")
+
+	created := time.Now().UTC().Add(-time.Hour)
+	users := []domain.User{
+		duplicateCandidate("USER1", "Foo", "Bar", "FooBar@Email.com ", created.Add(time.Minute)),
+		duplicateCandidate("USER2", "John", "Doe", "john@email.com", created),
+		duplicateCandidate("USER3", "Other", "Name", "foobar@email.com", created),
+		duplicateCandidate("USER4", "Jonh", " doe", "johndoe@email.com", created.Add(time.Minute)),
+		duplicateCandidate("USER5", "Alice", "Smith", "alice@email.com", created),
+	}
+	repositoryMock := new(repositoryMock)
+	repositoryMock.On("FindAllActive").Return(users, nil)
+
+	useCase := NewDefaultFindDuplicates(domain.DuplicateDetectionConfiguration{NameSimilarityThreshold: 0.7}, repositoryMock)
+
+	report, err := useCase.Execute()
+
+	assert.Nil(t, err)
+	assert.False(t, report.GeneratedDate.IsZero())
+	assert.Equal(t, 2, len(report.Groups))
+	assert.Equal(t, []string{domain.UserDuplicateReasonName}, report.Groups[0].Reasons)
+	assert.Equal(t, []string{"USER2", "USER4"}, []string{report.Groups[0].Users[0].Reference, report.Groups[0].Users[1].Reference})
+	assert.Equal(t, []string{domain.UserDuplicateReasonEmail}, report.Groups[1].Reasons)
+	assert.Equal(t, []string{"USER3", "USER1"}, []string{report.Groups[1].Users[0].Reference, report.Groups[1].Users[1].Reference})
+}
+
+func TestFindDuplicates_GivenNoThreshold_WhenExecute_ThenDoNotCompareNames(t *testing.T) {
+	t.Log("Successfully group the duplicate Users only by email")
+
+	created := time.Now().UTC()
+	users := []domain.User{
+		duplicateCandidate("USER1", "Foo", "Bar", "foo@email.com", created),
+		duplicateCandidate("USER2", "Foo", "Bar", "bar@email.com", created),
+	}
+	repositoryMock := new(repositoryMock)
+	repositoryMock.On("FindAllActive").Return(users, nil)
+
+	useCase := NewDefaultFindDuplicates(domain.DuplicateDetectionConfiguration{}, repositoryMock)
+
+	report, err := useCase.Execute()
+
+	assert.Nil(t, err)
+	assert.Empty(t, report.Groups)
+}
+
+func TestFindDuplicates_GivenARepositoryError_WhenExecute_ThenReturnAFatalError(t *testing.T) {
+	t.Log("Failure to group the duplicate Users because the repository returned an error")
+
+	repositoryMock := new(repositoryMock)
+	repositoryMock.On("FindAllActive").Return(nil, errors.New("repository error"))
+
+	useCase := NewDefaultFindDuplicates(domain.DuplicateDetectionConfiguration{}, repositoryMock)
+
+	_, err := useCase.Execute()
+
+	assert.NotNil(t, err)
+	assert.Equal(t, "unexpected error when try to get all users", err.Error())
+}
+
+func TestNameSimilarity_GivenTwoNames_WhenCompare_ThenReturnTheirSimilarity(t *testing.T) {
+	t.Log("Successfully compare two names")
+
+	assert.Equal(t, 1.0, nameSimilarity("john doe", "john doe"))
+	assert.Equal(t, 0.75, nameSimilarity("john doe", "jonh doe"))
+	assert.Equal(t, 0.0, nameSimilarity("abc", "xyz"))
+	assert.Equal(t, 1.0, nameSimilarity("", ""))
+}
@@ -0,0 +1,37 @@
+package user
+
+import (
+	"github.com/desarrollogj/golang-api-example/domain"
+	"github.com/desarrollogj/golang-api-example/infrastructure"
+	"github.com/desarrollogj/golang-api-example/libs/errors"
+	"github.com/desarrollogj/golang-api-example/libs/logger"
+)
+
+// FindTags represents the method to be implemented to get the user tags
+type FindTags interface {
+	Execute() ([]domain.TagCount, error)
+}
+
+// defaultFindTags is the default implementation of FindTags interface
+type defaultFindTags struct {
+	repository infrastructure.UserRepository
+}
+
+// NewDefaultFindTags creates a defaultFindTags instance
+func NewDefaultFindTags(repository infrastructure.UserRepository) defaultFindTags {
+	return defaultFindTags{
+		repository: repository,
+	}
+}
+
+// Execute returns the tags of the active users with their number of users, most used first
+func (s defaultFindTags) Execute() ([]domain.TagCount, error) {
+	tags, err := s.repository.CountTags()
+	if err != nil {
+		errMsg := "unexpected error when try to count the user tags"
+		logger.AppLog.Error().Err(err).Msg(errMsg)
+		return nil, errors.NewFatalError(errMsg)
+	}
+
+	return tags, nil
+}
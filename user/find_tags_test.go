@@ -0,0 +1,38 @@
+package user
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/desarrollogj/golang-api-example/domain"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindTags_WhenExecute_ThenReturnTheTagCounts(t *testing.T) {
+	t.Log("Successfully find the User tags")
+
+	tags := []domain.TagCount{{Tag: "beta", Count: 2}, {Tag: "vip", Count: 1}}
+	repositoryMock := new(repositoryMock)
+	repositoryMock.On("CountTags").Return(tags, nil)
+
+	useCase := NewDefaultFindTags(repositoryMock)
+
+	found, err := useCase.Execute()
+
+	assert.Nil(t, err)
+	assert.Equal(t, tags, found)
+}
+
+func TestFindTags_WhenExecuteAndRepositoryReturnedAnError_ThenReturnAFatalError(t *testing.T) {
+	t.Log("Failure to find the User tags because repository returned an error")
+
+	repositoryMock := new(repositoryMock)
+	repositoryMock.On("CountTags").Return(nil, errors.New("repository error"))
+
+	useCase := NewDefaultFindTags(repositoryMock)
+
+	_, err := useCase.Execute()
+
+	assert.NotNil(t, err)
+	assert.Equal(t, "unexpected error when try to count the user tags", err.Error())
+}
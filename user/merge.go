@@ -0,0 +1,177 @@
+package user
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/desarrollogj/golang-api-example/domain"
+	"github.com/desarrollogj/golang-api-example/infrastructure"
+	"github.com/desarrollogj/golang-api-example/libs/errors"
+	"github.com/desarrollogj/golang-api-example/libs/logger"
+	"github.com/google/uuid"
+)
+
+// Merge represents the method to be implemented to merge a duplicate user into another user
+type Merge interface {
+	Execute(input domain.UserMergeInput) (domain.User, error)
+}
+
+// defaultMerge is the default implementation of Merge interface
+type defaultMerge struct {
+	repository      infrastructure.UserRepository
+	groupRepository infrastructure.GroupRepository
+	auditRepository infrastructure.AuditRepository
+}
+
+// NewDefaultMerge creates a defaultMerge instance
+func NewDefaultMerge(repository infrastructure.UserRepository,
+	groupRepository infrastructure.GroupRepository,
+	auditRepository infrastructure.AuditRepository) defaultMerge {
+	return defaultMerge{
+		repository:      repository,
+		groupRepository: groupRepository,
+		auditRepository: auditRepository,
+	}
+}
+
+// Execute folds the duplicate user into the surviving user. The surviving user keeps its data and roles, and takes the
+// duplicate profile data and attributes it is missing, its tags, groups and external ids. The duplicate roles are not
+// granted to the surviving user. The duplicate is deleted pointing to the surviving user after its data was moved, so a
+// merge that failed before can be retried. The external ids are unique, so they are moved after the duplicate released them
+func (s defaultMerge) Execute(input domain.UserMergeInput) (domain.User, error) {
+	if input.Reference == input.DuplicateReference {
+		return domain.User{}, errors.NewValidationError("an user can not be merged into itself").WithKey("user_merge_itself")
+	}
+
+	survivor, err := s.findUser(input.Reference)
+	if err != nil {
+		return domain.User{}, err
+	}
+	if len(survivor.Reference) == 0 {
+		return domain.User{}, errors.NewNotFoundError("user not found").WithKey("user_not_found")
+	}
+	duplicate, err := s.findUser(input.DuplicateReference)
+	if err != nil {
+		return domain.User{}, err
+	}
+	if len(duplicate.Reference) == 0 {
+		return domain.User{}, errors.NewNotFoundError("duplicate user not found").WithKey("duplicate_user_not_found")
+	}
+	externalIDs, err := normalizeExternalIDs(append(append([]domain.UserExternalID{}, survivor.ExternalIDs...), duplicate.ExternalIDs...))
+	if err != nil {
+		return domain.User{}, err
+	}
+
+	merged := time.Now().UTC()
+	mergeUser(&survivor, duplicate)
+	survivor.UpdatedDate = merged
+	updated, err := s.repository.Update(survivor)
+	if err != nil {
+		return domain.User{}, storeError(err, "unexpected error when merge the user")
+	}
+
+	if _, err := s.groupRepository.MoveMember(duplicate.Reference, survivor.Reference, merged); err != nil {
+		errMsg := "unexpected error when move the duplicate user groups"
+		logger.AppLog.Error().Err(err).Msg(errMsg)
+		return domain.User{}, errors.NewFatalError(errMsg)
+	}
+
+	duplicateExternalIDs := duplicate.ExternalIDs
+	previous := statusOf(duplicate)
+	if err := transition(&duplicate, domain.UserStatusDeleted, fmt.Sprintf("merged into %s", survivor.Reference), merged); err != nil {
+		return domain.User{}, err
+	}
+	duplicate.MergedInto = survivor.Reference
+	duplicate.ExternalIDs = nil
+	deleted, err := s.repository.ChangeStatus(duplicate, previous)
+	if err != nil {
+		return domain.User{}, storeError(err, "unexpected error when delete the duplicate user")
+	}
+
+	if len(duplicateExternalIDs) > 0 {
+		updated.ExternalIDs = externalIDs
+		updated, err = s.repository.Update(updated)
+		if err != nil {
+			// The duplicate gets its external ids back, so they still lead to the surviving user
+			deleted.ExternalIDs = duplicateExternalIDs
+			if _, restoreErr := s.repository.Update(deleted); restoreErr != nil {
+				logger.AppLog.Error().Err(restoreErr).Str("reference", deleted.Reference).Msg("unable to restore the duplicate user external ids")
+			}
+			return domain.User{}, storeError(err, "unexpected error when move the duplicate user external ids")
+		}
+	}
+
+	_, err = s.auditRepository.Create(domain.AuditEntry{
+		Reference:       uuid.NewString(),
+		Action:          domain.AuditActionUserMerge,
+		EntityType:      domain.AuditEntityUser,
+		EntityReference: updated.Reference,
+		Details: map[string]string{
+			"duplicate": duplicate.Reference,
+			"mergedBy":  input.MergedBy,
+		},
+		CreatedDate: merged,
+	})
+	if err != nil {
+		errMsg := "unexpected error when register the user merge"
+		logger.AppLog.Error().Err(err).Msg(errMsg)
+		return domain.User{}, errors.NewFatalError(errMsg)
+	}
+
+	return updated, nil
+}
+
+// findUser returns the user, or an empty user if it does not exist or was deleted
+func (s defaultMerge) findUser(reference string) (domain.User, error) {
+	user, err := s.repository.FindByReference(reference)
+	if err != nil {
+		errMsg := fmt.Sprintf("unexpected error when try to get user with reference %s", reference)
+		logger.AppLog.Error().Err(err).Msg(errMsg)
+		return domain.User{}, errors.NewFatalError(errMsg)
+	}
+	if len(user.Reference) == 0 || statusOf(user) == domain.UserStatusDeleted {
+		return domain.User{}, nil
+	}
+
+	return user, nil
+}
+
+// mergeUser completes the user with the duplicate data it is missing
+func mergeUser(user *domain.User, duplicate domain.User) {
+	if len(user.Phone) == 0 {
+		user.Phone = duplicate.Phone
+	}
+	if user.BirthDate == nil {
+		user.BirthDate = duplicate.BirthDate
+	}
+	if len(user.Locale) == 0 {
+		user.Locale = duplicate.Locale
+	}
+	if len(user.Timezone) == 0 {
+		user.Timezone = duplicate.Timezone
+	}
+	if user.Address == nil {
+		user.Address = duplicate.Address
+	}
+	// The duplicate verification only proves the same email
+	if user.EmailVerifiedDate == nil && strings.EqualFold(strings.TrimSpace(user.Email), strings.TrimSpace(duplicate.Email)) {
+		user.EmailVerifiedDate = duplicate.EmailVerifiedDate
+	}
+	for name, value := range duplicate.Attributes {
+		if _, found := user.Attributes[name]; found {
+			continue
+		}
+		if user.Attributes == nil {
+			user.Attributes = map[string]interface{}{}
+		}
+		user.Attributes[name] = value
+	}
+	for _, tag := range duplicate.Tags {
+		if !containsString(user.Tags, tag) {
+			user.Tags = append(user.Tags, tag)
+		}
+	}
+	sort.Strings(user.Tags)
+}
@@ -0,0 +1,229 @@
+package user
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/desarrollogj/golang-api-example/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestMerge_GivenADuplicateUser_WhenExecute_ThenFoldItIntoTheSurvivor(t *testing.T) {
+	t.Log("Successfully merge a duplicate User, without granting its roles to the survivor")
+
+	verified := time.Now().UTC().Add(-time.Hour)
+	survivor := domain.User{
+		GenericEntity: domain.GenericEntity{Reference: "USER1", IsActive: true},
+		UserProfile:   domain.UserProfile{Locale: "es-AR"},
+		Email:         "foobar@email.com",
+		Roles:         []string{"viewer"},
+		Tags:          []string{"vip"},
+		Attributes:    map[string]interface{}{"plan": "pro"},
+		Status:        domain.UserStatusActive,
+	}
+	duplicate := domain.User{
+		GenericEntity:     domain.GenericEntity{Reference: "USER2", IsActive: true},
+		UserProfile:       domain.UserProfile{Phone: "+5491112345678", Locale: "en-US"},
+		Email:             "FooBar@email.com",
+		EmailVerifiedDate: &verified,
+		Roles:             []string{"editor", "viewer"},
+		Tags:              []string{"beta", "vip"},
+		Attributes:        map[string]interface{}{"plan": "free", "newsletter": true},
+		Status:            domain.UserStatusActive,
+	}
+	repositoryMock := new(repositoryMock)
+	repositoryMock.On("FindByReference", "USER1").Return(survivor, nil)
+	repositoryMock.On("FindByReference", "USER2").Return(duplicate, nil)
+	mergedUser := survivor
+	repositoryMock.On("Update", mock.MatchedBy(func(user domain.User) bool {
+		return user.Reference == "USER1" &&
+			user.Phone == "+5491112345678" &&
+			user.Locale == "es-AR" &&
+			user.EmailVerifiedDate == &verified &&
+			assert.ObjectsAreEqual([]string{"viewer"}, user.Roles) &&
+			assert.ObjectsAreEqual([]string{"beta", "vip"}, user.Tags) &&
+			assert.ObjectsAreEqual(map[string]interface{}{"plan": "pro", "newsletter": true}, user.Attributes)
+	})).Return(mergedUser, nil)
+	repositoryMock.On("ChangeStatus", mock.MatchedBy(func(user domain.User) bool {
+		return user.Reference == "USER2" &&
+			user.MergedInto == "USER1" &&
+			!user.IsActive &&
+			user.Status == domain.UserStatusDeleted &&
+			user.StatusReason == "merged into USER1"
+	}), domain.UserStatusActive).Return(duplicate, nil)
+	groupRepositoryMock := new(groupRepositoryMock)
+	groupRepositoryMock.On("MoveMember", "USER2", "USER1", mock.AnythingOfType("time.Time")).Return(int64(2), nil)
+	auditRepositoryMock := new(auditRepositoryMock)
+	auditRepositoryMock.On("Create", mock.MatchedBy(func(entry domain.AuditEntry) bool {
+		return entry.Action == domain.AuditActionUserMerge &&
+			entry.EntityReference == "USER1" &&
+			entry.Details["duplicate"] == "USER2" &&
+			entry.Details["mergedBy"] == "ADMIN"
+	})).Return(domain.AuditEntry{}, nil)
+
+	useCase := NewDefaultMerge(repositoryMock, groupRepositoryMock, auditRepositoryMock)
+
+	merged, err := useCase.Execute(domain.UserMergeInput{Reference: "USER1", DuplicateReference: "USER2", MergedBy: "ADMIN"})
+
+	assert.Nil(t, err)
+	assert.Equal(t, mergedUser, merged)
+
+	repositoryMock.AssertExpectations(t)
+	groupRepositoryMock.AssertExpectations(t)
+	auditRepositoryMock.AssertExpectations(t)
+}
+
+func TestMerge_GivenTheSameUser_WhenExecute_ThenReturnAValidationError(t *testing.T) {
+	t.Log("Failure to merge an User into itself")
+
+	repositoryMock := new(repositoryMock)
+
+	useCase := NewDefaultMerge(repositoryMock, new(groupRepositoryMock), new(auditRepositoryMock))
+
+	_, err := useCase.Execute(domain.UserMergeInput{Reference: "USER1", DuplicateReference: "USER1"})
+
+	assert.NotNil(t, err)
+	assert.Equal(t, "an user can not be merged into itself", err.Error())
+	repositoryMock.AssertNotCalled(t, "FindByReference", mock.Anything)
+}
+
+func TestMerge_GivenADeletedDuplicate_WhenExecute_ThenReturnANotFoundError(t *testing.T) {
+	t.Log("Failure to merge a duplicate User because it was deleted")
+
+	repositoryMock := new(repositoryMock)
+	repositoryMock.On("FindByReference", "USER1").Return(domain.User{
+		GenericEntity: domain.GenericEntity{Reference: "USER1", IsActive: true},
+	}, nil)
+	repositoryMock.On("FindByReference", "USER2").Return(domain.User{
+		GenericEntity: domain.GenericEntity{Reference: "USER2"},
+		Status:        domain.UserStatusDeleted,
+		MergedInto:    "USER1",
+	}, nil)
+
+	useCase := NewDefaultMerge(repositoryMock, new(groupRepositoryMock), new(auditRepositoryMock))
+
+	_, err := useCase.Execute(domain.UserMergeInput{Reference: "USER1", DuplicateReference: "USER2"})
+
+	assert.NotNil(t, err)
+	assert.Equal(t, "duplicate user not found", err.Error())
+	repositoryMock.AssertNotCalled(t, "Update", mock.Anything)
+}
+
+func TestMerge_GivenANonExistingSurvivor_WhenExecute_ThenReturnANotFoundError(t *testing.T) {
+	t.Log("Failure to merge a duplicate User because the surviving user does not exist")
+
+	repositoryMock := new(repositoryMock)
+	repositoryMock.On("FindByReference", "USER1").Return(domain.User{}, nil)
+
+	useCase := NewDefaultMerge(repositoryMock, new(groupRepositoryMock), new(auditRepositoryMock))
+
+	_, err := useCase.Execute(domain.UserMergeInput{Reference: "USER1", DuplicateReference: "USER2"})
+
+	assert.NotNil(t, err)
+	assert.Equal(t, "user not found", err.Error())
+}
+
+func TestMerge_GivenAGroupRepositoryError_WhenExecute_ThenReturnAFatalErrorAndKeepTheDuplicate(t *testing.T) {
+	t.Log("Failure to merge a duplicate User because its groups could not be moved")
+
+	repositoryMock := new(repositoryMock)
+	repositoryMock.On("FindByReference", "USER1").Return(domain.User{
+		GenericEntity: domain.GenericEntity{Reference: "USER1", IsActive: true},
+	}, nil)
+	repositoryMock.On("FindByReference", "USER2").Return(domain.User{
+		GenericEntity: domain.GenericEntity{Reference: "USER2", IsActive: true},
+	}, nil)
+	repositoryMock.On("Update", mock.AnythingOfType("User")).Return(domain.User{}, nil).Once()
+	groupRepositoryMock := new(groupRepositoryMock)
+	groupRepositoryMock.On("MoveMember", "USER2", "USER1", mock.AnythingOfType("time.Time")).Return(int64(0), errors.New("repository error"))
+
+	useCase := NewDefaultMerge(repositoryMock, groupRepositoryMock, new(auditRepositoryMock))
+
+	_, err := useCase.Execute(domain.UserMergeInput{Reference: "USER1", DuplicateReference: "USER2"})
+
+	assert.NotNil(t, err)
+	assert.Equal(t, "unexpected error when move the duplicate user groups", err.Error())
+	repositoryMock.AssertNumberOfCalls(t, "Update", 1)
+}
+
+func TestMerge_GivenADuplicateWithExternalIDs_WhenExecute_ThenMoveThemToTheSurvivor(t *testing.T) {
+	t.Log("Successfully merge a duplicate User, moving its external ids after the duplicate released them")
+
+	survivor := domain.User{
+		GenericEntity: domain.GenericEntity{Reference: "USER1", IsActive: true},
+		ExternalIDs:   []domain.UserExternalID{{Source: "crm", ID: "C-1"}},
+		Status:        domain.UserStatusActive,
+	}
+	duplicate := domain.User{
+		GenericEntity: domain.GenericEntity{Reference: "USER2", IsActive: true},
+		ExternalIDs:   []domain.UserExternalID{{Source: "erp", ID: "42"}},
+		Status:        domain.UserStatusActive,
+	}
+	repositoryMock := new(repositoryMock)
+	repositoryMock.On("FindByReference", "USER1").Return(survivor, nil)
+	repositoryMock.On("FindByReference", "USER2").Return(duplicate, nil)
+	repositoryMock.On("Update", mock.MatchedBy(func(user domain.User) bool {
+		return user.Reference == "USER1" && len(user.ExternalIDs) == 1
+	})).Return(survivor, nil).Once()
+	repositoryMock.On("ChangeStatus", mock.MatchedBy(func(user domain.User) bool {
+		return user.Reference == "USER2" && user.MergedInto == "USER1" && user.ExternalIDs == nil
+	}), domain.UserStatusActive).Return(domain.User{GenericEntity: domain.GenericEntity{Reference: "USER2"}, MergedInto: "USER1"}, nil).Once()
+	mergedUser := survivor
+	mergedUser.ExternalIDs = []domain.UserExternalID{{Source: "crm", ID: "C-1"}, {Source: "erp", ID: "42"}}
+	repositoryMock.On("Update", mock.MatchedBy(func(user domain.User) bool {
+		return user.Reference == "USER1" && len(user.ExternalIDs) == 2
+	})).Return(mergedUser, nil).Once()
+	groupRepositoryMock := new(groupRepositoryMock)
+	groupRepositoryMock.On("MoveMember", "USER2", "USER1", mock.AnythingOfType("time.Time")).Return(int64(0), nil)
+	auditRepositoryMock := new(auditRepositoryMock)
+	auditRepositoryMock.On("Create", mock.AnythingOfType("AuditEntry")).Return(domain.AuditEntry{}, nil)
+
+	useCase := NewDefaultMerge(repositoryMock, groupRepositoryMock, auditRepositoryMock)
+
+	merged, err := useCase.Execute(domain.UserMergeInput{Reference: "USER1", DuplicateReference: "USER2"})
+
+	assert.Nil(t, err)
+	assert.Equal(t, mergedUser, merged)
+	repositoryMock.AssertExpectations(t)
+}
+
+func TestMerge_GivenTheExternalIDsCouldNotBeMoved_WhenExecute_ThenRestoreThemToTheDuplicate(t *testing.T) {
+	t.Log("Failure to merge a duplicate User because the survivor could not take its external ids")
+
+	survivor := domain.User{GenericEntity: domain.GenericEntity{Reference: "USER1", IsActive: true}, Status: domain.UserStatusActive}
+	duplicate := domain.User{
+		GenericEntity: domain.GenericEntity{Reference: "USER2", IsActive: true},
+		ExternalIDs:   []domain.UserExternalID{{Source: "erp", ID: "42"}},
+		Status:        domain.UserStatusActive,
+	}
+	deleted := domain.User{GenericEntity: domain.GenericEntity{Reference: "USER2"}, Status: domain.UserStatusDeleted, MergedInto: "USER1"}
+	repositoryMock := new(repositoryMock)
+	repositoryMock.On("FindByReference", "USER1").Return(survivor, nil)
+	repositoryMock.On("FindByReference", "USER2").Return(duplicate, nil)
+	repositoryMock.On("Update", mock.MatchedBy(func(user domain.User) bool {
+		return user.Reference == "USER1" && len(user.ExternalIDs) == 0
+	})).Return(survivor, nil).Once()
+	repositoryMock.On("ChangeStatus", mock.MatchedBy(func(user domain.User) bool {
+		return user.Reference == "USER2" && user.ExternalIDs == nil
+	}), domain.UserStatusActive).Return(deleted, nil).Once()
+	repositoryMock.On("Update", mock.MatchedBy(func(user domain.User) bool {
+		return user.Reference == "USER1" && len(user.ExternalIDs) == 1
+	})).Return(domain.User{}, errors.New("repository error")).Once()
+	repositoryMock.On("Update", mock.MatchedBy(func(user domain.User) bool {
+		return user.Reference == "USER2" && user.MergedInto == "USER1" && len(user.ExternalIDs) == 1
+	})).Return(deleted, nil).Once()
+	groupRepositoryMock := new(groupRepositoryMock)
+	groupRepositoryMock.On("MoveMember", "USER2", "USER1", mock.AnythingOfType("time.Time")).Return(int64(0), nil)
+	auditRepositoryMock := new(auditRepositoryMock)
+
+	useCase := NewDefaultMerge(repositoryMock, groupRepositoryMock, auditRepositoryMock)
+
+	_, err := useCase.Execute(domain.UserMergeInput{Reference: "USER1", DuplicateReference: "USER2"})
+
+	assert.NotNil(t, err)
+	assert.Equal(t, "unexpected error when move the duplicate user external ids", err.Error())
+	repositoryMock.AssertExpectations(t)
+	auditRepositoryMock.AssertNotCalled(t, "Create", mock.Anything)
+}
@@ -0,0 +1,62 @@
+package user
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/desarrollogj/golang-api-example/domain"
+	"github.com/desarrollogj/golang-api-example/libs/errors"
+	"github.com/desarrollogj/golang-api-example/libs/password"
+)
+
+// validatePassword checks a password against the password policy. All the unmet rules are returned in the same error
+func validatePassword(policy domain.PasswordPolicyConfiguration, value string) error {
+	if len(value) > password.MaxLength {
+		return errors.NewValidationError(fmt.Sprintf("password must have at most %d bytes", password.MaxLength)).WithKey("password_too_long", password.MaxLength)
+	}
+
+	var hasUpper, hasLower, hasDigit, hasSymbol bool
+	for _, r := range value {
+		switch {
+		case unicode.IsUpper(r):
+			hasUpper = true
+		case unicode.IsLower(r):
+			hasLower = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r):
+			hasSymbol = true
+		}
+	}
+
+	// Each unmet rule is a cause of the error, so the rules are translated one by one
+	rules := []string{}
+	causes := []errors.ErrorCause{}
+	addRule := func(rule string, param string, key string, msg string, args ...interface{}) {
+		rules = append(rules, msg)
+		causes = append(causes, errors.ErrorCause{Field: "password", Rule: rule, Param: param, Message: "password must have " + msg, Key: key, Args: args})
+	}
+	if len([]rune(value)) < policy.MinLength {
+		addRule("min", fmt.Sprint(policy.MinLength), "password_min_length", fmt.Sprintf("at least %d characters", policy.MinLength), policy.MinLength)
+	}
+	if policy.RequireUpper && !hasUpper {
+		addRule("uppercase", "", "password_uppercase_required", "an uppercase letter")
+	}
+	if policy.RequireLower && !hasLower {
+		addRule("lowercase", "", "password_lowercase_required", "a lowercase letter")
+	}
+	if policy.RequireDigit && !hasDigit {
+		addRule("digit", "", "password_digit_required", "a digit")
+	}
+	if policy.RequireSymbol && !hasSymbol {
+		addRule("symbol", "", "password_symbol_required", "a symbol")
+	}
+	if len(rules) > 0 {
+		return errors.NewValidationError(fmt.Sprintf("password must have %s", strings.Join(rules, ", "))).
+			WithKey("password_policy_not_met").
+			WithCauses(causes...)
+	}
+
+	return nil
+}
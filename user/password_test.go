@@ -0,0 +1,53 @@
+package user
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/desarrollogj/golang-api-example/domain"
+	"github.com/desarrollogj/golang-api-example/libs/errors"
+	"github.com/desarrollogj/golang-api-example/libs/i18n"
+	"github.com/stretchr/testify/assert"
+)
+
+func newPasswordPolicyMock() domain.PasswordPolicyConfiguration {
+	return domain.PasswordPolicyConfiguration{
+		MinLength:    12,
+		RequireUpper: true,
+		RequireLower: true,
+		RequireDigit: true,
+	}
+}
+
+func TestValidatePassword_GivenAValidPassword_WhenValidate_ThenReturnNoError(t *testing.T) {
+	t.Log("A password that meets the policy should be valid")
+
+	assert.Nil(t, validatePassword(newPasswordPolicyMock(), "Secret-Password1"))
+}
+
+func TestValidatePassword_GivenAWeakPassword_WhenValidate_ThenReturnAllTheUnmetRules(t *testing.T) {
+	t.Log("A password that does not meet the policy should return all the unmet rules")
+
+	policy := newPasswordPolicyMock()
+	policy.RequireSymbol = true
+
+	err := validatePassword(policy, "secret")
+
+	assert.NotNil(t, err)
+	assert.Equal(t, "password must have at least 12 characters, an uppercase letter, a digit, a symbol", err.Error())
+
+	localized := errors.HandleBusinessError(err).Localize(i18n.Spanish)
+	assert.Equal(t, "la contraseña no cumple la política de contraseñas", localized.Message)
+	assert.Len(t, localized.Causes, 4)
+	assert.Equal(t, "la contraseña debe tener al menos 12 caracteres", localized.Causes[0].Message)
+	assert.Equal(t, "la contraseña debe tener un símbolo", localized.Causes[3].Message)
+}
+
+func TestValidatePassword_GivenATooLongPassword_WhenValidate_ThenReturnAValidationError(t *testing.T) {
+	t.Log("A password longer than the hash supports should not be valid")
+
+	err := validatePassword(newPasswordPolicyMock(), "Secret-Password1"+strings.Repeat("a", 72))
+
+	assert.NotNil(t, err)
+	assert.Equal(t, "password must have at most 72 bytes", err.Error())
+}
@@ -0,0 +1,119 @@
+package user
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/desarrollogj/golang-api-example/domain"
+	"github.com/desarrollogj/golang-api-example/infrastructure"
+	"github.com/desarrollogj/golang-api-example/libs/errors"
+	"github.com/desarrollogj/golang-api-example/libs/logger"
+)
+
+// Patch represents the method to be implemented to partially update an user
+type Patch interface {
+	Execute(input domain.UserPatchInput) (domain.User, error)
+}
+
+// defaultPatch is the default implementation of Patch interface
+type defaultPatch struct {
+	config     domain.UserAttributesConfiguration
+	repository infrastructure.UserRepository
+}
+
+// NewDefaultPatch creates a defaultPatch instance
+func NewDefaultPatch(config domain.UserAttributesConfiguration, repository infrastructure.UserRepository) defaultPatch {
+	return defaultPatch{
+		config:     config,
+		repository: repository,
+	}
+}
+
+// Execute applies a patch to the current User data and stores only the changed fields.
+// The patch errors are returned without changes
+func (s defaultPatch) Execute(input domain.UserPatchInput) (domain.User, error) {
+	currentUser, err := s.repository.FindByReference(input.Reference)
+	if err != nil {
+		errMsg := fmt.Sprintf("unexpected error when try to get user with reference %s", input.Reference)
+		logger.AppLog.Error().Err(err).Msg(errMsg)
+		return domain.User{}, errors.NewFatalError(errMsg)
+	}
+	if len(currentUser.Reference) == 0 {
+		return domain.User{}, errors.NewNotFoundError("user not found").WithKey("user_not_found")
+	}
+	if currentUser.ErasedDate != nil {
+		return domain.User{}, errors.NewValidationError("user was erased and can not be updated").WithKey("user_erased")
+	}
+	if len(currentUser.MergedInto) > 0 {
+		return domain.User{}, errors.NewValidationError(fmt.Sprintf("user was merged into %s and can not be updated", currentUser.MergedInto)).WithKey("user_merged", currentUser.MergedInto)
+	}
+
+	current := domain.UserCreateInput{
+		UserProfile: currentUser.UserProfile,
+		FirstName:   currentUser.FirstName,
+		LastName:    currentUser.LastName,
+		Email:       currentUser.Email,
+		Attributes:  currentUser.Attributes,
+		Tags:        currentUser.Tags,
+		ExternalIDs: currentUser.ExternalIDs,
+	}
+	patched, err := input.Apply(current)
+	if err != nil {
+		return domain.User{}, err
+	}
+
+	profile, err := normalizeProfile(patched.UserProfile)
+	if err != nil {
+		return domain.User{}, err
+	}
+	patched.UserProfile = profile
+	patched.Attributes, err = normalizeAttributes(s.config, patched.Attributes)
+	if err != nil {
+		return domain.User{}, err
+	}
+	patched.Tags, err = normalizeTags(patched.Tags)
+	if err != nil {
+		return domain.User{}, err
+	}
+	patched.ExternalIDs, err = normalizeExternalIDs(patched.ExternalIDs)
+	if err != nil {
+		return domain.User{}, err
+	}
+
+	if reflect.DeepEqual(current, patched) && statusOf(currentUser) != domain.UserStatusDeleted {
+		return currentUser, nil
+	}
+	err = checkExternalIDsAvailable(s.repository, currentUser.Reference, patched.ExternalIDs)
+	if err != nil {
+		return domain.User{}, err
+	}
+
+	updatedDate := time.Now().UTC()
+	// As an update, patching a deleted user reactivates it. Suspended and pending users keep their status
+	if statusOf(currentUser) == domain.UserStatusDeleted {
+		if err := transition(&currentUser, domain.UserStatusActive, "", updatedDate); err != nil {
+			return domain.User{}, err
+		}
+	}
+
+	currentUser.UserProfile = patched.UserProfile
+	currentUser.FirstName = patched.FirstName
+	currentUser.LastName = patched.LastName
+	currentUser.Attributes = patched.Attributes
+	currentUser.Tags = patched.Tags
+	currentUser.ExternalIDs = patched.ExternalIDs
+	if currentUser.Email != patched.Email {
+		// A new email must be verified again
+		currentUser.EmailVerifiedDate = nil
+	}
+	currentUser.Email = patched.Email
+	currentUser.UpdatedDate = updatedDate
+
+	updated, err := s.repository.Patch(currentUser)
+	if err != nil {
+		return domain.User{}, storeError(err, "unexpected error when patch the user")
+	}
+
+	return updated, nil
+}
@@ -0,0 +1,305 @@
+package user
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/desarrollogj/golang-api-example/domain"
+	"github.com/desarrollogj/golang-api-example/infrastructure"
+	libErrors "github.com/desarrollogj/golang-api-example/libs/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newPatchCurrentUserMock(reference string) domain.User {
+	return domain.User{
+		GenericEntity: domain.GenericEntity{
+			Reference: reference,
+			IsActive:  true,
+		},
+		UserProfile: domain.UserProfile{
+			Locale: "es-AR",
+		},
+		FirstName: "Foo",
+		LastName:  "Bar",
+		Email:     "foobar@email.com",
+	}
+}
+
+func TestPatch_GivenAPatch_WhenExecute_ThenPatchTheUser(t *testing.T) {
+	t.Log("Successfully patch an User")
+
+	reference := "REF1"
+	input := domain.UserPatchInput{
+		Reference: reference,
+		Apply: func(current domain.UserCreateInput) (domain.UserCreateInput, error) {
+			current.FirstName = "Another Foo"
+			current.Phone = "+54 9 11 1234-5678"
+			return current, nil
+		},
+	}
+	patchedUser := newPatchCurrentUserMock(reference)
+	patchedUser.FirstName = "Another Foo"
+	patchedUser.Phone = "+5491112345678"
+	repositoryMock := new(repositoryMock)
+	repositoryMock.On("FindByReference", reference).Return(newPatchCurrentUserMock(reference), nil)
+	repositoryMock.On("Patch", mock.MatchedBy(func(user domain.User) bool {
+		return user.FirstName == "Another Foo" &&
+			user.LastName == "Bar" &&
+			user.Phone == "+5491112345678" &&
+			user.Locale == "es-AR" &&
+			user.IsActive &&
+			!user.UpdatedDate.IsZero()
+	})).Return(patchedUser, nil)
+
+	useCase := NewDefaultPatch(attributesConfig, repositoryMock)
+
+	patched, err := useCase.Execute(input)
+
+	assert.Nil(t, err)
+	assert.Equal(t, patchedUser, patched)
+
+	repositoryMock.AssertExpectations(t)
+}
+
+func TestPatch_GivenAPatchWithoutChanges_WhenExecute_ThenReturnTheCurrentUser(t *testing.T) {
+	t.Log("Successfully patch an User without changes, so nothing is stored")
+
+	reference := "REF1"
+	currentUser := newPatchCurrentUserMock(reference)
+	input := domain.UserPatchInput{
+		Reference: reference,
+		Apply: func(current domain.UserCreateInput) (domain.UserCreateInput, error) {
+			current.Locale = "es-ar"
+			return current, nil
+		},
+	}
+	repositoryMock := new(repositoryMock)
+	repositoryMock.On("FindByReference", reference).Return(currentUser, nil)
+
+	useCase := NewDefaultPatch(attributesConfig, repositoryMock)
+
+	patched, err := useCase.Execute(input)
+
+	assert.Nil(t, err)
+	assert.Equal(t, currentUser, patched)
+
+	repositoryMock.AssertExpectations(t)
+	repositoryMock.AssertNotCalled(t, "Patch", mock.Anything)
+}
+
+func TestPatch_GivenAPatch_WhenExecuteAndUserNotFound_ThenReturnANotFoundError(t *testing.T) {
+	t.Log("Failure to patch an User because it was not found")
+
+	reference := "REF1"
+	repositoryMock := new(repositoryMock)
+	repositoryMock.On("FindByReference", reference).Return(domain.User{}, nil)
+
+	useCase := NewDefaultPatch(attributesConfig, repositoryMock)
+
+	_, err := useCase.Execute(domain.UserPatchInput{Reference: reference})
+
+	assert.NotNil(t, err)
+	assert.Equal(t, "user not found", err.Error())
+
+	repositoryMock.AssertExpectations(t)
+}
+
+func TestPatch_GivenAnErasedUser_WhenExecute_ThenReturnAValidationError(t *testing.T) {
+	t.Log("Failure to patch an User because it was erased")
+
+	reference := "REF1"
+	erasedDate := time.Now().UTC()
+	currentUser := newPatchCurrentUserMock(reference)
+	currentUser.ErasedDate = &erasedDate
+	repositoryMock := new(repositoryMock)
+	repositoryMock.On("FindByReference", reference).Return(currentUser, nil)
+
+	useCase := NewDefaultPatch(attributesConfig, repositoryMock)
+
+	_, err := useCase.Execute(domain.UserPatchInput{Reference: reference})
+
+	assert.NotNil(t, err)
+	assert.Equal(t, "user was erased and can not be updated", err.Error())
+
+	repositoryMock.AssertExpectations(t)
+}
+
+func TestPatch_GivenAMergedUser_WhenExecute_ThenReturnAValidationError(t *testing.T) {
+	t.Log("Failure to patch an User because it was merged into another User")
+
+	reference := "REF1"
+	currentUser := newPatchCurrentUserMock(reference)
+	currentUser.Status = domain.UserStatusDeleted
+	currentUser.MergedInto = "REF2"
+	repositoryMock := new(repositoryMock)
+	repositoryMock.On("FindByReference", reference).Return(currentUser, nil)
+
+	useCase := NewDefaultPatch(attributesConfig, repositoryMock)
+
+	_, err := useCase.Execute(domain.UserPatchInput{Reference: reference})
+
+	assert.NotNil(t, err)
+	assert.Equal(t, "user was merged into REF2 and can not be updated", err.Error())
+
+	repositoryMock.AssertExpectations(t)
+}
+
+func TestPatch_GivenAFailedPatch_WhenExecute_ThenReturnThePatchError(t *testing.T) {
+	t.Log("Failure to patch an User because the patch can not be applied")
+
+	reference := "REF1"
+	input := domain.UserPatchInput{
+		Reference: reference,
+		Apply: func(current domain.UserCreateInput) (domain.UserCreateInput, error) {
+			return domain.UserCreateInput{}, libErrors.NewConflictError("patch test operation failed")
+		},
+	}
+	repositoryMock := new(repositoryMock)
+	repositoryMock.On("FindByReference", reference).Return(newPatchCurrentUserMock(reference), nil)
+
+	useCase := NewDefaultPatch(attributesConfig, repositoryMock)
+
+	_, err := useCase.Execute(input)
+
+	assert.NotNil(t, err)
+	assert.Equal(t, libErrors.NewConflictError("patch test operation failed"), err)
+
+	repositoryMock.AssertExpectations(t)
+}
+
+func TestPatch_GivenAPatchWithNotValidProfile_WhenExecute_ThenReturnAValidationError(t *testing.T) {
+	t.Log("Failure to patch an User because the patched profile is not valid")
+
+	reference := "REF1"
+	input := domain.UserPatchInput{
+		Reference: reference,
+		Apply: func(current domain.UserCreateInput) (domain.UserCreateInput, error) {
+			current.Timezone = "America/Nowhere"
+			return current, nil
+		},
+	}
+	repositoryMock := new(repositoryMock)
+	repositoryMock.On("FindByReference", reference).Return(newPatchCurrentUserMock(reference), nil)
+
+	useCase := NewDefaultPatch(attributesConfig, repositoryMock)
+
+	_, err := useCase.Execute(input)
+
+	assert.NotNil(t, err)
+	assert.Equal(t, "timezone must be a valid IANA time zone name", err.Error())
+
+	repositoryMock.AssertExpectations(t)
+}
+
+func TestPatch_GivenAPatch_WhenExecuteAndPatchReturnedAnError_ThenReturnAFatalError(t *testing.T) {
+	t.Log("Failure to patch an User because repository returned an error")
+
+	reference := "REF1"
+	input := domain.UserPatchInput{
+		Reference: reference,
+		Apply: func(current domain.UserCreateInput) (domain.UserCreateInput, error) {
+			current.FirstName = "Another Foo"
+			return current, nil
+		},
+	}
+	repositoryMock := new(repositoryMock)
+	repositoryMock.On("FindByReference", reference).Return(newPatchCurrentUserMock(reference), nil)
+	repositoryMock.On("Patch", mock.AnythingOfType("User")).Return(domain.User{}, errors.New("repository error"))
+
+	useCase := NewDefaultPatch(attributesConfig, repositoryMock)
+
+	_, err := useCase.Execute(input)
+
+	assert.NotNil(t, err)
+	assert.Equal(t, "unexpected error when patch the user", err.Error())
+
+	repositoryMock.AssertExpectations(t)
+}
+
+func TestPatch_GivenAPatch_WhenExecuteAndUserWasModified_ThenReturnAConflictError(t *testing.T) {
+	t.Log("Failure to patch an User because it was modified while it was patched")
+
+	reference := "REF1"
+	input := domain.UserPatchInput{
+		Reference: reference,
+		Apply: func(current domain.UserCreateInput) (domain.UserCreateInput, error) {
+			current.FirstName = "Another Foo"
+			return current, nil
+		},
+	}
+	repositoryMock := new(repositoryMock)
+	repositoryMock.On("FindByReference", reference).Return(newPatchCurrentUserMock(reference), nil)
+	repositoryMock.On("Patch", mock.AnythingOfType("User")).Return(domain.User{}, infrastructure.ErrUserModified)
+
+	useCase := NewDefaultPatch(attributesConfig, repositoryMock)
+
+	_, err := useCase.Execute(input)
+
+	assert.NotNil(t, err)
+	assert.Equal(t, libErrors.ConflictErrorCode, err.(*libErrors.BusinessError).Err)
+
+	repositoryMock.AssertExpectations(t)
+}
+
+func TestPatch_GivenADeletedOrSuspendedUser_WhenExecute_ThenOnlyReactivateDeletedUsers(t *testing.T) {
+	t.Log("Patching a deleted User reactivates it, as an update does, but a suspended User keeps its status")
+
+	cases := map[domain.UserStatus]domain.UserStatus{
+		domain.UserStatusDeleted:   domain.UserStatusActive,
+		domain.UserStatusSuspended: domain.UserStatusSuspended,
+	}
+	for current, expected := range cases {
+		reference := "REF1"
+		input := domain.UserPatchInput{
+			Reference: reference,
+			Apply: func(current domain.UserCreateInput) (domain.UserCreateInput, error) {
+				current.FirstName = "Another Foo"
+				return current, nil
+			},
+		}
+		currentUser := newPatchCurrentUserMock(reference)
+		currentUser.IsActive = false
+		currentUser.Status = current
+		repositoryMock := new(repositoryMock)
+		repositoryMock.On("FindByReference", reference).Return(currentUser, nil)
+		repositoryMock.On("Patch", mock.MatchedBy(func(user domain.User) bool {
+			return user.Status == expected && user.IsActive == (expected == domain.UserStatusActive)
+		})).Return(currentUser, nil)
+
+		useCase := NewDefaultPatch(attributesConfig, repositoryMock)
+
+		_, err := useCase.Execute(input)
+
+		assert.Nil(t, err, current)
+		repositoryMock.AssertExpectations(t)
+	}
+}
+
+func TestPatch_GivenAnAttributesPatch_WhenExecute_ThenPatchTheAttributes(t *testing.T) {
+	t.Log("Successfully patch the User custom attributes")
+
+	reference := "REF1"
+	currentUser := newPatchCurrentUserMock(reference)
+	currentUser.Attributes = map[string]interface{}{"plan": "free"}
+	input := domain.UserPatchInput{
+		Reference: reference,
+		Apply: func(current domain.UserCreateInput) (domain.UserCreateInput, error) {
+			current.Attributes = map[string]interface{}{"plan": "pro", "seats": 3.0}
+			return current, nil
+		},
+	}
+	repositoryMock := new(repositoryMock)
+	repositoryMock.On("FindByReference", reference).Return(currentUser, nil)
+	repositoryMock.On("Patch", mock.MatchedBy(func(user domain.User) bool {
+		return assert.ObjectsAreEqual(map[string]interface{}{"plan": "pro", "seats": 3.0}, user.Attributes)
+	})).Return(currentUser, nil)
+
+	useCase := NewDefaultPatch(attributesConfig, repositoryMock)
+
+	_, err := useCase.Execute(input)
+
+	assert.Nil(t, err)
+	repositoryMock.AssertExpectations(t)
+}
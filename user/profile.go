@@ -0,0 +1,102 @@
+package user
+
+import (
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/desarrollogj/golang-api-example/domain"
+	"github.com/desarrollogj/golang-api-example/libs/errors"
+	"golang.org/x/text/language"
+)
+
+var (
+	phoneSeparators = strings.NewReplacer(" ", "", "-", "", ".", "", "(", "", ")", "")
+	e164Pattern     = regexp.MustCompile(`^\+[1-9][0-9]{7,14}$`)
+	countryPattern  = regexp.MustCompile(`^[A-Z]{2}$`)
+	minBirthDate    = time.Date(1900, 1, 1, 0, 0, 0, 0, time.UTC)
+)
+
+// normalizeProfile validates the user profile and returns it normalized:
+// the phone in E.164 format, the locale as a canonical BCP 47 tag and the country code in upper case
+func normalizeProfile(profile domain.UserProfile) (domain.UserProfile, error) {
+	normalized := profile
+
+	if len(profile.Phone) > 0 {
+		phone, err := NormalizePhone(profile.Phone)
+		if err != nil {
+			return domain.UserProfile{}, err
+		}
+		normalized.Phone = phone
+	}
+
+	if profile.BirthDate != nil {
+		birthDate := time.Date(profile.BirthDate.Year(), profile.BirthDate.Month(), profile.BirthDate.Day(), 0, 0, 0, 0, time.UTC)
+		if birthDate.Before(minBirthDate) || birthDate.After(time.Now().UTC()) {
+			return domain.UserProfile{}, fieldValidationError("birthDate", "range", "1900-01-01", "user_birth_date_not_valid", "birth date must be between 1900-01-01 and today")
+		}
+		normalized.BirthDate = &birthDate
+	}
+
+	if len(profile.Locale) > 0 {
+		locale, err := NormalizeLocale(profile.Locale)
+		if err != nil {
+			return domain.UserProfile{}, err
+		}
+		normalized.Locale = locale
+	}
+
+	if len(profile.Timezone) > 0 {
+		// Local is accepted by LoadLocation, but it is not an IANA time zone name
+		if _, err := time.LoadLocation(profile.Timezone); err != nil || profile.Timezone == "Local" {
+			return domain.UserProfile{}, fieldValidationError("timezone", "timezone", "", "user_timezone_not_valid", "timezone must be a valid IANA time zone name")
+		}
+	}
+
+	if profile.Address != nil {
+		address := *profile.Address
+		address.Country = NormalizeCountry(address.Country)
+		if !countryPattern.MatchString(address.Country) {
+			return domain.UserProfile{}, fieldValidationError("address.country", "iso3166_1_alpha2", "", "user_country_not_valid", "address country must be an ISO 3166-1 alpha-2 code")
+		}
+		normalized.Address = &address
+	}
+
+	return normalized, nil
+}
+
+// NormalizePhone removes the phone separators and checks it is an international number in E.164 format
+func NormalizePhone(phone string) (string, error) {
+	normalized := phoneSeparators.Replace(strings.TrimSpace(phone))
+	if strings.HasPrefix(normalized, "00") {
+		normalized = "+" + strings.TrimPrefix(normalized, "00")
+	}
+	if !e164Pattern.MatchString(normalized) {
+		return "", fieldValidationError("phone", "e164", "", "user_phone_not_valid", "phone must be an international number in E.164 format")
+	}
+
+	return normalized, nil
+}
+
+// NormalizeLocale returns the canonical BCP 47 tag of a locale
+func NormalizeLocale(locale string) (string, error) {
+	tag, err := language.Parse(strings.TrimSpace(locale))
+	if err != nil {
+		return "", fieldValidationError("locale", "bcp47_language_tag", "", "user_locale_not_valid", "locale must be a valid BCP 47 language tag")
+	}
+
+	return tag.String(), nil
+}
+
+// NormalizeCountry returns a country code in upper case
+func NormalizeCountry(country string) string {
+	return strings.ToUpper(strings.TrimSpace(country))
+}
+
+// fieldValidationError creates a validation error with the invalid field as its cause.
+// The message is translated with its key and arguments
+func fieldValidationError(field string, rule string, param string, key string, msg string, args ...interface{}) *errors.BusinessError {
+	return errors.NewValidationError(msg).
+		WithKey(key, args...).
+		WithCauses(errors.ErrorCause{Field: field, Rule: rule, Param: param, Message: msg, Key: key, Args: args})
+}
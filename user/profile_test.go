@@ -0,0 +1,88 @@
+package user
+
+import (
+	"testing"
+	"time"
+
+	"github.com/desarrollogj/golang-api-example/domain"
+	"github.com/desarrollogj/golang-api-example/libs/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeProfile_GivenAValidProfile_WhenNormalize_ThenReturnNormalizedProfile(t *testing.T) {
+	t.Log("Successfully normalize an user profile")
+
+	birthDate := time.Date(1990, 5, 17, 15, 30, 0, 0, time.UTC)
+	profile := domain.UserProfile{
+		Phone:     "+54 9 (11) 1234-5678",
+		BirthDate: &birthDate,
+		Locale:    "es-ar",
+		Timezone:  "America/Argentina/Buenos_Aires",
+		Address:   &domain.Address{Line1: "Street 123", City: "Buenos Aires", Country: "ar"},
+	}
+	expectedBirthDate := time.Date(1990, 5, 17, 0, 0, 0, 0, time.UTC)
+	expectedProfile := domain.UserProfile{
+		Phone:     "+5491112345678",
+		BirthDate: &expectedBirthDate,
+		Locale:    "es-AR",
+		Timezone:  "America/Argentina/Buenos_Aires",
+		Address:   &domain.Address{Line1: "Street 123", City: "Buenos Aires", Country: "AR"},
+	}
+
+	normalized, err := normalizeProfile(profile)
+
+	assert.Nil(t, err)
+	assert.Equal(t, expectedProfile, normalized)
+	assert.Equal(t, "ar", profile.Address.Country)
+}
+
+func TestNormalizeProfile_GivenAnEmptyProfile_WhenNormalize_ThenReturnEmptyProfile(t *testing.T) {
+	t.Log("Successfully normalize an empty user profile")
+
+	normalized, err := normalizeProfile(domain.UserProfile{})
+
+	assert.Nil(t, err)
+	assert.Equal(t, domain.UserProfile{}, normalized)
+}
+
+func TestNormalizeProfile_GivenANotValidProfile_WhenNormalize_ThenReturnAValidationError(t *testing.T) {
+	t.Log("Failure to normalize an user profile with not valid data")
+
+	future := time.Now().UTC().AddDate(0, 0, 2)
+	old := time.Date(1899, 12, 31, 0, 0, 0, 0, time.UTC)
+	cases := map[string]struct {
+		profile domain.UserProfile
+		field   string
+		message string
+	}{
+		"local phone":   {domain.UserProfile{Phone: "11 1234-5678"}, "phone", "phone must be an international number in E.164 format"},
+		"short phone":   {domain.UserProfile{Phone: "+54 11"}, "phone", "phone must be an international number in E.164 format"},
+		"letters phone": {domain.UserProfile{Phone: "+54 11 CALL-ME"}, "phone", "phone must be an international number in E.164 format"},
+		"future birth":  {domain.UserProfile{BirthDate: &future}, "birthDate", "birth date must be between 1900-01-01 and today"},
+		"old birth":     {domain.UserProfile{BirthDate: &old}, "birthDate", "birth date must be between 1900-01-01 and today"},
+		"locale":        {domain.UserProfile{Locale: "not a locale"}, "locale", "locale must be a valid BCP 47 language tag"},
+		"timezone":      {domain.UserProfile{Timezone: "America/Nowhere"}, "timezone", "timezone must be a valid IANA time zone name"},
+		"local zone":    {domain.UserProfile{Timezone: "Local"}, "timezone", "timezone must be a valid IANA time zone name"},
+		"country":       {domain.UserProfile{Address: &domain.Address{Country: "ARG"}}, "address.country", "address country must be an ISO 3166-1 alpha-2 code"},
+	}
+
+	for name, c := range cases {
+		_, err := normalizeProfile(c.profile)
+
+		assert.NotNil(t, err, name)
+		assert.Equal(t, c.message, err.Error(), name)
+		causes := err.(*errors.BusinessError).Causes
+		assert.Len(t, causes, 1, name)
+		assert.Equal(t, c.field, causes[0].Field, name)
+		assert.Equal(t, c.message, causes[0].Message, name)
+	}
+}
+
+func TestNormalizePhone_GivenAnInternationalPrefix_WhenNormalize_ThenReturnE164Phone(t *testing.T) {
+	t.Log("Successfully normalize a phone with 00 international prefix")
+
+	phone, err := NormalizePhone("0054 11 1234 5678")
+
+	assert.Nil(t, err)
+	assert.Equal(t, "+541112345678", phone)
+}
@@ -0,0 +1,68 @@
+package user
+
+import (
+	"time"
+
+	"github.com/desarrollogj/golang-api-example/domain"
+	"github.com/desarrollogj/golang-api-example/infrastructure"
+	"github.com/desarrollogj/golang-api-example/libs/errors"
+	"github.com/desarrollogj/golang-api-example/libs/logger"
+)
+
+// RemoveTag represents the method to be implemented to remove a tag from an user
+type RemoveTag interface {
+	Execute(input domain.UserTagInput) (domain.User, error)
+}
+
+// defaultRemoveTag is the default implementation of RemoveTag interface
+type defaultRemoveTag struct {
+	repository infrastructure.UserRepository
+}
+
+// NewDefaultRemoveTag creates a defaultRemoveTag instance
+func NewDefaultRemoveTag(repository infrastructure.UserRepository) defaultRemoveTag {
+	return defaultRemoveTag{
+		repository: repository,
+	}
+}
+
+// Execute remove a tag from an User. The tag is removed atomically, so the concurrent changes of the user are kept
+func (s defaultRemoveTag) Execute(input domain.UserTagInput) (domain.User, error) {
+	tag, err := NormalizeTag(input.Tag)
+	if err != nil {
+		return domain.User{}, err
+	}
+
+	currentUser, err := findUserToTag(s.repository, input.Reference)
+	if err != nil {
+		return domain.User{}, err
+	}
+	if !containsString(currentUser.Tags, tag) {
+		return domain.User{}, errors.NewNotFoundError("user tag not found").WithKey("user_tag_not_found")
+	}
+
+	updatedDate := time.Now().UTC()
+	ok, err := s.repository.RemoveTag(currentUser.Reference, tag, updatedDate)
+	if err != nil {
+		errMsg := "unexpected error when remove the user tag"
+		logger.AppLog.Error().Err(err).Msg(errMsg)
+		return domain.User{}, errors.NewFatalError(errMsg)
+	}
+	if !ok {
+		// The tag was removed after the user was read
+		return domain.User{}, errors.NewNotFoundError("user tag not found").WithKey("user_tag_not_found")
+	}
+
+	tags := []string{}
+	for _, current := range currentUser.Tags {
+		if current != tag {
+			tags = append(tags, current)
+		}
+	}
+	if len(tags) == 0 {
+		tags = nil
+	}
+	currentUser.Tags = tags
+	currentUser.UpdatedDate = updatedDate
+	return currentUser, nil
+}
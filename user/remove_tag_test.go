@@ -0,0 +1,78 @@
+package user
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/desarrollogj/golang-api-example/domain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestRemoveTag_GivenACurrentTag_WhenExecute_ThenRemoveTheTag(t *testing.T) {
+	t.Log("Successfully remove a tag from an User")
+
+	currentUser := domain.User{
+		GenericEntity: domain.GenericEntity{Reference: "REF1", IsActive: true},
+		Tags:          []string{"beta", "vip"},
+	}
+	repositoryMock := new(repositoryMock)
+	repositoryMock.On("FindByReference", "REF1").Return(currentUser, nil)
+	repositoryMock.On("RemoveTag", "REF1", "vip", mock.AnythingOfType("time.Time")).Return(true, nil)
+
+	useCase := NewDefaultRemoveTag(repositoryMock)
+
+	user, err := useCase.Execute(domain.UserTagInput{Reference: "REF1", Tag: "VIP"})
+
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"beta"}, user.Tags)
+	assert.False(t, user.UpdatedDate.IsZero())
+
+	repositoryMock.AssertExpectations(t)
+}
+
+func TestRemoveTag_GivenATagTheUserDoesNotHave_WhenExecute_ThenReturnANotFoundError(t *testing.T) {
+	t.Log("Failure to remove a tag from an User because the User does not have it")
+
+	repositoryMock := new(repositoryMock)
+	repositoryMock.On("FindByReference", "REF1").Return(domain.User{GenericEntity: domain.GenericEntity{Reference: "REF1"}}, nil)
+
+	useCase := NewDefaultRemoveTag(repositoryMock)
+
+	_, err := useCase.Execute(domain.UserTagInput{Reference: "REF1", Tag: "vip"})
+
+	assert.NotNil(t, err)
+	assert.Equal(t, "user tag not found", err.Error())
+
+	repositoryMock.AssertNotCalled(t, "RemoveTag", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestRemoveTag_GivenATagRemovedConcurrently_WhenExecute_ThenReturnANotFoundError(t *testing.T) {
+	t.Log("Failure to remove a tag from an User because another request removed it after the User was read")
+
+	repositoryMock := new(repositoryMock)
+	repositoryMock.On("FindByReference", "REF1").Return(domain.User{GenericEntity: domain.GenericEntity{Reference: "REF1"}, Tags: []string{"vip"}}, nil)
+	repositoryMock.On("RemoveTag", "REF1", "vip", mock.AnythingOfType("time.Time")).Return(false, nil)
+
+	useCase := NewDefaultRemoveTag(repositoryMock)
+
+	_, err := useCase.Execute(domain.UserTagInput{Reference: "REF1", Tag: "vip"})
+
+	assert.NotNil(t, err)
+	assert.Equal(t, "user tag not found", err.Error())
+}
+
+func TestRemoveTag_GivenACurrentTag_WhenExecuteAndRemoveTagReturnedAnError_ThenReturnAFatalError(t *testing.T) {
+	t.Log("Failure to remove a tag from an User because repository returned an error")
+
+	repositoryMock := new(repositoryMock)
+	repositoryMock.On("FindByReference", "REF1").Return(domain.User{GenericEntity: domain.GenericEntity{Reference: "REF1"}, Tags: []string{"vip"}}, nil)
+	repositoryMock.On("RemoveTag", "REF1", "vip", mock.AnythingOfType("time.Time")).Return(false, errors.New("repository error"))
+
+	useCase := NewDefaultRemoveTag(repositoryMock)
+
+	_, err := useCase.Execute(domain.UserTagInput{Reference: "REF1", Tag: "vip"})
+
+	assert.NotNil(t, err)
+	assert.Equal(t, "unexpected error when remove the user tag", err.Error())
+}
@@ -0,0 +1,81 @@
+package user
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/desarrollogj/golang-api-example/domain"
+	"github.com/desarrollogj/golang-api-example/infrastructure"
+	"github.com/desarrollogj/golang-api-example/libs/errors"
+	"github.com/desarrollogj/golang-api-example/libs/logger"
+)
+
+// ResendEmailVerification represents the method to be implemented to send again the email verification token
+type ResendEmailVerification interface {
+	Execute(reference string) (domain.User, error)
+}
+
+// defaultResendEmailVerification is the default implementation of ResendEmailVerification interface
+type defaultResendEmailVerification struct {
+	config     domain.EmailVerificationConfiguration
+	repository infrastructure.UserRepository
+	verifier   EmailVerifier
+}
+
+// NewDefaultResendEmailVerification creates a defaultResendEmailVerification instance
+func NewDefaultResendEmailVerification(config domain.EmailVerificationConfiguration,
+	repository infrastructure.UserRepository,
+	verifier EmailVerifier) defaultResendEmailVerification {
+	return defaultResendEmailVerification{
+		config:     config,
+		repository: repository,
+		verifier:   verifier,
+	}
+}
+
+// Execute sends a new verification token to the user email.
+// A new token can not be sent until the resend interval since the previous one has passed
+func (s defaultResendEmailVerification) Execute(reference string) (domain.User, error) {
+	currentUser, err := s.repository.FindByReference(reference)
+	if err != nil {
+		errMsg := fmt.Sprintf("unexpected error when try to get user with reference %s", reference)
+		logger.AppLog.Error().Err(err).Msg(errMsg)
+		return domain.User{}, errors.NewFatalError(errMsg)
+	}
+	if len(currentUser.Reference) == 0 {
+		return domain.User{}, errors.NewNotFoundError("user not found").WithKey("user_not_found")
+	}
+	if currentUser.ErasedDate != nil {
+		return domain.User{}, errors.NewValidationError("user was erased and can not be updated").WithKey("user_erased")
+	}
+	if currentUser.EmailVerifiedDate != nil {
+		return domain.User{}, errors.NewValidationError("user email is already verified").WithKey("user_email_already_verified")
+	}
+
+	sent := time.Now().UTC()
+	if currentUser.EmailVerificationSentDate != nil {
+		next := currentUser.EmailVerificationSentDate.Add(time.Duration(s.config.ResendIntervalSeconds) * time.Second)
+		if sent.Before(next) {
+			wait := int(math.Ceil(next.Sub(sent).Seconds()))
+			return domain.User{}, errors.NewTooManyRequestsError(fmt.Sprintf("verification email was already sent, retry in %d seconds", wait)).WithKey("verification_email_already_sent", wait)
+		}
+	}
+
+	currentUser.EmailVerificationSentDate = &sent
+	currentUser.UpdatedDate = sent
+
+	updated, err := s.repository.Update(currentUser)
+	if err != nil {
+		return domain.User{}, storeError(err, "unexpected error when update the user")
+	}
+
+	err = s.verifier.Send(updated)
+	if err != nil {
+		errMsg := "unexpected error when send the verification email"
+		logger.AppLog.Error().Err(err).Msg(errMsg)
+		return domain.User{}, errors.NewFatalError(errMsg)
+	}
+
+	return updated, nil
+}
@@ -0,0 +1,119 @@
+package user
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/desarrollogj/golang-api-example/domain"
+	appErrors "github.com/desarrollogj/golang-api-example/libs/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestResendEmailVerification_GivenAnUnverifiedUser_WhenExecute_ThenSendANewToken(t *testing.T) {
+	t.Log("Successfully resend the User email verification")
+
+	sent := time.Now().UTC().Add(-2 * time.Minute)
+	currentUser := domain.User{
+		GenericEntity:             domain.GenericEntity{Reference: "REF1", IsActive: true},
+		Email:                     "foobar@email.com",
+		EmailVerificationSentDate: &sent,
+	}
+	repositoryMock := new(repositoryMock)
+	repositoryMock.On("FindByReference", "REF1").Return(currentUser, nil)
+	repositoryMock.On("Update", mock.MatchedBy(func(user domain.User) bool {
+		return user.EmailVerificationSentDate.After(sent)
+	})).Return(currentUser, nil)
+	verifierMock := new(emailVerifierMock)
+	verifierMock.On("Send", currentUser).Return(nil)
+
+	useCase := NewDefaultResendEmailVerification(newEmailVerificationConfigurationMock(), repositoryMock, verifierMock)
+
+	_, err := useCase.Execute("REF1")
+
+	assert.Nil(t, err)
+
+	repositoryMock.AssertExpectations(t)
+	verifierMock.AssertExpectations(t)
+}
+
+func TestResendEmailVerification_GivenARecentlySentToken_WhenExecute_ThenReturnATooManyRequestsError(t *testing.T) {
+	t.Log("Failure to resend the User email verification because it was sent recently")
+
+	sent := time.Now().UTC().Add(-10 * time.Second)
+	currentUser := domain.User{
+		GenericEntity:             domain.GenericEntity{Reference: "REF1", IsActive: true},
+		Email:                     "foobar@email.com",
+		EmailVerificationSentDate: &sent,
+	}
+	repositoryMock := new(repositoryMock)
+	repositoryMock.On("FindByReference", "REF1").Return(currentUser, nil)
+	verifierMock := new(emailVerifierMock)
+
+	useCase := NewDefaultResendEmailVerification(newEmailVerificationConfigurationMock(), repositoryMock, verifierMock)
+
+	_, err := useCase.Execute("REF1")
+
+	assert.NotNil(t, err)
+	assert.Equal(t, "verification email was already sent, retry in 50 seconds", err.Error())
+	assert.Equal(t, appErrors.TooManyRequestsErrorCode, err.(*appErrors.BusinessError).Err)
+
+	repositoryMock.AssertNotCalled(t, "Update", mock.Anything)
+	verifierMock.AssertNotCalled(t, "Send", mock.Anything)
+}
+
+func TestResendEmailVerification_GivenAVerifiedUser_WhenExecute_ThenReturnAValidationError(t *testing.T) {
+	t.Log("Failure to resend the User email verification because the email is already verified")
+
+	verified := time.Now().UTC()
+	currentUser := domain.User{
+		GenericEntity:     domain.GenericEntity{Reference: "REF1", IsActive: true},
+		Email:             "foobar@email.com",
+		EmailVerifiedDate: &verified,
+	}
+	repositoryMock := new(repositoryMock)
+	repositoryMock.On("FindByReference", "REF1").Return(currentUser, nil)
+
+	useCase := NewDefaultResendEmailVerification(newEmailVerificationConfigurationMock(), repositoryMock, new(emailVerifierMock))
+
+	_, err := useCase.Execute("REF1")
+
+	assert.NotNil(t, err)
+	assert.Equal(t, "user email is already verified", err.Error())
+}
+
+func TestResendEmailVerification_GivenAReference_WhenExecuteAndUserNotFound_ThenReturnAnError(t *testing.T) {
+	t.Log("Failure to resend the User email verification because the user was not found")
+
+	repositoryMock := new(repositoryMock)
+	repositoryMock.On("FindByReference", "REF1").Return(domain.User{}, nil)
+
+	useCase := NewDefaultResendEmailVerification(newEmailVerificationConfigurationMock(), repositoryMock, new(emailVerifierMock))
+
+	_, err := useCase.Execute("REF1")
+
+	assert.NotNil(t, err)
+	assert.Equal(t, "user not found", err.Error())
+}
+
+func TestResendEmailVerification_GivenAnUnverifiedUser_WhenExecuteAndSendFailed_ThenReturnAFatalError(t *testing.T) {
+	t.Log("Failure to resend the User email verification because the mailer returned an error")
+
+	currentUser := domain.User{
+		GenericEntity: domain.GenericEntity{Reference: "REF1", IsActive: true},
+		Email:         "foobar@email.com",
+	}
+	repositoryMock := new(repositoryMock)
+	repositoryMock.On("FindByReference", "REF1").Return(currentUser, nil)
+	repositoryMock.On("Update", mock.AnythingOfType("User")).Return(currentUser, nil)
+	verifierMock := new(emailVerifierMock)
+	verifierMock.On("Send", currentUser).Return(errors.New("mailer error"))
+
+	useCase := NewDefaultResendEmailVerification(newEmailVerificationConfigurationMock(), repositoryMock, verifierMock)
+
+	_, err := useCase.Execute("REF1")
+
+	assert.NotNil(t, err)
+	assert.Equal(t, "unexpected error when send the verification email", err.Error())
+}
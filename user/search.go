@@ -14,19 +14,55 @@ type Search interface {
 
 // defaultSearch is the default implementation of Search interface
 type defaultSearch struct {
+	config     domain.UserAttributesConfiguration
 	repository infrastructure.UserRepository
 }
 
 // NewDefaulSearch creates a defaultSearch instance
-func NewDefaulSearch(repository infrastructure.UserRepository) defaultSearch {
+func NewDefaulSearch(config domain.UserAttributesConfiguration, repository infrastructure.UserRepository) defaultSearch {
 	return defaultSearch{
+		config:     config,
 		repository: repository,
 	}
 }
 
 // Execute Search users
 func (s defaultSearch) Execute(input domain.UserSearchInput) (domain.UserSearchOutput, error) {
-	output, err := s.repository.SearchActive(input)
+	if len(input.Locale) > 0 {
+		locale, err := NormalizeLocale(input.Locale)
+		if err != nil {
+			return domain.UserSearchOutput{}, err
+		}
+		input.Locale = locale
+	}
+	input.Country = NormalizeCountry(input.Country)
+	if len(input.Attributes) > 0 {
+		attributes, err := normalizeAttributeFilters(s.config, input.Attributes)
+		if err != nil {
+			return domain.UserSearchOutput{}, err
+		}
+		input.Attributes = attributes
+	}
+	if len(input.Tags) > 0 {
+		tags, err := normalizeTags(input.Tags)
+		if err != nil {
+			return domain.UserSearchOutput{}, err
+		}
+		input.Tags = tags
+	}
+	if len(input.AnyTags) > 0 {
+		tags, err := normalizeTags(input.AnyTags)
+		if err != nil {
+			return domain.UserSearchOutput{}, err
+		}
+		input.AnyTags = tags
+	}
+
+	output, err := s.repository.Search(input)
+	if err == infrastructure.ErrEncryptedNameSearch {
+		return domain.UserSearchOutput{}, errors.NewValidationError("users can not be searched by first or last name when the personal data is encrypted").
+			WithKey("user_name_search_encrypted")
+	}
 	if err != nil {
 		errMsg := "unexpected error when try to search users"
 		logger.AppLog.Error().Err(err).Msg(errMsg)
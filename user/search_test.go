@@ -6,6 +6,8 @@ import (
 	"time"
 
 	"github.com/desarrollogj/golang-api-example/domain"
+	"github.com/desarrollogj/golang-api-example/infrastructure"
+	appErrors "github.com/desarrollogj/golang-api-example/libs/errors"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -40,9 +42,9 @@ func TestSearch_WhenExecute_ThenGetSearchResultOutput(t *testing.T) {
 		},
 	}
 	repositoryMock := new(repositoryMock)
-	repositoryMock.On("SearchActive", searchInput).Return(searchOutput, nil)
+	repositoryMock.On("Search", searchInput).Return(searchOutput, nil)
 
-	useCase := NewDefaulSearch(repositoryMock)
+	useCase := NewDefaulSearch(attributesConfig, repositoryMock)
 
 	usersFound, err := useCase.Execute(searchInput)
 
@@ -63,9 +65,9 @@ func TestSearch_WhenExecute_AndRepositoryReturnsError_ThenReturnFatalError(t *te
 		},
 	}
 	repositoryMock := new(repositoryMock)
-	repositoryMock.On("SearchActive", searchInput).Return(domain.UserSearchOutput{}, errors.New("repository error"))
+	repositoryMock.On("Search", searchInput).Return(domain.UserSearchOutput{}, errors.New("repository error"))
 
-	useCase := NewDefaulSearch(repositoryMock)
+	useCase := NewDefaulSearch(attributesConfig, repositoryMock)
 
 	_, err := useCase.Execute(searchInput)
 
@@ -74,3 +76,72 @@ func TestSearch_WhenExecute_AndRepositoryReturnsError_ThenReturnFatalError(t *te
 
 	repositoryMock.AssertExpectations(t)
 }
+
+func TestSearch_GivenNameFilter_WhenExecute_AndPersonalDataIsEncrypted_ThenReturnValidationError(t *testing.T) {
+	t.Log("Failure to search Users by name because the personal data is encrypted")
+
+	searchInput := domain.UserSearchInput{
+		SearchInput: domain.SearchInput{
+			Page:     1,
+			PageSize: 10,
+		},
+		FirstName: "Foo",
+	}
+	repositoryMock := new(repositoryMock)
+	repositoryMock.On("Search", searchInput).Return(domain.UserSearchOutput{}, infrastructure.ErrEncryptedNameSearch)
+
+	useCase := NewDefaulSearch(attributesConfig, repositoryMock)
+
+	_, err := useCase.Execute(searchInput)
+
+	assert.NotNil(t, err)
+	assert.Equal(t, appErrors.ValidationErrorCode, err.(*appErrors.BusinessError).Err)
+
+	repositoryMock.AssertExpectations(t)
+}
+
+func TestSearch_GivenAttributeFilters_WhenExecute_ThenSearchWithTypedValues(t *testing.T) {
+	t.Log("Successfully search Users by custom attributes")
+
+	searchInput := domain.UserSearchInput{
+		SearchInput: domain.SearchInput{Page: 1, PageSize: 10},
+		Attributes:  map[string]interface{}{"plan": "pro", "seats": "10", "newsletter": "true"},
+	}
+	expectedInput := searchInput
+	expectedInput.Attributes = map[string]interface{}{"plan": "pro", "seats": 10.0, "newsletter": true}
+	repositoryMock := new(repositoryMock)
+	repositoryMock.On("Search", expectedInput).Return(domain.UserSearchOutput{}, nil)
+
+	useCase := NewDefaulSearch(attributesConfig, repositoryMock)
+
+	_, err := useCase.Execute(searchInput)
+
+	assert.Nil(t, err)
+	repositoryMock.AssertExpectations(t)
+
+	searchInput.Attributes = map[string]interface{}{"unknown": "value"}
+	_, err = useCase.Execute(searchInput)
+	assert.Equal(t, "attribute unknown is not valid", err.Error())
+}
+
+func TestSearch_GivenTagFilters_WhenExecute_ThenSearchWithNormalizedTags(t *testing.T) {
+	t.Log("Successfully search Users by tags")
+
+	searchInput := domain.UserSearchInput{
+		SearchInput: domain.SearchInput{Page: 1, PageSize: 10},
+		Tags:        []string{"VIP", "Early Adopter"},
+		AnyTags:     []string{"Beta"},
+	}
+	expectedInput := searchInput
+	expectedInput.Tags = []string{"early-adopter", "vip"}
+	expectedInput.AnyTags = []string{"beta"}
+	repositoryMock := new(repositoryMock)
+	repositoryMock.On("Search", expectedInput).Return(domain.UserSearchOutput{}, nil)
+
+	useCase := NewDefaulSearch(attributesConfig, repositoryMock)
+
+	_, err := useCase.Execute(searchInput)
+
+	assert.Nil(t, err)
+	repositoryMock.AssertExpectations(t)
+}
@@ -0,0 +1,127 @@
+package user
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/desarrollogj/golang-api-example/domain"
+	"github.com/desarrollogj/golang-api-example/infrastructure"
+	"github.com/desarrollogj/golang-api-example/libs/blob"
+	"github.com/desarrollogj/golang-api-example/libs/errors"
+	"github.com/desarrollogj/golang-api-example/libs/imaging"
+	"github.com/desarrollogj/golang-api-example/libs/logger"
+)
+
+// SetAvatar represents the method to be implemented to set an user avatar
+type SetAvatar interface {
+	Execute(input domain.UserAvatarInput) (domain.User, error)
+}
+
+// defaultSetAvatar is the default implementation of SetAvatar interface
+type defaultSetAvatar struct {
+	config     domain.AvatarConfiguration
+	repository infrastructure.UserRepository
+	store      blob.BlobStore
+}
+
+// NewDefaultSetAvatar creates a defaultSetAvatar instance
+func NewDefaultSetAvatar(config domain.AvatarConfiguration, repository infrastructure.UserRepository, store blob.BlobStore) defaultSetAvatar {
+	return defaultSetAvatar{
+		config:     config,
+		repository: repository,
+		store:      store,
+	}
+}
+
+// Execute validate the image and store a thumbnail for each configured size.
+// The format is sniffed from the image content, and the dimensions are checked before the image is decoded
+func (s defaultSetAvatar) Execute(input domain.UserAvatarInput) (domain.User, error) {
+	if len(input.Data) == 0 {
+		return domain.User{}, errors.NewValidationError("avatar image is required").WithKey("avatar_required")
+	}
+	if len(input.Data) > s.config.MaxSizeBytes {
+		return domain.User{}, errors.NewValidationError(fmt.Sprintf("avatar image is too large, the maximum size is %d bytes", s.config.MaxSizeBytes)).WithKey("avatar_too_large", s.config.MaxSizeBytes)
+	}
+	contentType := imaging.DetectContentType(input.Data)
+	if contentType != imaging.ContentTypeJPEG && contentType != imaging.ContentTypePNG {
+		return domain.User{}, errors.NewValidationError("avatar image must be a JPEG or PNG image").WithKey("avatar_format_not_valid")
+	}
+	imageConfig, err := imaging.DecodeConfig(input.Data)
+	if err != nil {
+		return domain.User{}, errors.NewValidationError("avatar image is not valid").WithKey("avatar_not_valid")
+	}
+	if imageConfig.Width > s.config.MaxDimension || imageConfig.Height > s.config.MaxDimension {
+		return domain.User{}, errors.NewValidationError(fmt.Sprintf("avatar image is too large, the maximum dimensions are %dx%d pixels",
+			s.config.MaxDimension, s.config.MaxDimension)).
+			WithKey("avatar_dimensions_too_large", s.config.MaxDimension, s.config.MaxDimension)
+	}
+
+	currentUser, err := s.repository.FindByReference(input.Reference)
+	if err != nil {
+		errMsg := fmt.Sprintf("unexpected error when try to get user with reference %s", input.Reference)
+		logger.AppLog.Error().Err(err).Msg(errMsg)
+		return domain.User{}, errors.NewFatalError(errMsg)
+	}
+	if len(currentUser.Reference) == 0 || statusOf(currentUser) == domain.UserStatusDeleted {
+		return domain.User{}, errors.NewNotFoundError("user not found").WithKey("user_not_found")
+	}
+
+	img, err := imaging.Decode(input.Data)
+	if err != nil {
+		return domain.User{}, errors.NewValidationError("avatar image is not valid").WithKey("avatar_not_valid")
+	}
+
+	hash := sha256.Sum256(input.Data)
+	avatar := domain.UserAvatar{
+		Version:     hex.EncodeToString(hash[:8]),
+		ContentType: contentType,
+		Sizes:       append([]int{}, s.config.Sizes...),
+		UpdatedDate: time.Now().UTC(),
+	}
+	for _, size := range avatar.Sizes {
+		data, err := imaging.Encode(imaging.Thumbnail(img, size), contentType)
+		if err == nil {
+			err = s.store.Put(avatarKey(currentUser.Reference, avatar.Version, size), data)
+		}
+		if err != nil {
+			errMsg := "unexpected error when store the user avatar"
+			logger.AppLog.Error().Err(err).Msg(errMsg)
+			return domain.User{}, errors.NewFatalError(errMsg)
+		}
+	}
+
+	previous := currentUser.Avatar
+	currentUser.Avatar = &avatar
+	currentUser.UpdatedDate = avatar.UpdatedDate
+
+	updated, err := s.repository.Update(currentUser)
+	if err != nil {
+		return domain.User{}, storeError(err, "unexpected error when set the user avatar")
+	}
+
+	// The same image keeps its version, and its thumbnails were just replaced
+	if previous != nil && previous.Version != avatar.Version {
+		if err := deleteAvatar(s.store, currentUser.Reference, previous); err != nil {
+			logger.AppLog.Error().Err(err).Str("reference", currentUser.Reference).Msg("unable to delete the previous user avatar")
+		}
+	}
+
+	return updated, nil
+}
+
+// avatarKey returns the blob key of an avatar thumbnail. Each version has its own keys, so cached thumbnails never change
+func avatarKey(reference string, version string, size int) string {
+	return fmt.Sprintf("avatars/%s/%s/%d", reference, version, size)
+}
+
+// deleteAvatar deletes all the thumbnails of an avatar
+func deleteAvatar(store blob.BlobStore, reference string, avatar *domain.UserAvatar) error {
+	for _, size := range avatar.Sizes {
+		if err := store.Delete(avatarKey(reference, avatar.Version, size)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
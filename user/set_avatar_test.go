@@ -0,0 +1,137 @@
+package user
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/png"
+	"testing"
+
+	"github.com/desarrollogj/golang-api-example/domain"
+	"github.com/desarrollogj/golang-api-example/libs/blob"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var avatarConfig = domain.AvatarConfiguration{
+	MaxSizeBytes: 1024 * 1024,
+	MaxDimension: 512,
+	Sizes:        []int{32, 16},
+}
+
+func pngImage(t *testing.T, width int, height int) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for x := 0; x < width; x++ {
+		for y := 0; y < height; y++ {
+			img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 128, A: 255})
+		}
+	}
+	var buffer bytes.Buffer
+	assert.Nil(t, png.Encode(&buffer, img))
+	return buffer.Bytes()
+}
+
+func TestSetAvatar_GivenAnImage_WhenExecute_ThenStoreTheThumbnails(t *testing.T) {
+	t.Log("Successfully set an User avatar")
+
+	reference := "REF1"
+	currentUser := domain.User{GenericEntity: domain.GenericEntity{Reference: reference, IsActive: true}}
+	repositoryMock := new(repositoryMock)
+	repositoryMock.On("FindByReference", reference).Return(currentUser, nil)
+	repositoryMock.On("Update", mock.MatchedBy(func(user domain.User) bool {
+		return user.Avatar != nil &&
+			user.Avatar.ContentType == "image/png" &&
+			len(user.Avatar.Version) == 16 &&
+			assert.ObjectsAreEqual([]int{32, 16}, user.Avatar.Sizes)
+	})).Return(currentUser, nil)
+	store := blob.NewLocalBlobStore(t.TempDir())
+
+	useCase := NewDefaultSetAvatar(avatarConfig, repositoryMock, store)
+
+	_, err := useCase.Execute(domain.UserAvatarInput{Reference: reference, Data: pngImage(t, 100, 50)})
+
+	assert.Nil(t, err)
+	avatar := repositoryMock.Calls[1].Arguments.Get(0).(domain.User).Avatar
+	for _, size := range []int{32, 16} {
+		data, err := store.Get(avatarKey(reference, avatar.Version, size))
+		assert.Nil(t, err)
+		config, err := png.DecodeConfig(bytes.NewReader(data))
+		assert.Nil(t, err)
+		assert.Equal(t, size, config.Width)
+		assert.Equal(t, size, config.Height)
+	}
+
+	repositoryMock.AssertExpectations(t)
+}
+
+func TestSetAvatar_GivenAnUserWithAvatar_WhenExecute_ThenDeleteThePreviousAvatar(t *testing.T) {
+	t.Log("Successfully replace an User avatar")
+
+	reference := "REF1"
+	currentUser := domain.User{
+		GenericEntity: domain.GenericEntity{Reference: reference, IsActive: true},
+		Avatar:        &domain.UserAvatar{Version: "OLD", ContentType: "image/png", Sizes: []int{16}},
+	}
+	repositoryMock := new(repositoryMock)
+	repositoryMock.On("FindByReference", reference).Return(currentUser, nil)
+	repositoryMock.On("Update", mock.AnythingOfType("User")).Return(currentUser, nil)
+	store := blob.NewLocalBlobStore(t.TempDir())
+	assert.Nil(t, store.Put(avatarKey(reference, "OLD", 16), []byte("image")))
+
+	useCase := NewDefaultSetAvatar(avatarConfig, repositoryMock, store)
+
+	_, err := useCase.Execute(domain.UserAvatarInput{Reference: reference, Data: pngImage(t, 20, 20)})
+
+	assert.Nil(t, err)
+	_, err = store.Get(avatarKey(reference, "OLD", 16))
+	assert.Equal(t, blob.ErrNotFound, err)
+}
+
+func TestSetAvatar_GivenAnInvalidImage_WhenExecute_ThenReturnAValidationError(t *testing.T) {
+	t.Log("Failure to set an User avatar because the image is not valid")
+
+	var gifImage bytes.Buffer
+	assert.Nil(t, gif.Encode(&gifImage, image.NewPaletted(image.Rect(0, 0, 10, 10), color.Palette{color.Black}), nil))
+	truncatedImage := pngImage(t, 20, 20)[:20]
+
+	tests := []struct {
+		name     string
+		data     []byte
+		expected string
+	}{
+		{"Empty", nil, "avatar image is required"},
+		{"Size", make([]byte, avatarConfig.MaxSizeBytes+1), "avatar image is too large, the maximum size is 1048576 bytes"},
+		{"Text", []byte("not an image"), "avatar image must be a JPEG or PNG image"},
+		{"GIF", gifImage.Bytes(), "avatar image must be a JPEG or PNG image"},
+		{"Truncated", truncatedImage, "avatar image is not valid"},
+		{"Dimensions", pngImage(t, 600, 10), "avatar image is too large, the maximum dimensions are 512x512 pixels"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			repositoryMock := new(repositoryMock)
+			useCase := NewDefaultSetAvatar(avatarConfig, repositoryMock, blob.NewLocalBlobStore(t.TempDir()))
+
+			_, err := useCase.Execute(domain.UserAvatarInput{Reference: "REF1", Data: test.data})
+
+			assert.NotNil(t, err)
+			assert.Equal(t, test.expected, err.Error())
+			repositoryMock.AssertNotCalled(t, "FindByReference", mock.Anything)
+		})
+	}
+}
+
+func TestSetAvatar_GivenANonExistingUser_WhenExecute_ThenReturnANotFoundError(t *testing.T) {
+	t.Log("Failure to set an User avatar because the user does not exist")
+
+	repositoryMock := new(repositoryMock)
+	repositoryMock.On("FindByReference", "REF1").Return(domain.User{}, nil)
+
+	useCase := NewDefaultSetAvatar(avatarConfig, repositoryMock, blob.NewLocalBlobStore(t.TempDir()))
+
+	_, err := useCase.Execute(domain.UserAvatarInput{Reference: "REF1", Data: pngImage(t, 20, 20)})
+
+	assert.NotNil(t, err)
+	assert.Equal(t, "user not found", err.Error())
+	repositoryMock.AssertNotCalled(t, "Update", mock.Anything)
+}
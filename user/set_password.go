@@ -0,0 +1,91 @@
+package user
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/desarrollogj/golang-api-example/domain"
+	"github.com/desarrollogj/golang-api-example/infrastructure"
+	"github.com/desarrollogj/golang-api-example/libs/errors"
+	"github.com/desarrollogj/golang-api-example/libs/logger"
+	"github.com/desarrollogj/golang-api-example/libs/password"
+)
+
+// SetPassword represents the method to be implemented to set the password credentials of an user
+type SetPassword interface {
+	Execute(input domain.UserPasswordInput) error
+}
+
+// defaultSetPassword is the default implementation of SetPassword interface
+type defaultSetPassword struct {
+	policy               domain.PasswordPolicyConfiguration
+	repository           infrastructure.UserRepository
+	credentialRepository infrastructure.CredentialRepository
+}
+
+// NewDefaultSetPassword creates a defaultSetPassword instance
+func NewDefaultSetPassword(policy domain.PasswordPolicyConfiguration,
+	repository infrastructure.UserRepository,
+	credentialRepository infrastructure.CredentialRepository) defaultSetPassword {
+	return defaultSetPassword{
+		policy:               policy,
+		repository:           repository,
+		credentialRepository: credentialRepository,
+	}
+}
+
+// Execute validates the password against the policy and stores its hash, replacing the previous one.
+// The current password must match when it is required
+func (s defaultSetPassword) Execute(input domain.UserPasswordInput) error {
+	currentUser, err := s.repository.FindByReference(input.Reference)
+	if err != nil {
+		errMsg := fmt.Sprintf("unexpected error when try to get user with reference %s", input.Reference)
+		logger.AppLog.Error().Err(err).Msg(errMsg)
+		return errors.NewFatalError(errMsg)
+	}
+	if len(currentUser.Reference) == 0 {
+		return errors.NewNotFoundError("user not found").WithKey("user_not_found")
+	}
+	if currentUser.ErasedDate != nil {
+		return errors.NewValidationError("user was erased and can not be updated").WithKey("user_erased")
+	}
+
+	if input.RequireCurrentPassword {
+		credential, err := s.credentialRepository.FindByUserReference(currentUser.Reference)
+		if err != nil {
+			errMsg := "unexpected error when try to get the user credential"
+			logger.AppLog.Error().Err(err).Msg(errMsg)
+			return errors.NewFatalError(errMsg)
+		}
+		if !password.Compare(credential.PasswordHash, input.CurrentPassword) {
+			return errors.NewForbiddenError("current password is not valid").WithKey("current_password_not_valid")
+		}
+	}
+
+	err = validatePassword(s.policy, input.Password)
+	if err != nil {
+		return err
+	}
+
+	hash, err := password.Hash(input.Password)
+	if err != nil {
+		errMsg := "unexpected error when hash the password"
+		logger.AppLog.Error().Err(err).Msg(errMsg)
+		return errors.NewFatalError(errMsg)
+	}
+
+	now := time.Now().UTC()
+	_, err = s.credentialRepository.Save(domain.Credential{
+		UserReference: currentUser.Reference,
+		PasswordHash:  hash,
+		CreatedDate:   now,
+		UpdatedDate:   now,
+	})
+	if err != nil {
+		errMsg := "unexpected error when save the user password"
+		logger.AppLog.Error().Err(err).Msg(errMsg)
+		return errors.NewFatalError(errMsg)
+	}
+
+	return nil
+}
@@ -0,0 +1,165 @@
+package user
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/desarrollogj/golang-api-example/domain"
+	"github.com/desarrollogj/golang-api-example/libs/password"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestSetPassword_GivenAValidPassword_WhenExecute_ThenSaveItsHash(t *testing.T) {
+	t.Log("Successfully set an User password")
+
+	currentUser := domain.User{
+		GenericEntity: domain.GenericEntity{Reference: "REF1", IsActive: true},
+	}
+	repositoryMock := new(repositoryMock)
+	repositoryMock.On("FindByReference", "REF1").Return(currentUser, nil)
+	credentialRepositoryMock := new(credentialRepositoryMock)
+	credentialRepositoryMock.On("Save", mock.MatchedBy(func(credential domain.Credential) bool {
+		return credential.UserReference == "REF1" &&
+			credential.PasswordHash != "Secret-Password1" &&
+			password.Compare(credential.PasswordHash, "Secret-Password1")
+	})).Return(domain.Credential{}, nil)
+
+	useCase := NewDefaultSetPassword(newPasswordPolicyMock(), repositoryMock, credentialRepositoryMock)
+
+	err := useCase.Execute(domain.UserPasswordInput{Reference: "REF1", Password: "Secret-Password1"})
+
+	assert.Nil(t, err)
+
+	repositoryMock.AssertExpectations(t)
+	credentialRepositoryMock.AssertExpectations(t)
+}
+
+func TestSetPassword_GivenTheCurrentPassword_WhenExecute_ThenSaveTheNewPasswordHash(t *testing.T) {
+	t.Log("Successfully change an User password with the current password")
+
+	currentHash, _ := password.Hash("Old-Password1")
+	currentUser := domain.User{
+		GenericEntity: domain.GenericEntity{Reference: "REF1", IsActive: true},
+	}
+	repositoryMock := new(repositoryMock)
+	repositoryMock.On("FindByReference", "REF1").Return(currentUser, nil)
+	credentialRepositoryMock := new(credentialRepositoryMock)
+	credentialRepositoryMock.On("FindByUserReference", "REF1").Return(domain.Credential{UserReference: "REF1", PasswordHash: currentHash}, nil)
+	credentialRepositoryMock.On("Save", mock.MatchedBy(func(credential domain.Credential) bool {
+		return password.Compare(credential.PasswordHash, "Secret-Password1")
+	})).Return(domain.Credential{}, nil)
+
+	useCase := NewDefaultSetPassword(newPasswordPolicyMock(), repositoryMock, credentialRepositoryMock)
+
+	err := useCase.Execute(domain.UserPasswordInput{
+		Reference:              "REF1",
+		Password:               "Secret-Password1",
+		CurrentPassword:        "Old-Password1",
+		RequireCurrentPassword: true,
+	})
+
+	assert.Nil(t, err)
+
+	credentialRepositoryMock.AssertExpectations(t)
+}
+
+func TestSetPassword_GivenAWrongCurrentPassword_WhenExecute_ThenReturnAForbiddenError(t *testing.T) {
+	t.Log("Failure to change an User password because the current password does not match")
+
+	currentHash, _ := password.Hash("Old-Password1")
+	currentUser := domain.User{
+		GenericEntity: domain.GenericEntity{Reference: "REF1", IsActive: true},
+	}
+	repositoryMock := new(repositoryMock)
+	repositoryMock.On("FindByReference", "REF1").Return(currentUser, nil)
+	credentialRepositoryMock := new(credentialRepositoryMock)
+	credentialRepositoryMock.On("FindByUserReference", "REF1").Return(domain.Credential{UserReference: "REF1", PasswordHash: currentHash}, nil)
+
+	useCase := NewDefaultSetPassword(newPasswordPolicyMock(), repositoryMock, credentialRepositoryMock)
+
+	err := useCase.Execute(domain.UserPasswordInput{
+		Reference:              "REF1",
+		Password:               "Secret-Password1",
+		CurrentPassword:        "wrong",
+		RequireCurrentPassword: true,
+	})
+
+	assert.NotNil(t, err)
+	assert.Equal(t, "current password is not valid", err.Error())
+
+	credentialRepositoryMock.AssertNotCalled(t, "Save", mock.Anything)
+}
+
+func TestSetPassword_GivenAWeakPassword_WhenExecute_ThenReturnAValidationError(t *testing.T) {
+	t.Log("Failure to set an User password because it does not meet the policy")
+
+	currentUser := domain.User{
+		GenericEntity: domain.GenericEntity{Reference: "REF1", IsActive: true},
+	}
+	repositoryMock := new(repositoryMock)
+	repositoryMock.On("FindByReference", "REF1").Return(currentUser, nil)
+	credentialRepositoryMock := new(credentialRepositoryMock)
+
+	useCase := NewDefaultSetPassword(newPasswordPolicyMock(), repositoryMock, credentialRepositoryMock)
+
+	err := useCase.Execute(domain.UserPasswordInput{Reference: "REF1", Password: "secret"})
+
+	assert.NotNil(t, err)
+	assert.Equal(t, "password must have at least 12 characters, an uppercase letter, a digit", err.Error())
+
+	credentialRepositoryMock.AssertNotCalled(t, "Save", mock.Anything)
+}
+
+func TestSetPassword_GivenAnErasedUser_WhenExecute_ThenReturnAValidationError(t *testing.T) {
+	t.Log("Failure to set an User password because the user was erased")
+
+	erased := time.Now().UTC()
+	currentUser := domain.User{
+		GenericEntity: domain.GenericEntity{Reference: "REF1"},
+		ErasedDate:    &erased,
+	}
+	repositoryMock := new(repositoryMock)
+	repositoryMock.On("FindByReference", "REF1").Return(currentUser, nil)
+
+	useCase := NewDefaultSetPassword(newPasswordPolicyMock(), repositoryMock, new(credentialRepositoryMock))
+
+	err := useCase.Execute(domain.UserPasswordInput{Reference: "REF1", Password: "Secret-Password1"})
+
+	assert.NotNil(t, err)
+	assert.Equal(t, "user was erased and can not be updated", err.Error())
+}
+
+func TestSetPassword_GivenAReference_WhenExecuteAndUserNotFound_ThenReturnANotFoundError(t *testing.T) {
+	t.Log("Failure to set an User password because the user was not found")
+
+	repositoryMock := new(repositoryMock)
+	repositoryMock.On("FindByReference", "REF1").Return(domain.User{}, nil)
+
+	useCase := NewDefaultSetPassword(newPasswordPolicyMock(), repositoryMock, new(credentialRepositoryMock))
+
+	err := useCase.Execute(domain.UserPasswordInput{Reference: "REF1", Password: "Secret-Password1"})
+
+	assert.NotNil(t, err)
+	assert.Equal(t, "user not found", err.Error())
+}
+
+func TestSetPassword_GivenAValidPassword_WhenExecuteAndSaveReturnedAnError_ThenReturnAFatalError(t *testing.T) {
+	t.Log("Failure to set an User password because the credential repository returned an error")
+
+	currentUser := domain.User{
+		GenericEntity: domain.GenericEntity{Reference: "REF1", IsActive: true},
+	}
+	repositoryMock := new(repositoryMock)
+	repositoryMock.On("FindByReference", "REF1").Return(currentUser, nil)
+	credentialRepositoryMock := new(credentialRepositoryMock)
+	credentialRepositoryMock.On("Save", mock.AnythingOfType("Credential")).Return(domain.Credential{}, errors.New("repository error"))
+
+	useCase := NewDefaultSetPassword(newPasswordPolicyMock(), repositoryMock, credentialRepositoryMock)
+
+	err := useCase.Execute(domain.UserPasswordInput{Reference: "REF1", Password: "Secret-Password1"})
+
+	assert.NotNil(t, err)
+	assert.Equal(t, "unexpected error when save the user password", err.Error())
+}
@@ -0,0 +1,108 @@
+package user
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/desarrollogj/golang-api-example/domain"
+	"github.com/desarrollogj/golang-api-example/infrastructure"
+	"github.com/desarrollogj/golang-api-example/libs/errors"
+	"github.com/desarrollogj/golang-api-example/libs/logger"
+	"github.com/google/uuid"
+)
+
+// SetRoles represents the method to be implemented to set the roles of an user
+type SetRoles interface {
+	Execute(input domain.UserRolesInput) (domain.User, error)
+}
+
+// defaultSetRoles is the default implementation of SetRoles interface
+type defaultSetRoles struct {
+	config          domain.AuthorizationConfiguration
+	repository      infrastructure.UserRepository
+	auditRepository infrastructure.AuditRepository
+}
+
+// NewDefaultSetRoles creates a defaultSetRoles instance
+func NewDefaultSetRoles(config domain.AuthorizationConfiguration,
+	repository infrastructure.UserRepository,
+	auditRepository infrastructure.AuditRepository) defaultSetRoles {
+	return defaultSetRoles{
+		config:          config,
+		repository:      repository,
+		auditRepository: auditRepository,
+	}
+}
+
+// Execute replaces the User roles with configured roles, and registers the change in the audit.
+// Users can not change their own roles
+func (s defaultSetRoles) Execute(input domain.UserRolesInput) (domain.User, error) {
+	roles := []string{}
+	for _, role := range input.Roles {
+		if _, ok := s.config.Roles[role]; !ok {
+			return domain.User{}, errors.NewValidationError(fmt.Sprintf("role %s is not valid", role)).WithKey("user_role_not_valid", role)
+		}
+		if !containsString(roles, role) {
+			roles = append(roles, role)
+		}
+	}
+	sort.Strings(roles)
+
+	if len(input.ChangedBy) > 0 && input.ChangedBy == input.Reference {
+		return domain.User{}, errors.NewForbiddenError("users can not change their own roles").WithKey("user_own_roles_forbidden")
+	}
+
+	currentUser, err := s.repository.FindByReference(input.Reference)
+	if err != nil {
+		errMsg := fmt.Sprintf("unexpected error when try to get user with reference %s", input.Reference)
+		logger.AppLog.Error().Err(err).Msg(errMsg)
+		return domain.User{}, errors.NewFatalError(errMsg)
+	}
+	if len(currentUser.Reference) == 0 {
+		return domain.User{}, errors.NewNotFoundError("user not found").WithKey("user_not_found")
+	}
+	if currentUser.ErasedDate != nil {
+		return domain.User{}, errors.NewValidationError("user was erased and can not be updated").WithKey("user_erased")
+	}
+
+	previous := strings.Join(currentUser.Roles, ",")
+	changed := time.Now().UTC()
+	currentUser.Roles = roles
+	currentUser.UpdatedDate = changed
+	updated, err := s.repository.Update(currentUser)
+	if err != nil {
+		return domain.User{}, storeError(err, "unexpected error when set the user roles")
+	}
+
+	_, err = s.auditRepository.Create(domain.AuditEntry{
+		Reference:       uuid.NewString(),
+		Action:          domain.AuditActionUserRoles,
+		EntityType:      domain.AuditEntityUser,
+		EntityReference: updated.Reference,
+		Details: map[string]string{
+			"from":      previous,
+			"to":        strings.Join(roles, ","),
+			"changedBy": input.ChangedBy,
+		},
+		CreatedDate: changed,
+	})
+	if err != nil {
+		errMsg := "unexpected error when register the user roles change"
+		logger.AppLog.Error().Err(err).Msg(errMsg)
+		return domain.User{}, errors.NewFatalError(errMsg)
+	}
+
+	return updated, nil
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,111 @@
+package user
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/desarrollogj/golang-api-example/domain"
+	appErrors "github.com/desarrollogj/golang-api-example/libs/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newAuthorizationConfigurationMock() domain.AuthorizationConfiguration {
+	return domain.AuthorizationConfiguration{
+		Enabled: true,
+		Roles: map[string][]string{
+			"viewer": {domain.PermissionUsersRead},
+			"admin":  {domain.PermissionUsersRead, domain.PermissionUsersWrite, domain.PermissionUsersAdmin},
+		},
+	}
+}
+
+func TestSetRoles_GivenValidRoles_WhenExecute_ThenReplaceTheUserRolesAndAuditIt(t *testing.T) {
+	t.Log("Successfully set the User roles")
+
+	currentUser := domain.User{
+		GenericEntity: domain.GenericEntity{Reference: "REF1", IsActive: true},
+		Roles:         []string{"viewer"},
+	}
+	repositoryMock := new(repositoryMock)
+	repositoryMock.On("FindByReference", "REF1").Return(currentUser, nil)
+	repositoryMock.On("Update", mock.MatchedBy(func(user domain.User) bool {
+		return assert.ObjectsAreEqual([]string{"admin", "viewer"}, user.Roles) && !user.UpdatedDate.IsZero()
+	})).Return(currentUser, nil)
+	auditRepositoryMock := new(auditRepositoryMock)
+	auditRepositoryMock.On("Create", mock.MatchedBy(func(entry domain.AuditEntry) bool {
+		return entry.Action == domain.AuditActionUserRoles &&
+			entry.Details["from"] == "viewer" &&
+			entry.Details["to"] == "admin,viewer" &&
+			entry.Details["changedBy"] == "ADMIN1"
+	})).Return(domain.AuditEntry{}, nil)
+
+	useCase := NewDefaultSetRoles(newAuthorizationConfigurationMock(), repositoryMock, auditRepositoryMock)
+
+	_, err := useCase.Execute(domain.UserRolesInput{Reference: "REF1", Roles: []string{"viewer", "admin", "viewer"}, ChangedBy: "ADMIN1"})
+
+	assert.Nil(t, err)
+
+	repositoryMock.AssertExpectations(t)
+	auditRepositoryMock.AssertExpectations(t)
+}
+
+func TestSetRoles_GivenAnUnknownRole_WhenExecute_ThenReturnAValidationError(t *testing.T) {
+	t.Log("Failure to set the User roles because a role is not configured")
+
+	repositoryMock := new(repositoryMock)
+
+	useCase := NewDefaultSetRoles(newAuthorizationConfigurationMock(), repositoryMock, new(auditRepositoryMock))
+
+	_, err := useCase.Execute(domain.UserRolesInput{Reference: "REF1", Roles: []string{"owner"}})
+
+	assert.NotNil(t, err)
+	assert.Equal(t, "role owner is not valid", err.Error())
+
+	repositoryMock.AssertNotCalled(t, "FindByReference", mock.Anything)
+}
+
+func TestSetRoles_GivenTheCallerOwnReference_WhenExecute_ThenReturnAForbiddenError(t *testing.T) {
+	t.Log("Failure to set the User roles because users can not change their own roles")
+
+	repositoryMock := new(repositoryMock)
+
+	useCase := NewDefaultSetRoles(newAuthorizationConfigurationMock(), repositoryMock, new(auditRepositoryMock))
+
+	_, err := useCase.Execute(domain.UserRolesInput{Reference: "REF1", Roles: []string{"admin"}, ChangedBy: "REF1"})
+
+	assert.NotNil(t, err)
+	assert.Equal(t, "users can not change their own roles", err.Error())
+	assert.Equal(t, appErrors.ForbiddenErrorCode, err.(*appErrors.BusinessError).Err)
+
+	repositoryMock.AssertNotCalled(t, "FindByReference", mock.Anything)
+}
+
+func TestSetRoles_GivenAReference_WhenExecuteAndUserNotFound_ThenReturnAnError(t *testing.T) {
+	t.Log("Failure to set the User roles because the user was not found")
+
+	repositoryMock := new(repositoryMock)
+	repositoryMock.On("FindByReference", "REF1").Return(domain.User{}, nil)
+
+	useCase := NewDefaultSetRoles(newAuthorizationConfigurationMock(), repositoryMock, new(auditRepositoryMock))
+
+	_, err := useCase.Execute(domain.UserRolesInput{Reference: "REF1", Roles: []string{"admin"}})
+
+	assert.NotNil(t, err)
+	assert.Equal(t, "user not found", err.Error())
+}
+
+func TestSetRoles_GivenValidRoles_WhenExecuteAndUpdateReturnedAnError_ThenReturnAFatalError(t *testing.T) {
+	t.Log("Failure to set the User roles because update returned an unexpected error")
+
+	repositoryMock := new(repositoryMock)
+	repositoryMock.On("FindByReference", "REF1").Return(domain.User{GenericEntity: domain.GenericEntity{Reference: "REF1"}}, nil)
+	repositoryMock.On("Update", mock.AnythingOfType("User")).Return(domain.User{}, errors.New("repository error"))
+
+	useCase := NewDefaultSetRoles(newAuthorizationConfigurationMock(), repositoryMock, new(auditRepositoryMock))
+
+	_, err := useCase.Execute(domain.UserRolesInput{Reference: "REF1", Roles: []string{"admin"}})
+
+	assert.NotNil(t, err)
+	assert.Equal(t, "unexpected error when set the user roles", err.Error())
+}
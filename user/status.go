@@ -0,0 +1,124 @@
+package user
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/desarrollogj/golang-api-example/domain"
+	"github.com/desarrollogj/golang-api-example/infrastructure"
+	"github.com/desarrollogj/golang-api-example/libs/errors"
+	"github.com/desarrollogj/golang-api-example/libs/logger"
+	"github.com/google/uuid"
+)
+
+// userStatusTransitions are the allowed user status transitions, keyed by the current status
+var userStatusTransitions = map[domain.UserStatus][]domain.UserStatus{
+	domain.UserStatusPending:   {domain.UserStatusActive, domain.UserStatusDeleted},
+	domain.UserStatusActive:    {domain.UserStatusSuspended, domain.UserStatusDeleted},
+	domain.UserStatusSuspended: {domain.UserStatusActive, domain.UserStatusDeleted},
+	domain.UserStatusDeleted:   {domain.UserStatusActive},
+}
+
+// CanTransition returns true when an user can change from one status to another
+func CanTransition(from domain.UserStatus, to domain.UserStatus) bool {
+	for _, status := range userStatusTransitions[from] {
+		if status == to {
+			return true
+		}
+	}
+
+	return false
+}
+
+// statusOf returns the user status. Users without status are active or deleted, depending on their active mark
+func statusOf(user domain.User) domain.UserStatus {
+	return domain.ResolveUserStatus(user.Status, user.IsActive)
+}
+
+// transition changes the user status, keeping the active mark in sync. Illegal transitions are validation errors
+func transition(user *domain.User, to domain.UserStatus, reason string, date time.Time) error {
+	from := statusOf(*user)
+	if !CanTransition(from, to) {
+		return errors.NewValidationError(fmt.Sprintf("user can not change from %s to %s", from, to)).WithKey("user_status_transition_not_valid", from, to)
+	}
+
+	user.Status = to
+	user.StatusReason = reason
+	user.StatusDate = date
+	user.IsActive = to == domain.UserStatusActive
+	user.UpdatedDate = date
+	return nil
+}
+
+// ChangeStatus represents the method to be implemented to change the status of an user
+type ChangeStatus interface {
+	Execute(input domain.UserStatusChangeInput) (domain.User, error)
+}
+
+// defaultChangeStatus is the default implementation of ChangeStatus interface
+type defaultChangeStatus struct {
+	repository      infrastructure.UserRepository
+	auditRepository infrastructure.AuditRepository
+}
+
+// NewDefaultChangeStatus creates a defaultChangeStatus instance
+func NewDefaultChangeStatus(repository infrastructure.UserRepository, auditRepository infrastructure.AuditRepository) defaultChangeStatus {
+	return defaultChangeStatus{
+		repository:      repository,
+		auditRepository: auditRepository,
+	}
+}
+
+// Execute changes the User status, following the status transitions, and registers the change in the audit
+func (s defaultChangeStatus) Execute(input domain.UserStatusChangeInput) (domain.User, error) {
+	if input.Status == domain.UserStatusSuspended && len(input.Reason) == 0 {
+		return domain.User{}, errors.NewValidationError("a reason is required to suspend an user").WithKey("user_suspension_reason_required")
+	}
+
+	currentUser, err := s.repository.FindByReference(input.Reference)
+	if err != nil {
+		errMsg := fmt.Sprintf("unexpected error when try to get user with reference %s", input.Reference)
+		logger.AppLog.Error().Err(err).Msg(errMsg)
+		return domain.User{}, errors.NewFatalError(errMsg)
+	}
+	if len(currentUser.Reference) == 0 {
+		return domain.User{}, errors.NewNotFoundError("user not found").WithKey("user_not_found")
+	}
+	if currentUser.ErasedDate != nil {
+		return domain.User{}, errors.NewValidationError("user was erased and can not be updated").WithKey("user_erased")
+	}
+	if len(currentUser.MergedInto) > 0 {
+		return domain.User{}, errors.NewValidationError(fmt.Sprintf("user was merged into %s and can not be updated", currentUser.MergedInto)).WithKey("user_merged", currentUser.MergedInto)
+	}
+
+	previous := statusOf(currentUser)
+	changed := time.Now().UTC()
+	if err := transition(&currentUser, input.Status, input.Reason, changed); err != nil {
+		return domain.User{}, err
+	}
+
+	updated, err := s.repository.ChangeStatus(currentUser, previous)
+	if err != nil {
+		return domain.User{}, storeError(err, "unexpected error when change the user status")
+	}
+
+	_, err = s.auditRepository.Create(domain.AuditEntry{
+		Reference:       uuid.NewString(),
+		Action:          domain.AuditActionUserStatus,
+		EntityType:      domain.AuditEntityUser,
+		EntityReference: updated.Reference,
+		Details: map[string]string{
+			"from":   string(previous),
+			"to":     string(input.Status),
+			"reason": input.Reason,
+		},
+		CreatedDate: changed,
+	})
+	if err != nil {
+		errMsg := "unexpected error when register the user status change"
+		logger.AppLog.Error().Err(err).Msg(errMsg)
+		return domain.User{}, errors.NewFatalError(errMsg)
+	}
+
+	return updated, nil
+}
@@ -0,0 +1,314 @@
+package user
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/desarrollogj/golang-api-example/domain"
+	"github.com/desarrollogj/golang-api-example/infrastructure"
+	"github.com/desarrollogj/golang-api-example/libs/encryption"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestCanTransition_GivenTheUserStatuses_WhenCheck_ThenFollowTheTransitionTable(t *testing.T) {
+	t.Log("Only the transitions in the table are allowed")
+
+	assert.True(t, CanTransition(domain.UserStatusPending, domain.UserStatusActive))
+	assert.True(t, CanTransition(domain.UserStatusPending, domain.UserStatusDeleted))
+	assert.True(t, CanTransition(domain.UserStatusActive, domain.UserStatusSuspended))
+	assert.True(t, CanTransition(domain.UserStatusActive, domain.UserStatusDeleted))
+	assert.True(t, CanTransition(domain.UserStatusSuspended, domain.UserStatusActive))
+	assert.True(t, CanTransition(domain.UserStatusSuspended, domain.UserStatusDeleted))
+	assert.True(t, CanTransition(domain.UserStatusDeleted, domain.UserStatusActive))
+
+	assert.False(t, CanTransition(domain.UserStatusPending, domain.UserStatusSuspended))
+	assert.False(t, CanTransition(domain.UserStatusActive, domain.UserStatusActive))
+	assert.False(t, CanTransition(domain.UserStatusActive, domain.UserStatusPending))
+	assert.False(t, CanTransition(domain.UserStatusDeleted, domain.UserStatusSuspended))
+	assert.False(t, CanTransition(domain.UserStatus("unknown"), domain.UserStatusActive))
+}
+
+func TestChangeStatus_GivenAnActiveUser_WhenSuspend_ThenSuspendTheUser(t *testing.T) {
+	t.Log("Successfully suspend an User")
+
+	reference := "REF1"
+	currentUser := domain.User{
+		GenericEntity: domain.GenericEntity{
+			Reference: reference,
+			IsActive:  true,
+		},
+		Status: domain.UserStatusActive,
+	}
+	suspendedUser := domain.User{
+		GenericEntity: domain.GenericEntity{
+			Reference: reference,
+			IsActive:  false,
+		},
+		Status:       domain.UserStatusSuspended,
+		StatusReason: "abuse report",
+		StatusDate:   time.Now().UTC(),
+	}
+	repositoryMock := new(repositoryMock)
+	repositoryMock.On("FindByReference", reference).Return(currentUser, nil)
+	repositoryMock.On("ChangeStatus", mock.MatchedBy(func(user domain.User) bool {
+		return user.Status == domain.UserStatusSuspended &&
+			user.StatusReason == "abuse report" &&
+			!user.IsActive &&
+			!user.StatusDate.IsZero() &&
+			user.UpdatedDate == user.StatusDate
+	}), domain.UserStatusActive).Return(suspendedUser, nil)
+	auditRepositoryMock := new(auditRepositoryMock)
+	auditRepositoryMock.On("Create", mock.MatchedBy(func(entry domain.AuditEntry) bool {
+		return entry.Action == domain.AuditActionUserStatus &&
+			entry.EntityReference == reference &&
+			entry.Details["from"] == "active" &&
+			entry.Details["to"] == "suspended" &&
+			entry.Details["reason"] == "abuse report"
+	})).Return(domain.AuditEntry{}, nil)
+
+	useCase := NewDefaultChangeStatus(repositoryMock, auditRepositoryMock)
+
+	updated, err := useCase.Execute(domain.UserStatusChangeInput{
+		Reference: reference,
+		Status:    domain.UserStatusSuspended,
+		Reason:    "abuse report",
+	})
+
+	assert.Nil(t, err)
+	assert.Equal(t, suspendedUser, updated)
+
+	repositoryMock.AssertExpectations(t)
+	auditRepositoryMock.AssertExpectations(t)
+}
+
+func TestChangeStatus_GivenAnUserWithoutStatus_WhenReactivate_ThenUseTheActiveMark(t *testing.T) {
+	t.Log("Successfully reactivate a deleted User stored without status")
+
+	reference := "REF1"
+	currentUser := domain.User{
+		GenericEntity: domain.GenericEntity{
+			Reference: reference,
+			IsActive:  false,
+		},
+	}
+	repositoryMock := new(repositoryMock)
+	repositoryMock.On("FindByReference", reference).Return(currentUser, nil)
+	repositoryMock.On("ChangeStatus", mock.MatchedBy(func(user domain.User) bool {
+		return user.Status == domain.UserStatusActive && user.IsActive
+	}), domain.UserStatusDeleted).Return(currentUser, nil)
+	auditRepositoryMock := new(auditRepositoryMock)
+	auditRepositoryMock.On("Create", mock.MatchedBy(func(entry domain.AuditEntry) bool {
+		return entry.Details["from"] == "deleted" && entry.Details["to"] == "active"
+	})).Return(domain.AuditEntry{}, nil)
+
+	useCase := NewDefaultChangeStatus(repositoryMock, auditRepositoryMock)
+
+	_, err := useCase.Execute(domain.UserStatusChangeInput{Reference: reference, Status: domain.UserStatusActive})
+
+	assert.Nil(t, err)
+
+	repositoryMock.AssertExpectations(t)
+	auditRepositoryMock.AssertExpectations(t)
+}
+
+func TestChangeStatus_GivenAnIllegalTransition_WhenExecute_ThenReturnAValidationError(t *testing.T) {
+	t.Log("Failure to change the User status because the transition is not allowed")
+
+	reference := "REF1"
+	currentUser := domain.User{
+		GenericEntity: domain.GenericEntity{
+			Reference: reference,
+		},
+		Status: domain.UserStatusPending,
+	}
+	repositoryMock := new(repositoryMock)
+	repositoryMock.On("FindByReference", reference).Return(currentUser, nil)
+	auditRepositoryMock := new(auditRepositoryMock)
+
+	useCase := NewDefaultChangeStatus(repositoryMock, auditRepositoryMock)
+
+	_, err := useCase.Execute(domain.UserStatusChangeInput{
+		Reference: reference,
+		Status:    domain.UserStatusSuspended,
+		Reason:    "abuse report",
+	})
+
+	assert.NotNil(t, err)
+	assert.Equal(t, "user can not change from pending to suspended", err.Error())
+
+	repositoryMock.AssertExpectations(t)
+	repositoryMock.AssertNotCalled(t, "ChangeStatus", mock.Anything, mock.Anything)
+}
+
+func TestChangeStatus_GivenASuspensionWithoutReason_WhenExecute_ThenReturnAValidationError(t *testing.T) {
+	t.Log("Failure to suspend an User without a reason")
+
+	repositoryMock := new(repositoryMock)
+	auditRepositoryMock := new(auditRepositoryMock)
+
+	useCase := NewDefaultChangeStatus(repositoryMock, auditRepositoryMock)
+
+	_, err := useCase.Execute(domain.UserStatusChangeInput{Reference: "REF1", Status: domain.UserStatusSuspended})
+
+	assert.NotNil(t, err)
+	assert.Equal(t, "a reason is required to suspend an user", err.Error())
+}
+
+func TestChangeStatus_GivenAnUnknownUser_WhenExecute_ThenReturnANotFoundError(t *testing.T) {
+	t.Log("Failure to change the User status because it was not found")
+
+	reference := "REF1"
+	repositoryMock := new(repositoryMock)
+	repositoryMock.On("FindByReference", reference).Return(domain.User{}, nil)
+	auditRepositoryMock := new(auditRepositoryMock)
+
+	useCase := NewDefaultChangeStatus(repositoryMock, auditRepositoryMock)
+
+	_, err := useCase.Execute(domain.UserStatusChangeInput{Reference: reference, Status: domain.UserStatusActive})
+
+	assert.NotNil(t, err)
+	assert.Equal(t, "user not found", err.Error())
+
+	repositoryMock.AssertExpectations(t)
+}
+
+func TestChangeStatus_GivenAnErasedUser_WhenExecute_ThenReturnAValidationError(t *testing.T) {
+	t.Log("Failure to change the User status because it was erased")
+
+	reference := "REF1"
+	erasedDate := time.Now().UTC()
+	currentUser := domain.User{
+		GenericEntity: domain.GenericEntity{
+			Reference: reference,
+		},
+		Status:     domain.UserStatusDeleted,
+		ErasedDate: &erasedDate,
+	}
+	repositoryMock := new(repositoryMock)
+	repositoryMock.On("FindByReference", reference).Return(currentUser, nil)
+	auditRepositoryMock := new(auditRepositoryMock)
+
+	useCase := NewDefaultChangeStatus(repositoryMock, auditRepositoryMock)
+
+	_, err := useCase.Execute(domain.UserStatusChangeInput{Reference: reference, Status: domain.UserStatusActive})
+
+	assert.NotNil(t, err)
+	assert.Equal(t, "user was erased and can not be updated", err.Error())
+
+	repositoryMock.AssertExpectations(t)
+}
+
+func TestChangeStatus_GivenAMergedUser_WhenExecute_ThenReturnAValidationError(t *testing.T) {
+	t.Log("Failure to change the User status because it was merged")
+
+	reference := "REF1"
+	currentUser := domain.User{
+		GenericEntity: domain.GenericEntity{
+			Reference: reference,
+		},
+		Status:     domain.UserStatusDeleted,
+		MergedInto: "REF2",
+	}
+	repositoryMock := new(repositoryMock)
+	repositoryMock.On("FindByReference", reference).Return(currentUser, nil)
+
+	useCase := NewDefaultChangeStatus(repositoryMock, new(auditRepositoryMock))
+
+	_, err := useCase.Execute(domain.UserStatusChangeInput{Reference: reference, Status: domain.UserStatusActive})
+
+	assert.NotNil(t, err)
+	assert.Equal(t, "user was merged into REF2 and can not be updated", err.Error())
+
+	repositoryMock.AssertNotCalled(t, "ChangeStatus", mock.Anything, mock.Anything)
+}
+
+func TestChangeStatus_GivenAnUser_WhenExecuteAndUpdateReturnedAnError_ThenReturnAFatalError(t *testing.T) {
+	t.Log("Failure to change the User status because update returned an unexpected error")
+
+	reference := "REF1"
+	currentUser := domain.User{
+		GenericEntity: domain.GenericEntity{
+			Reference: reference,
+			IsActive:  true,
+		},
+		Status: domain.UserStatusActive,
+	}
+	repositoryMock := new(repositoryMock)
+	repositoryMock.On("FindByReference", reference).Return(currentUser, nil)
+	repositoryMock.On("ChangeStatus", mock.AnythingOfType("User"), domain.UserStatusActive).Return(domain.User{}, errors.New("repository error"))
+	auditRepositoryMock := new(auditRepositoryMock)
+
+	useCase := NewDefaultChangeStatus(repositoryMock, auditRepositoryMock)
+
+	_, err := useCase.Execute(domain.UserStatusChangeInput{Reference: reference, Status: domain.UserStatusDeleted})
+
+	assert.NotNil(t, err)
+	assert.Equal(t, "unexpected error when change the user status", err.Error())
+
+	repositoryMock.AssertExpectations(t)
+	auditRepositoryMock.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+func TestChangeStatus_GivenAnUserEncryptedWithAnUnknownKey_WhenExecute_ThenReturnAnUnprocessableError(t *testing.T) {
+	t.Log("Failure to change the User status because its personal data can not be decrypted, so it is not stored blank")
+
+	reference := "REF1"
+	previousKeyring, _ := encryption.NewKeyring("KEY1", map[string][]byte{"KEY1": bytes.Repeat([]byte{1}, 32)}, bytes.Repeat([]byte{3}, 32))
+	keyring, _ := encryption.NewKeyring("KEY2", map[string][]byte{"KEY2": bytes.Repeat([]byte{2}, 32)}, bytes.Repeat([]byte{3}, 32))
+	stored, err := infrastructure.NewEncryptedMongoRepositoryMapper(infrastructure.NewDefaultMongoRepositoryMapper(), previousKeyring).
+		MapDomainToRepository(domain.User{
+			GenericEntity: domain.GenericEntity{Reference: reference, IsActive: true},
+			FirstName:     "Foo",
+			Email:         "foo@email.com",
+			Status:        domain.UserStatusActive,
+		})
+	assert.Nil(t, err)
+	currentUser := infrastructure.NewEncryptedMongoRepositoryMapper(infrastructure.NewDefaultMongoRepositoryMapper(), keyring).
+		MapRepositoryToDomain(stored)
+
+	repositoryMock := new(repositoryMock)
+	repositoryMock.On("FindByReference", reference).Return(currentUser, nil)
+	repositoryMock.On("ChangeStatus", mock.MatchedBy(func(user domain.User) bool {
+		return user.Undecryptable && user.Status == domain.UserStatusSuspended
+	}), domain.UserStatusActive).Return(domain.User{}, infrastructure.ErrUndecryptableUser)
+	auditRepositoryMock := new(auditRepositoryMock)
+
+	useCase := NewDefaultChangeStatus(repositoryMock, auditRepositoryMock)
+
+	_, err = useCase.Execute(domain.UserStatusChangeInput{Reference: reference, Status: domain.UserStatusSuspended, Reason: "abuse report"})
+
+	assert.NotNil(t, err)
+	assert.Equal(t, "the user personal data can not be decrypted, so the user can not be updated", err.Error())
+	assert.True(t, currentUser.Undecryptable)
+	assert.Equal(t, "", currentUser.FirstName)
+
+	repositoryMock.AssertExpectations(t)
+	auditRepositoryMock.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+func TestChangeStatus_GivenAnUserWhoseStatusChangedMeanwhile_WhenExecute_ThenReturnAConflictError(t *testing.T) {
+	t.Log("Failure to change the User status because another change was stored after the user was read")
+
+	reference := "REF1"
+	currentUser := domain.User{
+		GenericEntity: domain.GenericEntity{Reference: reference, IsActive: true},
+		Status:        domain.UserStatusActive,
+	}
+	repositoryMock := new(repositoryMock)
+	repositoryMock.On("FindByReference", reference).Return(currentUser, nil)
+	repositoryMock.On("ChangeStatus", mock.MatchedBy(func(user domain.User) bool {
+		return user.Status == domain.UserStatusSuspended
+	}), domain.UserStatusActive).Return(domain.User{}, infrastructure.ErrUserModified)
+	auditRepositoryMock := new(auditRepositoryMock)
+
+	useCase := NewDefaultChangeStatus(repositoryMock, auditRepositoryMock)
+
+	_, err := useCase.Execute(domain.UserStatusChangeInput{Reference: reference, Status: domain.UserStatusSuspended, Reason: "abuse report"})
+
+	assert.NotNil(t, err)
+	assert.Equal(t, "the user was modified while it was updated, try again", err.Error())
+	auditRepositoryMock.AssertNotCalled(t, "Create", mock.Anything)
+}
@@ -0,0 +1,84 @@
+package user
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/desarrollogj/golang-api-example/domain"
+	"github.com/desarrollogj/golang-api-example/infrastructure"
+	"github.com/desarrollogj/golang-api-example/libs/errors"
+	"github.com/desarrollogj/golang-api-example/libs/logger"
+)
+
+const (
+	maxTagLength = 50
+	maxUserTags  = 50
+)
+
+// NormalizeTag returns the tag in lower case, with its spaces replaced by hyphens.
+// Tags can only have letters, digits, hyphens, underscores, colons and dots
+func NormalizeTag(tag string) (string, error) {
+	normalized := strings.Join(strings.Fields(strings.ToLower(tag)), "-")
+	if len(normalized) == 0 {
+		return "", errors.NewValidationError("tag is required").WithKey("tag_required")
+	}
+	if utf8.RuneCountInString(normalized) > maxTagLength {
+		return "", errors.NewValidationError(fmt.Sprintf("tag %s is longer than %d characters", normalized, maxTagLength)).WithKey("tag_too_long", normalized, maxTagLength)
+	}
+	for _, r := range normalized {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && !strings.ContainsRune("-_:.", r) {
+			return "", errors.NewValidationError(fmt.Sprintf("tag %s is not valid", normalized)).WithKey("tag_not_valid", normalized)
+		}
+	}
+
+	return normalized, nil
+}
+
+// normalizeTags returns the normalized tags, sorted and without duplicates
+func normalizeTags(tags []string) ([]string, error) {
+	normalized := []string{}
+	for _, tag := range tags {
+		tag, err := NormalizeTag(tag)
+		if err != nil {
+			return nil, err
+		}
+		if !containsString(normalized, tag) {
+			normalized = append(normalized, tag)
+		}
+	}
+	if len(normalized) > maxUserTags {
+		return nil, tagsLimitError()
+	}
+	if len(normalized) == 0 {
+		return nil, nil
+	}
+
+	sort.Strings(normalized)
+	return normalized, nil
+}
+
+// tagsLimitError is the error of an user with more tags than allowed
+func tagsLimitError() error {
+	return errors.NewValidationError(fmt.Sprintf("an user can not have more than %d tags", maxUserTags)).WithKey("user_tags_limit", maxUserTags)
+}
+
+// findUserToTag returns the user whose tags are changed, that must exist and must not be erased
+func findUserToTag(repository infrastructure.UserRepository, reference string) (domain.User, error) {
+	currentUser, err := repository.FindByReference(reference)
+	if err != nil {
+		errMsg := fmt.Sprintf("unexpected error when try to get user with reference %s", reference)
+		logger.AppLog.Error().Err(err).Msg(errMsg)
+		return domain.User{}, errors.NewFatalError(errMsg)
+	}
+	if len(currentUser.Reference) == 0 {
+		return domain.User{}, errors.NewNotFoundError("user not found").WithKey("user_not_found")
+	}
+	if currentUser.ErasedDate != nil {
+		return domain.User{}, errors.NewValidationError("user was erased and can not be updated").WithKey("user_erased")
+	}
+
+	return currentUser, nil
+}
@@ -0,0 +1,56 @@
+package user
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeTag_GivenTags_WhenNormalize_ThenReturnTheNormalizedTagOrAValidationError(t *testing.T) {
+	t.Log("Successfully normalize the tags")
+
+	tests := []struct {
+		tag      string
+		expected string
+		err      string
+	}{
+		{" VIP ", "vip", ""},
+		{"Early  Adopter", "early-adopter", ""},
+		{"team:sales", "team:sales", ""},
+		{"año.2024", "año.2024", ""},
+		{" ", "", "tag is required"},
+		{"vip!", "", "tag vip! is not valid"},
+		{strings.Repeat("a", 51), "", "tag " + strings.Repeat("a", 51) + " is longer than 50 characters"},
+	}
+	for _, test := range tests {
+		tag, err := NormalizeTag(test.tag)
+		if len(test.err) > 0 {
+			assert.EqualError(t, err, test.err)
+			continue
+		}
+		assert.Nil(t, err)
+		assert.Equal(t, test.expected, tag)
+	}
+}
+
+func TestNormalizeTags_GivenTags_WhenNormalize_ThenReturnSortedTagsWithoutDuplicates(t *testing.T) {
+	t.Log("Successfully normalize a tag list")
+
+	tags, err := normalizeTags([]string{"VIP", "beta", "vip"})
+
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"beta", "vip"}, tags)
+
+	tags, err = normalizeTags([]string{})
+	assert.Nil(t, err)
+	assert.Nil(t, tags)
+
+	tooMany := []string{}
+	for i := 0; i <= maxUserTags; i++ {
+		tooMany = append(tooMany, fmt.Sprintf("tag%d", i))
+	}
+	_, err = normalizeTags(tooMany)
+	assert.EqualError(t, err, "an user can not have more than 50 tags")
+}
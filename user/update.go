@@ -17,12 +17,14 @@ type Update interface {
 
 // defaultUpdate is the default implementation of Update interface
 type defaultUpdate struct {
+	config     domain.UserAttributesConfiguration
 	repository infrastructure.UserRepository
 }
 
 // NewDefaultUpdate creates a defaultUpdate instance
-func NewDefaultUpdate(repository infrastructure.UserRepository) defaultUpdate {
+func NewDefaultUpdate(config domain.UserAttributesConfiguration, repository infrastructure.UserRepository) defaultUpdate {
 	return defaultUpdate{
+		config:     config,
 		repository: repository,
 	}
 }
@@ -36,20 +38,60 @@ func (s defaultUpdate) Execute(input domain.UserUpdateInput) (domain.User, error
 		return domain.User{}, errors.NewFatalError(errMsg)
 	}
 	if len(currentUser.Reference) == 0 {
-		return domain.User{}, errors.NewNotFoundError("user not found")
+		return domain.User{}, errors.NewNotFoundError("user not found").WithKey("user_not_found")
+	}
+	if currentUser.ErasedDate != nil {
+		return domain.User{}, errors.NewValidationError("user was erased and can not be updated").WithKey("user_erased")
+	}
+	if len(currentUser.MergedInto) > 0 {
+		return domain.User{}, errors.NewValidationError(fmt.Sprintf("user was merged into %s and can not be updated", currentUser.MergedInto)).WithKey("user_merged", currentUser.MergedInto)
+	}
+
+	profile, err := normalizeProfile(input.UserProfile)
+	if err != nil {
+		return domain.User{}, err
+	}
+	attributes, err := normalizeAttributes(s.config, input.Attributes)
+	if err != nil {
+		return domain.User{}, err
+	}
+	tags, err := normalizeTags(input.Tags)
+	if err != nil {
+		return domain.User{}, err
+	}
+	externalIDs, err := normalizeExternalIDs(input.ExternalIDs)
+	if err != nil {
+		return domain.User{}, err
+	}
+	err = checkExternalIDsAvailable(s.repository, currentUser.Reference, externalIDs)
+	if err != nil {
+		return domain.User{}, err
 	}
 
+	updatedDate := time.Now().UTC()
+	// Updating a deleted user reactivates it. Suspended and pending users keep their status
+	if statusOf(currentUser) == domain.UserStatusDeleted {
+		if err := transition(&currentUser, domain.UserStatusActive, "", updatedDate); err != nil {
+			return domain.User{}, err
+		}
+	}
+
+	currentUser.UserProfile = profile
 	currentUser.FirstName = input.FirstName
 	currentUser.LastName = input.LastName
+	currentUser.Attributes = attributes
+	currentUser.Tags = tags
+	currentUser.ExternalIDs = externalIDs
+	if currentUser.Email != input.Email {
+		// A new email must be verified again
+		currentUser.EmailVerifiedDate = nil
+	}
 	currentUser.Email = input.Email
-	currentUser.IsActive = true
-	currentUser.UpdatedDate = time.Now().UTC()
+	currentUser.UpdatedDate = updatedDate
 
 	updated, err := s.repository.Update(currentUser)
 	if err != nil {
-		errMsg := "unexpected error when update the user"
-		logger.AppLog.Error().Err(err).Msg(errMsg)
-		return domain.User{}, errors.NewFatalError(errMsg)
+		return domain.User{}, storeError(err, "unexpected error when update the user")
 	}
 
 	return updated, nil
@@ -3,6 +3,7 @@ package user
 import (
 	"errors"
 	"testing"
+	"time"
 
 	"github.com/desarrollogj/golang-api-example/domain"
 	"github.com/stretchr/testify/assert"
@@ -41,7 +42,7 @@ func TestUpdate_GivenAnUser_WhenExecute_ThenUpdateAnUser(t *testing.T) {
 	repositoryMock.On("FindByReference", reference).Return(currentUser, nil)
 	repositoryMock.On("Update", mock.AnythingOfType("User")).Return(updatedUser, nil)
 
-	useCase := NewDefaultUpdate(repositoryMock)
+	useCase := NewDefaultUpdate(attributesConfig, repositoryMock)
 
 	updated, err := useCase.Execute(input)
 
@@ -67,7 +68,7 @@ func TestUpdate_GivenAnUser_WhenExecuteAndFindReturnedAnError_ThenReturnAnError(
 	repositoryMock := new(repositoryMock)
 	repositoryMock.On("FindByReference", reference).Return(domain.User{}, errors.New("repository error"))
 
-	useCase := NewDefaultUpdate(repositoryMock)
+	useCase := NewDefaultUpdate(attributesConfig, repositoryMock)
 
 	_, err := useCase.Execute(input)
 
@@ -92,7 +93,7 @@ func TestUpdate_GivenAnUser_WhenExecuteAndUserNotFound_ThenReturnANotFoundError(
 	repositoryMock := new(repositoryMock)
 	repositoryMock.On("FindByReference", reference).Return(domain.User{}, nil)
 
-	useCase := NewDefaultUpdate(repositoryMock)
+	useCase := NewDefaultUpdate(attributesConfig, repositoryMock)
 
 	_, err := useCase.Execute(input)
 
@@ -126,7 +127,7 @@ func TestUpdate_GivenAnUser_WhenExecuteAndUpdateReturnedAnError_ThenReturnAFatal
 	repositoryMock.On("FindByReference", reference).Return(currentUser, nil)
 	repositoryMock.On("Update", mock.AnythingOfType("User")).Return(domain.User{}, errors.New("repository error"))
 
-	useCase := NewDefaultUpdate(repositoryMock)
+	useCase := NewDefaultUpdate(attributesConfig, repositoryMock)
 
 	_, err := useCase.Execute(input)
 
@@ -135,3 +136,184 @@ func TestUpdate_GivenAnUser_WhenExecuteAndUpdateReturnedAnError_ThenReturnAFatal
 
 	repositoryMock.AssertExpectations(t)
 }
+
+func TestUpdate_GivenAnErasedUser_WhenExecute_ThenReturnAValidationError(t *testing.T) {
+	t.Log("Failure to update an User because it was erased")
+
+	reference := "REF1"
+	erased := time.Now().UTC()
+	input := domain.UserUpdateInput{
+		UserCreateInput: domain.UserCreateInput{
+			FirstName: "Foo",
+			LastName:  "Bar",
+			Email:     "foobar@email.com",
+		},
+		Reference: reference,
+	}
+	currentUser := domain.User{
+		GenericEntity: domain.GenericEntity{
+			Reference: reference,
+		},
+		FirstName:  "erased",
+		LastName:   "erased",
+		Email:      "erased-REF1@erased.invalid",
+		ErasedDate: &erased,
+	}
+	repositoryMock := new(repositoryMock)
+	repositoryMock.On("FindByReference", reference).Return(currentUser, nil)
+
+	useCase := NewDefaultUpdate(attributesConfig, repositoryMock)
+
+	_, err := useCase.Execute(input)
+
+	assert.NotNil(t, err)
+	assert.Equal(t, "user was erased and can not be updated", err.Error())
+
+	repositoryMock.AssertExpectations(t)
+}
+
+func TestUpdate_GivenAMergedUser_WhenExecute_ThenReturnAValidationError(t *testing.T) {
+	t.Log("Failure to update an User because it was merged into another User")
+
+	reference := "REF1"
+	input := domain.UserUpdateInput{
+		UserCreateInput: domain.UserCreateInput{
+			FirstName: "Foo",
+			LastName:  "Bar",
+			Email:     "foobar@email.com",
+		},
+		Reference: reference,
+	}
+	currentUser := domain.User{
+		GenericEntity: domain.GenericEntity{
+			Reference: reference,
+		},
+		FirstName:  "Foo",
+		LastName:   "Bar",
+		Email:      "foobar@email.com",
+		Status:     domain.UserStatusDeleted,
+		MergedInto: "REF2",
+	}
+	repositoryMock := new(repositoryMock)
+	repositoryMock.On("FindByReference", reference).Return(currentUser, nil)
+
+	useCase := NewDefaultUpdate(attributesConfig, repositoryMock)
+
+	_, err := useCase.Execute(input)
+
+	assert.NotNil(t, err)
+	assert.Equal(t, "user was merged into REF2 and can not be updated", err.Error())
+
+	repositoryMock.AssertExpectations(t)
+	repositoryMock.AssertNotCalled(t, "Update", mock.Anything)
+}
+
+func TestUpdate_GivenADeletedOrSuspendedUser_WhenExecute_ThenOnlyReactivateDeletedUsers(t *testing.T) {
+	t.Log("Updating a deleted User reactivates it, but a suspended User keeps its status")
+
+	cases := map[domain.UserStatus]domain.UserStatus{
+		domain.UserStatusDeleted:   domain.UserStatusActive,
+		domain.UserStatusSuspended: domain.UserStatusSuspended,
+		domain.UserStatusPending:   domain.UserStatusPending,
+	}
+
+	for current, expected := range cases {
+		reference := "REF1"
+		input := domain.UserUpdateInput{
+			UserCreateInput: domain.UserCreateInput{
+				FirstName: "Foo",
+				LastName:  "Bar",
+				Email:     "foobar@email.com",
+			},
+			Reference: reference,
+		}
+		currentUser := domain.User{
+			GenericEntity: domain.GenericEntity{
+				Reference: reference,
+			},
+			Status: current,
+		}
+		expectedStatus := expected
+		repositoryMock := new(repositoryMock)
+		repositoryMock.On("FindByReference", reference).Return(currentUser, nil)
+		repositoryMock.On("Update", mock.MatchedBy(func(user domain.User) bool {
+			return user.Status == expectedStatus && user.IsActive == (expectedStatus == domain.UserStatusActive)
+		})).Return(currentUser, nil)
+
+		useCase := NewDefaultUpdate(attributesConfig, repositoryMock)
+
+		_, err := useCase.Execute(input)
+
+		assert.Nil(t, err, string(current))
+		repositoryMock.AssertExpectations(t)
+	}
+}
+
+func TestUpdate_GivenAnUserWithANewEmail_WhenExecute_ThenTheEmailIsNotVerified(t *testing.T) {
+	t.Log("Successfully update an User email, which must be verified again")
+
+	verified := time.Now().UTC()
+	input := domain.UserUpdateInput{
+		UserCreateInput: domain.UserCreateInput{
+			FirstName: "Foo",
+			LastName:  "Bar",
+			Email:     "new@email.com",
+		},
+		Reference: "REF1",
+	}
+	currentUser := domain.User{
+		GenericEntity:     domain.GenericEntity{Reference: "REF1", IsActive: true},
+		FirstName:         "Foo",
+		LastName:          "Bar",
+		Email:             "foobar@email.com",
+		EmailVerifiedDate: &verified,
+	}
+	repositoryMock := new(repositoryMock)
+	repositoryMock.On("FindByReference", "REF1").Return(currentUser, nil)
+	repositoryMock.On("Update", mock.MatchedBy(func(user domain.User) bool {
+		return user.Email == "new@email.com" && user.EmailVerifiedDate == nil
+	})).Return(currentUser, nil)
+
+	useCase := NewDefaultUpdate(attributesConfig, repositoryMock)
+
+	_, err := useCase.Execute(input)
+
+	assert.Nil(t, err)
+
+	repositoryMock.AssertExpectations(t)
+}
+
+func TestUpdate_GivenAnExternalIDOfAnotherUser_WhenExecute_ThenReturnAConflictError(t *testing.T) {
+	t.Log("Failure to update an User because an external id belongs to another user")
+
+	input := domain.UserUpdateInput{
+		UserCreateInput: domain.UserCreateInput{
+			FirstName:   "Foo",
+			LastName:    "Bar",
+			Email:       "foobar@email.com",
+			ExternalIDs: []domain.UserExternalID{{Source: "crm", ID: "C-1"}, {Source: "erp", ID: "42"}},
+		},
+		Reference: "REF1",
+	}
+	currentUser := domain.User{
+		GenericEntity: domain.GenericEntity{Reference: "REF1", IsActive: true},
+		FirstName:     "Foo",
+		LastName:      "Bar",
+		Email:         "foobar@email.com",
+		ExternalIDs:   []domain.UserExternalID{{Source: "crm", ID: "C-1"}},
+	}
+	repositoryMock := new(repositoryMock)
+	repositoryMock.On("FindByReference", "REF1").Return(currentUser, nil)
+	repositoryMock.On("FindByExternalID", "crm", "C-1").Return(currentUser, nil)
+	repositoryMock.On("FindByExternalID", "erp", "42").Return(domain.User{GenericEntity: domain.GenericEntity{Reference: "REF2"}}, nil)
+
+	useCase := NewDefaultUpdate(attributesConfig, repositoryMock)
+
+	_, err := useCase.Execute(input)
+
+	assert.NotNil(t, err)
+	assert.Equal(t, "external id erp/42 belongs to another user", err.Error())
+
+	repositoryMock.AssertExpectations(t)
+	repositoryMock.AssertNotCalled(t, "Update", mock.Anything)
+}
@@ -2,8 +2,10 @@ package user
 
 import (
 	"errors"
+	"time"
 
 	"github.com/desarrollogj/golang-api-example/domain"
+	"github.com/desarrollogj/golang-api-example/libs/mail"
 	"github.com/stretchr/testify/mock"
 )
 
@@ -44,6 +46,28 @@ func (m *repositoryMock) FindByReference(reference string) (domain.User, error)
 	return user, args.Error(1)
 }
 
+func (m *repositoryMock) FindAllActiveByEmail(email string) ([]domain.User, error) {
+	args := m.Called(email)
+
+	users, ok := args.Get(0).([]domain.User)
+	if !ok {
+		return []domain.User{}, errors.New("mock error")
+	}
+
+	return users, args.Error(1)
+}
+
+func (m *repositoryMock) FindByExternalID(source string, id string) (domain.User, error) {
+	args := m.Called(source, id)
+
+	user, ok := args.Get(0).(domain.User)
+	if !ok {
+		return domain.User{}, errors.New("mock error")
+	}
+
+	return user, args.Error(1)
+}
+
 func (m *repositoryMock) Create(user domain.User) (domain.User, error) {
 	args := m.Called(user)
 
@@ -66,8 +90,8 @@ func (m *repositoryMock) Update(user domain.User) (domain.User, error) {
 	return user, args.Error(1)
 }
 
-func (m *repositoryMock) Delete(reference string) (domain.User, error) {
-	args := m.Called(reference)
+func (m *repositoryMock) Patch(user domain.User) (domain.User, error) {
+	args := m.Called(user)
 
 	user, ok := args.Get(0).(domain.User)
 	if !ok {
@@ -77,7 +101,28 @@ func (m *repositoryMock) Delete(reference string) (domain.User, error) {
 	return user, args.Error(1)
 }
 
-func (m *repositoryMock) SearchActive(input domain.UserSearchInput) (domain.UserSearchOutput, error) {
+func (m *repositoryMock) AddTag(reference string, tag string, maxTags int, updatedDate time.Time) (bool, error) {
+	args := m.Called(reference, tag, maxTags, updatedDate)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *repositoryMock) RemoveTag(reference string, tag string, updatedDate time.Time) (bool, error) {
+	args := m.Called(reference, tag, updatedDate)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *repositoryMock) ChangeStatus(user domain.User, from domain.UserStatus) (domain.User, error) {
+	args := m.Called(user, from)
+
+	user, ok := args.Get(0).(domain.User)
+	if !ok {
+		return domain.User{}, errors.New("mock error")
+	}
+
+	return user, args.Error(1)
+}
+
+func (m *repositoryMock) Search(input domain.UserSearchInput) (domain.UserSearchOutput, error) {
 	args := m.Called(input)
 
 	user, ok := args.Get(0).(domain.UserSearchOutput)
@@ -87,3 +132,286 @@ func (m *repositoryMock) SearchActive(input domain.UserSearchInput) (domain.User
 
 	return user, args.Error(1)
 }
+
+func (m *repositoryMock) CountTags() ([]domain.TagCount, error) {
+	args := m.Called()
+
+	tags, ok := args.Get(0).([]domain.TagCount)
+	if !ok {
+		return []domain.TagCount{}, errors.New("mock error")
+	}
+
+	return tags, args.Error(1)
+}
+
+type auditRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *auditRepositoryMock) Create(entry domain.AuditEntry) (domain.AuditEntry, error) {
+	args := m.Called(entry)
+
+	entry, ok := args.Get(0).(domain.AuditEntry)
+	if !ok {
+		return domain.AuditEntry{}, errors.New("mock error")
+	}
+
+	return entry, args.Error(1)
+}
+
+func (m *auditRepositoryMock) FindByEntity(entityType string, entityReference string) ([]domain.AuditEntry, error) {
+	args := m.Called(entityType, entityReference)
+
+	entries, ok := args.Get(0).([]domain.AuditEntry)
+	if !ok {
+		return []domain.AuditEntry{}, errors.New("mock error")
+	}
+
+	return entries, args.Error(1)
+}
+
+type dataContributorMock struct {
+	mock.Mock
+}
+
+func (m *dataContributorMock) Name() string {
+	args := m.Called()
+	return args.String(0)
+}
+
+func (m *dataContributorMock) Contribute(user domain.User) ([]map[string]interface{}, error) {
+	args := m.Called(user)
+
+	records, ok := args.Get(0).([]map[string]interface{})
+	if !ok {
+		return nil, args.Error(1)
+	}
+
+	return records, args.Error(1)
+}
+
+type mailerMock struct {
+	mock.Mock
+}
+
+func (m *mailerMock) Send(message mail.Message) error {
+	args := m.Called(message)
+	return args.Error(0)
+}
+
+func (m *mailerMock) Purge(to string) error {
+	args := m.Called(to)
+	return args.Error(0)
+}
+
+type erasurePurgerMock struct {
+	mock.Mock
+}
+
+func (m *erasurePurgerMock) Name() string {
+	args := m.Called()
+	return args.String(0)
+}
+
+func (m *erasurePurgerMock) Purge(user domain.User) error {
+	args := m.Called(user)
+	return args.Error(0)
+}
+
+type emailVerifierMock struct {
+	mock.Mock
+}
+
+func (m *emailVerifierMock) Send(user domain.User) error {
+	args := m.Called(user)
+	return args.Error(0)
+}
+
+func (m *emailVerifierMock) Verify(token string) (EmailVerificationClaims, error) {
+	args := m.Called(token)
+
+	claims, ok := args.Get(0).(EmailVerificationClaims)
+	if !ok {
+		return EmailVerificationClaims{}, errors.New("mock error")
+	}
+
+	return claims, args.Error(1)
+}
+
+type credentialRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *credentialRepositoryMock) FindByUserReference(userReference string) (domain.Credential, error) {
+	args := m.Called(userReference)
+
+	credential, ok := args.Get(0).(domain.Credential)
+	if !ok {
+		return domain.Credential{}, errors.New("mock error")
+	}
+
+	return credential, args.Error(1)
+}
+
+func (m *credentialRepositoryMock) Save(credential domain.Credential) (domain.Credential, error) {
+	args := m.Called(credential)
+
+	credential, ok := args.Get(0).(domain.Credential)
+	if !ok {
+		return domain.Credential{}, errors.New("mock error")
+	}
+
+	return credential, args.Error(1)
+}
+
+func (m *credentialRepositoryMock) DeleteByUserReference(userReference string) error {
+	args := m.Called(userReference)
+	return args.Error(0)
+}
+
+type groupRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *groupRepositoryMock) FindAllActive() ([]domain.Group, error) {
+	args := m.Called()
+
+	groups, ok := args.Get(0).([]domain.Group)
+	if !ok {
+		return []domain.Group{}, errors.New("mock error")
+	}
+
+	return groups, args.Error(1)
+}
+
+func (m *groupRepositoryMock) FindActiveByReference(reference string) (domain.Group, error) {
+	args := m.Called(reference)
+
+	group, ok := args.Get(0).(domain.Group)
+	if !ok {
+		return domain.Group{}, errors.New("mock error")
+	}
+
+	return group, args.Error(1)
+}
+
+func (m *groupRepositoryMock) FindActiveByMember(userReference string) ([]domain.Group, error) {
+	args := m.Called(userReference)
+
+	groups, ok := args.Get(0).([]domain.Group)
+	if !ok {
+		return []domain.Group{}, errors.New("mock error")
+	}
+
+	return groups, args.Error(1)
+}
+
+func (m *groupRepositoryMock) FindByMember(userReference string) ([]domain.Group, error) {
+	args := m.Called(userReference)
+
+	groups, ok := args.Get(0).([]domain.Group)
+	if !ok {
+		return []domain.Group{}, errors.New("mock error")
+	}
+
+	return groups, args.Error(1)
+}
+
+func (m *groupRepositoryMock) Create(group domain.Group) (domain.Group, error) {
+	args := m.Called(group)
+
+	group, ok := args.Get(0).(domain.Group)
+	if !ok {
+		return domain.Group{}, errors.New("mock error")
+	}
+
+	return group, args.Error(1)
+}
+
+func (m *groupRepositoryMock) Update(group domain.Group) (domain.Group, error) {
+	args := m.Called(group)
+
+	group, ok := args.Get(0).(domain.Group)
+	if !ok {
+		return domain.Group{}, errors.New("mock error")
+	}
+
+	return group, args.Error(1)
+}
+
+func (m *groupRepositoryMock) AddMember(groupReference string, member domain.GroupMember) (bool, error) {
+	args := m.Called(groupReference, member)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *groupRepositoryMock) RemoveMember(groupReference string, userReference string, removedDate time.Time) (bool, error) {
+	args := m.Called(groupReference, userReference, removedDate)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *groupRepositoryMock) RemoveMemberFromAll(userReference string) (int64, error) {
+	args := m.Called(userReference)
+
+	removed, ok := args.Get(0).(int64)
+	if !ok {
+		return 0, errors.New("mock error")
+	}
+
+	return removed, args.Error(1)
+}
+
+func (m *groupRepositoryMock) MoveMember(userReference string, toUserReference string, movedDate time.Time) (int64, error) {
+	args := m.Called(userReference, toUserReference, movedDate)
+
+	moved, ok := args.Get(0).(int64)
+	if !ok {
+		return 0, errors.New("mock error")
+	}
+
+	return moved, args.Error(1)
+}
+
+type referenceGeneratorMock struct {
+	mock.Mock
+}
+
+func (m *referenceGeneratorMock) Generate() string {
+	args := m.Called()
+	return args.String(0)
+}
+
+type idempotencyRepositoryMock struct {
+	mock.Mock
+}
+
+func (m *idempotencyRepositoryMock) Acquire(record domain.IdempotencyRecord) (domain.IdempotencyRecord, bool, error) {
+	args := m.Called(record)
+
+	current, ok := args.Get(0).(domain.IdempotencyRecord)
+	if !ok {
+		return domain.IdempotencyRecord{}, false, errors.New("mock error")
+	}
+
+	return current, args.Bool(1), args.Error(2)
+}
+
+func (m *idempotencyRepositoryMock) Complete(record domain.IdempotencyRecord) error {
+	args := m.Called(record)
+	return args.Error(0)
+}
+
+func (m *idempotencyRepositoryMock) Release(record domain.IdempotencyRecord) error {
+	args := m.Called(record)
+	return args.Error(0)
+}
+
+func (m *idempotencyRepositoryMock) DeleteByResource(resourceReference string) (int64, error) {
+	args := m.Called(resourceReference)
+
+	removed, ok := args.Get(0).(int64)
+	if !ok {
+		return 0, errors.New("mock error")
+	}
+
+	return removed, args.Error(1)
+}
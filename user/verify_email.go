@@ -0,0 +1,64 @@
+package user
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/desarrollogj/golang-api-example/domain"
+	"github.com/desarrollogj/golang-api-example/infrastructure"
+	"github.com/desarrollogj/golang-api-example/libs/errors"
+	"github.com/desarrollogj/golang-api-example/libs/logger"
+)
+
+// VerifyEmail represents the method to be implemented to confirm an user email with a verification token
+type VerifyEmail interface {
+	Execute(token string) (domain.User, error)
+}
+
+// defaultVerifyEmail is the default implementation of VerifyEmail interface
+type defaultVerifyEmail struct {
+	repository infrastructure.UserRepository
+	verifier   EmailVerifier
+}
+
+// NewDefaultVerifyEmail creates a defaultVerifyEmail instance
+func NewDefaultVerifyEmail(repository infrastructure.UserRepository, verifier EmailVerifier) defaultVerifyEmail {
+	return defaultVerifyEmail{
+		repository: repository,
+		verifier:   verifier,
+	}
+}
+
+// Execute marks the user email as verified. Tokens issued for a previous email are rejected,
+// and verifying an already verified email has no effect
+func (s defaultVerifyEmail) Execute(token string) (domain.User, error) {
+	claims, err := s.verifier.Verify(token)
+	if err != nil {
+		logger.AppLog.Debug().Err(err).Msg("email verification token rejected")
+		return domain.User{}, errors.NewValidationError("verification token is not valid or expired").WithKey("verification_token_invalid")
+	}
+
+	currentUser, err := s.repository.FindByReference(claims.Subject)
+	if err != nil {
+		errMsg := fmt.Sprintf("unexpected error when try to get user with reference %s", claims.Subject)
+		logger.AppLog.Error().Err(err).Msg(errMsg)
+		return domain.User{}, errors.NewFatalError(errMsg)
+	}
+	if len(currentUser.Reference) == 0 || currentUser.ErasedDate != nil || currentUser.Email != claims.Email {
+		return domain.User{}, errors.NewValidationError("verification token is not valid or expired").WithKey("verification_token_invalid")
+	}
+	if currentUser.EmailVerifiedDate != nil {
+		return currentUser, nil
+	}
+
+	verified := time.Now().UTC()
+	currentUser.EmailVerifiedDate = &verified
+	currentUser.UpdatedDate = verified
+
+	updated, err := s.repository.Update(currentUser)
+	if err != nil {
+		return domain.User{}, storeError(err, "unexpected error when verify the user email")
+	}
+
+	return updated, nil
+}
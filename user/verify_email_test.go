@@ -0,0 +1,128 @@
+package user
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/desarrollogj/golang-api-example/domain"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newEmailVerificationClaimsMock(reference string, email string) EmailVerificationClaims {
+	return EmailVerificationClaims{
+		Email:            email,
+		RegisteredClaims: jwt.RegisteredClaims{Subject: reference},
+	}
+}
+
+func TestVerifyEmail_GivenAValidToken_WhenExecute_ThenVerifyTheEmail(t *testing.T) {
+	t.Log("Successfully verify an User email")
+
+	currentUser := domain.User{
+		GenericEntity: domain.GenericEntity{Reference: "REF1", IsActive: true},
+		Email:         "foobar@email.com",
+	}
+	verifierMock := new(emailVerifierMock)
+	verifierMock.On("Verify", "TOKEN").Return(newEmailVerificationClaimsMock("REF1", "foobar@email.com"), nil)
+	repositoryMock := new(repositoryMock)
+	repositoryMock.On("FindByReference", "REF1").Return(currentUser, nil)
+	repositoryMock.On("Update", mock.MatchedBy(func(user domain.User) bool {
+		return user.EmailVerifiedDate != nil && user.UpdatedDate.Equal(*user.EmailVerifiedDate)
+	})).Return(currentUser, nil)
+
+	useCase := NewDefaultVerifyEmail(repositoryMock, verifierMock)
+
+	_, err := useCase.Execute("TOKEN")
+
+	assert.Nil(t, err)
+
+	repositoryMock.AssertExpectations(t)
+	verifierMock.AssertExpectations(t)
+}
+
+func TestVerifyEmail_GivenANotValidToken_WhenExecute_ThenReturnAValidationError(t *testing.T) {
+	t.Log("Failure to verify an User email because the token is not valid")
+
+	verifierMock := new(emailVerifierMock)
+	verifierMock.On("Verify", "TOKEN").Return(EmailVerificationClaims{}, errors.New("token is expired"))
+	repositoryMock := new(repositoryMock)
+
+	useCase := NewDefaultVerifyEmail(repositoryMock, verifierMock)
+
+	_, err := useCase.Execute("TOKEN")
+
+	assert.NotNil(t, err)
+	assert.Equal(t, "verification token is not valid or expired", err.Error())
+
+	repositoryMock.AssertNotCalled(t, "FindByReference", mock.Anything)
+}
+
+func TestVerifyEmail_GivenATokenForAPreviousEmail_WhenExecute_ThenReturnAValidationError(t *testing.T) {
+	t.Log("Failure to verify an User email because the token was issued for another email")
+
+	currentUser := domain.User{
+		GenericEntity: domain.GenericEntity{Reference: "REF1", IsActive: true},
+		Email:         "new@email.com",
+	}
+	verifierMock := new(emailVerifierMock)
+	verifierMock.On("Verify", "TOKEN").Return(newEmailVerificationClaimsMock("REF1", "foobar@email.com"), nil)
+	repositoryMock := new(repositoryMock)
+	repositoryMock.On("FindByReference", "REF1").Return(currentUser, nil)
+
+	useCase := NewDefaultVerifyEmail(repositoryMock, verifierMock)
+
+	_, err := useCase.Execute("TOKEN")
+
+	assert.NotNil(t, err)
+	assert.Equal(t, "verification token is not valid or expired", err.Error())
+
+	repositoryMock.AssertNotCalled(t, "Update", mock.Anything)
+}
+
+func TestVerifyEmail_GivenAnAlreadyVerifiedEmail_WhenExecute_ThenReturnTheUser(t *testing.T) {
+	t.Log("Verify an already verified User email has no effect")
+
+	verified := time.Now().UTC()
+	currentUser := domain.User{
+		GenericEntity:     domain.GenericEntity{Reference: "REF1", IsActive: true},
+		Email:             "foobar@email.com",
+		EmailVerifiedDate: &verified,
+	}
+	verifierMock := new(emailVerifierMock)
+	verifierMock.On("Verify", "TOKEN").Return(newEmailVerificationClaimsMock("REF1", "foobar@email.com"), nil)
+	repositoryMock := new(repositoryMock)
+	repositoryMock.On("FindByReference", "REF1").Return(currentUser, nil)
+
+	useCase := NewDefaultVerifyEmail(repositoryMock, verifierMock)
+
+	user, err := useCase.Execute("TOKEN")
+
+	assert.Nil(t, err)
+	assert.Equal(t, currentUser, user)
+
+	repositoryMock.AssertNotCalled(t, "Update", mock.Anything)
+}
+
+func TestVerifyEmail_GivenAValidToken_WhenExecuteAndUpdateReturnedAnError_ThenReturnAFatalError(t *testing.T) {
+	t.Log("Failure to verify an User email because update returned an unexpected error")
+
+	currentUser := domain.User{
+		GenericEntity: domain.GenericEntity{Reference: "REF1", IsActive: true},
+		Email:         "foobar@email.com",
+	}
+	verifierMock := new(emailVerifierMock)
+	verifierMock.On("Verify", "TOKEN").Return(newEmailVerificationClaimsMock("REF1", "foobar@email.com"), nil)
+	repositoryMock := new(repositoryMock)
+	repositoryMock.On("FindByReference", "REF1").Return(currentUser, nil)
+	repositoryMock.On("Update", mock.AnythingOfType("User")).Return(domain.User{}, errors.New("repository error"))
+
+	useCase := NewDefaultVerifyEmail(repositoryMock, verifierMock)
+
+	_, err := useCase.Execute("TOKEN")
+
+	assert.NotNil(t, err)
+	assert.Equal(t, "unexpected error when verify the user email", err.Error())
+}